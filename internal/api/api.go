@@ -21,25 +21,27 @@ import (
 
 // Config contains the configuration fields for the Benthos API.
 type Config struct {
-	Address        string              `json:"address" yaml:"address"`
-	Enabled        bool                `json:"enabled" yaml:"enabled"`
-	RootPath       string              `json:"root_path" yaml:"root_path"`
-	DebugEndpoints bool                `json:"debug_endpoints" yaml:"debug_endpoints"`
-	CertFile       string              `json:"cert_file" yaml:"cert_file"`
-	KeyFile        string              `json:"key_file" yaml:"key_file"`
-	CORS           httpdocs.ServerCORS `json:"cors" yaml:"cors"`
+	Address           string              `json:"address" yaml:"address"`
+	Enabled           bool                `json:"enabled" yaml:"enabled"`
+	RootPath          string              `json:"root_path" yaml:"root_path"`
+	DebugEndpoints    bool                `json:"debug_endpoints" yaml:"debug_endpoints"`
+	ResourceEndpoints bool                `json:"resource_endpoints" yaml:"resource_endpoints"`
+	CertFile          string              `json:"cert_file" yaml:"cert_file"`
+	KeyFile           string              `json:"key_file" yaml:"key_file"`
+	CORS              httpdocs.ServerCORS `json:"cors" yaml:"cors"`
 }
 
 // NewConfig creates a new API config with default values.
 func NewConfig() Config {
 	return Config{
-		Address:        "0.0.0.0:4195",
-		Enabled:        true,
-		RootPath:       "/benthos",
-		DebugEndpoints: false,
-		CertFile:       "",
-		KeyFile:        "",
-		CORS:           httpdocs.NewServerCORS(),
+		Address:           "0.0.0.0:4195",
+		Enabled:           true,
+		RootPath:          "/benthos",
+		DebugEndpoints:    false,
+		ResourceEndpoints: false,
+		CertFile:          "",
+		KeyFile:           "",
+		CORS:              httpdocs.NewServerCORS(),
 	}
 }
 
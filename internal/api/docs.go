@@ -16,6 +16,9 @@ func Spec() docs.FieldSpecs {
 		docs.FieldBool(
 			"debug_endpoints", "Whether to register a few extra endpoints that can be useful for debugging performance or behavioral problems.",
 		).HasDefault(false),
+		docs.FieldBool(
+			"resource_endpoints", "Whether to register endpoints for listing, creating, updating and removing cache, processor and rate limit resources at runtime. This is not supported in streams mode, where resources are managed with the streams API.",
+		).Advanced().HasDefault(false),
 		docs.FieldString("cert_file", "An optional certificate file for enabling TLS.").Advanced().HasDefault(""),
 		docs.FieldString("key_file", "An optional key file for enabling TLS.").Advanced().HasDefault(""),
 		httpdocs.ServerCORSFieldSpec(),
@@ -4,21 +4,27 @@ import "github.com/benthosdev/benthos/v4/internal/component/processor"
 
 // Config contains configuration parameters for a batch policy.
 type Config struct {
-	ByteSize   int                `json:"byte_size" yaml:"byte_size"`
-	Count      int                `json:"count" yaml:"count"`
-	Check      string             `json:"check" yaml:"check"`
-	Period     string             `json:"period" yaml:"period"`
-	Processors []processor.Config `json:"processors" yaml:"processors"`
+	ByteSize         int                `json:"byte_size" yaml:"byte_size"`
+	Count            int                `json:"count" yaml:"count"`
+	Check            string             `json:"check" yaml:"check"`
+	Period           string             `json:"period" yaml:"period"`
+	Jitter           float64            `json:"jitter" yaml:"jitter"`
+	MaxInFlightBytes int                `json:"max_in_flight_bytes" yaml:"max_in_flight_bytes"`
+	TargetLatency    string             `json:"target_latency" yaml:"target_latency"`
+	Processors       []processor.Config `json:"processors" yaml:"processors"`
 }
 
 // NewConfig creates a default PolicyConfig.
 func NewConfig() Config {
 	return Config{
-		ByteSize:   0,
-		Count:      0,
-		Check:      "",
-		Period:     "",
-		Processors: []processor.Config{},
+		ByteSize:         0,
+		Count:            0,
+		Check:            "",
+		Period:           "",
+		Jitter:           0,
+		MaxInFlightBytes: 0,
+		TargetLatency:    "",
+		Processors:       []processor.Config{},
 	}
 }
 
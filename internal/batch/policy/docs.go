@@ -44,6 +44,20 @@ Allows you to configure a [batching policy](/docs/configuration/batching).`,
 				"A [Bloblang query](/docs/guides/bloblang/about/) that should return a boolean value indicating whether a message should end a batch.",
 				`this.type == "end_of_transaction"`,
 			).HasDefault(""),
+			docs.FieldFloat(
+				"jitter",
+				"A factor between `0` and `1` by which the `period` is randomly extended for each batch, which prevents many instances from flushing in lockstep. For example, a `period` of `10s` with a `jitter` of `0.2` results in batches being flushed after a period of between 10 and 12 seconds.",
+				0.1,
+			).HasDefault(0).Advanced().AtVersion("4.2.0"),
+			docs.FieldInt(
+				"max_in_flight_bytes",
+				"An optional maximum number of bytes of flushed batches that can be waiting to be acknowledged by the output at a time, once reached no more messages are consumed until batches are acknowledged. If `0` there is no limit. This field only applies to batching policies of outputs.",
+			).HasDefault(0).Advanced().AtVersion("4.2.0"),
+			docs.FieldString(
+				"target_latency",
+				"An optional target for the time taken for flushed batches to be acknowledged by the output, which enables adaptive batching. When a batch takes longer than this target the `count` and `byte_size` limits are temporarily shrunk, and they are gradually restored to their configured values while batches are acknowledged within the target. This field only applies to batching policies of outputs.",
+				"500ms", "2s",
+			).HasDefault("").Advanced().AtVersion("4.2.0"),
 			docs.FieldProcessor(
 				"processors",
 				"A list of [processors](/docs/components/processors/about) to apply to a batch as it is flushed. This allows you to aggregate and archive the batch however you see fit. Please note that all resulting messages are flushed as a single batch, therefore splitting the batch into smaller batches using these processors is a no-op.",
@@ -26,6 +26,9 @@ func TestBatchPolicySanit(t *testing.T) {
 byte_size: 0
 period: ""
 check: ""
+jitter: 0
+max_in_flight_bytes: 0
+target_latency: ""
 processors: []
 `
 
@@ -3,7 +3,10 @@ package policy
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
@@ -23,6 +26,7 @@ type Batcher struct {
 	byteSize  int
 	count     int
 	period    time.Duration
+	jitter    float64
 	check     *mapping.Executor
 	procs     []iprocessor.V1
 	sizeTally int
@@ -31,6 +35,17 @@ type Batcher struct {
 	triggered bool
 	lastBatch time.Time
 
+	// The period of the current batch, which is extended by jitter.
+	nextPeriod time.Duration
+
+	maxInFlightBytes int
+
+	// When a target latency is set the count and byte size limits are scaled
+	// down as acknowledgements exceed it.
+	targetLatency time.Duration
+	scaleMut      sync.Mutex
+	scale         float64
+
 	mSizeBatch   metrics.StatCounter
 	mCountBatch  metrics.StatCounter
 	mPeriodBatch metrics.StatCounter
@@ -58,6 +73,18 @@ func New(conf batchconfig.Config, mgr bundle.NewManagement) (*Batcher, error) {
 			return nil, fmt.Errorf("failed to parse duration string: %v", err)
 		}
 	}
+	if conf.Jitter < 0 || conf.Jitter > 1 {
+		return nil, fmt.Errorf("jitter must be between 0 and 1, got %v", conf.Jitter)
+	}
+	if conf.MaxInFlightBytes < 0 {
+		return nil, fmt.Errorf("max_in_flight_bytes must not be negative, got %v", conf.MaxInFlightBytes)
+	}
+	var targetLatency time.Duration
+	if len(conf.TargetLatency) > 0 {
+		if targetLatency, err = time.ParseDuration(conf.TargetLatency); err != nil {
+			return nil, fmt.Errorf("failed to parse target_latency duration string: %v", err)
+		}
+	}
 	var procs []iprocessor.V1
 	for i, pconf := range conf.Processors {
 		pMgr := mgr.IntoPath("processors", strconv.Itoa(i))
@@ -69,22 +96,66 @@ func New(conf batchconfig.Config, mgr bundle.NewManagement) (*Batcher, error) {
 	}
 
 	batchOn := mgr.Metrics().GetCounterVec("batch_created", "mechanism")
-	return &Batcher{
+	p := &Batcher{
 		log: mgr.Logger(),
 
 		byteSize: conf.ByteSize,
 		count:    conf.Count,
 		period:   period,
+		jitter:   conf.Jitter,
 		check:    check,
 		procs:    procs,
 
 		lastBatch: time.Now(),
 
+		maxInFlightBytes: conf.MaxInFlightBytes,
+		targetLatency:    targetLatency,
+		scale:            1,
+
 		mSizeBatch:   batchOn.With("size"),
 		mCountBatch:  batchOn.With("count"),
 		mPeriodBatch: batchOn.With("period"),
 		mCheckBatch:  batchOn.With("check"),
-	}, nil
+	}
+	p.nextPeriod = p.jitteredPeriod()
+	return p, nil
+}
+
+// jitteredPeriod returns the period extended by a random proportion of itself
+// up to the jitter factor.
+func (p *Batcher) jitteredPeriod() time.Duration {
+	if p.jitter <= 0 || p.period <= 0 {
+		return p.period
+	}
+	return p.period + time.Duration(rand.Float64()*p.jitter*float64(p.period))
+}
+
+// The smallest proportion of the configured limits that adaptive batching is
+// able to shrink batches to, and the proportion restored for each batch that
+// is acknowledged within the target latency.
+const (
+	minAdaptiveScale  = 0.01
+	adaptiveScaleStep = 0.1
+)
+
+// limits returns the count and byte size limits of the current batch, which
+// are scaled down when adaptive batching is enabled and latency is high.
+func (p *Batcher) limits() (count, byteSize int) {
+	if p.targetLatency <= 0 {
+		return p.count, p.byteSize
+	}
+
+	p.scaleMut.Lock()
+	scale := p.scale
+	p.scaleMut.Unlock()
+
+	scaleLimit := func(l int) int {
+		if l <= 0 {
+			return l
+		}
+		return int(math.Ceil(float64(l) * scale))
+	}
+	return scaleLimit(p.count), scaleLimit(p.byteSize)
 }
 
 //------------------------------------------------------------------------------
@@ -95,12 +166,13 @@ func (p *Batcher) Add(part *message.Part) bool {
 	p.sizeTally += len(part.Get())
 	p.parts = append(p.parts, part)
 
-	if !p.triggered && p.count > 0 && len(p.parts) >= p.count {
+	count, byteSize := p.limits()
+	if !p.triggered && count > 0 && len(p.parts) >= count {
 		p.triggered = true
 		p.mCountBatch.Incr(1)
 		p.log.Traceln("Batching based on count")
 	}
-	if !p.triggered && p.byteSize > 0 && p.sizeTally >= p.byteSize {
+	if !p.triggered && byteSize > 0 && p.sizeTally >= byteSize {
 		p.triggered = true
 		p.mSizeBatch.Incr(1)
 		p.log.Traceln("Batching based on byte_size")
@@ -120,7 +192,7 @@ func (p *Batcher) Add(part *message.Part) bool {
 			p.log.Traceln("Batching based on check query")
 		}
 	}
-	return p.triggered || (p.period > 0 && time.Since(p.lastBatch) > p.period)
+	return p.triggered || (p.nextPeriod > 0 && time.Since(p.lastBatch) > p.nextPeriod)
 }
 
 // Flush clears all messages stored by this batch policy. Returns nil if the
@@ -148,7 +220,7 @@ func (p *Batcher) Flush() *message.Batch {
 func (p *Batcher) flushAny() []*message.Batch {
 	var newMsg *message.Batch
 	if len(p.parts) > 0 {
-		if !p.triggered && p.period > 0 && time.Since(p.lastBatch) > p.period {
+		if !p.triggered && p.nextPeriod > 0 && time.Since(p.lastBatch) > p.nextPeriod {
 			p.mPeriodBatch.Incr(1)
 			p.log.Traceln("Batching based on period")
 		}
@@ -158,6 +230,7 @@ func (p *Batcher) flushAny() []*message.Batch {
 	p.parts = nil
 	p.sizeTally = 0
 	p.lastBatch = time.Now()
+	p.nextPeriod = p.jitteredPeriod()
 	p.triggered = false
 
 	if newMsg == nil {
@@ -186,10 +259,35 @@ func (p *Batcher) Count() int {
 // should be flushed due to a configured period. A negative duration indicates
 // a period has not been set.
 func (p *Batcher) UntilNext() time.Duration {
-	if p.period <= 0 {
+	if p.nextPeriod <= 0 {
 		return -1
 	}
-	return time.Until(p.lastBatch.Add(p.period))
+	return time.Until(p.lastBatch.Add(p.nextPeriod))
+}
+
+// MaxInFlightBytes returns the maximum number of bytes of flushed batches that
+// may be awaiting acknowledgement at a time, or zero if there is no limit.
+func (p *Batcher) MaxInFlightBytes() int {
+	return p.maxInFlightBytes
+}
+
+// ReportLatency informs the policy of the time taken for a flushed batch to be
+// acknowledged, which is used to adapt the size of subsequent batches when a
+// target latency is configured. This method is safe to call concurrently with
+// the other methods of the policy.
+func (p *Batcher) ReportLatency(d time.Duration) {
+	if p.targetLatency <= 0 {
+		return
+	}
+
+	p.scaleMut.Lock()
+	defer p.scaleMut.Unlock()
+
+	if d > p.targetLatency {
+		p.scale = math.Max(p.scale/2, minAdaptiveScale)
+	} else {
+		p.scale = math.Min(p.scale+adaptiveScaleStep, 1)
+	}
 }
 
 //------------------------------------------------------------------------------
@@ -124,6 +124,74 @@ func TestPolicyPeriod(t *testing.T) {
 	}
 }
 
+func TestPolicyPeriodJitter(t *testing.T) {
+	conf := batchconfig.NewConfig()
+	conf.Period = "1s"
+	conf.Jitter = 0.5
+
+	pol, err := policy.New(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		pol.CloseAsync()
+		require.NoError(t, pol.WaitForClose(time.Second))
+	})
+
+	for i := 0; i < 10; i++ {
+		v := pol.UntilNext()
+		assert.Greater(t, v, time.Millisecond*900)
+		assert.LessOrEqual(t, v, time.Millisecond*1500)
+		_ = pol.Flush()
+	}
+}
+
+func TestPolicyBadJitter(t *testing.T) {
+	conf := batchconfig.NewConfig()
+	conf.Period = "1s"
+	conf.Jitter = 1.5
+
+	_, err := policy.New(conf, mock.NewManager())
+	require.Error(t, err)
+}
+
+func TestPolicyAdaptive(t *testing.T) {
+	conf := batchconfig.NewConfig()
+	conf.Count = 10
+	conf.TargetLatency = "100ms"
+
+	pol, err := policy.New(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		pol.CloseAsync()
+		require.NoError(t, pol.WaitForClose(time.Second))
+	})
+
+	addUntilTriggered := func() int {
+		for i := 1; i <= 20; i++ {
+			if pol.Add(message.NewPart(nil)) {
+				require.NotNil(t, pol.Flush())
+				return i
+			}
+		}
+		t.Fatal("Batch was not triggered")
+		return 0
+	}
+
+	assert.Equal(t, 10, addUntilTriggered())
+
+	pol.ReportLatency(time.Second)
+	assert.Equal(t, 5, addUntilTriggered())
+
+	pol.ReportLatency(time.Second)
+	assert.Equal(t, 3, addUntilTriggered())
+
+	for i := 0; i < 10; i++ {
+		pol.ReportLatency(time.Millisecond)
+	}
+	assert.Equal(t, 10, addUntilTriggered())
+}
+
 func TestPolicySize(t *testing.T) {
 	conf := batchconfig.NewConfig()
 	conf.ByteSize = 10
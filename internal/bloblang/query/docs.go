@@ -153,6 +153,7 @@ var (
 	MethodCategoryParsing        = "Parsing"
 	MethodCategoryObjectAndArray = "Object & Array Manipulation"
 	MethodCategoryGeoIP          = "GeoIP"
+	MethodCategoryNetwork        = "Network"
 	MethodCategoryDeprecated     = "Deprecated"
 	MethodCategoryPlugin         = "Plugin"
 )
@@ -33,6 +33,8 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
 )
 
 var _ = registerSimpleMethod(
@@ -995,8 +997,8 @@ var _ = registerSimpleMethod(
 			default:
 				return nil, NewTypeError(v, ValueString)
 			}
-			var jObj interface{}
-			if err := json.Unmarshal(jsonBytes, &jObj); err != nil {
+			jObj, err := message.ParseJSON(jsonBytes)
+			if err != nil {
 				return nil, fmt.Errorf("failed to parse value as JSON: %w", err)
 			}
 			return jObj, nil
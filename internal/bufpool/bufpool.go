@@ -0,0 +1,43 @@
+// Package bufpool provides a pool of byte buffers that can be reused in hot
+// paths such as serialisation and compression in order to reduce allocations
+// and therefore GC pressure.
+package bufpool
+
+import (
+	"bytes"
+	"sync"
+)
+
+// MaxRetainedSize is the maximum capacity of a buffer that is returned to the
+// pool. Larger buffers are dropped in order to avoid an occasional large
+// message pinning a large amount of memory.
+const MaxRetainedSize = 1 << 22 // 4MB
+
+var pool = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
+// Get returns an empty buffer from the pool.
+func Get() *bytes.Buffer {
+	return pool.Get().(*bytes.Buffer)
+}
+
+// Put resets a buffer and returns it to the pool. The buffer, and any slices
+// obtained from it via Bytes, must not be used after calling Put.
+func Put(buf *bytes.Buffer) {
+	if buf.Cap() > MaxRetainedSize {
+		return
+	}
+	buf.Reset()
+	pool.Put(buf)
+}
+
+// Bytes returns a copy of the contents of a buffer that is safe to use after
+// the buffer has been returned to the pool.
+func Bytes(buf *bytes.Buffer) []byte {
+	b := make([]byte, buf.Len())
+	copy(b, buf.Bytes())
+	return b
+}
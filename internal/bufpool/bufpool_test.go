@@ -0,0 +1,34 @@
+package bufpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferReuse(t *testing.T) {
+	buf := Get()
+	assert.Equal(t, 0, buf.Len())
+
+	_, _ = buf.WriteString("hello world")
+	b := Bytes(buf)
+	Put(buf)
+
+	buf = Get()
+	assert.Equal(t, 0, buf.Len())
+	_, _ = buf.WriteString("goodbye")
+
+	assert.Equal(t, "hello world", string(b))
+	Put(buf)
+}
+
+func TestBufferLargeNotRetained(t *testing.T) {
+	buf := Get()
+	buf.Grow(MaxRetainedSize + 1)
+	_, _ = buf.WriteString("hello world")
+
+	// Should not panic or retain the buffer, we can't observe the pool
+	// directly so this just ensures the large buffer path is exercised.
+	Put(buf)
+	assert.Equal(t, "hello world", buf.String())
+}
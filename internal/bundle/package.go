@@ -15,6 +15,7 @@ import (
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/buffer"
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
@@ -44,6 +45,8 @@ type NewManagement interface {
 	BloblEnvironment() *bloblang.Environment
 
 	RegisterEndpoint(path, desc string, h http.HandlerFunc)
+	Lifecycle() *component.Lifecycle
+	SharedClients() *component.SharedClients
 
 	NewBuffer(conf buffer.Config) (buffer.Streamed, error)
 	NewCache(conf cache.Config) (cache.V1, error)
@@ -55,6 +58,7 @@ type NewManagement interface {
 	ProbeCache(name string) bool
 	AccessCache(ctx context.Context, name string, fn func(cache.V1)) error
 	StoreCache(ctx context.Context, name string, conf cache.Config) error
+	RemoveCache(ctx context.Context, name string) error
 
 	ProbeInput(name string) bool
 	AccessInput(ctx context.Context, name string, fn func(input.Streamed)) error
@@ -63,6 +67,7 @@ type NewManagement interface {
 	ProbeProcessor(name string) bool
 	AccessProcessor(ctx context.Context, name string, fn func(processor.V1)) error
 	StoreProcessor(ctx context.Context, name string, conf processor.Config) error
+	RemoveProcessor(ctx context.Context, name string) error
 
 	ProbeOutput(name string) bool
 	AccessOutput(ctx context.Context, name string, fn func(output.Sync)) error
@@ -71,6 +76,7 @@ type NewManagement interface {
 	ProbeRateLimit(name string) bool
 	AccessRateLimit(ctx context.Context, name string, fn func(ratelimit.V1)) error
 	StoreRateLimit(ctx context.Context, name string, conf ratelimit.Config) error
+	RemoveRateLimit(ctx context.Context, name string) error
 
 	GetPipe(name string) (<-chan message.Transaction, error)
 	SetPipe(name string, t <-chan message.Transaction)
@@ -16,6 +16,24 @@ var AllTracers = &TracerSet{
 
 //------------------------------------------------------------------------------
 
+// TracerAdd adds a new tracer to this environment by providing a constructor
+// and documentation.
+func (e *Environment) TracerAdd(constructor TracerConstructor, spec docs.ComponentSpec) error {
+	return e.tracers.Add(constructor, spec)
+}
+
+// TracerInit attempts to initialise a tracer from a config.
+func (e *Environment) TracerInit(conf tracer.Config) (tracer.Type, error) {
+	return e.tracers.Init(conf)
+}
+
+// TracerDocs returns a slice of tracer specs, which document each method.
+func (e *Environment) TracerDocs() []docs.ComponentSpec {
+	return e.tracers.Docs()
+}
+
+//------------------------------------------------------------------------------
+
 // TracerConstructor constructs an tracer component.
 type TracerConstructor func(tracer.Config) (tracer.Type, error)
 
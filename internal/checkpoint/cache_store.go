@@ -0,0 +1,166 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+)
+
+// CacheManager is the subset of manager functionality required by a
+// CacheStore.
+type CacheManager interface {
+	AccessCache(ctx context.Context, name string, fn func(cache.V1)) error
+	RegisterEndpoint(path, desc string, h http.HandlerFunc)
+}
+
+// CacheStore persists the checkpointed integer offsets of an input within a
+// cache resource, allowing consumption to resume from them after a restart.
+// Offsets are grouped by partition, where the meaning of a partition and offset
+// depends on the input, for example a file path and the number of messages
+// consumed from it.
+//
+// A CacheStore also registers an admin endpoint at /checkpoints/{id}, where
+// the offsets known to the store are returned from GET requests, and can be
+// rewound with POST requests in order to replay data.
+type CacheStore struct {
+	mgr   CacheManager
+	cache string
+	id    string
+
+	mut       sync.Mutex
+	offsets   map[string]int64
+	rewindFns []func(partition string, offset int64)
+}
+
+// NewCacheStore returns a checkpoint store that persists offsets within the
+// named cache resource with keys prefixed by an identifier, which should be
+// unique to the input.
+func NewCacheStore(mgr CacheManager, cacheName, id string) *CacheStore {
+	s := &CacheStore{
+		mgr:     mgr,
+		cache:   cacheName,
+		id:      id,
+		offsets: map[string]int64{},
+	}
+	mgr.RegisterEndpoint(
+		"/checkpoints/"+id,
+		"View the checkpoints of an input with a GET request, or rewind the checkpoint of a partition with a POST request containing a JSON object with the fields `partition` and `offset`.",
+		s.handleEndpoint,
+	)
+	return s
+}
+
+func (s *CacheStore) key(partition string) string {
+	return s.id + ":" + partition
+}
+
+// Get returns the stored offset of a partition, or false if there isn't one.
+func (s *CacheStore) Get(ctx context.Context, partition string) (int64, bool, error) {
+	var value []byte
+	var getErr error
+	if err := s.mgr.AccessCache(ctx, s.cache, func(c cache.V1) {
+		value, getErr = c.Get(ctx, s.key(partition))
+	}); err != nil {
+		return 0, false, err
+	}
+	if getErr != nil {
+		if errors.Is(getErr, component.ErrKeyNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, getErr
+	}
+
+	offset, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse checkpoint of partition %v: %w", partition, err)
+	}
+
+	s.mut.Lock()
+	s.offsets[partition] = offset
+	s.mut.Unlock()
+	return offset, true, nil
+}
+
+// Set stores the offset of a partition.
+func (s *CacheStore) Set(ctx context.Context, partition string, offset int64) error {
+	var setErr error
+	if err := s.mgr.AccessCache(ctx, s.cache, func(c cache.V1) {
+		setErr = c.Set(ctx, s.key(partition), []byte(strconv.FormatInt(offset, 10)), nil)
+	}); err != nil {
+		return err
+	}
+	if setErr != nil {
+		return setErr
+	}
+
+	s.mut.Lock()
+	s.offsets[partition] = offset
+	s.mut.Unlock()
+	return nil
+}
+
+// OnRewind registers a function to be called each time the offset of a
+// partition is rewound via the admin endpoint, after the new offset is stored.
+func (s *CacheStore) OnRewind(fn func(partition string, offset int64)) {
+	s.mut.Lock()
+	s.rewindFns = append(s.rewindFns, fn)
+	s.mut.Unlock()
+}
+
+// Offsets returns a copy of the offsets that have been read or written by this
+// store.
+func (s *CacheStore) Offsets() map[string]int64 {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	offsets := make(map[string]int64, len(s.offsets))
+	for k, v := range s.offsets {
+		offsets[k] = v
+	}
+	return offsets
+}
+
+type rewindRequest struct {
+	Partition string `json:"partition"`
+	Offset    *int64 `json:"offset"`
+}
+
+func (s *CacheStore) handleEndpoint(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req rewindRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to parse request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Partition == "" || req.Offset == nil || *req.Offset < 0 {
+			http.Error(w, "Request must contain a partition and a non-negative offset", http.StatusBadRequest)
+			return
+		}
+		if err := s.Set(r.Context(), req.Partition, *req.Offset); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to rewind checkpoint: %v", err), http.StatusBadGateway)
+			return
+		}
+
+		s.mut.Lock()
+		rewindFns := s.rewindFns
+		s.mut.Unlock()
+		for _, fn := range rewindFns {
+			fn(req.Partition, *req.Offset)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.Offsets())
+}
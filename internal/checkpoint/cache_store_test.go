@@ -0,0 +1,84 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestCacheStore(t *testing.T) {
+	ctx := context.Background()
+
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{
+		"fooinput:a": {Value: "5"},
+		"fooinput:b": {Value: "nope"},
+	}
+
+	endpoints := map[string]http.HandlerFunc{}
+	mgr.OnRegisterEndpoint = func(path string, h http.HandlerFunc) {
+		endpoints[path] = h
+	}
+
+	s := NewCacheStore(mgr, "foocache", "fooinput")
+
+	offset, exists, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, int64(5), offset)
+
+	_, _, err = s.Get(ctx, "b")
+	require.Error(t, err)
+
+	_, exists, err = s.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, s.Set(ctx, "c", 10))
+	assert.Equal(t, "10", mgr.Caches["foocache"]["fooinput:c"].Value)
+	assert.Equal(t, map[string]int64{"a": 5, "c": 10}, s.Offsets())
+
+	var rewinds []string
+	s.OnRewind(func(partition string, offset int64) {
+		rewinds = append(rewinds, partition)
+	})
+
+	handler := endpoints["/checkpoints/fooinput"]
+	require.NotNil(t, handler)
+
+	getOffsets := func(req *http.Request) (int, map[string]int64) {
+		t.Helper()
+		res := httptest.NewRecorder()
+		handler(res, req)
+		if res.Code != http.StatusOK {
+			return res.Code, nil
+		}
+		var offsets map[string]int64
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &offsets))
+		return res.Code, offsets
+	}
+
+	code, offsets := getOffsets(httptest.NewRequest("GET", "/checkpoints/fooinput", nil))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]int64{"a": 5, "c": 10}, offsets)
+
+	code, offsets = getOffsets(httptest.NewRequest("POST", "/checkpoints/fooinput", strings.NewReader(`{"partition":"c","offset":2}`)))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]int64{"a": 5, "c": 2}, offsets)
+	assert.Equal(t, "2", mgr.Caches["foocache"]["fooinput:c"].Value)
+	assert.Equal(t, []string{"c"}, rewinds)
+
+	code, _ = getOffsets(httptest.NewRequest("POST", "/checkpoints/fooinput", strings.NewReader(`{"partition":"c"}`)))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = getOffsets(httptest.NewRequest("DELETE", "/checkpoints/fooinput", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
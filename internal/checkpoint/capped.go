@@ -38,6 +38,13 @@ func (c *Capped) Highest() interface{} {
 	return c.t.Highest()
 }
 
+// Pending returns the number of tracked offsets that are yet to be resolved.
+func (c *Capped) Pending() int64 {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	return c.t.Pending()
+}
+
 // Track a new unresolved integer offset. This offset will be cached until it is
 // marked as resolved. While it is cached no higher valued offset will ever be
 // committed. If the provided value is lower than an already provided value an
@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+const benchPipeName = "benthos_bench"
+
+type benchConfig struct {
+	// Rate is the target number of messages per second, where zero means the
+	// rate is only limited by Concurrency.
+	Rate        float64
+	Duration    time.Duration
+	Size        int
+	Concurrency int
+}
+
+type benchResults struct {
+	mut       sync.Mutex
+	size      int
+	sent      int
+	failed    int
+	elapsed   time.Duration
+	latencies []time.Duration
+	lastErr   error
+}
+
+func (r *benchResults) record(latency time.Duration, err error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.sent++
+	if err != nil {
+		r.failed++
+		r.lastErr = err
+		return
+	}
+	r.latencies = append(r.latencies, latency)
+}
+
+// percentile returns the latency at a given percentile (0-100) of delivered
+// messages, latencies must already be sorted.
+func (r *benchResults) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(r.latencies)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(r.latencies) {
+		i = len(r.latencies) - 1
+	}
+	return r.latencies[i]
+}
+
+func (r *benchResults) String() string {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	sort.Slice(r.latencies, func(i, j int) bool {
+		return r.latencies[i] < r.latencies[j]
+	})
+
+	delivered := len(r.latencies)
+
+	var perSec float64
+	if r.elapsed > 0 {
+		perSec = float64(delivered) / r.elapsed.Seconds()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Duration:    %v\n", r.elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "Sent:        %v\n", r.sent)
+	fmt.Fprintf(&b, "Delivered:   %v\n", delivered)
+	fmt.Fprintf(&b, "Failed:      %v\n", r.failed)
+	fmt.Fprintf(&b, "Throughput:  %.1f msg/sec (%.1f KB/sec)\n", perSec, perSec*float64(r.size)/1024)
+	if delivered > 0 {
+		b.WriteString("Latency:\n")
+		for _, p := range []struct {
+			name string
+			p    float64
+		}{
+			{"min", 0}, {"p50", 50}, {"p90", 90}, {"p99", 99}, {"p99.9", 99.9}, {"max", 100},
+		} {
+			fmt.Fprintf(&b, "  %-6v %v\n", p.name+":", r.percentile(p.p))
+		}
+	}
+	if r.lastErr != nil {
+		fmt.Fprintf(&b, "Last error:  %v\n", r.lastErr)
+	}
+	return b.String()
+}
+
+func benchPayload(size int) []byte {
+	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))]
+	}
+	return b
+}
+
+// runBench writes generated messages to a transaction channel at the
+// configured rate until the duration has elapsed, and then waits for all
+// in-flight messages to be acknowledged. The provided context cancels the
+// benchmark early, including waiting for acknowledgements.
+func runBench(ctx context.Context, tChan chan<- message.Transaction, conf benchConfig) *benchResults {
+	sendCtx, done := context.WithTimeout(ctx, conf.Duration)
+	defer done()
+
+	concurrency := conf.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	payload := benchPayload(conf.Size)
+	res := &benchResults{size: conf.Size}
+	start := time.Now()
+
+	tokens := make(chan struct{})
+	go func() {
+		defer close(tokens)
+		for n := 0; ; n++ {
+			if conf.Rate > 0 {
+				due := start.Add(time.Duration(float64(n) / conf.Rate * float64(time.Second)))
+				if wait := time.Until(due); wait > 0 {
+					select {
+					case <-time.After(wait):
+					case <-sendCtx.Done():
+						return
+					}
+				}
+			}
+			select {
+			case tokens <- struct{}{}:
+			case <-sendCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for range tokens {
+				resChan := make(chan error, 1)
+				batch := message.QuickBatch([][]byte{append([]byte(nil), payload...)})
+
+				sentAt := time.Now()
+				select {
+				case tChan <- message.NewTransaction(batch, resChan):
+				case <-sendCtx.Done():
+					return
+				}
+				select {
+				case err := <-resChan:
+					res.record(time.Since(sentAt), err)
+				case <-ctx.Done():
+					res.record(0, ctx.Err())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	res.elapsed = time.Since(start)
+	return res
+}
+
+func benchCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: "Drive a config with generated load and report throughput and latency",
+		Description: `
+Replaces the input of a config with a generator that writes messages at a
+target rate for a period of time, and then prints a report of the throughput
+and end-to-end latency of the pipeline and outputs, where latency is measured
+from a message being sent until it is acknowledged:
+
+  benthos -c ./config.yaml bench --rate 5000 --duration 30s
+
+Instead of a config an output can be benchmarked on its own by providing it
+as YAML:
+
+  benthos bench --output 'http_client: { url: http://localhost:4195/post }'
+
+If neither a config nor an output is specified then messages are written to a
+drop output, which is useful for measuring the overhead of Benthos itself.`[1:],
+		Flags: []cli.Flag{
+			&cli.Float64Flag{
+				Name:  "rate",
+				Value: 0,
+				Usage: "The target number of messages per second, zero means as fast as possible.",
+			},
+			&cli.DurationFlag{
+				Name:  "duration",
+				Value: 10 * time.Second,
+				Usage: "The period of time to generate load for.",
+			},
+			&cli.IntFlag{
+				Name:  "size",
+				Value: 128,
+				Usage: "The size in bytes of generated messages.",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: 64,
+				Usage: "The maximum number of messages in flight at a given time.",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Value: "",
+				Usage: "An output config as YAML, which replaces the output of the config.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			confPath := c.String("config")
+			confReader := readConfig(confPath, false, c.StringSlice("resources"), nil, c.StringSlice("set"))
+
+			conf := config.New()
+			lints, err := confReader.Read(&conf)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Configuration file read error: %v\n", err)
+				os.Exit(1)
+			}
+			if !c.Bool("chilled") && len(lints) > 0 {
+				for _, lint := range lints {
+					fmt.Fprintln(os.Stderr, lint)
+				}
+				fmt.Fprintln(os.Stderr, "Shutting down due to linter errors, to prevent shutdown run Benthos with --chilled")
+				os.Exit(1)
+			}
+
+			if outStr := c.String("output"); outStr != "" {
+				outConf := output.NewConfig()
+				if err := yaml.Unmarshal([]byte(outStr), &outConf); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to parse output config: %v\n", err)
+					os.Exit(1)
+				}
+				conf.Output = outConf
+			} else if confPath == "" {
+				conf.Output = output.NewConfig()
+				conf.Output.Type = "drop"
+			}
+
+			if lvl := c.String("log.level"); len(lvl) > 0 {
+				conf.Logger.LogLevel = strings.ToUpper(lvl)
+			}
+			logger, err := log.NewV2(os.Stderr, conf.Logger)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create logger: %v\n", err)
+				os.Exit(1)
+			}
+
+			mgr, err := manager.New(conf.ResourceConfig, mock.NewManager(), logger, metrics.Noop())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create resources: %v\n", err)
+				os.Exit(1)
+			}
+
+			tChan := make(chan message.Transaction)
+			mgr.SetPipe(benchPipeName, tChan)
+
+			conf.Input = input.NewConfig()
+			conf.Input.Type = "inproc"
+			conf.Input.Inproc = input.InprocConfig(benchPipeName)
+
+			strm, err := stream.New(conf.Config, mgr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create stream: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, done := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer done()
+
+			res := runBench(ctx, tChan, benchConfig{
+				Rate:        c.Float64("rate"),
+				Duration:    c.Duration("duration"),
+				Size:        c.Int("size"),
+				Concurrency: c.Int("concurrency"),
+			})
+
+			if err := strm.Stop(time.Second * 30); err != nil {
+				logger.Warnf("Failed to cleanly close stream: %v", err)
+			}
+			mgr.CloseAsync()
+			_ = mgr.WaitForClose(time.Second * 30)
+
+			fmt.Print(res.String())
+			if res.failed > 0 && res.failed == res.sent {
+				return errors.New("no messages were delivered successfully")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestBenchResultsPercentiles(t *testing.T) {
+	res := &benchResults{size: 10, elapsed: time.Second}
+	for i := 100; i > 0; i-- {
+		res.record(time.Duration(i)*time.Millisecond, nil)
+	}
+	res.record(0, errors.New("nope"))
+
+	report := res.String()
+
+	assert.Equal(t, time.Millisecond, res.percentile(0))
+	assert.Equal(t, 50*time.Millisecond, res.percentile(50))
+	assert.Equal(t, 99*time.Millisecond, res.percentile(99))
+	assert.Equal(t, 100*time.Millisecond, res.percentile(100))
+
+	assert.Contains(t, report, "Sent:        101\n")
+	assert.Contains(t, report, "Delivered:   100\n")
+	assert.Contains(t, report, "Failed:      1\n")
+	assert.Contains(t, report, "Throughput:  100.0 msg/sec")
+	assert.Contains(t, report, "Last error:  nope\n")
+}
+
+func TestRunBench(t *testing.T) {
+	tChan := make(chan message.Transaction)
+	go func() {
+		var n int
+		for tran := range tChan {
+			assert.Equal(t, 16, len(tran.Payload.Get(0).Get()))
+			var err error
+			if n++; n%2 == 0 {
+				err = errors.New("nope")
+			}
+			assert.NoError(t, tran.Ack(context.Background(), err))
+		}
+	}()
+	defer close(tChan)
+
+	res := runBench(context.Background(), tChan, benchConfig{
+		Rate:        200,
+		Duration:    time.Millisecond * 100,
+		Size:        16,
+		Concurrency: 4,
+	})
+
+	assert.Greater(t, res.sent, 5)
+	assert.LessOrEqual(t, res.sent, 25)
+	assert.Equal(t, res.sent/2, res.failed)
+	assert.Equal(t, res.sent-res.failed, len(res.latencies))
+}
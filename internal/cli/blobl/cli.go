@@ -66,7 +66,7 @@ Find out more about Bloblang at: https://benthos.dev/docs/guides/bloblang/about`
 			{
 				Name:        "server",
 				Usage:       "EXPERIMENTAL: Run a web server that hosts a Bloblang app",
-				Description: "Run a web server that provides an interactive application for writing and testing Bloblang mappings.",
+				Description: "Run a web server that provides an interactive application for writing and testing Bloblang mappings. Mappings and inputs can be shared with others as a link by clicking the share button within the app.",
 				Action:      runServer,
 				Flags: []cli.Flag{
 					&cli.StringFlag{
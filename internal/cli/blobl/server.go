@@ -1,11 +1,15 @@
 package blobl
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
@@ -138,6 +142,51 @@ func (f *fileSync) mapping() string {
 	return f.mappingString
 }
 
+type snippet struct {
+	Mapping string `json:"mapping"`
+	Input   string `json:"input"`
+}
+
+// encodeSnippet compresses a mapping and input pair into a URL safe string
+// that can be shared as a link to the app.
+func encodeSnippet(s snippet) (string, error) {
+	jBytes, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(jBytes); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeSnippet extracts a mapping and input pair from a string created with
+// encodeSnippet.
+func decodeSnippet(str string) (s snippet, err error) {
+	var zBytes []byte
+	if zBytes, err = base64.RawURLEncoding.DecodeString(str); err != nil {
+		return
+	}
+
+	var zr *gzip.Reader
+	if zr, err = gzip.NewReader(bytes.NewReader(zBytes)); err != nil {
+		return
+	}
+
+	var jBytes []byte
+	if jBytes, err = io.ReadAll(zr); err != nil {
+		return
+	}
+	err = json.Unmarshal(jBytes, &s)
+	return
+}
+
 func runServer(c *cli.Context) error {
 	fSync := newFileSync(c.String("input-file"), c.String("mapping-file"), c.Bool("write"))
 	defer fSync.write()
@@ -145,11 +194,31 @@ func runServer(c *cli.Context) error {
 	mux := http.NewServeMux()
 	execCache := newExecCache()
 
+	mux.HandleFunc("/share", func(w http.ResponseWriter, r *http.Request) {
+		var req snippet
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		token, err := encodeSnippet(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		resBytes, err := json.Marshal(struct {
+			Share string `json:"share"`
+		}{token})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(resBytes)
+	})
+
 	mux.HandleFunc("/execute", func(w http.ResponseWriter, r *http.Request) {
-		req := struct {
-			Mapping string `json:"mapping"`
-			Input   string `json:"input"`
-		}{}
+		var req snippet
 		dec := json.NewDecoder(r.Body)
 		if err := dec.Decode(&req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -193,12 +262,24 @@ func runServer(c *cli.Context) error {
 	indexTemplate := template.Must(template.New("index").Parse(bloblangEditorPage))
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		initial := snippet{
+			Input:   fSync.input(),
+			Mapping: fSync.mapping(),
+		}
+		if token := r.URL.Query().Get("share"); token != "" {
+			var err error
+			if initial, err = decodeSnippet(token); err != nil {
+				http.Error(w, fmt.Sprintf("Failed to read shared snippet: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
 		err := indexTemplate.Execute(w, struct {
 			InitialInput   string
 			InitialMapping string
 		}{
-			fSync.input(),
-			fSync.mapping(),
+			initial.Input,
+			initial.Mapping,
 		})
 		if err != nil {
 			http.Error(w, "Template error", http.StatusBadGateway)
@@ -0,0 +1,28 @@
+package blobl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnippetEncoding(t *testing.T) {
+	in := snippet{
+		Mapping: "root.foo = this.bar.uppercase()\nroot.id = uuid_v4()",
+		Input:   `{"bar":"hello world & <friends>"}`,
+	}
+
+	token, err := encodeSnippet(in)
+	require.NoError(t, err)
+	assert.NotContains(t, token, "+")
+	assert.NotContains(t, token, "/")
+	assert.NotContains(t, token, "=")
+
+	out, err := decodeSnippet(token)
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+
+	_, err = decodeSnippet("not a valid token")
+	require.Error(t, err)
+}
@@ -113,7 +113,13 @@ forward slashes:
   benthos create stdin/bloblang,awk/nats
   benthos create file,http_server/protobuf/http_client
 
-If the expression is omitted a default config is created.`[1:],
+If the expression is omitted a default config is created.
+
+Alternatively, the --interactive flag walks through choosing an input,
+processors and an output, prompting for the fields of each component with
+their documentation shown inline:
+
+  benthos create --interactive`[1:],
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "small",
@@ -121,8 +127,30 @@ If the expression is omitted a default config is created.`[1:],
 				Value:   false,
 				Usage:   "Print only the main components of a Benthos config (input, pipeline, output) and omit all fields marked as advanced.",
 			},
+			&cli.BoolFlag{
+				Name:    "interactive",
+				Aliases: []string{"i"},
+				Value:   false,
+				Usage:   "Interactively choose components and their fields, with documentation shown for each field.",
+			},
 		},
 		Action: func(c *cli.Context) error {
+			if c.Bool("interactive") {
+				b := newInteractiveBuilder(os.Stdin, os.Stderr)
+				configYAML, lints, err := b.Run()
+				if err == nil {
+					for _, l := range lints {
+						fmt.Fprintf(os.Stderr, "Lint: %v\n", l)
+					}
+					err = b.Save(configYAML, os.Stdout)
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Generate error: %v\n", err)
+					os.Exit(1)
+				}
+				return nil
+			}
+
 			conf := config.New()
 
 			if expression := c.Args().First(); len(expression) > 0 {
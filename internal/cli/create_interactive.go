@@ -0,0 +1,532 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// componentAnswer is a component chosen during an interactive session along
+// with the field values provided for it.
+type componentAnswer struct {
+	name   string
+	fields []fieldAnswer
+}
+
+type fieldAnswer struct {
+	path  []string
+	value *yaml.Node
+}
+
+// interactiveBuilder walks a user through the creation of a config by
+// prompting for components and their fields.
+type interactiveBuilder struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newInteractiveBuilder(in io.Reader, out io.Writer) *interactiveBuilder {
+	return &interactiveBuilder{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+func (b *interactiveBuilder) prompt(format string, args ...interface{}) (string, error) {
+	fmt.Fprintf(b.out, format, args...)
+	line, err := b.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (b *interactiveBuilder) confirm(question string, def bool) (bool, error) {
+	opts := "y/N"
+	if def {
+		opts = "Y/n"
+	}
+	for {
+		answer, err := b.prompt("%v [%v]: ", question, opts)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+func componentDocs(cType docs.Type) []docs.ComponentSpec {
+	switch cType {
+	case docs.TypeInput:
+		return bundle.AllInputs.Docs()
+	case docs.TypeProcessor:
+		return bundle.AllProcessors.Docs()
+	case docs.TypeOutput:
+		return bundle.AllOutputs.Docs()
+	}
+	return nil
+}
+
+// chooseComponent prompts for a component type, where an empty answer is only
+// accepted when the component is optional.
+func (b *interactiveBuilder) chooseComponent(cType docs.Type, optional bool) (docs.ComponentSpec, bool, error) {
+	specs := componentDocs(cType)
+
+	hint := "name, search term, or ? to list all"
+	if optional {
+		hint += ", leave empty to skip"
+	}
+	for {
+		answer, err := b.prompt("\nChoose %v %v (%v): ", articleFor(string(cType)), cType, hint)
+		if err != nil {
+			return docs.ComponentSpec{}, false, err
+		}
+		if answer == "" {
+			if optional {
+				return docs.ComponentSpec{}, false, nil
+			}
+			continue
+		}
+
+		var matches []docs.ComponentSpec
+		for _, spec := range specs {
+			if spec.Status == docs.StatusDeprecated {
+				continue
+			}
+			if spec.Name == answer {
+				matches = []docs.ComponentSpec{spec}
+				break
+			}
+			if answer == "?" || strings.Contains(spec.Name, answer) || strings.Contains(strings.ToLower(spec.Summary), strings.ToLower(answer)) {
+				matches = append(matches, spec)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			fmt.Fprintf(b.out, "No %v components match '%v'\n", cType, answer)
+		case 1:
+			spec := matches[0]
+			fmt.Fprintf(b.out, "\n%v: %v\n", spec.Name, firstParagraph(spec.Summary))
+			if spec.Status != docs.StatusStable {
+				fmt.Fprintf(b.out, "Note: this component is %v\n", spec.Status)
+			}
+			return spec, true, nil
+		default:
+			for _, spec := range matches {
+				fmt.Fprintf(b.out, "  %-30v %v\n", spec.Name, firstParagraph(spec.Summary))
+			}
+		}
+	}
+}
+
+// configureComponent prompts for the non-advanced fields of a component and
+// lints the result, prompting again if lint errors are found.
+func (b *interactiveBuilder) configureComponent(cType docs.Type, spec docs.ComponentSpec) (componentAnswer, error) {
+	for {
+		answer := componentAnswer{name: spec.Name}
+
+		var err error
+		if len(spec.Config.Children) == 0 {
+			if spec.Config.Type != docs.FieldTypeObject {
+				err = b.promptField(spec.Config, nil, &answer)
+			}
+		} else {
+			err = b.promptFields(spec.Config.Children, nil, &answer)
+		}
+		if err != nil {
+			return answer, err
+		}
+
+		lints, err := lintAnswer(cType, answer)
+		if err != nil {
+			return answer, err
+		}
+		if len(lints) == 0 {
+			return answer, nil
+		}
+		for _, l := range lints {
+			fmt.Fprintf(b.out, "Lint error: %v\n", l)
+		}
+		retry, err := b.confirm("Configure this component again?", true)
+		if err != nil || !retry {
+			return answer, err
+		}
+	}
+}
+
+func (b *interactiveBuilder) promptFields(fields docs.FieldSpecs, path []string, answer *componentAnswer) error {
+	for _, f := range fields {
+		if f.IsAdvanced || f.IsDeprecated {
+			continue
+		}
+		fPath := append(append([]string{}, path...), f.Name)
+		if f.Type == docs.FieldTypeObject && f.Kind == docs.KindScalar && len(f.Children) > 0 {
+			if err := b.promptFields(f.Children, fPath, answer); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := b.promptField(f, fPath, answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *interactiveBuilder) promptField(f docs.FieldSpec, path []string, answer *componentAnswer) error {
+	name := strings.Join(path, ".")
+	if name == "" {
+		name = answer.name
+	}
+
+	switch f.Type {
+	case docs.FieldTypeInput, docs.FieldTypeOutput, docs.FieldTypeProcessor,
+		docs.FieldTypeBuffer, docs.FieldTypeCache, docs.FieldTypeRateLimit,
+		docs.FieldTypeMetrics, docs.FieldTypeTracer:
+		fmt.Fprintf(b.out, "\nSkipping field %v, child components need to be added to the config manually\n", name)
+		return nil
+	}
+
+	fmt.Fprintf(b.out, "\n%v (%v)\n", name, fieldTypeString(f))
+	if desc := firstParagraph(f.Description); desc != "" {
+		fmt.Fprintf(b.out, "  %v\n", desc)
+	}
+	if len(f.Options) > 0 {
+		fmt.Fprintf(b.out, "  Options: %v\n", strings.Join(f.Options, ", "))
+	}
+	for _, o := range f.AnnotatedOptions {
+		fmt.Fprintf(b.out, "  - %v: %v\n", o[0], firstParagraph(o[1]))
+	}
+	if len(f.Examples) > 0 {
+		if exBytes, err := yaml.Marshal(f.Examples[0]); err == nil {
+			fmt.Fprintf(b.out, "  Example: %v\n", strings.TrimSpace(string(exBytes)))
+		}
+	}
+
+	promptStr := "> "
+	if f.Default != nil {
+		if defBytes, err := yaml.Marshal(*f.Default); err == nil {
+			promptStr = fmt.Sprintf("[%v] > ", strings.TrimSpace(string(defBytes)))
+		}
+	}
+
+	for {
+		raw, err := b.prompt("  %v", promptStr)
+		if err != nil {
+			return err
+		}
+		if raw == "" {
+			return nil
+		}
+		node, err := parseFieldValue(f, raw)
+		if err != nil {
+			fmt.Fprintf(b.out, "  Invalid value: %v\n", err)
+			continue
+		}
+		if f.Kind == docs.KindMap {
+			// Maps cannot be set directly and so each key is set individually.
+			for i := 0; i < len(node.Content)-1; i += 2 {
+				kPath := append(append([]string{}, path...), node.Content[i].Value)
+				answer.fields = append(answer.fields, fieldAnswer{path: kPath, value: node.Content[i+1]})
+			}
+			return nil
+		}
+		answer.fields = append(answer.fields, fieldAnswer{path: path, value: node})
+		return nil
+	}
+}
+
+// parseFieldValue converts a raw answer into a YAML node that matches the type
+// and kind of a field. Array values are comma separated, and map values are
+// comma separated key=value pairs.
+func parseFieldValue(f docs.FieldSpec, raw string) (*yaml.Node, error) {
+	splitList := func(s string) []string {
+		var elements []string
+		for _, e := range strings.Split(s, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				elements = append(elements, e)
+			}
+		}
+		return elements
+	}
+
+	switch f.Kind {
+	case docs.KindArray:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, e := range splitList(raw) {
+			eNode, err := parseScalarValue(f.Type, e)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, eNode)
+		}
+		return node, nil
+	case docs.KindMap:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, e := range splitList(raw) {
+			kv := strings.SplitN(e, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("expected key=value pair, got '%v'", e)
+			}
+			vNode, err := parseScalarValue(f.Type, strings.TrimSpace(kv[1]))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{
+				Kind:  yaml.ScalarNode,
+				Tag:   "!!str",
+				Value: strings.TrimSpace(kv[0]),
+			}, vNode)
+		}
+		return node, nil
+	case docs.Kind2DArray:
+		return nil, errors.New("two dimensional arrays must be added to the config manually")
+	}
+	if len(f.Options) > 0 && f.Type == docs.FieldTypeString {
+		var found bool
+		for _, o := range f.Options {
+			if o == raw {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("expected one of: %v", strings.Join(f.Options, ", "))
+		}
+	}
+	return parseScalarValue(f.Type, raw)
+}
+
+func parseScalarValue(t docs.FieldType, raw string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: raw}
+	switch t {
+	case docs.FieldTypeString:
+		node.Tag = "!!str"
+	case docs.FieldTypeInt:
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, fmt.Errorf("expected an integer value: %w", err)
+		}
+		node.Tag = "!!int"
+	case docs.FieldTypeFloat:
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, fmt.Errorf("expected a float value: %w", err)
+		}
+		node.Tag = "!!float"
+	case docs.FieldTypeBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean value: %w", err)
+		}
+		node.Tag = "!!bool"
+		node.Value = strconv.FormatBool(b)
+	default:
+		var anyNode yaml.Node
+		if err := yaml.Unmarshal([]byte(raw), &anyNode); err != nil {
+			return nil, err
+		}
+		if anyNode.Kind == yaml.DocumentNode && len(anyNode.Content) > 0 {
+			return anyNode.Content[0], nil
+		}
+	}
+	return node, nil
+}
+
+func lintAnswer(cType docs.Type, answer componentAnswer) ([]string, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	compNode := &yaml.Node{Kind: yaml.MappingNode}
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: answer.name}, compNode)
+
+	spec, _ := docs.DeprecatedProvider.GetDocs(answer.name, cType)
+	if len(spec.Config.Children) == 0 && spec.Config.Type != docs.FieldTypeObject && spec.Config.Default != nil {
+		if err := root.Content[1].Encode(*spec.Config.Default); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range answer.fields {
+		if len(f.path) == 0 {
+			root.Content[1] = f.value
+			continue
+		}
+		if err := spec.Config.SetYAMLPath(docs.DeprecatedProvider, compNode, f.value, f.path...); err != nil {
+			return nil, err
+		}
+	}
+
+	var lints []string
+	for _, l := range docs.LintYAML(docs.NewLintContext(), cType, root) {
+		if l.Level == docs.LintError {
+			lints = append(lints, l.What)
+		}
+	}
+	return lints, nil
+}
+
+func fieldTypeString(f docs.FieldSpec) string {
+	switch f.Kind {
+	case docs.KindArray:
+		return fmt.Sprintf("comma separated list of %v", f.Type)
+	case docs.KindMap:
+		return fmt.Sprintf("comma separated key=value pairs of %v", f.Type)
+	}
+	return string(f.Type)
+}
+
+func firstParagraph(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n\n"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func articleFor(s string) string {
+	if s != "" && strings.ContainsAny(s[:1], "aeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+// Run walks through the selection of an input, processors and an output, and
+// returns a linted config.
+func (b *interactiveBuilder) Run() ([]byte, []string, error) {
+	conf := config.New()
+
+	inSpec, _, err := b.chooseComponent(docs.TypeInput, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	conf.Input.Type = inSpec.Name
+	inAnswer, err := b.configureComponent(docs.TypeInput, inSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var procAnswers []componentAnswer
+	for {
+		procSpec, chosen, err := b.chooseComponent(docs.TypeProcessor, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !chosen {
+			break
+		}
+		procConf := processor.NewConfig()
+		procConf.Type = procSpec.Name
+		conf.Pipeline.Processors = append(conf.Pipeline.Processors, procConf)
+		procAnswer, err := b.configureComponent(docs.TypeProcessor, procSpec)
+		if err != nil {
+			return nil, nil, err
+		}
+		procAnswers = append(procAnswers, procAnswer)
+	}
+
+	outSpec, _, err := b.chooseComponent(docs.TypeOutput, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	conf.Output.Type = outSpec.Name
+	outAnswer, err := b.configureComponent(docs.TypeOutput, outSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var node yaml.Node
+	if err := node.Encode(minimalCreateConfig{
+		Input:    conf.Input,
+		Pipeline: conf.Pipeline,
+		Output:   conf.Output,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	sanitConf := docs.NewSanitiseConfig()
+	sanitConf.RemoveTypeField = true
+	sanitConf.RemoveDeprecated = true
+	sanitConf.ForExample = true
+	sanitConf.Filter = func(spec docs.FieldSpec) bool {
+		return !spec.IsAdvanced
+	}
+
+	confSpec := config.Spec()
+	if err := confSpec.SanitiseYAML(&node, sanitConf); err != nil {
+		return nil, nil, err
+	}
+
+	setAnswer := func(a componentAnswer, prefix ...string) error {
+		for _, f := range a.fields {
+			path := append(append(append([]string{}, prefix...), a.name), f.path...)
+			if err := confSpec.SetYAMLPath(docs.DeprecatedProvider, &node, f.value, path...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := setAnswer(inAnswer, "input"); err != nil {
+		return nil, nil, err
+	}
+	for i, a := range procAnswers {
+		if err := setAnswer(a, "pipeline", "processors", strconv.Itoa(i)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := setAnswer(outAnswer, "output"); err != nil {
+		return nil, nil, err
+	}
+
+	configYAML, err := config.MarshalYAML(node)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lints, err := config.LintBytes(docs.NewLintContext(), configYAML)
+	if err != nil {
+		return nil, nil, err
+	}
+	return configYAML, lints, nil
+}
+
+// Save prompts for a file path to write a config to, and writes it to the
+// fallback writer if no path is given.
+func (b *interactiveBuilder) Save(configYAML []byte, fallback io.Writer) error {
+	path, err := b.prompt("\nWrite config to file (leave empty to print to stdout): ")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = fallback.Write(configYAML)
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		overwrite, err := b.confirm(fmt.Sprintf("File '%v' already exists, overwrite it?", path), false)
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return errors.New("config was not written")
+		}
+	}
+	if err := os.WriteFile(path, configYAML, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(b.out, "Config written to '%v', run it with: benthos -c %v\n", path, path)
+	return nil
+}
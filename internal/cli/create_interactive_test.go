@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	iprocessor "github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// An input is registered for choosing components interactively, which is never
+// constructed.
+func init() {
+	if err := bundle.AllInputs.Add(func(input.Config, bundle.NewManagement, ...iprocessor.PipelineConstructorFunc) (input.Streamed, error) {
+		return nil, errors.New("not implemented")
+	}, docs.ComponentSpec{
+		Name:    "cli_test_interactive_choose",
+		Summary: "An input for testing interactive component choices.",
+		Plugin:  true,
+		Config:  docs.FieldComponent(),
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func TestInteractiveParseFieldValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       docs.FieldSpec
+		input       string
+		expected    string
+		errContains string
+	}{
+		{
+			name:     "string",
+			field:    docs.FieldString("foo", ""),
+			input:    "hello world",
+			expected: "hello world\n",
+		},
+		{
+			name:     "int",
+			field:    docs.FieldInt("foo", ""),
+			input:    "10",
+			expected: "10\n",
+		},
+		{
+			name:        "bad int",
+			field:       docs.FieldInt("foo", ""),
+			input:       "nope",
+			errContains: "expected an integer",
+		},
+		{
+			name:     "bool",
+			field:    docs.FieldBool("foo", ""),
+			input:    "TRUE",
+			expected: "true\n",
+		},
+		{
+			name:     "string array",
+			field:    docs.FieldString("foo", "").Array(),
+			input:    "a, b,c",
+			expected: "- a\n- b\n- c\n",
+		},
+		{
+			name:     "string map",
+			field:    docs.FieldString("foo", "").Map(),
+			input:    "a=1, b=2",
+			expected: "a: \"1\"\nb: \"2\"\n",
+		},
+		{
+			name:        "bad option",
+			field:       docs.FieldString("foo", "").HasOptions("a", "b"),
+			input:       "c",
+			errContains: "expected one of: a, b",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			node, err := parseFieldValue(test.field, test.input)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+
+			resBytes, err := yaml.Marshal(node)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(resBytes))
+		})
+	}
+}
+
+func TestInteractiveChooseComponent(t *testing.T) {
+	var out bytes.Buffer
+	b := newInteractiveBuilder(strings.NewReader("nope\n?\ninteractive_choose\n"), &out)
+
+	spec, chosen, err := b.chooseComponent(docs.TypeInput, false)
+	require.NoError(t, err)
+	assert.True(t, chosen)
+	assert.Equal(t, "cli_test_interactive_choose", spec.Name)
+	assert.Contains(t, out.String(), "No input components match 'nope'")
+	assert.Contains(t, out.String(), "  cli_test_interactive_choose")
+
+	b = newInteractiveBuilder(strings.NewReader("\n"), &out)
+	_, chosen, err = b.chooseComponent(docs.TypeProcessor, true)
+	require.NoError(t, err)
+	assert.False(t, chosen)
+}
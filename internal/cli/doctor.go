@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/config/doctor"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func doctorFile(path string, fix bool) ([]doctor.Suggestion, *yaml.Node, error) {
+	confBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(confBytes, &node); err != nil {
+		return nil, nil, err
+	}
+
+	var suggestions []doctor.Suggestion
+	if fix {
+		suggestions, err = doctor.Fix(docs.DeprecatedProvider, config.Spec(), &node)
+	} else {
+		suggestions, err = doctor.Diagnose(docs.DeprecatedProvider, config.Spec(), &node)
+	}
+	return suggestions, &node, err
+}
+
+func doctorCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "doctor",
+		Usage: "Analyse Benthos configs and suggest improvements",
+		Description: `
+Analyses configs for deprecated fields and components, as well as patterns that
+are likely to be inefficient, and prints suggestions for how they could be
+improved:
+
+  benthos -c ./config.yaml doctor
+  benthos doctor ./foo.yaml ./bar.yaml
+
+With the --fix flag any suggestions that can be applied without changing the
+behaviour of the config are applied, and the resulting config is printed to
+stdout. The remaining suggestions are printed to stderr:
+
+  benthos doctor --fix ./config.yaml > ./migrated.yaml`[1:],
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "fix",
+				Value: false,
+				Usage: "Apply suggestions that can be made automatically and print the resulting config.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			targets := c.Args().Slice()
+			if conf := c.String("config"); len(conf) > 0 {
+				targets = append(targets, conf)
+			}
+			if len(targets) == 0 {
+				return errors.New("a config file must be specified")
+			}
+
+			fix := c.Bool("fix")
+			if fix && len(targets) > 1 {
+				return errors.New("the --fix flag can only be used with a single config file")
+			}
+
+			for _, target := range targets {
+				suggestions, node, err := doctorFile(target, fix)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%v: %v\n", target, red(err))
+					os.Exit(1)
+				}
+				for _, s := range suggestions {
+					fmt.Fprintf(os.Stderr, "%v: %v\n", target, yellow(s.String()))
+				}
+				if fix {
+					resBytes, err := config.MarshalYAML(*node)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "%v: %v\n", target, red(err))
+						os.Exit(1)
+					}
+					fmt.Print(string(resBytes))
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+// drainable is implemented by stoppable types that are able to flush all
+// buffered and in-flight data to their outputs before closing.
+type drainable interface {
+	Drain(timeout time.Duration, progress func(id, layer string)) error
+}
+
+// Drain stops the inputs of the current stream and waits for all buffered and
+// in-flight data to be flushed to the outputs.
+func (s *swappableStopper) Drain(timeout time.Duration, progress func(id, layer string)) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.stopped {
+		return nil
+	}
+
+	s.stopped = true
+	if strm, ok := s.current.(*mainStream); ok {
+		return strm.Drain(timeout, func(layer string) {
+			progress("main", layer)
+		})
+	}
+	return s.current.Stop(timeout)
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	drainStateRunning  = "running"
+	drainStateDraining = "draining"
+	drainStateDrained  = "drained"
+	drainStateFailed   = "failed"
+)
+
+// drainTracker coordinates draining the service, either as a result of a
+// termination signal or a request to the drain HTTP endpoint, and keeps track
+// of its progress.
+type drainTracker struct {
+	log log.Modular
+
+	requestedChan chan struct{}
+	requestOnce   sync.Once
+
+	mut     sync.Mutex
+	state   string
+	started time.Time
+	ended   time.Time
+	layers  map[string]string
+	err     error
+}
+
+func newDrainTracker(logger log.Modular) *drainTracker {
+	return &drainTracker{
+		log:           logger,
+		requestedChan: make(chan struct{}),
+		state:         drainStateRunning,
+		layers:        map[string]string{},
+	}
+}
+
+// requested returns a channel that is closed once a drain has been requested
+// via the HTTP endpoint.
+func (d *drainTracker) requested() <-chan struct{} {
+	return d.requestedChan
+}
+
+func (d *drainTracker) progress(id, layer string) {
+	d.mut.Lock()
+	d.layers[id] = layer
+	elapsed := time.Since(d.started)
+	d.mut.Unlock()
+
+	d.log.Infof("Draining stream %v, waiting for the %v layer to flush (%v elapsed).\n", id, layer, elapsed.Round(time.Millisecond))
+}
+
+// run drains the provided stoppable, falling back to a regular stop when it
+// does not support draining.
+func (d *drainTracker) run(s stoppable, timeout time.Duration) error {
+	d.mut.Lock()
+	d.state = drainStateDraining
+	d.started = time.Now()
+	d.mut.Unlock()
+
+	var err error
+	if ds, ok := s.(drainable); ok {
+		err = ds.Drain(timeout, d.progress)
+	} else {
+		err = s.Stop(timeout)
+	}
+
+	d.mut.Lock()
+	d.ended = time.Now()
+	if err != nil {
+		d.state = drainStateFailed
+		d.err = err
+	} else {
+		d.state = drainStateDrained
+	}
+	elapsed := d.ended.Sub(d.started)
+	d.mut.Unlock()
+
+	if err == nil {
+		d.log.Infof("All streams drained after %v.\n", elapsed.Round(time.Millisecond))
+	}
+	return err
+}
+
+func (d *drainTracker) handler(w http.ResponseWriter, r *http.Request) {
+	statusCode := http.StatusOK
+	switch r.Method {
+	case "POST":
+		d.requestOnce.Do(func() {
+			close(d.requestedChan)
+		})
+		statusCode = http.StatusAccepted
+	case "GET":
+	default:
+		http.Error(w, "Error: method not supported", http.StatusBadRequest)
+		return
+	}
+
+	d.mut.Lock()
+	status := struct {
+		State   string            `json:"state"`
+		Elapsed string            `json:"elapsed,omitempty"`
+		Streams map[string]string `json:"streams,omitempty"`
+		Error   string            `json:"error,omitempty"`
+	}{
+		State: d.state,
+	}
+	if !d.started.IsZero() {
+		ended := d.ended
+		if ended.IsZero() {
+			ended = time.Now()
+		}
+		status.Elapsed = ended.Sub(d.started).String()
+	}
+	if len(d.layers) > 0 {
+		status.Streams = make(map[string]string, len(d.layers))
+		for k, v := range d.layers {
+			status.Streams[k] = v
+		}
+	}
+	if d.err != nil {
+		status.Error = d.err.Error()
+	}
+	d.mut.Unlock()
+
+	resBytes, err := json.Marshal(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(resBytes)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+type fakeDrainable struct {
+	err error
+}
+
+func (f *fakeDrainable) Stop(timeout time.Duration) error {
+	return errors.New("should not be stopped")
+}
+
+func (f *fakeDrainable) Drain(timeout time.Duration, progress func(id, layer string)) error {
+	progress("foo", "input")
+	progress("foo", "output")
+	progress("bar", "buffer")
+	return f.err
+}
+
+func TestDrainTracker(t *testing.T) {
+	d := newDrainTracker(log.Noop())
+
+	response := httptest.NewRecorder()
+	d.handler(response, httptest.NewRequest("GET", "/drain", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"state":"running"}`, response.Body.String())
+
+	response = httptest.NewRecorder()
+	d.handler(response, httptest.NewRequest("POST", "/drain", nil))
+	assert.Equal(t, http.StatusAccepted, response.Code)
+
+	select {
+	case <-d.requested():
+	default:
+		t.Fatal("expected drain to be requested")
+	}
+
+	// Requesting a second time must not panic
+	response = httptest.NewRecorder()
+	d.handler(response, httptest.NewRequest("POST", "/drain", nil))
+	assert.Equal(t, http.StatusAccepted, response.Code)
+
+	require.NoError(t, d.run(&fakeDrainable{}, time.Second))
+
+	response = httptest.NewRecorder()
+	d.handler(response, httptest.NewRequest("GET", "/drain", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"state":"drained"`)
+	assert.Contains(t, response.Body.String(), `"streams":{"bar":"buffer","foo":"output"}`)
+}
+
+func TestDrainTrackerFailed(t *testing.T) {
+	d := newDrainTracker(log.Noop())
+	require.Error(t, d.run(&fakeDrainable{err: errors.New("nope")}, time.Second))
+
+	response := httptest.NewRecorder()
+	d.handler(response, httptest.NewRequest("GET", "/drain", nil))
+	assert.Contains(t, response.Body.String(), `"state":"failed"`)
+	assert.Contains(t, response.Body.String(), `"error":"nope"`)
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/config/graph"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func renderGraph(node *yaml.Node, format string) (string, error) {
+	g, err := graph.FromYAML(docs.DeprecatedProvider, node)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case "", "dot":
+		return g.DOT(), nil
+	case "mermaid":
+		return g.Mermaid(), nil
+	}
+	return "", fmt.Errorf("format not recognised: %v", format)
+}
+
+// graphHandler returns an HTTP handler that renders a graph of a config, the
+// format can be selected with the query parameter `format`.
+func graphHandler(node yaml.Node) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := renderGraph(&node, r.URL.Query().Get("format"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(res))
+	}
+}
+
+func graphCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "graph",
+		Usage: "Print a graph of the components within a config",
+		Description: `
+Parses a config file, including any resources, and prints a graph of the
+components within it and how data flows between them. The graph is printed in
+the Graphviz DOT language by default, or as a Mermaid flowchart:
+
+  benthos -c ./config.yaml graph | dot -Tsvg > ./config.svg
+  benthos -c ./config.yaml -r ./resources.yaml graph --format mermaid
+
+Solid edges show the flow of data between components, and dashed edges show
+components that are executed by (or referenced from) another component.`[1:],
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Value:   "dot",
+				Usage:   "The format of the graph, one of: dot, mermaid",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			confReader := readConfig(c.String("config"), false, c.StringSlice("resources"), nil, c.StringSlice("set"))
+			conf := config.New()
+			if _, err := confReader.Read(&conf); err != nil {
+				fmt.Fprintf(os.Stderr, "Configuration file read error: %v\n", err)
+				os.Exit(1)
+			}
+
+			var node yaml.Node
+			err := node.Encode(conf)
+			if err == nil {
+				sanitConf := docs.NewSanitiseConfig()
+				sanitConf.RemoveTypeField = true
+				err = config.Spec().SanitiseYAML(&node, sanitConf)
+			}
+			var res string
+			if err == nil {
+				res, err = renderGraph(&node, c.String("format"))
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Graph error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(res)
+			return nil
+		},
+	}
+}
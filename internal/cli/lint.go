@@ -13,6 +13,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/config/policy"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	ifilepath "github.com/benthosdev/benthos/v4/internal/filepath"
 )
@@ -27,7 +28,28 @@ type pathLint struct {
 	err    string
 }
 
-func lintFile(path string, rejectDeprecated bool) (pathLints []pathLint) {
+func checkPolicies(policies []*policy.Policy, configBytes []byte) (violations []string, err error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	var node yaml.Node
+	if err = yaml.Unmarshal(configBytes, &node); err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		var pViolations []policy.Violation
+		if pViolations, err = p.Check(docs.DeprecatedProvider, config.Spec(), &node); err != nil {
+			return nil, err
+		}
+		for _, v := range pViolations {
+			violations = append(violations, v.String())
+		}
+	}
+	return
+}
+
+func lintFile(path string, rejectDeprecated bool, policies []*policy.Policy) (pathLints []pathLint) {
 	conf := config.New()
 	lints, err := config.ReadFileLinted(path, rejectDeprecated, &conf)
 	if err != nil {
@@ -43,10 +65,29 @@ func lintFile(path string, rejectDeprecated bool) (pathLints []pathLint) {
 			lint:   l,
 		})
 	}
+
+	if len(policies) > 0 {
+		confBytes, _, err := config.ReadFileEnvSwap(path)
+		if err == nil {
+			lints, err = checkPolicies(policies, confBytes)
+		}
+		if err != nil {
+			pathLints = append(pathLints, pathLint{
+				source: path,
+				err:    err.Error(),
+			})
+		}
+		for _, l := range lints {
+			pathLints = append(pathLints, pathLint{
+				source: path,
+				lint:   l,
+			})
+		}
+	}
 	return
 }
 
-func lintMDSnippets(path string, rejectDeprecated bool) (pathLints []pathLint) {
+func lintMDSnippets(path string, rejectDeprecated bool, policies []*policy.Policy) (pathLints []pathLint) {
 	rawBytes, err := os.ReadFile(path)
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
@@ -95,6 +136,15 @@ func lintMDSnippets(path string, rejectDeprecated bool) (pathLints []pathLint) {
 					err:    err.Error(),
 				})
 			}
+			if violations, err := checkPolicies(policies, configBytes); err != nil {
+				pathLints = append(pathLints, pathLint{
+					source: path,
+					line:   snippetLine,
+					err:    err.Error(),
+				})
+			} else {
+				lints = append(lints, violations...)
+			}
 			for _, l := range lints {
 				pathLints = append(pathLints, pathLint{
 					source: path,
@@ -124,13 +174,22 @@ Exits with a status code 1 if any linting errors are detected:
   benthos lint ./configs/...
 
 If a path ends with '...' then Benthos will walk the target and lint any
-files with the .yaml or .yml extension.`[1:],
+files with the .yaml or .yml extension.
+
+Custom policy rules can be enforced with the --policy flag, which points to a
+YAML file of Bloblang predicates that configs must satisfy:
+
+  benthos lint --policy ./policy.yaml ./configs/...`[1:],
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "deprecated",
 				Value: false,
 				Usage: "Print linting errors for the presence of deprecated fields.",
 			},
+			&cli.StringSliceFlag{
+				Name:  "policy",
+				Usage: "A path to a file of policy rules that configs must satisfy, can be specified multiple times.",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			targets, err := ifilepath.GlobsAndSuperPaths(c.Args().Slice(), "yaml", "yml")
@@ -144,6 +203,16 @@ files with the .yaml or .yml extension.`[1:],
 
 			rejectDeprecated := c.Bool("deprecated")
 
+			var policies []*policy.Policy
+			for _, path := range c.StringSlice("policy") {
+				p, err := policy.ReadFile(path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Policy file %v error: %v\n", path, err)
+					os.Exit(1)
+				}
+				policies = append(policies, p)
+			}
+
 			var pathLintMut sync.Mutex
 			var pathLints []pathLint
 			threads := runtime.NumCPU()
@@ -161,9 +230,9 @@ files with the .yaml or .yml extension.`[1:],
 						}
 						var lints []pathLint
 						if path.Ext(target) == ".md" {
-							lints = lintMDSnippets(target, rejectDeprecated)
+							lints = lintMDSnippets(target, rejectDeprecated, policies)
 						} else {
-							lints = lintFile(target, rejectDeprecated)
+							lints = lintFile(target, rejectDeprecated, policies)
 						}
 						if len(lints) > 0 {
 							pathLintMut.Lock()
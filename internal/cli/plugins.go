@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config"
+)
+
+// pluginPathsFromConfig extracts the paths of plugins declared within a config
+// file. Relative paths are resolved from the directory of the config file.
+func pluginPathsFromConfig(path string) ([]string, error) {
+	confBytes, _, err := config.ReadFileEnvSwap(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf struct {
+		Plugins []string `yaml:"plugins"`
+	}
+	if err := yaml.Unmarshal(confBytes, &conf); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(conf.Plugins))
+	for _, p := range conf.Plugins {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(path), p)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// loadPlugins opens a list of Go plugins. Plugins register their components
+// with the global environment from init functions, which are executed when the
+// plugin is opened.
+func loadPlugins(paths ...string) error {
+	for _, p := range paths {
+		if _, err := plugin.Open(p); err != nil {
+			return fmt.Errorf("failed to load plugin %v: %w", p, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginPathsFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	confPath := filepath.Join(dir, "config.yaml")
+
+	require.NoError(t, os.WriteFile(confPath, []byte(`
+plugins:
+  - ./plugins/foo.so
+  - /opt/benthos/bar.so
+input:
+  stdin: {}
+`), 0o644))
+
+	paths, err := pluginPathsFromConfig(confPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "plugins", "foo.so"),
+		"/opt/benthos/bar.so",
+	}, paths)
+}
+
+func TestLoadPluginsMissing(t *testing.T) {
+	err := loadPlugins(filepath.Join(t.TempDir(), "nope.so"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load plugin")
+}
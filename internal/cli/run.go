@@ -152,6 +152,10 @@ func Run() {
 			Aliases: []string{"t"},
 			Usage:   "EXPERIMENTAL: import Benthos templates, supports glob patterns (requires quotes)",
 		},
+		&cli.StringSliceFlag{
+			Name:  "plugins",
+			Usage: "EXPERIMENTAL: load Go plugins that register extra components, supports glob patterns (requires quotes)",
+		},
 		&cli.BoolFlag{
 			Name:  "chilled",
 			Value: false,
@@ -194,6 +198,24 @@ Either run Benthos as a stream processor or choose a command:
 				}
 			}
 
+			pluginPaths, err := filepath.Globs(c.StringSlice("plugins"))
+			if err != nil {
+				fmt.Printf("Failed to resolve plugin glob pattern: %v\n", err)
+				os.Exit(1)
+			}
+			if confPath := c.String("config"); confPath != "" {
+				confPluginPaths, err := pluginPathsFromConfig(confPath)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Configuration file read error: %v\n", err)
+					os.Exit(1)
+				}
+				pluginPaths = append(pluginPaths, confPluginPaths...)
+			}
+			if err := loadPlugins(pluginPaths...); err != nil {
+				fmt.Fprintf(os.Stderr, "Plugin load error: %v\n", err)
+				os.Exit(1)
+			}
+
 			templatesPaths, err := filepath.Globs(c.StringSlice("templates"))
 			if err != nil {
 				fmt.Printf("Failed to resolve template glob pattern: %v\n", err)
@@ -273,6 +295,10 @@ variables have been resolved:
 				},
 			},
 			lintCliCommand(),
+			graphCliCommand(),
+			doctorCliCommand(),
+			benchCliCommand(),
+			windowsServiceCliCommand(),
 			{
 				Name:  "streams",
 				Usage: "Run Benthos in streams mode",
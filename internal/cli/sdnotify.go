@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"net"
+	"os"
+	"strings"
+)
+
+// Notification states understood by systemd, see sd_notify(3).
+const (
+	sdNotifyReady     = "READY=1"
+	sdNotifyReloading = "RELOADING=1"
+	sdNotifyStopping  = "STOPPING=1"
+)
+
+// sdNotify sends a state notification to the service manager when the process
+// is running as a systemd unit of Type=notify, which is detected via the
+// NOTIFY_SOCKET environment variable. Returns false without error when the
+// variable is unset.
+func sdNotify(state string) (bool, error) {
+	socketAddr := os.Getenv("NOTIFY_SOCKET")
+	if socketAddr == "" {
+		return false, nil
+	}
+
+	// A leading @ denotes a socket in the abstract namespace.
+	if strings.HasPrefix(socketAddr, "@") {
+		socketAddr = "\x00" + socketAddr[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: socketAddr,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDNotifyNoSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	sent, err := sdNotify(sdNotifyReady)
+	require.NoError(t, err)
+	assert.False(t, sent)
+}
+
+func TestSDNotify(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	t.Setenv("NOTIFY_SOCKET", socketPath)
+
+	for _, state := range []string{sdNotifyReady, sdNotifyReloading, sdNotifyStopping} {
+		sent, err := sdNotify(state)
+		require.NoError(t, err)
+		assert.True(t, sent)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		buf := make([]byte, 64)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, state, string(buf[:n]))
+	}
+}
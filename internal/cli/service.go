@@ -10,6 +10,7 @@ import (
 	"runtime/pprof"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -90,10 +91,17 @@ func initStreamsMode(
 		logger.Infoln(lint)
 	}
 
-	for id, conf := range streamConfs {
-		if err := streamMgr.Create(id, conf); err != nil {
-			logger.Errorf("Failed to create stream (%v): %v\n", id, err)
-			os.Exit(1)
+	tiers, err := strmmgr.StartupOrder(streamConfs)
+	if err != nil {
+		logger.Errorf("Failed to order streams: %v\n", err)
+		os.Exit(1)
+	}
+	for _, tier := range tiers {
+		for _, id := range tier {
+			if err := streamMgr.Create(id, streamConfs[id]); err != nil {
+				logger.Errorf("Failed to create stream (%v): %v\n", id, err)
+				os.Exit(1)
+			}
 		}
 	}
 	logger.Infoln("Launching benthos in streams mode, use CTRL+C to close.")
@@ -162,6 +170,29 @@ func (s *swappableStopper) Replace(fn func() (stoppable, error)) error {
 	return nil
 }
 
+// mainStream wraps the stream of normal mode in order to track whether it was
+// stopped deliberately, either by the service closing or by the stream being
+// replaced during a config reload, in which case its closure does not mean that
+// the pipeline has terminated.
+type mainStream struct {
+	*stream.Type
+	stopped int32
+}
+
+func (m *mainStream) Stop(timeout time.Duration) error {
+	atomic.StoreInt32(&m.stopped, 1)
+	return m.Type.Stop(timeout)
+}
+
+func (m *mainStream) Drain(timeout time.Duration, progress func(layer string)) error {
+	atomic.StoreInt32(&m.stopped, 1)
+	return m.Type.Drain(timeout, progress)
+}
+
+func (m *mainStream) wasStopped() bool {
+	return atomic.LoadInt32(&m.stopped) == 1
+}
+
 func initNormalMode(
 	conf config.Type,
 	strict, watching bool,
@@ -172,15 +203,23 @@ func initNormalMode(
 ) (newStream stoppable, stoppedChan chan struct{}) {
 	stoppedChan = make(chan struct{})
 
+	var stoppedOnce sync.Once
 	streamInit := func() (stoppable, error) {
-		return stream.New(
+		strm := &mainStream{}
+		var err error
+		if strm.Type, err = stream.New(
 			conf.Config, manager,
 			stream.OptOnClose(func() {
-				if !watching {
-					close(stoppedChan)
+				if !watching && !strm.wasStopped() {
+					stoppedOnce.Do(func() {
+						close(stoppedChan)
+					})
 				}
 			}),
-		)
+		); err != nil {
+			return nil, err
+		}
+		return strm, nil
 	}
 
 	var stoppableStream swappableStopper
@@ -305,8 +344,34 @@ func cmdService(
 		return 1
 	}
 
+	switch conf.SystemClosePolicy {
+	case "graceful", "drain":
+	default:
+		logger.Errorf("Unrecognised shutdown policy: %v\n", conf.SystemClosePolicy)
+		return 1
+	}
+
+	drains := newDrainTracker(logger)
+	httpServer.RegisterEndpoint(
+		"/drain",
+		"GET: Returns the progress of draining the service. POST: Stop all inputs and exit once all buffered and in-flight messages have been flushed to outputs.",
+		drains.handler,
+	)
+
+	if !streamsMode {
+		httpServer.RegisterEndpoint(
+			"/graph",
+			"Returns a graph of the components within the loaded config in the Graphviz DOT language, or as a Mermaid flowchart with the query parameter format=mermaid.",
+			graphHandler(sanitNode),
+		)
+	}
+
 	// Create resource manager.
-	manager, err := manager.New(conf.ResourceConfig, httpServer, logger, stats, manager.OptSetStreamsMode(streamsMode))
+	manager, err := manager.New(
+		conf.ResourceConfig, httpServer, logger, stats,
+		manager.OptSetStreamsMode(streamsMode),
+		manager.OptSetResourceEndpoints(conf.HTTP.ResourceEndpoints && !streamsMode),
+	)
 	if err != nil {
 		logger.Errorf("Failed to create resource: %v\n", err)
 		return 1
@@ -341,16 +406,31 @@ func cmdService(
 		}
 	}
 
+	if _, err := sdNotify(sdNotifyReady); err != nil {
+		logger.Warnf("Failed to notify service manager of readiness: %v", err)
+	}
+
 	// Defer clean up.
+	var drain bool
 	defer func() {
-		go func() {
-			_ = httpServer.Shutdown(context.Background())
-			select {
-			case <-httpServerClosedChan:
-			case <-time.After(exitTimeout / 2):
-				logger.Warnln("Service failed to close HTTP server gracefully in time.")
-			}
-		}()
+		_, _ = sdNotify(sdNotifyStopping)
+
+		shutdownHTTP := func() {
+			go func() {
+				_ = httpServer.Shutdown(context.Background())
+				select {
+				case <-httpServerClosedChan:
+				case <-time.After(exitTimeout / 2):
+					logger.Warnln("Service failed to close HTTP server gracefully in time.")
+				}
+			}()
+		}
+
+		// When draining we keep the HTTP server running until the streams are
+		// flushed so that progress can be checked.
+		if !drain {
+			shutdownHTTP()
+		}
 
 		go func() {
 			<-time.After(exitTimeout + time.Second)
@@ -368,7 +448,13 @@ func cmdService(
 		}
 
 		timesOut := time.Now().Add(exitTimeout)
-		if err := stoppableStream.Stop(exitTimeout); err != nil {
+		if drain {
+			if err := drains.run(stoppableStream, exitTimeout); err != nil {
+				logger.Errorf("Failed to drain streams within allocated time: %v\n", err)
+				os.Exit(1)
+			}
+			shutdownHTTP()
+		} else if err := stoppableStream.Stop(exitTimeout); err != nil {
 			os.Exit(1)
 		}
 		manager.CloseAsync()
@@ -385,18 +471,43 @@ func cmdService(
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
 	// Wait for termination signal
-	select {
-	case <-sigChan:
-		logger.Infoln("Received SIGTERM, the service is closing.")
-	case <-dataStreamClosedChan:
-		logger.Infoln("Pipeline has terminated. Shutting down the service.")
-	case <-httpServerClosedChan:
-		logger.Infoln("HTTP Server has terminated. Shutting down the service.")
-	case <-optContext.Done():
-		logger.Infoln("Run context was cancelled. Shutting down the service.")
-	}
-	return 0
+	for {
+		select {
+		case <-hupChan:
+			logger.Infoln("Received SIGHUP, reloading config files.")
+			reloadConfig(confReader, manager, strict, logger)
+			continue
+		case <-sigChan:
+			logger.Infoln("Received SIGTERM, the service is closing.")
+			drain = conf.SystemClosePolicy == "drain"
+		case <-drains.requested():
+			logger.Infoln("Received drain request, the service is closing once all streams are drained.")
+			drain = true
+		case <-dataStreamClosedChan:
+			logger.Infoln("Pipeline has terminated. Shutting down the service.")
+		case <-httpServerClosedChan:
+			logger.Infoln("HTTP Server has terminated. Shutting down the service.")
+		case <-optContext.Done():
+			logger.Infoln("Run context was cancelled. Shutting down the service.")
+		}
+		return 0
+	}
+}
+
+// reloadConfig reads all config files again and applies any changes, notifying
+// the service manager (when present) of the reload.
+func reloadConfig(confReader *config.Reader, mgr bundle.NewManagement, strict bool, logger log.Modular) {
+	_, _ = sdNotify(sdNotifyReloading)
+	if err := confReader.TriggerReload(mgr, strict); err != nil {
+		logger.Errorf("Failed to reload config files: %v", err)
+	} else {
+		logger.Infoln("Finished reloading config files.")
+	}
+	_, _ = sdNotify(sdNotifyReady)
 }
 
 //------------------------------------------------------------------------------
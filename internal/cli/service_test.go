@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	iprocessor "github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// The components of the pure package cannot be imported here without an
+// import cycle, and so minimal inputs and outputs are registered for tests.
+func init() {
+	for _, in := range []struct {
+		name  string
+		count int
+	}{
+		{name: "cli_test_generate", count: -1},
+		{name: "cli_test_generate_once", count: 1},
+	} {
+		count := in.count
+		if err := bundle.AllInputs.Add(func(input.Config, bundle.NewManagement, ...iprocessor.PipelineConstructorFunc) (input.Streamed, error) {
+			return newTestInput(count), nil
+		}, docs.ComponentSpec{
+			Name:   in.name,
+			Plugin: true,
+			Config: docs.FieldComponent(),
+		}); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := bundle.AllOutputs.Add(func(output.Config, bundle.NewManagement, ...iprocessor.PipelineConstructorFunc) (output.Streamed, error) {
+		return newTestOutput(), nil
+	}, docs.ComponentSpec{
+		Name:   "cli_test_drop",
+		Plugin: true,
+		Config: docs.FieldComponent(),
+	}); err != nil {
+		panic(err)
+	}
+}
+
+type testInput struct {
+	tChan      chan message.Transaction
+	ctx        context.Context
+	done       func()
+	closedChan chan struct{}
+}
+
+// newTestInput creates an input that emits count messages, or messages
+// indefinitely when count is negative, before shutting down.
+func newTestInput(count int) *testInput {
+	ctx, done := context.WithCancel(context.Background())
+	i := &testInput{
+		tChan:      make(chan message.Transaction),
+		ctx:        ctx,
+		done:       done,
+		closedChan: make(chan struct{}),
+	}
+	go func() {
+		defer func() {
+			close(i.tChan)
+			close(i.closedChan)
+		}()
+		for n := 0; count < 0 || n < count; n++ {
+			resChan := make(chan error, 1)
+			select {
+			case i.tChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("hello world")}), resChan):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-resChan:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return i
+}
+
+func (i *testInput) TransactionChan() <-chan message.Transaction {
+	return i.tChan
+}
+
+func (i *testInput) Connected() bool {
+	return true
+}
+
+func (i *testInput) CloseAsync() {
+	i.done()
+}
+
+func (i *testInput) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-i.closedChan:
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
+
+type testOutput struct {
+	ctx        context.Context
+	done       func()
+	closedChan chan struct{}
+}
+
+// newTestOutput creates an output that acknowledges and drops every message.
+func newTestOutput() *testOutput {
+	ctx, done := context.WithCancel(context.Background())
+	return &testOutput{
+		ctx:        ctx,
+		done:       done,
+		closedChan: make(chan struct{}),
+	}
+}
+
+func (o *testOutput) Consume(ts <-chan message.Transaction) error {
+	go func() {
+		defer close(o.closedChan)
+		for {
+			select {
+			case t, open := <-ts:
+				if !open {
+					return
+				}
+				_ = t.Ack(o.ctx, nil)
+			case <-o.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (o *testOutput) Connected() bool {
+	return true
+}
+
+func (o *testOutput) CloseAsync() {
+	o.done()
+}
+
+func (o *testOutput) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-o.closedChan:
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func TestNormalModeReloadKeepsRunning(t *testing.T) {
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(confPath, []byte(`
+input:
+  cli_test_generate: {}
+output:
+  cli_test_drop: {}
+`), 0o644))
+
+	confReader := config.NewReader(confPath, nil)
+
+	conf := config.New()
+	_, err := confReader.Read(&conf)
+	require.NoError(t, err)
+
+	mgr, err := manager.New(manager.NewResourceConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	strm, stoppedChan := initNormalMode(conf, true, false, confReader, mgr, log.Noop(), metrics.Noop())
+
+	// Reloading more than once must neither terminate the service nor panic.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, confReader.TriggerReload(mgr, true))
+	}
+
+	select {
+	case <-stoppedChan:
+		t.Fatal("Service terminated as a result of a reload")
+	case <-time.After(time.Millisecond * 1500):
+	}
+
+	require.NoError(t, strm.Stop(time.Second*5))
+
+	select {
+	case <-stoppedChan:
+		t.Fatal("Service termination signalled by a deliberate stop")
+	case <-time.After(time.Millisecond * 1500):
+	}
+}
+
+func TestNormalModePipelineTerminates(t *testing.T) {
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(confPath, []byte(`
+input:
+  cli_test_generate_once: {}
+output:
+  cli_test_drop: {}
+`), 0o644))
+
+	confReader := config.NewReader(confPath, nil)
+
+	conf := config.New()
+	_, err := confReader.Read(&conf)
+	require.NoError(t, err)
+
+	mgr, err := manager.New(manager.NewResourceConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	strm, stoppedChan := initNormalMode(conf, true, false, confReader, mgr, log.Noop(), metrics.Noop())
+
+	select {
+	case <-stoppedChan:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Expected the service to terminate with the pipeline")
+	}
+	assert.NoError(t, strm.Stop(time.Second*5))
+}
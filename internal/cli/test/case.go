@@ -1,12 +1,18 @@
 package test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 
 	yaml "gopkg.in/yaml.v3"
 
+	"github.com/benthosdev/benthos/v4/internal/component/output"
 	iprocessor "github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/message"
 )
@@ -63,13 +69,15 @@ func (i *InputPart) UnmarshalYAML(value *yaml.Node) error {
 
 // Case contains a definition of a single Benthos config test case.
 type Case struct {
-	Name             string               `yaml:"name"`
-	Environment      map[string]string    `yaml:"environment"`
-	TargetProcessors string               `yaml:"target_processors"`
-	TargetMapping    string               `yaml:"target_mapping"`
-	Mocks            map[string]yaml.Node `yaml:"mocks"`
-	InputBatch       []InputPart          `yaml:"input_batch"`
-	OutputBatches    [][]ConditionsMap    `yaml:"output_batches"`
+	Name             string                       `yaml:"name"`
+	Environment      map[string]string            `yaml:"environment"`
+	TargetProcessors string                       `yaml:"target_processors"`
+	TargetMapping    string                       `yaml:"target_mapping"`
+	Mocks            map[string]yaml.Node         `yaml:"mocks"`
+	InputBatch       []InputPart                  `yaml:"input_batch"`
+	OutputBatches    [][]ConditionsMap            `yaml:"output_batches"`
+	TargetOutput     string                       `yaml:"target_output"`
+	OutputWrites     map[string][][]ConditionsMap `yaml:"output_writes"`
 
 	line int
 }
@@ -90,6 +98,8 @@ func NewCase() Case {
 		Mocks:            map[string]yaml.Node{},
 		InputBatch:       []InputPart{},
 		OutputBatches:    [][]ConditionsMap{},
+		TargetOutput:     "",
+		OutputWrites:     map[string][][]ConditionsMap{},
 	}
 }
 
@@ -128,6 +138,13 @@ type ProcProvider interface {
 	ProvideBloblang(path string) ([]iprocessor.V1, error)
 }
 
+// OutputProvider returns a compiled output extracted from a Benthos config
+// using a JSON Pointer, where the outputs identified by captures are replaced
+// with mocks that write to the returned transaction channels.
+type OutputProvider interface {
+	ProvideOutput(jsonPtr string, environment map[string]string, mocks map[string]yaml.Node, captures []string) (output.Streamed, map[string]<-chan message.Transaction, error)
+}
+
 // ExecuteFrom executes a test case from the perspective of a given directory,
 // which is used for obtaining relative condition file imports.
 func (c *Case) ExecuteFrom(dir string, provider ProcProvider) (failures []CaseFailure, err error) {
@@ -175,6 +192,12 @@ func (c *Case) ExecuteFrom(dir string, provider ProcProvider) (failures []CaseFa
 		reportFailure(fmt.Sprintf("wrong batch count, expected %v, got %v", lExp, lAct))
 	}
 
+	if c.TargetOutput != "" {
+		if err = c.executeOutput(dir, provider, outputBatches, reportFailure); err != nil {
+			return nil, err
+		}
+	}
+
 	for i, v := range outputBatches {
 		if len(c.OutputBatches) <= i {
 			reportFailure(fmt.Sprintf("unexpected batch: %s", message.GetAllBytes(v)))
@@ -201,3 +224,113 @@ func (c *Case) ExecuteFrom(dir string, provider ProcProvider) (failures []CaseFa
 	}
 	return
 }
+
+var outputTimeout = time.Second * 30
+
+// executeOutput writes the resulting batches of a test case to the target
+// output and checks the batches captured from mocked outputs.
+func (c *Case) executeOutput(dir string, provider ProcProvider, batches []*message.Batch, reportFailure func(string)) error {
+	oProvider, ok := provider.(OutputProvider)
+	if !ok {
+		return errors.New("testing outputs is not supported by this provider")
+	}
+
+	captureKeys := make([]string, 0, len(c.OutputWrites))
+	for k := range c.OutputWrites {
+		captureKeys = append(captureKeys, k)
+	}
+	sort.Strings(captureKeys)
+
+	out, pipes, err := oProvider.ProvideOutput(c.TargetOutput, c.Environment, c.Mocks, captureKeys)
+	if err != nil {
+		return fmt.Errorf("failed to initialise output '%v': %v", c.TargetOutput, err)
+	}
+
+	var captureWG sync.WaitGroup
+	var captureMut sync.Mutex
+	captured := map[string][]*message.Batch{}
+	for k, pipe := range pipes {
+		captureWG.Add(1)
+		go func(k string, pipe <-chan message.Transaction) {
+			defer captureWG.Done()
+			for tran := range pipe {
+				captureMut.Lock()
+				captured[k] = append(captured[k], tran.Payload.Copy())
+				captureMut.Unlock()
+				_ = tran.Ack(context.Background(), nil)
+			}
+		}(k, pipe)
+	}
+
+	tranChan := make(chan message.Transaction)
+	if err = out.Consume(tranChan); err != nil {
+		out.CloseAsync()
+		return fmt.Errorf("failed to start output '%v': %v", c.TargetOutput, err)
+	}
+
+	resChan := make(chan error)
+	for i, b := range batches {
+		select {
+		case tranChan <- message.NewTransaction(b.Copy(), resChan):
+		case <-time.After(outputTimeout):
+			reportFailure(fmt.Sprintf("timed out writing batch %v to output", i))
+			continue
+		}
+		select {
+		case res := <-resChan:
+			if res != nil {
+				reportFailure(fmt.Sprintf("output rejected batch %v: %v", i, res))
+			}
+		case <-time.After(outputTimeout):
+			reportFailure(fmt.Sprintf("timed out waiting for output to acknowledge batch %v", i))
+		}
+	}
+
+	close(tranChan)
+	out.CloseAsync()
+	if err := out.WaitForClose(outputTimeout); err != nil {
+		reportFailure(fmt.Sprintf("failed to close output: %v", err))
+	}
+
+	capturesDone := make(chan struct{})
+	go func() {
+		captureWG.Wait()
+		close(capturesDone)
+	}()
+	select {
+	case <-capturesDone:
+	case <-time.After(outputTimeout):
+		return errors.New("timed out waiting for captured outputs to close")
+	}
+
+	captureMut.Lock()
+	defer captureMut.Unlock()
+
+	for _, k := range captureKeys {
+		expectedBatches, actualBatches := c.OutputWrites[k], captured[k]
+		if lExp, lAct := len(expectedBatches), len(actualBatches); lAct < lExp {
+			reportFailure(fmt.Sprintf("wrong batch count written to output '%v', expected %v, got %v", k, lExp, lAct))
+		}
+		for i, v := range actualBatches {
+			if len(expectedBatches) <= i {
+				reportFailure(fmt.Sprintf("unexpected batch written to output '%v': %s", k, message.GetAllBytes(v)))
+				continue
+			}
+			expectedBatch := expectedBatches[i]
+			if lExp, lAct := len(expectedBatch), v.Len(); lExp != lAct {
+				reportFailure(fmt.Sprintf("mismatch of output '%v' batch %v message counts, expected %v, got %v", k, i, lExp, lAct))
+			}
+			_ = v.Iter(func(i2 int, part *message.Part) error {
+				if len(expectedBatch) <= i2 {
+					reportFailure(fmt.Sprintf("unexpected message written to output '%v' in batch %v: %s", k, i, part.Get()))
+					return nil
+				}
+				for _, condErr := range expectedBatch[i2].CheckAll(dir, part) {
+					reportFailure(fmt.Sprintf("output '%v' batch %v message %v: %v", k, i, i2, condErr))
+				}
+				return nil
+			})
+		}
+	}
+	return nil
+}
@@ -53,7 +53,7 @@ It is also possible to target processors in a separate file by prefixing the tar
 		).HasDefault(""),
 		docs.FieldAnything(
 			"mocks",
-			"An optional map of processors to mock. Keys should contain either a label or a JSON pointer of a processor that should be mocked. Values should contain a processor definition, which will replace the mocked processor. Most of the time you'll want to use a `bloblang` processor here, and use it to create a result that emulates the target processor.",
+			"An optional map of processors to mock. Keys should contain either a label or a JSON pointer of a processor that should be mocked. Values should contain a processor definition, which will replace the mocked processor. Most of the time you'll want to use a `bloblang` processor here, and use it to create a result that emulates the target processor. Alternatively, a value can contain a field `canned_responses` with a list of responses to emit depending on the message the mocked processor receives.",
 			map[string]interface{}{
 				"get_foobar_api": map[string]interface{}{
 					"bloblang": "root = content().string() + \" this is some mock content\"",
@@ -64,6 +64,33 @@ It is also possible to target processors in a separate file by prefixing the tar
 					"bloblang": "root = content().string() + \" this is some mock content\"",
 				},
 			},
+			map[string]interface{}{
+				"get_foobar_api": map[string]interface{}{
+					"canned_responses": []interface{}{
+						map[string]interface{}{
+							"match":        "this.id == 1",
+							"json_content": map[string]interface{}{"name": "foo"},
+						},
+					},
+				},
+			},
+		).Map().Optional(),
+		docs.FieldString(
+			"target_output",
+			"An optional [JSON Pointer][json-pointer] or label that identifies an output to which the resulting batches of the test should be written. Outputs that are networked should either be mocked with the `mocks` field or captured with the `output_writes` field.",
+			"/output",
+			"foo_output",
+		).HasDefault(""),
+		docs.FieldAnything(
+			"output_writes",
+			"An optional map of outputs within the `target_output` to capture, where keys are either a label or a JSON pointer of an output. Captured outputs are replaced with a mock that records the batches written to it, which are then checked against a list of batches of conditions following the same format as `output_batches`.",
+			map[string]interface{}{
+				"foo_output": []interface{}{
+					[]interface{}{
+						map[string]interface{}{"content_equals": "foo"},
+					},
+				},
+			},
 		).Map().Optional(),
 		docs.FieldObject(
 			"input_batch", "",
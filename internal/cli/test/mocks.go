@@ -0,0 +1,109 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// CannedResponse describes the result of a mocked processor for messages that
+// match a Bloblang query. Canned responses allow networked processors such as
+// `http` and `sql_select` to be mocked by the request that they would have
+// made.
+type CannedResponse struct {
+	Match    string
+	Response InputPart
+}
+
+// UnmarshalYAML extracts a CannedResponse from a YAML node.
+func (r *CannedResponse) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %v: expected object value, got %v", value.Line, value.Tag)
+	}
+
+	partNode := *value
+	partNode.Content = nil
+	for i := 0; i < len(value.Content)-1; i += 2 {
+		if value.Content[i].Value == "match" {
+			if err := value.Content[i+1].Decode(&r.Match); err != nil {
+				return fmt.Errorf("line %v: %v", value.Content[i+1].Line, err)
+			}
+			continue
+		}
+		partNode.Content = append(partNode.Content, value.Content[i], value.Content[i+1])
+	}
+	return partNode.Decode(&r.Response)
+}
+
+const cannedResponsesField = "canned_responses"
+
+// expandCannedResponses checks whether a mock node is a list of canned
+// responses and, if so, returns an equivalent processor config. Any other mock
+// node is returned unchanged.
+func expandCannedResponses(dir string, node yaml.Node) (yaml.Node, error) {
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 || node.Content[0].Value != cannedResponsesField {
+		return node, nil
+	}
+
+	var responses []CannedResponse
+	if err := node.Content[1].Decode(&responses); err != nil {
+		return node, err
+	}
+	if len(responses) == 0 {
+		return node, errors.New("at least one canned response must be specified")
+	}
+
+	cases := make([]interface{}, 0, len(responses)+1)
+	for i, r := range responses {
+		content, err := r.Response.getContent(dir)
+		if err != nil {
+			return node, fmt.Errorf("canned response %v: %w", i, err)
+		}
+
+		var mapping strings.Builder
+		mapping.WriteString("root = " + strconv.Quote(content))
+
+		metaKeys := make([]string, 0, len(r.Response.Metadata))
+		for k := range r.Response.Metadata {
+			metaKeys = append(metaKeys, k)
+		}
+		sort.Strings(metaKeys)
+		for _, k := range metaKeys {
+			mapping.WriteString("\nmeta " + strconv.Quote(k) + " = " + strconv.Quote(r.Response.Metadata[k]))
+		}
+
+		cases = append(cases, map[string]interface{}{
+			"check": r.Match,
+			"processors": []interface{}{
+				map[string]interface{}{"bloblang": mapping.String()},
+			},
+		})
+	}
+	cases = append(cases, map[string]interface{}{
+		"processors": []interface{}{
+			map[string]interface{}{"bloblang": `root = throw("no canned response matched the message")`},
+		},
+	})
+
+	var procNode yaml.Node
+	if err := procNode.Encode(map[string]interface{}{"switch": cases}); err != nil {
+		return node, err
+	}
+	return procNode, nil
+}
+
+// captureMockNode returns a mock output config that writes messages into a
+// named pipe where they can be captured by the test.
+func captureMockNode(pipe string) (yaml.Node, error) {
+	var node yaml.Node
+	err := node.Encode(map[string]interface{}{"inproc": pipe})
+	return node, err
+}
+
+func capturePipeName(key string) string {
+	return "benthos_test_capture_" + key
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/parser"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/config"
 	"github.com/benthosdev/benthos/v4/internal/docs"
@@ -80,6 +81,57 @@ func (p *ProcessorsProvider) Provide(jsonPtr string, environment map[string]stri
 	return p.initProcs(confs)
 }
 
+// ProvideOutput attempts to extract and construct an output from a Benthos
+// config. Supports injected mocked components in the parsed config, and any
+// outputs identified by the captures slice (either by label or JSON Pointer)
+// are replaced with mocks that write into a channel, which can be read from
+// the returned map.
+func (p *ProcessorsProvider) ProvideOutput(jsonPtr string, environment map[string]string, mocks map[string]yaml.Node, captures []string) (output.Streamed, map[string]<-chan message.Transaction, error) {
+	allMocks := make(map[string]yaml.Node, len(mocks)+len(captures))
+	for k, v := range mocks {
+		allMocks[k] = v
+	}
+	for _, c := range captures {
+		if _, exists := allMocks[c]; exists {
+			return nil, nil, fmt.Errorf("output '%v' cannot be both mocked and captured", c)
+		}
+		node, err := captureMockNode(capturePipeName(c))
+		if err != nil {
+			return nil, nil, err
+		}
+		allMocks[c] = node
+	}
+
+	mgrConf, root, targetPath, err := p.resolveTarget(jsonPtr, environment, allMocks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outConf := output.NewConfig()
+	if err := root.Decode(&outConf); err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve case output from '%v': %v", targetPath, err)
+	}
+
+	mgr, err := manager.New(mgrConf, mock.NewManager(), p.logger, metrics.Noop())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialise resources: %v", err)
+	}
+
+	out, err := mgr.NewOutput(outConf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialise output: %v", err)
+	}
+
+	pipes := make(map[string]<-chan message.Transaction, len(captures))
+	for _, c := range captures {
+		if pipes[c], err = mgr.GetPipe(capturePipeName(c)); err != nil {
+			out.CloseAsync()
+			return nil, nil, fmt.Errorf("failed to capture output '%v': %v", c, err)
+		}
+	}
+	return out, pipes, nil
+}
+
 // ProvideBloblang attempts to parse a Bloblang mapping and returns a processor
 // slice that executes it.
 func (p *ProcessorsProvider) ProvideBloblang(pathStr string) ([]processor.V1, error) {
@@ -221,10 +273,36 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		return confs, nil
 	}
 
-	targetPath, procPath, err := resolveProcessorsPointer(p.targetPath, jsonPtr)
+	mgrConf, root, targetPath, err := p.resolveTarget(jsonPtr, environment, mocks)
 	if err != nil {
 		return confs, err
 	}
+	confs.mgr = mgrConf
+
+	if root.Kind == yaml.SequenceNode {
+		if err = root.Decode(&confs.procs); err != nil {
+			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+		}
+	} else {
+		var procConf processor.Config
+		if err = root.Decode(&procConf); err != nil {
+			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+		}
+		confs.procs = append(confs.procs, procConf)
+	}
+
+	p.cachedConfigs[cacheKey] = confs
+	return confs, nil
+}
+
+// resolveTarget parses the config file targeted by a JSON Pointer (or label),
+// applies any mocks to it and returns the resources config of the file along
+// with the YAML node of the target.
+func (p *ProcessorsProvider) resolveTarget(jsonPtr string, environment map[string]string, mocks map[string]yaml.Node) (mgrWrapper manager.ResourceConfig, root *yaml.Node, targetPath string, err error) {
+	var procPath string
+	if targetPath, procPath, err = resolveProcessorsPointer(p.targetPath, jsonPtr); err != nil {
+		return
+	}
 	if targetPath == "" {
 		targetPath = p.targetPath
 	}
@@ -241,38 +319,46 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 
 	remainingMocks := map[string]yaml.Node{}
 	for k, v := range mocks {
+		if v, err = expandCannedResponses(filepath.Dir(p.targetPath), v); err != nil {
+			err = fmt.Errorf("failed to parse mock '%v': %w", k, err)
+			return
+		}
 		remainingMocks[k] = v
 	}
 
-	configBytes, _, err := config.ReadFileEnvSwap(targetPath)
-	if err != nil {
-		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+	var configBytes []byte
+	if configBytes, _, err = config.ReadFileEnvSwap(targetPath); err != nil {
+		err = fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+		return
 	}
 
-	mgrWrapper := manager.NewResourceConfig()
+	mgrWrapper = manager.NewResourceConfig()
 	if err = yaml.Unmarshal(configBytes, &mgrWrapper); err != nil {
-		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+		err = fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+		return
 	}
 
 	for _, path := range p.resourcesPaths {
-		resourceBytes, _, err := config.ReadFileEnvSwap(path)
-		if err != nil {
-			return confs, fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
+		resourceBytes, _, rerr := config.ReadFileEnvSwap(path)
+		if rerr != nil {
+			err = fmt.Errorf("failed to parse resources config file '%v': %v", path, rerr)
+			return
 		}
 		extraMgrWrapper := manager.NewResourceConfig()
 		if err = yaml.Unmarshal(resourceBytes, &extraMgrWrapper); err != nil {
-			return confs, fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
+			err = fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
+			return
 		}
 		if err = mgrWrapper.AddFrom(&extraMgrWrapper); err != nil {
-			return confs, fmt.Errorf("failed to merge resources from '%v': %v", path, err)
+			err = fmt.Errorf("failed to merge resources from '%v': %v", path, err)
+			return
 		}
 	}
 
-	confs.mgr = mgrWrapper
-
-	root := &yaml.Node{}
+	root = &yaml.Node{}
 	if err = yaml.Unmarshal(configBytes, root); err != nil {
-		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+		err = fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+		return
 	}
 
 	// Replace mock components, starting with all absolute paths in JSON pointer
@@ -282,12 +368,14 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		if !strings.HasPrefix(k, "/") {
 			continue
 		}
-		mockPathSlice, err := gabs.JSONPointerToSlice(k)
-		if err != nil {
-			return confs, fmt.Errorf("failed to parse mock path '%v': %w", k, err)
+		mockPathSlice, perr := gabs.JSONPointerToSlice(k)
+		if perr != nil {
+			err = fmt.Errorf("failed to parse mock path '%v': %w", k, perr)
+			return
 		}
 		if err = confSpec.SetYAMLPath(docs.DeprecatedProvider, root, &v, mockPathSlice...); err != nil {
-			return confs, fmt.Errorf("failed to set mock '%v': %w", k, err)
+			err = fmt.Errorf("failed to set mock '%v': %w", k, err)
+			return
 		}
 		delete(remainingMocks, k)
 	}
@@ -298,10 +386,12 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		for k, v := range remainingMocks {
 			mockPathSlice, exists := labelsToPaths[k]
 			if !exists {
-				return confs, fmt.Errorf("mock for label '%v' could not be applied as the label was not found in the test target file, it is not currently possible to mock resources imported separate to the test file", k)
+				err = fmt.Errorf("mock for label '%v' could not be applied as the label was not found in the test target file, it is not currently possible to mock resources imported separate to the test file", k)
+				return
 			}
 			if err = confSpec.SetYAMLPath(docs.DeprecatedProvider, root, &v, mockPathSlice...); err != nil {
-				return confs, fmt.Errorf("failed to set mock '%v': %w", k, err)
+				err = fmt.Errorf("failed to set mock '%v': %w", k, err)
+				return
 			}
 			delete(remainingMocks, k)
 		}
@@ -310,33 +400,22 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 	var pathSlice []string
 	if strings.HasPrefix(procPath, "/") {
 		if pathSlice, err = gabs.JSONPointerToSlice(procPath); err != nil {
-			return confs, fmt.Errorf("failed to parse case processors path '%v': %w", procPath, err)
+			err = fmt.Errorf("failed to parse case processors path '%v': %w", procPath, err)
+			return
 		}
 	} else {
 		if len(labelsToPaths) == 0 {
 			confSpec.YAMLLabelsToPaths(docs.DeprecatedProvider, root, labelsToPaths, nil)
 		}
+		var exists bool
 		if pathSlice, exists = labelsToPaths[procPath]; !exists {
-			return confs, fmt.Errorf("target for label '%v' failed as the label was not found in the test target file, it is not currently possible to target resources imported separate to the test file", procPath)
+			err = fmt.Errorf("target for label '%v' failed as the label was not found in the test target file, it is not currently possible to target resources imported separate to the test file", procPath)
+			return
 		}
 	}
 
 	if root, err = docs.GetYAMLPath(root, pathSlice...); err != nil {
-		return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+		err = fmt.Errorf("failed to resolve case target from '%v': %v", targetPath, err)
 	}
-
-	if root.Kind == yaml.SequenceNode {
-		if err = root.Decode(&confs.procs); err != nil {
-			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
-		}
-	} else {
-		var procConf processor.Config
-		if err = root.Decode(&procConf); err != nil {
-			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
-		}
-		confs.procs = append(confs.procs, procConf)
-	}
-
-	p.cachedConfigs[cacheKey] = confs
-	return confs, nil
+	return
 }
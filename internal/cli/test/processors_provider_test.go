@@ -1,9 +1,11 @@
 package test_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -197,6 +199,107 @@ pipeline:
 	assert.Equal(t, "starts with first mock first proc second mock second proc", string(msgs[0].Get(0).Get()))
 }
 
+func TestProcessorsProviderCannedResponses(t *testing.T) {
+	files := map[string]string{
+		"config1.yaml": `
+pipeline:
+  processors:
+    - label: get_user
+      http:
+        url: http://example.com/users
+        verb: POST
+`,
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	mocks := map[string]yaml.Node{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+get_user:
+  canned_responses:
+    - match: this.id == 1
+      json_content:
+        name: foo
+      metadata:
+        http_status_code: "200"
+    - match: this.id == 2
+      content: not found
+      metadata:
+        http_status_code: "404"
+`), &mocks))
+
+	provider := test.NewProcessorsProvider(filepath.Join(testDir, "config1.yaml"))
+	procs, err := provider.Provide("/pipeline/processors", nil, mocks)
+	require.NoError(t, err)
+	require.Len(t, procs, 1)
+
+	msgs, res := processor.ExecuteAll(procs, message.QuickBatch([][]byte{
+		[]byte(`{"id":1}`),
+		[]byte(`{"id":2}`),
+		[]byte(`{"id":3}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	require.Equal(t, 3, msgs[0].Len())
+
+	assert.Equal(t, `{"name":"foo"}`, string(msgs[0].Get(0).Get()))
+	assert.Equal(t, "200", msgs[0].Get(0).MetaGet("http_status_code"))
+	assert.Equal(t, "not found", string(msgs[0].Get(1).Get()))
+	assert.Equal(t, "404", msgs[0].Get(1).MetaGet("http_status_code"))
+	assert.Error(t, msgs[0].Get(2).ErrorGet())
+}
+
+func TestProcessorsProviderOutputCaptures(t *testing.T) {
+	files := map[string]string{
+		"config1.yaml": `
+output:
+  switch:
+    cases:
+      - check: this.type == "foo"
+        output:
+          label: foo_out
+          http_client:
+            url: http://example.com/foo
+      - output:
+          label: bar_out
+          http_client:
+            url: http://example.com/bar
+`,
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	provider := test.NewProcessorsProvider(filepath.Join(testDir, "config1.yaml"))
+	out, pipes, err := provider.ProvideOutput("/output", nil, nil, []string{"foo_out", "bar_out"})
+	require.NoError(t, err)
+	require.Len(t, pipes, 2)
+
+	tranChan := make(chan message.Transaction)
+	require.NoError(t, out.Consume(tranChan))
+
+	resChan := make(chan error, 1)
+	go func() {
+		tranChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte(`{"type":"foo"}`)}), resChan)
+	}()
+
+	select {
+	case tran := <-pipes["foo_out"]:
+		assert.Equal(t, `{"type":"foo"}`, string(tran.Payload.Get(0).Get()))
+		require.NoError(t, tran.Ack(context.Background(), nil))
+	case tran := <-pipes["bar_out"]:
+		t.Fatalf("unexpected write to bar_out: %s", tran.Payload.Get(0).Get())
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	require.NoError(t, <-resChan)
+
+	close(tranChan)
+	out.CloseAsync()
+	require.NoError(t, out.WaitForClose(time.Second*5))
+}
+
 func TestProcessorsProviderMocksFromLabel(t *testing.T) {
 	files := map[string]string{
 		"config1.yaml": `
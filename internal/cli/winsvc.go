@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+)
+
+const defaultWindowsServiceName = "benthos"
+
+// windowsServiceArgs returns the arguments that an installed service should be
+// executed with, which includes global flags that point to config files as
+// absolute paths, since services are not started from the current directory.
+func windowsServiceArgs(c *cli.Context, name string) ([]string, error) {
+	var args []string
+
+	absPath := func(p string) (string, error) {
+		if p == "" {
+			return p, nil
+		}
+		return filepath.Abs(p)
+	}
+
+	if confPath := c.String("config"); confPath != "" {
+		p, err := absPath(confPath)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--config", p)
+	}
+	for _, r := range c.StringSlice("resources") {
+		p, err := absPath(r)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--resources", p)
+	}
+	for _, t := range c.StringSlice("templates") {
+		p, err := absPath(t)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--templates", p)
+	}
+	for _, pl := range c.StringSlice("plugins") {
+		p, err := absPath(pl)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--plugins", p)
+	}
+	if envPath := c.String("env-file"); envPath != "" {
+		p, err := absPath(envPath)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--env-file", p)
+	}
+	for _, s := range c.StringSlice("set") {
+		args = append(args, "--set", s)
+	}
+	if lvl := c.String("log.level"); lvl != "" {
+		args = append(args, "--log.level", lvl)
+	}
+	if c.Bool("chilled") {
+		args = append(args, "--chilled")
+	}
+	if c.Bool("watcher") {
+		args = append(args, "--watcher")
+	}
+
+	return append(args, "service", "run", "--name", name), nil
+}
+
+func windowsServiceCliCommand() *cli.Command {
+	nameFlag := &cli.StringFlag{
+		Name:  "name",
+		Value: defaultWindowsServiceName,
+		Usage: "The name of the Windows service.",
+	}
+
+	return &cli.Command{
+		Name:  "service",
+		Usage: "Install, uninstall and run Benthos as a Windows service",
+		Description: `
+Manages Benthos as a native Windows service. Global flags such as the config
+and resource files provided when installing the service are used each time
+the service is started:
+
+  benthos -c ./config.yaml -r ./resources.yaml service install
+  benthos service uninstall
+
+Once installed the service can be controlled with the Services app, or with
+commands such as 'sc.exe start benthos'. The run subcommand is executed by
+the service manager and is not intended to be called directly.`[1:],
+		Subcommands: []*cli.Command{
+			{
+				Name:  "install",
+				Usage: "Install Benthos as a Windows service that starts automatically",
+				Flags: []cli.Flag{
+					nameFlag,
+					&cli.StringFlag{
+						Name:  "display-name",
+						Value: "Benthos",
+						Usage: "The display name of the Windows service.",
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Value: "Benthos stream processor",
+						Usage: "The description of the Windows service.",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					exePath, err := os.Executable()
+					if err != nil {
+						return err
+					}
+					args, err := windowsServiceArgs(c, c.String("name"))
+					if err != nil {
+						return err
+					}
+					if err := installWindowsService(c.String("name"), c.String("display-name"), c.String("description"), exePath, args); err != nil {
+						return fmt.Errorf("failed to install service: %w", err)
+					}
+					fmt.Printf("Installed service %v\n", c.String("name"))
+					return nil
+				},
+			},
+			{
+				Name:  "uninstall",
+				Usage: "Remove an installed Windows service",
+				Flags: []cli.Flag{nameFlag},
+				Action: func(c *cli.Context) error {
+					if err := uninstallWindowsService(c.String("name")); err != nil {
+						return fmt.Errorf("failed to uninstall service: %w", err)
+					}
+					fmt.Printf("Uninstalled service %v\n", c.String("name"))
+					return nil
+				},
+			},
+			{
+				Name:  "run",
+				Usage: "Run Benthos under the Windows service manager",
+				Flags: []cli.Flag{nameFlag},
+				Action: func(c *cli.Context) error {
+					return runWindowsService(c.String("name"), func(ctx context.Context) int {
+						optContext = ctx
+						return cmdService(
+							c.String("config"),
+							c.StringSlice("resources"),
+							c.StringSlice("set"),
+							c.String("log.level"),
+							!c.Bool("chilled"),
+							c.Bool("watcher"),
+							false,
+							false,
+							nil,
+						)
+					})
+				},
+			},
+		},
+	}
+}
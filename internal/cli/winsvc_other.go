@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"context"
+	"errors"
+)
+
+var errWindowsServiceUnsupported = errors.New("windows services are only supported on Windows")
+
+func installWindowsService(name, displayName, description, exePath string, args []string) error {
+	return errWindowsServiceUnsupported
+}
+
+func uninstallWindowsService(name string) error {
+	return errWindowsServiceUnsupported
+}
+
+func runWindowsService(name string, run func(ctx context.Context) int) error {
+	return errWindowsServiceUnsupported
+}
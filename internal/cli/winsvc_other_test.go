@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowsServiceUnsupported(t *testing.T) {
+	for _, args := range [][]string{
+		{"service", "install"},
+		{"service", "install", "--name", "foo"},
+		{"service", "uninstall"},
+		{"service", "run", "--name", "foo"},
+	} {
+		err := windowsServiceTestApp().Run(append([]string{"benthos"}, args...))
+		require.Error(t, err, args)
+		assert.ErrorIs(t, err, errWindowsServiceUnsupported, args)
+	}
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// windowsServiceTestApp returns an app with the service command and the global
+// flags that are passed on to an installed service.
+func windowsServiceTestApp() *cli.App {
+	return &cli.App{
+		Name: "benthos",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "log.level"},
+			&cli.StringSliceFlag{Name: "set", Aliases: []string{"s"}},
+			&cli.StringFlag{Name: "config", Aliases: []string{"c"}},
+			&cli.StringSliceFlag{Name: "resources", Aliases: []string{"r"}},
+			&cli.StringSliceFlag{Name: "templates", Aliases: []string{"t"}},
+			&cli.StringSliceFlag{Name: "plugins"},
+			&cli.StringFlag{Name: "env-file", Aliases: []string{"e"}},
+			&cli.BoolFlag{Name: "chilled"},
+			&cli.BoolFlag{Name: "watcher", Aliases: []string{"w"}},
+		},
+		Commands: []*cli.Command{
+			windowsServiceCliCommand(),
+		},
+	}
+}
+
+func TestWindowsServiceFlags(t *testing.T) {
+	absPath := func(p string) string {
+		t.Helper()
+		abs, err := filepath.Abs(p)
+		require.NoError(t, err)
+		return abs
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]string
+		svcArgs  []string
+	}{
+		{
+			name: "install defaults",
+			args: []string{"service", "install"},
+			expected: map[string]string{
+				"name":         "benthos",
+				"display-name": "Benthos",
+				"description":  "Benthos stream processor",
+			},
+			svcArgs: []string{"service", "run", "--name", "benthos"},
+		},
+		{
+			name: "install with global flags",
+			args: []string{
+				"-c", "./config.yaml",
+				"-r", "./foo.yaml", "-r", "./bar.yaml",
+				"-t", "./templates/*.yaml",
+				"--plugins", "./plugins/*.so",
+				"-e", "./.env",
+				"-s", "input.type=stdin",
+				"--log.level", "debug",
+				"--chilled", "-w",
+				"service", "install",
+				"--name", "foo",
+				"--display-name", "Foo",
+				"--description", "Foo processor",
+			},
+			expected: map[string]string{
+				"name":         "foo",
+				"display-name": "Foo",
+				"description":  "Foo processor",
+			},
+			svcArgs: []string{
+				"--config", absPath("./config.yaml"),
+				"--resources", absPath("./foo.yaml"),
+				"--resources", absPath("./bar.yaml"),
+				"--templates", absPath("./templates/*.yaml"),
+				"--plugins", absPath("./plugins/*.so"),
+				"--env-file", absPath("./.env"),
+				"--set", "input.type=stdin",
+				"--log.level", "debug",
+				"--chilled",
+				"--watcher",
+				"service", "run", "--name", "foo",
+			},
+		},
+		{
+			name:     "uninstall defaults",
+			args:     []string{"service", "uninstall"},
+			expected: map[string]string{"name": "benthos"},
+		},
+		{
+			name:     "uninstall with name",
+			args:     []string{"service", "uninstall", "--name", "foo"},
+			expected: map[string]string{"name": "foo"},
+		},
+		{
+			name:     "run defaults",
+			args:     []string{"service", "run"},
+			expected: map[string]string{"name": "benthos"},
+		},
+		{
+			name: "run with name and config",
+			args: []string{"-c", "./config.yaml", "service", "run", "--name", "foo"},
+			expected: map[string]string{
+				"name":   "foo",
+				"config": "./config.yaml",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			app := windowsServiceTestApp()
+
+			var called bool
+			for _, sub := range app.Commands[0].Subcommands {
+				sub.Action = func(c *cli.Context) error {
+					called = true
+					for k, v := range test.expected {
+						assert.Equal(t, v, c.String(k), k)
+					}
+					if test.svcArgs != nil {
+						args, err := windowsServiceArgs(c, c.String("name"))
+						require.NoError(t, err)
+						assert.Equal(t, test.svcArgs, args)
+					}
+					return nil
+				}
+			}
+
+			require.NoError(t, app.Run(append([]string{"benthos"}, test.args...)))
+			assert.True(t, called)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+//go:build windows
+// +build windows
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+func installWindowsService(name, displayName, description, exePath string, args []string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %v already exists", name)
+	}
+
+	s, err := m.CreateService(name, exePath, mgr.Config{
+		DisplayName: displayName,
+		Description: description,
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return nil
+}
+
+func uninstallWindowsService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %v is not installed: %w", name, err)
+	}
+	defer s.Close()
+	return s.Delete()
+}
+
+// windowsService implements svc.Handler by running the Benthos service until
+// the service manager requests that it stops.
+type windowsService struct {
+	run func(ctx context.Context) int
+}
+
+func (w *windowsService) Execute(args []string, reqs <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exitCodeChan := make(chan int, 1)
+	go func() {
+		exitCodeChan <- w.run(ctx)
+	}()
+
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case req := <-reqs:
+			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+			}
+		case code := <-exitCodeChan:
+			changes <- svc.Status{State: svc.StopPending}
+			return code != 0, uint32(code)
+		}
+	}
+}
+
+func runWindowsService(name string, run func(ctx context.Context) int) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return err
+	}
+	if !isService {
+		return errors.New("the run subcommand must be executed by the Windows service manager, to run Benthos directly omit the service subcommand")
+	}
+	return svc.Run(name, &windowsService{run: run})
+}
@@ -7,6 +7,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +17,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/message"
 )
@@ -27,14 +31,18 @@ var ReaderDocs = docs.FieldString(
 	"auto", "EXPERIMENTAL: Attempts to derive a codec for each file based on information such as the extension. For example, a .tar.gz file would be consumed with the `gzip/tar` codec. Defaults to all-bytes.",
 	"all-bytes", "Consume the entire file as a single binary message.",
 	"chunker:x", "Consume the file in chunks of a given number of bytes.",
+	"chunker:x:json_array", "Consume a JSON array in chunks of at most a given number of bytes, where each chunk is a JSON array of whole elements of the original array. An element that exceeds the size is consumed as a chunk of its own.",
+	"chunker:x:lines", "Consume the file in chunks of at most a given number of bytes, where chunks only end at linebreaks, which is useful for consuming large NDJSON files. A line that exceeds the size is consumed as a chunk of its own.",
 	"csv", "Consume structured rows as comma separated values, the first row must be a header row.",
 	"csv:x", "Consume structured rows as values separated by a custom delimiter, the first row must be a header row. The custom delimiter must be a single character, e.g. the codec `\"csv:\\t\"` would consume a tab delimited file.",
 	"delim:x", "Consume the file in segments divided by a custom delimiter.",
 	"gzip", "Decompress a gzip file, this codec should precede another codec, e.g. `gzip/all-bytes`, `gzip/tar`, `gzip/csv`, etc.",
 	"lines", "Consume the file in segments divided by linebreaks.",
+	"lz4", "Decompress an lz4 stream, this codec should precede another codec, e.g. `lz4/all-bytes`, `lz4/lines`, etc.",
 	"multipart", "Consumes the output of another codec and batches messages together. A batch ends when an empty message is consumed. For example, the codec `lines/multipart` could be used to consume multipart messages where an empty line indicates the end of each batch.",
 	"regex:(?m)^\\d\\d:\\d\\d:\\d\\d", "Consume the file in segments divided by regular expression.",
 	"tar", "Parse the file as a tar archive, and consume each file of the archive as a message.",
+	"zstd", "Decompress a zstd stream, this codec should precede another codec, e.g. `zstd/all-bytes`, `zstd/lines`, `zstd/tar`, etc.",
 ).LinterFunc(nil) // Disable default option linter as it doesn't include foo:bar formats.
 
 //------------------------------------------------------------------------------
@@ -179,8 +187,24 @@ func chainedReader(codec string, conf ReaderConfig) (ReaderConstructor, error) {
 	return partCtor, nil
 }
 
+// decompressReadCloser closes both a decompressing reader and the source it
+// reads from.
+type decompressReadCloser struct {
+	io.Reader
+	closeFn func()
+	source  io.ReadCloser
+}
+
+func (d *decompressReadCloser) Close() error {
+	if d.closeFn != nil {
+		d.closeFn()
+	}
+	return d.source.Close()
+}
+
 func ioReader(codec string, conf ReaderConfig) (ioReaderConstructor, bool) {
-	if codec == "gzip" {
+	switch codec {
+	case "gzip":
 		return func(_ string, r io.ReadCloser) (io.ReadCloser, error) {
 			g, err := gzip.NewReader(r)
 			if err != nil {
@@ -189,6 +213,19 @@ func ioReader(codec string, conf ReaderConfig) (ioReaderConstructor, bool) {
 			}
 			return g, nil
 		}, true
+	case "zstd":
+		return func(_ string, r io.ReadCloser) (io.ReadCloser, error) {
+			z, err := zstd.NewReader(r)
+			if err != nil {
+				r.Close()
+				return nil, err
+			}
+			return &decompressReadCloser{Reader: z, closeFn: z.Close, source: r}, nil
+		}, true
+	case "lz4":
+		return func(_ string, r io.ReadCloser) (io.ReadCloser, error) {
+			return &decompressReadCloser{Reader: lz4.NewReader(r), source: r}, nil
+		}, true
 	}
 	return nil, false
 }
@@ -243,13 +280,25 @@ func partReader(codec string, conf ReaderConfig) (ReaderConstructor, bool, error
 		}, true, nil
 	}
 	if strings.HasPrefix(codec, "chunker:") {
-		chunkSize, err := strconv.ParseInt(strings.TrimPrefix(codec, "chunker:"), 10, 64)
+		sizeStr, boundary := strings.TrimPrefix(codec, "chunker:"), ""
+		if i := strings.Index(sizeStr, ":"); i >= 0 {
+			sizeStr, boundary = sizeStr[:i], sizeStr[i+1:]
+		}
+		chunkSize, err := strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
 			return nil, false, fmt.Errorf("invalid chunk size for chunker codec: %w", err)
 		}
-		return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
-			return newChunkerReader(conf, r, chunkSize, fn)
-		}, true, nil
+		switch boundary {
+		case "":
+			return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
+				return newChunkerReader(conf, r, chunkSize, fn)
+			}, true, nil
+		case "lines", "json_array":
+			return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
+				return newBoundaryChunkerReader(r, chunkSize, boundary, fn)
+			}, true, nil
+		}
+		return nil, false, fmt.Errorf("chunker codec boundary not recognised: %v", boundary)
 	}
 	if strings.HasPrefix(codec, "regex:") {
 		by := strings.TrimPrefix(codec, "regex:")
@@ -260,7 +309,66 @@ func partReader(codec string, conf ReaderConfig) (ReaderConstructor, bool, error
 			return newRexExpSplitReader(conf, r, by, fn)
 		}, true, nil
 	}
-	return nil, false, nil
+	return pluginReader(codec, conf)
+}
+
+//------------------------------------------------------------------------------
+
+// PluginReaderConstructor creates a ReaderConstructor for a plugin codec from
+// the argument following the codec name (e.g. the codec `foo:bar` provides the
+// argument `bar`) and the general reader config.
+type PluginReaderConstructor func(arg string, conf ReaderConfig) (ReaderConstructor, error)
+
+var pluginReaders = struct {
+	sync.RWMutex
+	ctors map[string]PluginReaderConstructor
+}{
+	ctors: map[string]PluginReaderConstructor{},
+}
+
+func isBuiltInReader(name string) bool {
+	switch name {
+	case "auto", "all-bytes", "chunker", "csv", "csv-gzip", "delim", "gzip",
+		"lines", "lz4", "multipart", "regex", "tar", "tar-gzip", "zstd":
+		return true
+	}
+	return false
+}
+
+// RegisterPluginReader adds a custom codec that can be referenced by name from
+// the codec field of any codec aware input. Names must not contain the
+// characters `:` or `/`, and must not clash with a built in codec.
+func RegisterPluginReader(name string, ctor PluginReaderConstructor) error {
+	if name == "" || strings.ContainsAny(name, ":/") {
+		return fmt.Errorf("codec name '%v' is invalid", name)
+	}
+	if isBuiltInReader(name) {
+		return fmt.Errorf("codec name '%v' clashes with a built in codec", name)
+	}
+	pluginReaders.Lock()
+	pluginReaders.ctors[name] = ctor
+	pluginReaders.Unlock()
+	return nil
+}
+
+func pluginReader(codec string, conf ReaderConfig) (ReaderConstructor, bool, error) {
+	name, arg := codec, ""
+	if i := strings.Index(codec, ":"); i >= 0 {
+		name, arg = codec[:i], codec[i+1:]
+	}
+
+	pluginReaders.RLock()
+	ctor, exists := pluginReaders.ctors[name]
+	pluginReaders.RUnlock()
+	if !exists {
+		return nil, false, nil
+	}
+
+	rCtor, err := ctor(arg, conf)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create codec '%v': %w", name, err)
+	}
+	return rCtor, true, nil
 }
 
 func convertDeprecatedCodec(codec string) string {
@@ -299,6 +407,10 @@ func autoCodec(conf ReaderConfig) ReaderConstructor {
 			codec = "gzip/tar"
 		} else if strings.HasSuffix(path, ".tar.gz") {
 			codec = "gzip/tar"
+		} else if strings.HasSuffix(path, ".tar.zst") {
+			codec = "zstd/tar"
+		} else if strings.HasSuffix(path, ".tar.lz4") {
+			codec = "lz4/tar"
 		}
 
 		ctor, err := GetReader(codec, conf)
@@ -686,6 +798,172 @@ func (a *chunkerReader) Close(ctx context.Context) error {
 
 //------------------------------------------------------------------------------
 
+// boundaryChunkerReader consumes chunks of up to a maximum number of bytes
+// that only end at the boundaries of units of the content, such as lines or
+// the elements of a JSON array, and therefore each unit is held in memory
+// rather than the entire source.
+type boundaryChunkerReader struct {
+	chunkSize int64
+	nextUnit  func() ([]byte, error)
+	r         io.ReadCloser
+	sourceAck ReaderAckFn
+
+	// The bytes that open, separate and close the units of a chunk.
+	prefix, sep, suffix []byte
+
+	// A unit that did not fit within the previous chunk.
+	carried []byte
+
+	mut      sync.Mutex
+	finished bool
+	pending  int32
+}
+
+func newBoundaryChunkerReader(r io.ReadCloser, chunkSize int64, boundary string, ackFn ReaderAckFn) (Reader, error) {
+	a := &boundaryChunkerReader{
+		chunkSize: chunkSize,
+		r:         r,
+		sourceAck: ackOnce(ackFn),
+	}
+
+	switch boundary {
+	case "lines":
+		buf := bufio.NewReader(r)
+		a.nextUnit = func() ([]byte, error) {
+			line, err := buf.ReadBytes('\n')
+			if err == io.EOF && len(line) > 0 {
+				// The final line of a file might not end with a linebreak.
+				return line, nil
+			}
+			return line, err
+		}
+	case "json_array":
+		a.prefix, a.sep, a.suffix = []byte("["), []byte(","), []byte("]")
+		dec := json.NewDecoder(r)
+		opened := false
+		a.nextUnit = func() ([]byte, error) {
+			if !opened {
+				t, err := dec.Token()
+				if err != nil {
+					if err == io.EOF {
+						err = errors.New("expected a JSON array, found an empty source")
+					}
+					return nil, err
+				}
+				if d, ok := t.(json.Delim); !ok || d != '[' {
+					return nil, fmt.Errorf("expected a JSON array, found %v", t)
+				}
+				opened = true
+			}
+			if !dec.More() {
+				if _, err := dec.Token(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			return raw, nil
+		}
+	}
+	return a, nil
+}
+
+func (a *boundaryChunkerReader) ack(ctx context.Context, err error) error {
+	a.mut.Lock()
+	a.pending--
+	doAck := a.pending == 0 && a.finished
+	a.mut.Unlock()
+
+	if err != nil {
+		return a.sourceAck(ctx, err)
+	}
+	if doAck {
+		return a.sourceAck(ctx, nil)
+	}
+	return nil
+}
+
+func (a *boundaryChunkerReader) Next(ctx context.Context) ([]*message.Part, ReaderAckFn, error) {
+	if a.finished {
+		return nil, nil, io.EOF
+	}
+
+	var units [][]byte
+	size := int64(len(a.prefix) + len(a.suffix))
+	if a.carried != nil {
+		units = append(units, a.carried)
+		size += int64(len(a.carried))
+		a.carried = nil
+	}
+
+	var err error
+	for {
+		var unit []byte
+		if unit, err = a.nextUnit(); err != nil {
+			break
+		}
+		unitSize := int64(len(unit))
+		if len(units) > 0 {
+			unitSize += int64(len(a.sep))
+		}
+		if len(units) > 0 && size+unitSize > a.chunkSize {
+			a.carried = unit
+			break
+		}
+		units = append(units, unit)
+		if size += unitSize; size >= a.chunkSize {
+			break
+		}
+	}
+
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if err != nil {
+		if err == io.EOF {
+			a.finished = true
+		} else {
+			_ = a.sourceAck(ctx, err)
+			return nil, nil, err
+		}
+	}
+
+	if len(units) == 0 {
+		return nil, nil, err
+	}
+
+	chunk := make([]byte, 0, size)
+	chunk = append(chunk, a.prefix...)
+	for i, unit := range units {
+		if i > 0 {
+			chunk = append(chunk, a.sep...)
+		}
+		chunk = append(chunk, unit...)
+	}
+	chunk = append(chunk, a.suffix...)
+
+	a.pending++
+	return []*message.Part{message.NewPart(chunk)}, a.ack, nil
+}
+
+func (a *boundaryChunkerReader) Close(ctx context.Context) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if !a.finished {
+		_ = a.sourceAck(ctx, errors.New("service shutting down"))
+	}
+	if a.pending == 0 {
+		_ = a.sourceAck(ctx, nil)
+	}
+	return a.r.Close()
+}
+
+//------------------------------------------------------------------------------
+
 type tarReader struct {
 	buf       *tar.Reader
 	r         io.ReadCloser
@@ -11,6 +11,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -400,6 +402,25 @@ func TestCSVGzipReaderOld(t *testing.T) {
 	)
 }
 
+func TestLinesZstdReader(t *testing.T) {
+	var zstdBuf bytes.Buffer
+	zw, err := zstd.NewWriter(&zstdBuf)
+	require.NoError(t, err)
+	_, _ = zw.Write([]byte("foo\nbar\nbaz"))
+	require.NoError(t, zw.Close())
+
+	testReaderSuite(t, "zstd/lines", "", zstdBuf.Bytes(), "foo", "bar", "baz")
+}
+
+func TestLinesLZ4Reader(t *testing.T) {
+	var lz4Buf bytes.Buffer
+	zw := lz4.NewWriter(&lz4Buf)
+	_, _ = zw.Write([]byte("foo\nbar\nbaz"))
+	require.NoError(t, zw.Close())
+
+	testReaderSuite(t, "lz4/lines", "", lz4Buf.Bytes(), "foo", "bar", "baz")
+}
+
 func TestAllBytesReader(t *testing.T) {
 	data := []byte("foo\nbar\nbaz")
 	testReaderSuite(t, "all-bytes", "", data, "foo\nbar\nbaz")
@@ -437,6 +458,38 @@ func TestChunkerReader(t *testing.T) {
 			"hell4world", "hell5world", "hell6world",
 		)
 	})
+
+	t.Run("line boundaries", func(t *testing.T) {
+		data := []byte("a\nbb\nccc\ndddd\ne")
+		testReaderSuite(t, "chunker:6:lines", "", data, "a\nbb\n", "ccc\n", "dddd\ne")
+	})
+
+	t.Run("line exceeds chunk size", func(t *testing.T) {
+		data := []byte("toolongline\nx\n")
+		testReaderSuite(t, "chunker:4:lines", "", data, "toolongline\n", "x\n")
+	})
+
+	t.Run("json array boundaries", func(t *testing.T) {
+		data := []byte(`[1, {"a":"b"}, "foo", [2,3]]`)
+		testReaderSuite(t, "chunker:12:json_array", "", data, `[1]`, `[{"a":"b"}]`, `["foo"]`, `[[2,3]]`)
+		testReaderSuite(t, "chunker:20:json_array", "", data, `[1,{"a":"b"},"foo"]`, `[[2,3]]`)
+	})
+}
+
+func TestChunkerReaderBadBoundaries(t *testing.T) {
+	_, err := GetReader("chunker:10:nope", NewReaderConfig())
+	require.Error(t, err)
+
+	ctor, err := GetReader("chunker:10:json_array", NewReaderConfig())
+	require.NoError(t, err)
+
+	r, err := ctor("", noopCloser{bytes.NewReader([]byte(`{"not":"an array"}`)), false}, func(ctx context.Context, err error) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, _, err = r.Next(context.Background())
+	require.Error(t, err)
 }
 
 func TestTarReader(t *testing.T) {
@@ -2,24 +2,31 @@ package codec
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/message"
 )
 
 // WriterDocs is a static field documentation for output codecs.
 var WriterDocs = docs.FieldString(
-	"codec", "The way in which the bytes of messages should be written out into the output data stream. It's possible to write lines using a custom delimiter with the `delim:x` codec, where x is the character sequence custom delimiter.", "lines", "delim:\t", "delim:foobar",
+	"codec", "The way in which the bytes of messages should be written out into the output data stream. It's possible to write lines using a custom delimiter with the `delim:x` codec, where x is the character sequence custom delimiter. The output stream can be compressed by preceding a codec with a compression algorithm, for example `zstd/lines` writes a zstd compressed stream of lines.", "lines", "delim:\t", "delim:foobar", "gzip/lines", "zstd/lines",
 ).HasAnnotatedOptions(
 	"all-bytes", "Only applicable to file based outputs. Writes each message to a file in full, if the file already exists the old content is deleted.",
 	"append", "Append each message to the output stream without any delimiter or special encoding.",
 	"lines", "Append each message to the output stream followed by a line break.",
 	"delim:x", "Append each message to the output stream followed by a custom delimiter.",
+	"gzip", "Compress the output stream with gzip, this codec should precede another codec, e.g. `gzip/lines`.",
+	"lz4", "Compress the output stream with lz4, this codec should precede another codec, e.g. `lz4/lines`.",
+	"zstd", "Compress the output stream with zstd, this codec should precede another codec, e.g. `zstd/lines`.",
 ).LinterFunc(nil) // Disable default option linter as it doesn't include foo:bar formats.
 
 //------------------------------------------------------------------------------
@@ -43,6 +50,21 @@ type WriterConstructor func(io.WriteCloser) (Writer, error)
 
 // GetWriter returns a constructor that creates write codecs.
 func GetWriter(codec string) (WriterConstructor, WriterConfig, error) {
+	if i := strings.Index(codec, "/"); i >= 0 {
+		compressor, ok := compressWriter(codec[:i])
+		if !ok {
+			return nil, WriterConfig{}, fmt.Errorf("unable to follow codec '%v' with '%v'", codec[:i], codec[i+1:])
+		}
+		ctor, conf, err := GetWriter(codec[i+1:])
+		if err != nil {
+			return nil, WriterConfig{}, err
+		}
+		return chainCompressIntoWriterCtor(compressor, ctor), conf, nil
+	}
+	if _, ok := compressWriter(codec); ok {
+		return nil, WriterConfig{}, fmt.Errorf("codec '%v' must be followed by another codec", codec)
+	}
+
 	switch codec {
 	case "all-bytes":
 		return func(w io.WriteCloser) (Writer, error) {
@@ -69,6 +91,85 @@ func GetWriter(codec string) (WriterConstructor, WriterConfig, error) {
 
 //------------------------------------------------------------------------------
 
+// flushWriteCloser is a compressing io.WriteCloser that buffers writes until
+// it is flushed.
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type compressWriterConstructor func(io.WriteCloser) (flushWriteCloser, error)
+
+func compressWriter(codec string) (compressWriterConstructor, bool) {
+	switch codec {
+	case "gzip":
+		return func(w io.WriteCloser) (flushWriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		}, true
+	case "zstd":
+		return func(w io.WriteCloser) (flushWriteCloser, error) {
+			return zstd.NewWriter(w)
+		}, true
+	case "lz4":
+		return func(w io.WriteCloser) (flushWriteCloser, error) {
+			return lz4.NewWriter(w), nil
+		}, true
+	}
+	return nil, false
+}
+
+// compressedWriteCloser closes both a compressing writer and the destination
+// it writes to.
+type compressedWriteCloser struct {
+	flushWriteCloser
+	dest io.WriteCloser
+}
+
+func (c *compressedWriteCloser) Close() error {
+	err := c.flushWriteCloser.Close()
+	if cErr := c.dest.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// compressedWriter flushes the compressor after each message so that the
+// output stream never holds a partially written message, which is important
+// for continuous streams such as sockets.
+type compressedWriter struct {
+	w Writer
+	c flushWriteCloser
+}
+
+func chainCompressIntoWriterCtor(first compressWriterConstructor, second WriterConstructor) WriterConstructor {
+	return func(w io.WriteCloser) (Writer, error) {
+		c, err := first(w)
+		if err != nil {
+			return nil, err
+		}
+		wc := &compressedWriteCloser{flushWriteCloser: c, dest: w}
+		w2, err := second(wc)
+		if err != nil {
+			wc.Close()
+			return nil, err
+		}
+		return &compressedWriter{w: w2, c: c}, nil
+	}
+}
+
+func (c *compressedWriter) Write(ctx context.Context, p *message.Part) error {
+	if err := c.w.Write(ctx, p); err != nil {
+		return err
+	}
+	return c.c.Flush()
+}
+
+func (c *compressedWriter) Close(ctx context.Context) error {
+	return c.w.Close(ctx)
+}
+
+//------------------------------------------------------------------------------
+
 var allBytesConfig = WriterConfig{
 	Truncate:   true,
 	CloseAfter: true,
@@ -0,0 +1,75 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCompressedWriterRoundTrip(t *testing.T) {
+	for _, algo := range []string{"gzip", "lz4", "zstd"} {
+		algo := algo
+		t.Run(algo, func(t *testing.T) {
+			ctx := context.Background()
+
+			wCtor, wConf, err := GetWriter(algo + "/lines")
+			require.NoError(t, err)
+			assert.Equal(t, linesWriterConfig, wConf)
+
+			buf := &closeRecorder{}
+			w, err := wCtor(buf)
+			require.NoError(t, err)
+
+			for _, s := range []string{"foo", "bar", "baz"} {
+				require.NoError(t, w.Write(ctx, message.NewPart([]byte(s))))
+			}
+			require.NoError(t, w.Close(ctx))
+			assert.True(t, buf.closed)
+
+			rCtor, err := GetReader(algo+"/lines", NewReaderConfig())
+			require.NoError(t, err)
+
+			r, err := rCtor("", noopCloser{bytes.NewReader(buf.Bytes()), false}, func(ctx context.Context, err error) error {
+				return nil
+			})
+			require.NoError(t, err)
+
+			var lines []string
+			for {
+				parts, ackFn, err := r.Next(ctx)
+				if err != nil {
+					break
+				}
+				require.NoError(t, ackFn(ctx, nil))
+				for _, p := range parts {
+					lines = append(lines, string(p.Get()))
+				}
+			}
+			assert.Equal(t, []string{"foo", "bar", "baz"}, lines)
+			require.NoError(t, r.Close(ctx))
+		})
+	}
+}
+
+func TestCompressedWriterErrors(t *testing.T) {
+	_, _, err := GetWriter("zstd")
+	require.Error(t, err)
+
+	_, _, err = GetWriter("lines/zstd")
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package input
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,6 +24,7 @@ type AsyncReader struct {
 	connBackoff backoff.BackOff
 
 	allowSkipAcks bool
+	ackTimeout    time.Duration
 
 	typeStr string
 	reader  Async
@@ -34,6 +36,25 @@ type AsyncReader struct {
 	shutSig      *shutdown.Signaller
 }
 
+// ErrAckTimeout is the error used to reject messages that were not resolved
+// within the ack timeout of an AsyncReader.
+var ErrAckTimeout = errors.New("message was not acknowledged within the ack timeout")
+
+// AsyncReaderOpt is a func that configures optional behaviour of an
+// AsyncReader.
+type AsyncReaderOpt func(r *AsyncReader)
+
+// AsyncReaderWithAckTimeout sets a maximum period of time to wait for a
+// consumed message to be resolved downstream, after which it is rejected with
+// ErrAckTimeout as if it were nacked. This prevents messages from being held
+// in flight indefinitely by a wedged output. Any resolution that arrives after
+// the timeout is ignored. A timeout <= 0 disables this behaviour.
+func AsyncReaderWithAckTimeout(timeout time.Duration) AsyncReaderOpt {
+	return func(r *AsyncReader) {
+		r.ackTimeout = timeout
+	}
+}
+
 // NewAsyncReader creates a new AsyncReader input type.
 func NewAsyncReader(
 	typeStr string,
@@ -41,6 +62,7 @@ func NewAsyncReader(
 	r Async,
 	log log.Modular,
 	stats metrics.Type,
+	opts ...AsyncReaderOpt,
 ) (Streamed, error) {
 	boff := backoff.NewExponentialBackOff()
 	boff.InitialInterval = time.Millisecond * 100
@@ -57,6 +79,9 @@ func NewAsyncReader(
 		transactions:  make(chan message.Transaction),
 		shutSig:       shutdown.NewSignaller(),
 	}
+	for _, opt := range opts {
+		opt(rdr)
+	}
 
 	go rdr.loop()
 	return rdr, nil
@@ -72,6 +97,7 @@ func (r *AsyncReader) loop() {
 		mFailedConn = r.stats.GetCounter("input_connection_failed")
 		mLostConn   = r.stats.GetCounter("input_connection_lost")
 		mLatency    = r.stats.GetTimer("input_latency_ns")
+		mAckTimeout = r.stats.GetCounter("input_ack_timeout")
 	)
 
 	defer func() {
@@ -182,10 +208,31 @@ func (r *AsyncReader) loop() {
 		) {
 			defer pendingAcks.Done()
 
+			var timeoutChan <-chan time.Time
+			if r.ackTimeout > 0 {
+				timer := time.NewTimer(r.ackTimeout)
+				defer timer.Stop()
+				timeoutChan = timer.C
+			}
+
 			var res error
 			var open bool
 			select {
 			case res, open = <-rChan:
+			case <-timeoutChan:
+				mAckTimeout.Incr(1)
+				r.log.Warnf("Batch of %v messages consumed from '%v' was not resolved within the ack timeout of %v, it will be rejected\n", m.Len(), r.typeStr, r.ackTimeout)
+				res, open = ErrAckTimeout, true
+
+				// The transaction may still be resolved later on, in which
+				// case the result is consumed and ignored so that the sender
+				// isn't blocked.
+				go func() {
+					select {
+					case <-rChan:
+					case <-r.shutSig.CloseNowChan():
+					}
+				}()
 			case <-r.shutSig.CloseNowChan():
 				// Even if the pipeline is terminating we still want to attempt
 				// to propagate an acknowledgement from in-transit messages.
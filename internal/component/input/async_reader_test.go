@@ -579,6 +579,60 @@ func TestAsyncReaderCloseWithPendingAcks(t *testing.T) {
 	}
 }
 
+func TestAsyncReaderAckTimeout(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	readerImpl := newMockAsyncReader()
+	readerImpl.msgsToSnd = []*message.Batch{message.QuickBatch([][]byte{[]byte("foo")})}
+
+	r, err := NewAsyncReader(
+		"foo", true, readerImpl,
+		log.Noop(), metrics.Noop(),
+		AsyncReaderWithAckTimeout(time.Millisecond*50),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		select {
+		case readerImpl.connChan <- nil:
+		case <-tCtx.Done():
+		}
+		select {
+		case readerImpl.readChan <- nil:
+		case <-tCtx.Done():
+		}
+	}()
+
+	var ts message.Transaction
+	select {
+	case ts = <-r.TransactionChan():
+	case <-tCtx.Done():
+		t.Fatal("Timed out")
+	}
+
+	// Withhold the acknowledgement until the reader gives up on it.
+	select {
+	case readerImpl.ackChan <- nil:
+	case <-tCtx.Done():
+		t.Fatal("Timed out")
+	}
+
+	readerImpl.ackMut.Lock()
+	assert.Equal(t, []error{ErrAckTimeout}, readerImpl.ackRcvd)
+	readerImpl.ackMut.Unlock()
+
+	// A late acknowledgement should not block and is ignored.
+	require.NoError(t, ts.Ack(tCtx, nil))
+
+	readerImpl.ackMut.Lock()
+	assert.Equal(t, []error{ErrAckTimeout}, readerImpl.ackRcvd)
+	readerImpl.ackMut.Unlock()
+
+	r.CloseAsync()
+	require.NoError(t, r.WaitForClose(time.Second))
+}
+
 func TestAsyncReaderSadPath(t *testing.T) {
 	tCtx, done := context.WithTimeout(context.Background(), time.Second*5)
 	defer done()
@@ -1,6 +1,8 @@
 package input
 
 import (
+	"encoding/json"
+
 	btls "github.com/benthosdev/benthos/v4/internal/tls"
 )
 
@@ -8,8 +10,63 @@ import (
 // queue needs to be declared and bound to an exchange, as well as any fields
 // specifying how to accomplish that.
 type AMQP09QueueDeclareConfig struct {
-	Enabled bool `json:"enabled" yaml:"enabled"`
-	Durable bool `json:"durable" yaml:"durable"`
+	Enabled   bool                   `json:"enabled" yaml:"enabled"`
+	Durable   bool                   `json:"durable" yaml:"durable"`
+	Type      string                 `json:"type" yaml:"type"`
+	Arguments map[string]interface{} `json:"arguments" yaml:"arguments"`
+}
+
+// AMQP09ExchangeDeclareConfig contains fields describing an exchange to be
+// declared.
+type AMQP09ExchangeDeclareConfig struct {
+	Exchange string `json:"exchange" yaml:"exchange"`
+	Type     string `json:"type" yaml:"type"`
+	Durable  bool   `json:"durable" yaml:"durable"`
+}
+
+// NewAMQP09ExchangeDeclareConfig returns a new AMQP09ExchangeDeclareConfig
+// with default values.
+func NewAMQP09ExchangeDeclareConfig() AMQP09ExchangeDeclareConfig {
+	return AMQP09ExchangeDeclareConfig{
+		Exchange: "",
+		Type:     "direct",
+		Durable:  true,
+	}
+}
+
+// UnmarshalJSON ensures that when parsing configs that are in a slice the
+// default values are still applied.
+func (e *AMQP09ExchangeDeclareConfig) UnmarshalJSON(bytes []byte) error {
+	type confAlias AMQP09ExchangeDeclareConfig
+	aliased := confAlias(NewAMQP09ExchangeDeclareConfig())
+
+	if err := json.Unmarshal(bytes, &aliased); err != nil {
+		return err
+	}
+
+	*e = AMQP09ExchangeDeclareConfig(aliased)
+	return nil
+}
+
+// UnmarshalYAML ensures that when parsing configs that are in a slice the
+// default values are still applied.
+func (e *AMQP09ExchangeDeclareConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type confAlias AMQP09ExchangeDeclareConfig
+	aliased := confAlias(NewAMQP09ExchangeDeclareConfig())
+
+	if err := unmarshal(&aliased); err != nil {
+		return err
+	}
+
+	*e = AMQP09ExchangeDeclareConfig(aliased)
+	return nil
+}
+
+// AMQP09DeadLetterConfig contains fields describing an exchange that rejected
+// messages are published to.
+type AMQP09DeadLetterConfig struct {
+	Exchange   string `json:"exchange" yaml:"exchange"`
+	RoutingKey string `json:"key" yaml:"key"`
 }
 
 // AMQP09BindingConfig contains fields describing a queue binding to be
@@ -21,16 +78,21 @@ type AMQP09BindingConfig struct {
 
 // AMQP09Config contains configuration for the AMQP09 input type.
 type AMQP09Config struct {
-	URLs               []string                 `json:"urls" yaml:"urls"`
-	Queue              string                   `json:"queue" yaml:"queue"`
-	QueueDeclare       AMQP09QueueDeclareConfig `json:"queue_declare" yaml:"queue_declare"`
-	BindingsDeclare    []AMQP09BindingConfig    `json:"bindings_declare" yaml:"bindings_declare"`
-	ConsumerTag        string                   `json:"consumer_tag" yaml:"consumer_tag"`
-	AutoAck            bool                     `json:"auto_ack" yaml:"auto_ack"`
-	NackRejectPatterns []string                 `json:"nack_reject_patterns" yaml:"nack_reject_patterns"`
-	PrefetchCount      int                      `json:"prefetch_count" yaml:"prefetch_count"`
-	PrefetchSize       int                      `json:"prefetch_size" yaml:"prefetch_size"`
-	TLS                btls.Config              `json:"tls" yaml:"tls"`
+	URLs               []string                      `json:"urls" yaml:"urls"`
+	Queue              string                        `json:"queue" yaml:"queue"`
+	QueueDeclare       AMQP09QueueDeclareConfig      `json:"queue_declare" yaml:"queue_declare"`
+	ExchangesDeclare   []AMQP09ExchangeDeclareConfig `json:"exchanges_declare" yaml:"exchanges_declare"`
+	BindingsDeclare    []AMQP09BindingConfig         `json:"bindings_declare" yaml:"bindings_declare"`
+	ConsumerTag        string                        `json:"consumer_tag" yaml:"consumer_tag"`
+	AutoAck            bool                          `json:"auto_ack" yaml:"auto_ack"`
+	AutoDecompress     bool                          `json:"auto_decompress" yaml:"auto_decompress"`
+	NackRejectPatterns []string                      `json:"nack_reject_patterns" yaml:"nack_reject_patterns"`
+	NackPolicy         NackPolicyConfig              `json:"nack_policy" yaml:"nack_policy"`
+	DeadLetter         AMQP09DeadLetterConfig        `json:"dead_letter" yaml:"dead_letter"`
+	AckTimeout         string                        `json:"ack_timeout" yaml:"ack_timeout"`
+	PrefetchCount      int                           `json:"prefetch_count" yaml:"prefetch_count"`
+	PrefetchSize       int                           `json:"prefetch_size" yaml:"prefetch_size"`
+	TLS                btls.Config                   `json:"tls" yaml:"tls"`
 }
 
 // NewAMQP09Config creates a new AMQP09Config with default values.
@@ -39,15 +101,25 @@ func NewAMQP09Config() AMQP09Config {
 		URLs:  []string{},
 		Queue: "",
 		QueueDeclare: AMQP09QueueDeclareConfig{
-			Enabled: false,
-			Durable: true,
+			Enabled:   false,
+			Durable:   true,
+			Type:      "classic",
+			Arguments: map[string]interface{}{},
 		},
 		ConsumerTag:        "",
 		AutoAck:            false,
+		AutoDecompress:     false,
 		NackRejectPatterns: []string{},
-		PrefetchCount:      10,
-		PrefetchSize:       0,
-		TLS:                btls.NewConfig(),
-		BindingsDeclare:    []AMQP09BindingConfig{},
+		NackPolicy:         NewNackPolicyConfig(),
+		DeadLetter: AMQP09DeadLetterConfig{
+			Exchange:   "",
+			RoutingKey: "",
+		},
+		AckTimeout:       "",
+		PrefetchCount:    10,
+		PrefetchSize:     0,
+		TLS:              btls.NewConfig(),
+		ExchangesDeclare: []AMQP09ExchangeDeclareConfig{},
+		BindingsDeclare:  []AMQP09BindingConfig{},
 	}
 }
@@ -7,10 +7,12 @@ import (
 // AWSSQSConfig contains configuration values for the input type.
 type AWSSQSConfig struct {
 	sess.Config         `json:",inline" yaml:",inline"`
-	URL                 string `json:"url" yaml:"url"`
-	DeleteMessage       bool   `json:"delete_message" yaml:"delete_message"`
-	ResetVisibility     bool   `json:"reset_visibility" yaml:"reset_visibility"`
-	MaxNumberOfMessages int    `json:"max_number_of_messages" yaml:"max_number_of_messages"`
+	URL                 string           `json:"url" yaml:"url"`
+	DeleteMessage       bool             `json:"delete_message" yaml:"delete_message"`
+	ResetVisibility     bool             `json:"reset_visibility" yaml:"reset_visibility"`
+	MaxNumberOfMessages int              `json:"max_number_of_messages" yaml:"max_number_of_messages"`
+	NackPolicy          NackPolicyConfig `json:"nack_policy" yaml:"nack_policy"`
+	AckTimeout          string           `json:"ack_timeout" yaml:"ack_timeout"`
 }
 
 // NewAWSSQSConfig creates a new Config with default values.
@@ -21,5 +23,7 @@ func NewAWSSQSConfig() AWSSQSConfig {
 		DeleteMessage:       true,
 		ResetVisibility:     true,
 		MaxNumberOfMessages: 10,
+		NackPolicy:          NewNackPolicyConfig(),
+		AckTimeout:          "",
 	}
 }
@@ -11,6 +11,9 @@ type AzureQueueStorageConfig struct {
 	DequeueVisibilityTimeout string `json:"dequeue_visibility_timeout" yaml:"dequeue_visibility_timeout"`
 	MaxInFlight              int32  `json:"max_in_flight" yaml:"max_in_flight"`
 	TrackProperties          bool   `json:"track_properties" yaml:"track_properties"`
+	RenewVisibility          bool   `json:"renew_visibility" yaml:"renew_visibility"`
+	MaxDequeueCount          int64  `json:"max_dequeue_count" yaml:"max_dequeue_count"`
+	PoisonQueueName          string `json:"poison_queue_name" yaml:"poison_queue_name"`
 }
 
 // NewAzureQueueStorageConfig creates a new AzureQueueStorageConfig with default
@@ -20,5 +23,8 @@ func NewAzureQueueStorageConfig() AzureQueueStorageConfig {
 		DequeueVisibilityTimeout: "30s",
 		MaxInFlight:              10,
 		TrackProperties:          false,
+		RenewVisibility:          false,
+		MaxDequeueCount:          0,
+		PoisonQueueName:          "",
 	}
 }
@@ -0,0 +1,33 @@
+package input
+
+// AzureTableStorageConfig contains configuration fields for the
+// AzureTableStorage input type.
+type AzureTableStorageConfig struct {
+	StorageAccount          string `json:"storage_account" yaml:"storage_account"`
+	StorageAccessKey        string `json:"storage_access_key" yaml:"storage_access_key"`
+	StorageConnectionString string `json:"storage_connection_string" yaml:"storage_connection_string"`
+	TableName               string `json:"table_name" yaml:"table_name"`
+	Filter                  string `json:"filter" yaml:"filter"`
+	PartitionKeyStart       string `json:"partition_key_start" yaml:"partition_key_start"`
+	PartitionKeyEnd         string `json:"partition_key_end" yaml:"partition_key_end"`
+	PageSize                int32  `json:"page_size" yaml:"page_size"`
+	CheckpointCache         string `json:"checkpoint_cache" yaml:"checkpoint_cache"`
+	CheckpointKey           string `json:"checkpoint_key" yaml:"checkpoint_key"`
+}
+
+// NewAzureTableStorageConfig creates a new AzureTableStorageConfig with default
+// values.
+func NewAzureTableStorageConfig() AzureTableStorageConfig {
+	return AzureTableStorageConfig{
+		StorageAccount:          "",
+		StorageAccessKey:        "",
+		StorageConnectionString: "",
+		TableName:               "",
+		Filter:                  "",
+		PartitionKeyStart:       "",
+		PartitionKeyEnd:         "",
+		PageSize:                1000,
+		CheckpointCache:         "",
+		CheckpointKey:           "",
+	}
+}
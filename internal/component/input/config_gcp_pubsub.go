@@ -2,15 +2,20 @@ package input
 
 import (
 	"cloud.google.com/go/pubsub"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 )
 
 // GCPPubSubConfig contains configuration values for the input type.
 type GCPPubSubConfig struct {
-	ProjectID              string `json:"project" yaml:"project"`
-	SubscriptionID         string `json:"subscription" yaml:"subscription"`
-	MaxOutstandingMessages int    `json:"max_outstanding_messages" yaml:"max_outstanding_messages"`
-	MaxOutstandingBytes    int    `json:"max_outstanding_bytes" yaml:"max_outstanding_bytes"`
-	Sync                   bool   `json:"sync" yaml:"sync"`
+	ProjectID              string             `json:"project" yaml:"project"`
+	SubscriptionID         string             `json:"subscription" yaml:"subscription"`
+	MaxOutstandingMessages int                `json:"max_outstanding_messages" yaml:"max_outstanding_messages"`
+	MaxOutstandingBytes    int                `json:"max_outstanding_bytes" yaml:"max_outstanding_bytes"`
+	Sync                   bool               `json:"sync" yaml:"sync"`
+	MaxExtension           string             `json:"max_extension" yaml:"max_extension"`
+	MaxExtensionPeriod     string             `json:"max_extension_period" yaml:"max_extension_period"`
+	Credentials            credentials.Config `json:"credentials" yaml:"credentials"`
 }
 
 // NewGCPPubSubConfig creates a new Config with default values.
@@ -21,5 +26,8 @@ func NewGCPPubSubConfig() GCPPubSubConfig {
 		MaxOutstandingMessages: pubsub.DefaultReceiveSettings.MaxOutstandingMessages,
 		MaxOutstandingBytes:    pubsub.DefaultReceiveSettings.MaxOutstandingBytes,
 		Sync:                   false,
+		MaxExtension:           "60m",
+		MaxExtensionPeriod:     "",
+		Credentials:            credentials.NewConfig(),
 	}
 }
@@ -11,6 +11,7 @@ type HTTPServerResponseConfig struct {
 	Status          string                        `json:"status" yaml:"status"`
 	Headers         map[string]string             `json:"headers" yaml:"headers"`
 	ExtractMetadata imetadata.IncludeFilterConfig `json:"metadata_headers" yaml:"metadata_headers"`
+	Streaming       string                        `json:"streaming" yaml:"streaming"`
 }
 
 // NewHTTPServerResponseConfig creates a new HTTPServerConfig with default values.
@@ -21,6 +22,25 @@ func NewHTTPServerResponseConfig() HTTPServerResponseConfig {
 			"Content-Type": "application/octet-stream",
 		},
 		ExtractMetadata: imetadata.NewIncludeFilterConfig(),
+		Streaming:       "none",
+	}
+}
+
+// HTTPServerJournalConfig contains configuration fields for the write-ahead
+// journal of the HTTPServer input.
+type HTTPServerJournalConfig struct {
+	Enabled    bool   `json:"enabled" yaml:"enabled"`
+	Path       string `json:"path" yaml:"path"`
+	MaxEntries int    `json:"max_entries" yaml:"max_entries"`
+}
+
+// NewHTTPServerJournalConfig creates a new HTTPServerJournalConfig with
+// default values.
+func NewHTTPServerJournalConfig() HTTPServerJournalConfig {
+	return HTTPServerJournalConfig{
+		Enabled:    false,
+		Path:       "",
+		MaxEntries: 10000,
 	}
 }
 
@@ -36,8 +56,10 @@ type HTTPServerConfig struct {
 	RateLimit          string                   `json:"rate_limit" yaml:"rate_limit"`
 	CertFile           string                   `json:"cert_file" yaml:"cert_file"`
 	KeyFile            string                   `json:"key_file" yaml:"key_file"`
+	MultipartMaxMemory int64                    `json:"multipart_max_memory" yaml:"multipart_max_memory"`
 	CORS               httpdocs.ServerCORS      `json:"cors" yaml:"cors"`
 	Response           HTTPServerResponseConfig `json:"sync_response" yaml:"sync_response"`
+	Journal            HTTPServerJournalConfig  `json:"journal" yaml:"journal"`
 }
 
 // NewHTTPServerConfig creates a new HTTPServerConfig with default values.
@@ -51,11 +73,13 @@ func NewHTTPServerConfig() HTTPServerConfig {
 		AllowedVerbs: []string{
 			"POST",
 		},
-		Timeout:   "5s",
-		RateLimit: "",
-		CertFile:  "",
-		KeyFile:   "",
-		CORS:      httpdocs.NewServerCORS(),
-		Response:  NewHTTPServerResponseConfig(),
+		Timeout:            "5s",
+		RateLimit:          "",
+		CertFile:           "",
+		KeyFile:            "",
+		MultipartMaxMemory: 32 << 20,
+		CORS:               httpdocs.NewServerCORS(),
+		Response:           NewHTTPServerResponseConfig(),
+		Journal:            NewHTTPServerJournalConfig(),
 	}
 }
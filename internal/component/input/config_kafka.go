@@ -36,10 +36,13 @@ type KafkaConfig struct {
 	ExtractTracingMap   string                   `json:"extract_tracing_map" yaml:"extract_tracing_map"`
 	MaxProcessingPeriod string                   `json:"max_processing_period" yaml:"max_processing_period"`
 	FetchBufferCap      int                      `json:"fetch_buffer_cap" yaml:"fetch_buffer_cap"`
+	LagMetricsPeriod    string                   `json:"lag_metrics_period" yaml:"lag_metrics_period"`
 	StartFromOldest     bool                     `json:"start_from_oldest" yaml:"start_from_oldest"`
+	AutoDecompress      bool                     `json:"auto_decompress" yaml:"auto_decompress"`
 	TargetVersion       string                   `json:"target_version" yaml:"target_version"`
 	TLS                 btls.Config              `json:"tls" yaml:"tls"`
 	SASL                sasl.Config              `json:"sasl" yaml:"sasl"`
+	SharedClient        bool                     `json:"shared_client" yaml:"shared_client"`
 	Batching            batchconfig.Config       `json:"batching" yaml:"batching"`
 }
 
@@ -56,10 +59,13 @@ func NewKafkaConfig() KafkaConfig {
 		CheckpointLimit:     1024,
 		MaxProcessingPeriod: "100ms",
 		FetchBufferCap:      256,
+		LagMetricsPeriod:    "",
 		StartFromOldest:     true,
+		AutoDecompress:      false,
 		TargetVersion:       "2.0.0",
 		TLS:                 btls.NewConfig(),
 		SASL:                sasl.NewConfig(),
+		SharedClient:        false,
 		Batching:            batchconfig.NewConfig(),
 	}
 }
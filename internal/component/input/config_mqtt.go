@@ -18,22 +18,25 @@ type MQTTConfig struct {
 	Password              string        `json:"password" yaml:"password"`
 	ConnectTimeout        string        `json:"connect_timeout" yaml:"connect_timeout"`
 	KeepAlive             int64         `json:"keepalive" yaml:"keepalive"`
+	ProtocolVersion       string        `json:"protocol_version" yaml:"protocol_version"`
+	SessionExpiryInterval string        `json:"session_expiry_interval" yaml:"session_expiry_interval"`
 	TLS                   tls.Config    `json:"tls" yaml:"tls"`
 }
 
 // NewMQTTConfig creates a new MQTTConfig with default values.
 func NewMQTTConfig() MQTTConfig {
 	return MQTTConfig{
-		URLs:           []string{},
-		QoS:            1,
-		Topics:         []string{},
-		ClientID:       "",
-		Will:           mqttconf.EmptyWill(),
-		CleanSession:   true,
-		User:           "",
-		Password:       "",
-		ConnectTimeout: "30s",
-		KeepAlive:      30,
-		TLS:            tls.NewConfig(),
+		URLs:            []string{},
+		QoS:             1,
+		Topics:          []string{},
+		ClientID:        "",
+		Will:            mqttconf.EmptyWill(),
+		CleanSession:    true,
+		User:            "",
+		Password:        "",
+		ConnectTimeout:  "30s",
+		KeepAlive:       30,
+		ProtocolVersion: "3.1.1",
+		TLS:             tls.NewConfig(),
 	}
 }
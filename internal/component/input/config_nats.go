@@ -7,22 +7,53 @@ import (
 
 // NATSConfig contains configuration fields for the NATS input type.
 type NATSConfig struct {
-	URLs          []string    `json:"urls" yaml:"urls"`
-	Subject       string      `json:"subject" yaml:"subject"`
-	QueueID       string      `json:"queue" yaml:"queue"`
-	PrefetchCount int         `json:"prefetch_count" yaml:"prefetch_count"`
-	TLS           btls.Config `json:"tls" yaml:"tls"`
-	Auth          auth.Config `json:"auth" yaml:"auth"`
+	URLs           []string            `json:"urls" yaml:"urls"`
+	Subject        string              `json:"subject" yaml:"subject"`
+	QueueID        string              `json:"queue" yaml:"queue"`
+	PrefetchCount  int                 `json:"prefetch_count" yaml:"prefetch_count"`
+	AutoDecompress bool                `json:"auto_decompress" yaml:"auto_decompress"`
+	JetStream      NATSJetStreamConfig `json:"jetstream" yaml:"jetstream"`
+	NackPolicy     NackPolicyConfig    `json:"nack_policy" yaml:"nack_policy"`
+	AckTimeout     string              `json:"ack_timeout" yaml:"ack_timeout"`
+	TLS            btls.Config         `json:"tls" yaml:"tls"`
+	Auth           auth.Config         `json:"auth" yaml:"auth"`
+}
+
+// NATSJetStreamConfig contains configuration fields for consuming from a NATS
+// JetStream pull consumer.
+type NATSJetStreamConfig struct {
+	Enabled       bool   `json:"enabled" yaml:"enabled"`
+	Stream        string `json:"stream" yaml:"stream"`
+	Durable       string `json:"durable" yaml:"durable"`
+	AckWait       string `json:"ack_wait" yaml:"ack_wait"`
+	MaxAckPending int    `json:"max_ack_pending" yaml:"max_ack_pending"`
+	FetchSize     int    `json:"fetch_size" yaml:"fetch_size"`
+}
+
+// NewNATSJetStreamConfig creates a new NATSJetStreamConfig with default values.
+func NewNATSJetStreamConfig() NATSJetStreamConfig {
+	return NATSJetStreamConfig{
+		Enabled:       false,
+		Stream:        "",
+		Durable:       "",
+		AckWait:       "30s",
+		MaxAckPending: 1024,
+		FetchSize:     32,
+	}
 }
 
 // NewNATSConfig creates a new NATSConfig with default values.
 func NewNATSConfig() NATSConfig {
 	return NATSConfig{
-		URLs:          []string{},
-		Subject:       "",
-		QueueID:       "",
-		PrefetchCount: 32,
-		TLS:           btls.NewConfig(),
-		Auth:          auth.New(),
+		URLs:           []string{},
+		Subject:        "",
+		QueueID:        "",
+		PrefetchCount:  32,
+		AutoDecompress: false,
+		JetStream:      NewNATSJetStreamConfig(),
+		NackPolicy:     NewNackPolicyConfig(),
+		AckTimeout:     "",
+		TLS:            btls.NewConfig(),
+		Auth:           auth.New(),
 	}
 }
@@ -8,15 +8,24 @@ import (
 // type.
 type RedisStreamsConfig struct {
 	bredis.Config   `json:",inline" yaml:",inline"`
-	BodyKey         string   `json:"body_key" yaml:"body_key"`
-	Streams         []string `json:"streams" yaml:"streams"`
-	CreateStreams   bool     `json:"create_streams" yaml:"create_streams"`
-	ConsumerGroup   string   `json:"consumer_group" yaml:"consumer_group"`
-	ClientID        string   `json:"client_id" yaml:"client_id"`
-	Limit           int64    `json:"limit" yaml:"limit"`
-	StartFromOldest bool     `json:"start_from_oldest" yaml:"start_from_oldest"`
-	CommitPeriod    string   `json:"commit_period" yaml:"commit_period"`
-	Timeout         string   `json:"timeout" yaml:"timeout"`
+	BodyKey         string                      `json:"body_key" yaml:"body_key"`
+	Streams         []string                    `json:"streams" yaml:"streams"`
+	CreateStreams   bool                        `json:"create_streams" yaml:"create_streams"`
+	ConsumerGroup   string                      `json:"consumer_group" yaml:"consumer_group"`
+	ClientID        string                      `json:"client_id" yaml:"client_id"`
+	Limit           int64                       `json:"limit" yaml:"limit"`
+	StartFromOldest bool                        `json:"start_from_oldest" yaml:"start_from_oldest"`
+	CommitPeriod    string                      `json:"commit_period" yaml:"commit_period"`
+	Timeout         string                      `json:"timeout" yaml:"timeout"`
+	AutoClaim       RedisStreamsAutoClaimConfig `json:"auto_claim" yaml:"auto_claim"`
+}
+
+// RedisStreamsAutoClaimConfig contains configuration fields for claiming the
+// pending entries of other consumers within a group.
+type RedisStreamsAutoClaimConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	MinIdle string `json:"min_idle" yaml:"min_idle"`
+	Period  string `json:"period" yaml:"period"`
 }
 
 // NewRedisStreamsConfig creates a new RedisStreamsConfig with default values.
@@ -32,5 +41,10 @@ func NewRedisStreamsConfig() RedisStreamsConfig {
 		StartFromOldest: true,
 		CommitPeriod:    "1s",
 		Timeout:         "1s",
+		AutoClaim: RedisStreamsAutoClaimConfig{
+			Enabled: false,
+			MinIdle: "1m",
+			Period:  "30s",
+		},
 	}
 }
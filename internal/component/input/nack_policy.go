@@ -0,0 +1,114 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// NackPolicyConfig describes how an input that supports negative
+// acknowledgements should handle messages that were rejected downstream.
+type NackPolicyConfig struct {
+	RequeueDelay string `json:"requeue_delay" yaml:"requeue_delay"`
+	MaxAttempts  int    `json:"max_attempts" yaml:"max_attempts"`
+}
+
+// NewNackPolicyConfig creates a new NackPolicyConfig with default values,
+// where rejected messages are requeued immediately.
+func NewNackPolicyConfig() NackPolicyConfig {
+	return NackPolicyConfig{
+		RequeueDelay: "",
+		MaxAttempts:  0,
+	}
+}
+
+// NackPolicyFieldSpec returns a field spec describing a nack policy.
+func NackPolicyFieldSpec() docs.FieldSpec {
+	return docs.FieldObject(
+		"nack_policy",
+		"Determines how messages that are rejected downstream (nacked) are handled. By default rejected messages are requeued immediately.",
+	).WithChildren(
+		docs.FieldString("requeue_delay", "An optional period of time to wait before requeuing a rejected message, which avoids tight redelivery loops when a downstream component fails persistently.", "1s", "30s").HasDefault(""),
+		docs.FieldInt("max_attempts", "The maximum number of times a message can be delivered before it is rejected without being requeued, which drops it or routes it to a dead letter queue if one is configured for the source. A value of `0` means messages are always requeued. This requires the source to report the number of times a message has been delivered.").HasDefault(0),
+	).Advanced().AtVersion("4.2.0")
+}
+
+// Policy creates a NackPolicy from the config.
+func (c NackPolicyConfig) Policy() (NackPolicy, error) {
+	var p NackPolicy
+	if c.RequeueDelay != "" {
+		var err error
+		if p.requeueDelay, err = time.ParseDuration(c.RequeueDelay); err != nil {
+			return p, fmt.Errorf("failed to parse nack_policy.requeue_delay: %w", err)
+		}
+	}
+	if c.MaxAttempts < 0 {
+		return p, fmt.Errorf("nack_policy.max_attempts must not be negative, got %v", c.MaxAttempts)
+	}
+	p.maxAttempts = c.MaxAttempts
+	return p, nil
+}
+
+// NackPolicy determines whether a rejected message should be requeued, and
+// how long to wait before doing so, or whether it should be rejected without
+// being requeued.
+type NackPolicy struct {
+	requeueDelay time.Duration
+	maxAttempts  int
+}
+
+// RequeueDelay returns the period of time to wait before requeuing a rejected
+// message.
+func (p NackPolicy) RequeueDelay() time.Duration {
+	return p.requeueDelay
+}
+
+// ShouldReject returns true if a message that has been delivered a number of
+// times should be rejected without being requeued. An attempts value of zero
+// means the number of deliveries is unknown, in which case the message is
+// always requeued.
+func (p NackPolicy) ShouldReject(attempts int) bool {
+	return p.maxAttempts > 0 && attempts >= p.maxAttempts
+}
+
+// Nack applies the policy to a rejected message that has been delivered a
+// number of times (zero if unknown) by calling either the reject or requeue
+// func. Requeues are delayed according to the policy, and if the context is
+// cancelled during the delay the message is neither requeued nor rejected.
+func (p NackPolicy) Nack(ctx context.Context, attempts int, requeue, reject func() error) error {
+	if p.ShouldReject(attempts) {
+		return reject()
+	}
+	if p.requeueDelay > 0 {
+		select {
+		case <-time.After(p.requeueDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return requeue()
+}
+
+// AckTimeoutFieldSpec returns a field spec describing an ack timeout.
+func AckTimeoutFieldSpec() docs.FieldSpec {
+	return docs.FieldString(
+		"ack_timeout",
+		"An optional maximum period of time to wait for a consumed message to be acknowledged downstream, after which it is rejected (nacked) and a warning is logged. This prevents messages from being held in flight indefinitely, for example when an output is wedged. An acknowledgement that arrives after the timeout is ignored, and therefore a message may be delivered more than once.",
+		"30s", "5m",
+	).HasDefault("").Advanced().AtVersion("4.2.0")
+}
+
+// AckTimeoutOpt parses an ack timeout duration string and returns an
+// AsyncReaderOpt that applies it. An empty string disables the timeout.
+func AckTimeoutOpt(timeout string) (AsyncReaderOpt, error) {
+	var d time.Duration
+	if timeout != "" {
+		var err error
+		if d, err = time.ParseDuration(timeout); err != nil {
+			return nil, fmt.Errorf("failed to parse ack_timeout: %w", err)
+		}
+	}
+	return AsyncReaderWithAckTimeout(d), nil
+}
@@ -0,0 +1,90 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNackPolicyConfigErrors(t *testing.T) {
+	conf := NewNackPolicyConfig()
+	conf.RequeueDelay = "not a duration"
+	_, err := conf.Policy()
+	require.Error(t, err)
+
+	conf = NewNackPolicyConfig()
+	conf.MaxAttempts = -1
+	_, err = conf.Policy()
+	require.Error(t, err)
+}
+
+func TestNackPolicyDefault(t *testing.T) {
+	p, err := NewNackPolicyConfig().Policy()
+	require.NoError(t, err)
+
+	var requeued, rejected int
+	for _, attempts := range []int{0, 1, 100} {
+		require.NoError(t, p.Nack(context.Background(), attempts, func() error {
+			requeued++
+			return nil
+		}, func() error {
+			rejected++
+			return nil
+		}))
+	}
+	assert.Equal(t, 3, requeued)
+	assert.Equal(t, 0, rejected)
+}
+
+func TestNackPolicyMaxAttempts(t *testing.T) {
+	conf := NewNackPolicyConfig()
+	conf.MaxAttempts = 3
+
+	p, err := conf.Policy()
+	require.NoError(t, err)
+
+	errRequeue, errReject := errors.New("requeue"), errors.New("reject")
+	nack := func(attempts int) error {
+		return p.Nack(context.Background(), attempts, func() error {
+			return errRequeue
+		}, func() error {
+			return errReject
+		})
+	}
+
+	assert.Equal(t, errRequeue, nack(0))
+	assert.Equal(t, errRequeue, nack(1))
+	assert.Equal(t, errRequeue, nack(2))
+	assert.Equal(t, errReject, nack(3))
+	assert.Equal(t, errReject, nack(4))
+}
+
+func TestNackPolicyRequeueDelay(t *testing.T) {
+	conf := NewNackPolicyConfig()
+	conf.RequeueDelay = "50ms"
+
+	p, err := conf.Policy()
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Millisecond, p.RequeueDelay())
+
+	start := time.Now()
+	require.NoError(t, p.Nack(context.Background(), 1, func() error {
+		return nil
+	}, nil))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, done := context.WithCancel(context.Background())
+	done()
+
+	var requeued bool
+	err = p.Nack(ctx, 1, func() error {
+		requeued = true
+		return nil
+	}, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, requeued)
+}
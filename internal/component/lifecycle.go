@@ -0,0 +1,177 @@
+package component
+
+import (
+	"context"
+	"sync"
+)
+
+// HealthCheck is a func registered by a component that returns a non-nil error
+// when the component is unhealthy.
+type HealthCheck func(ctx context.Context) error
+
+// LifecycleHooks are optional callbacks registered by a component that are
+// executed at various stages of the lifetime of the stream that owns it.
+type LifecycleHooks struct {
+	// PostStart is called once the stream has started.
+	PostStart func(ctx context.Context)
+
+	// PreDrain is called when the stream begins a graceful drain, before the
+	// input layer is closed.
+	PreDrain func(ctx context.Context)
+
+	// PostClose is called once the stream has fully closed.
+	PostClose func(ctx context.Context)
+}
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+type registeredHooks struct {
+	hooks  LifecycleHooks
+	closed bool
+}
+
+// Lifecycle is a registry of health checks and lifecycle hooks registered by
+// the components of a stream. Interactions with this type are thread safe.
+type Lifecycle struct {
+	mut    sync.Mutex
+	checks []*namedHealthCheck
+	hooks  []*registeredHooks
+}
+
+// NewLifecycle creates an empty lifecycle registry.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// AddHealthCheck registers a health check under a name that identifies the
+// component that registered it. Returns a func that removes the health check,
+// which should be called once the component is closed.
+func (l *Lifecycle) AddHealthCheck(name string, check HealthCheck) (remove func()) {
+	c := &namedHealthCheck{name: name, check: check}
+
+	l.mut.Lock()
+	l.checks = append(l.checks, c)
+	l.mut.Unlock()
+
+	return func() {
+		l.mut.Lock()
+		defer l.mut.Unlock()
+		for i, existing := range l.checks {
+			if existing == c {
+				l.checks = append(l.checks[:i:i], l.checks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// AddHooks registers a set of lifecycle hooks, any of which may be nil.
+// Returns a func that removes the hooks, which should be called once the
+// component is closed. Since components are closed before the stream that owns
+// them, a PostClose hook of removed hooks is still called by the next
+// PostClose, after which the hooks are dropped.
+func (l *Lifecycle) AddHooks(hooks LifecycleHooks) (remove func()) {
+	h := &registeredHooks{hooks: hooks}
+
+	l.mut.Lock()
+	l.hooks = append(l.hooks, h)
+	l.mut.Unlock()
+
+	return func() {
+		l.mut.Lock()
+		defer l.mut.Unlock()
+		if h.hooks.PostClose != nil {
+			h.closed = true
+			return
+		}
+		l.removeHooks(h)
+	}
+}
+
+// removeHooks drops registered hooks, the mutex must be held by the caller.
+func (l *Lifecycle) removeHooks(h *registeredHooks) {
+	for i, existing := range l.hooks {
+		if existing == h {
+			l.hooks = append(l.hooks[:i:i], l.hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// CheckHealth executes all registered health checks and calls fn with the name
+// and error of each one that reports as unhealthy. Returns true if all checks
+// passed.
+func (l *Lifecycle) CheckHealth(ctx context.Context, fn func(name string, err error)) bool {
+	l.mut.Lock()
+	checks := make([]*namedHealthCheck, len(l.checks))
+	copy(checks, l.checks)
+	l.mut.Unlock()
+
+	healthy := true
+	for _, c := range checks {
+		if err := c.check(ctx); err != nil {
+			healthy = false
+			fn(c.name, err)
+		}
+	}
+	return healthy
+}
+
+func (l *Lifecycle) walkHooks(fn func(h LifecycleHooks)) {
+	l.mut.Lock()
+	hooks := make([]LifecycleHooks, 0, len(l.hooks))
+	for _, h := range l.hooks {
+		if !h.closed {
+			hooks = append(hooks, h.hooks)
+		}
+	}
+	l.mut.Unlock()
+
+	for _, h := range hooks {
+		fn(h)
+	}
+}
+
+// PostStart executes all registered PostStart hooks.
+func (l *Lifecycle) PostStart(ctx context.Context) {
+	l.walkHooks(func(h LifecycleHooks) {
+		if h.PostStart != nil {
+			h.PostStart(ctx)
+		}
+	})
+}
+
+// PreDrain executes all registered PreDrain hooks.
+func (l *Lifecycle) PreDrain(ctx context.Context) {
+	l.walkHooks(func(h LifecycleHooks) {
+		if h.PreDrain != nil {
+			h.PreDrain(ctx)
+		}
+	})
+}
+
+// PostClose executes all registered PostClose hooks, including those of
+// removed hooks, which are then dropped.
+func (l *Lifecycle) PostClose(ctx context.Context) {
+	l.mut.Lock()
+	hooks := make([]*registeredHooks, len(l.hooks))
+	copy(hooks, l.hooks)
+	l.mut.Unlock()
+
+	for _, h := range hooks {
+		if h.hooks.PostClose != nil {
+			h.hooks.PostClose(ctx)
+		}
+	}
+
+	l.mut.Lock()
+	for _, h := range hooks {
+		if h.closed {
+			l.removeHooks(h)
+		}
+	}
+	l.mut.Unlock()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 
 	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
@@ -51,6 +52,7 @@ type AsyncWriter struct {
 	writer      AsyncSink
 
 	injectTracingMap *mapping.Executor
+	affinityKey      *field.Expression
 
 	log   log.Modular
 	stats metrics.Type
@@ -89,8 +91,68 @@ func (w *AsyncWriter) SetNoCancel() {
 	w.noCancel = true
 }
 
+// SetAffinityKey configures the async writer so that batches that resolve to
+// the same key, which is evaluated against the first message of each batch, are
+// written one at a time in the order that they were consumed, whilst batches of
+// different keys are written in parallel up to the max in flight. Batches that
+// are waiting on a prior batch of their key are queued without occupying a
+// writer, and up to max in flight batches can be queued before consumption is
+// paused.
+func (w *AsyncWriter) SetAffinityKey(key *field.Expression) {
+	w.affinityKey = key
+}
+
 //------------------------------------------------------------------------------
 
+// affinityQueues tracks the keys that are currently being written along with
+// the batches of each key that are waiting to be written after them.
+type affinityQueues struct {
+	mut    sync.Mutex
+	queues map[string][]message.Transaction
+}
+
+// push registers a batch for a key. Returns true if no other batch of the key
+// is being written, in which case the caller must write the batch and then call
+// next, otherwise the batch is queued.
+func (a *affinityQueues) push(key string, ts message.Transaction) bool {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	if queue, exists := a.queues[key]; exists {
+		a.queues[key] = append(queue, ts)
+		return false
+	}
+	a.queues[key] = nil
+	return true
+}
+
+// next returns the next queued batch of a key once the prior batch has been
+// written. Returns false when there are no queued batches, in which case the
+// key is no longer being written.
+func (a *affinityQueues) next(key string) (message.Transaction, bool) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	queue := a.queues[key]
+	if len(queue) == 0 {
+		delete(a.queues, key)
+		return message.Transaction{}, false
+	}
+	ts := queue[0]
+	queue[0] = message.Transaction{}
+	a.queues[key] = queue[1:]
+	return ts, true
+}
+
+// drain removes and returns all queued batches.
+func (a *affinityQueues) drain() (queued []message.Transaction) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	for key, queue := range a.queues {
+		queued = append(queued, queue...)
+		delete(a.queues, key)
+	}
+	return
+}
+
 func (w *AsyncWriter) latencyMeasuringWrite(msg *message.Batch) (latencyNs int64, err error) {
 	t0 := time.Now()
 	var ctx context.Context
@@ -232,59 +294,119 @@ func (w *AsyncWriter) loop() {
 		}
 	}
 
-	writerLoop := func() {
-		defer wg.Done()
+	affinity := &affinityQueues{queues: map[string][]message.Transaction{}}
 
+	// Each queued batch holds a slot of queueSlots, which limits the number of
+	// queued batches to the max in flight, at which point consumption pauses.
+	queueSlots := make(chan struct{}, w.maxInflight)
+
+	// readTransaction reads the next transaction to be written. When an
+	// affinity key is set transactions of a key that is already being written
+	// are queued, and the next transaction is read instead. The read and the
+	// queueing are performed under a lock so that batches are queued in the
+	// order that they were consumed.
+	var readMut sync.Mutex
+	readTransaction := func() (ts message.Transaction, key string, open bool) {
+		if w.affinityKey == nil {
+			select {
+			case ts, open = <-w.transactions:
+			case <-w.shutSig.CloseAtLeisureChan():
+			}
+			return
+		}
 		for {
-			var ts message.Transaction
-			var open bool
+			select {
+			case queueSlots <- struct{}{}:
+			case <-w.shutSig.CloseAtLeisureChan():
+				return message.Transaction{}, "", false
+			}
+
+			readMut.Lock()
 			select {
 			case ts, open = <-w.transactions:
-				if !open {
-					return
-				}
 			case <-w.shutSig.CloseAtLeisureChan():
+				open = false
+			}
+			if !open {
+				readMut.Unlock()
+				<-queueSlots
+				return message.Transaction{}, "", false
+			}
+			key = w.affinityKey.String(0, ts.Payload)
+			writeNow := affinity.push(key, ts)
+			readMut.Unlock()
+
+			if writeNow {
+				<-queueSlots
 				return
 			}
+		}
+	}
 
-			w.log.Tracef("Attempting to write %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
-			spans := tracing.CreateChildSpans("output_"+w.typeStr, ts.Payload)
-			ts.Payload = w.injectSpans(ts.Payload, spans)
+	// writeTransaction writes a transaction and acknowledges it, returns false
+	// if the writer has been closed.
+	writeTransaction := func(ts message.Transaction) bool {
+		w.log.Tracef("Attempting to write %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
+		spans := tracing.CreateChildSpans("output_"+w.typeStr, ts.Payload)
+		ts.Payload = w.injectSpans(ts.Payload, spans)
 
-			latency, err := w.latencyMeasuringWrite(ts.Payload)
+		latency, err := w.latencyMeasuringWrite(ts.Payload)
 
-			// If our writer says it is not connected.
-			if err == component.ErrNotConnected {
-				latency, err = connectLoop(ts.Payload)
-			} else if err != nil {
-				mError.Incr(1)
-			}
+		// If our writer says it is not connected.
+		if err == component.ErrNotConnected {
+			latency, err = connectLoop(ts.Payload)
+		} else if err != nil {
+			mError.Incr(1)
+		}
 
-			// Close immediately if our writer is closed.
-			if err == component.ErrTypeClosed {
-				return
-			}
+		// Close immediately if our writer is closed.
+		if err == component.ErrTypeClosed {
+			return false
+		}
 
-			if err != nil {
-				if w.typeStr != "reject" {
-					// TODO: Maybe reintroduce a sleep here if we encounter a
-					// busy retry loop.
-					w.log.Errorf("Failed to send message to %v: %v\n", w.typeStr, err)
-				} else {
-					w.log.Debugf("Rejecting message: %v\n", err)
-				}
+		if err != nil {
+			if w.typeStr != "reject" {
+				// TODO: Maybe reintroduce a sleep here if we encounter a
+				// busy retry loop.
+				w.log.Errorf("Failed to send message to %v: %v\n", w.typeStr, err)
 			} else {
-				mBatchSent.Incr(1)
-				mSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
-				mLatency.Timing(latency)
-				w.log.Tracef("Successfully wrote %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
+				w.log.Debugf("Rejecting message: %v\n", err)
 			}
+		} else {
+			mBatchSent.Incr(1)
+			mSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
+			mLatency.Timing(latency)
+			w.log.Tracef("Successfully wrote %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
+		}
 
-			for _, s := range spans {
-				s.Finish()
-			}
+		for _, s := range spans {
+			s.Finish()
+		}
+
+		_ = ts.Ack(closeLeisureCtx, err)
+		return true
+	}
+
+	writerLoop := func() {
+		defer wg.Done()
 
-			_ = ts.Ack(closeLeisureCtx, err)
+		for {
+			ts, key, open := readTransaction()
+			if !open {
+				return
+			}
+			for {
+				if !writeTransaction(ts) {
+					return
+				}
+				if w.affinityKey == nil || w.shutSig.ShouldCloseAtLeisure() {
+					break
+				}
+				if ts, open = affinity.next(key); !open {
+					break
+				}
+				<-queueSlots
+			}
 		}
 	}
 
@@ -292,6 +414,16 @@ func (w *AsyncWriter) loop() {
 		go writerLoop()
 	}
 	wg.Wait()
+
+	// Batches still queued behind a prior batch of their key are rejected so
+	// that they can be retried upstream.
+	if queued := affinity.drain(); len(queued) > 0 {
+		nackCtx, nackDone := w.shutSig.CloseNowCtx(context.Background())
+		defer nackDone()
+		for _, ts := range queued {
+			_ = ts.Ack(nackCtx, component.ErrTypeClosed)
+		}
+	}
 }
 
 // Consume assigns a messages channel for the output to read.
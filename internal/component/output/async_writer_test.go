@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/log"
@@ -664,3 +667,285 @@ func TestAsyncWriterSadPath(t *testing.T) {
 		t.Errorf("Wrong message sent: %v != %v", act, exp)
 	}
 }
+
+type affinityRecordingWriter struct {
+	mut       sync.Mutex
+	active    map[string]int
+	written   map[string][]string
+	maxActive int
+	maxPerKey int
+	total     int
+}
+
+func (w *affinityRecordingWriter) ConnectWithContext(ctx context.Context) error {
+	return nil
+}
+
+func (w *affinityRecordingWriter) WriteWithContext(ctx context.Context, msg *message.Batch) error {
+	key := msg.Get(0).MetaGet("key")
+
+	w.mut.Lock()
+	w.active[key]++
+	w.total++
+	if w.active[key] > w.maxPerKey {
+		w.maxPerKey = w.active[key]
+	}
+	if w.total > w.maxActive {
+		w.maxActive = w.total
+	}
+	w.mut.Unlock()
+
+	<-time.After(time.Millisecond * 5)
+
+	w.mut.Lock()
+	w.active[key]--
+	w.total--
+	w.written[key] = append(w.written[key], string(msg.Get(0).Get()))
+	w.mut.Unlock()
+	return nil
+}
+
+func (w *affinityRecordingWriter) CloseAsync() {}
+
+func (w *affinityRecordingWriter) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func TestAsyncWriterAffinityKey(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := &affinityRecordingWriter{
+		active:  map[string]int{},
+		written: map[string][]string{},
+	}
+
+	w, err := NewAsyncWriter("foo", 4, writerImpl, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	key, err := bloblang.GlobalEnvironment().NewField(`${! meta("key") }`)
+	require.NoError(t, err)
+	w.(*AsyncWriter).SetAffinityKey(key)
+
+	msgChan := make(chan message.Transaction)
+	resChan := make(chan error, 40)
+	require.NoError(t, w.Consume(msgChan))
+
+	keys := []string{"a", "b", "c", "d"}
+	expWritten := map[string][]string{}
+	for i := 0; i < 40; i++ {
+		k := keys[i%len(keys)]
+		expWritten[k] = append(expWritten[k], strconv.Itoa(i))
+
+		part := message.NewPart([]byte(strconv.Itoa(i)))
+		part.MetaSet("key", k)
+		batch := message.QuickBatch(nil)
+		batch.Append(part)
+
+		select {
+		case msgChan <- message.NewTransaction(batch, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out")
+		}
+	}
+
+	for i := 0; i < 40; i++ {
+		select {
+		case err := <-resChan:
+			require.NoError(t, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out")
+		}
+	}
+
+	writerImpl.mut.Lock()
+	assert.Equal(t, expWritten, writerImpl.written)
+	assert.Equal(t, 1, writerImpl.maxPerKey)
+	assert.Greater(t, writerImpl.maxActive, 1)
+	writerImpl.mut.Unlock()
+
+	close(msgChan)
+	require.NoError(t, w.WaitForClose(time.Second*5))
+}
+
+func TestAsyncWriterAffinityKeyAcksWhileIdle(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := &affinityRecordingWriter{
+		active:  map[string]int{},
+		written: map[string][]string{},
+	}
+
+	w, err := NewAsyncWriter("foo", 4, writerImpl, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	key, err := bloblang.GlobalEnvironment().NewField(`${! meta("key") }`)
+	require.NoError(t, err)
+	w.(*AsyncWriter).SetAffinityKey(key)
+
+	msgChan := make(chan message.Transaction)
+	resChan := make(chan error)
+	require.NoError(t, w.Consume(msgChan))
+
+	// Each batch is acked before the next is sent, and therefore the final
+	// ack must not depend on any further transactions arriving.
+	for i, k := range []string{"a", "a", "b"} {
+		part := message.NewPart([]byte(strconv.Itoa(i)))
+		part.MetaSet("key", k)
+		batch := message.QuickBatch(nil)
+		batch.Append(part)
+
+		select {
+		case msgChan <- message.NewTransaction(batch, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out")
+		}
+
+		select {
+		case err := <-resChan:
+			require.NoError(t, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for ack")
+		}
+	}
+
+	close(msgChan)
+	require.NoError(t, w.WaitForClose(time.Second*5))
+}
+
+type affinityBlockingWriter struct {
+	blockKey string
+	release  chan struct{}
+
+	mut     sync.Mutex
+	written []string
+}
+
+func (w *affinityBlockingWriter) ConnectWithContext(ctx context.Context) error {
+	return nil
+}
+
+func (w *affinityBlockingWriter) WriteWithContext(ctx context.Context, msg *message.Batch) error {
+	if msg.Get(0).MetaGet("key") == w.blockKey {
+		select {
+		case <-w.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	w.mut.Lock()
+	w.written = append(w.written, string(msg.Get(0).Get()))
+	w.mut.Unlock()
+	return nil
+}
+
+func (w *affinityBlockingWriter) CloseAsync() {}
+
+func (w *affinityBlockingWriter) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func affinityTransaction(t *testing.T, msgChan chan<- message.Transaction, content, key string) <-chan error {
+	t.Helper()
+
+	part := message.NewPart([]byte(content))
+	part.MetaSet("key", key)
+	batch := message.QuickBatch(nil)
+	batch.Append(part)
+
+	resChan := make(chan error, 1)
+	select {
+	case msgChan <- message.NewTransaction(batch, resChan):
+	case <-time.After(time.Second):
+		t.Fatal("Timed out")
+	}
+	return resChan
+}
+
+func TestAsyncWriterAffinityKeyHeldKey(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := &affinityBlockingWriter{
+		blockKey: "a",
+		release:  make(chan struct{}),
+	}
+
+	w, err := NewAsyncWriter("foo", 3, writerImpl, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	key, err := bloblang.GlobalEnvironment().NewField(`${! meta("key") }`)
+	require.NoError(t, err)
+	w.(*AsyncWriter).SetAffinityKey(key)
+
+	msgChan := make(chan message.Transaction)
+	require.NoError(t, w.Consume(msgChan))
+
+	// Batches queued behind a held key must not occupy the writers, and
+	// therefore batches of other keys are still written.
+	var aResChans []<-chan error
+	for i := 0; i < 3; i++ {
+		aResChans = append(aResChans, affinityTransaction(t, msgChan, "a"+strconv.Itoa(i), "a"))
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-affinityTransaction(t, msgChan, "b"+strconv.Itoa(i), "b"):
+			require.NoError(t, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for ack")
+		}
+	}
+
+	close(writerImpl.release)
+	for _, resChan := range aResChans {
+		select {
+		case err := <-resChan:
+			require.NoError(t, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for ack")
+		}
+	}
+
+	writerImpl.mut.Lock()
+	assert.Equal(t, []string{"b0", "b1", "b2", "a0", "a1", "a2"}, writerImpl.written)
+	writerImpl.mut.Unlock()
+
+	close(msgChan)
+	require.NoError(t, w.WaitForClose(time.Second*5))
+}
+
+func TestAsyncWriterAffinityKeyNacksQueuedOnClose(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := &affinityBlockingWriter{
+		blockKey: "a",
+		release:  make(chan struct{}),
+	}
+
+	w, err := NewAsyncWriter("foo", 2, writerImpl, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	key, err := bloblang.GlobalEnvironment().NewField(`${! meta("key") }`)
+	require.NoError(t, err)
+	w.(*AsyncWriter).SetAffinityKey(key)
+
+	msgChan := make(chan message.Transaction)
+	require.NoError(t, w.Consume(msgChan))
+
+	var resChans []<-chan error
+	for i := 0; i < 3; i++ {
+		resChans = append(resChans, affinityTransaction(t, msgChan, strconv.Itoa(i), "a"))
+	}
+
+	w.CloseAsync()
+
+	// The batches queued behind the held batch must be rejected rather than
+	// abandoned.
+	for _, resChan := range resChans[1:] {
+		select {
+		case err := <-resChan:
+			assert.Equal(t, component.ErrTypeClosed, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for nack")
+		}
+	}
+	require.NoError(t, w.WaitForClose(time.Second*5))
+}
@@ -3,6 +3,7 @@ package batcher
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
@@ -86,8 +87,22 @@ func (m *Impl) loop() {
 		nextTimedBatchChan = time.After(tNext)
 	}
 
+	// Tracks the size of flushed batches that are yet to be acknowledged in
+	// order to enforce a max in flight bytes limit.
+	maxInFlightBytes := int64(m.batcher.MaxInFlightBytes())
+	var inFlightBytes int64
+	inFlightReleased := make(chan struct{}, 1)
+
 	var pendingTrans []*transaction.Tracked
 	for !m.shutSig.ShouldCloseAtLeisure() {
+		for maxInFlightBytes > 0 && atomic.LoadInt64(&inFlightBytes) >= maxInFlightBytes {
+			select {
+			case <-inFlightReleased:
+			case <-m.shutSig.CloseAtLeisureChan():
+				return
+			}
+		}
+
 		if nextTimedBatchChan == nil {
 			if tNext := m.batcher.UntilNext(); tNext >= 0 {
 				nextTimedBatchChan = time.After(tNext)
@@ -135,12 +150,20 @@ func (m *Impl) loop() {
 			continue
 		}
 
+		var sendBytes int64
+		_ = sendMsg.Iter(func(i int, p *message.Part) error {
+			sendBytes += int64(len(p.Get()))
+			return nil
+		})
+
 		resChan := make(chan error)
 		select {
 		case m.messagesOut <- message.NewTransaction(sendMsg, resChan):
 		case <-m.shutSig.CloseAtLeisureChan():
 			return
 		}
+		sentAt := time.Now()
+		atomic.AddInt64(&inFlightBytes, sendBytes)
 
 		go func(rChan chan error, upstreamTrans []*transaction.Tracked) {
 			select {
@@ -150,6 +173,13 @@ func (m *Impl) loop() {
 				if !open {
 					return
 				}
+				m.batcher.ReportLatency(time.Since(sentAt))
+				atomic.AddInt64(&inFlightBytes, -sendBytes)
+				select {
+				case inFlightReleased <- struct{}{}:
+				default:
+				}
+
 				closeAtLeisureCtx, done := m.shutSig.CloseAtLeisureCtx(context.Background())
 				for _, t := range upstreamTrans {
 					if err := t.Ack(closeAtLeisureCtx, res); err != nil {
@@ -29,6 +29,7 @@ type Config struct {
 	Broker             BrokerConfig            `json:"broker" yaml:"broker"`
 	Cache              CacheConfig             `json:"cache" yaml:"cache"`
 	Cassandra          CassandraConfig         `json:"cassandra" yaml:"cassandra"`
+	DedupeCheckpoint   DedupeCheckpointConfig  `json:"dedupe_checkpoint" yaml:"dedupe_checkpoint"`
 	Drop               DropConfig              `json:"drop" yaml:"drop"`
 	DropOn             DropOnConfig            `json:"drop_on" yaml:"drop_on"`
 	Dynamic            DynamicConfig           `json:"dynamic" yaml:"dynamic"`
@@ -40,6 +41,7 @@ type Config struct {
 	HDFS               HDFSConfig              `json:"hdfs" yaml:"hdfs"`
 	HTTPClient         HTTPClientConfig        `json:"http_client" yaml:"http_client"`
 	HTTPServer         HTTPServerConfig        `json:"http_server" yaml:"http_server"`
+	Idempotent         IdempotentConfig        `json:"idempotent" yaml:"idempotent"`
 	Inproc             string                  `json:"inproc" yaml:"inproc"`
 	Kafka              KafkaConfig             `json:"kafka" yaml:"kafka"`
 	MongoDB            MongoDBConfig           `json:"mongodb" yaml:"mongodb"`
@@ -87,6 +89,7 @@ func NewConfig() Config {
 		Broker:             NewBrokerConfig(),
 		Cache:              NewCacheConfig(),
 		Cassandra:          NewCassandraConfig(),
+		DedupeCheckpoint:   NewDedupeCheckpointConfig(),
 		Drop:               NewDropConfig(),
 		DropOn:             NewDropOnConfig(),
 		Dynamic:            NewDynamicConfig(),
@@ -98,6 +101,7 @@ func NewConfig() Config {
 		HDFS:               NewHDFSConfig(),
 		HTTPClient:         NewHTTPClientConfig(),
 		HTTPServer:         NewHTTPServerConfig(),
+		Idempotent:         NewIdempotentConfig(),
 		Inproc:             "",
 		Kafka:              NewKafkaConfig(),
 		MQTT:               NewMQTTConfig(),
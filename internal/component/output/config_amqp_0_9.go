@@ -1,6 +1,7 @@
 package output
 
 import (
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/metadata"
 	btls "github.com/benthosdev/benthos/v4/internal/tls"
 )
@@ -16,20 +17,21 @@ type AMQPExchangeDeclareConfig struct {
 
 // AMQPConfig contains configuration fields for the AMQP output type.
 type AMQPConfig struct {
-	URLs            []string                     `json:"urls" yaml:"urls"`
-	MaxInFlight     int                          `json:"max_in_flight" yaml:"max_in_flight"`
-	Exchange        string                       `json:"exchange" yaml:"exchange"`
-	ExchangeDeclare AMQPExchangeDeclareConfig    `json:"exchange_declare" yaml:"exchange_declare"`
-	BindingKey      string                       `json:"key" yaml:"key"`
-	Type            string                       `json:"type" yaml:"type"`
-	ContentType     string                       `json:"content_type" yaml:"content_type"`
-	ContentEncoding string                       `json:"content_encoding" yaml:"content_encoding"`
-	Metadata        metadata.ExcludeFilterConfig `json:"metadata" yaml:"metadata"`
-	Priority        string                       `json:"priority" yaml:"priority"`
-	Persistent      bool                         `json:"persistent" yaml:"persistent"`
-	Mandatory       bool                         `json:"mandatory" yaml:"mandatory"`
-	Immediate       bool                         `json:"immediate" yaml:"immediate"`
-	TLS             btls.Config                  `json:"tls" yaml:"tls"`
+	URLs               []string                     `json:"urls" yaml:"urls"`
+	MaxInFlight        int                          `json:"max_in_flight" yaml:"max_in_flight"`
+	Exchange           string                       `json:"exchange" yaml:"exchange"`
+	ExchangeDeclare    AMQPExchangeDeclareConfig    `json:"exchange_declare" yaml:"exchange_declare"`
+	BindingKey         string                       `json:"key" yaml:"key"`
+	Type               string                       `json:"type" yaml:"type"`
+	ContentType        string                       `json:"content_type" yaml:"content_type"`
+	ContentEncoding    string                       `json:"content_encoding" yaml:"content_encoding"`
+	PayloadCompression compression.Config           `json:"payload_compression" yaml:"payload_compression"`
+	Metadata           metadata.ExcludeFilterConfig `json:"metadata" yaml:"metadata"`
+	Priority           string                       `json:"priority" yaml:"priority"`
+	Persistent         bool                         `json:"persistent" yaml:"persistent"`
+	Mandatory          bool                         `json:"mandatory" yaml:"mandatory"`
+	Immediate          bool                         `json:"immediate" yaml:"immediate"`
+	TLS                btls.Config                  `json:"tls" yaml:"tls"`
 }
 
 // NewAMQPConfig creates a new AMQPConfig with default values.
@@ -43,15 +45,16 @@ func NewAMQPConfig() AMQPConfig {
 			Type:    "direct",
 			Durable: true,
 		},
-		BindingKey:      "",
-		Type:            "",
-		ContentType:     "application/octet-stream",
-		ContentEncoding: "",
-		Metadata:        metadata.NewExcludeFilterConfig(),
-		Priority:        "",
-		Persistent:      false,
-		Mandatory:       false,
-		Immediate:       false,
-		TLS:             btls.NewConfig(),
+		BindingKey:         "",
+		Type:               "",
+		ContentType:        "application/octet-stream",
+		ContentEncoding:    "",
+		PayloadCompression: compression.NewConfig(),
+		Metadata:           metadata.NewExcludeFilterConfig(),
+		Priority:           "",
+		Persistent:         false,
+		Mandatory:          false,
+		Immediate:          false,
+		TLS:                btls.NewConfig(),
 	}
 }
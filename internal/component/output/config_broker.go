@@ -4,18 +4,42 @@ import "github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
 
 // BrokerConfig contains configuration fields for the Broker output type.
 type BrokerConfig struct {
-	Copies   int                `json:"copies" yaml:"copies"`
-	Pattern  string             `json:"pattern" yaml:"pattern"`
-	Outputs  []Config           `json:"outputs" yaml:"outputs"`
-	Batching batchconfig.Config `json:"batching" yaml:"batching"`
+	Copies   int                  `json:"copies" yaml:"copies"`
+	Pattern  string               `json:"pattern" yaml:"pattern"`
+	Outputs  []Config             `json:"outputs" yaml:"outputs"`
+	Weights  []int                `json:"weights" yaml:"weights"`
+	Priority BrokerPriorityConfig `json:"priority" yaml:"priority"`
+	Sharded  BrokerShardedConfig  `json:"sharded" yaml:"sharded"`
+	Batching batchconfig.Config   `json:"batching" yaml:"batching"`
+}
+
+// BrokerPriorityConfig contains configuration fields for the priority pattern
+// of the Broker output type.
+type BrokerPriorityConfig struct {
+	CheckInterval string `json:"check_interval" yaml:"check_interval"`
+	Cooldown      string `json:"cooldown" yaml:"cooldown"`
+}
+
+// BrokerShardedConfig contains configuration fields for the sharded pattern of
+// the Broker output type.
+type BrokerShardedConfig struct {
+	Key string `json:"key" yaml:"key"`
 }
 
 // NewBrokerConfig creates a new BrokerConfig with default values.
 func NewBrokerConfig() BrokerConfig {
 	return BrokerConfig{
-		Copies:   1,
-		Pattern:  "fan_out",
-		Outputs:  []Config{},
+		Copies:  1,
+		Pattern: "fan_out",
+		Outputs: []Config{},
+		Weights: []int{},
+		Priority: BrokerPriorityConfig{
+			CheckInterval: "1s",
+			Cooldown:      "30s",
+		},
+		Sharded: BrokerShardedConfig{
+			Key: "",
+		},
 		Batching: batchconfig.NewConfig(),
 	}
 }
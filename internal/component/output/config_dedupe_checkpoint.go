@@ -0,0 +1,49 @@
+package output
+
+import (
+	"encoding/json"
+)
+
+// DedupeCheckpointConfig contains configuration values for the
+// DedupeCheckpoint output type.
+type DedupeCheckpointConfig struct {
+	Cache  string  `json:"cache" yaml:"cache"`
+	Output *Config `json:"output" yaml:"output"`
+}
+
+// NewDedupeCheckpointConfig creates a new DedupeCheckpointConfig with default
+// values.
+func NewDedupeCheckpointConfig() DedupeCheckpointConfig {
+	return DedupeCheckpointConfig{
+		Cache:  "",
+		Output: nil,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dummyDedupeCheckpointConfig struct {
+	Cache  string      `json:"cache" yaml:"cache"`
+	Output interface{} `json:"output" yaml:"output"`
+}
+
+func (d DedupeCheckpointConfig) dummy() dummyDedupeCheckpointConfig {
+	dummy := dummyDedupeCheckpointConfig{
+		Cache:  d.Cache,
+		Output: d.Output,
+	}
+	if d.Output == nil {
+		dummy.Output = struct{}{}
+	}
+	return dummy
+}
+
+// MarshalJSON prints an empty object instead of nil.
+func (d DedupeCheckpointConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.dummy())
+}
+
+// MarshalYAML prints an empty object instead of nil.
+func (d DedupeCheckpointConfig) MarshalYAML() (interface{}, error) {
+	return d.dummy(), nil
+}
@@ -33,6 +33,7 @@ type ElasticsearchConfig struct {
 	AWS             OptionalAWSConfig    `json:"aws" yaml:"aws"`
 	GzipCompression bool                 `json:"gzip_compression" yaml:"gzip_compression"`
 	MaxInFlight     int                  `json:"max_in_flight" yaml:"max_in_flight"`
+	AffinityKey     string               `json:"affinity_key" yaml:"affinity_key"`
 	retries.Config  `json:",inline" yaml:",inline"`
 	Batching        batchconfig.Config `json:"batching" yaml:"batching"`
 }
@@ -63,6 +64,7 @@ func NewElasticsearchConfig() ElasticsearchConfig {
 		},
 		GzipCompression: false,
 		MaxInFlight:     64,
+		AffinityKey:     "",
 		Config:          rConf,
 		Batching:        batchconfig.NewConfig(),
 	}
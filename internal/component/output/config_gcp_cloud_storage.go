@@ -4,6 +4,7 @@ import (
 	"google.golang.org/api/googleapi"
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 )
 
 const (
@@ -31,6 +32,7 @@ type GCPCloudStorageConfig struct {
 	MaxInFlight     int                `json:"max_in_flight" yaml:"max_in_flight"`
 	Batching        batchconfig.Config `json:"batching" yaml:"batching"`
 	CollisionMode   string             `json:"collision_mode" yaml:"collision_mode"`
+	Credentials     credentials.Config `json:"credentials" yaml:"credentials"`
 }
 
 // NewGCPCloudStorageConfig creates a new Config with default values.
@@ -44,5 +46,6 @@ func NewGCPCloudStorageConfig() GCPCloudStorageConfig {
 		MaxInFlight:     64,
 		Batching:        batchconfig.NewConfig(),
 		CollisionMode:   GCPCloudStorageOverwriteCollisionMode,
+		Credentials:     credentials.NewConfig(),
 	}
 }
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 	"github.com/benthosdev/benthos/v4/internal/metadata"
 )
 
@@ -12,6 +13,7 @@ type GCPPubSubConfig struct {
 	PublishTimeout string                       `json:"publish_timeout" yaml:"publish_timeout"`
 	Metadata       metadata.ExcludeFilterConfig `json:"metadata" yaml:"metadata"`
 	OrderingKey    string                       `json:"ordering_key" yaml:"ordering_key"`
+	Credentials    credentials.Config           `json:"credentials" yaml:"credentials"`
 }
 
 // NewGCPPubSubConfig creates a new Config with default values.
@@ -23,5 +25,6 @@ func NewGCPPubSubConfig() GCPPubSubConfig {
 		PublishTimeout: "60s",
 		Metadata:       metadata.NewExcludeFilterConfig(),
 		OrderingKey:    "",
+		Credentials:    credentials.NewConfig(),
 	}
 }
@@ -3,6 +3,7 @@ package output
 import (
 	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
 	"github.com/benthosdev/benthos/v4/internal/http/docs"
+	"github.com/benthosdev/benthos/v4/internal/old/util/retries"
 )
 
 // HTTPClientMultipartExpression represents dynamic expressions that define a
@@ -15,6 +16,30 @@ type HTTPClientMultipartExpression struct {
 	Body               string `json:"body" yaml:"body"`
 }
 
+// HTTPClientSpoolConfig contains configuration fields for the disk spool of the
+// HTTPClient output.
+type HTTPClientSpoolConfig struct {
+	Enabled    bool            `json:"enabled" yaml:"enabled"`
+	Path       string          `json:"path" yaml:"path"`
+	MaxBatches int             `json:"max_batches" yaml:"max_batches"`
+	Backoff    retries.Backoff `json:"backoff" yaml:"backoff"`
+}
+
+// NewHTTPClientSpoolConfig creates a new HTTPClientSpoolConfig with default
+// values.
+func NewHTTPClientSpoolConfig() HTTPClientSpoolConfig {
+	return HTTPClientSpoolConfig{
+		Enabled:    false,
+		Path:       "",
+		MaxBatches: 10000,
+		Backoff: retries.Backoff{
+			InitialInterval: "1s",
+			MaxInterval:     "1m",
+			MaxElapsedTime:  "0s",
+		},
+	}
+}
+
 // HTTPClientConfig contains configuration fields for the HTTPClient output
 // type.
 type HTTPClientConfig struct {
@@ -24,6 +49,7 @@ type HTTPClientConfig struct {
 	PropagateResponse bool                            `json:"propagate_response" yaml:"propagate_response"`
 	Batching          batchconfig.Config              `json:"batching" yaml:"batching"`
 	Multipart         []HTTPClientMultipartExpression `json:"multipart" yaml:"multipart"`
+	Spool             HTTPClientSpoolConfig           `json:"spool" yaml:"spool"`
 }
 
 // NewHTTPClientConfig creates a new HTTPClientConfig with default values.
@@ -34,5 +60,6 @@ func NewHTTPClientConfig() HTTPClientConfig {
 		MaxInFlight:       64,
 		PropagateResponse: false,
 		Batching:          batchconfig.NewConfig(),
+		Spool:             NewHTTPClientSpoolConfig(),
 	}
 }
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"encoding/json"
+)
+
+// IdempotentConfig contains configuration values for the Idempotent output
+// type.
+type IdempotentConfig struct {
+	Cache   string  `json:"cache" yaml:"cache"`
+	Key     string  `json:"key" yaml:"key"`
+	TTL     string  `json:"ttl" yaml:"ttl"`
+	LockTTL string  `json:"lock_ttl" yaml:"lock_ttl"`
+	Output  *Config `json:"output" yaml:"output"`
+}
+
+// NewIdempotentConfig creates a new IdempotentConfig with default values.
+func NewIdempotentConfig() IdempotentConfig {
+	return IdempotentConfig{
+		Cache:   "",
+		Key:     "",
+		TTL:     "",
+		LockTTL: "5m",
+		Output:  nil,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dummyIdempotentConfig struct {
+	Cache   string      `json:"cache" yaml:"cache"`
+	Key     string      `json:"key" yaml:"key"`
+	TTL     string      `json:"ttl" yaml:"ttl"`
+	LockTTL string      `json:"lock_ttl" yaml:"lock_ttl"`
+	Output  interface{} `json:"output" yaml:"output"`
+}
+
+func (i IdempotentConfig) dummy() dummyIdempotentConfig {
+	dummy := dummyIdempotentConfig{
+		Cache:   i.Cache,
+		Key:     i.Key,
+		TTL:     i.TTL,
+		LockTTL: i.LockTTL,
+		Output:  i.Output,
+	}
+	if i.Output == nil {
+		dummy.Output = struct{}{}
+	}
+	return dummy
+}
+
+// MarshalJSON prints an empty object instead of nil.
+func (i IdempotentConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.dummy())
+}
+
+// MarshalYAML prints an empty object instead of nil.
+func (i IdempotentConfig) MarshalYAML() (interface{}, error) {
+	return i.dummy(), nil
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/impl/kafka/sasl"
 	"github.com/benthosdev/benthos/v4/internal/metadata"
 	"github.com/benthosdev/benthos/v4/internal/old/util/retries"
@@ -10,27 +11,33 @@ import (
 
 // KafkaConfig contains configuration fields for the Kafka output type.
 type KafkaConfig struct {
-	Addresses        []string    `json:"addresses" yaml:"addresses"`
-	ClientID         string      `json:"client_id" yaml:"client_id"`
-	RackID           string      `json:"rack_id" yaml:"rack_id"`
-	Key              string      `json:"key" yaml:"key"`
-	Partitioner      string      `json:"partitioner" yaml:"partitioner"`
-	Partition        string      `json:"partition" yaml:"partition"`
-	Topic            string      `json:"topic" yaml:"topic"`
-	Compression      string      `json:"compression" yaml:"compression"`
-	MaxMsgBytes      int         `json:"max_msg_bytes" yaml:"max_msg_bytes"`
-	Timeout          string      `json:"timeout" yaml:"timeout"`
-	AckReplicas      bool        `json:"ack_replicas" yaml:"ack_replicas"`
-	TargetVersion    string      `json:"target_version" yaml:"target_version"`
-	TLS              btls.Config `json:"tls" yaml:"tls"`
-	SASL             sasl.Config `json:"sasl" yaml:"sasl"`
-	MaxInFlight      int         `json:"max_in_flight" yaml:"max_in_flight"`
-	retries.Config   `json:",inline" yaml:",inline"`
-	RetryAsBatch     bool                         `json:"retry_as_batch" yaml:"retry_as_batch"`
-	Batching         batchconfig.Config           `json:"batching" yaml:"batching"`
-	StaticHeaders    map[string]string            `json:"static_headers" yaml:"static_headers"`
-	Metadata         metadata.ExcludeFilterConfig `json:"metadata" yaml:"metadata"`
-	InjectTracingMap string                       `json:"inject_tracing_map" yaml:"inject_tracing_map"`
+	Addresses          []string    `json:"addresses" yaml:"addresses"`
+	ClientID           string      `json:"client_id" yaml:"client_id"`
+	RackID             string      `json:"rack_id" yaml:"rack_id"`
+	Key                string      `json:"key" yaml:"key"`
+	Partitioner        string      `json:"partitioner" yaml:"partitioner"`
+	Partition          string      `json:"partition" yaml:"partition"`
+	Topic              string      `json:"topic" yaml:"topic"`
+	Compression        string      `json:"compression" yaml:"compression"`
+	CompressionLevel   int         `json:"compression_level" yaml:"compression_level"`
+	MaxMsgBytes        int         `json:"max_msg_bytes" yaml:"max_msg_bytes"`
+	Linger             string      `json:"linger" yaml:"linger"`
+	LingerBytes        int         `json:"linger_bytes" yaml:"linger_bytes"`
+	Timeout            string      `json:"timeout" yaml:"timeout"`
+	AckReplicas        bool        `json:"ack_replicas" yaml:"ack_replicas"`
+	IdempotentWrite    bool        `json:"idempotent_write" yaml:"idempotent_write"`
+	TargetVersion      string      `json:"target_version" yaml:"target_version"`
+	TLS                btls.Config `json:"tls" yaml:"tls"`
+	SASL               sasl.Config `json:"sasl" yaml:"sasl"`
+	SharedClient       bool        `json:"shared_client" yaml:"shared_client"`
+	MaxInFlight        int         `json:"max_in_flight" yaml:"max_in_flight"`
+	retries.Config     `json:",inline" yaml:",inline"`
+	RetryAsBatch       bool                         `json:"retry_as_batch" yaml:"retry_as_batch"`
+	Batching           batchconfig.Config           `json:"batching" yaml:"batching"`
+	StaticHeaders      map[string]string            `json:"static_headers" yaml:"static_headers"`
+	PayloadCompression compression.Config           `json:"payload_compression" yaml:"payload_compression"`
+	Metadata           metadata.ExcludeFilterConfig `json:"metadata" yaml:"metadata"`
+	InjectTracingMap   string                       `json:"inject_tracing_map" yaml:"inject_tracing_map"`
 }
 
 // NewKafkaConfig creates a new KafkaConfig with default values.
@@ -41,25 +48,31 @@ func NewKafkaConfig() KafkaConfig {
 	rConf.Backoff.MaxElapsedTime = "30s"
 
 	return KafkaConfig{
-		Addresses:     []string{},
-		ClientID:      "benthos",
-		RackID:        "",
-		Key:           "",
-		Partitioner:   "fnv1a_hash",
-		Partition:     "",
-		Topic:         "",
-		Compression:   "none",
-		MaxMsgBytes:   1000000,
-		Timeout:       "5s",
-		AckReplicas:   false,
-		TargetVersion: "2.0.0",
-		StaticHeaders: map[string]string{},
-		Metadata:      metadata.NewExcludeFilterConfig(),
-		TLS:           btls.NewConfig(),
-		SASL:          sasl.NewConfig(),
-		MaxInFlight:   64,
-		Config:        rConf,
-		RetryAsBatch:  false,
-		Batching:      batchconfig.NewConfig(),
+		Addresses:          []string{},
+		ClientID:           "benthos",
+		RackID:             "",
+		Key:                "",
+		Partitioner:        "fnv1a_hash",
+		Partition:          "",
+		Topic:              "",
+		Compression:        "none",
+		CompressionLevel:   -1,
+		MaxMsgBytes:        1000000,
+		Linger:             "",
+		LingerBytes:        0,
+		Timeout:            "5s",
+		AckReplicas:        false,
+		IdempotentWrite:    false,
+		TargetVersion:      "2.0.0",
+		StaticHeaders:      map[string]string{},
+		PayloadCompression: compression.NewConfig(),
+		Metadata:           metadata.NewExcludeFilterConfig(),
+		TLS:                btls.NewConfig(),
+		SASL:               sasl.NewConfig(),
+		SharedClient:       false,
+		MaxInFlight:        64,
+		Config:             rConf,
+		RetryAsBatch:       false,
+		Batching:           batchconfig.NewConfig(),
 	}
 }
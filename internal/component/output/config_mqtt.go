@@ -14,6 +14,7 @@ type MQTTConfig struct {
 	Topic                 string        `json:"topic" yaml:"topic"`
 	ClientID              string        `json:"client_id" yaml:"client_id"`
 	DynamicClientIDSuffix string        `json:"dynamic_client_id_suffix" yaml:"dynamic_client_id_suffix"`
+	CleanSession          bool          `json:"clean_session" yaml:"clean_session"`
 	Will                  mqttconf.Will `json:"will" yaml:"will"`
 	User                  string        `json:"user" yaml:"user"`
 	Password              string        `json:"password" yaml:"password"`
@@ -21,23 +22,27 @@ type MQTTConfig struct {
 	WriteTimeout          string        `json:"write_timeout" yaml:"write_timeout"`
 	KeepAlive             int64         `json:"keepalive" yaml:"keepalive"`
 	MaxInFlight           int           `json:"max_in_flight" yaml:"max_in_flight"`
+	ProtocolVersion       string        `json:"protocol_version" yaml:"protocol_version"`
+	MessageExpiry         string        `json:"message_expiry" yaml:"message_expiry"`
 	TLS                   tls.Config    `json:"tls" yaml:"tls"`
 }
 
 // NewMQTTConfig creates a new MQTTConfig with default values.
 func NewMQTTConfig() MQTTConfig {
 	return MQTTConfig{
-		URLs:           []string{},
-		QoS:            1,
-		Topic:          "",
-		ClientID:       "",
-		Will:           mqttconf.EmptyWill(),
-		User:           "",
-		Password:       "",
-		ConnectTimeout: "30s",
-		WriteTimeout:   "3s",
-		MaxInFlight:    64,
-		KeepAlive:      30,
-		TLS:            tls.NewConfig(),
+		URLs:            []string{},
+		QoS:             1,
+		Topic:           "",
+		ClientID:        "",
+		CleanSession:    true,
+		Will:            mqttconf.EmptyWill(),
+		User:            "",
+		Password:        "",
+		ConnectTimeout:  "30s",
+		WriteTimeout:    "3s",
+		MaxInFlight:     64,
+		KeepAlive:       30,
+		ProtocolVersion: "3.1.1",
+		TLS:             tls.NewConfig(),
 	}
 }
@@ -10,15 +10,21 @@ type RedisListConfig struct {
 	bredis.Config `json:",inline" yaml:",inline"`
 	Key           string             `json:"key" yaml:"key"`
 	MaxInFlight   int                `json:"max_in_flight" yaml:"max_in_flight"`
+	MaxLength     int64              `json:"max_length" yaml:"max_length"`
+	TTL           string             `json:"ttl" yaml:"ttl"`
+	Transactional bool               `json:"transactional" yaml:"transactional"`
 	Batching      batchconfig.Config `json:"batching" yaml:"batching"`
 }
 
 // NewRedisListConfig creates a new RedisListConfig with default values.
 func NewRedisListConfig() RedisListConfig {
 	return RedisListConfig{
-		Config:      bredis.NewConfig(),
-		Key:         "",
-		MaxInFlight: 64,
-		Batching:    batchconfig.NewConfig(),
+		Config:        bredis.NewConfig(),
+		Key:           "",
+		MaxInFlight:   64,
+		MaxLength:     0,
+		TTL:           "",
+		Transactional: false,
+		Batching:      batchconfig.NewConfig(),
 	}
 }
@@ -9,6 +9,9 @@ import (
 // RetryConfig contains configuration values for the Retry output type.
 type RetryConfig struct {
 	Output         *Config `json:"output" yaml:"output"`
+	Ordered        bool    `json:"ordered" yaml:"ordered"`
+	OrderingKey    string  `json:"ordering_key" yaml:"ordering_key"`
+	ExpiresAt      string  `json:"expires_at" yaml:"expires_at"`
 	retries.Config `json:",inline" yaml:",inline"`
 }
 
@@ -20,21 +23,30 @@ func NewRetryConfig() RetryConfig {
 	rConf.Backoff.MaxInterval = "1s"
 	rConf.Backoff.MaxElapsedTime = "0s"
 	return RetryConfig{
-		Output: nil,
-		Config: retries.NewConfig(),
+		Output:      nil,
+		Ordered:     false,
+		OrderingKey: "",
+		ExpiresAt:   "",
+		Config:      retries.NewConfig(),
 	}
 }
 
 type dummyRetryConfig struct {
 	Output         interface{} `json:"output" yaml:"output"`
+	Ordered        bool        `json:"ordered" yaml:"ordered"`
+	OrderingKey    string      `json:"ordering_key" yaml:"ordering_key"`
+	ExpiresAt      string      `json:"expires_at" yaml:"expires_at"`
 	retries.Config `json:",inline" yaml:",inline"`
 }
 
 // MarshalJSON prints an empty object instead of nil.
 func (r RetryConfig) MarshalJSON() ([]byte, error) {
 	dummy := dummyRetryConfig{
-		Output: r.Output,
-		Config: r.Config,
+		Output:      r.Output,
+		Ordered:     r.Ordered,
+		OrderingKey: r.OrderingKey,
+		ExpiresAt:   r.ExpiresAt,
+		Config:      r.Config,
 	}
 	if r.Output == nil {
 		dummy.Output = struct{}{}
@@ -45,8 +57,11 @@ func (r RetryConfig) MarshalJSON() ([]byte, error) {
 // MarshalYAML prints an empty object instead of nil.
 func (r RetryConfig) MarshalYAML() (interface{}, error) {
 	dummy := dummyRetryConfig{
-		Output: r.Output,
-		Config: r.Config,
+		Output:      r.Output,
+		Ordered:     r.Ordered,
+		OrderingKey: r.OrderingKey,
+		ExpiresAt:   r.ExpiresAt,
+		Config:      r.Config,
 	}
 	if r.Output == nil {
 		dummy.Output = struct{}{}
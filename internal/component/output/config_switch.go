@@ -18,16 +18,20 @@ func NewSwitchConfig() SwitchConfig {
 
 // SwitchConfigCase contains configuration fields per output of a switch type.
 type SwitchConfigCase struct {
-	Check    string `json:"check" yaml:"check"`
-	Continue bool   `json:"continue" yaml:"continue"`
-	Output   Config `json:"output" yaml:"output"`
+	Check      string  `json:"check" yaml:"check"`
+	Continue   bool    `json:"continue" yaml:"continue"`
+	Output     Config  `json:"output" yaml:"output"`
+	MaxRetries int     `json:"max_retries" yaml:"max_retries"`
+	DeadLetter *Config `json:"dead_letter,omitempty" yaml:"dead_letter,omitempty"`
 }
 
 // NewSwitchConfigCase creates a new switch output config with default values.
 func NewSwitchConfigCase() SwitchConfigCase {
 	return SwitchConfigCase{
-		Check:    "",
-		Continue: false,
-		Output:   NewConfig(),
+		Check:      "",
+		Continue:   false,
+		Output:     NewConfig(),
+		MaxRetries: 0,
+		DeadLetter: nil,
 	}
 }
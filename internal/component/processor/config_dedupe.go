@@ -2,9 +2,18 @@ package processor
 
 // DedupeConfig contains configuration fields for the Dedupe processor.
 type DedupeConfig struct {
-	Cache          string `json:"cache" yaml:"cache"`
-	Key            string `json:"key" yaml:"key"`
-	DropOnCacheErr bool   `json:"drop_on_err" yaml:"drop_on_err"`
+	Cache          string                  `json:"cache" yaml:"cache"`
+	Key            string                  `json:"key" yaml:"key"`
+	Mode           string                  `json:"mode" yaml:"mode"`
+	DropOnCacheErr bool                    `json:"drop_on_err" yaml:"drop_on_err"`
+	BloomFilter    DedupeBloomFilterConfig `json:"bloom_filter" yaml:"bloom_filter"`
+}
+
+// DedupeBloomFilterConfig contains configuration fields for the in-memory
+// bloom filter mode of the Dedupe processor.
+type DedupeBloomFilterConfig struct {
+	Capacity          int     `json:"capacity" yaml:"capacity"`
+	FalsePositiveRate float64 `json:"false_positive_rate" yaml:"false_positive_rate"`
 }
 
 // NewDedupeConfig returns a DedupeConfig with default values.
@@ -12,6 +21,11 @@ func NewDedupeConfig() DedupeConfig {
 	return DedupeConfig{
 		Cache:          "",
 		Key:            "",
+		Mode:           "key",
 		DropOnCacheErr: true,
+		BloomFilter: DedupeBloomFilterConfig{
+			Capacity:          0,
+			FalsePositiveRate: 0.01,
+		},
 	}
 }
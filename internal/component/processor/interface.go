@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"context"
 	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -29,6 +30,24 @@ type V1 interface {
 	WaitForClose(timeout time.Duration) error
 }
 
+// V1WithContext is implemented by processors that are able to abandon the
+// processing of a message once a context is cancelled.
+type V1WithContext interface {
+	// ProcessMessageWithContext attempts to process a message, where the
+	// context is passed to the underlying processor and is therefore able to
+	// cancel blocking operations.
+	ProcessMessageWithContext(ctx context.Context, msg *message.Batch) ([]*message.Batch, error)
+}
+
+// ProcessWithContext attempts to process a message with a processor, passing
+// it the context when it implements V1WithContext.
+func ProcessWithContext(ctx context.Context, p V1, msg *message.Batch) ([]*message.Batch, error) {
+	if cp, ok := p.(V1WithContext); ok {
+		return cp.ProcessMessageWithContext(ctx, msg)
+	}
+	return p.ProcessMessage(msg)
+}
+
 // Pipeline is an interface that implements channel based based consumer and
 // producer methods for streaming data through a processing pipeline.
 type Pipeline interface {
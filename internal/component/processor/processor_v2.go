@@ -69,6 +69,10 @@ func NewV2ToV1Processor(typeStr string, p V2, stats metrics.Type) V1 {
 }
 
 func (a *v2ToV1Processor) ProcessMessage(msg *message.Batch) ([]*message.Batch, error) {
+	return a.ProcessMessageWithContext(context.Background(), msg)
+}
+
+func (a *v2ToV1Processor) ProcessMessageWithContext(ctx context.Context, msg *message.Batch) ([]*message.Batch, error) {
 	a.mReceived.Incr(int64(msg.Len()))
 	a.mBatchReceived.Incr(1)
 
@@ -79,7 +83,7 @@ func (a *v2ToV1Processor) ProcessMessage(msg *message.Batch) ([]*message.Batch,
 	_ = msg.Iter(func(i int, part *message.Part) error {
 		span := tracing.CreateChildSpan(a.typeStr, part)
 
-		nextParts, err := a.p.Process(context.Background(), part)
+		nextParts, err := a.p.Process(ctx, part)
 		if err != nil {
 			newPart := part.Copy()
 			a.mError.Incr(1)
@@ -156,13 +160,17 @@ func NewV2BatchedToV1Processor(typeStr string, p V2Batched, stats metrics.Type)
 }
 
 func (a *v2BatchedToV1Processor) ProcessMessage(msg *message.Batch) ([]*message.Batch, error) {
+	return a.ProcessMessageWithContext(context.Background(), msg)
+}
+
+func (a *v2BatchedToV1Processor) ProcessMessageWithContext(ctx context.Context, msg *message.Batch) ([]*message.Batch, error) {
 	a.mReceived.Incr(int64(msg.Len()))
 	a.mBatchReceived.Incr(1)
 
 	tStarted := time.Now()
 	spans := tracing.CreateChildSpans(a.typeStr, msg)
 
-	outputBatches, err := a.p.ProcessBatch(context.Background(), spans, msg)
+	outputBatches, err := a.p.ProcessBatch(ctx, spans, msg)
 	if err != nil {
 		a.mError.Incr(1)
 		outputBatch := msg.Copy()
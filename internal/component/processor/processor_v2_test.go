@@ -72,6 +72,24 @@ func TestProcessorAirGapOneToOne(t *testing.T) {
 	assert.Equal(t, "unchanged", string(msg.Get(0).Get()))
 }
 
+func TestProcessorAirGapContext(t *testing.T) {
+	agrp := NewV2ToV1Processor("foo", &fnProcessor{
+		fn: func(c context.Context, m *message.Part) ([]*message.Part, error) {
+			<-c.Done()
+			return nil, c.Err()
+		},
+	}, metrics.Noop())
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*5)
+	defer done()
+
+	msg := message.QuickBatch([][]byte{[]byte("foo")})
+	msgs, res := ProcessWithContext(ctx, agrp, msg)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.EqualError(t, msgs[0].Get(0).ErrorGet(), "context deadline exceeded")
+}
+
 func TestProcessorAirGapOneToError(t *testing.T) {
 	agrp := NewV2ToV1Processor("foo", &fnProcessor{
 		fn: func(c context.Context, m *message.Part) ([]*message.Part, error) {
@@ -0,0 +1,72 @@
+package component
+
+import (
+	"io"
+	"sync"
+)
+
+type sharedClient struct {
+	client io.Closer
+	refs   int
+}
+
+// SharedClients is a registry of reference counted clients that allows
+// multiple components targeting the same endpoint to reuse a single client
+// (and therefore connection pool) rather than each creating their own.
+// Interactions with this type are thread safe.
+type SharedClients struct {
+	mut     sync.Mutex
+	clients map[string]*sharedClient
+}
+
+// NewSharedClients creates an empty shared client registry.
+func NewSharedClients() *SharedClients {
+	return &SharedClients{
+		clients: map[string]*sharedClient{},
+	}
+}
+
+// Acquire returns the client registered under a key, or creates one with the
+// provided constructor if none exists. The key should uniquely identify the
+// endpoint and any options that affect the behaviour of the client.
+//
+// The returned release func must be called once the component is finished
+// with the client, and the client is closed once all components that acquired
+// it have released it.
+func (s *SharedClients) Acquire(key string, ctor func() (io.Closer, error)) (io.Closer, func() error, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	c, exists := s.clients[key]
+	if !exists {
+		client, err := ctor()
+		if err != nil {
+			return nil, nil, err
+		}
+		c = &sharedClient{client: client}
+		s.clients[key] = c
+	}
+	c.refs++
+
+	var releaseOnce sync.Once
+	return c.client, func() (err error) {
+		releaseOnce.Do(func() {
+			err = s.release(key, c)
+		})
+		return
+	}, nil
+}
+
+func (s *SharedClients) release(key string, c *sharedClient) error {
+	s.mut.Lock()
+	c.refs--
+	if c.refs > 0 {
+		s.mut.Unlock()
+		return nil
+	}
+	if s.clients[key] == c {
+		delete(s.clients, key)
+	}
+	s.mut.Unlock()
+	return c.client.Close()
+}
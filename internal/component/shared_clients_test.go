@@ -0,0 +1,66 @@
+package component_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+)
+
+type closeCounter struct {
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestSharedClients(t *testing.T) {
+	s := component.NewSharedClients()
+
+	var created int
+	ctor := func() (io.Closer, error) {
+		created++
+		return &closeCounter{}, nil
+	}
+
+	cA, releaseA, err := s.Acquire("foo", ctor)
+	require.NoError(t, err)
+
+	cB, releaseB, err := s.Acquire("foo", ctor)
+	require.NoError(t, err)
+
+	cC, releaseC, err := s.Acquire("bar", ctor)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, created)
+	assert.Same(t, cA, cB)
+	assert.NotSame(t, cA, cC)
+
+	require.NoError(t, releaseA())
+	require.NoError(t, releaseA())
+	assert.Equal(t, 0, cA.(*closeCounter).closed)
+
+	require.NoError(t, releaseB())
+	assert.Equal(t, 1, cA.(*closeCounter).closed)
+
+	require.NoError(t, releaseC())
+	assert.Equal(t, 1, cC.(*closeCounter).closed)
+
+	// A new client is created once all references are released.
+	cD, releaseD, err := s.Acquire("foo", ctor)
+	require.NoError(t, err)
+	assert.Equal(t, 3, created)
+	assert.NotSame(t, cA, cD)
+	require.NoError(t, releaseD())
+
+	_, _, err = s.Acquire("baz", func() (io.Closer, error) {
+		return nil, errors.New("nope")
+	})
+	assert.EqualError(t, err, "nope")
+}
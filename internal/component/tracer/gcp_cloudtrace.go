@@ -1,11 +1,16 @@
 package tracer
 
+import (
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
+)
+
 // CloudTraceConfig is config for the Google Cloud Trace tracer.
 type CloudTraceConfig struct {
-	Project       string            `json:"project" yaml:"project"`
-	SamplingRatio float64           `json:"sampling_ratio" yaml:"sampling_ratio"`
-	Tags          map[string]string `json:"tags" yaml:"tags"`
-	FlushInterval string            `json:"flush_interval" yaml:"flush_interval"`
+	Project       string             `json:"project" yaml:"project"`
+	SamplingRatio float64            `json:"sampling_ratio" yaml:"sampling_ratio"`
+	Tags          map[string]string  `json:"tags" yaml:"tags"`
+	FlushInterval string             `json:"flush_interval" yaml:"flush_interval"`
+	Credentials   credentials.Config `json:"credentials" yaml:"credentials"`
 }
 
 // NewCloudTraceConfig creates an CloudTraceConfig struct with default values.
@@ -15,5 +20,6 @@ func NewCloudTraceConfig() CloudTraceConfig {
 		SamplingRatio: 1.0,
 		Tags:          map[string]string{},
 		FlushInterval: "",
+		Credentials:   credentials.NewConfig(),
 	}
 }
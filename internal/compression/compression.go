@@ -0,0 +1,195 @@
+// Package compression provides the transparent compression of message payloads
+// written to message buses, where compressed payloads are labelled with a
+// content encoding so that consumers are able to decompress them.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/pierrec/lz4/v4"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// Encodings lists the content encodings that payloads can be compressed with.
+var Encodings = []string{"gzip", "deflate", "snappy", "lz4"}
+
+// The headers that label the content encoding of compressed payloads, which
+// differ in case as NATS canonicalises header keys.
+const (
+	KafkaHeader = "content-encoding"
+	NATSHeader  = "Content-Encoding"
+)
+
+// FieldSpecs returns a docs spec for the fields within a compression config
+// struct.
+func FieldSpecs() docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldString("algorithm", "The algorithm to compress payloads with, which is set as their content encoding. Set to `none` in order to disable compression.").
+			HasOptions(append([]string{"none"}, Encodings...)...).HasDefault("none"),
+		docs.FieldInt("threshold", "The minimum size in bytes of a payload in order for it to be compressed, smaller payloads are written uncompressed.").
+			HasDefault(1024),
+	}
+}
+
+// FieldSpec returns a docs spec for the payload compression field of an output,
+// where label names the content encoding of written messages and inputs names
+// the components able to decompress them.
+func FieldSpec(label, inputs string) docs.FieldSpec {
+	return docs.FieldObject(
+		"payload_compression",
+		fmt.Sprintf("Optionally compress the payloads of messages that exceed a size threshold, in which case the %v of compressed messages is set to the algorithm used, allowing the %v to decompress them with `auto_decompress`.", label, inputs),
+	).WithChildren(FieldSpecs()...).Advanced().AtVersion("4.2.0")
+}
+
+// AutoDecompressFieldSpec returns a docs spec for the field of an input that
+// enables decompression, where label names the content encoding of consumed
+// messages and output names the component that compresses them.
+func AutoDecompressFieldSpec(label, output string) docs.FieldSpec {
+	return docs.FieldBool(
+		"auto_decompress",
+		fmt.Sprintf("Whether to decompress messages with a %v of `gzip`, `deflate`, `snappy` or `lz4`, such as those compressed by the `payload_compression` field of the %v. The %v is omitted from the metadata of decompressed messages, and messages that fail to decompress are left unchanged.", label, output, label),
+	).HasDefault(false).Advanced().AtVersion("4.2.0")
+}
+
+// Config describes how payloads are compressed before being written.
+type Config struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Threshold int    `json:"threshold" yaml:"threshold"`
+}
+
+// NewConfig returns a compression config struct with default values.
+func NewConfig() Config {
+	return Config{
+		Algorithm: "none",
+		Threshold: 1024,
+	}
+}
+
+// Compressor attempts to construct a compressor from the config, which is nil
+// when compression is disabled.
+func (c Config) Compressor() (*Compressor, error) {
+	return NewCompressor(c.Algorithm, c.Threshold)
+}
+
+// Compressor compresses payloads that exceed a size threshold.
+type Compressor struct {
+	encoding  string
+	threshold int
+}
+
+// NewCompressor returns a compressor for an algorithm, which is nil when the
+// algorithm is `none` or empty.
+func NewCompressor(algorithm string, threshold int) (*Compressor, error) {
+	if algorithm == "" || algorithm == "none" {
+		return nil, nil
+	}
+	if !isEncoding(algorithm) {
+		return nil, fmt.Errorf("compression algorithm not recognised: %v", algorithm)
+	}
+	if threshold < 0 {
+		return nil, fmt.Errorf("compression threshold must not be negative, got %v", threshold)
+	}
+	return &Compressor{
+		encoding:  algorithm,
+		threshold: threshold,
+	}, nil
+}
+
+// Compress compresses a payload when it exceeds the threshold of the
+// compressor, returning the content encoding of the result, which is empty
+// when the payload was left uncompressed.
+func (c *Compressor) Compress(b []byte) ([]byte, string, error) {
+	if c == nil || len(b) < c.threshold {
+		return b, "", nil
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch c.encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "snappy":
+		return snappy.Encode(nil, b), c.encoding, nil
+	case "lz4":
+		w = lz4.NewWriter(&buf)
+	}
+	if _, err := w.Write(b); err != nil {
+		w.Close()
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), c.encoding, nil
+}
+
+func isEncoding(encoding string) bool {
+	for _, e := range Encodings {
+		if e == encoding {
+			return true
+		}
+	}
+	return false
+}
+
+// Decompress decompresses a payload of a given content encoding. When the
+// encoding is not one that payloads are compressed with the payload is returned
+// unchanged and false is returned.
+func Decompress(encoding string, b []byte) ([]byte, bool, error) {
+	var r io.Reader
+	switch encoding {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, false, err
+		}
+		r = gr
+	case "deflate":
+		zr, err := zlib.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, false, err
+		}
+		r = zr
+	case "snappy":
+		decoded, err := snappy.Decode(nil, b)
+		if err != nil {
+			return nil, false, err
+		}
+		return decoded, true, nil
+	case "lz4":
+		r = lz4.NewReader(bytes.NewReader(b))
+	default:
+		return b, false, nil
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, false, err
+	}
+	return decoded, true, nil
+}
+
+// DecompressPart decompresses the contents of a message part when the metadata
+// key, which holds the content encoding header of the message, is set to an
+// encoding that payloads are compressed with, in which case the metadata key
+// is removed.
+func DecompressPart(p *message.Part, metaKey string) error {
+	encoding := p.MetaGet(metaKey)
+	decoded, ok, err := Decompress(encoding, p.Get())
+	if err != nil {
+		return fmt.Errorf("failed to decompress message with content encoding %v: %w", encoding, err)
+	}
+	if ok {
+		p.Set(decoded)
+		p.MetaDelete(metaKey)
+	}
+	return nil
+}
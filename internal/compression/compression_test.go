@@ -0,0 +1,71 @@
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressorDisabled(t *testing.T) {
+	c, err := NewConfig().Compressor()
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	out, encoding, err := c.Compress([]byte("hello world"))
+	require.NoError(t, err)
+	assert.Equal(t, "", encoding)
+	assert.Equal(t, "hello world", string(out))
+}
+
+func TestCompressorBadConfig(t *testing.T) {
+	_, err := NewCompressor("nope", 0)
+	require.Error(t, err)
+
+	_, err = NewCompressor("gzip", -1)
+	require.Error(t, err)
+}
+
+func TestCompressorRoundTrip(t *testing.T) {
+	large := bytes.Repeat([]byte(`{"hello":"world"}`), 100)
+	small := []byte(`{"hello":"world"}`)
+
+	for _, encoding := range Encodings {
+		encoding := encoding
+		t.Run(encoding, func(t *testing.T) {
+			c, err := NewCompressor(encoding, 100)
+			require.NoError(t, err)
+
+			out, outEncoding, err := c.Compress(small)
+			require.NoError(t, err)
+			assert.Equal(t, "", outEncoding)
+			assert.Equal(t, small, out)
+
+			out, outEncoding, err = c.Compress(large)
+			require.NoError(t, err)
+			assert.Equal(t, encoding, outEncoding)
+			assert.Less(t, len(out), len(large))
+
+			decoded, ok, err := Decompress(outEncoding, out)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, large, decoded)
+		})
+	}
+}
+
+func TestDecompressUnknown(t *testing.T) {
+	out, ok, err := Decompress("", []byte("hello world"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "hello world", string(out))
+
+	out, ok, err = Decompress("br", []byte("hello world"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "hello world", string(out))
+
+	_, _, err = Decompress("gzip", []byte("not gzip"))
+	require.Error(t, err)
+}
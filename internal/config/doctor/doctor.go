@@ -0,0 +1,268 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// Suggestion is an actionable change that could be made to a config.
+type Suggestion struct {
+	Line    int
+	Path    string
+	Message string
+
+	// Fixable is true when the suggestion can be applied to the config
+	// automatically without changing its behaviour.
+	Fixable bool
+
+	fix func()
+}
+
+// String returns a human readable description of the suggestion.
+func (s Suggestion) String() string {
+	if s.Line > 0 {
+		return fmt.Sprintf("line %v: %v: %v", s.Line, s.Path, s.Message)
+	}
+	return fmt.Sprintf("%v: %v", s.Path, s.Message)
+}
+
+type doctor struct {
+	prov docs.Provider
+	root *yaml.Node
+
+	suggestions []Suggestion
+}
+
+// Diagnose walks a parsed config according to a spec and returns a list of
+// suggestions for deprecated fields and components as well as patterns that
+// are likely to be inefficient.
+func Diagnose(prov docs.Provider, spec docs.FieldSpecs, root *yaml.Node) ([]Suggestion, error) {
+	if root = unwrap(root); root == nil || root.Kind != yaml.MappingNode {
+		return nil, errors.New("expected object value")
+	}
+
+	d := &doctor{prov: prov, root: root}
+	for _, f := range spec {
+		d.field(f.Name, f, root, field(root, f.Name))
+	}
+	return d.suggestions, nil
+}
+
+// Fix applies all fixable suggestions to a parsed config, modifying it in
+// place, and returns the suggestions that could not be applied.
+func Fix(prov docs.Provider, spec docs.FieldSpecs, root *yaml.Node) ([]Suggestion, error) {
+	suggestions, err := Diagnose(prov, spec, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining []Suggestion
+	for _, s := range suggestions {
+		if s.Fixable {
+			s.fix()
+		} else {
+			remaining = append(remaining, s)
+		}
+	}
+	return remaining, nil
+}
+
+//------------------------------------------------------------------------------
+
+func unwrap(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+func field(node *yaml.Node, name string) *yaml.Node {
+	node = unwrap(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeField(node *yaml.Node, name string) {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == name {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func (d *doctor) add(node *yaml.Node, path, msg string, fix func()) {
+	s := Suggestion{
+		Path:    path,
+		Message: msg,
+		Fixable: fix != nil,
+		fix:     fix,
+	}
+	if node != nil {
+		s.Line = node.Line
+	}
+	d.suggestions = append(d.suggestions, s)
+}
+
+func isDefault(spec docs.FieldSpec, node *yaml.Node) bool {
+	if spec.Default == nil {
+		return false
+	}
+
+	var defNode yaml.Node
+	if err := defNode.Encode(*spec.Default); err != nil {
+		return false
+	}
+
+	var defValue, value interface{}
+	if err := defNode.Decode(&defValue); err != nil {
+		return false
+	}
+	if err := node.Decode(&value); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(defValue, value)
+}
+
+// field checks a field of a config, where parent is the object containing the
+// field.
+func (d *doctor) field(path string, spec docs.FieldSpec, parent, node *yaml.Node) {
+	if node = unwrap(node); node == nil {
+		return
+	}
+
+	if spec.IsDeprecated {
+		if parent != nil && isDefault(spec, node) {
+			d.add(node, path, fmt.Sprintf("field %v is deprecated and is set to its default value, it can be removed", spec.Name), func() {
+				removeField(parent, spec.Name)
+			})
+		} else {
+			d.add(node, path, fmt.Sprintf("field %v is deprecated and will be removed in the next major version, check the documentation of this component for alternatives", spec.Name), nil)
+		}
+		return
+	}
+
+	switch spec.Kind {
+	case docs.KindArray, docs.Kind2DArray:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		elemSpec := spec.Scalar()
+		if spec.Kind == docs.Kind2DArray {
+			elemSpec = spec.Array()
+		}
+		for i, n := range node.Content {
+			d.field(fmt.Sprintf("%v.%v", path, i), elemSpec, nil, n)
+		}
+		return
+	case docs.KindMap:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			d.field(path+"."+node.Content[i].Value, spec.Scalar(), nil, node.Content[i+1])
+		}
+		return
+	}
+
+	if cType, isCore := spec.Type.IsCoreComponent(); isCore {
+		d.component(path, cType, node)
+		return
+	}
+
+	for _, c := range spec.Children {
+		d.field(path+"."+c.Name, c, node, field(node, c.Name))
+	}
+}
+
+// component checks a component config and all of its children.
+func (d *doctor) component(path string, cType docs.Type, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	name, spec, err := docs.GetInferenceCandidateFromYAML(d.prov, cType, node)
+	if err != nil {
+		// Components that cannot be inferred are reported by the linter.
+		return
+	}
+
+	if spec.Status == docs.StatusDeprecated {
+		d.add(node, path, fmt.Sprintf("%v %v is deprecated and will be removed in the next major version, check the documentation of this component for alternatives", cType, name), nil)
+	}
+
+	confNode := field(node, name)
+	if cType == docs.TypeOutput {
+		d.batching(path+"."+name, name, spec.Config, node, confNode)
+		if path == "output" {
+			d.outputProcessors(path, node)
+		}
+	}
+
+	d.field(path+"."+name, spec.Config, node, confNode)
+	if cType == docs.TypeInput || cType == docs.TypeOutput {
+		d.field(path+".processors", docs.FieldProcessor("processors", "").Array(), node, field(node, "processors"))
+	}
+}
+
+// batching checks whether an output that supports batching has it configured.
+func (d *doctor) batching(path, name string, spec docs.FieldSpec, node, confNode *yaml.Node) {
+	var supported bool
+	for _, c := range spec.Children {
+		if c.Name == "batching" && c.Type == docs.FieldTypeObject {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return
+	}
+
+	batchNode := field(confNode, "batching")
+	for _, k := range []string{"count", "byte_size", "period", "check"} {
+		if v := field(batchNode, k); v != nil && v.Value != "" && v.Value != "0" {
+			return
+		}
+	}
+	d.add(node, path, fmt.Sprintf("output %v supports batching but none is configured, setting batching.count or batching.period can greatly improve throughput", name), nil)
+}
+
+// outputProcessors checks whether the processors of the root output can be
+// moved to the end of the pipeline, where they can be executed in parallel.
+func (d *doctor) outputProcessors(path string, node *yaml.Node) {
+	procsNode := field(node, "processors")
+	if procsNode == nil || procsNode.Kind != yaml.SequenceNode || len(procsNode.Content) == 0 {
+		return
+	}
+
+	d.add(procsNode, path+".processors", "processors of the output can be moved to the end of pipeline.processors, where they are executed in parallel according to pipeline.threads", func() {
+		removeField(node, "processors")
+
+		pipeNode := field(d.root, "pipeline")
+		if pipeNode == nil || pipeNode.Kind != yaml.MappingNode {
+			pipeNode = &yaml.Node{Kind: yaml.MappingNode}
+			removeField(d.root, "pipeline")
+			d.root.Content = append(d.root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "pipeline"}, pipeNode)
+		}
+
+		pipeProcs := field(pipeNode, "processors")
+		if pipeProcs == nil || pipeProcs.Kind != yaml.SequenceNode {
+			pipeProcs = &yaml.Node{Kind: yaml.SequenceNode}
+			removeField(pipeNode, "processors")
+			pipeNode.Content = append(pipeNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "processors"}, pipeProcs)
+		}
+		pipeProcs.Content = append(pipeProcs.Content, procsNode.Content...)
+	})
+}
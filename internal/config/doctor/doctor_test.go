@@ -0,0 +1,132 @@
+package doctor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config/doctor"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func testProvider() docs.Provider {
+	prov := docs.NewMappedDocsProvider()
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "foo",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("a", ""),
+			docs.FieldString("b", "").Deprecated().HasDefault(""),
+			docs.FieldInt("c", "").Deprecated().HasDefault(0),
+		),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "bar",
+		Type:   docs.TypeProcessor,
+		Config: docs.FieldString("", ""),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "old",
+		Type:   docs.TypeProcessor,
+		Status: docs.StatusDeprecated,
+		Config: docs.FieldString("", ""),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "baz",
+		Type: docs.TypeOutput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("b", ""),
+			docs.FieldObject("batching", "").WithChildren(
+				docs.FieldInt("count", "").HasDefault(0),
+				docs.FieldString("period", "").HasDefault(""),
+			),
+		),
+	})
+	return prov
+}
+
+func testSpec() docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldInput("input", ""),
+		docs.FieldObject("pipeline", "").WithChildren(
+			docs.FieldProcessor("processors", "").Array(),
+		),
+		docs.FieldOutput("output", ""),
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	conf := `
+input:
+  foo:
+    a: hello
+    b: ""
+    c: 10
+pipeline:
+  processors:
+    - old: x
+output:
+  baz:
+    b: meow
+  processors:
+    - bar: y
+`
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+
+	suggestions, err := doctor.Diagnose(testProvider(), testSpec(), &node)
+	require.NoError(t, err)
+
+	var strs []string
+	for _, s := range suggestions {
+		strs = append(strs, s.String())
+	}
+	assert.Equal(t, []string{
+		"line 5: input.foo.b: field b is deprecated and is set to its default value, it can be removed",
+		"line 6: input.foo.c: field c is deprecated and will be removed in the next major version, check the documentation of this component for alternatives",
+		"line 9: pipeline.processors.0: processor old is deprecated and will be removed in the next major version, check the documentation of this component for alternatives",
+		"line 11: output.baz: output baz supports batching but none is configured, setting batching.count or batching.period can greatly improve throughput",
+		"line 14: output.processors: processors of the output can be moved to the end of pipeline.processors, where they are executed in parallel according to pipeline.threads",
+	}, strs)
+}
+
+func TestFix(t *testing.T) {
+	conf := `
+input:
+  foo:
+    a: hello
+    b: ""
+output:
+  baz:
+    b: meow
+    batching:
+      count: 10
+  processors:
+    - bar: y
+`
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+
+	remaining, err := doctor.Fix(testProvider(), testSpec(), &node)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	resBytes, err := yaml.Marshal(&node)
+	require.NoError(t, err)
+	assert.Equal(t, `input:
+    foo:
+        a: hello
+output:
+    baz:
+        b: meow
+        batching:
+            count: 10
+pipeline:
+    processors:
+        - bar: y
+`, string(resBytes))
+}
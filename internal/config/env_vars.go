@@ -22,16 +22,22 @@ var (
 // the environment variable is empty or does not exist then either the default
 // value is used or the field will be left empty.
 func ReplaceEnvVariables(inBytes []byte) []byte {
+	return ReplaceVariables(inBytes, os.Getenv)
+}
+
+// ReplaceVariables behaves the same as ReplaceEnvVariables, but obtains the
+// value of each variable from a lookup func rather than the environment.
+func ReplaceVariables(inBytes []byte, lookup func(name string) string) []byte {
 	replaced := envRegex.ReplaceAllFunc(inBytes, func(content []byte) []byte {
 		var value string
 		if len(content) > 3 {
 			if colonIndex := bytes.IndexByte(content, ':'); colonIndex == -1 {
-				value = os.Getenv(string(content[2 : len(content)-1]))
+				value = lookup(string(content[2 : len(content)-1]))
 			} else {
 				targetVar := content[2:colonIndex]
 				defaultVal := content[colonIndex+1 : len(content)-1]
 
-				value = os.Getenv(string(targetVar))
+				value = lookup(string(targetVar))
 				if value == "" {
 					value = string(defaultVal)
 				}
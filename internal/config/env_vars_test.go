@@ -42,3 +42,30 @@ func TestEnvSwapping(t *testing.T) {
 		}
 	}
 }
+
+func TestVariableSwapping(t *testing.T) {
+	vars := map[string]string{
+		"TOPIC": "foo",
+		"MULTI": "foo\nbar",
+	}
+	lookup := func(name string) string {
+		return vars[name]
+	}
+
+	tests := map[string]string{
+		"topic: ${TOPIC}":                "topic: foo",
+		"topic: ${TOPIC:bar}":            "topic: foo",
+		"topic: ${NOPE:bar}":             "topic: bar",
+		"topic: ${NOPE}":                 "topic: ",
+		"topic: ${MULTI}":                "topic: foo\\nbar",
+		"topic: ${{TOPIC}}":              "topic: ${TOPIC}",
+		`topic: ${! meta("kafka_key") }`: `topic: ${! meta("kafka_key") }`,
+	}
+
+	for in, exp := range tests {
+		out := ReplaceVariables([]byte(in), lookup)
+		if act := string(out); act != exp {
+			t.Errorf("Wrong result: %v != %v", act, exp)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// Node is a single component within a config graph.
+type Node struct {
+	ID       string
+	Type     docs.Type
+	Name     string
+	Label    string
+	Resource bool
+}
+
+// Title returns a human readable title of the node.
+func (n Node) Title() string {
+	if n.Label != "" {
+		return fmt.Sprintf("%v (%v)", n.Label, n.Name)
+	}
+	return n.Name
+}
+
+// Edge is a connection between two nodes of a config graph. Edges that are
+// nested describe a parent component executing or referencing a child
+// component, rather than the child receiving data from the parent.
+type Edge struct {
+	From   string
+	To     string
+	Nested bool
+}
+
+// Graph describes the components of a config and how data flows between them.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+type resourceRef struct {
+	from  string
+	cType docs.Type
+	label string
+}
+
+type builder struct {
+	prov docs.Provider
+	g    *Graph
+
+	counts    map[docs.Type]int
+	resources map[docs.Type]map[string]string
+	refs      []resourceRef
+}
+
+var resourceFields = []struct {
+	name  string
+	cType docs.Type
+}{
+	{"input_resources", docs.TypeInput},
+	{"processor_resources", docs.TypeProcessor},
+	{"output_resources", docs.TypeOutput},
+	{"cache_resources", docs.TypeCache},
+	{"rate_limit_resources", docs.TypeRateLimit},
+}
+
+// FromYAML walks a parsed config and returns a graph of the components within
+// it. Components that cannot be inferred result in an error.
+func FromYAML(prov docs.Provider, root *yaml.Node) (*Graph, error) {
+	b := &builder{
+		prov:      prov,
+		g:         &Graph{},
+		counts:    map[docs.Type]int{},
+		resources: map[docs.Type]map[string]string{},
+	}
+
+	if root = unwrap(root); root == nil || root.Kind != yaml.MappingNode {
+		return nil, errors.New("expected object value")
+	}
+
+	var prev string
+	var err error
+
+	if inNode := field(root, "input"); inNode != nil {
+		if prev, err = b.input(inNode); err != nil {
+			return nil, fmt.Errorf("input: %w", err)
+		}
+	}
+
+	if bufNode := field(root, "buffer"); bufNode != nil {
+		if name, _, _ := docs.GetInferenceCandidateFromYAML(prov, docs.TypeBuffer, bufNode); name != "" && name != "none" {
+			var id string
+			if id, err = b.component(docs.TypeBuffer, bufNode, false); err != nil {
+				return nil, fmt.Errorf("buffer: %w", err)
+			}
+			b.edge(prev, id, false)
+			prev = id
+		}
+	}
+
+	if pipeNode := field(root, "pipeline"); pipeNode != nil {
+		if prev, err = b.processors(field(pipeNode, "processors"), prev); err != nil {
+			return nil, fmt.Errorf("pipeline: %w", err)
+		}
+	}
+
+	if outNode := field(root, "output"); outNode != nil {
+		if err = b.output(outNode, prev); err != nil {
+			return nil, fmt.Errorf("output: %w", err)
+		}
+	}
+
+	for _, rf := range resourceFields {
+		rNode := field(root, rf.name)
+		if rNode == nil || rNode.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i, n := range rNode.Content {
+			if err = b.resource(rf.cType, n); err != nil {
+				return nil, fmt.Errorf("%v.%v: %w", rf.name, i, err)
+			}
+		}
+	}
+
+	for _, ref := range b.refs {
+		if to, exists := b.resources[ref.cType][ref.label]; exists {
+			b.edge(ref.from, to, true)
+		}
+	}
+	return b.g, nil
+}
+
+func unwrap(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+func field(node *yaml.Node, name string) *yaml.Node {
+	node = unwrap(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func (b *builder) edge(from, to string, nested bool) {
+	if from == "" || to == "" {
+		return
+	}
+	b.g.Edges = append(b.g.Edges, Edge{From: from, To: to, Nested: nested})
+}
+
+// component adds a node for a component and walks any child components within
+// its config.
+func (b *builder) component(cType docs.Type, node *yaml.Node, isResource bool) (string, error) {
+	name, spec, err := docs.GetInferenceCandidateFromYAML(b.prov, cType, node)
+	if err != nil {
+		return "", err
+	}
+
+	id := fmt.Sprintf("%v_%v", cType, b.counts[cType])
+	b.counts[cType]++
+
+	var label string
+	if lNode := field(node, "label"); lNode != nil {
+		label = lNode.Value
+	}
+
+	b.g.Nodes = append(b.g.Nodes, Node{
+		ID:       id,
+		Type:     cType,
+		Name:     name,
+		Label:    label,
+		Resource: isResource,
+	})
+
+	confNode := field(node, name)
+	if name == "resource" {
+		if confNode != nil {
+			b.refs = append(b.refs, resourceRef{from: id, cType: cType, label: confNode.Value})
+		}
+		return id, nil
+	}
+
+	// Components that access cache and rate limit resources by label.
+	switch {
+	case name == "cache" && cType == docs.TypeProcessor:
+		b.refField(id, docs.TypeCache, confNode, "resource")
+	case name == "cache" && cType == docs.TypeOutput:
+		b.refField(id, docs.TypeCache, confNode, "target")
+	case name == "rate_limit" && cType == docs.TypeProcessor:
+		b.refField(id, docs.TypeRateLimit, confNode, "resource")
+	}
+
+	if err := b.children(id, spec.Config, confNode); err != nil {
+		return "", fmt.Errorf("%v: %w", name, err)
+	}
+	return id, nil
+}
+
+func (b *builder) refField(from string, cType docs.Type, confNode *yaml.Node, name string) {
+	if lNode := field(confNode, name); lNode != nil && lNode.Value != "" {
+		b.refs = append(b.refs, resourceRef{from: from, cType: cType, label: lNode.Value})
+	}
+}
+
+func (b *builder) resource(cType docs.Type, node *yaml.Node) error {
+	id, err := b.component(cType, node, true)
+	if err != nil {
+		return err
+	}
+	if lNode := field(node, "label"); lNode != nil {
+		if b.resources[cType] == nil {
+			b.resources[cType] = map[string]string{}
+		}
+		b.resources[cType][lNode.Value] = id
+	}
+	return nil
+}
+
+// input adds an input and its processors, returning the ID of the last node
+// that data flows through.
+func (b *builder) input(node *yaml.Node) (string, error) {
+	id, err := b.component(docs.TypeInput, node, false)
+	if err != nil {
+		return "", err
+	}
+	return b.processors(field(node, "processors"), id)
+}
+
+// output adds an output preceded by its processors.
+func (b *builder) output(node *yaml.Node, prev string) error {
+	procsStart := len(b.g.Nodes)
+	last, err := b.processors(field(node, "processors"), "")
+	if err != nil {
+		return err
+	}
+
+	id, err := b.component(docs.TypeOutput, node, false)
+	if err != nil {
+		return err
+	}
+	if last == "" {
+		b.edge(prev, id, false)
+		return nil
+	}
+	b.edge(prev, b.g.Nodes[procsStart].ID, false)
+	b.edge(last, id, false)
+	return nil
+}
+
+// processors adds a chain of processors following a node, and returns the ID
+// of the last processor in the chain.
+func (b *builder) processors(node *yaml.Node, prev string) (string, error) {
+	node = unwrap(node)
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return prev, nil
+	}
+	for i, pNode := range node.Content {
+		id, err := b.component(docs.TypeProcessor, pNode, false)
+		if err != nil {
+			return "", fmt.Errorf("processors.%v: %w", i, err)
+		}
+		b.edge(prev, id, false)
+		prev = id
+	}
+	return prev, nil
+}
+
+// children walks the config of a component according to its spec and adds any
+// child components found.
+func (b *builder) children(parent string, spec docs.FieldSpec, node *yaml.Node) error {
+	node = unwrap(node)
+	if node == nil {
+		return nil
+	}
+
+	if spec.Type == docs.FieldTypeProcessor && spec.Kind == docs.KindArray {
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		var prev string
+		for i, pNode := range node.Content {
+			id, err := b.component(docs.TypeProcessor, pNode, false)
+			if err != nil {
+				return fmt.Errorf("%v.%v: %w", spec.Name, i, err)
+			}
+			if prev == "" {
+				b.edge(parent, id, true)
+			} else {
+				b.edge(prev, id, false)
+			}
+			prev = id
+		}
+		return nil
+	}
+
+	switch spec.Kind {
+	case docs.KindArray, docs.Kind2DArray:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		elemSpec := spec.Scalar()
+		if spec.Kind == docs.Kind2DArray {
+			elemSpec = spec.Array()
+		}
+		for _, n := range node.Content {
+			if err := b.children(parent, elemSpec, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case docs.KindMap:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := b.children(parent, spec.Scalar(), node.Content[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch spec.Type {
+	case docs.FieldTypeInput:
+		last, err := b.input(node)
+		if err != nil {
+			return fmt.Errorf("%v: %w", spec.Name, err)
+		}
+		b.edge(last, parent, false)
+	case docs.FieldTypeOutput:
+		if err := b.output(node, parent); err != nil {
+			return fmt.Errorf("%v: %w", spec.Name, err)
+		}
+	case docs.FieldTypeProcessor, docs.FieldTypeCache, docs.FieldTypeRateLimit, docs.FieldTypeBuffer:
+		id, err := b.component(docs.Type(spec.Type), node, false)
+		if err != nil {
+			return fmt.Errorf("%v: %w", spec.Name, err)
+		}
+		b.edge(parent, id, true)
+	case docs.FieldTypeObject:
+		for _, c := range spec.Children {
+			if err := b.children(parent, c, field(node, c.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (g *Graph) sortedNodes(resources bool) []Node {
+	var nodes []Node
+	for _, n := range g.Nodes {
+		if n.Resource == resources {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return typeOrder(nodes[i].Type) < typeOrder(nodes[j].Type)
+	})
+	return nodes
+}
+
+func typeOrder(t docs.Type) int {
+	switch t {
+	case docs.TypeInput:
+		return 0
+	case docs.TypeBuffer:
+		return 1
+	case docs.TypeProcessor:
+		return 2
+	case docs.TypeOutput:
+		return 3
+	case docs.TypeCache:
+		return 4
+	}
+	return 5
+}
+
+func dotShape(t docs.Type) string {
+	switch t {
+	case docs.TypeInput:
+		return "invhouse"
+	case docs.TypeOutput:
+		return "house"
+	case docs.TypeBuffer, docs.TypeCache:
+		return "cylinder"
+	case docs.TypeRateLimit:
+		return "octagon"
+	}
+	return "box"
+}
+
+// DOT returns the graph in the Graphviz DOT language.
+func (g *Graph) DOT() string {
+	var buf strings.Builder
+	buf.WriteString("digraph benthos {\n  rankdir=LR;\n")
+
+	writeNode := func(indent string, n Node) {
+		fmt.Fprintf(&buf, "%v%v [label=%q, shape=%v];\n", indent, n.ID, string(n.Type)+"\n"+n.Title(), dotShape(n.Type))
+	}
+
+	for _, n := range g.sortedNodes(false) {
+		writeNode("  ", n)
+	}
+	if resources := g.sortedNodes(true); len(resources) > 0 {
+		buf.WriteString("  subgraph cluster_resources {\n    label=\"resources\";\n    style=dashed;\n")
+		for _, n := range resources {
+			writeNode("    ", n)
+		}
+		buf.WriteString("  }\n")
+	}
+
+	for _, e := range g.Edges {
+		if e.Nested {
+			fmt.Fprintf(&buf, "  %v -> %v [style=dashed];\n", e.From, e.To)
+		} else {
+			fmt.Fprintf(&buf, "  %v -> %v;\n", e.From, e.To)
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func mermaidNode(n Node) string {
+	title := strings.ReplaceAll(string(n.Type)+": "+n.Title(), `"`, "#quot;")
+	switch n.Type {
+	case docs.TypeInput, docs.TypeOutput:
+		return fmt.Sprintf(`%v(["%v"])`, n.ID, title)
+	case docs.TypeBuffer, docs.TypeCache:
+		return fmt.Sprintf(`%v[("%v")]`, n.ID, title)
+	case docs.TypeRateLimit:
+		return fmt.Sprintf(`%v{{"%v"}}`, n.ID, title)
+	}
+	return fmt.Sprintf(`%v["%v"]`, n.ID, title)
+}
+
+// Mermaid returns the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	var buf strings.Builder
+	buf.WriteString("flowchart LR\n")
+
+	for _, n := range g.sortedNodes(false) {
+		fmt.Fprintf(&buf, "  %v\n", mermaidNode(n))
+	}
+	if resources := g.sortedNodes(true); len(resources) > 0 {
+		buf.WriteString("  subgraph resources\n")
+		for _, n := range resources {
+			fmt.Fprintf(&buf, "    %v\n", mermaidNode(n))
+		}
+		buf.WriteString("  end\n")
+	}
+
+	for _, e := range g.Edges {
+		if e.Nested {
+			fmt.Fprintf(&buf, "  %v -.-> %v\n", e.From, e.To)
+		} else {
+			fmt.Fprintf(&buf, "  %v --> %v\n", e.From, e.To)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,110 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config/graph"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func testProvider() docs.Provider {
+	prov := docs.NewMappedDocsProvider()
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "foo",
+		Type:   docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(docs.FieldString("a", "")),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "broker",
+		Type:   docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(docs.FieldInput("inputs", "").Array()),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "bar",
+		Type:   docs.TypeProcessor,
+		Config: docs.FieldString("", ""),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "resource",
+		Type:   docs.TypeProcessor,
+		Config: docs.FieldString("", ""),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "switch",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().Array().WithChildren(
+			docs.FieldString("check", ""),
+			docs.FieldProcessor("processors", "").Array(),
+		),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "baz",
+		Type:   docs.TypeOutput,
+		Config: docs.FieldComponent().WithChildren(docs.FieldString("b", "")),
+	})
+	return prov
+}
+
+func TestGraphFromYAML(t *testing.T) {
+	conf := `
+input:
+  broker:
+    inputs:
+      - label: first
+        foo:
+          a: hello
+      - foo:
+          a: world
+        processors:
+          - bar: x
+pipeline:
+  processors:
+    - switch:
+        - check: 'true'
+          processors:
+            - bar: y
+            - resource: shared
+output:
+  baz:
+    b: meow
+processor_resources:
+  - label: shared
+    bar: z
+`
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+
+	g, err := graph.FromYAML(testProvider(), &node)
+	require.NoError(t, err)
+
+	assert.Equal(t, `flowchart LR
+  input_0(["input: broker"])
+  input_1(["input: first (foo)"])
+  input_2(["input: foo"])
+  processor_0["processor: bar"]
+  processor_1["processor: switch"]
+  processor_2["processor: bar"]
+  processor_3["processor: resource"]
+  output_0(["output: baz"])
+  subgraph resources
+    processor_4["processor: shared (bar)"]
+  end
+  input_1 --> input_0
+  input_2 --> processor_0
+  processor_0 --> input_0
+  processor_1 -.-> processor_2
+  processor_2 --> processor_3
+  input_0 --> processor_1
+  processor_1 --> output_0
+  processor_3 -.-> processor_4
+`, g.Mermaid())
+
+	assert.Contains(t, g.DOT(), "  input_1 [label=\"input\\nfirst (foo)\", shape=invhouse];\n")
+	assert.Contains(t, g.DOT(), "  subgraph cluster_resources {\n")
+	assert.Contains(t, g.DOT(), "  processor_1 -> processor_2 [style=dashed];\n")
+}
@@ -0,0 +1,256 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// RuleConfig describes a single policy rule, which is a Bloblang predicate that
+// a config (or each matching component within it) must satisfy.
+type RuleConfig struct {
+	ID            string `yaml:"id"`
+	Description   string `yaml:"description"`
+	ComponentType string `yaml:"component_type"`
+	ComponentName string `yaml:"component_name"`
+	Check         string `yaml:"check"`
+}
+
+// Config contains a list of policy rules.
+type Config struct {
+	Rules []RuleConfig `yaml:"rules"`
+}
+
+// Violation describes a part of a config that does not satisfy a policy rule.
+type Violation struct {
+	Line   int
+	Path   string
+	RuleID string
+	What   string
+}
+
+// String returns a human readable description of the violation.
+func (v Violation) String() string {
+	if v.Line > 0 {
+		return fmt.Sprintf("line %v: %v: policy %v violated: %v", v.Line, v.Path, v.RuleID, v.What)
+	}
+	return fmt.Sprintf("%v: policy %v violated: %v", v.Path, v.RuleID, v.What)
+}
+
+type rule struct {
+	conf  RuleConfig
+	check *mapping.Executor
+}
+
+// Policy is a set of parsed rules that can be checked against configs.
+type Policy struct {
+	rules []rule
+}
+
+// New parses a policy config into a Policy. An error is returned if any of the
+// rules are invalid.
+func New(conf Config) (*Policy, error) {
+	p := &Policy{}
+	for i, r := range conf.Rules {
+		if r.ID == "" {
+			r.ID = fmt.Sprintf("%v", i)
+		}
+		if r.ComponentName != "" && r.ComponentType == "" {
+			return nil, fmt.Errorf("rule %v: component_type must be set when component_name is set", r.ID)
+		}
+		if r.ComponentType != "" {
+			if _, isCore := docs.FieldType(r.ComponentType).IsCoreComponent(); !isCore {
+				return nil, fmt.Errorf("rule %v: component_type %v is not recognised", r.ID, r.ComponentType)
+			}
+		}
+		if r.Check == "" {
+			return nil, fmt.Errorf("rule %v: a check must be specified", r.ID)
+		}
+		check, err := bloblang.GlobalEnvironment().NewMapping(r.Check)
+		if err != nil {
+			return nil, fmt.Errorf("rule %v: failed to parse check: %w", r.ID, err)
+		}
+		p.rules = append(p.rules, rule{conf: r, check: check})
+	}
+	return p, nil
+}
+
+// ReadFile reads and parses a policy from a YAML file.
+func ReadFile(path string) (*Policy, error) {
+	confBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf Config
+	if err := yaml.Unmarshal(confBytes, &conf); err != nil {
+		return nil, err
+	}
+	return New(conf)
+}
+
+// Check walks a parsed config according to a spec and returns any violations
+// of the policy rules. Rules without a component_type are checked against the
+// entire config, otherwise they are checked against the config of each
+// matching component.
+func (p *Policy) Check(prov docs.Provider, spec docs.FieldSpecs, root *yaml.Node) ([]Violation, error) {
+	if root = unwrap(root); root == nil || root.Kind != yaml.MappingNode {
+		return nil, errors.New("expected object value")
+	}
+
+	c := &checker{prov: prov, p: p}
+	for _, r := range p.rules {
+		if r.conf.ComponentType == "" {
+			c.check(r, "root", root, root)
+		}
+	}
+	for _, f := range spec {
+		c.field(f.Name, f, field(root, f.Name))
+	}
+	return c.violations, nil
+}
+
+//------------------------------------------------------------------------------
+
+func unwrap(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+func field(node *yaml.Node, name string) *yaml.Node {
+	node = unwrap(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+type checker struct {
+	prov docs.Provider
+	p    *Policy
+
+	violations []Violation
+}
+
+// check executes a rule against a config node and records a violation if the
+// check does not pass, violations are reported at the line of lineNode.
+func (c *checker) check(r rule, path string, lineNode, node *yaml.Node) {
+	var v interface{}
+	if node != nil {
+		if err := node.Decode(&v); err != nil {
+			c.add(r, path, lineNode, fmt.Sprintf("failed to decode config: %v", err))
+			return
+		}
+	}
+
+	part := message.NewPart(nil)
+	part.SetJSON(v)
+
+	msg := message.QuickBatch(nil)
+	msg.Append(part)
+
+	passed, err := r.check.QueryPart(0, msg)
+	if err != nil {
+		c.add(r, path, lineNode, fmt.Sprintf("check failed: %v", err))
+		return
+	}
+	if !passed {
+		what := r.conf.Description
+		if what == "" {
+			what = r.conf.Check
+		}
+		c.add(r, path, lineNode, what)
+	}
+}
+
+func (c *checker) add(r rule, path string, lineNode *yaml.Node, what string) {
+	v := Violation{
+		Path:   path,
+		RuleID: r.conf.ID,
+		What:   what,
+	}
+	if lineNode != nil {
+		v.Line = lineNode.Line
+	}
+	c.violations = append(c.violations, v)
+}
+
+func (c *checker) field(path string, spec docs.FieldSpec, node *yaml.Node) {
+	if node = unwrap(node); node == nil {
+		return
+	}
+
+	switch spec.Kind {
+	case docs.KindArray, docs.Kind2DArray:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		elemSpec := spec.Scalar()
+		if spec.Kind == docs.Kind2DArray {
+			elemSpec = spec.Array()
+		}
+		for i, n := range node.Content {
+			c.field(fmt.Sprintf("%v.%v", path, i), elemSpec, n)
+		}
+		return
+	case docs.KindMap:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			c.field(path+"."+node.Content[i].Value, spec.Scalar(), node.Content[i+1])
+		}
+		return
+	}
+
+	if cType, isCore := spec.Type.IsCoreComponent(); isCore {
+		c.component(path, cType, node)
+		return
+	}
+
+	for _, child := range spec.Children {
+		c.field(path+"."+child.Name, child, field(node, child.Name))
+	}
+}
+
+func (c *checker) component(path string, cType docs.Type, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	name, spec, err := docs.GetInferenceCandidateFromYAML(c.prov, cType, node)
+	if err != nil {
+		// Components that cannot be inferred are reported by the linter.
+		return
+	}
+
+	confNode := field(node, name)
+	for _, r := range c.p.rules {
+		if r.conf.ComponentType != string(cType) {
+			continue
+		}
+		if r.conf.ComponentName != "" && r.conf.ComponentName != name {
+			continue
+		}
+		c.check(r, path+"."+name, node, confNode)
+	}
+
+	c.field(path+"."+name, spec.Config, confNode)
+	if cType == docs.TypeInput || cType == docs.TypeOutput {
+		c.field(path+".processors", docs.FieldProcessor("processors", "").Array(), field(node, "processors"))
+	}
+}
@@ -0,0 +1,162 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config/policy"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func testProvider() docs.Provider {
+	prov := docs.NewMappedDocsProvider()
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "foo",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("a", ""),
+		),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "kafka",
+		Type: docs.TypeOutput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldObject("tls", "").WithChildren(
+				docs.FieldBool("enabled", ""),
+			),
+		),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name:   "drop",
+		Type:   docs.TypeOutput,
+		Config: docs.FieldObject("", ""),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "broker",
+		Type: docs.TypeOutput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldOutput("outputs", "").Array(),
+		),
+	})
+	return prov
+}
+
+func testSpec() docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldInput("input", ""),
+		docs.FieldOutput("output", ""),
+		docs.FieldString("environment", ""),
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p, err := policy.New(policy.Config{
+		Rules: []policy.RuleConfig{
+			{
+				ID:            "kafka_tls",
+				Description:   "kafka outputs must enable TLS",
+				ComponentType: "output",
+				ComponentName: "kafka",
+				Check:         `this.tls.enabled == true`,
+			},
+			{
+				ID:            "no_drop",
+				ComponentType: "output",
+				ComponentName: "drop",
+				Check:         `false`,
+			},
+			{
+				ID:          "has_env",
+				Description: "the environment must be set",
+				Check:       `this.environment.or("") != ""`,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	conf := `
+input:
+  foo:
+    a: hello
+output:
+  broker:
+    outputs:
+      - kafka:
+          tls:
+            enabled: true
+      - kafka: {}
+      - drop: {}
+`
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+
+	violations, err := p.Check(testProvider(), testSpec(), &node)
+	require.NoError(t, err)
+
+	var strs []string
+	for _, v := range violations {
+		strs = append(strs, v.String())
+	}
+	assert.Equal(t, []string{
+		"line 2: root: policy has_env violated: the environment must be set",
+		"line 11: output.broker.outputs.1.kafka: policy kafka_tls violated: kafka outputs must enable TLS",
+		"line 12: output.broker.outputs.2.drop: policy no_drop violated: false",
+	}, strs)
+}
+
+func TestPolicyCheckError(t *testing.T) {
+	p, err := policy.New(policy.Config{
+		Rules: []policy.RuleConfig{
+			{ID: "nope", Check: `this.foo.number()`},
+		},
+	})
+	require.NoError(t, err)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`foo: bar`), &node))
+
+	violations, err := p.Check(testProvider(), testSpec(), &node)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].What, "check failed")
+}
+
+func TestPolicyBadRules(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		rule policy.RuleConfig
+		err  string
+	}{
+		{
+			name: "no check",
+			rule: policy.RuleConfig{ID: "foo"},
+			err:  "rule foo: a check must be specified",
+		},
+		{
+			name: "bad component type",
+			rule: policy.RuleConfig{ID: "foo", ComponentType: "nope", Check: "true"},
+			err:  "rule foo: component_type nope is not recognised",
+		},
+		{
+			name: "name without type",
+			rule: policy.RuleConfig{ID: "foo", ComponentName: "kafka", Check: "true"},
+			err:  "rule foo: component_type must be set when component_name is set",
+		},
+		{
+			name: "bad mapping",
+			rule: policy.RuleConfig{ID: "foo", Check: "this.("},
+			err:  "rule foo: failed to parse check",
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := policy.New(policy.Config{Rules: []policy.RuleConfig{test.rule}})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
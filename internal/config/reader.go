@@ -66,6 +66,10 @@ type Reader struct {
 	streamUpdateFn StreamUpdateFunc
 	watcher        *fsnotify.Watcher
 
+	// Serialises reactions to config changes, which can be triggered either
+	// by the file watcher or by a call to TriggerReload.
+	reactMut sync.Mutex
+
 	changeFlushPeriod time.Duration
 	changeDelayPeriod time.Duration
 }
@@ -230,6 +234,7 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 						continue
 					}
 					var succeeded bool
+					r.reactMut.Lock()
 					if nameClean == filepath.Clean(r.mainPath) {
 						succeeded = r.reactMainUpdate(mgr, strict)
 					} else if _, exists := r.streamFileInfo[nameClean]; exists {
@@ -237,6 +242,7 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 					} else {
 						succeeded = r.reactResourceUpdate(mgr, strict, nameClean)
 					}
+					r.reactMut.Unlock()
 					if succeeded {
 						delete(collapsedChanges, nameClean)
 					} else {
@@ -291,6 +297,60 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 	return nil
 }
 
+// TriggerReload reads all configuration files again and applies any changes
+// found within them, as if each file had been modified. This is intended to be
+// called when the service receives a SIGHUP, and does not require file
+// watching to be enabled. Returns an error if any of the changes could not be
+// applied.
+//
+// WARNING: Either SubscribeConfigChanges or SubscribeStreamChanges must be
+// called before this.
+func (r *Reader) TriggerReload(mgr bundle.NewManagement, strict bool) error {
+	if r.mainUpdateFn == nil && r.streamUpdateFn == nil {
+		return errors.New("a reload cannot be triggered without a subscription function registered")
+	}
+
+	r.reactMut.Lock()
+	defer r.reactMut.Unlock()
+
+	var failed []string
+
+	resourcePaths, err := r.resourcePathsExpanded()
+	if err != nil {
+		return err
+	}
+	for _, p := range resourcePaths {
+		if !r.reactResourceUpdate(mgr, strict, filepath.Clean(p)) {
+			failed = append(failed, p)
+		}
+	}
+
+	if !r.streamsMode && r.mainPath != "" {
+		if !r.reactMainUpdate(mgr, strict) {
+			failed = append(failed, r.mainPath)
+		}
+	}
+
+	streamsPaths, err := r.streamPathsExpanded()
+	if err != nil {
+		return err
+	}
+	for _, p := range streamsPaths {
+		nameClean := filepath.Clean(p[1])
+		if _, exists := r.streamFileInfo[nameClean]; !exists {
+			continue
+		}
+		if !r.reactStreamUpdate(mgr, strict, nameClean) {
+			failed = append(failed, p[1])
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to apply changes from: %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 // Close the reader, when this method exits all reloading will be stopped.
 func (r *Reader) Close(ctx context.Context) error {
 	if r.watcher != nil {
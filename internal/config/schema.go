@@ -20,6 +20,8 @@ type Type struct {
 	Metrics                metrics.Config `json:"metrics" yaml:"metrics"`
 	Tracer                 tracer.Config  `json:"tracer" yaml:"tracer"`
 	SystemCloseTimeout     string         `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	SystemClosePolicy      string         `json:"shutdown_policy" yaml:"shutdown_policy"`
+	Plugins                []string       `json:"plugins" yaml:"plugins"`
 	Tests                  []interface{}  `json:"tests,omitempty" yaml:"tests,omitempty"`
 }
 
@@ -33,6 +35,8 @@ func New() Type {
 		Metrics:            metrics.NewConfig(),
 		Tracer:             tracer.NewConfig(),
 		SystemCloseTimeout: "20s",
+		SystemClosePolicy:  "graceful",
+		Plugins:            []string{},
 		Tests:              nil,
 	}
 }
@@ -44,15 +48,21 @@ var observabilityFields = docs.FieldSpecs{
 	docs.FieldMetrics("metrics", "A mechanism for exporting metrics.").Optional(),
 	docs.FieldTracer("tracer", "A mechanism for exporting traces.").Optional(),
 	docs.FieldString("shutdown_timeout", "The maximum period of time to wait for a clean shutdown. If this time is exceeded Benthos will forcefully close.").HasDefault("20s"),
+	docs.FieldString("shutdown_policy", "The policy to follow when the service receives a termination signal.").HasAnnotatedOptions(
+		"graceful", "Attempt to flush in-flight messages, but give up and close components less gracefully when the flush takes too long.",
+		"drain", "Stop all inputs and wait for all buffered and in-flight messages to be flushed to outputs before exiting, for up to the `shutdown_timeout` period. Progress can be checked with the `/drain` HTTP endpoint.",
+	).HasDefault("graceful").Advanced(),
 }
 
+var pluginsField = docs.FieldString("plugins", "EXPERIMENTAL: A list of paths to Go plugins to load before the config is parsed, which can register extra components. Relative paths are resolved from the directory of the config file. Plugins must be built with the same version of Go and the same dependency versions as the Benthos binary loading them.").Array().HasDefault([]string{}).Advanced()
+
 // Spec returns a docs.FieldSpec for an entire Benthos configuration.
 func Spec() docs.FieldSpecs {
 	fields := docs.FieldSpecs{httpField}
 	fields = append(fields, stream.Spec()...)
 	fields = append(fields, manager.Spec()...)
 	fields = append(fields, observabilityFields...)
-	fields = append(fields, tdocs.ConfigSpec())
+	fields = append(fields, pluginsField, tdocs.ConfigSpec())
 	return fields
 }
 
@@ -61,6 +71,6 @@ func SpecWithoutStream() docs.FieldSpecs {
 	fields := docs.FieldSpecs{httpField}
 	fields = append(fields, manager.Spec()...)
 	fields = append(fields, observabilityFields...)
-	fields = append(fields, tdocs.ConfigSpec())
+	fields = append(fields, pluginsField, tdocs.ConfigSpec())
 	return fields
 }
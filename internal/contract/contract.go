@@ -0,0 +1,284 @@
+// Package contract infers JSON schemas from the structure of sampled messages
+// and detects messages that drift from them, allowing the implicit contract of
+// a stream of data to be monitored and enforced.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Contract infers a schema from the first messages it observes and, once a
+// number of samples have been observed, reports whether subsequent messages
+// drift from that schema. A contract is safe to use concurrently.
+type Contract struct {
+	sampleCount  int
+	keepLearning bool
+
+	mut     sync.RWMutex
+	schema  *node
+	samples int
+}
+
+// New creates a contract that infers its schema from a number of samples
+// before enforcing it. When keepLearning is true the schema continues to be
+// inferred from every message observed, and drift is never reported.
+func New(sampleCount int, keepLearning bool) *Contract {
+	return &Contract{
+		sampleCount:  sampleCount,
+		keepLearning: keepLearning,
+		schema:       newNode(),
+	}
+}
+
+// Check observes a structured value, returning descriptions of the ways in
+// which it drifts from the inferred schema once the schema is enforced. The
+// value is added to the samples of the schema when it is still being inferred.
+func (c *Contract) Check(v interface{}) []string {
+	c.mut.RLock()
+	enforced := !c.keepLearning && c.samples >= c.sampleCount
+	var drift []string
+	if enforced {
+		drift = c.schema.drift("root", v, nil)
+	}
+	c.mut.RUnlock()
+	if enforced {
+		return drift
+	}
+
+	c.mut.Lock()
+	if c.keepLearning || c.samples < c.sampleCount {
+		c.schema.observe(v)
+		c.samples++
+	}
+	c.mut.Unlock()
+	return nil
+}
+
+// Enforced returns whether the schema has finished being inferred and is being
+// enforced.
+func (c *Contract) Enforced() bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return !c.keepLearning && c.samples >= c.sampleCount
+}
+
+// Samples returns the number of messages that the schema was inferred from.
+func (c *Contract) Samples() int {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.samples
+}
+
+// JSONSchema returns a JSON schema document describing the inferred schema.
+func (c *Contract) JSONSchema() map[string]interface{} {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	doc := c.schema.jsonSchema()
+	doc["$schema"] = "http://json-schema.org/draft-07/schema#"
+	return doc
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	typeNull    = "null"
+	typeBoolean = "boolean"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeString  = "string"
+	typeArray   = "array"
+	typeObject  = "object"
+)
+
+// node describes the inferred schema of a value, which is a union of the types
+// it has been observed as.
+type node struct {
+	types map[string]struct{}
+
+	// The number of objects observed, and the number of those objects that
+	// each property was observed in, which determines whether the property is
+	// required.
+	objects    int
+	properties map[string]*node
+	seen       map[string]int
+
+	items *node
+}
+
+func newNode() *node {
+	return &node{
+		types:      map[string]struct{}{},
+		properties: map[string]*node{},
+		seen:       map[string]int{},
+	}
+}
+
+func typeOf(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return typeNull
+	case bool:
+		return typeBoolean
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return typeInteger
+		}
+		return typeNumber
+	case float64:
+		if t == math.Trunc(t) {
+			return typeInteger
+		}
+		return typeNumber
+	case float32:
+		if float64(t) == math.Trunc(float64(t)) {
+			return typeInteger
+		}
+		return typeNumber
+	case int, int64, int32, uint64, uint32:
+		return typeInteger
+	case string:
+		return typeString
+	case []interface{}:
+		return typeArray
+	case map[string]interface{}:
+		return typeObject
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func (n *node) observe(v interface{}) {
+	n.types[typeOf(v)] = struct{}{}
+	switch t := v.(type) {
+	case []interface{}:
+		if n.items == nil {
+			n.items = newNode()
+		}
+		for _, e := range t {
+			n.items.observe(e)
+		}
+	case map[string]interface{}:
+		n.objects++
+		for k, e := range t {
+			p, exists := n.properties[k]
+			if !exists {
+				p = newNode()
+				n.properties[k] = p
+			}
+			p.observe(e)
+			n.seen[k]++
+		}
+	}
+}
+
+func (n *node) accepts(t string) bool {
+	if _, exists := n.types[t]; exists {
+		return true
+	}
+	if t == typeInteger {
+		_, exists := n.types[typeNumber]
+		return exists
+	}
+	return false
+}
+
+func (n *node) typeNames() []string {
+	names := make([]string, 0, len(n.types))
+	for t := range n.types {
+		if t == typeInteger {
+			if _, exists := n.types[typeNumber]; exists {
+				continue
+			}
+		}
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (n *node) required() []string {
+	var keys []string
+	for k, count := range n.seen {
+		if count == n.objects {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (n *node) drift(path string, v interface{}, drift []string) []string {
+	t := typeOf(v)
+	if !n.accepts(t) {
+		types := n.typeNames()
+		if len(types) == 1 {
+			return append(drift, fmt.Sprintf("%v: expected %v, got %v", path, types[0], t))
+		}
+		return append(drift, fmt.Sprintf("%v: expected one of %v, got %v", path, types, t))
+	}
+
+	switch t := v.(type) {
+	case []interface{}:
+		if n.items == nil {
+			break
+		}
+		for i, e := range t {
+			drift = n.items.drift(fmt.Sprintf("%v[%v]", path, i), e, drift)
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(t) {
+			p, exists := n.properties[k]
+			if !exists {
+				drift = append(drift, fmt.Sprintf("%v.%v: unexpected field", path, k))
+				continue
+			}
+			drift = p.drift(path+"."+k, t[k], drift)
+		}
+		for _, k := range n.required() {
+			if _, exists := t[k]; !exists {
+				drift = append(drift, fmt.Sprintf("%v.%v: missing required field", path, k))
+			}
+		}
+	}
+	return drift
+}
+
+func (n *node) jsonSchema() map[string]interface{} {
+	doc := map[string]interface{}{}
+
+	types := n.typeNames()
+	if len(types) == 1 {
+		doc["type"] = types[0]
+	} else if len(types) > 1 {
+		doc["type"] = types
+	}
+
+	if len(n.properties) > 0 {
+		props := map[string]interface{}{}
+		for k, p := range n.properties {
+			props[k] = p.jsonSchema()
+		}
+		doc["properties"] = props
+		if required := n.required(); len(required) > 0 {
+			doc["required"] = required
+		}
+	}
+
+	if n.items != nil && len(n.items.types) > 0 {
+		doc["items"] = n.items.jsonSchema()
+	}
+	return doc
+}
@@ -0,0 +1,110 @@
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestContractInference(t *testing.T) {
+	c := New(3, false)
+
+	for _, s := range []string{
+		`{"id":1,"name":"foo","tags":["a"],"meta":{"score":1}}`,
+		`{"id":2,"name":"bar","tags":[],"meta":{"score":1.5}}`,
+		`{"id":3,"name":null,"tags":["b","c"]}`,
+	} {
+		assert.False(t, c.Enforced())
+		assert.Empty(t, c.Check(parse(t, s)))
+	}
+	assert.True(t, c.Enforced())
+	assert.Equal(t, 3, c.Samples())
+
+	schemaBytes, err := json.Marshal(c.JSONSchema())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"id": {"type": "integer"},
+		"name": {"type": ["null","string"]},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"meta": {
+			"type": "object",
+			"properties": {"score": {"type": "number"}},
+			"required": ["score"]
+		}
+	},
+	"required": ["id","name","tags"]
+}`, string(schemaBytes))
+}
+
+func TestContractDrift(t *testing.T) {
+	c := New(2, false)
+	c.Check(parse(t, `{"id":1,"name":"foo","tags":["a"],"meta":{"score":1.5}}`))
+	c.Check(parse(t, `{"id":2,"name":"bar","tags":["b"]}`))
+
+	tests := []struct {
+		input string
+		drift []string
+	}{
+		{input: `{"id":3,"name":"baz","tags":[]}`},
+		{input: `{"id":3,"name":"baz","tags":[],"meta":{"score":2}}`},
+		{
+			input: `{"id":"3","name":"baz","tags":["a",1]}`,
+			drift: []string{
+				"root.id: expected integer, got string",
+				"root.tags[1]: expected string, got integer",
+			},
+		},
+		{
+			input: `{"id":3,"tags":[],"extra":true}`,
+			drift: []string{
+				"root.extra: unexpected field",
+				"root.name: missing required field",
+			},
+		},
+		{
+			input: `{"id":3.5,"name":"baz","tags":[]}`,
+			drift: []string{"root.id: expected integer, got number"},
+		},
+		{
+			input: `["nope"]`,
+			drift: []string{"root: expected object, got array"},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.drift, c.Check(parse(t, test.input)), test.input)
+	}
+	assert.Equal(t, 2, c.Samples())
+}
+
+func TestContractKeepLearning(t *testing.T) {
+	c := New(1, true)
+	assert.Empty(t, c.Check(parse(t, `{"id":1}`)))
+	assert.Empty(t, c.Check(parse(t, `{"id":"1","name":"foo"}`)))
+	assert.False(t, c.Enforced())
+	assert.Equal(t, 2, c.Samples())
+
+	schemaBytes, err := json.Marshal(c.JSONSchema())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"id": {"type": ["integer","string"]},
+		"name": {"type": "string"}
+	},
+	"required": ["id"]
+}`, string(schemaBytes))
+}
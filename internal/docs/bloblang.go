@@ -366,6 +366,7 @@ func BloblangMethodsMarkdown() ([]byte, error) {
 		query.MethodCategoryParsing,
 		query.MethodCategoryEncoding,
 		query.MethodCategoryGeoIP,
+		query.MethodCategoryNetwork,
 		query.MethodCategoryDeprecated,
 	} {
 		methods := methodCategory{
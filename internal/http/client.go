@@ -82,11 +82,10 @@ func NewClient(conf docs.Config, opts ...func(*Client)) (*Client, error) {
 		headers:   map[string]*field.Expression{},
 		host:      nil,
 	}
-	h.oauthClientCtx, h.oauthClientCancel = context.WithCancel(context.Background())
-	h.client = conf.OAuth2.Client(h.oauthClientCtx)
+	h.client = &http.Client{}
 
+	var err error
 	if tout := conf.Timeout; len(tout) > 0 {
-		var err error
 		if h.client.Timeout, err = time.ParseDuration(tout); err != nil {
 			return nil, fmt.Errorf("failed to parse timeout string: %v", err)
 		}
@@ -142,7 +141,13 @@ func NewClient(conf docs.Config, opts ...func(*Client)) (*Client, error) {
 		opt(&h)
 	}
 
-	var err error
+	// OAuth2 is applied last as it wraps the transport configured above.
+	h.oauthClientCtx, h.oauthClientCancel = context.WithCancel(context.Background())
+	if h.client.Transport, err = conf.OAuth2.WrapTransport(h.oauthClientCtx, h.client.Transport); err != nil {
+		h.oauthClientCancel()
+		return nil, err
+	}
+
 	if h.url, err = h.mgr.BloblEnvironment().NewField(conf.URL); err != nil {
 		return nil, fmt.Errorf("failed to parse URL expression: %v", err)
 	}
@@ -373,7 +378,10 @@ func (h *Client) CreateRequest(sendMsg, refMsg *message.Batch) (req *http.Reques
 		req.Header.Add("Content-Type", overrideContentType)
 	}
 
-	err = h.conf.Config.Sign(req)
+	if err = h.conf.Config.Sign(req); err != nil {
+		return
+	}
+	err = h.conf.Kerberos.Sign(req)
 	return
 }
 
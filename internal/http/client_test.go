@@ -469,3 +469,160 @@ func TestHTTPClientReceiveMultipart(t *testing.T) {
 		assert.Equal(t, "201", resMsg.Get(1).MetaGet("http_status_code"))
 	}
 }
+
+func TestHTTPClientOAuth2(t *testing.T) {
+	tests := []struct {
+		name         string
+		refreshToken string
+		expectedForm map[string]string
+	}{
+		{
+			name: "client credentials",
+			expectedForm: map[string]string{
+				"grant_type": "client_credentials",
+				"audience":   "foo",
+			},
+		},
+		{
+			name:         "refresh token",
+			refreshToken: "rtoken0",
+			expectedForm: map[string]string{
+				"grant_type":    "refresh_token",
+				"refresh_token": "rtoken0",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var tokenReqs uint32
+			tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				n := atomic.AddUint32(&tokenReqs, 1)
+				if n == 1 {
+					for k, v := range test.expectedForm {
+						assert.Equal(t, v, r.PostForm.Get(k), k)
+					}
+				} else if test.refreshToken != "" {
+					// Rotated refresh tokens must be used for renewals.
+					assert.Equal(t, fmt.Sprintf("rtoken%v", n-1), r.PostForm.Get("refresh_token"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"access_token":"token%v","refresh_token":"rtoken%v","token_type":"bearer","expires_in":60}`, n, n)
+			}))
+			defer tokenServer.Close()
+
+			authHeaders := make(chan string, 10)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authHeaders <- r.Header.Get("Authorization")
+			}))
+			defer ts.Close()
+
+			conf := docs.NewConfig()
+			conf.URL = ts.URL + "/testpost"
+			conf.OAuth2.Enabled = true
+			conf.OAuth2.ClientKey = "key"
+			conf.OAuth2.ClientSecret = "secret"
+			conf.OAuth2.TokenURL = tokenServer.URL
+			conf.OAuth2.RefreshToken = test.refreshToken
+			conf.OAuth2.EndpointParams = map[string]string{"audience": "foo"}
+
+			// Tokens are cached while they remain valid for longer than the
+			// renewal period.
+			conf.OAuth2.RenewBeforeExpiry = "10s"
+
+			h, err := NewClient(conf)
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				testMsg := message.QuickBatch([][]byte{[]byte("test")})
+				_, err = h.Send(context.Background(), testMsg, testMsg)
+				require.NoError(t, err)
+				assert.Equal(t, "Bearer token1", <-authHeaders)
+			}
+			assert.Equal(t, uint32(1), atomic.LoadUint32(&tokenReqs))
+			require.NoError(t, h.Close(context.Background()))
+
+			// Tokens are renewed on every request when they expire within the
+			// renewal period.
+			conf.OAuth2.RenewBeforeExpiry = "2m"
+			if test.refreshToken != "" {
+				conf.OAuth2.RefreshToken = "rtoken1"
+			}
+
+			h, err = NewClient(conf)
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				testMsg := message.QuickBatch([][]byte{[]byte("test")})
+				_, err = h.Send(context.Background(), testMsg, testMsg)
+				require.NoError(t, err)
+				assert.Equal(t, fmt.Sprintf("Bearer token%v", i+2), <-authHeaders)
+			}
+			require.NoError(t, h.Close(context.Background()))
+		})
+	}
+}
+
+func TestHTTPClientOAuth2Unauthorized(t *testing.T) {
+	var tokenReqs uint32
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddUint32(&tokenReqs, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token%v","token_type":"bearer","expires_in":3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	type request struct {
+		auth string
+		body string
+	}
+	reqs := make(chan request, 10)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		reqs <- request{auth: r.Header.Get("Authorization"), body: string(b)}
+
+		// The first token is revoked before it expires.
+		if r.Header.Get("Authorization") == "Bearer token1" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	conf := docs.NewConfig()
+	conf.URL = ts.URL + "/testpost"
+	conf.TLS.Enabled = true
+	conf.TLS.InsecureSkipVerify = true
+	conf.OAuth2.Enabled = true
+	conf.OAuth2.ClientKey = "key"
+	conf.OAuth2.ClientSecret = "secret"
+	conf.OAuth2.TokenURL = tokenServer.URL
+
+	h, err := NewClient(conf)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		testMsg := message.QuickBatch([][]byte{[]byte("test")})
+		_, err = h.Send(context.Background(), testMsg, testMsg)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, request{auth: "Bearer token1", body: "test"}, <-reqs)
+	assert.Equal(t, request{auth: "Bearer token2", body: "test"}, <-reqs)
+	assert.Equal(t, request{auth: "Bearer token2", body: "test"}, <-reqs)
+	assert.Equal(t, uint32(2), atomic.LoadUint32(&tokenReqs))
+
+	require.NoError(t, h.Close(context.Background()))
+}
+
+func TestHTTPClientOAuth2BadRenewal(t *testing.T) {
+	conf := docs.NewConfig()
+	conf.URL = "http://localhost:1234"
+	conf.OAuth2.Enabled = true
+	conf.OAuth2.RenewBeforeExpiry = "nope"
+
+	_, err := NewClient(conf)
+	require.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package auth
 
-import "github.com/benthosdev/benthos/v4/internal/docs"
+import (
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/kerberos"
+)
 
 // BasicAuthFieldSpec returns a basic authentication field spec.
 func BasicAuthFieldSpec() docs.FieldSpec {
@@ -41,7 +44,7 @@ func oAuthFieldSpec() docs.FieldSpec {
 
 func oAuth2FieldSpec() docs.FieldSpec {
 	return docs.FieldObject("oauth2",
-		"Allows you to specify open authentication via OAuth version 2 using the client credentials token flow.",
+		"Allows you to specify open authentication via OAuth version 2 using either the client credentials token flow or, when a `refresh_token` is specified, the refresh token flow. Tokens are cached and renewed shortly before they expire, and when a request is rejected with a 401 status code the token is renewed and the request is retried once.",
 	).Advanced().WithChildren(
 		docs.FieldBool(
 			"enabled", "Whether to use OAuth version 2 in requests.",
@@ -62,6 +65,20 @@ func oAuth2FieldSpec() docs.FieldSpec {
 		docs.FieldString(
 			"scopes", "A list of optional requested permissions.",
 		).Array().Advanced().AtVersion("3.45.0"),
+
+		docs.FieldString(
+			"refresh_token", "An optional refresh token, when specified access tokens are obtained using the refresh token flow rather than the client credentials flow. If the token provider rotates refresh tokens then the latest refresh token is used for subsequent renewals.",
+		).HasDefault("").Advanced().AtVersion("4.2.0"),
+
+		docs.FieldString(
+			"endpoint_params", "A map of additional parameters to send to the token provider with client credentials token requests.",
+			map[string]string{"audience": "https://example.com/api"},
+		).Map().HasDefault(map[string]string{}).Advanced().AtVersion("4.2.0"),
+
+		docs.FieldString(
+			"renew_before_expiry", "A period of time before an access token expires at which it is renewed, which prevents requests from being sent with tokens that expire while in flight.",
+			"10s", "1m",
+		).HasDefault("10s").Advanced().AtVersion("4.2.0"),
 	)
 }
 
@@ -91,6 +108,20 @@ func jwtFieldSpec() docs.FieldSpec {
 	)
 }
 
+func kerberosFieldSpec() docs.FieldSpec {
+	return docs.FieldObject("kerberos",
+		"Allows you to specify [SPNEGO](https://datatracker.ietf.org/doc/html/rfc4559) authentication with Kerberos, where a service ticket is obtained for each request and added to the `Authorization` header.",
+	).Advanced().AtVersion("4.2.0").WithChildren(append(docs.FieldSpecs{
+		docs.FieldBool(
+			"enabled", "Whether to use SPNEGO authentication in requests.",
+		).HasDefault(false),
+
+		docs.FieldString(
+			"spn", "The service principal name to obtain tickets for. By default the name `HTTP/<host>` is used, where the host is taken from the request URL.", "HTTP/example.com",
+		).HasDefault(""),
+	}, kerberos.FieldSpecs()...)...)
+}
+
 // FieldSpecs returns a map of field specs for an auth type.
 func FieldSpecs() docs.FieldSpecs {
 	return docs.FieldSpecs{
@@ -107,5 +138,6 @@ func FieldSpecsExpanded() docs.FieldSpecs {
 		oAuth2FieldSpec(),
 		jwtFieldSpec(),
 		BasicAuthFieldSpec(),
+		kerberosFieldSpec(),
 	}
 }
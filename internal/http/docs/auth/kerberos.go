@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+
+	"github.com/benthosdev/benthos/v4/internal/kerberos"
+)
+
+//------------------------------------------------------------------------------
+
+// KerberosConfig holds the configuration parameters for SPNEGO authentication
+// with Kerberos.
+type KerberosConfig struct {
+	Enabled         bool   `json:"enabled" yaml:"enabled"`
+	SPN             string `json:"spn" yaml:"spn"`
+	kerberos.Config `json:",inline" yaml:",inline"`
+
+	// internal private fields
+	clientMx *sync.Mutex
+	client   **client.Client
+}
+
+// NewKerberosConfig returns a new KerberosConfig with default values.
+func NewKerberosConfig() KerberosConfig {
+	var cl *client.Client
+	return KerberosConfig{
+		Enabled:  false,
+		SPN:      "",
+		Config:   kerberos.NewConfig(),
+		clientMx: &sync.Mutex{},
+		client:   &cl,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Sign method to sign an HTTP request with a SPNEGO token.
+func (k KerberosConfig) Sign(req *http.Request) error {
+	if !k.Enabled {
+		return nil
+	}
+
+	cl, err := k.getClient()
+	if err != nil {
+		return err
+	}
+	return spnego.SetSPNEGOHeader(cl, req, k.SPN)
+}
+
+// getClient creates the Kerberos client once, which caches tickets between
+// requests. Needs mutex locking as Sign might be called by parallel threads.
+func (k KerberosConfig) getClient() (*client.Client, error) {
+	k.clientMx.Lock()
+	defer k.clientMx.Unlock()
+
+	if *k.client != nil {
+		return *k.client, nil
+	}
+
+	cl, err := k.Config.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	*k.client = cl
+	return cl, nil
+}
@@ -2,8 +2,14 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sync"
+	"time"
 
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
@@ -11,39 +17,194 @@ import (
 
 // OAuth2Config holds the configuration parameters for an OAuth2 exchange.
 type OAuth2Config struct {
-	Enabled      bool     `json:"enabled" yaml:"enabled"`
-	ClientKey    string   `json:"client_key" yaml:"client_key"`
-	ClientSecret string   `json:"client_secret" yaml:"client_secret"`
-	TokenURL     string   `json:"token_url" yaml:"token_url"`
-	Scopes       []string `json:"scopes" yaml:"scopes"`
+	Enabled           bool              `json:"enabled" yaml:"enabled"`
+	ClientKey         string            `json:"client_key" yaml:"client_key"`
+	ClientSecret      string            `json:"client_secret" yaml:"client_secret"`
+	TokenURL          string            `json:"token_url" yaml:"token_url"`
+	Scopes            []string          `json:"scopes" yaml:"scopes"`
+	RefreshToken      string            `json:"refresh_token" yaml:"refresh_token"`
+	EndpointParams    map[string]string `json:"endpoint_params" yaml:"endpoint_params"`
+	RenewBeforeExpiry string            `json:"renew_before_expiry" yaml:"renew_before_expiry"`
 }
 
 // NewOAuth2Config returns a new OAuth2Config with default values.
 func NewOAuth2Config() OAuth2Config {
 	return OAuth2Config{
-		Enabled:      false,
-		ClientKey:    "",
-		ClientSecret: "",
-		TokenURL:     "",
-		Scopes:       []string{},
+		Enabled:           false,
+		ClientKey:         "",
+		ClientSecret:      "",
+		TokenURL:          "",
+		Scopes:            []string{},
+		RefreshToken:      "",
+		EndpointParams:    map[string]string{},
+		RenewBeforeExpiry: "10s",
 	}
 }
 
 //------------------------------------------------------------------------------
 
-// Client returns an http.Client with OAuth2 configured.
-func (oauth OAuth2Config) Client(ctx context.Context) *http.Client {
+// WrapTransport returns an http.RoundTripper that adds OAuth2 access tokens to
+// requests sent with a base round tripper, or the base round tripper itself
+// when OAuth2 is disabled. Tokens are obtained with the refresh token flow when
+// a refresh token is configured, otherwise the client credentials flow is used.
+// Tokens are cached and renewed once they are within the configured period of
+// expiring, and are also renewed when a request is rejected as unauthorized,
+// in which case the request is retried once with the new token.
+func (oauth OAuth2Config) WrapTransport(ctx context.Context, base http.RoundTripper) (http.RoundTripper, error) {
 	if !oauth.Enabled {
-		var client http.Client
-		return &client
+		return base, nil
 	}
 
-	conf := &clientcredentials.Config{
-		ClientID:     oauth.ClientKey,
-		ClientSecret: oauth.ClientSecret,
-		TokenURL:     oauth.TokenURL,
-		Scopes:       oauth.Scopes,
+	var renewBefore time.Duration
+	if oauth.RenewBeforeExpiry != "" {
+		var err error
+		if renewBefore, err = time.ParseDuration(oauth.RenewBeforeExpiry); err != nil {
+			return nil, fmt.Errorf("failed to parse oauth2 renew_before_expiry: %w", err)
+		}
 	}
 
-	return conf.Client(ctx)
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// Token requests are sent with the base round tripper in order to honour
+	// any TLS and proxy settings.
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: base})
+
+	src := &renewingTokenSource{renewBefore: renewBefore}
+	if oauth.RefreshToken != "" {
+		conf := &oauth2.Config{
+			ClientID:     oauth.ClientKey,
+			ClientSecret: oauth.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				TokenURL: oauth.TokenURL,
+			},
+			Scopes: oauth.Scopes,
+		}
+		refreshToken := oauth.RefreshToken
+		src.fetch = func() (*oauth2.Token, error) {
+			// A fresh token source is created for each renewal as the sources
+			// provided by the oauth2 package cache tokens themselves, and
+			// would otherwise prevent early renewal.
+			tok, err := conf.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+			if err != nil {
+				return nil, err
+			}
+			// Providers may rotate refresh tokens.
+			refreshToken = tok.RefreshToken
+			return tok, nil
+		}
+	} else {
+		conf := &clientcredentials.Config{
+			ClientID:     oauth.ClientKey,
+			ClientSecret: oauth.ClientSecret,
+			TokenURL:     oauth.TokenURL,
+			Scopes:       oauth.Scopes,
+		}
+		if len(oauth.EndpointParams) > 0 {
+			conf.EndpointParams = url.Values{}
+			for k, v := range oauth.EndpointParams {
+				conf.EndpointParams.Set(k, v)
+			}
+		}
+		src.fetch = func() (*oauth2.Token, error) {
+			return conf.Token(ctx)
+		}
+	}
+
+	return &oauth2Transport{src: src, base: base}, nil
+}
+
+// oauth2Transport sets the Authorization header of requests, and renews the
+// token and retries a request once when it is rejected as unauthorized, which
+// covers tokens that are revoked by the provider before they expire.
+type oauth2Transport struct {
+	src  *renewingTokenSource
+	base http.RoundTripper
+}
+
+func (t *oauth2Transport) send(req *http.Request) (*http.Response, *oauth2.Token, error) {
+	tok, err := t.src.Token()
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, nil, err
+	}
+
+	// Round trippers must not modify the provided request.
+	req = req.Clone(req.Context())
+	tok.SetAuthHeader(req)
+
+	res, err := t.base.RoundTrip(req)
+	return res, tok, err
+}
+
+func (t *oauth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, tok, err := t.send(req)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	// Requests with a body can only be retried when the body can be obtained
+	// again.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return res, nil
+	}
+	t.src.invalidate(tok)
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return res, nil
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	res.Body.Close()
+
+	res, _, err = t.send(req)
+	return res, err
+}
+
+// renewingTokenSource caches a token and obtains a new one from the underlying
+// source once the cached token is within a period of expiring, which prevents
+// requests from being sent with tokens that expire while in flight.
+type renewingTokenSource struct {
+	fetch       func() (*oauth2.Token, error)
+	renewBefore time.Duration
+
+	mut sync.Mutex
+	tok *oauth2.Token
+}
+
+func (r *renewingTokenSource) Token() (*oauth2.Token, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.tok != nil && r.tok.AccessToken != "" &&
+		(r.tok.Expiry.IsZero() || time.Until(r.tok.Expiry) > r.renewBefore) {
+		return r.tok, nil
+	}
+
+	tok, err := r.fetch()
+	if err != nil {
+		return nil, err
+	}
+	r.tok = tok
+	return tok, nil
+}
+
+// invalidate discards a cached token, which results in a new token being
+// obtained by the next call to Token. Tokens that have already been replaced
+// are ignored so that concurrent rejections only result in a single renewal.
+func (r *renewingTokenSource) invalidate(tok *oauth2.Token) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.tok == tok {
+		r.tok = nil
+	}
 }
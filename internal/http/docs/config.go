@@ -24,7 +24,8 @@ type Config struct {
 	TLS             tls.Config                   `json:"tls" yaml:"tls"`
 	ProxyURL        string                       `json:"proxy_url" yaml:"proxy_url"`
 	auth.Config     `json:",inline" yaml:",inline"`
-	OAuth2          auth.OAuth2Config `json:"oauth2" yaml:"oauth2"`
+	OAuth2          auth.OAuth2Config   `json:"oauth2" yaml:"oauth2"`
+	Kerberos        auth.KerberosConfig `json:"kerberos" yaml:"kerberos"`
 }
 
 // NewConfig creates a new Config with default values.
@@ -47,5 +48,6 @@ func NewConfig() Config {
 		TLS:             tls.NewConfig(),
 		Config:          auth.NewConfig(),
 		OAuth2:          auth.NewOAuth2Config(),
+		Kerberos:        auth.NewKerberosConfig(),
 	}
 }
@@ -18,10 +18,12 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	btls "github.com/benthosdev/benthos/v4/internal/tls"
+	"github.com/benthosdev/benthos/v4/internal/transaction"
 )
 
 func init() {
@@ -31,7 +33,11 @@ func init() {
 		if a, err = newAMQP09Reader(c.AMQP09, nm.Logger()); err != nil {
 			return nil, err
 		}
-		return input.NewAsyncReader("amqp_0_9", true, a, nm.Logger(), nm.Metrics())
+		ackTimeout, err := input.AckTimeoutOpt(c.AMQP09.AckTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return input.NewAsyncReader("amqp_0_9", true, a, nm.Logger(), nm.Metrics(), ackTimeout)
 	}), docs.ComponentSpec{
 		Name: "amqp_0_9",
 		Summary: `
@@ -67,7 +73,35 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### Dead Letter Exchanges
+
+Messages that are rejected, either because their error matches one of the
+` + "[`nack_reject_patterns`](#nack_reject_patterns)" + ` or because they have
+exhausted the attempts of the ` + "[`nack_policy`](#nack_policy)" + `, are by
+default dropped by the server, or routed to a dead letter exchange when one is
+configured on the queue itself.
+
+Alternatively, setting ` + "[`dead_letter.exchange`](#dead_letterexchange)" + `
+causes rejected messages to be published to that exchange by this input. The
+original message is only acknowledged once the server has confirmed the
+publish, and if the publish is not confirmed the message is requeued instead,
+which means rejected messages are never lost. This is useful with queues that
+do not support at-least-once dead lettering, and for routing rejected messages
+to a different destination than other dead letters of the queue.
+
+### Request Reply
+
+Messages that set the ` + "`reply_to`" + ` property are treated as requests, and can
+be replied to with the result of the pipeline by using a
+` + "[`sync_response` output](/docs/components/outputs/sync_response)" + `. Each
+response message is published via the default exchange to the queue named by
+` + "`reply_to`" + `, with the ` + "`correlation_id`" + ` of the request and with its metadata
+added as headers, excluding fields prefixed with ` + "`amqp_`" + `. Responses are
+only sent once messages have been successfully delivered to all outputs, and a
+failure to send a response is treated as a delivery failure. For more
+information read [Synchronous Responses](/docs/guides/sync_responses).`,
 		Categories: []string{
 			"Services",
 		},
@@ -85,7 +119,29 @@ then the declaration passively verifies that they match the target fields.`,
 			).WithChildren(
 				docs.FieldBool("enabled", "Whether to enable queue declaration.").HasDefault(false),
 				docs.FieldBool("durable", "Whether the declared queue is durable.").HasDefault(false),
+				docs.FieldString("type", "The type of the declared queue. Quorum queues must be durable.").HasOptions(
+					"classic", "quorum",
+				).AtVersion("4.2.0").HasDefault("classic"),
+				docs.FieldAnything("arguments", "Optional arguments of the declared queue.", map[string]interface{}{
+					"x-max-length":           10000,
+					"x-dead-letter-exchange": "dlx",
+				}).Map().AtVersion("4.2.0").HasDefault(map[string]interface{}{}),
 			).Advanced().HasDefault(map[string]interface{}{}),
+			docs.FieldObject("exchanges_declare",
+				"Allows you to declare exchanges, which are declared before any bindings. If an exchange already exists then the declaration verifies that it matches the target fields.",
+				[]interface{}{
+					map[string]interface{}{
+						"exchange": "foo",
+						"type":     "topic",
+					},
+				},
+			).Array().WithChildren(
+				docs.FieldString("exchange", "The name of the exchange.").HasDefault(""),
+				docs.FieldString("type", "The type of the exchange.").HasOptions(
+					"direct", "fanout", "topic", "headers", "x-custom",
+				).HasDefault("direct"),
+				docs.FieldBool("durable", "Whether the exchange should be durable.").HasDefault(true),
+			).Advanced().AtVersion("4.2.0").HasDefault([]interface{}{}),
 			docs.FieldObject("bindings_declare",
 				"Allows you to passively declare bindings for the target queue.",
 				[]interface{}{
@@ -100,7 +156,14 @@ then the declaration passively verifies that they match the target fields.`,
 			).Advanced().HasDefault([]interface{}{}),
 			docs.FieldString("consumer_tag", "A consumer tag.").HasDefault(""),
 			docs.FieldBool("auto_ack", "Acknowledge messages automatically as they are consumed rather than waiting for acknowledgments from downstream. This can improve throughput and prevent the pipeline from blocking but at the cost of eliminating delivery guarantees.").Advanced().HasDefault(false),
+			compression.AutoDecompressFieldSpec("content encoding", "`amqp_0_9` output"),
 			docs.FieldString("nack_reject_patterns", "A list of regular expression patterns whereby if a message that has failed to be delivered by Benthos has an error that matches it will be dropped (or delivered to a dead-letter queue if one exists). By default failed messages are nacked with requeue enabled.", []string{"^reject me please:.+$"}).Array().Advanced().AtVersion("3.64.0").HasDefault([]interface{}{}),
+			input.NackPolicyFieldSpec(),
+			docs.FieldObject("dead_letter", "Optionally publish rejected messages to an exchange, acknowledging the original message only once the publish is confirmed by the server.").WithChildren(
+				docs.FieldString("exchange", "An exchange to publish rejected messages to. If empty rejected messages are dropped by the server instead (or routed by the dead letter exchange of the queue).").HasDefault(""),
+				docs.FieldString("key", "The routing key to publish rejected messages with. If empty the original routing key of each message is used.").HasDefault(""),
+			).Advanced().AtVersion("4.2.0").HasDefault(map[string]interface{}{}),
+			input.AckTimeoutFieldSpec(),
 			docs.FieldInt("prefetch_count", "The maximum number of pending messages to have consumed at a time.").HasDefault(10),
 			docs.FieldInt("prefetch_size", "The maximum amount of pending messages measured in bytes to have consumed at a time.").Advanced().HasDefault(0),
 			btls.FieldSpec(),
@@ -120,10 +183,15 @@ type amqp09Reader struct {
 	amqpChan     *amqp.Channel
 	consumerChan <-chan amqp.Delivery
 
+	deadLetterChan     *amqp.Channel
+	deadLetterConfirms <-chan amqp.Confirmation
+	deadLetterMut      sync.Mutex
+
 	urls    []string
 	tlsConf *tls.Config
 
 	nackRejectPattens []*regexp.Regexp
+	nackPolicy        input.NackPolicy
 
 	conf input.AMQP09Config
 	log  log.Modular
@@ -157,8 +225,30 @@ func newAMQP09Reader(conf input.AMQP09Config, log log.Modular) (*amqp09Reader, e
 		a.nackRejectPattens = append(a.nackRejectPattens, r)
 	}
 
+	var err error
+	if a.nackPolicy, err = conf.NackPolicy.Policy(); err != nil {
+		return nil, err
+	}
+
+	for _, eConf := range conf.ExchangesDeclare {
+		if eConf.Exchange == "" {
+			return nil, errors.New("exchanges to declare must have a name")
+		}
+	}
+
+	if conf.QueueDeclare.Enabled {
+		switch conf.QueueDeclare.Type {
+		case "", "classic":
+		case "quorum":
+			if !conf.QueueDeclare.Durable {
+				return nil, errors.New("quorum queues must be durable")
+			}
+		default:
+			return nil, fmt.Errorf("queue type %v was not recognised", conf.QueueDeclare.Type)
+		}
+	}
+
 	if conf.TLS.Enabled {
-		var err error
 		if a.tlsConf, err = conf.TLS.Get(); err != nil {
 			return nil, err
 		}
@@ -190,14 +280,35 @@ func (a *amqp09Reader) ConnectWithContext(ctx context.Context) (err error) {
 		return fmt.Errorf("AMQP 0.9 Channel: %s", err)
 	}
 
+	for _, eConf := range a.conf.ExchangesDeclare {
+		if err = amqpChan.ExchangeDeclare(
+			eConf.Exchange, // name of the exchange
+			eConf.Type,     // type
+			eConf.Durable,  // durable
+			false,          // delete when complete
+			false,          // internal
+			false,          // noWait
+			nil,            // arguments
+		); err != nil {
+			return fmt.Errorf("exchange Declare: %s", err)
+		}
+	}
+
 	if a.conf.QueueDeclare.Enabled {
+		args := amqp.Table{}
+		for k, v := range a.conf.QueueDeclare.Arguments {
+			args[k] = v
+		}
+		if a.conf.QueueDeclare.Type == "quorum" {
+			args["x-queue-type"] = "quorum"
+		}
 		if _, err = amqpChan.QueueDeclare(
 			a.conf.Queue,                // name of the queue
 			a.conf.QueueDeclare.Durable, // durable
 			false,                       // delete when unused
 			false,                       // exclusive
 			false,                       // noWait
-			nil,                         // arguments
+			args,                        // arguments
 		); err != nil {
 			return fmt.Errorf("queue Declare: %s", err)
 		}
@@ -233,6 +344,18 @@ func (a *amqp09Reader) ConnectWithContext(ctx context.Context) (err error) {
 		return fmt.Errorf("queue Consume: %s", err)
 	}
 
+	if a.conf.DeadLetter.Exchange != "" {
+		// Rejected messages are published on a separate channel in confirm
+		// mode so that confirmations are not mixed with consumed messages.
+		if a.deadLetterChan, err = conn.Channel(); err != nil {
+			return fmt.Errorf("AMQP 0.9 dead letter Channel: %s", err)
+		}
+		if err = a.deadLetterChan.Confirm(false); err != nil {
+			return fmt.Errorf("dead letter channel could not be put into confirm mode: %s", err)
+		}
+		a.deadLetterConfirms = a.deadLetterChan.NotifyPublish(make(chan amqp.Confirmation, 1))
+	}
+
 	a.conn = conn
 	a.amqpChan = amqpChan
 	a.consumerChan = consumerChan
@@ -252,6 +375,8 @@ func (a *amqp09Reader) disconnect() error {
 		}
 		a.amqpChan = nil
 	}
+	a.deadLetterChan = nil
+	a.deadLetterConfirms = nil
 	if a.conn != nil {
 		if err := a.conn.Close(); err != nil {
 			a.log.Errorf("Failed to close connection cleanly: %v\n", err)
@@ -325,14 +450,23 @@ func (a *amqp09Reader) ReadWithContext(ctx context.Context) (*message.Batch, inp
 
 	msg := message.QuickBatch(nil)
 	addPart := func(data amqp.Delivery) {
-		part := message.NewPart(data.Body)
+		body, contentEncoding := data.Body, data.ContentEncoding
+		if a.conf.AutoDecompress {
+			decoded, ok, err := compression.Decompress(contentEncoding, body)
+			if err != nil {
+				a.log.Warnf("Failed to decompress message with content encoding %v: %v\n", contentEncoding, err)
+			} else if ok {
+				body, contentEncoding = decoded, ""
+			}
+		}
+		part := message.NewPart(body)
 
 		for k, v := range data.Headers {
 			amqpSetMetadata(part, k, v)
 		}
 
 		amqpSetMetadata(part, "amqp_content_type", data.ContentType)
-		amqpSetMetadata(part, "amqp_content_encoding", data.ContentEncoding)
+		amqpSetMetadata(part, "amqp_content_encoding", contentEncoding)
 
 		if data.DeliveryMode != 0 {
 			amqpSetMetadata(part, "amqp_delivery_mode", data.DeliveryMode)
@@ -367,7 +501,7 @@ func (a *amqp09Reader) ReadWithContext(ctx context.Context) (*message.Batch, inp
 			return nil, nil, component.ErrNotConnected
 		}
 		addPart(data)
-		return msg, func(actx context.Context, res error) error {
+		ackFn := func(actx context.Context, res error) error {
 			if a.conf.AutoAck {
 				return nil
 			}
@@ -375,18 +509,154 @@ func (a *amqp09Reader) ReadWithContext(ctx context.Context) (*message.Batch, inp
 				errStr := res.Error()
 				for _, p := range a.nackRejectPattens {
 					if p.MatchString(errStr) {
-						return data.Nack(false, false)
+						return a.reject(data)
 					}
 				}
-				return data.Nack(false, true)
+				return a.nackPolicy.Nack(actx, amqpDeliveryAttempts(data), func() error {
+					return data.Nack(false, true)
+				}, func() error {
+					return a.reject(data)
+				})
 			}
 			return data.Ack(false)
-		}, nil
+		}
+		if data.ReplyTo == "" {
+			return msg, ackFn, nil
+		}
+		return msg, transaction.WithReplies(msg, func(ctx context.Context, res *message.Batch) error {
+			return a.reply(data, res)
+		}, ackFn), nil
 	case <-ctx.Done():
 	}
 	return nil, nil, component.ErrTimeout
 }
 
+// reply publishes response messages to the queue named by the reply_to
+// property of a request via the default exchange, with the correlation ID of the
+// request.
+func (a *amqp09Reader) reply(data amqp.Delivery, res *message.Batch) error {
+	a.m.RLock()
+	amqpChan := a.amqpChan
+	a.m.RUnlock()
+
+	if amqpChan == nil {
+		return component.ErrNotConnected
+	}
+
+	return res.Iter(func(i int, p *message.Part) error {
+		headers := amqp.Table{}
+		_ = p.MetaIter(func(k, v string) error {
+			if !strings.HasPrefix(k, "amqp_") {
+				headers[k] = v
+			}
+			return nil
+		})
+		return amqpChan.Publish(
+			"",           // publish to the default exchange
+			data.ReplyTo, // routing to the reply queue
+			false,        // mandatory
+			false,        // immediate
+			amqp.Publishing{
+				Headers:       headers,
+				ContentType:   p.MetaGet("amqp_content_type"),
+				CorrelationId: data.CorrelationId,
+				Body:          p.Get(),
+			},
+		)
+	})
+}
+
+// reject a message, publishing it to the dead letter exchange first when one is
+// configured. When the publish is not confirmed the message is requeued rather
+// than rejected so that it isn't lost.
+func (a *amqp09Reader) reject(data amqp.Delivery) error {
+	if a.conf.DeadLetter.Exchange == "" {
+		return data.Nack(false, false)
+	}
+	if err := a.publishDeadLetter(data); err != nil {
+		a.log.Errorf("Failed to publish rejected message to dead letter exchange, requeuing: %v\n", err)
+		return data.Nack(false, true)
+	}
+	return data.Ack(false)
+}
+
+func (a *amqp09Reader) publishDeadLetter(data amqp.Delivery) error {
+	a.m.RLock()
+	dlChan, dlConfirms := a.deadLetterChan, a.deadLetterConfirms
+	a.m.RUnlock()
+
+	if dlChan == nil {
+		return component.ErrNotConnected
+	}
+
+	// Publishes are serialised so that each confirmation can be attributed to
+	// the message that was published.
+	a.deadLetterMut.Lock()
+	defer a.deadLetterMut.Unlock()
+
+	key := a.conf.DeadLetter.RoutingKey
+	if key == "" {
+		key = data.RoutingKey
+	}
+	if err := dlChan.Publish(
+		a.conf.DeadLetter.Exchange, // publish to an exchange
+		key,                        // routing to 0 or more queues
+		false,                      // mandatory
+		false,                      // immediate
+		amqp.Publishing{
+			Headers:         data.Headers,
+			ContentType:     data.ContentType,
+			ContentEncoding: data.ContentEncoding,
+			DeliveryMode:    data.DeliveryMode,
+			Priority:        data.Priority,
+			CorrelationId:   data.CorrelationId,
+			ReplyTo:         data.ReplyTo,
+			Expiration:      data.Expiration,
+			MessageId:       data.MessageId,
+			Timestamp:       data.Timestamp,
+			Type:            data.Type,
+			UserId:          data.UserId,
+			AppId:           data.AppId,
+			Body:            data.Body,
+		},
+	); err != nil {
+		return err
+	}
+
+	confirm, open := <-dlConfirms
+	if !open {
+		return component.ErrNotConnected
+	}
+	if !confirm.Ack {
+		return component.ErrNoAck
+	}
+	return nil
+}
+
+// amqpDeliveryAttempts returns the number of times a message has been delivered
+// according to the x-delivery-count header, which is set by quorum queues, or
+// zero if unknown.
+func amqpDeliveryAttempts(data amqp.Delivery) int {
+	var count int64
+	switch v := data.Headers["x-delivery-count"].(type) {
+	case int64:
+		count = v
+	case int32:
+		count = int64(v)
+	case int16:
+		count = int64(v)
+	case int:
+		count = int64(v)
+	default:
+		if !data.Redelivered {
+			return 1
+		}
+		return 0
+	}
+	// The header counts prior deliveries and is absent on the first.
+	return int(count) + 1
+}
+
 // CloseAsync shuts down the AMQP09 input and stops processing requests.
 func (a *amqp09Reader) CloseAsync() {
 	_ = a.disconnect()
@@ -18,6 +18,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/component/output/processors"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -73,6 +74,7 @@ The fields 'key' and 'type' can be dynamically set using function interpolations
 			docs.FieldString("type", "The type property to set for each message.").IsInterpolated().HasDefault(""),
 			docs.FieldString("content_type", "The content type attribute to set for each message.").IsInterpolated().Advanced().HasDefault("application/octet-stream"),
 			docs.FieldString("content_encoding", "The content encoding attribute to set for each message.").IsInterpolated().Advanced().HasDefault(""),
+			compression.FieldSpec("content encoding", "`amqp_0_9` input"),
 			docs.FieldObject("metadata", "Specify criteria for which metadata values are attached to messages as headers.").WithChildren(metadata.ExcludeFilterFields()...).HasDefault(map[string]interface{}{}),
 			docs.FieldString("priority", "Set the priority of each message with a dynamic interpolated expression.", "0", `${! meta("amqp_priority") }`, `${! json("doc.priority") }`).IsInterpolated().Advanced().HasDefault(""),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput.").HasDefault(64),
@@ -110,6 +112,7 @@ type amqp09Writer struct {
 	returnChan  <-chan amqp.Return
 
 	deliveryMode uint8
+	compressor   *compression.Compressor
 
 	connLock sync.RWMutex
 }
@@ -139,6 +142,9 @@ func newAMQP09Writer(mgr bundle.NewManagement, conf output.AMQPConfig, log log.M
 	if a.priority, err = mgr.BloblEnvironment().NewField(conf.Priority); err != nil {
 		return nil, fmt.Errorf("failed to parse priority property expression: %w", err)
 	}
+	if a.compressor, err = conf.PayloadCompression.Compressor(); err != nil {
+		return nil, err
+	}
 	if conf.Persistent {
 		a.deliveryMode = amqp.Persistent
 	}
@@ -261,7 +267,15 @@ func (a *amqp09Writer) WriteWithContext(ctx context.Context, msg *message.Batch)
 			return nil
 		})
 
-		err := amqpChan.Publish(
+		body, compressedEncoding, err := a.compressor.Compress(p.Get())
+		if err != nil {
+			return fmt.Errorf("failed to compress message: %w", err)
+		}
+		if compressedEncoding != "" {
+			contentEncoding = compressedEncoding
+		}
+
+		err = amqpChan.Publish(
 			a.conf.Exchange,  // publish to an exchange
 			bindingKey,       // routing to 0 or more queues
 			a.conf.Mandatory, // mandatory
@@ -270,7 +284,7 @@ func (a *amqp09Writer) WriteWithContext(ctx context.Context, msg *message.Batch)
 				Headers:         headers,
 				ContentType:     contentType,
 				ContentEncoding: contentEncoding,
-				Body:            p.Get(),
+				Body:            body,
 				DeliveryMode:    a.deliveryMode, // 1=non-persistent, 2=persistent
 				Priority:        priority,       // 0-9
 				Type:            msgType,
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
@@ -59,6 +60,8 @@ If your notification events are being routed to SQS via an SNS topic then the ev
 
 When using SQS please make sure you have sensible values for ` + "`sqs.max_messages`" + ` and also the visibility timeout of the queue itself. When Benthos consumes an S3 object the SQS message that triggered it is not deleted until the S3 object has been sent onwards. This ensures at-least-once crash resiliency, but also means that if the S3 object takes longer to process than the visibility timeout of your queue then the same objects might be processed multiple times.
 
+In order to avoid this when processing large objects you can set ` + "`sqs.visibility_timeout`" + `, in which case the visibility timeout of each SQS message is set to that period when it is received, and is then extended in the background at half of that period until all of the objects referenced by the message have been acknowledged, at which point the message is deleted.
+
 ## Downloading Large Files
 
 When downloading large files it's often necessary to process it in streamed parts in order to avoid loading the entire file in memory at a given time. In order to do this a ` + "[`codec`](#codec)" + ` can be specified that determines how to break the input into smaller individual messages.
@@ -101,6 +104,11 @@ You can access these metadata fields using [function interpolation](/docs/config
 					"10s", "5m",
 				).Advanced(),
 				docs.FieldInt("max_messages", "The maximum number of SQS messages to consume from each request.").Advanced(),
+				docs.FieldString(
+					"visibility_timeout",
+					"An optional visibility timeout to set on SQS messages when they are received, which is extended periodically until the objects referenced by a message have been processed. By default the visibility timeout of the queue is used and is not extended.",
+					"30s", "5m",
+				).Advanced().AtVersion("4.2.0"),
 			),
 		).ChildDefaultAndTypesFromStruct(input.NewAWSS3Config()),
 		Categories: []string{
@@ -247,9 +255,14 @@ type sqsTargetReader struct {
 	sqs  *sqs.SQS
 	s3   *s3.S3
 
+	visibilityTimeout time.Duration
+
 	nextRequest time.Time
 
 	pending []*s3ObjectTarget
+
+	closeCtx  context.Context
+	closeFunc func()
 }
 
 func newSQSTargetReader(
@@ -257,8 +270,18 @@ func newSQSTargetReader(
 	log log.Modular,
 	s3 *s3.S3,
 	sqs *sqs.SQS,
+	visibilityTimeout time.Duration,
 ) *sqsTargetReader {
-	return &sqsTargetReader{conf, log, sqs, s3, time.Time{}, nil}
+	closeCtx, closeFunc := context.WithCancel(context.Background())
+	return &sqsTargetReader{
+		conf:              conf,
+		log:               log,
+		sqs:               sqs,
+		s3:                s3,
+		visibilityTimeout: visibilityTimeout,
+		closeCtx:          closeCtx,
+		closeFunc:         closeFunc,
+	}
 }
 
 func (s *sqsTargetReader) Pop(ctx context.Context) (*s3ObjectTarget, error) {
@@ -299,6 +322,7 @@ func (s *sqsTargetReader) Close(ctx context.Context) error {
 			err = aerr
 		}
 	}
+	s.closeFunc()
 	return err
 }
 
@@ -381,13 +405,17 @@ func (s *sqsTargetReader) readSQSEvents(ctx context.Context) ([]*s3ObjectTarget,
 		})
 	}
 
-	output, err := s.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+	receiveInput := &sqs.ReceiveMessageInput{
 		QueueUrl:            aws.String(s.conf.SQS.URL),
 		MaxNumberOfMessages: aws.Int64(s.conf.SQS.MaxMessages),
 		AttributeNames: []*string{
 			aws.String("SentTimestamp"),
 		},
-	})
+	}
+	if s.visibilityTimeout > 0 {
+		receiveInput.VisibilityTimeout = aws.Int64(visibilitySeconds(s.visibilityTimeout))
+	}
+	output, err := s.sqs.ReceiveMessageWithContext(ctx, receiveInput)
 	if err != nil {
 		return nil, err
 	}
@@ -422,6 +450,7 @@ func (s *sqsTargetReader) readSQSEvents(ctx context.Context) ([]*s3ObjectTarget,
 			continue
 		}
 
+		stopExtension := s.extendVisibility(sqsMsg)
 		pendingAcks := int32(len(objects))
 		var nackOnce sync.Once
 		for _, object := range objects {
@@ -435,6 +464,7 @@ func (s *sqsTargetReader) readSQSEvents(ctx context.Context) ([]*s3ObjectTarget,
 							nackOnce.Do(func() {
 								// Prevent future acks from triggering a delete.
 								atomic.StoreInt32(&pendingAcks, -1)
+								stopExtension()
 
 								s.log.Debugf("Pushing SQS notification back into the queue due to error: %v\n", err)
 
@@ -448,6 +478,7 @@ func (s *sqsTargetReader) readSQSEvents(ctx context.Context) ([]*s3ObjectTarget,
 						} else {
 							ackOnce.Do(func() {
 								if atomic.AddInt32(&pendingAcks, -1) == 0 {
+									stopExtension()
 									aerr = s.ackSQSMessage(ctx, sqsMsg)
 								}
 							})
@@ -478,6 +509,53 @@ func (s *sqsTargetReader) readSQSEvents(ctx context.Context) ([]*s3ObjectTarget,
 	return pendingObjects, nil
 }
 
+// visibilitySeconds converts a visibility timeout to the whole seconds
+// expected by SQS, rounding up.
+func visibilitySeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// extendVisibility extends the visibility timeout of an SQS message at half of
+// the timeout until the returned func is called, which blocks until extension
+// has stopped. When no visibility timeout is configured the message is left
+// with the visibility timeout of the queue.
+func (s *sqsTargetReader) extendVisibility(msg *sqs.Message) func() {
+	if s.visibilityTimeout <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(s.closeCtx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.visibilityTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			_, err := s.sqs.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String(s.conf.SQS.URL),
+				ReceiptHandle:     msg.ReceiptHandle,
+				VisibilityTimeout: aws.Int64(visibilitySeconds(s.visibilityTimeout)),
+			})
+			if err != nil && ctx.Err() == nil {
+				s.log.Warnf("Failed to extend the visibility timeout of SQS message %v: %v\n", aws.StringValue(msg.MessageId), err)
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
 func (s *sqsTargetReader) nackSQSMessage(ctx context.Context, msg *sqs.Message) error {
 	_, err := s.sqs.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
 		QueueUrl:          aws.String(s.conf.SQS.URL),
@@ -509,7 +587,8 @@ type awsS3Reader struct {
 	s3      *s3.S3
 	sqs     *sqs.SQS
 
-	gracePeriod time.Duration
+	gracePeriod       time.Duration
+	visibilityTimeout time.Duration
 
 	objectMut sync.Mutex
 	object    *s3PendingObject
@@ -545,12 +624,20 @@ func newAmazonS3Reader(conf input.AWSS3Config, nm bundle.NewManagement) (*awsS3R
 			return nil, fmt.Errorf("failed to parse grace period: %w", err)
 		}
 	}
+	if len(conf.SQS.VisibilityTimeout) > 0 {
+		if s.visibilityTimeout, err = time.ParseDuration(conf.SQS.VisibilityTimeout); err != nil {
+			return nil, fmt.Errorf("failed to parse visibility timeout: %w", err)
+		}
+		if s.visibilityTimeout < time.Second || s.visibilityTimeout > time.Hour*12 {
+			return nil, fmt.Errorf("visibility timeout must be between 1s and 12h, got %v", s.visibilityTimeout)
+		}
+	}
 	return s, nil
 }
 
 func (a *awsS3Reader) getTargetReader(ctx context.Context) (s3ObjectTargetReader, error) {
 	if a.sqs != nil {
-		return newSQSTargetReader(a.conf, a.log, a.s3, a.sqs), nil
+		return newSQSTargetReader(a.conf, a.log, a.s3, a.sqs, a.visibilityTimeout), nil
 	}
 	return newStaticTargetReader(ctx, a.conf, a.log, a.s3)
 }
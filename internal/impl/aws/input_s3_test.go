@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestS3InputVisibilityTimeout(t *testing.T) {
+	conf := input.NewAWSS3Config()
+	conf.SQS.URL = "http://localhost:4566/000000000000/foo"
+
+	r, err := newAmazonS3Reader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), r.visibilityTimeout)
+
+	conf.SQS.VisibilityTimeout = "90s"
+	r, err = newAmazonS3Reader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, time.Second*90, r.visibilityTimeout)
+
+	for _, v := range []string{"nope", "500ms", "13h"} {
+		conf.SQS.VisibilityTimeout = v
+		_, err = newAmazonS3Reader(conf, mock.NewManager())
+		require.Error(t, err, v)
+	}
+}
+
+func TestVisibilitySeconds(t *testing.T) {
+	assert.Equal(t, int64(30), visibilitySeconds(time.Second*30))
+	assert.Equal(t, int64(2), visibilitySeconds(time.Millisecond*1500))
+}
@@ -2,6 +2,8 @@ package aws
 
 import (
 	"context"
+	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -29,7 +31,11 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
-		return input.NewAsyncReader("aws_sqs", false, r, nm.Logger(), nm.Metrics())
+		ackTimeout, err := input.AckTimeoutOpt(conf.AWSSQS.AckTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return input.NewAsyncReader("aws_sqs", false, r, nm.Logger(), nm.Metrics(), ackTimeout)
 	}), docs.ComponentSpec{
 		Name:   "aws_sqs",
 		Status: docs.StatusStable,
@@ -55,12 +61,18 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### Nack Policy
+
+When a ` + "`nack_policy.requeue_delay`" + ` is set the visibility timeout of rejected messages is set to the delay (rounded up to the nearest second) rather than zero. Messages that reach ` + "`nack_policy.max_attempts`" + `, according to their approximate receive count, are not made visible again by Benthos and instead become visible once their visibility timeout expires, at which point the redrive policy of the queue is able to move them to a dead letter queue.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("url", "The SQS URL to consume from."),
 			docs.FieldBool("delete_message", "Whether to delete the consumed message once it is acked. Disabling allows you to handle the deletion using a different mechanism.").Advanced(),
 			docs.FieldBool("reset_visibility", "Whether to set the visibility timeout of the consumed message to zero once it is nacked. Disabling honors the preset visibility timeout specified for the queue.").AtVersion("3.58.0").Advanced(),
 			docs.FieldInt("max_number_of_messages", "The maximum number of messages to return on one poll. Valid values: 1 to 10.").Advanced(),
+			input.NackPolicyFieldSpec(),
+			input.AckTimeoutFieldSpec(),
 		).WithChildren(sess.FieldSpecs()...).ChildDefaultAndTypesFromStruct(input.NewAWSSQSConfig()),
 		Categories: []string{
 			"Services",
@@ -85,11 +97,20 @@ type awsSQSReader struct {
 	nackMessagesChan chan sqsMessageHandle
 	closeSignal      *shutdown.Signaller
 
+	nackPolicy     input.NackPolicy
+	nackVisibility int64
+
 	log log.Modular
 }
 
 func newAWSSQSReader(conf input.AWSSQSConfig, log log.Modular) (*awsSQSReader, error) {
+	nackPolicy, err := conf.NackPolicy.Policy()
+	if err != nil {
+		return nil, err
+	}
 	return &awsSQSReader{
+		nackPolicy:       nackPolicy,
+		nackVisibility:   int64(math.Ceil(nackPolicy.RequeueDelay().Seconds())),
 		conf:             conf,
 		log:              log,
 		messagesChan:     make(chan *sqs.Message),
@@ -156,7 +177,7 @@ func (a *awsSQSReader) ackLoop(wg *sync.WaitGroup) {
 
 		ctx, done := a.closeSignal.CloseNowCtx(context.Background())
 		defer done()
-		if err := a.resetMessages(ctx, tmpNacks...); err != nil {
+		if err := a.resetMessages(ctx, a.nackVisibility, tmpNacks...); err != nil {
 			a.log.Errorf("Failed to reset the visibility timeout of messages: %v", err)
 		}
 	}
@@ -207,7 +228,7 @@ func (a *awsSQSReader) readLoop(wg *sync.WaitGroup) {
 			}
 			ctx, done := a.closeSignal.CloseNowCtx(context.Background())
 			defer done()
-			if err := a.resetMessages(ctx, tmpNacks...); err != nil {
+			if err := a.resetMessages(ctx, 0, tmpNacks...); err != nil {
 				a.log.Errorf("Failed to reset visibility timeout for pending messages: %v", err)
 			}
 		}
@@ -292,7 +313,7 @@ func (a *awsSQSReader) deleteMessages(ctx context.Context, msgs ...sqsMessageHan
 	return nil
 }
 
-func (a *awsSQSReader) resetMessages(ctx context.Context, msgs ...sqsMessageHandle) error {
+func (a *awsSQSReader) resetMessages(ctx context.Context, visibility int64, msgs ...sqsMessageHandle) error {
 	if !a.conf.ResetVisibility {
 		return nil
 	}
@@ -307,7 +328,7 @@ func (a *awsSQSReader) resetMessages(ctx context.Context, msgs ...sqsMessageHand
 			input.Entries = append(input.Entries, &sqs.ChangeMessageVisibilityBatchRequestEntry{
 				Id:                aws.String(msg.id),
 				ReceiptHandle:     aws.String(msg.receiptHandle),
-				VisibilityTimeout: aws.Int64(0),
+				VisibilityTimeout: aws.Int64(visibility),
 			})
 			if len(input.Entries) == a.conf.MaxNumberOfMessages {
 				break
@@ -374,6 +395,11 @@ func (a *awsSQSReader) ReadWithContext(ctx context.Context) (*message.Batch, inp
 	if next.ReceiptHandle != nil {
 		mHandle.receiptHandle = *next.ReceiptHandle
 	}
+
+	var receiveCount int
+	if rCountStr := next.Attributes["ApproximateReceiveCount"]; rCountStr != nil {
+		receiveCount, _ = strconv.Atoi(*rCountStr)
+	}
 	return msg, func(rctx context.Context, res error) error {
 		if mHandle.receiptHandle == "" {
 			return nil
@@ -393,11 +419,17 @@ func (a *awsSQSReader) ReadWithContext(ctx context.Context) (*message.Batch, inp
 			return nil
 		}
 
+		if a.nackPolicy.ShouldReject(receiveCount) {
+			// Leave the message invisible so that the redrive policy of the
+			// queue can dead letter it.
+			return nil
+		}
+
 		select {
 		case <-rctx.Done():
 			return rctx.Err()
 		case <-a.closeSignal.CloseAtLeisureChan():
-			return a.resetMessages(rctx, mHandle)
+			return a.resetMessages(rctx, a.nackVisibility, mHandle)
 		case a.nackMessagesChan <- mHandle:
 		}
 		return nil
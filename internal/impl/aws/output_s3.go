@@ -11,6 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
@@ -25,6 +26,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	"github.com/benthosdev/benthos/v4/internal/metadata"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
 )
 
 func init() {
@@ -114,7 +116,13 @@ output:
       processors:
         - archive:
             format: json_array
-`+"```"+``),
+`+"```"+`
+
+### Multipart Uploads
+
+When `+"`multipart.enabled`"+` is set messages are not uploaded as their own objects, instead the contents of each message are appended as they are to a long-lived [multipart upload](https://docs.aws.amazon.com/AmazonS3/latest/userguide/mpuoverview.html) of the object at its `+"`path`"+`, which allows very large objects to be aggregated from a stream without holding them in memory. Data is buffered until it reaches `+"`multipart.part_size`"+`, at which point it is uploaded as a part, and an upload is completed once it reaches `+"`multipart.max_size`"+` or once it is older than `+"`multipart.max_age`"+`, after which further messages with the same path start a new upload. The headers, metadata and tags of an object are taken from the first message written to it.
+
+Messages are acknowledged once they have been appended to an upload, and therefore any data that is buffered or belongs to uploads that are not completed when Benthos is terminated abruptly is lost. Open uploads are completed when Benthos shuts down gracefully, and it is recommended to configure a [lifecycle rule](https://docs.aws.amazon.com/AmazonS3/latest/userguide/mpu-abort-incomplete-mpu-lifecycle-config.html) on the bucket that aborts incomplete multipart uploads.`),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("bucket", "The bucket to upload messages to."),
 			docs.FieldString(
@@ -143,6 +151,12 @@ output:
 			docs.FieldString("kms_key_id", "An optional server side encryption key.").Advanced(),
 			docs.FieldString("server_side_encryption", "An optional server side encryption algorithm.").AtVersion("3.63.0").Advanced(),
 			docs.FieldBool("force_path_style_urls", "Forces the client API to use path style URLs, which helps when connecting to custom endpoints.").Advanced(),
+			docs.FieldObject("multipart", "Options for [streaming messages](#multipart-uploads) into long-lived multipart uploads rather than uploading each message as an object.").WithChildren(
+				docs.FieldBool("enabled", "Whether to append messages to multipart uploads."),
+				docs.FieldString("part_size", "The size of the data to buffer for an upload before it is uploaded as a part, which must be at least 5MiB.", "5MiB", "64MiB"),
+				docs.FieldString("max_size", "The size at which an upload is completed.", "1GiB", "50GiB"),
+				docs.FieldString("max_age", "The maximum period after an upload was started before it is completed. Set to an empty string in order to only complete uploads by size.", "1h", "10m"),
+			).Advanced().AtVersion("4.2.0"),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 			docs.FieldString("timeout", "The maximum period to wait on an upload before abandoning it and reattempting.").Advanced(),
 			policy.FieldSpec(),
@@ -180,6 +194,9 @@ type amazonS3Writer struct {
 	uploader *s3manager.Uploader
 	timeout  time.Duration
 
+	multipart *s3MultipartUploads
+	shutSig   *shutdown.Signaller
+
 	log log.Modular
 }
 
@@ -195,6 +212,7 @@ func newAmazonS3Writer(conf output.AmazonS3Config, mgr bundle.NewManagement) (*a
 		conf:    conf,
 		log:     mgr.Logger(),
 		timeout: timeout,
+		shutSig: shutdown.NewSignaller(),
 	}
 	var err error
 	if a.path, err = mgr.BloblEnvironment().NewField(conf.Path); err != nil {
@@ -241,6 +259,16 @@ func newAmazonS3Writer(conf output.AmazonS3Config, mgr bundle.NewManagement) (*a
 		return a.tags[i].key < a.tags[j].key
 	})
 
+	if conf.Multipart.Enabled {
+		settings, err := s3MultipartSettingsFromConfig(conf.Multipart)
+		if err != nil {
+			return nil, err
+		}
+		a.multipart = newS3MultipartUploads(settings, conf.Bucket, a.log)
+		go a.loopMultipart(a.multipart)
+	} else {
+		a.shutSig.ShutdownComplete()
+	}
 	return a, nil
 }
 
@@ -258,6 +286,9 @@ func (a *amazonS3Writer) ConnectWithContext(ctx context.Context) error {
 
 	a.session = sess
 	a.uploader = s3manager.NewUploader(sess)
+	if a.multipart != nil {
+		a.multipart.setClient(s3.New(sess))
+	}
 
 	a.log.Infof("Uploading message parts as objects to Amazon S3 bucket: %v\n", a.conf.Bucket)
 	return nil
@@ -273,6 +304,10 @@ func (a *amazonS3Writer) WriteWithContext(wctx context.Context, msg *message.Bat
 	)
 	defer cancel()
 
+	if a.multipart != nil {
+		return a.writeMultipart(ctx, msg)
+	}
+
 	return output.IterateBatchedSend(msg, func(i int, p *message.Part) error {
 		metadata := map[string]*string{}
 		_ = a.metaFilter.Iter(p, func(k, v string) error {
@@ -344,8 +379,14 @@ func (a *amazonS3Writer) WriteWithContext(wctx context.Context, msg *message.Bat
 }
 
 func (a *amazonS3Writer) CloseAsync() {
+	a.shutSig.CloseAtLeisure()
 }
 
-func (a *amazonS3Writer) WaitForClose(time.Duration) error {
+func (a *amazonS3Writer) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-a.shutSig.HasClosedChan():
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
 	return nil
 }
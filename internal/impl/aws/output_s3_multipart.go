@@ -0,0 +1,347 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/dustin/go-humanize"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+const (
+	s3MinPartSize = 5 * 1024 * 1024
+	s3MaxParts    = 10000
+)
+
+type s3MultipartSettings struct {
+	partSize int
+	maxSize  int64
+	maxAge   time.Duration
+}
+
+func s3MultipartSettingsFromConfig(conf output.AmazonS3MultipartConfig) (s s3MultipartSettings, err error) {
+	partSize, err := humanize.ParseBytes(conf.PartSize)
+	if err != nil {
+		return s, fmt.Errorf("failed to parse multipart.part_size: %w", err)
+	}
+	if partSize < s3MinPartSize {
+		return s, fmt.Errorf("multipart.part_size must be at least 5MiB, got %v", conf.PartSize)
+	}
+	s.partSize = int(partSize)
+
+	maxSize, err := humanize.ParseBytes(conf.MaxSize)
+	if err != nil {
+		return s, fmt.Errorf("failed to parse multipart.max_size: %w", err)
+	}
+	if maxSize/partSize >= s3MaxParts {
+		return s, fmt.Errorf("multipart.max_size must not exceed %v parts of multipart.part_size", s3MaxParts)
+	}
+	s.maxSize = int64(maxSize)
+
+	if conf.MaxAge != "" {
+		if s.maxAge, err = time.ParseDuration(conf.MaxAge); err != nil {
+			return s, fmt.Errorf("failed to parse multipart.max_age: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// s3MultipartUpload is an object being written as a multipart upload, where
+// data is buffered until it reaches the part size before being uploaded as a
+// part.
+type s3MultipartUpload struct {
+	mut sync.Mutex
+
+	key      string
+	uploadID *string
+	started  time.Time
+	size     int64
+	buffer   bytes.Buffer
+	parts    []*s3.CompletedPart
+	complete bool
+}
+
+// s3MultipartUploads tracks the open multipart uploads of an output by the key
+// of their objects.
+type s3MultipartUploads struct {
+	settings s3MultipartSettings
+	bucket   string
+	client   *s3.S3
+	log      log.Modular
+
+	mut     sync.Mutex
+	uploads map[string]*s3MultipartUpload
+}
+
+func newS3MultipartUploads(settings s3MultipartSettings, bucket string, log log.Modular) *s3MultipartUploads {
+	return &s3MultipartUploads{
+		settings: settings,
+		bucket:   bucket,
+		log:      log,
+		uploads:  map[string]*s3MultipartUpload{},
+	}
+}
+
+// setClient sets the client used for uploads, which must be called before
+// anything is written.
+func (m *s3MultipartUploads) setClient(client *s3.S3) {
+	m.mut.Lock()
+	m.client = client
+	m.mut.Unlock()
+}
+
+// get returns the open upload of an object, creating it when it does not yet
+// exist with the properties of the message that is written to it first.
+func (m *s3MultipartUploads) get(ctx context.Context, key string, createInput func() *s3.CreateMultipartUploadInput) (*s3MultipartUpload, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	if u, exists := m.uploads[key]; exists {
+		return u, nil
+	}
+
+	res, err := m.client.CreateMultipartUploadWithContext(ctx, createInput())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	u := &s3MultipartUpload{
+		key:      key,
+		uploadID: res.UploadId,
+		started:  time.Now(),
+	}
+	m.uploads[key] = u
+	return u, nil
+}
+
+func (m *s3MultipartUploads) remove(u *s3MultipartUpload) {
+	m.mut.Lock()
+	if m.uploads[u.key] == u {
+		delete(m.uploads, u.key)
+	}
+	m.mut.Unlock()
+}
+
+// uploadPart uploads the buffered data of an upload as its next part. The
+// upload must be locked by the caller.
+func (m *s3MultipartUploads) uploadPart(ctx context.Context, u *s3MultipartUpload) error {
+	partNumber := aws.Int64(int64(len(u.parts) + 1))
+	res, err := m.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(m.bucket),
+		Key:        aws.String(u.key),
+		UploadId:   u.uploadID,
+		PartNumber: partNumber,
+		Body:       bytes.NewReader(u.buffer.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload part: %w", err)
+	}
+	u.parts = append(u.parts, &s3.CompletedPart{
+		ETag:       res.ETag,
+		PartNumber: partNumber,
+	})
+	u.buffer.Reset()
+	return nil
+}
+
+// completeLocked uploads any remaining data of an upload as its final part and
+// completes it. The upload must be locked by the caller.
+func (m *s3MultipartUploads) completeLocked(ctx context.Context, u *s3MultipartUpload) error {
+	if u.complete {
+		return nil
+	}
+	if u.buffer.Len() > 0 || len(u.parts) == 0 {
+		if err := m.uploadPart(ctx, u); err != nil {
+			return err
+		}
+	}
+	if _, err := m.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(m.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        u.uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: u.parts},
+	}); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	u.complete = true
+	m.remove(u)
+	m.log.Debugf("Completed multipart upload of object %v with %v parts\n", u.key, len(u.parts))
+	return nil
+}
+
+// write appends data to an upload, uploading a part once the buffered data
+// reaches the part size and completing the upload once it reaches the maximum
+// size. When the upload has been completed concurrently false is returned and
+// the data must be written to a new upload.
+func (m *s3MultipartUploads) write(ctx context.Context, u *s3MultipartUpload, data []byte) (bool, error) {
+	u.mut.Lock()
+	defer u.mut.Unlock()
+
+	if u.complete {
+		return false, nil
+	}
+
+	prevLen := u.buffer.Len()
+	_, _ = u.buffer.Write(data)
+	u.size += int64(len(data))
+
+	if u.buffer.Len() >= m.settings.partSize || u.size >= m.settings.maxSize {
+		if err := m.uploadPart(ctx, u); err != nil {
+			// Remove the data from the buffer so that it isn't duplicated
+			// when the message is reattempted.
+			u.buffer.Truncate(prevLen)
+			u.size -= int64(len(data))
+			return true, err
+		}
+	}
+	if u.size >= m.settings.maxSize {
+		// The data has already been uploaded as a part, and therefore failing
+		// to complete the upload is reattempted by subsequent writes or once
+		// the upload expires rather than rejecting the message.
+		if err := m.completeLocked(ctx, u); err != nil {
+			m.log.Errorf("Failed to complete multipart upload of object %v: %v\n", u.key, err)
+		}
+	}
+	return true, nil
+}
+
+// completeExpired completes all uploads that were started longer than the
+// maximum age ago, or all uploads when all is true.
+func (m *s3MultipartUploads) completeExpired(ctx context.Context, all bool) error {
+	m.mut.Lock()
+	var expired []*s3MultipartUpload
+	for _, u := range m.uploads {
+		if all || (m.settings.maxAge > 0 && time.Since(u.started) >= m.settings.maxAge) {
+			expired = append(expired, u)
+		}
+	}
+	m.mut.Unlock()
+
+	var errs []string
+	for _, u := range expired {
+		u.mut.Lock()
+		err := m.completeLocked(ctx, u)
+		u.mut.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", u.key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (a *amazonS3Writer) createMultipartInput(key string, i int, msg *message.Batch) *s3.CreateMultipartUploadInput {
+	p := msg.Get(i)
+
+	metadata := map[string]*string{}
+	_ = a.metaFilter.Iter(p, func(k, v string) error {
+		metadata[k] = aws.String(v)
+		return nil
+	})
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:       aws.String(a.conf.Bucket),
+		Key:          aws.String(key),
+		ContentType:  aws.String(a.contentType.String(i, msg)),
+		StorageClass: aws.String(a.storageClass.String(i, msg)),
+		Metadata:     metadata,
+	}
+	if ce := a.contentEncoding.String(i, msg); len(ce) > 0 {
+		input.ContentEncoding = aws.String(ce)
+	}
+	if ce := a.cacheControl.String(i, msg); len(ce) > 0 {
+		input.CacheControl = aws.String(ce)
+	}
+	if ce := a.contentDisposition.String(i, msg); len(ce) > 0 {
+		input.ContentDisposition = aws.String(ce)
+	}
+	if ce := a.contentLanguage.String(i, msg); len(ce) > 0 {
+		input.ContentLanguage = aws.String(ce)
+	}
+	if ce := a.websiteRedirectLocation.String(i, msg); len(ce) > 0 {
+		input.WebsiteRedirectLocation = aws.String(ce)
+	}
+
+	if len(a.tags) > 0 {
+		tags := make([]string, len(a.tags))
+		for j, pair := range a.tags {
+			tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(pair.value.String(i, msg))
+		}
+		input.Tagging = aws.String(strings.Join(tags, "&"))
+	}
+
+	if a.conf.KMSKeyID != "" {
+		input.ServerSideEncryption = aws.String("aws:kms")
+		input.SSEKMSKeyId = &a.conf.KMSKeyID
+	}
+	if a.conf.ServerSideEncryption != "" {
+		input.ServerSideEncryption = &a.conf.ServerSideEncryption
+	}
+	return input
+}
+
+func (a *amazonS3Writer) writeMultipart(ctx context.Context, msg *message.Batch) error {
+	return msg.Iter(func(i int, p *message.Part) error {
+		key := a.path.String(i, msg)
+		for {
+			u, err := a.multipart.get(ctx, key, func() *s3.CreateMultipartUploadInput {
+				return a.createMultipartInput(key, i, msg)
+			})
+			if err != nil {
+				return err
+			}
+			written, err := a.multipart.write(ctx, u, p.Get())
+			if err != nil {
+				return err
+			}
+			if written {
+				return nil
+			}
+		}
+	})
+}
+
+// loopMultipart completes uploads that exceed their maximum age until the
+// writer is closed, at which point all remaining uploads are completed.
+func (a *amazonS3Writer) loopMultipart(uploads *s3MultipartUploads) {
+	defer a.shutSig.ShutdownComplete()
+
+	period := uploads.settings.maxAge / 10
+	if period < time.Second {
+		period = time.Second
+	} else if period > time.Minute {
+		period = time.Minute
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uploads.completeExpired(context.Background(), false); err != nil {
+				a.log.Errorf("Failed to complete multipart uploads: %v\n", err)
+			}
+		case <-a.shutSig.CloseAtLeisureChan():
+			ctx, done := a.shutSig.CloseNowCtx(context.Background())
+			if err := uploads.completeExpired(ctx, true); err != nil {
+				a.log.Errorf("Failed to complete multipart uploads: %v\n", err)
+			}
+			done()
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestS3MultipartSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		partSize    string
+		maxSize     string
+		maxAge      string
+		expected    s3MultipartSettings
+		errContains string
+	}{
+		{
+			name:     "defaults",
+			partSize: "5MiB",
+			maxSize:  "1GiB",
+			maxAge:   "1h",
+			expected: s3MultipartSettings{
+				partSize: 5 * 1024 * 1024,
+				maxSize:  1024 * 1024 * 1024,
+				maxAge:   time.Hour,
+			},
+		},
+		{
+			name:     "no max age",
+			partSize: "10MiB",
+			maxSize:  "20GiB",
+			expected: s3MultipartSettings{
+				partSize: 10 * 1024 * 1024,
+				maxSize:  20 * 1024 * 1024 * 1024,
+			},
+		},
+		{
+			name:        "part size too small",
+			partSize:    "1MiB",
+			maxSize:     "1GiB",
+			errContains: "at least 5MiB",
+		},
+		{
+			name:        "too many parts",
+			partSize:    "5MiB",
+			maxSize:     "100GiB",
+			errContains: "must not exceed 10000 parts",
+		},
+		{
+			name:        "bad max age",
+			partSize:    "5MiB",
+			maxSize:     "1GiB",
+			maxAge:      "nope",
+			errContains: "max_age",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := output.NewAmazonS3MultipartConfig()
+			conf.PartSize = test.partSize
+			conf.MaxSize = test.maxSize
+			conf.MaxAge = test.maxAge
+
+			settings, err := s3MultipartSettingsFromConfig(conf)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, settings)
+		})
+	}
+}
+
+func TestS3MultipartClose(t *testing.T) {
+	conf := output.NewAmazonS3Config()
+	conf.Bucket = "foo"
+	conf.Multipart.Enabled = true
+
+	w, err := newAmazonS3Writer(conf, mock.NewManager())
+	require.NoError(t, err)
+	require.NotNil(t, w.multipart)
+
+	w.CloseAsync()
+	require.NoError(t, w.WaitForClose(time.Second))
+}
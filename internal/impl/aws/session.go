@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"errors"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
@@ -23,7 +25,7 @@ func sessionFields() []*service.ConfigField {
 			Advanced(),
 		service.NewObjectField("credentials",
 			service.NewStringField("profile").
-				Description("A profile from `~/.aws/credentials` to use.").
+				Description("A profile from `~/.aws/credentials` or `~/.aws/config` to use.").
 				Default(""),
 			service.NewStringField("id").
 				Description("The ID of credentials to use.").
@@ -42,64 +44,62 @@ func sessionFields() []*service.ConfigField {
 				Default("").Advanced(),
 			service.NewStringField("role_external_id").
 				Description("An external ID to provide when assuming a role.").
-				Default("").Advanced()).
+				Default("").Advanced(),
+			service.NewStringField("web_identity_token_file").
+				Description("The path of a web identity token file, such as a Kubernetes service account token, used to assume the `role` with web identity federation.").
+				Default("").Advanced().Version("4.2.0")).
 			Advanced().
 			Description("Optional manual configuration of AWS credentials to use. More information can be found [in this document](/docs/guides/cloud/aws)."),
 	}
 }
 
-func getSession(parsedConf *service.ParsedConfig, opts ...func(*aws.Config)) (*session.Session, error) {
-	awsConf := aws.NewConfig()
-
-	if region, _ := parsedConf.FieldString("region"); region != "" {
-		awsConf = awsConf.WithRegion(region)
-	}
-	if endpoint, _ := parsedConf.FieldString("endpoint"); endpoint != "" {
-		awsConf = awsConf.WithEndpoint(endpoint)
-	}
-	if profile, _ := parsedConf.FieldString("credentials", "profile"); profile != "" {
-		awsConf = awsConf.WithCredentials(credentials.NewSharedCredentials(
-			"", profile,
-		))
-	} else if id, _ := parsedConf.FieldString("credentials", "id"); id != "" {
-		secret, _ := parsedConf.FieldString("credentials", "secret")
-		token, _ := parsedConf.FieldString("credentials", "token")
-		awsConf = awsConf.WithCredentials(credentials.NewStaticCredentials(
-			id, secret, token,
-		))
+// sessionConfigFromParsed reads the session fields of a parsed config into a
+// Config.
+func sessionConfigFromParsed(parsedConf *service.ParsedConfig) (bsession.Config, error) {
+	c := bsession.NewConfig()
+
+	var err error
+	for _, f := range []struct {
+		path []string
+		dst  *string
+	}{
+		{[]string{"region"}, &c.Region},
+		{[]string{"endpoint"}, &c.Endpoint},
+		{[]string{"credentials", "profile"}, &c.Credentials.Profile},
+		{[]string{"credentials", "id"}, &c.Credentials.ID},
+		{[]string{"credentials", "secret"}, &c.Credentials.Secret},
+		{[]string{"credentials", "token"}, &c.Credentials.Token},
+		{[]string{"credentials", "role"}, &c.Credentials.Role},
+		{[]string{"credentials", "role_external_id"}, &c.Credentials.ExternalID},
+		{[]string{"credentials", "web_identity_token_file"}, &c.Credentials.WebIdentityTokenFile},
+	} {
+		if *f.dst, err = parsedConf.FieldString(f.path...); err != nil {
+			return c, err
+		}
 	}
-
-	for _, opt := range opts {
-		opt(awsConf)
+	if c.Credentials.UseEC2Creds, err = parsedConf.FieldBool("credentials", "from_ec2_role"); err != nil {
+		return c, err
 	}
+	return c, nil
+}
 
-	sess, err := session.NewSession(awsConf)
+func getSession(parsedConf *service.ParsedConfig, opts ...func(*aws.Config)) (*session.Session, error) {
+	c, err := sessionConfigFromParsed(parsedConf)
 	if err != nil {
 		return nil, err
 	}
-
-	if role, _ := parsedConf.FieldString("credentials", "role"); role != "" {
-		var opts []func(*stscreds.AssumeRoleProvider)
-		if externalID, _ := parsedConf.FieldString("credentials", "role_external_id"); externalID != "" {
-			opts = []func(*stscreds.AssumeRoleProvider){
-				func(p *stscreds.AssumeRoleProvider) {
-					p.ExternalID = &externalID
-				},
-			}
-		}
-		sess.Config = sess.Config.WithCredentials(
-			stscreds.NewCredentials(sess, role, opts...),
-		)
-	}
-
-	if useEC2, _ := parsedConf.FieldBool("from_ec2_role"); useEC2 {
-		sess.Config = sess.Config.WithCredentials(ec2rolecreds.NewCredentials(sess))
-	}
-
-	return sess, nil
+	return GetSessionFromConf(c, opts...)
 }
 
-// GetSessionFromConf attempts to create an AWS session based on Config.
+// GetSessionFromConf attempts to create an AWS session based on Config. This is
+// the single place where the credentials of all AWS components are resolved.
+//
+// When a profile is specified it is loaded from both the shared credentials
+// file and the shared config file, and therefore profiles that assume a role,
+// use web identity federation or a credential process can be configured once
+// and referenced by any number of components. Otherwise the shared config file
+// is only loaded when the AWS_SDK_LOAD_CONFIG environment variable is set, as
+// is the default of the SDK.
 func GetSessionFromConf(c bsession.Config, opts ...func(*aws.Config)) (*session.Session, error) {
 	awsConf := aws.NewConfig()
 	if len(c.Region) > 0 {
@@ -110,11 +110,7 @@ func GetSessionFromConf(c bsession.Config, opts ...func(*aws.Config)) (*session.
 		awsConf = awsConf.WithEndpoint(c.Endpoint)
 	}
 
-	if len(c.Credentials.Profile) > 0 {
-		awsConf = awsConf.WithCredentials(credentials.NewSharedCredentials(
-			"", c.Credentials.Profile,
-		))
-	} else if len(c.Credentials.ID) > 0 {
+	if len(c.Credentials.ID) > 0 && len(c.Credentials.Profile) == 0 {
 		awsConf = awsConf.WithCredentials(credentials.NewStaticCredentials(
 			c.Credentials.ID,
 			c.Credentials.Secret,
@@ -126,12 +122,28 @@ func GetSessionFromConf(c bsession.Config, opts ...func(*aws.Config)) (*session.
 		opt(awsConf)
 	}
 
-	sess, err := session.NewSession(awsConf)
+	sharedConfigState := session.SharedConfigStateFromEnv
+	if len(c.Credentials.Profile) > 0 {
+		sharedConfigState = session.SharedConfigEnable
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConf,
+		Profile:           c.Credentials.Profile,
+		SharedConfigState: sharedConfigState,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(c.Credentials.Role) > 0 {
+	if len(c.Credentials.WebIdentityTokenFile) > 0 {
+		if len(c.Credentials.Role) == 0 {
+			return nil, errors.New("a role must be specified in order to use a web identity token file")
+		}
+		sess.Config = sess.Config.WithCredentials(
+			stscreds.NewWebIdentityCredentials(sess, c.Credentials.Role, "", c.Credentials.WebIdentityTokenFile),
+		)
+	} else if len(c.Credentials.Role) > 0 {
 		var opts []func(*stscreds.AssumeRoleProvider)
 		if len(c.Credentials.ExternalID) > 0 {
 			opts = []func(*stscreds.AssumeRoleProvider){
@@ -10,13 +10,14 @@ func FieldSpecs() docs.FieldSpecs {
 		docs.FieldObject("credentials", "Optional manual configuration of AWS credentials to use. More information can be found [in this document](/docs/guides/cloud/aws).").
 			Advanced().
 			WithChildren(
-				docs.FieldString("profile", "A profile from `~/.aws/credentials` to use.").HasDefault(""),
+				docs.FieldString("profile", "A profile from `~/.aws/credentials` or `~/.aws/config` to use.").HasDefault(""),
 				docs.FieldString("id", "The ID of credentials to use.").HasDefault(""),
 				docs.FieldString("secret", "The secret for the credentials being used.").HasDefault(""),
 				docs.FieldString("token", "The token for the credentials being used, required when using short term credentials.").HasDefault(""),
 				docs.FieldBool("from_ec2_role", "Use the credentials of a host EC2 machine configured to assume [an IAM role associated with the instance](https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_use_switch-role-ec2.html).").HasDefault(false).AtVersion("4.2.0"),
 				docs.FieldString("role", "A role ARN to assume.").HasDefault(""),
 				docs.FieldString("role_external_id", "An external ID to provide when assuming a role.").HasDefault(""),
+				docs.FieldString("web_identity_token_file", "The path of a web identity token file, such as a Kubernetes service account token, used to assume the `role` with web identity federation.").HasDefault("").AtVersion("4.2.0"),
 			),
 	}
 }
@@ -2,13 +2,14 @@ package session
 
 // CredentialsConfig contains configuration params for AWS credentials.
 type CredentialsConfig struct {
-	Profile     string `json:"profile" yaml:"profile"`
-	ID          string `json:"id" yaml:"id"`
-	Secret      string `json:"secret" yaml:"secret"`
-	Token       string `json:"token" yaml:"token"`
-	UseEC2Creds bool   `json:"from_ec2_role" yaml:"from_ec2_role"`
-	Role        string `json:"role" yaml:"role"`
-	ExternalID  string `json:"role_external_id" yaml:"role_external_id"`
+	Profile              string `json:"profile" yaml:"profile"`
+	ID                   string `json:"id" yaml:"id"`
+	Secret               string `json:"secret" yaml:"secret"`
+	Token                string `json:"token" yaml:"token"`
+	UseEC2Creds          bool   `json:"from_ec2_role" yaml:"from_ec2_role"`
+	Role                 string `json:"role" yaml:"role"`
+	ExternalID           string `json:"role_external_id" yaml:"role_external_id"`
+	WebIdentityTokenFile string `json:"web_identity_token_file" yaml:"web_identity_token_file"`
 }
 
 // Config contains configuration fields for an AWS session. This config is
@@ -23,12 +24,13 @@ type Config struct {
 func NewConfig() Config {
 	return Config{
 		Credentials: CredentialsConfig{
-			Profile:    "",
-			ID:         "",
-			Secret:     "",
-			Token:      "",
-			Role:       "",
-			ExternalID: "",
+			Profile:              "",
+			ID:                   "",
+			Secret:               "",
+			Token:                "",
+			Role:                 "",
+			ExternalID:           "",
+			WebIdentityTokenFile: "",
 		},
 		Endpoint: "",
 		Region:   "",
@@ -0,0 +1,150 @@
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bsession "github.com/benthosdev/benthos/v4/internal/impl/aws/session"
+)
+
+// isolateSessionEnv prevents the environment of the host from leaking into the
+// sessions created by a test.
+func isolateSessionEnv(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for k, v := range map[string]string{
+		"AWS_CONFIG_FILE":             filepath.Join(dir, "config"),
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(dir, "credentials"),
+		"AWS_SDK_LOAD_CONFIG":         "",
+		"AWS_PROFILE":                 "",
+		"AWS_DEFAULT_PROFILE":         "",
+		"AWS_REGION":                  "",
+		"AWS_DEFAULT_REGION":          "",
+		"AWS_ACCESS_KEY_ID":           "",
+		"AWS_SECRET_ACCESS_KEY":       "",
+		"AWS_ROLE_ARN":                "",
+		"AWS_WEB_IDENTITY_TOKEN_FILE": "",
+	} {
+		t.Setenv(k, v)
+	}
+	return dir
+}
+
+func TestGetSessionSharedConfig(t *testing.T) {
+	dir := isolateSessionEnv(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config"), []byte(`
+[default]
+region = eu-west-2
+
+[profile foo]
+region = eu-west-1
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "credentials"), []byte(`
+[default]
+aws_access_key_id = default_id
+aws_secret_access_key = default_secret
+
+[foo]
+aws_access_key_id = foo_id
+aws_secret_access_key = foo_secret
+`), 0o644))
+
+	// Without a profile the shared config file is not loaded by default.
+	sess, err := GetSessionFromConf(bsession.NewConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "", aws.StringValue(sess.Config.Region))
+
+	// Selecting a profile loads it from the shared config file.
+	conf := bsession.NewConfig()
+	conf.Credentials.Profile = "foo"
+	sess, err = GetSessionFromConf(conf)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", aws.StringValue(sess.Config.Region))
+
+	creds, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "foo_id", creds.AccessKeyID)
+
+	// The SDK environment variable still enables the shared config file
+	// without a profile.
+	t.Setenv("AWS_SDK_LOAD_CONFIG", "1")
+	sess, err = GetSessionFromConf(bsession.NewConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-2", aws.StringValue(sess.Config.Region))
+}
+
+func TestGetSessionWebIdentityErrors(t *testing.T) {
+	dir := isolateSessionEnv(t)
+
+	conf := bsession.NewConfig()
+	conf.Region = "eu-west-1"
+	conf.Credentials.WebIdentityTokenFile = filepath.Join(dir, "token")
+
+	_, err := GetSessionFromConf(conf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a role must be specified")
+
+	conf.Credentials.Role = "arn:aws:iam::123456789012:role/foo"
+	sess, err := GetSessionFromConf(conf)
+	require.NoError(t, err)
+
+	// The token file does not exist, which is reported when credentials are
+	// resolved.
+	_, err = sess.Config.Credentials.Get()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to read file")
+}
+
+func TestGetSessionRoleErrors(t *testing.T) {
+	isolateSessionEnv(t)
+
+	var roleARN, externalID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		roleARN = r.PostForm.Get("RoleArn")
+		externalID = r.PostForm.Get("ExternalId")
+
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>AccessDenied</Code>
+    <Message>not authorized to assume role</Message>
+  </Error>
+  <RequestId>foo</RequestId>
+</ErrorResponse>`))
+	}))
+	defer server.Close()
+
+	conf := bsession.NewConfig()
+	conf.Region = "eu-west-1"
+	conf.Endpoint = server.URL
+	conf.Credentials.ID = "foo_id"
+	conf.Credentials.Secret = "foo_secret"
+	conf.Credentials.Role = "arn:aws:iam::123456789012:role/foo"
+	conf.Credentials.ExternalID = "bar_id"
+
+	sess, err := GetSessionFromConf(conf, func(c *aws.Config) {
+		c.MaxRetries = aws.Int(0)
+	})
+	require.NoError(t, err)
+
+	_, err = sess.Config.Credentials.Get()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+
+	assert.Equal(t, "arn:aws:iam::123456789012:role/foo", roleARN)
+	assert.Equal(t, "bar_id", externalID)
+}
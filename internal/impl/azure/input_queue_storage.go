@@ -2,14 +2,17 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-storage-queue-go/azqueue"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
@@ -40,10 +43,21 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 - queue_storage_insertion_time
 - queue_storage_queue_name
+- queue_storage_dequeue_count
 - queue_storage_message_lag (if 'track_properties' set to true)
 - All user defined queue metadata
 ` + "```" + `
 
+Messages are deleted from the queue once they are acknowledged, and messages that are rejected are made visible again so that they can be redelivered.
+
+### Visibility Renewal
+
+Messages become visible to other consumers again once ` + "`dequeue_visibility_timeout`" + ` has elapsed, which results in duplicate deliveries when processing takes longer than the timeout. When ` + "`renew_visibility`" + ` is set to ` + "`true`" + ` the visibility timeout of each message is extended periodically until it is acknowledged.
+
+### Poison Messages
+
+When ` + "`max_dequeue_count`" + ` is greater than zero messages that have been dequeued more times than the count are moved to a poison queue instead of being consumed, which is named after the source queue with the suffix ` + "`-poison`" + ` unless ` + "`poison_queue_name`" + ` is set. This prevents messages that repeatedly fail from being redelivered forever.
+
 Only one authentication method is required, ` + "`storage_connection_string`" + ` or ` + "`storage_account` and `storage_access_key`" + `. If both are set then the ` + "`storage_connection_string`" + ` is given priority.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString(
@@ -70,6 +84,9 @@ Only one authentication method is required, ` + "`storage_connection_string`" +
 			).AtVersion("3.45.0").Advanced(),
 			docs.FieldInt("max_in_flight", "The maximum number of unprocessed messages to fetch at a given time.").Advanced(),
 			docs.FieldBool("track_properties", "If set to `true` the queue is polled on each read request for information such as the queue message lag. These properties are added to consumed messages as metadata, but will also have a negative performance impact.").Advanced(),
+			docs.FieldBool("renew_visibility", "Whether to periodically extend the visibility timeout of messages until they are acknowledged.").Advanced().AtVersion("4.2.0"),
+			docs.FieldInt("max_dequeue_count", "The number of times a message can be dequeued before it is moved to a [poison queue](#poison-messages). Set to zero in order to disable poison message handling.").Advanced().AtVersion("4.2.0"),
+			docs.FieldString("poison_queue_name", "An optional name of the queue to move poison messages to. By default the name of the source queue is used with the suffix `-poison`.").Advanced().AtVersion("4.2.0"),
 		).ChildDefaultAndTypesFromStruct(input.NewAzureQueueStorageConfig()),
 		Categories: []string{
 			"Services",
@@ -115,6 +132,12 @@ func newAzureQueueStorage(conf input.AzureQueueStorageConfig, mgr bundle.NewMana
 			return nil, fmt.Errorf("unable to parse dequeue visibility timeout duration string: %w", err)
 		}
 	}
+	if conf.RenewVisibility && a.dequeueVisibilityTimeout <= 0 {
+		return nil, errors.New("a dequeue_visibility_timeout must be set in order to renew visibility")
+	}
+	if conf.MaxDequeueCount < 0 {
+		return nil, fmt.Errorf("max_dequeue_count must not be negative, got %v", conf.MaxDequeueCount)
+	}
 
 	return a, nil
 }
@@ -149,12 +172,19 @@ func (a *azureQueueStorage) ReadWithContext(ctx context.Context) (msg *message.B
 		props, _ := queueURL.GetProperties(ctx)
 		metadata := props.NewMetadata()
 		msg := message.QuickBatch(nil)
-		dqm := make([]*azqueue.DequeuedMessage, n)
+		dqm := make([]*azqueue.DequeuedMessage, 0, n)
 		for i := int32(0); i < n; i++ {
 			queueMsg := dequeue.Message(i)
+			if a.conf.MaxDequeueCount > 0 && queueMsg.DequeueCount > a.conf.MaxDequeueCount {
+				if err := a.movePoisonMessage(ctx, queueName, messageURL, queueMsg); err != nil {
+					a.log.Errorf("Failed to move poison message %v: %v", queueMsg.ID, err)
+				}
+				continue
+			}
 			part := message.NewPart([]byte(queueMsg.Text))
 			part.MetaSet("queue_storage_insertion_time", queueMsg.InsertionTime.Format(time.RFC3339))
 			part.MetaSet("queue_storage_queue_name", queueName)
+			part.MetaSet("queue_storage_dequeue_count", strconv.FormatInt(queueMsg.DequeueCount, 10))
 			if a.conf.TrackProperties {
 				msgLag := 0
 				if approxMsgCount >= n {
@@ -166,13 +196,32 @@ func (a *azureQueueStorage) ReadWithContext(ctx context.Context) (msg *message.B
 				part.MetaSet(k, v)
 			}
 			msg.Append(part)
-			dqm[i] = queueMsg
+			dqm = append(dqm, queueMsg)
+		}
+		if len(dqm) == 0 {
+			return nil, nil, component.ErrTimeout
+		}
+
+		receipts := newPopReceipts(dqm)
+		var stopRenewal func()
+		if a.conf.RenewVisibility {
+			stopRenewal = a.renewVisibility(messageURL, receipts)
 		}
 		return msg, func(ctx context.Context, res error) error {
-			for i := int32(0); i < n; i++ {
-				msgIDURL := messageURL.NewMessageIDURL(dqm[i].ID)
-				_, err = msgIDURL.Delete(ctx, dqm[i].PopReceipt)
-				if err != nil {
+			if stopRenewal != nil {
+				stopRenewal()
+			}
+			for i, m := range dqm {
+				msgIDURL := messageURL.NewMessageIDURL(m.ID)
+				if res != nil {
+					// Make rejected messages visible again so that they can be
+					// redelivered.
+					if _, err := msgIDURL.Update(ctx, receipts.get(i), 0, m.Text); err != nil {
+						return fmt.Errorf("error making message visible: %v", err)
+					}
+					continue
+				}
+				if _, err := msgIDURL.Delete(ctx, receipts.get(i)); err != nil {
 					return fmt.Errorf("error deleting message: %v", err)
 				}
 			}
@@ -182,6 +231,92 @@ func (a *azureQueueStorage) ReadWithContext(ctx context.Context) (msg *message.B
 	return nil, nil, nil
 }
 
+// movePoisonMessage moves a message that has been dequeued too many times to
+// the poison queue.
+func (a *azureQueueStorage) movePoisonMessage(ctx context.Context, queueName string, messageURL azqueue.MessagesURL, queueMsg *azqueue.DequeuedMessage) error {
+	poisonName := a.conf.PoisonQueueName
+	if poisonName == "" {
+		poisonName = queueName + "-poison"
+	}
+	poisonURL := a.serviceURL.NewQueueURL(poisonName)
+
+	_, err := poisonURL.NewMessagesURL().Enqueue(ctx, queueMsg.Text, 0, 0)
+	if cerr, ok := err.(azqueue.StorageError); ok && cerr.ServiceCode() == azqueue.ServiceCodeQueueNotFound {
+		if _, err = poisonURL.Create(ctx, azqueue.Metadata{}); err != nil {
+			return err
+		}
+		_, err = poisonURL.NewMessagesURL().Enqueue(ctx, queueMsg.Text, 0, 0)
+	}
+	if err != nil {
+		return err
+	}
+
+	a.log.Warnf("Moved message %v to poison queue %v after %v dequeues", queueMsg.ID, poisonName, queueMsg.DequeueCount)
+	_, err = messageURL.NewMessageIDURL(queueMsg.ID).Delete(ctx, queueMsg.PopReceipt)
+	return err
+}
+
+// popReceipts tracks the pop receipts of dequeued messages, which change each
+// time the visibility of a message is renewed.
+type popReceipts struct {
+	mut      sync.Mutex
+	messages []*azqueue.DequeuedMessage
+	receipts []azqueue.PopReceipt
+}
+
+func newPopReceipts(messages []*azqueue.DequeuedMessage) *popReceipts {
+	p := &popReceipts{
+		messages: messages,
+		receipts: make([]azqueue.PopReceipt, len(messages)),
+	}
+	for i, m := range messages {
+		p.receipts[i] = m.PopReceipt
+	}
+	return p
+}
+
+func (p *popReceipts) get(i int) azqueue.PopReceipt {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	return p.receipts[i]
+}
+
+// renewVisibility extends the visibility timeout of messages at half of the
+// timeout until the returned func is called, which blocks until renewal has
+// stopped.
+func (a *azureQueueStorage) renewVisibility(messageURL azqueue.MessagesURL, receipts *popReceipts) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(a.dequeueVisibilityTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			receipts.mut.Lock()
+			for i, m := range receipts.messages {
+				res, err := messageURL.NewMessageIDURL(m.ID).Update(ctx, receipts.receipts[i], a.dequeueVisibilityTimeout, m.Text)
+				if err != nil {
+					if ctx.Err() == nil {
+						a.log.Warnf("Failed to renew visibility of message %v: %v", m.ID, err)
+					}
+					continue
+				}
+				receipts.receipts[i] = res.PopReceipt
+			}
+			receipts.mut.Unlock()
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
 func (a *azureQueueStorage) CloseAsync() {
 }
 
@@ -0,0 +1,306 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func init() {
+	err := bundle.AllInputs.Add(processors.WrapConstructor(func(conf input.Config, nm bundle.NewManagement) (input.Streamed, error) {
+		r, err := newAzureTableStorage(conf.AzureTableStorage, nm, nm.Logger())
+		if err != nil {
+			return nil, err
+		}
+		return input.NewAsyncReader("azure_table_storage", false, r, nm.Logger(), nm.Metrics())
+	}), docs.ComponentSpec{
+		Name:    "azure_table_storage",
+		Status:  docs.StatusBeta,
+		Version: "4.2.0",
+		Summary: `
+Queries an Azure Storage Table, optionally with a filter, and creates a message for each entity.`,
+		Description: `
+Queries an Azure Storage Table, optionally with a filter, and creates a message for each entity. Each message is a JSON object containing the properties of an entity, including its ` + "`PartitionKey`" + ` and ` + "`RowKey`" + `. Once all entities of the query have been consumed the input shuts down.
+
+Entities are read in pages of ` + "`page_size`" + ` entities, which are each emitted as a batch. The scan can be limited to a range of partitions with ` + "`partition_key_start` and `partition_key_end`" + `, and any further [OData filter](https://docs.microsoft.com/en-us/rest/api/storageservices/querying-tables-and-entities#constructing-filter-strings) can be provided with ` + "`filter`" + `.
+
+This input adds the following metadata fields to each message:
+
+` + "```" + `
+- table_storage_table_name
+- table_storage_partition_key
+- table_storage_row_key
+` + "```" + `
+
+### Checkpointing
+
+When ` + "`checkpoint_cache`" + ` is set the partition and row key of the last entity acknowledged is stored within the cache, and upon restart the scan resumes from the entity following it, which allows large tables to be consumed over multiple runs.
+
+Only one authentication method is required, ` + "`storage_connection_string`" + ` or ` + "`storage_account` and `storage_access_key`" + `. If both are set then the ` + "`storage_connection_string`" + ` is given priority.`,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString(
+				"storage_account",
+				"The storage account to query entities from. This field is ignored if `storage_connection_string` is set.",
+			),
+			docs.FieldString(
+				"storage_access_key",
+				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
+			),
+			docs.FieldString(
+				"storage_connection_string",
+				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` are not set.",
+			),
+			docs.FieldString("table_name", "The table to query entities from.", "Foo"),
+			docs.FieldString(
+				"filter", "An optional [OData filter](https://docs.microsoft.com/en-us/rest/api/storageservices/querying-tables-and-entities#constructing-filter-strings) to apply to the query.",
+				"Status eq 'pending'", "Timestamp ge datetime'2022-01-01T00:00:00Z'",
+			),
+			docs.FieldString("partition_key_start", "An optional partition key to start the scan from, which is inclusive."),
+			docs.FieldString("partition_key_end", "An optional partition key to end the scan at, which is exclusive."),
+			docs.FieldInt("page_size", "The maximum number of entities to read from each page of the query, which are emitted as a batch.").Advanced(),
+			docs.FieldString("checkpoint_cache", "An optional [cache resource](/docs/components/caches/about) to store the position of the scan within, allowing it to [resume](#checkpointing) after a restart.").Advanced(),
+			docs.FieldString("checkpoint_key", "The key to store the position of the scan within the checkpoint cache. By default the table name is used.").Advanced(),
+		).ChildDefaultAndTypesFromStruct(input.NewAzureTableStorageConfig()),
+		Categories: []string{
+			"Services",
+			"Azure",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// tableStoragePosition is the position of a scan within a table.
+type tableStoragePosition struct {
+	PartitionKey string `json:"partition_key"`
+	RowKey       string `json:"row_key"`
+}
+
+type azureTableStorage struct {
+	conf   input.AzureTableStorageConfig
+	client *aztables.ServiceClient
+	mgr    bundle.NewManagement
+	log    log.Modular
+
+	checkpointKey string
+	checkpointer  *checkpoint.Capped
+	storeMut      sync.Mutex
+
+	pagerMut sync.Mutex
+	pager    aztables.ListEntitiesPager
+}
+
+func newAzureTableStorage(conf input.AzureTableStorageConfig, mgr bundle.NewManagement, log log.Modular) (*azureTableStorage, error) {
+	if conf.TableName == "" {
+		return nil, errors.New("a table_name must be specified")
+	}
+	if conf.PageSize <= 0 {
+		return nil, fmt.Errorf("page_size must be greater than zero, got %v", conf.PageSize)
+	}
+	if conf.CheckpointCache != "" && !mgr.ProbeCache(conf.CheckpointCache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", conf.CheckpointCache)
+	}
+
+	client, err := newTablesServiceClient(conf.StorageAccount, conf.StorageAccessKey, conf.StorageConnectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &azureTableStorage{
+		conf:          conf,
+		client:        client,
+		mgr:           mgr,
+		log:           log,
+		checkpointKey: conf.CheckpointKey,
+		checkpointer:  checkpoint.NewCapped(int64(conf.PageSize) * 10),
+	}
+	if a.checkpointKey == "" {
+		a.checkpointKey = conf.TableName
+	}
+	return a, nil
+}
+
+// escapeODataString escapes a string literal for use within an OData filter.
+func escapeODataString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// tableStorageFilter builds the filter of a scan from the configured filter,
+// partition key range and position to resume from.
+func tableStorageFilter(conf input.AzureTableStorageConfig, pos *tableStoragePosition) string {
+	var clauses []string
+	if conf.Filter != "" {
+		clauses = append(clauses, "("+conf.Filter+")")
+	}
+	if conf.PartitionKeyStart != "" {
+		clauses = append(clauses, "PartitionKey ge "+escapeODataString(conf.PartitionKeyStart))
+	}
+	if conf.PartitionKeyEnd != "" {
+		clauses = append(clauses, "PartitionKey lt "+escapeODataString(conf.PartitionKeyEnd))
+	}
+	if pos != nil {
+		pk := escapeODataString(pos.PartitionKey)
+		clauses = append(clauses, fmt.Sprintf(
+			"((PartitionKey gt %v) or (PartitionKey eq %v and RowKey gt %v))",
+			pk, pk, escapeODataString(pos.RowKey),
+		))
+	}
+	return strings.Join(clauses, " and ")
+}
+
+func (a *azureTableStorage) loadPosition(ctx context.Context) (*tableStoragePosition, error) {
+	if a.conf.CheckpointCache == "" {
+		return nil, nil
+	}
+
+	var posBytes []byte
+	var cerr error
+	if err := a.mgr.AccessCache(ctx, a.conf.CheckpointCache, func(c cache.V1) {
+		posBytes, cerr = c.Get(ctx, a.checkpointKey)
+	}); err != nil {
+		return nil, err
+	}
+	if errors.Is(cerr, component.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	var pos tableStoragePosition
+	if err := json.Unmarshal(posBytes, &pos); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &pos, nil
+}
+
+func (a *azureTableStorage) storePosition(ctx context.Context) error {
+	a.storeMut.Lock()
+	defer a.storeMut.Unlock()
+
+	pos, _ := a.checkpointer.Highest().(*tableStoragePosition)
+	if pos == nil {
+		return nil
+	}
+
+	posBytes, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+
+	var cerr error
+	if err := a.mgr.AccessCache(ctx, a.conf.CheckpointCache, func(c cache.V1) {
+		cerr = c.Set(ctx, a.checkpointKey, posBytes, nil)
+	}); err != nil {
+		return err
+	}
+	return cerr
+}
+
+func (a *azureTableStorage) ConnectWithContext(ctx context.Context) error {
+	a.pagerMut.Lock()
+	defer a.pagerMut.Unlock()
+
+	if a.pager != nil {
+		return nil
+	}
+
+	pos, err := a.loadPosition(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	opts := &aztables.ListEntitiesOptions{
+		Top: &a.conf.PageSize,
+	}
+	if filter := tableStorageFilter(a.conf, pos); filter != "" {
+		opts.Filter = &filter
+	}
+
+	a.pager = a.client.NewClient(a.conf.TableName).List(opts)
+	if pos != nil {
+		a.log.Infof("Resuming query of Azure Storage Table %v from partition key %v and row key %v", a.conf.TableName, pos.PartitionKey, pos.RowKey)
+	} else {
+		a.log.Infof("Querying Azure Storage Table: %v", a.conf.TableName)
+	}
+	return nil
+}
+
+func (a *azureTableStorage) ReadWithContext(ctx context.Context) (*message.Batch, input.AsyncAckFn, error) {
+	a.pagerMut.Lock()
+	defer a.pagerMut.Unlock()
+
+	if a.pager == nil {
+		return nil, nil, component.ErrNotConnected
+	}
+
+	if !a.pager.NextPage(ctx) {
+		if err := a.pager.Err(); err != nil {
+			a.pager = nil
+			return nil, nil, fmt.Errorf("failed to query table: %w", err)
+		}
+		return nil, nil, component.ErrTypeClosed
+	}
+
+	entities := a.pager.PageResponse().Entities
+	if len(entities) == 0 {
+		return nil, nil, component.ErrTimeout
+	}
+
+	msg := message.QuickBatch(nil)
+	var last tableStoragePosition
+	for _, e := range entities {
+		if err := json.Unmarshal(e, &last); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse entity: %w", err)
+		}
+		part := message.NewPart(e)
+		part.MetaSet("table_storage_table_name", a.conf.TableName)
+		part.MetaSet("table_storage_partition_key", last.PartitionKey)
+		part.MetaSet("table_storage_row_key", last.RowKey)
+		msg.Append(part)
+	}
+
+	if a.conf.CheckpointCache == "" {
+		return msg, func(context.Context, error) error {
+			return nil
+		}, nil
+	}
+
+	resolveFn, err := a.checkpointer.Track(ctx, &last, int64(len(entities)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, func(ctx context.Context, res error) error {
+		if res != nil {
+			// The scan does not rewind, and therefore rejected entities are
+			// left to be consumed again after a restart.
+			return nil
+		}
+		resolveFn()
+		return a.storePosition(ctx)
+	}, nil
+}
+
+func (a *azureTableStorage) CloseAsync() {
+}
+
+func (a *azureTableStorage) WaitForClose(time.Duration) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+)
+
+func TestTableStorageFilter(t *testing.T) {
+	conf := input.NewAzureTableStorageConfig()
+	assert.Equal(t, "", tableStorageFilter(conf, nil))
+
+	conf.Filter = "Status eq 'pending'"
+	conf.PartitionKeyStart = "a"
+	conf.PartitionKeyEnd = "m"
+	assert.Equal(t,
+		"(Status eq 'pending') and PartitionKey ge 'a' and PartitionKey lt 'm'",
+		tableStorageFilter(conf, nil),
+	)
+
+	conf = input.NewAzureTableStorageConfig()
+	assert.Equal(t,
+		"((PartitionKey gt 'foo''s') or (PartitionKey eq 'foo''s' and RowKey gt '10'))",
+		tableStorageFilter(conf, &tableStoragePosition{PartitionKey: "foo's", RowKey: "10"}),
+	)
+}
@@ -0,0 +1,39 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+)
+
+// newTablesServiceClient creates a table storage client from storage fields.
+func newTablesServiceClient(storageAccount, storageAccessKey, storageConnectionString string) (*aztables.ServiceClient, error) {
+	if storageAccount == "" && storageConnectionString == "" {
+		return nil, errors.New("invalid azure storage account credentials")
+	}
+	var client *aztables.ServiceClient
+	var err error
+	if storageConnectionString != "" {
+		if strings.Contains(storageConnectionString, "UseDevelopmentStorage=true;") {
+			// Only here to support legacy configs that pass UseDevelopmentStorage=true;
+			// `UseDevelopmentStorage=true` is not available in the current SDK, neither `storage.NewEmulatorClient()` (which was used in the previous SDK).
+			// Instead, we use the http connection string to connect to the emulator endpoints with the default table storage port.
+			// https://docs.microsoft.com/en-us/azure/storage/common/storage-use-azurite?tabs=visual-studio#http-connection-strings
+			client, err = aztables.NewServiceClientFromConnectionString("DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==;TableEndpoint=http://127.0.0.1:10002/devstoreaccount1;", nil)
+		} else {
+			client, err = aztables.NewServiceClientFromConnectionString(storageConnectionString, nil)
+		}
+	} else {
+		cred, credErr := aztables.NewSharedKeyCredential(storageAccount, storageAccessKey)
+		if credErr != nil {
+			return nil, fmt.Errorf("invalid azure storage account credentials: %v", credErr)
+		}
+		client, err = aztables.NewServiceClientWithSharedKey(fmt.Sprintf("https://%s.table.core.windows.net/", storageAccount), cred, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure storage account credentials: %v", err)
+	}
+	return client, nil
+}
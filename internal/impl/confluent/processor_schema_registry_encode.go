@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -46,11 +47,24 @@ For example, the union schema ` + "`[\"null\",\"string\",\"Foo\"]`, where `Foo`"
 - the string ` + "`\"a\"` as `{\"string\": \"a\"}`" + `; and
 - a ` + "`Foo` instance as `{\"Foo\": {...}}`, where `{...}` indicates the JSON encoding of a `Foo`" + ` instance.
 
-However, it is possible to instead consume documents in raw JSON format (that match the schema) by setting the field ` + "[`avro_raw_json`](#avro_raw_json) to `true`" + `.`).
+However, it is possible to instead consume documents in raw JSON format (that match the schema) by setting the field ` + "[`avro_raw_json`](#avro_raw_json) to `true`" + `.
+
+### Subject Name Strategies
+
+The ` + "[`subject`](#subject)" + ` field is resolved for each message, and therefore any subject name strategy can be implemented with interpolation functions. For example, when writing to Kafka the TopicNameStrategy can be achieved with ` + "`${! meta(\"kafka_topic\") }-value`" + `, and the TopicRecordNameStrategy with ` + "`${! meta(\"kafka_topic\") }-${! meta(\"record_name\") }`" + `, where the fully qualified record name is provided as metadata.
+
+Schemas are cached for each combination of subject and ` + "[`subject_version`](#subject_version)" + `, and so the registry is only queried the first time a combination is seen and when the latest version of a subject is periodically refreshed. Schemas of a specific version never change and are therefore not refreshed.`).
 		Field(service.NewStringField("url").Description("The base URL of the schema registry service.")).
 		Field(service.NewInterpolatedStringField("subject").Description("The schema subject to derive schemas from.").
 			Example("foo").
-			Example(`${! meta("kafka_topic") }`)).
+			Example(`${! meta("kafka_topic") }`).
+			Example(`${! meta("kafka_topic") }-${! meta("record_name") }`)).
+		Field(service.NewInterpolatedStringField("subject_version").
+			Description("The version of the subject to encode messages with, either `latest` or a specific version number.").
+			Advanced().Default("latest").Version("4.2.0").
+			Example("latest").
+			Example("3").
+			Example(`${! meta("schema_version") }`)).
 		Field(service.NewStringField("refresh_period").
 			Description("The period after which a schema is refreshed for each subject, this is done by polling the schema registry service.").
 			Default("10m").
@@ -80,12 +94,13 @@ func init() {
 type schemaRegistryEncoder struct {
 	client             *http.Client
 	subject            *service.InterpolatedString
+	subjectVersion     *service.InterpolatedString
 	avroRawJSON        bool
 	schemaRefreshAfter time.Duration
 
 	schemaRegistryBaseURL *url.URL
 
-	schemas    map[string]*cachedSchemaEncoder
+	schemas    map[schemaSubjectVersion]*cachedSchemaEncoder
 	cacheMut   sync.RWMutex
 	requestMut sync.Mutex
 	shutSig    *shutdown.Signaller
@@ -103,6 +118,10 @@ func newSchemaRegistryEncoderFromConfig(conf *service.ParsedConfig, logger *serv
 	if err != nil {
 		return nil, err
 	}
+	subjectVersion, err := conf.FieldInterpolatedString("subject_version")
+	if err != nil {
+		return nil, err
+	}
 	avroRawJSON, err := conf.FieldBool("avro_raw_json")
 	if err != nil {
 		return nil, err
@@ -123,7 +142,12 @@ func newSchemaRegistryEncoderFromConfig(conf *service.ParsedConfig, logger *serv
 	if err != nil {
 		return nil, err
 	}
-	return newSchemaRegistryEncoder(urlStr, tlsConf, subject, avroRawJSON, refreshPeriod, refreshTicker, logger)
+	e, err := newSchemaRegistryEncoder(urlStr, tlsConf, subject, avroRawJSON, refreshPeriod, refreshTicker, logger)
+	if err != nil {
+		return nil, err
+	}
+	e.subjectVersion = subjectVersion
+	return e, nil
 }
 
 func newSchemaRegistryEncoder(
@@ -144,7 +168,7 @@ func newSchemaRegistryEncoder(
 		subject:               subject,
 		avroRawJSON:           avroRawJSON,
 		schemaRefreshAfter:    schemaRefreshAfter,
-		schemas:               map[string]*cachedSchemaEncoder{},
+		schemas:               map[schemaSubjectVersion]*cachedSchemaEncoder{},
 		shutSig:               shutdown.NewSignaller(),
 		logger:                logger,
 		nowFn:                 time.Now,
@@ -180,7 +204,15 @@ func newSchemaRegistryEncoder(
 func (s *schemaRegistryEncoder) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
 	batch = batch.Copy()
 	for i, msg := range batch {
-		encoder, id, err := s.getEncoder(batch.InterpolatedString(i, s.subject))
+		key := schemaSubjectVersion{
+			subject: batch.InterpolatedString(i, s.subject),
+			version: latestSubjectVersion,
+		}
+		if s.subjectVersion != nil {
+			key.version = batch.InterpolatedString(i, s.subjectVersion)
+		}
+
+		encoder, id, err := s.getEncoder(key)
 		if err != nil {
 			msg.SetError(err)
 			continue
@@ -223,6 +255,29 @@ func (s *schemaRegistryEncoder) Close(ctx context.Context) error {
 
 type schemaEncoder func(m *service.Message) error
 
+const latestSubjectVersion = "latest"
+
+// schemaSubjectVersion identifies a schema within the registry by its subject
+// and either a version number or latest.
+type schemaSubjectVersion struct {
+	subject string
+	version string
+}
+
+func (k schemaSubjectVersion) String() string {
+	return fmt.Sprintf("%v (version %v)", k.subject, k.version)
+}
+
+func (k schemaSubjectVersion) validate() error {
+	if k.version == latestSubjectVersion {
+		return nil
+	}
+	if v, err := strconv.Atoi(k.version); err != nil || v < 1 {
+		return fmt.Errorf("schema subject version '%v' must be either latest or a positive integer", k.version)
+	}
+	return nil
+}
+
 type cachedSchemaEncoder struct {
 	lastUsedUnixSeconds    int64
 	lastUpdatedUnixSeconds int64
@@ -245,11 +300,13 @@ func (s *schemaRegistryEncoder) refreshEncoders() {
 	s.cacheMut.RLock()
 	purgeTargetTime := s.nowFn().Add(-schemaStaleAfter).Unix()
 	updateTargetTime := s.nowFn().Add(-s.schemaRefreshAfter).Unix()
-	var purgeTargets, refreshTargets []string
+	var purgeTargets, refreshTargets []schemaSubjectVersion
 	for k, v := range s.schemas {
 		if atomic.LoadInt64(&v.lastUsedUnixSeconds) < purgeTargetTime {
 			purgeTargets = append(purgeTargets, k)
-		} else if atomic.LoadInt64(&v.lastUpdatedUnixSeconds) < updateTargetTime {
+		} else if k.version == latestSubjectVersion && atomic.LoadInt64(&v.lastUpdatedUnixSeconds) < updateTargetTime {
+			// Specific versions of a subject are immutable and therefore only
+			// the latest version needs refreshing.
 			refreshTargets = append(refreshTargets, k)
 		}
 	}
@@ -270,7 +327,7 @@ func (s *schemaRegistryEncoder) refreshEncoders() {
 	if len(refreshTargets) > 0 {
 		s.requestMut.Lock()
 		for _, k := range refreshTargets {
-			encoder, id, err := s.getLatestEncoder(k)
+			encoder, id, err := s.fetchEncoder(k)
 			if err != nil {
 				s.logger.Errorf("Failed to refresh schema subject '%v': %v", k, err)
 			} else {
@@ -285,12 +342,12 @@ func (s *schemaRegistryEncoder) refreshEncoders() {
 	}
 }
 
-func (s *schemaRegistryEncoder) getLatestEncoder(subject string) (schemaEncoder, int, error) {
+func (s *schemaRegistryEncoder) fetchEncoder(subject schemaSubjectVersion) (schemaEncoder, int, error) {
 	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
 	defer done()
 
 	reqURL := *s.schemaRegistryBaseURL
-	reqURL.Path = path.Join(reqURL.Path, fmt.Sprintf("/subjects/%s/versions/latest", subject))
+	reqURL.Path = path.Join(reqURL.Path, fmt.Sprintf("/subjects/%s/versions/%s", subject.subject, subject.version))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL.String(), http.NoBody)
 	if err != nil {
@@ -320,7 +377,7 @@ func (s *schemaRegistryEncoder) getLatestEncoder(subject string) (schemaEncoder,
 		}
 
 		if res.Body == nil {
-			s.logger.Errorf("request for schema subject latest '%v' returned an empty body", subject)
+			s.logger.Errorf("request for schema subject '%v' returned an empty body", subject)
 			err = errors.New("schema request returned an empty body")
 			continue
 		}
@@ -378,7 +435,11 @@ func (s *schemaRegistryEncoder) getLatestEncoder(subject string) (schemaEncoder,
 	}, resPayload.ID, nil
 }
 
-func (s *schemaRegistryEncoder) getEncoder(subject string) (schemaEncoder, int, error) {
+func (s *schemaRegistryEncoder) getEncoder(subject schemaSubjectVersion) (schemaEncoder, int, error) {
+	if err := subject.validate(); err != nil {
+		return nil, 0, err
+	}
+
 	s.cacheMut.RLock()
 	c, ok := s.schemas[subject]
 	s.cacheMut.RUnlock()
@@ -400,7 +461,7 @@ func (s *schemaRegistryEncoder) getEncoder(subject string) (schemaEncoder, int,
 		return c.encoder, c.id, nil
 	}
 
-	encoder, id, err := s.getLatestEncoder(subject)
+	encoder, id, err := s.fetchEncoder(subject)
 	if err != nil {
 		return nil, 0, err
 	}
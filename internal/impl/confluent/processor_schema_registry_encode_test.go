@@ -262,19 +262,19 @@ func TestSchemaRegistryEncodeClearExpired(t *testing.T) {
 	tNearlyStale := time.Now().Add(-(schemaStaleAfter / 2)).Unix()
 
 	encoder.cacheMut.Lock()
-	encoder.schemas = map[string]*cachedSchemaEncoder{
-		"5":  {lastUsedUnixSeconds: tStale, lastUpdatedUnixSeconds: tNotStale},
-		"10": {lastUsedUnixSeconds: tNotStale, lastUpdatedUnixSeconds: tNotStale},
-		"15": {lastUsedUnixSeconds: tNearlyStale, lastUpdatedUnixSeconds: tNotStale},
+	encoder.schemas = map[schemaSubjectVersion]*cachedSchemaEncoder{
+		{subject: "5", version: latestSubjectVersion}:  {lastUsedUnixSeconds: tStale, lastUpdatedUnixSeconds: tNotStale},
+		{subject: "10", version: latestSubjectVersion}: {lastUsedUnixSeconds: tNotStale, lastUpdatedUnixSeconds: tNotStale},
+		{subject: "15", version: latestSubjectVersion}: {lastUsedUnixSeconds: tNearlyStale, lastUpdatedUnixSeconds: tNotStale},
 	}
 	encoder.cacheMut.Unlock()
 
 	encoder.refreshEncoders()
 
 	encoder.cacheMut.Lock()
-	assert.Equal(t, map[string]*cachedSchemaEncoder{
-		"10": {lastUsedUnixSeconds: tNotStale, lastUpdatedUnixSeconds: tNotStale},
-		"15": {lastUsedUnixSeconds: tNearlyStale, lastUpdatedUnixSeconds: tNotStale},
+	assert.Equal(t, map[schemaSubjectVersion]*cachedSchemaEncoder{
+		{subject: "10", version: latestSubjectVersion}: {lastUsedUnixSeconds: tNotStale, lastUpdatedUnixSeconds: tNotStale},
+		{subject: "15", version: latestSubjectVersion}: {lastUsedUnixSeconds: tNearlyStale, lastUpdatedUnixSeconds: tNotStale},
 	}, encoder.schemas)
 	encoder.cacheMut.Unlock()
 }
@@ -327,13 +327,13 @@ func TestSchemaRegistryEncodeRefresh(t *testing.T) {
 	}
 
 	encoder.cacheMut.Lock()
-	encoder.schemas = map[string]*cachedSchemaEncoder{
-		"foo": {
+	encoder.schemas = map[schemaSubjectVersion]*cachedSchemaEncoder{
+		{subject: "foo", version: latestSubjectVersion}: {
 			lastUsedUnixSeconds:    tNotStale,
 			lastUpdatedUnixSeconds: tStale,
 			id:                     1,
 		},
-		"bar": {
+		{subject: "bar", version: latestSubjectVersion}: {
 			lastUsedUnixSeconds:    tNotStale,
 			lastUpdatedUnixSeconds: tNearlyStale,
 			id:                     11,
@@ -347,20 +347,20 @@ func TestSchemaRegistryEncodeRefresh(t *testing.T) {
 	encoder.refreshEncoders()
 
 	encoder.cacheMut.Lock()
-	encoder.schemas["foo"].encoder = nil
-	assert.Equal(t, map[string]*cachedSchemaEncoder{
-		"foo": {
+	encoder.schemas[schemaSubjectVersion{subject: "foo", version: latestSubjectVersion}].encoder = nil
+	assert.Equal(t, map[schemaSubjectVersion]*cachedSchemaEncoder{
+		{subject: "foo", version: latestSubjectVersion}: {
 			lastUsedUnixSeconds:    tNotStale,
 			lastUpdatedUnixSeconds: tNotStale,
 			id:                     2,
 		},
-		"bar": {
+		{subject: "bar", version: latestSubjectVersion}: {
 			lastUsedUnixSeconds:    tNotStale,
 			lastUpdatedUnixSeconds: tNearlyStale,
 			id:                     11,
 		},
 	}, encoder.schemas)
-	encoder.schemas["bar"].lastUpdatedUnixSeconds = tStale
+	encoder.schemas[schemaSubjectVersion{subject: "bar", version: latestSubjectVersion}].lastUpdatedUnixSeconds = tStale
 	encoder.cacheMut.Unlock()
 
 	assert.Equal(t, int32(1), atomic.LoadInt32(&fooReqs))
@@ -369,14 +369,14 @@ func TestSchemaRegistryEncodeRefresh(t *testing.T) {
 	encoder.refreshEncoders()
 
 	encoder.cacheMut.Lock()
-	encoder.schemas["bar"].encoder = nil
-	assert.Equal(t, map[string]*cachedSchemaEncoder{
-		"foo": {
+	encoder.schemas[schemaSubjectVersion{subject: "bar", version: latestSubjectVersion}].encoder = nil
+	assert.Equal(t, map[schemaSubjectVersion]*cachedSchemaEncoder{
+		{subject: "foo", version: latestSubjectVersion}: {
 			lastUsedUnixSeconds:    tNotStale,
 			lastUpdatedUnixSeconds: tNotStale,
 			id:                     2,
 		},
-		"bar": {
+		{subject: "bar", version: latestSubjectVersion}: {
 			lastUsedUnixSeconds:    tNotStale,
 			lastUpdatedUnixSeconds: tNotStale,
 			id:                     12,
@@ -387,3 +387,78 @@ func TestSchemaRegistryEncodeRefresh(t *testing.T) {
 	assert.Equal(t, int32(1), atomic.LoadInt32(&fooReqs))
 	assert.Equal(t, int32(1), atomic.LoadInt32(&barReqs))
 }
+
+func TestSchemaRegistryEncodeSubjectVersion(t *testing.T) {
+	fooLatest, err := json.Marshal(struct {
+		Schema string `json:"schema"`
+		ID     int    `json:"id"`
+	}{
+		Schema: testSchema,
+		ID:     3,
+	})
+	require.NoError(t, err)
+
+	fooSecond, err := json.Marshal(struct {
+		Schema string `json:"schema"`
+		ID     int    `json:"id"`
+	}{
+		Schema: testSchema,
+		ID:     2,
+	})
+	require.NoError(t, err)
+
+	var reqs int32
+	urlStr := runSchemaRegistryServer(t, func(path string) ([]byte, error) {
+		atomic.AddInt32(&reqs, 1)
+		switch path {
+		case "/subjects/foo/versions/latest":
+			return fooLatest, nil
+		case "/subjects/foo/versions/2":
+			return fooSecond, nil
+		}
+		return nil, errors.New("nope")
+	})
+
+	conf, err := schemaRegistryEncoderConfig().ParseYAML(fmt.Sprintf(`
+url: %v
+subject: foo
+subject_version: ${! meta("version") }
+avro_raw_json: true
+`, urlStr), nil)
+	require.NoError(t, err)
+
+	encoder, err := newSchemaRegistryEncoderFromConfig(conf, nil)
+	require.NoError(t, err)
+
+	newMsg := func(version string) *service.Message {
+		msg := service.NewMessage([]byte(`{"Address":{"City":"foo","State":"bar"},"Name":"foo","MaybeHobby":null}`))
+		msg.MetaSet("version", version)
+		return msg
+	}
+
+	for i := 0; i < 3; i++ {
+		batches, err := encoder.ProcessBatch(context.Background(), service.MessageBatch{
+			newMsg("latest"), newMsg("2"), newMsg("nope"),
+		})
+		require.NoError(t, err)
+		require.Len(t, batches, 1)
+		require.Len(t, batches[0], 3)
+
+		b, err := batches[0][0].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, "\x00\x00\x00\x00\x03", string(b[:5]))
+
+		b, err = batches[0][1].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, "\x00\x00\x00\x00\x02", string(b[:5]))
+
+		err = batches[0][2].GetError()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be either latest or a positive integer")
+	}
+
+	// Each subject version is only requested once.
+	assert.Equal(t, int32(2), atomic.LoadInt32(&reqs))
+
+	require.NoError(t, encoder.Close(context.Background()))
+}
@@ -0,0 +1,42 @@
+package dns
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+)
+
+var (
+	defaultResolver     *resolver
+	defaultResolverOnce sync.Once
+)
+
+func init() {
+	if err := bloblang.RegisterMethodV2("dns_lookup",
+		bloblang.NewPluginSpec().
+			Experimental().
+			Impure().
+			Category(query.MethodCategoryNetwork).
+			Version("4.2.0").
+			Description("Performs a DNS lookup of a name, or for `ptr` lookups an IP address, using the name servers listed in `/etc/resolv.conf` and returns an array of the records found, which is empty when the name does not exist. Records of the type `mx` are objects with the fields `host` and `preference`, and all other records are strings. Results are cached for the TTL provided with them by the name server. For more control over lookups use the [`dns` processor](/docs/components/processors/dns).").
+			Param(bloblang.NewStringParam("record_type").Description("The type of records to look up, one of `a`, `aaaa`, `ptr`, `txt` and `mx`.").Default("a")).
+			Example("", `root.src_host = this.src_ip.dns_lookup("ptr").index(0).catch(null)`).
+			Example("", `root.mail_servers = this.domain.dns_lookup("mx").map_each(mx -> mx.host)`),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			recordType, err := args.GetString("record_type")
+			if err != nil {
+				return nil, err
+			}
+			defaultResolverOnce.Do(func() {
+				defaultResolver = newResolver(nil, time.Second*5, 1024)
+			})
+			return bloblang.StringMethod(func(s string) (interface{}, error) {
+				return defaultResolver.Lookup(context.Background(), recordType, s)
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+}
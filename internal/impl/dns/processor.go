@@ -0,0 +1,115 @@
+package dns
+
+import (
+	"context"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func processorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		// Stable(). TODO
+		Categories("Integration").
+		Version("4.2.0").
+		Summary("Performs DNS lookups for each message and replaces its contents with an array of the records found.").
+		Description(`
+The records of a lookup are cached for the TTL provided with them by the name server, and the absence of records is cached for the negative TTL of the zone when the name server provides one. When a name does not exist the result is an empty array, and other failed lookups flag the message as failed, which can be handled with [error handling patterns](/docs/configuration/error_handling).
+
+Records of the types `+"`a`, `aaaa`, `ptr` and `txt`"+` result in an array of strings, and records of the type `+"`mx`"+` result in an array of objects with the fields `+"`host` and `preference`"+`. For `+"`ptr`"+` lookups the query can be an IP address, which is converted into its reverse lookup name.
+
+This processor replaces the contents of messages, and in order to enrich messages with the results of lookups instead it should be placed within a `+"[`branch` processor](/docs/components/processors/branch)"+`.`).
+		Field(service.NewStringEnumField("record_type", recordTypeNames()...).
+			Description("The type of records to look up.").
+			Default("a")).
+		Field(service.NewInterpolatedStringField("query").
+			Description("The name or, for `ptr` lookups, the IP address to look up.").
+			Example(`${! json("hostname") }`).
+			Default("${! content() }")).
+		Field(service.NewStringListField("resolvers").
+			Description("A list of name server addresses to send lookups to, which are attempted in order until one responds. Addresses without a port use port 53. When empty the name servers listed in `/etc/resolv.conf` are used.").
+			Example([]string{"8.8.8.8", "1.1.1.1:53"}).
+			Default([]string{})).
+		Field(service.NewDurationField("timeout").
+			Description("The maximum period of time to wait for a name server to respond to a lookup.").
+			Default("5s")).
+		Field(service.NewIntField("cache_size").
+			Description("The maximum number of lookup results to cache. Set to `0` in order to disable caching.").
+			Advanced().
+			Default(1024)).
+		Example("Reverse DNS",
+			"Here we add the hostnames of the source IP addresses of network events to each message:",
+			`
+pipeline:
+  processors:
+    - branch:
+        request_map: root = this.src_ip
+        processors:
+          - dns:
+              record_type: ptr
+        result_map: root.src_host = this.index(0).catch(null)
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"dns", processorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newProcessorFromConfig(conf)
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	recordType string
+	query      *service.InterpolatedString
+	resolver   *resolver
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig) (*processor, error) {
+	recordType, err := conf.FieldString("record_type")
+	if err != nil {
+		return nil, err
+	}
+	query, err := conf.FieldInterpolatedString("query")
+	if err != nil {
+		return nil, err
+	}
+	servers, err := conf.FieldStringList("resolvers")
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration("timeout")
+	if err != nil {
+		return nil, err
+	}
+	cacheSize, err := conf.FieldInt("cache_size")
+	if err != nil {
+		return nil, err
+	}
+	return &processor{
+		recordType: recordType,
+		query:      query,
+		resolver:   newResolver(servers, timeout, cacheSize),
+	}, nil
+}
+
+func (p *processor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	values, err := p.resolver.Lookup(ctx, p.recordType, p.query.String(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	resMsg := msg.Copy()
+	resMsg.SetStructured(values)
+	return service.MessageBatch{resMsg}, nil
+}
+
+func (p *processor) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,367 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+var recordTypes = map[string]dnsmessage.Type{
+	"a":    dnsmessage.TypeA,
+	"aaaa": dnsmessage.TypeAAAA,
+	"ptr":  dnsmessage.TypePTR,
+	"txt":  dnsmessage.TypeTXT,
+	"mx":   dnsmessage.TypeMX,
+}
+
+func recordTypeNames() []string {
+	names := make([]string, 0, len(recordTypes))
+	for k := range recordTypes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type cacheKey struct {
+	name  string
+	qtype dnsmessage.Type
+}
+
+type cacheEntry struct {
+	values  []interface{}
+	expires time.Time
+}
+
+// resolver performs DNS lookups against a list of name servers and caches the
+// results for the TTL provided with them. Name servers are attempted in order
+// until one of them responds.
+type resolver struct {
+	servers    []string
+	timeout    time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mut   sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+func newResolver(servers []string, timeout time.Duration, maxEntries int) *resolver {
+	if len(servers) == 0 {
+		servers = systemServers()
+	}
+	addrs := make([]string, 0, len(servers))
+	for _, s := range servers {
+		if _, _, err := net.SplitHostPort(s); err != nil {
+			s = net.JoinHostPort(s, "53")
+		}
+		addrs = append(addrs, s)
+	}
+	return &resolver{
+		servers:    addrs,
+		timeout:    timeout,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		cache:      map[cacheKey]cacheEntry{},
+	}
+}
+
+// systemServers returns the name servers listed in /etc/resolv.conf, or a
+// local name server when none are found.
+func systemServers() []string {
+	var servers []string
+	if f, err := os.Open("/etc/resolv.conf"); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "nameserver" {
+				servers = append(servers, fields[1])
+			}
+		}
+		f.Close()
+	}
+	if len(servers) == 0 {
+		servers = []string{"127.0.0.1"}
+	}
+	return servers
+}
+
+// Lookup returns the records of a given type for a name, where the records of
+// a name that does not exist are empty. For PTR lookups the name may be an IP
+// address, which is converted into its reverse lookup name.
+func (r *resolver) Lookup(ctx context.Context, recordType, name string) ([]interface{}, error) {
+	qtype, exists := recordTypes[recordType]
+	if !exists {
+		return nil, fmt.Errorf("record type %v not recognised", recordType)
+	}
+	if qtype == dnsmessage.TypePTR {
+		if ip := net.ParseIP(name); ip != nil {
+			name = reverseName(ip)
+		}
+	}
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+
+	key := cacheKey{name: strings.ToLower(name), qtype: qtype}
+	if values, exists := r.cached(key); exists {
+		return values, nil
+	}
+
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %v: %w", name, err)
+	}
+
+	var res *dnsmessage.Message
+	for _, server := range r.servers {
+		if res, err = r.exchange(ctx, server, qname, qtype); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values, ttl, err := parseAnswers(res, qtype)
+	if err != nil {
+		return nil, err
+	}
+	r.store(key, values, ttl)
+	return copyValues(values), nil
+}
+
+func (r *resolver) cached(key cacheKey) ([]interface{}, bool) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	entry, exists := r.cache[key]
+	if !exists {
+		return nil, false
+	}
+	if !r.now().Before(entry.expires) {
+		delete(r.cache, key)
+		return nil, false
+	}
+	return copyValues(entry.values), true
+}
+
+func (r *resolver) store(key cacheKey, values []interface{}, ttl time.Duration) {
+	if r.maxEntries <= 0 || ttl <= 0 {
+		return
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	now := r.now()
+	if len(r.cache) >= r.maxEntries {
+		for k, v := range r.cache {
+			if !now.Before(v.expires) {
+				delete(r.cache, k)
+			}
+		}
+	}
+	for k := range r.cache {
+		if len(r.cache) < r.maxEntries {
+			break
+		}
+		delete(r.cache, k)
+	}
+	r.cache[key] = cacheEntry{values: values, expires: now.Add(ttl)}
+}
+
+func (r *resolver) exchange(ctx context.Context, server string, name dnsmessage.Name, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	id := uint16(rand.Intn(1 << 16))
+	req := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: name, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	reqBytes, err := req.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.timeout > 0 {
+		var done func()
+		ctx, done = context.WithTimeout(ctx, r.timeout)
+		defer done()
+	}
+
+	res, err := r.exchangeUDP(ctx, server, reqBytes, id)
+	if err == nil && res.Truncated {
+		// The response did not fit within a datagram, which means it must be
+		// retried over TCP.
+		res, err = r.exchangeTCP(ctx, server, reqBytes, id)
+	}
+	return res, err
+}
+
+func (r *resolver) exchangeUDP(ctx context.Context, server string, reqBytes []byte, id uint16) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(reqBytes); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 512)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var res dnsmessage.Message
+		if err := res.Unpack(buf[:n]); err != nil || res.ID != id {
+			// Ignore responses that aren't to our request.
+			continue
+		}
+		return &res, nil
+	}
+}
+
+func (r *resolver) exchangeTCP(ctx context.Context, server string, reqBytes []byte, id uint16) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	lenPrefixed := make([]byte, 2+len(reqBytes))
+	binary.BigEndian.PutUint16(lenPrefixed, uint16(len(reqBytes)))
+	copy(lenPrefixed[2:], reqBytes)
+	if _, err := conn.Write(lenPrefixed); err != nil {
+		return nil, err
+	}
+
+	var resLen [2]byte
+	if _, err := io.ReadFull(conn, resLen[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(resLen[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+
+	var res dnsmessage.Message
+	if err := res.Unpack(buf); err != nil {
+		return nil, err
+	}
+	if res.ID != id {
+		return nil, errors.New("response does not match the request")
+	}
+	return &res, nil
+}
+
+// parseAnswers extracts the records of a type from a response along with the
+// lowest TTL of the response, which is how long the records may be cached for.
+func parseAnswers(res *dnsmessage.Message, qtype dnsmessage.Type) ([]interface{}, time.Duration, error) {
+	switch res.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return []interface{}{}, negativeTTL(res), nil
+	default:
+		return nil, 0, fmt.Errorf("lookup failed with response code: %v", res.RCode)
+	}
+
+	values := []interface{}{}
+	var minTTL uint32
+	for i, a := range res.Answers {
+		if i == 0 || a.Header.TTL < minTTL {
+			minTTL = a.Header.TTL
+		}
+		if a.Header.Type != qtype {
+			continue
+		}
+		switch b := a.Body.(type) {
+		case *dnsmessage.AResource:
+			values = append(values, net.IP(b.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			values = append(values, net.IP(b.AAAA[:]).String())
+		case *dnsmessage.PTRResource:
+			values = append(values, b.PTR.String())
+		case *dnsmessage.TXTResource:
+			values = append(values, strings.Join(b.TXT, ""))
+		case *dnsmessage.MXResource:
+			values = append(values, map[string]interface{}{
+				"host":       b.MX.String(),
+				"preference": int64(b.Pref),
+			})
+		}
+	}
+	if len(values) == 0 {
+		return values, negativeTTL(res), nil
+	}
+	return values, time.Duration(minTTL) * time.Second, nil
+}
+
+// negativeTTL returns the period of time that the absence of records may be
+// cached for, which is provided by an SOA record when the server has one.
+func negativeTTL(res *dnsmessage.Message) time.Duration {
+	for _, a := range res.Authorities {
+		if soa, ok := a.Body.(*dnsmessage.SOAResource); ok {
+			ttl := a.Header.TTL
+			if soa.MinTTL < ttl {
+				ttl = soa.MinTTL
+			}
+			return time.Duration(ttl) * time.Second
+		}
+	}
+	return 0
+}
+
+func reverseName(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+	const hexDigits = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		_ = b.WriteByte(hexDigits[ip[i]&0xf])
+		_ = b.WriteByte('.')
+		_ = b.WriteByte(hexDigits[ip[i]>>4])
+		_ = b.WriteByte('.')
+	}
+	_, _ = b.WriteString("ip6.arpa.")
+	return b.String()
+}
+
+func copyValues(values []interface{}) []interface{} {
+	c := make([]interface{}, len(values))
+	for i, v := range values {
+		if m, ok := v.(map[string]interface{}); ok {
+			mc := make(map[string]interface{}, len(m))
+			for k, mv := range m {
+				mc[k] = mv
+			}
+			v = mc
+		}
+		c[i] = v
+	}
+	return c
+}
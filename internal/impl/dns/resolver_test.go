@@ -0,0 +1,171 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func startTestServer(t *testing.T, handler func(q dnsmessage.Question) dnsmessage.Message) (addr string, requests *int32) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	requests = new(int32)
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil {
+				continue
+			}
+			atomic.AddInt32(requests, 1)
+
+			res := handler(req.Questions[0])
+			res.ID = req.ID
+			res.Response = true
+			res.Questions = req.Questions
+			resBytes, err := res.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(resBytes, from)
+		}
+	}()
+	return conn.LocalAddr().String(), requests
+}
+
+func TestResolverLookup(t *testing.T) {
+	addr, _ := startTestServer(t, func(q dnsmessage.Question) dnsmessage.Message {
+		hdr := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 60}
+		switch q.Name.String() {
+		case "example.com.":
+			switch q.Type {
+			case dnsmessage.TypeA:
+				return dnsmessage.Message{Answers: []dnsmessage.Resource{
+					{Header: hdr, Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+					{Header: hdr, Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}},
+				}}
+			case dnsmessage.TypeMX:
+				return dnsmessage.Message{Answers: []dnsmessage.Resource{
+					{Header: hdr, Body: &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.example.com.")}},
+				}}
+			case dnsmessage.TypeTXT:
+				return dnsmessage.Message{Answers: []dnsmessage.Resource{
+					{Header: hdr, Body: &dnsmessage.TXTResource{TXT: []string{"foo", "bar"}}},
+				}}
+			}
+		case "1.0.0.10.in-addr.arpa.":
+			return dnsmessage.Message{Answers: []dnsmessage.Resource{
+				{Header: hdr, Body: &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName("host.example.com.")}},
+			}}
+		case "fail.example.com.":
+			return dnsmessage.Message{Header: dnsmessage.Header{RCode: dnsmessage.RCodeServerFailure}}
+		}
+		return dnsmessage.Message{Header: dnsmessage.Header{RCode: dnsmessage.RCodeNameError}}
+	})
+
+	r := newResolver([]string{addr}, time.Second, 10)
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		recordType string
+		name       string
+		expected   []interface{}
+	}{
+		{recordType: "a", name: "example.com", expected: []interface{}{"10.0.0.1", "10.0.0.2"}},
+		{recordType: "mx", name: "example.com", expected: []interface{}{
+			map[string]interface{}{"host": "mail.example.com.", "preference": int64(10)},
+		}},
+		{recordType: "txt", name: "example.com.", expected: []interface{}{"foobar"}},
+		{recordType: "ptr", name: "10.0.0.1", expected: []interface{}{"host.example.com."}},
+		{recordType: "a", name: "nope.example.com", expected: []interface{}{}},
+	} {
+		values, err := r.Lookup(ctx, test.recordType, test.name)
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, values, test.name)
+	}
+
+	_, err := r.Lookup(ctx, "a", "fail.example.com")
+	require.Error(t, err)
+
+	_, err = r.Lookup(ctx, "nope", "example.com")
+	require.Error(t, err)
+}
+
+func TestResolverCacheTTL(t *testing.T) {
+	addr, requests := startTestServer(t, func(q dnsmessage.Question) dnsmessage.Message {
+		return dnsmessage.Message{Answers: []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 30},
+			Body:   &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+		}}}
+	})
+
+	now := time.Now()
+	r := newResolver([]string{addr}, time.Second, 10)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		values, err := r.Lookup(ctx, "a", "example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"10.0.0.1"}, values)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+
+	now = now.Add(time.Second * 31)
+	_, err := r.Lookup(ctx, "a", "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	r.maxEntries = 0
+	now = now.Add(time.Second * 31)
+	for i := 0; i < 2; i++ {
+		_, err = r.Lookup(ctx, "a", "example.com")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
+
+func TestResolverFallbackServer(t *testing.T) {
+	addr, _ := startTestServer(t, func(q dnsmessage.Question) dnsmessage.Message {
+		return dnsmessage.Message{Answers: []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 30},
+			Body:   &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+		}}}
+	})
+
+	// Nothing is listening on the first server, which means the lookup times
+	// out and falls back to the second.
+	unused, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unusedAddr := unused.LocalAddr().String()
+	require.NoError(t, unused.Close())
+
+	r := newResolver([]string{unusedAddr, addr}, time.Millisecond*200, 10)
+	values, err := r.Lookup(context.Background(), "a", "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"10.0.0.1"}, values)
+}
+
+func TestReverseName(t *testing.T) {
+	assert.Equal(t, "4.3.2.1.in-addr.arpa.", reverseName(net.ParseIP("1.2.3.4")))
+	assert.Equal(t,
+		"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.",
+		reverseName(net.ParseIP("2001:db8::1")),
+	)
+}
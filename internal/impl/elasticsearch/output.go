@@ -47,7 +47,11 @@ sending batched messages these interpolations are performed per message part.
 
 It's possible to enable AWS connectivity with this output using the `+"`aws`"+`
 fields. However, you may need to set `+"`sniff` and `healthcheck`"+` to
-false for connections to succeed.`),
+false for connections to succeed.
+
+### Ordering
+
+Batches are written in parallel up to the limit of `+"`max_in_flight`"+`, and therefore writes that target the same document may be applied out of order. Setting an `+"`affinity_key`"+` makes it possible to balance throughput with ordering, as batches that resolve to the same key are written one at a time in the order that they were consumed, whilst batches of different keys are still written in parallel. Batches that are waiting on a prior batch of their key do not occupy a slot of `+"`max_in_flight`"+`, although consumption pauses once `+"`max_in_flight`"+` batches are waiting. The key is resolved from the first message of each batch, and therefore batches should be formed from messages that share a key, which can be achieved with a `+"[`group_by_value`](/docs/components/processors/group_by_value)"+` processor.`),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("urls", "A list of URLs to connect to. If an item of the list contains commas it will be expanded into multiple URLs.", []string{"http://localhost:9200"}).Array(),
 			docs.FieldString("index", "The index to place messages.").IsInterpolated(),
@@ -61,6 +65,7 @@ false for connections to succeed.`),
 			docs.FieldString("timeout", "The maximum time to wait before abandoning a request (and trying again).").Advanced(),
 			itls.FieldSpec(),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldString("affinity_key", "An optional key, resolved from the first message of each batch, where batches that share a key are written one at a time in the order that they were consumed. See [ordering](#ordering) for more information.", `${! meta("kafka_partition") }`, `${! json("doc_id") }`).IsInterpolated().Advanced().AtVersion("4.2.0"),
 		).WithChildren(retries.FieldSpecs()...).WithChildren(
 			auth.BasicAuthFieldSpec(),
 			policy.FieldSpec(),
@@ -92,6 +97,19 @@ func NewElasticsearch(conf output.Config, mgr bundle.NewManagement, log log.Modu
 	if err != nil {
 		return w, err
 	}
+
+	if conf.Elasticsearch.AffinityKey != "" {
+		aw, ok := w.(*output.AsyncWriter)
+		if !ok {
+			return nil, fmt.Errorf("unable to set an affinity_key due to wrong type: %T", w)
+		}
+
+		affinityKey, err := mgr.BloblEnvironment().NewField(conf.Elasticsearch.AffinityKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse affinity_key expression: %v", err)
+		}
+		aw.SetAffinityKey(affinityKey)
+	}
 	return batcher.NewFromConfig(conf.Elasticsearch.Batching, w, mgr, log, stats)
 }
 
@@ -3,6 +3,7 @@ package gcp
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/Masterminds/squirrel"
@@ -17,6 +18,7 @@ type bigqueryIterator interface {
 
 type bqClient interface {
 	RunQuery(ctx context.Context, options *bqQueryBuilderOptions) (bigqueryIterator, error)
+	ReadTable(ctx context.Context, table string, columns []string) (bigqueryIterator, error)
 	Close() error
 }
 
@@ -59,6 +61,61 @@ func (client *wrappedBQClient) RunQuery(ctx context.Context, options *bqQueryBui
 	return it, nil
 }
 
+func (client *wrappedBQClient) ReadTable(ctx context.Context, table string, columns []string) (bigqueryIterator, error) {
+	ref, err := parseBQTableRef(client.wrapped, table)
+	if err != nil {
+		return nil, err
+	}
+
+	client.logger.With("table", table).Debug("reading bigquery table")
+
+	var iter bigqueryIterator = ref.Read(ctx)
+	for _, c := range columns {
+		if c == "*" {
+			return iter, nil
+		}
+	}
+	return &columnsIterator{wrapped: iter, columns: columns}, nil
+}
+
+func parseBQTableRef(client *bigquery.Client, table string) (*bigquery.Table, error) {
+	parts := strings.Split(table, ".")
+	switch len(parts) {
+	case 2:
+		return client.Dataset(parts[0]).Table(parts[1]), nil
+	case 3:
+		return client.DatasetInProject(parts[0], parts[1]).Table(parts[2]), nil
+	}
+	return nil, fmt.Errorf("expected table name in the form [project.]dataset.table, got: %v", table)
+}
+
+// columnsIterator removes all columns from the rows of a table that were not
+// explicitly selected, as reading a table directly always returns every column.
+type columnsIterator struct {
+	wrapped bigqueryIterator
+	columns []string
+}
+
+func (c *columnsIterator) Next(dst interface{}) error {
+	row, ok := dst.(*map[string]bigquery.Value)
+	if !ok {
+		return c.wrapped.Next(dst)
+	}
+
+	var fullRow map[string]bigquery.Value
+	if err := c.wrapped.Next(&fullRow); err != nil {
+		return err
+	}
+
+	*row = make(map[string]bigquery.Value, len(c.columns))
+	for _, k := range c.columns {
+		if v, exists := fullRow[k]; exists {
+			(*row)[k] = v
+		}
+	}
+	return nil
+}
+
 func (client *wrappedBQClient) Close() error {
 	return client.wrapped.Close()
 }
@@ -49,6 +49,17 @@ func (client *mockBQClient) RunQuery(ctx context.Context, options *bqQueryBuilde
 	return iter, args.Error(1)
 }
 
+func (client *mockBQClient) ReadTable(ctx context.Context, table string, columns []string) (bigqueryIterator, error) {
+	args := client.Mock.Called(ctx, table, columns)
+
+	var iter bigqueryIterator
+	if mi := args.Get(0); mi != nil {
+		iter = mi.(bigqueryIterator)
+	}
+
+	return iter, args.Error(1)
+}
+
 func (client *mockBQClient) Close() error {
 	return nil
 }
@@ -15,7 +15,8 @@ func gcpCloudStorageCacheConfig() *service.ConfigSpec {
 		Summary(`Use a Google Cloud Storage bucket as a cache.`).
 		Description(`It is not possible to atomically upload cloud storage objects exclusively when the target does not already exist, therefore this cache is not suitable for deduplication.`).
 		Field(service.NewStringField("bucket").
-			Description("The Google Cloud Storage bucket to store items in."))
+			Description("The Google Cloud Storage bucket to store items in.")).
+		Field(credentialsField())
 
 	return spec
 }
@@ -38,7 +39,16 @@ func newGcpCloudStorageCacheFromConfig(parsedConf *service.ParsedConfig) (*gcpCl
 		return nil, err
 	}
 
-	client, err := storage.NewClient(context.Background())
+	creds, err := credentialsFromParsed(parsedConf)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := clientOptionsFromCredentials(context.Background(), creds)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
 	if err != nil {
 		return nil, err
 	}
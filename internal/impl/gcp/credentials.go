@@ -0,0 +1,82 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const gcpCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+func credentialsField() *service.ConfigField {
+	return service.NewInternalField(credentials.FieldSpec())
+}
+
+// credentialsFromParsed reads the credentials field of a parsed config into a
+// Config.
+func credentialsFromParsed(pConf *service.ParsedConfig) (conf credentials.Config, err error) {
+	conf = credentials.NewConfig()
+	if !pConf.Contains("credentials") {
+		return
+	}
+	c := pConf.Namespace("credentials")
+
+	if conf.File, err = c.FieldString("file"); err != nil {
+		return
+	}
+	if conf.JSON, err = c.FieldString("json"); err != nil {
+		return
+	}
+	if conf.Impersonate.ServiceAccount, err = c.FieldString("impersonate", "service_account"); err != nil {
+		return
+	}
+	conf.Impersonate.Delegates, err = c.FieldStringList("impersonate", "delegates")
+	return
+}
+
+// clientOptionsFromCredentials returns the client options that authenticate a
+// Google Cloud client with a credentials config. The context is used for
+// obtaining tokens when impersonating a service account and should therefore
+// outlive the client.
+func clientOptionsFromCredentials(ctx context.Context, conf credentials.Config) ([]option.ClientOption, error) {
+	var credsJSON []byte
+	switch {
+	case conf.File != "" && conf.JSON != "":
+		return nil, errors.New("credentials file and json cannot both be set")
+	case conf.File != "":
+		var err error
+		if credsJSON, err = os.ReadFile(conf.File); err != nil {
+			return nil, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+	case conf.JSON != "":
+		credsJSON = []byte(conf.JSON)
+	}
+
+	var opts []option.ClientOption
+	if credsJSON != nil {
+		opts = append(opts, option.WithCredentialsJSON(credsJSON))
+	}
+	if conf.Impersonate.ServiceAccount == "" {
+		if len(conf.Impersonate.Delegates) > 0 {
+			return nil, errors.New("credentials impersonate delegates require a service_account")
+		}
+		return opts, nil
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: conf.Impersonate.ServiceAccount,
+		Scopes:          []string{gcpCloudPlatformScope},
+		Delegates:       conf.Impersonate.Delegates,
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate service account: %w", err)
+	}
+	return []option.ClientOption{option.WithTokenSource(ts)}, nil
+}
@@ -0,0 +1,18 @@
+package credentials
+
+import "github.com/benthosdev/benthos/v4/internal/docs"
+
+// FieldSpec returns the documentation spec for GCP credentials fields.
+func FieldSpec() docs.FieldSpec {
+	return docs.FieldObject("credentials", "Optional manual configuration of Google Cloud credentials to use, when omitted [application default credentials](https://cloud.google.com/docs/authentication/production) are used. More information can be found [in this document](/docs/guides/cloud/gcp).").
+		Advanced().
+		AtVersion("4.2.0").
+		WithChildren(
+			docs.FieldString("file", "The path of a credentials file to use, which can either be a service account key or a [workload identity federation](https://cloud.google.com/iam/docs/workload-identity-federation) configuration that exchanges OIDC or AWS credentials for Google Cloud credentials.").HasDefault(""),
+			docs.FieldString("json", "The contents of a credentials file to use, as an alternative to `file`.").HasDefault(""),
+			docs.FieldObject("impersonate", "Optionally impersonate a service account with the credentials.").WithChildren(
+				docs.FieldString("service_account", "The email address of a service account to impersonate, the credentials must be granted the Service Account Token Creator role on it.", "foo@bar.iam.gserviceaccount.com").HasDefault(""),
+				docs.FieldString("delegates", "An optional chain of service accounts to delegate through, where each service account must be granted the Service Account Token Creator role on the next, and the last on `service_account`.").Array().HasDefault([]interface{}{}),
+			),
+		)
+}
@@ -0,0 +1,28 @@
+package credentials
+
+// ImpersonateConfig contains configuration fields for impersonating a service
+// account.
+type ImpersonateConfig struct {
+	ServiceAccount string   `json:"service_account" yaml:"service_account"`
+	Delegates      []string `json:"delegates" yaml:"delegates"`
+}
+
+// Config contains configuration fields for authenticating with Google Cloud.
+// This config is common across any GCP components.
+type Config struct {
+	File        string            `json:"file" yaml:"file"`
+	JSON        string            `json:"json" yaml:"json"`
+	Impersonate ImpersonateConfig `json:"impersonate" yaml:"impersonate"`
+}
+
+// NewConfig returns a Config with default values.
+func NewConfig() Config {
+	return Config{
+		File: "",
+		JSON: "",
+		Impersonate: ImpersonateConfig{
+			ServiceAccount: "",
+			Delegates:      []string{},
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package gcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestCredentialsFromParsed(t *testing.T) {
+	spec := service.NewConfigSpec().Field(credentialsField())
+
+	pConf, err := spec.ParseYAML(`
+credentials:
+  file: /tmp/creds.json
+  impersonate:
+    service_account: foo@bar.iam.gserviceaccount.com
+    delegates: [ baz@bar.iam.gserviceaccount.com ]
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := credentialsFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/creds.json", conf.File)
+	assert.Equal(t, "", conf.JSON)
+	assert.Equal(t, "foo@bar.iam.gserviceaccount.com", conf.Impersonate.ServiceAccount)
+	assert.Equal(t, []string{"baz@bar.iam.gserviceaccount.com"}, conf.Impersonate.Delegates)
+
+	pConf, err = spec.ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+
+	conf, err = credentialsFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, credentials.NewConfig(), conf)
+}
+
+func TestClientOptionsFromCredentials(t *testing.T) {
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	require.NoError(t, os.WriteFile(credsPath, []byte(`{"type":"external_account"}`), 0o644))
+
+	tests := []struct {
+		name    string
+		conf    func(c *credentials.Config)
+		options int
+		errs    bool
+	}{
+		{
+			name:    "defaults",
+			conf:    func(c *credentials.Config) {},
+			options: 0,
+		},
+		{
+			name: "file",
+			conf: func(c *credentials.Config) {
+				c.File = credsPath
+			},
+			options: 1,
+		},
+		{
+			name: "json",
+			conf: func(c *credentials.Config) {
+				c.JSON = `{"type":"external_account"}`
+			},
+			options: 1,
+		},
+		{
+			name: "missing file",
+			conf: func(c *credentials.Config) {
+				c.File = filepath.Join(t.TempDir(), "nope.json")
+			},
+			errs: true,
+		},
+		{
+			name: "file and json",
+			conf: func(c *credentials.Config) {
+				c.File = credsPath
+				c.JSON = `{"type":"external_account"}`
+			},
+			errs: true,
+		},
+		{
+			name: "delegates without service account",
+			conf: func(c *credentials.Config) {
+				c.Impersonate.Delegates = []string{"baz@bar.iam.gserviceaccount.com"}
+			},
+			errs: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := credentials.NewConfig()
+			test.conf(&conf)
+
+			opts, err := clientOptionsFromCredentials(context.Background(), conf)
+			if test.errs {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, opts, test.options)
+		})
+	}
+}
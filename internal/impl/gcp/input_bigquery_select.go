@@ -3,11 +3,13 @@ package gcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"cloud.google.com/go/bigquery"
 	"google.golang.org/api/iterator"
 
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/bloblang"
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -18,6 +20,8 @@ type bigQuerySelectInputConfig struct {
 	queryParts  *bqQueryParts
 	argsMapping *bloblang.Executor
 	jobLabels   map[string]string
+	directRead  bool
+	credentials credentials.Config
 }
 
 func bigQuerySelectInputConfigFromParsed(inConf *service.ParsedConfig) (conf bigQuerySelectInputConfig, err error) {
@@ -66,6 +70,17 @@ func bigQuerySelectInputConfigFromParsed(inConf *service.ParsedConfig) (conf big
 		}
 	}
 
+	if conf.directRead, err = inConf.FieldBool("direct_read"); err != nil {
+		return
+	}
+	if conf.credentials, err = credentialsFromParsed(inConf); err != nil {
+		return
+	}
+	if conf.directRead && (queryParts.where != "" || queryParts.prefix != "" || queryParts.suffix != "" || conf.argsMapping != nil) {
+		err = errors.New("fields where, args_mapping, prefix and suffix cannot be set when direct_read is enabled")
+		return
+	}
+
 	return
 }
 
@@ -75,7 +90,12 @@ func newBigQuerySelectInputConfig() *service.ConfigSpec {
 		Version("3.63.0").
 		Categories("Services", "GCP").
 		Summary("Executes a `SELECT` query against BigQuery and creates a message for each row received.").
-		Description(`Once the rows from the query are exhausted, this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a [sequence](/docs/components/inputs/sequence) to execute).`).
+		Description(`
+Once the rows from the query are exhausted, this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a [sequence](/docs/components/inputs/sequence) to execute).
+
+### Reading Tables Directly
+
+When `+"`direct_read`"+` is enabled the rows of the table are read directly rather than by running a query job, which means reading an entire table does not incur query costs. In this mode the fields `+"`where`, `args_mapping`, `prefix` and `suffix`"+` cannot be used, and the columns of each row are limited to those listed in `+"`columns`"+`, where a column of `+"`*`"+` selects them all.`).
 		Field(service.NewStringField("project").Description("GCP project where the query job will execute.")).
 		Field(service.NewStringField("table").Description("Fully-qualified BigQuery table name to query.").Example("bigquery-public-data.samples.shakespeare")).
 		Field(service.NewStringListField("columns").Description("A list of columns to query.")).
@@ -96,6 +116,12 @@ func newBigQuerySelectInputConfig() *service.ConfigSpec {
 		Field(service.NewStringField("suffix").
 			Description("An optional suffix to append to the select query.").
 			Optional()).
+		Field(service.NewBoolField("direct_read").
+			Description("Whether to read the rows of the table directly instead of executing a query job. When enabled the fields `where`, `args_mapping`, `prefix` and `suffix` cannot be set.").
+			Version("4.2.0").
+			Advanced().
+			Default(false)).
+		Field(credentialsField()).
 		Example("Word counts",
 			`
 Here we query the public corpus of Shakespeare's works to generate a stream of the top 10 words that are 3 or more characters long:`,
@@ -149,13 +175,26 @@ func (inp *bigQuerySelectInput) Connect(ctx context.Context) error {
 	jobctx, _ := inp.shutdownSig.CloseAtLeisureCtx(context.Background())
 
 	if inp.client == nil {
-		client, err := bigquery.NewClient(jobctx, inp.config.project)
+		opts, err := clientOptionsFromCredentials(jobctx, inp.config.credentials)
+		if err != nil {
+			return err
+		}
+		client, err := bigquery.NewClient(jobctx, inp.config.project, opts...)
 		if err != nil {
 			return fmt.Errorf("failed to create bigquery client: %w", err)
 		}
 		inp.client = wrapBQClient(client, inp.logger)
 	}
 
+	if inp.config.directRead {
+		iter, err := inp.client.ReadTable(jobctx, inp.config.queryParts.table, inp.config.queryParts.columns)
+		if err != nil {
+			return err
+		}
+		inp.iterator = iter
+		return nil
+	}
+
 	var args []interface{}
 	argsMapping := inp.config.argsMapping
 
@@ -165,3 +165,66 @@ func TestGCPBigQuerySelectInput_ConnectError(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestGCPBigQuerySelectInput_DirectRead(t *testing.T) {
+	spec := newBigQuerySelectInputConfig()
+
+	parsed, err := spec.ParseYAML(`
+project: job-project
+table: bigquery-public-data.samples.shakespeare
+columns: [ word, word_count ]
+direct_read: true
+`, nil)
+	require.NoError(t, err)
+
+	inp, err := newBigQuerySelectInput(parsed, nil)
+	require.NoError(t, err)
+
+	iter := &mockBQIterator{
+		rows: []string{
+			`{"corpus":"hamlet","word":"the","word_count":1}`,
+			`{"corpus":"hamlet","word":"and","word_count":2}`,
+		},
+	}
+
+	mockClient := &mockBQClient{}
+	mockClient.On("ReadTable", mock.Anything, "bigquery-public-data.samples.shakespeare", []string{"word", "word_count"}).
+		Return(&columnsIterator{wrapped: iter, columns: []string{"word", "word_count"}}, nil)
+	inp.client = mockClient
+
+	require.NoError(t, inp.Connect(context.Background()))
+
+	for _, exp := range []string{
+		`{"word":"the","word_count":1}`,
+		`{"word":"and","word_count":2}`,
+	} {
+		msg, ack, err := inp.Read(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, ack(context.Background(), nil))
+
+		bs, err := msg.AsBytes()
+		require.NoError(t, err)
+		require.Equal(t, exp, string(bs))
+	}
+
+	_, _, err = inp.Read(context.Background())
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGCPBigQuerySelectInput_DirectReadBadConfig(t *testing.T) {
+	spec := newBigQuerySelectInputConfig()
+
+	parsed, err := spec.ParseYAML(`
+project: job-project
+table: bigquery-public-data.samples.shakespeare
+columns: [ word ]
+where: length(word) >= 3
+direct_read: true
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newBigQuerySelectInput(parsed, nil)
+	require.Error(t, err)
+}
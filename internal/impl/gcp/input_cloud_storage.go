@@ -20,6 +20,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 )
@@ -73,6 +74,7 @@ services. You can find out more [in this document](/docs/guides/cloud/gcp).`,
 			docs.FieldString("prefix", "An optional path prefix, if set only objects with the prefix are consumed."),
 			codec.ReaderDocs,
 			docs.FieldBool("delete_objects", "Whether to delete downloaded objects from the bucket once they are processed.").Advanced(),
+			credentials.FieldSpec(),
 		).ChildDefaultAndTypesFromStruct(input.NewGCPCloudStorageConfig()),
 	})
 	if err != nil {
@@ -235,8 +237,12 @@ func newGCPCloudStorageInput(conf input.GCPCloudStorageConfig, log log.Modular,
 // ConnectWithContext attempts to establish a connection to the target Google
 // Cloud Storage bucket.
 func (g *gcpCloudStorageInput) ConnectWithContext(ctx context.Context) error {
-	var err error
-	g.client, err = storage.NewClient(context.Background())
+	opts, err := clientOptionsFromCredentials(context.Background(), g.conf.Credentials)
+	if err != nil {
+		return err
+	}
+
+	g.client, err = storage.NewClient(context.Background(), opts...)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 )
@@ -38,7 +40,25 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### Ack Deadlines
+
+Whilst a message is being processed its ack deadline is extended
+automatically, for up to ` + "`max_extension`" + ` in total, so that it isn't
+redelivered before it is acknowledged. Each extension is by default based on
+the time taken to acknowledge prior messages, which can be capped with
+` + "`max_extension_period`" + ` in order to keep deadlines short and have
+messages of a crashed consumer redelivered quickly.
+
+### Exactly-Once Delivery
+
+Subscriptions with exactly-once delivery enabled are supported, in which case
+the acknowledgement of each message is only considered complete once Pub/Sub
+has confirmed it. Acknowledgements that are rejected, for example because the
+ack deadline of the message expired before it was acknowledged, are logged as
+errors along with the status returned by Pub/Sub, and the message is then
+redelivered.`,
 		Categories: []string{
 			"Services",
 			"GCP",
@@ -49,6 +69,9 @@ You can access these metadata fields using
 			docs.FieldBool("sync", "Enable synchronous pull mode."),
 			docs.FieldInt("max_outstanding_messages", "The maximum number of outstanding pending messages to be consumed at a given time."),
 			docs.FieldInt("max_outstanding_bytes", "The maximum number of outstanding pending messages to be consumed measured in bytes."),
+			docs.FieldString("max_extension", "The maximum period for which the [ack deadline](#ack-deadlines) of a message is extended whilst it is being processed.").Advanced().AtVersion("4.2.0"),
+			docs.FieldString("max_extension_period", "The maximum period by which the [ack deadline](#ack-deadlines) of a message is extended at a time, which is calculated by the client when empty.", "30s").Advanced().AtVersion("4.2.0"),
+			credentials.FieldSpec(),
 		).ChildDefaultAndTypesFromStruct(input.NewGCPPubSubConfig()),
 	})
 	if err != nil {
@@ -68,6 +91,9 @@ func newGCPPubSubInput(conf input.Config, mgr bundle.NewManagement, log log.Modu
 type gcpPubSubReader struct {
 	conf input.GCPPubSubConfig
 
+	maxExtension       time.Duration
+	maxExtensionPeriod time.Duration
+
 	subscription *pubsub.Subscription
 	msgsChan     chan *pubsub.Message
 	closeFunc    context.CancelFunc
@@ -79,14 +105,34 @@ type gcpPubSubReader struct {
 }
 
 func newGCPPubSubReader(conf input.GCPPubSubConfig, log log.Modular, stats metrics.Type) (*gcpPubSubReader, error) {
-	client, err := pubsub.NewClient(context.Background(), conf.ProjectID)
+	var maxExtension, maxExtensionPeriod time.Duration
+	var err error
+	if conf.MaxExtension != "" {
+		if maxExtension, err = time.ParseDuration(conf.MaxExtension); err != nil {
+			return nil, fmt.Errorf("failed to parse max_extension: %w", err)
+		}
+	}
+	if conf.MaxExtensionPeriod != "" {
+		if maxExtensionPeriod, err = time.ParseDuration(conf.MaxExtensionPeriod); err != nil {
+			return nil, fmt.Errorf("failed to parse max_extension_period: %w", err)
+		}
+	}
+
+	opts, err := clientOptionsFromCredentials(context.Background(), conf.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := pubsub.NewClient(context.Background(), conf.ProjectID, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &gcpPubSubReader{
-		conf:   conf,
-		log:    log,
-		client: client,
+		conf:               conf,
+		maxExtension:       maxExtension,
+		maxExtensionPeriod: maxExtensionPeriod,
+		log:                log,
+		client:             client,
 	}, nil
 }
 
@@ -101,6 +147,10 @@ func (c *gcpPubSubReader) ConnectWithContext(ignored context.Context) error {
 	sub.ReceiveSettings.MaxOutstandingMessages = c.conf.MaxOutstandingMessages
 	sub.ReceiveSettings.MaxOutstandingBytes = c.conf.MaxOutstandingBytes
 	sub.ReceiveSettings.Synchronous = c.conf.Sync
+	if c.maxExtension > 0 {
+		sub.ReceiveSettings.MaxExtension = c.maxExtension
+	}
+	sub.ReceiveSettings.MaxExtensionPeriod = c.maxExtensionPeriod
 
 	subCtx, cancel := context.WithCancel(context.Background())
 	msgsChan := make(chan *pubsub.Message, 1)
@@ -163,15 +213,41 @@ func (c *gcpPubSubReader) ReadWithContext(ctx context.Context) (*message.Batch,
 	msg.Append(part)
 
 	return msg, func(ctx context.Context, res error) error {
+		// The results of acks and nacks are only tracked for subscriptions
+		// with exactly-once delivery, and for other subscriptions the
+		// WithResult variants resolve immediately without acknowledging the
+		// message. Therefore we also call the plain variants, which are a
+		// no-op when the message has already been acknowledged.
+		var ackRes *pubsub.AckResult
 		if res != nil {
+			ackRes = gmsg.NackWithResult()
 			gmsg.Nack()
 		} else {
+			ackRes = gmsg.AckWithResult()
 			gmsg.Ack()
 		}
+		status, err := ackRes.Get(ctx)
+		if err != nil {
+			return fmt.Errorf("%v: %w", ackStatusString(status), err)
+		}
 		return nil
 	}, nil
 }
 
+func ackStatusString(status pubsub.AcknowledgeStatus) string {
+	switch status {
+	case pubsub.AcknowledgeStatusSuccess:
+		return "success"
+	case pubsub.AcknowledgeStatusPermissionDenied:
+		return "permission denied"
+	case pubsub.AcknowledgeStatusFailedPrecondition:
+		return "failed precondition"
+	case pubsub.AcknowledgeStatusInvalidAckID:
+		return "invalid ack ID"
+	}
+	return "unknown status"
+}
+
 func (c *gcpPubSubReader) CloseAsync() {
 	c.subMut.Lock()
 	if c.closeFunc != nil {
@@ -0,0 +1,110 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+func TestGCPPubSubInputAcks(t *testing.T) {
+	tests := []struct {
+		name        string
+		exactlyOnce bool
+		ackErr      bool
+		errContains string
+		acks        int
+	}{
+		{
+			name: "at least once",
+			acks: 1,
+		},
+		{
+			name:        "exactly once",
+			exactlyOnce: true,
+			acks:        1,
+		},
+		{
+			name:        "exactly once rejected",
+			exactlyOnce: true,
+			ackErr:      true,
+			errContains: "permission denied",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+			defer done()
+
+			var opts []pstest.ServerReactorOption
+			if test.ackErr {
+				opts = append(opts, pstest.WithErrorInjection("Acknowledge", codes.PermissionDenied, "nope"))
+			}
+			srv := pstest.NewServer(opts...)
+			defer srv.Close()
+
+			client, err := pubsub.NewClient(ctx, "benthos-test-project",
+				option.WithEndpoint(srv.Addr),
+				option.WithoutAuthentication(),
+				option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+			)
+			require.NoError(t, err)
+			defer client.Close()
+
+			topic, err := client.CreateTopic(ctx, "foo")
+			require.NoError(t, err)
+			_, err = client.CreateSubscription(ctx, "foo-sub", pubsub.SubscriptionConfig{
+				Topic:                     topic,
+				EnableExactlyOnceDelivery: test.exactlyOnce,
+			})
+			require.NoError(t, err)
+
+			msgID := srv.Publish("projects/benthos-test-project/topics/foo", []byte("hello world"), map[string]string{
+				"foo": "bar",
+			})
+
+			conf := input.NewGCPPubSubConfig()
+			conf.ProjectID = "benthos-test-project"
+			conf.SubscriptionID = "foo-sub"
+
+			reader := &gcpPubSubReader{
+				conf:   conf,
+				client: client,
+				log:    log.Noop(),
+			}
+			require.NoError(t, reader.ConnectWithContext(ctx))
+			defer reader.CloseAsync()
+
+			msg, ackFn, err := reader.ReadWithContext(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 1, msg.Len())
+			assert.Equal(t, "hello world", string(msg.Get(0).Get()))
+			assert.Equal(t, "bar", msg.Get(0).MetaGet("foo"))
+
+			err = ackFn(ctx, nil)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				return srv.Message(msgID).Acks == test.acks
+			}, time.Second*5, time.Millisecond*10)
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
 
@@ -62,6 +63,8 @@ type gcpBigQueryOutputConfig struct {
 
 	// CSV options
 	CSVOptions gcpBigQueryCSVConfig
+
+	Credentials credentials.Config
 }
 
 func gcpBigQueryOutputConfigFromParsed(conf *service.ParsedConfig) (gconf gcpBigQueryOutputConfig, err error) {
@@ -101,14 +104,15 @@ func gcpBigQueryOutputConfigFromParsed(conf *service.ParsedConfig) (gconf gcpBig
 	if gconf.CSVOptions, err = gcpBigQueryCSVConfigFromParsed(conf.Namespace("csv")); err != nil {
 		return
 	}
+	gconf.Credentials, err = credentialsFromParsed(conf)
 	return
 }
 
 type gcpBQClientURL string
 
-func (g gcpBQClientURL) NewClient(ctx context.Context, projectID string) (*bigquery.Client, error) {
+func (g gcpBQClientURL) NewClient(ctx context.Context, projectID string, opts ...option.ClientOption) (*bigquery.Client, error) {
 	if g == "" {
-		return bigquery.NewClient(ctx, projectID)
+		return bigquery.NewClient(ctx, projectID, opts...)
 	}
 	return bigquery.NewClient(ctx, projectID, option.WithoutAuthentication(), option.WithEndpoint(string(g)))
 }
@@ -209,7 +213,8 @@ For the CSV format when the field `+"`csv.header`"+` is specified a header row w
 				Advanced().
 				Default(1),
 		).Description("Specify how CSV data should be interpretted.")).
-		Field(service.NewBatchPolicyField("batching"))
+		Field(service.NewBatchPolicyField("batching")).
+		Field(credentialsField())
 }
 
 func init() {
@@ -302,8 +307,13 @@ func (g *gcpBigQueryOutput) Connect(ctx context.Context) (err error) {
 	g.connMut.Lock()
 	defer g.connMut.Unlock()
 
+	var opts []option.ClientOption
+	if opts, err = clientOptionsFromCredentials(context.Background(), g.conf.Credentials); err != nil {
+		return
+	}
+
 	var client *bigquery.Client
-	if client, err = g.clientURL.NewClient(context.Background(), g.conf.ProjectID); err != nil {
+	if client, err = g.clientURL.NewClient(context.Background(), g.conf.ProjectID, opts...); err != nil {
 		err = fmt.Errorf("error creating big query client: %w", err)
 		return
 	}
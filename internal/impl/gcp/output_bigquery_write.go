@@ -0,0 +1,516 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/bigquery/storage/managedwriter"
+	"cloud.google.com/go/bigquery/storage/managedwriter/adapt"
+	"google.golang.org/api/option"
+	storagepb "google.golang.org/genproto/googleapis/cloud/bigquery/storage/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	bqwStreamTypePending   = "pending"
+	bqwStreamTypeCommitted = "committed"
+)
+
+type gcpBigQueryWriteOutputConfig struct {
+	ProjectID           string
+	DatasetID           string
+	TableID             string
+	StreamType          string
+	CreateDisposition   string
+	IgnoreUnknownValues bool
+	Credentials         credentials.Config
+}
+
+func gcpBigQueryWriteOutputConfigFromParsed(conf *service.ParsedConfig) (gconf gcpBigQueryWriteOutputConfig, err error) {
+	if gconf.ProjectID, err = conf.FieldString("project"); err != nil {
+		return
+	}
+	if gconf.ProjectID == "" {
+		gconf.ProjectID = bigquery.DetectProjectID
+	}
+	if gconf.DatasetID, err = conf.FieldString("dataset"); err != nil {
+		return
+	}
+	if gconf.TableID, err = conf.FieldString("table"); err != nil {
+		return
+	}
+	if gconf.StreamType, err = conf.FieldString("stream_type"); err != nil {
+		return
+	}
+	if gconf.CreateDisposition, err = conf.FieldString("create_disposition"); err != nil {
+		return
+	}
+	if gconf.IgnoreUnknownValues, err = conf.FieldBool("ignore_unknown_values"); err != nil {
+		return
+	}
+	gconf.Credentials, err = credentialsFromParsed(conf)
+	return
+}
+
+func gcpBigQueryWriteConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("GCP", "Services").
+		Version("4.2.0").
+		Summary(`Writes messages as rows to a Google Cloud BigQuery table using the Storage Write API.`).
+		Description(output.Description(true, true, `
+## Credentials
+
+By default Benthos will use a shared credentials file when connecting to GCP services. You can find out more [in this document](/docs/guides/cloud/gcp).
+
+## Rows
+
+Each message must be a JSON object, which is converted into a row by matching its fields against the columns of the table. Rows are encoded as protocol buffers with a schema derived from the table, where columns of type `+"`TIMESTAMP`"+` expect an integer of microseconds since the unix epoch and columns of type `+"`DATE`"+` expect an integer of days since the unix epoch.
+
+When the table does not exist and `+"`create_disposition`"+` is `+"`CREATE_IF_NEEDED`"+` the table is created with a schema derived from the structure of the first message written to it, where numbers without a fractional part become `+"`INTEGER`"+` columns, other numbers become `+"`FLOAT`"+` columns, objects become `+"`RECORD`"+` columns and arrays become repeated columns. Fields with null values or empty arrays are omitted from the derived schema.
+
+## Delivery Guarantees
+
+With a `+"`stream_type`"+` of `+"`pending`"+` each batch of messages is appended to its own pending stream, which is only committed to the table once all of the rows of the batch have been appended. Messages are acknowledged once the stream is committed, and a batch that fails is retried with a new stream, which means the rows of a batch are written exactly once, unless a commit succeeds without its response being received.
+
+With a `+"`stream_type`"+` of `+"`committed`"+` rows are appended to a long lived stream and are visible as soon as they are written, which results in lower latency. Rows are appended at explicit offsets in order to prevent the client from duplicating them, but batches that are retried after a failed append are written to a new stream and may therefore be duplicated.`)).
+		Field(service.NewStringField("project").Description("The project ID of the dataset to insert data to. If not set, it will be inferred from the credentials or read from the GOOGLE_CLOUD_PROJECT environment variable.").Default("")).
+		Field(service.NewStringField("dataset").Description("The BigQuery Dataset ID.")).
+		Field(service.NewStringField("table").Description("The table to insert messages to.")).
+		Field(service.NewStringAnnotatedEnumField("stream_type", map[string]string{
+			bqwStreamTypePending:   "Append each batch to a pending stream that is committed once the batch is written, providing exactly once delivery of batches.",
+			bqwStreamTypeCommitted: "Append rows to a committed stream where they are visible immediately, providing at least once delivery.",
+		}).
+			Description("The type of write stream to append rows to.").
+			Default(bqwStreamTypePending)).
+		Field(service.NewIntField("max_in_flight").
+			Description("The maximum number of message batches to have in flight at a given time. Increase this to improve throughput.").
+			Default(64)).
+		Field(service.NewStringEnumField("create_disposition", string(bigquery.CreateIfNeeded), string(bigquery.CreateNever)).
+			Description("Specifies the circumstances under which the destination table will be created. If CREATE_IF_NEEDED is used the table is created with a schema derived from the first message written when it does not already exist. The CREATE_NEVER option ensures the table must already exist.").
+			Advanced().
+			Default(string(bigquery.CreateNever))).
+		Field(service.NewBoolField("ignore_unknown_values").
+			Description("Causes fields of messages that do not match a column of the table to be ignored. If this field is set to false (the default value), messages containing unknown fields are rejected.").
+			Advanced().
+			Default(false)).
+		Field(service.NewBatchPolicyField("batching")).
+		Field(credentialsField())
+}
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"gcp_bigquery_write", gcpBigQueryWriteConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (output service.BatchOutput, batchPol service.BatchPolicy, maxInFlight int, err error) {
+			if batchPol, err = conf.FieldBatchPolicy("batching"); err != nil {
+				return
+			}
+			if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
+				return
+			}
+			var gconf gcpBigQueryWriteOutputConfig
+			if gconf, err = gcpBigQueryWriteOutputConfigFromParsed(conf); err != nil {
+				return
+			}
+			output = newGCPBigQueryWriteOutput(gconf, mgr.Logger())
+			return
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// bqwSchema is the protobuf representation of a table schema that rows are
+// encoded with.
+type bqwSchema struct {
+	message    protoreflect.MessageDescriptor
+	descriptor *descriptorpb.DescriptorProto
+}
+
+func newBQWSchema(schema bigquery.Schema) (*bqwSchema, error) {
+	tableSchema, err := adapt.BQSchemaToStorageTableSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert table schema: %w", err)
+	}
+	desc, err := adapt.StorageSchemaToProto2Descriptor(tableSchema, "root")
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive protobuf descriptor from table schema: %w", err)
+	}
+	message, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("expected a message descriptor, got %T", desc)
+	}
+	descriptor, err := adapt.NormalizeDescriptor(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalise protobuf descriptor: %w", err)
+	}
+	return &bqwSchema{
+		message:    message,
+		descriptor: descriptor,
+	}, nil
+}
+
+// encodeRows encodes a batch of JSON messages as serialised protobuf rows.
+func (s *bqwSchema) encodeRows(batch service.MessageBatch, discardUnknown bool) ([][]byte, error) {
+	opts := protojson.UnmarshalOptions{DiscardUnknown: discardUnknown}
+	rows := make([][]byte, len(batch))
+	for i, msg := range batch {
+		msgBytes, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		row := dynamicpb.NewMessage(s.message)
+		if err = opts.Unmarshal(msgBytes, row); err != nil {
+			return nil, fmt.Errorf("failed to convert message %v to a row: %w", i, err)
+		}
+		if rows[i], err = proto.Marshal(row); err != nil {
+			return nil, fmt.Errorf("failed to encode message %v as a row: %w", i, err)
+		}
+	}
+	return rows, nil
+}
+
+// bqwSchemaFromStructure derives a table schema from the structure of a
+// message.
+func bqwSchemaFromStructure(v interface{}) (bigquery.Schema, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %T", v)
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var schema bigquery.Schema
+	for _, k := range keys {
+		field, err := bqwFieldFromValue(k, obj[k])
+		if err != nil {
+			return nil, err
+		}
+		if field != nil {
+			schema = append(schema, field)
+		}
+	}
+	return schema, nil
+}
+
+func bqwFieldFromValue(name string, v interface{}) (*bigquery.FieldSchema, error) {
+	field := &bigquery.FieldSchema{Name: name}
+	switch t := v.(type) {
+	case string:
+		field.Type = bigquery.StringFieldType
+	case bool:
+		field.Type = bigquery.BooleanFieldType
+	case json.Number:
+		field.Type = bigquery.FloatFieldType
+		if _, err := t.Int64(); err == nil {
+			field.Type = bigquery.IntegerFieldType
+		}
+	case float64, float32:
+		field.Type = bigquery.FloatFieldType
+	case int, int64, int32, uint64, uint32:
+		field.Type = bigquery.IntegerFieldType
+	case map[string]interface{}:
+		schema, err := bqwSchemaFromStructure(t)
+		if err != nil {
+			return nil, err
+		}
+		if len(schema) == 0 {
+			return nil, nil
+		}
+		field.Type = bigquery.RecordFieldType
+		field.Schema = schema
+	case []interface{}:
+		if len(t) == 0 {
+			return nil, nil
+		}
+		if _, isArray := t[0].([]interface{}); isArray {
+			return nil, fmt.Errorf("field %v: nested arrays are not supported", name)
+		}
+		elem, err := bqwFieldFromValue(name, t[0])
+		if elem == nil || err != nil {
+			return elem, err
+		}
+		elem.Repeated = true
+		return elem, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("field %v: unsupported value type %T", name, v)
+	}
+	return field, nil
+}
+
+//------------------------------------------------------------------------------
+
+type gcpBigQueryWriteOutput struct {
+	conf gcpBigQueryWriteOutputConfig
+
+	connMut     sync.RWMutex
+	client      *bigquery.Client
+	writeClient *managedwriter.Client
+	tableParent string
+	schema      *bqwSchema
+
+	// The stream and next offset to append rows to when using committed
+	// streams.
+	streamMut sync.Mutex
+	stream    *managedwriter.ManagedStream
+	offset    int64
+
+	log *service.Logger
+}
+
+func newGCPBigQueryWriteOutput(conf gcpBigQueryWriteOutputConfig, log *service.Logger) *gcpBigQueryWriteOutput {
+	return &gcpBigQueryWriteOutput{
+		conf: conf,
+		log:  log,
+	}
+}
+
+func (g *gcpBigQueryWriteOutput) Connect(ctx context.Context) (err error) {
+	g.connMut.Lock()
+	defer g.connMut.Unlock()
+
+	if g.writeClient != nil {
+		return nil
+	}
+
+	var opts []option.ClientOption
+	if opts, err = clientOptionsFromCredentials(context.Background(), g.conf.Credentials); err != nil {
+		return
+	}
+
+	var client *bigquery.Client
+	if client, err = bigquery.NewClient(context.Background(), g.conf.ProjectID, opts...); err != nil {
+		err = fmt.Errorf("error creating big query client: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			client.Close()
+		}
+	}()
+
+	var schema *bqwSchema
+	table := client.DatasetInProject(client.Project(), g.conf.DatasetID).Table(g.conf.TableID)
+	if meta, merr := table.Metadata(ctx); merr != nil {
+		if !hasStatusCode(merr, http.StatusNotFound) {
+			err = fmt.Errorf("error checking table existence: %w", merr)
+			return
+		}
+		if g.conf.CreateDisposition == string(bigquery.CreateNever) {
+			err = fmt.Errorf("table does not exist: %v", g.conf.TableID)
+			return
+		}
+		// The table is created with a schema derived from the first batch
+		// written.
+	} else if schema, err = newBQWSchema(meta.Schema); err != nil {
+		return
+	}
+
+	var writeClient *managedwriter.Client
+	if writeClient, err = managedwriter.NewClient(context.Background(), client.Project(), opts...); err != nil {
+		err = fmt.Errorf("error creating big query write client: %w", err)
+		return
+	}
+
+	g.client = client
+	g.writeClient = writeClient
+	g.schema = schema
+	g.tableParent = fmt.Sprintf("projects/%v/datasets/%v/tables/%v", client.Project(), g.conf.DatasetID, g.conf.TableID)
+	g.log.Infof("Writing messages as rows to GCP BigQuery: %v:%v:%v\n", client.Project(), g.conf.DatasetID, g.conf.TableID)
+	return nil
+}
+
+// createTable creates the destination table with a schema derived from the
+// first message of a batch, unless the schema is already known.
+func (g *gcpBigQueryWriteOutput) createTable(ctx context.Context, batch service.MessageBatch) (*bqwSchema, error) {
+	g.connMut.Lock()
+	defer g.connMut.Unlock()
+
+	if g.schema != nil {
+		return g.schema, nil
+	}
+	if g.client == nil {
+		return nil, service.ErrNotConnected
+	}
+
+	structured, err := batch[0].AsStructured()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as JSON: %w", err)
+	}
+	tableSchema, err := bqwSchemaFromStructure(structured)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive table schema from message: %w", err)
+	}
+
+	table := g.client.DatasetInProject(g.client.Project(), g.conf.DatasetID).Table(g.conf.TableID)
+	if err = table.Create(ctx, &bigquery.TableMetadata{Schema: tableSchema}); err != nil {
+		if !hasStatusCode(err, http.StatusConflict) {
+			return nil, fmt.Errorf("error creating table: %w", err)
+		}
+		// Another writer created the table first, in which case we adopt its
+		// schema.
+		meta, err := table.Metadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading table schema: %w", err)
+		}
+		tableSchema = meta.Schema
+	}
+
+	if g.schema, err = newBQWSchema(tableSchema); err != nil {
+		return nil, err
+	}
+	return g.schema, nil
+}
+
+func (g *gcpBigQueryWriteOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	g.connMut.RLock()
+	writeClient, schema := g.writeClient, g.schema
+	g.connMut.RUnlock()
+	if writeClient == nil {
+		return service.ErrNotConnected
+	}
+
+	var err error
+	if schema == nil {
+		if schema, err = g.createTable(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	rows, err := schema.encodeRows(batch, g.conf.IgnoreUnknownValues)
+	if err != nil {
+		return err
+	}
+
+	if g.conf.StreamType == bqwStreamTypeCommitted {
+		return g.writeCommitted(ctx, writeClient, schema, rows)
+	}
+	return g.writePending(ctx, writeClient, schema, rows)
+}
+
+// writePending appends rows to a new pending stream which is committed once
+// all rows have been appended, and therefore a failed batch leaves no rows in
+// the table.
+func (g *gcpBigQueryWriteOutput) writePending(ctx context.Context, client *managedwriter.Client, schema *bqwSchema, rows [][]byte) error {
+	stream, err := client.NewManagedStream(ctx,
+		managedwriter.WithDestinationTable(g.tableParent),
+		managedwriter.WithType(managedwriter.PendingStream),
+		managedwriter.WithSchemaDescriptor(schema.descriptor),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pending stream: %w", err)
+	}
+	defer stream.Close()
+
+	res, err := stream.AppendRows(ctx, rows, managedwriter.WithOffset(0))
+	if err != nil {
+		return fmt.Errorf("failed to append rows: %w", err)
+	}
+	if _, err = res.GetResult(ctx); err != nil {
+		return fmt.Errorf("failed to append rows: %w", err)
+	}
+	if _, err = stream.Finalize(ctx); err != nil {
+		return fmt.Errorf("failed to finalise pending stream: %w", err)
+	}
+
+	resp, err := client.BatchCommitWriteStreams(ctx, &storagepb.BatchCommitWriteStreamsRequest{
+		Parent:       g.tableParent,
+		WriteStreams: []string{stream.StreamName()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit pending stream: %w", err)
+	}
+	if streamErrs := resp.GetStreamErrors(); len(streamErrs) > 0 {
+		return fmt.Errorf("failed to commit pending stream: %v", streamErrs[0].GetErrorMessage())
+	}
+	return nil
+}
+
+// writeCommitted appends rows to a shared committed stream at explicit
+// offsets. When an append fails the stream is abandoned, as the offsets of any
+// appends that followed it are no longer valid.
+func (g *gcpBigQueryWriteOutput) writeCommitted(ctx context.Context, client *managedwriter.Client, schema *bqwSchema, rows [][]byte) error {
+	g.streamMut.Lock()
+	if g.stream == nil {
+		stream, err := client.NewManagedStream(ctx,
+			managedwriter.WithDestinationTable(g.tableParent),
+			managedwriter.WithType(managedwriter.CommittedStream),
+			managedwriter.WithSchemaDescriptor(schema.descriptor),
+		)
+		if err != nil {
+			g.streamMut.Unlock()
+			return fmt.Errorf("failed to create committed stream: %w", err)
+		}
+		g.stream, g.offset = stream, 0
+	}
+	stream := g.stream
+	res, err := stream.AppendRows(ctx, rows, managedwriter.WithOffset(g.offset))
+	if err == nil {
+		g.offset += int64(len(rows))
+	}
+	g.streamMut.Unlock()
+
+	if err == nil {
+		_, err = res.GetResult(ctx)
+	}
+	if err != nil {
+		g.abandonStream(stream)
+		return fmt.Errorf("failed to append rows: %w", err)
+	}
+	return nil
+}
+
+func (g *gcpBigQueryWriteOutput) abandonStream(stream *managedwriter.ManagedStream) {
+	g.streamMut.Lock()
+	defer g.streamMut.Unlock()
+	if g.stream != stream {
+		return
+	}
+	if err := stream.Close(); err != nil {
+		g.log.Debugf("Failed to close committed stream: %v\n", err)
+	}
+	g.stream = nil
+}
+
+func (g *gcpBigQueryWriteOutput) Close(ctx context.Context) error {
+	g.streamMut.Lock()
+	if g.stream != nil {
+		g.stream.Close()
+		g.stream = nil
+	}
+	g.streamMut.Unlock()
+
+	g.connMut.Lock()
+	if g.writeClient != nil {
+		g.writeClient.Close()
+		g.writeClient = nil
+	}
+	if g.client != nil {
+		g.client.Close()
+		g.client = nil
+	}
+	g.connMut.Unlock()
+	return nil
+}
@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestGCPBigQueryWriteSchemaFromStructure(t *testing.T) {
+	msg := service.NewMessage([]byte(`{
+	"name": "foo",
+	"count": 10,
+	"ratio": 0.5,
+	"active": true,
+	"nothing": null,
+	"empty": [],
+	"tags": ["a","b"],
+	"nested": {"id": 1, "scores": [1.5, 2.5]}
+}`))
+	structured, err := msg.AsStructured()
+	require.NoError(t, err)
+
+	schema, err := bqwSchemaFromStructure(structured)
+	require.NoError(t, err)
+
+	assert.Equal(t, bigquery.Schema{
+		{Name: "active", Type: bigquery.BooleanFieldType},
+		{Name: "count", Type: bigquery.IntegerFieldType},
+		{Name: "name", Type: bigquery.StringFieldType},
+		{Name: "nested", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "id", Type: bigquery.IntegerFieldType},
+			{Name: "scores", Type: bigquery.FloatFieldType, Repeated: true},
+		}},
+		{Name: "ratio", Type: bigquery.FloatFieldType},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+	}, schema)
+}
+
+func TestGCPBigQueryWriteSchemaFromStructureErrors(t *testing.T) {
+	_, err := bqwSchemaFromStructure([]interface{}{"foo"})
+	require.Error(t, err)
+
+	_, err = bqwSchemaFromStructure(map[string]interface{}{
+		"matrix": []interface{}{[]interface{}{1}},
+	})
+	require.Error(t, err)
+}
+
+func TestGCPBigQueryWriteEncodeRows(t *testing.T) {
+	schema, err := newBQWSchema(bigquery.Schema{
+		{Name: "name", Type: bigquery.StringFieldType},
+		{Name: "count", Type: bigquery.IntegerFieldType},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+	})
+	require.NoError(t, err)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(`{"name":"foo","count":10,"tags":["a","b"]}`)),
+		service.NewMessage([]byte(`{"name":"bar"}`)),
+	}
+
+	rows, err := schema.encodeRows(batch, false)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	var decoded []string
+	for _, row := range rows {
+		msg := dynamicpb.NewMessage(schema.message)
+		require.NoError(t, proto.Unmarshal(row, msg))
+		jBytes, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		require.NoError(t, err)
+		decoded = append(decoded, string(jBytes))
+	}
+	assert.JSONEq(t, `{"name":"foo","count":"10","tags":["a","b"]}`, decoded[0])
+	assert.JSONEq(t, `{"name":"bar"}`, decoded[1])
+
+	unknown := service.MessageBatch{
+		service.NewMessage([]byte(`{"name":"foo","nope":true}`)),
+	}
+	_, err = schema.encodeRows(unknown, false)
+	require.Error(t, err)
+
+	rows, err = schema.encodeRows(unknown, true)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component/tracer"
 	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/impl/gcp/credentials"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 )
 
@@ -35,6 +36,7 @@ func init() {
 			docs.FieldFloat("sampling_ratio", "Sets the ratio of traces to sample. Tuning the sampling ratio is recommended for high-volume production workloads.", 1.0).HasDefault(1.0),
 			docs.FieldString("tags", "A map of tags to add to tracing spans.").Map().Advanced().HasDefault(map[string]interface{}{}),
 			docs.FieldString("flush_interval", "The period of time between each flush of tracing spans.").HasDefault(""),
+			credentials.FieldSpec(),
 		),
 	})
 }
@@ -51,7 +53,15 @@ func NewCloudTrace(config tracer.Config) (tracer.Type, error) {
 
 	sampler := tracesdk.ParentBased(tracesdk.TraceIDRatioBased(config.CloudTrace.SamplingRatio))
 
-	exp, err := gcptrace.New(gcptrace.WithProjectID(config.CloudTrace.Project))
+	clientOpts, err := clientOptionsFromCredentials(context.Background(), config.CloudTrace.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := gcptrace.New(
+		gcptrace.WithProjectID(config.CloudTrace.Project),
+		gcptrace.WithTraceClientOptions(clientOpts),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cloud trace exporter: %w", err)
 	}
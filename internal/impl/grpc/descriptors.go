@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+)
+
+var importPathsDescription = "A list of directories containing the .proto files that define the service of the `method`, all files within these directories are parsed."
+
+// splitMethodName splits a fully qualified method name of the form
+// `package.Service/Method` into the service and method names, where a leading
+// slash is permitted in order to support the form used on the wire.
+func splitMethodName(fullName string) (service, method string, err error) {
+	fullName = strings.TrimPrefix(fullName, "/")
+	i := strings.LastIndex(fullName, "/")
+	if i <= 0 || i == len(fullName)-1 {
+		return "", "", fmt.Errorf("method '%v' must be of the form package.Service/Method", fullName)
+	}
+	return fullName[:i], fullName[i+1:], nil
+}
+
+// parseDescriptors parses all .proto files found within a list of import
+// paths.
+func parseDescriptors(importPaths []string) ([]*desc.FileDescriptor, error) {
+	parser := protoparse.Parser{ImportPaths: importPaths}
+
+	var files []string
+	for _, importPath := range importPaths {
+		if err := filepath.Walk(importPath, func(path string, info os.FileInfo, ferr error) error {
+			if ferr != nil || info.IsDir() {
+				return ferr
+			}
+			if filepath.Ext(info.Name()) == ".proto" {
+				rPath, ferr := filepath.Rel(importPath, path)
+				if ferr != nil {
+					return fmt.Errorf("failed to get relative path: %v", ferr)
+				}
+				files = append(files, rPath)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	fds, err := parser.ParseFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse .proto file: %v", err)
+	}
+	if len(fds) == 0 {
+		return nil, fmt.Errorf("no .proto files were found in the paths '%v'", importPaths)
+	}
+	return fds, nil
+}
+
+// findMethod returns the descriptor of a fully qualified method from a list of
+// parsed files.
+func findMethod(fullName string, fds []*desc.FileDescriptor) (*desc.MethodDescriptor, error) {
+	svcName, methodName, err := splitMethodName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	for _, fd := range fds {
+		if svc := fd.FindService(svcName); svc != nil {
+			return methodFromService(svc, methodName)
+		}
+	}
+	return nil, fmt.Errorf("unable to find service '%v' definition", svcName)
+}
+
+func methodFromService(svc *desc.ServiceDescriptor, methodName string) (*desc.MethodDescriptor, error) {
+	m := svc.FindMethodByName(methodName)
+	if m == nil {
+		return nil, fmt.Errorf("service '%v' does not have a method '%v'", svc.GetFullyQualifiedName(), methodName)
+	}
+	if m.IsServerStreaming() && !m.IsClientStreaming() {
+		return nil, fmt.Errorf("method '%v' is server streaming, which is not supported", m.GetFullyQualifiedName())
+	}
+	return m, nil
+}
@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const testProto = `
+syntax = "proto3";
+package test.ingest;
+
+message Event {
+  string id = 1;
+  int64 count = 2;
+}
+
+message Ack {}
+
+service Ingest {
+  rpc Send(stream Event) returns (stream Ack);
+  rpc SendOne(Event) returns (Ack);
+  rpc Watch(Event) returns (stream Ack);
+}
+`
+
+func writeTestProto(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ingest.proto"), []byte(testProto), 0o644))
+	return dir
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	return addr
+}
+
+func TestSplitMethodName(t *testing.T) {
+	for _, test := range []struct {
+		input   string
+		service string
+		method  string
+		errs    bool
+	}{
+		{input: "foo.Bar/Baz", service: "foo.Bar", method: "Baz"},
+		{input: "/foo.Bar/Baz", service: "foo.Bar", method: "Baz"},
+		{input: "foo.Bar", errs: true},
+		{input: "foo.Bar/", errs: true},
+		{input: "/Baz", errs: true},
+	} {
+		svc, method, err := splitMethodName(test.input)
+		if test.errs {
+			assert.Error(t, err, test.input)
+			continue
+		}
+		require.NoError(t, err, test.input)
+		assert.Equal(t, test.service, svc, test.input)
+		assert.Equal(t, test.method, method, test.input)
+	}
+}
+
+func TestGRPCRoundTrip(t *testing.T) {
+	dir := writeTestProto(t)
+
+	for _, method := range []string{"Send", "SendOne"} {
+		method := method
+		t.Run(method, func(t *testing.T) {
+			ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+			defer done()
+
+			addr := freeAddress(t)
+
+			inConf, err := grpcServerInputConfig().ParseYAML(fmt.Sprintf(`
+address: %v
+method: test.ingest.Ingest/%v
+import_paths: [ %v ]
+`, addr, method, dir), nil)
+			require.NoError(t, err)
+
+			in, err := newGRPCServerInputFromParsed(inConf, service.MockResources())
+			require.NoError(t, err)
+			require.NoError(t, in.Connect(ctx))
+
+			outConf, err := grpcClientOutputConfig().ParseYAML(fmt.Sprintf(`
+address: %v
+method: test.ingest.Ingest/%v
+import_paths: [ %v ]
+`, addr, method, dir), nil)
+			require.NoError(t, err)
+
+			out, err := newGRPCClientOutputFromParsed(outConf, service.MockResources())
+			require.NoError(t, err)
+			require.NoError(t, out.Connect(ctx))
+
+			writeErr := make(chan error, 1)
+			go func() {
+				writeErr <- out.WriteBatch(ctx, service.MessageBatch{
+					service.NewMessage([]byte(`{"id":"foo","count":"1"}`)),
+					service.NewMessage([]byte(`{"id":"bar","count":"2"}`)),
+				})
+			}()
+
+			for _, exp := range []string{`{"id":"foo","count":"1"}`, `{"id":"bar","count":"2"}`} {
+				msg, ackFn, err := in.Read(ctx)
+				require.NoError(t, err)
+
+				b, err := msg.AsBytes()
+				require.NoError(t, err)
+				assert.JSONEq(t, exp, string(b))
+
+				v, _ := msg.MetaGet("grpc_server_method")
+				assert.Equal(t, "/test.ingest.Ingest/"+method, v)
+				require.NoError(t, ackFn(ctx, nil))
+			}
+			require.NoError(t, <-writeErr)
+
+			// A rejected request fails the write.
+			go func() {
+				writeErr <- out.WriteBatch(ctx, service.MessageBatch{
+					service.NewMessage([]byte(`{"id":"baz"}`)),
+				})
+			}()
+
+			_, ackFn, err := in.Read(ctx)
+			require.NoError(t, err)
+			require.NoError(t, ackFn(ctx, errors.New("nope")))
+			require.Error(t, <-writeErr)
+
+			require.NoError(t, out.Close(ctx))
+			require.NoError(t, in.Close(ctx))
+		})
+	}
+}
+
+func TestGRPCServerStreamingRejected(t *testing.T) {
+	conf, err := grpcServerInputConfig().ParseYAML(fmt.Sprintf(`
+method: test.ingest.Ingest/Watch
+import_paths: [ %v ]
+`, writeTestProto(t)), nil)
+	require.NoError(t, err)
+
+	_, err = newGRPCServerInputFromParsed(conf, service.MockResources())
+	require.Error(t, err)
+}
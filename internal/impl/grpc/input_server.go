@@ -0,0 +1,315 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	// nolint:staticcheck // Ignore SA1019 deprecation warning until we can switch to "google.golang.org/protobuf/types/dynamicpb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func grpcServerInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.2.0").
+		Summary("Serves a gRPC method and consumes the requests sent to it, converting each request into a JSON document.").
+		Description(`
+The service of the `+"`method`"+` is defined by the .proto files found within `+"`import_paths`"+`, and the method can either be unary, client streaming or bidirectional streaming. Each request is converted into a JSON document following the [JSON mapping of protobuf messages](https://developers.google.com/protocol-buffers/docs/proto3#json).
+
+A response, which is an empty message of the response type of the method, is only sent once the request has been acknowledged by the output. For client streaming methods the response is sent once all requests of the stream are acknowledged, and for bidirectional streaming methods a response is sent for each request of the stream. When a request is rejected the RPC fails with the status code `+"`UNAVAILABLE`"+`, allowing the client to retry it.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- grpc_server_method
+- All metadata of the RPC
+`+"```"+`
+
+You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).`).
+		Field(service.NewStringField("address").
+			Description("The address to listen from.").
+			Default("0.0.0.0:50051")).
+		Field(service.NewStringField("method").
+			Description("The fully qualified name of the method to serve.").
+			Example("acme.ingest.v1.Ingest/Send")).
+		Field(service.NewStringListField("import_paths").
+			Description(importPathsDescription).
+			Example([]string{"./protos"})).
+		Field(service.NewStringField("cert_file").
+			Description("An optional certificate file for enabling TLS.").
+			Default("").
+			Advanced()).
+		Field(service.NewStringField("key_file").
+			Description("An optional key file for enabling TLS.").
+			Default("").
+			Advanced()).
+		Example(
+			"Ingesting Events",
+			"With the following .proto file within the directory `./protos` we can accept events streamed by clients, where each event is acknowledged once it has been written to Kafka:\n\n```protobuf\nsyntax = \"proto3\";\npackage acme.ingest.v1;\n\nmessage Event {\n  string id = 1;\n  string payload = 2;\n}\n\nmessage Ack {}\n\nservice Ingest {\n  rpc Send(stream Event) returns (stream Ack);\n}\n```",
+			`
+input:
+  grpc_server:
+    address: 0.0.0.0:50051
+    method: acme.ingest.v1.Ingest/Send
+    import_paths: [ ./protos ]
+
+output:
+  kafka:
+    addresses: [ localhost:9092 ]
+    topic: events
+    key: ${! json("id") }
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterInput(
+		"grpc_server", grpcServerInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			return newGRPCServerInputFromParsed(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type grpcServerMessage struct {
+	msg     *service.Message
+	resChan chan<- error
+}
+
+type grpcServerInput struct {
+	address  string
+	method   *desc.MethodDescriptor
+	certFile string
+	keyFile  string
+
+	marshaler *jsonpb.Marshaler
+
+	serverMut sync.Mutex
+	server    *grpc.Server
+
+	messages chan grpcServerMessage
+
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+}
+
+func newGRPCServerInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*grpcServerInput, error) {
+	g := &grpcServerInput{
+		messages: make(chan grpcServerMessage),
+		log:      mgr.Logger(),
+		shutSig:  shutdown.NewSignaller(),
+	}
+
+	var err error
+	if g.address, err = conf.FieldString("address"); err != nil {
+		return nil, err
+	}
+	methodName, err := conf.FieldString("method")
+	if err != nil {
+		return nil, err
+	}
+	importPaths, err := conf.FieldStringList("import_paths")
+	if err != nil {
+		return nil, err
+	}
+	if g.certFile, err = conf.FieldString("cert_file"); err != nil {
+		return nil, err
+	}
+	if g.keyFile, err = conf.FieldString("key_file"); err != nil {
+		return nil, err
+	}
+	if (g.certFile == "") != (g.keyFile == "") {
+		return nil, errors.New("both cert_file and key_file must be specified, or neither")
+	}
+
+	fds, err := parseDescriptors(importPaths)
+	if err != nil {
+		return nil, err
+	}
+	if g.method, err = findMethod(methodName, fds); err != nil {
+		return nil, err
+	}
+
+	g.marshaler = &jsonpb.Marshaler{
+		AnyResolver: dynamic.AnyResolver(dynamic.NewMessageFactoryWithDefaults(), fds...),
+	}
+	return g, nil
+}
+
+func (g *grpcServerInput) Connect(ctx context.Context) error {
+	g.serverMut.Lock()
+	defer g.serverMut.Unlock()
+	if g.server != nil {
+		return nil
+	}
+
+	var opts []grpc.ServerOption
+	if g.certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(g.certFile, g.keyFile)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	ln, err := net.Listen("tcp", g.address)
+	if err != nil {
+		return err
+	}
+
+	svc := g.method.GetService()
+	server := grpc.NewServer(opts...)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: svc.GetFullyQualifiedName(),
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    g.method.GetName(),
+			Handler:       g.handleStream,
+			ServerStreams: g.method.IsServerStreaming(),
+			ClientStreams: g.method.IsClientStreaming(),
+		}},
+		Metadata: svc.GetFile().GetName(),
+	}, g)
+	g.server = server
+
+	g.log.Infof("Serving gRPC method /%v/%v at: %v\n", svc.GetFullyQualifiedName(), g.method.GetName(), ln.Addr())
+	go func() {
+		if err := server.Serve(ln); err != nil {
+			g.log.Errorf("Server error: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// handleStream serves all kinds of supported method, where a unary method is
+// a stream of a single request.
+func (g *grpcServerInput) handleStream(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+
+	var md metadata.MD
+	if incoming, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		md = incoming
+	}
+
+	for {
+		req := dynamic.NewMessage(g.method.GetInputType())
+		if err := stream.RecvMsg(req); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		data, err := req.MarshalJSONPB(g.marshaler)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to marshal request: %v", err)
+		}
+
+		msg := service.NewMessage(data)
+		for k, v := range md {
+			if len(v) > 0 {
+				msg.MetaSet(k, v[0])
+			}
+		}
+		msg.MetaSet("grpc_server_method", fullMethod)
+
+		if err := g.deliver(stream.Context(), msg); err != nil {
+			return err
+		}
+		if g.method.IsServerStreaming() {
+			if err := stream.SendMsg(dynamic.NewMessage(g.method.GetOutputType())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if g.method.IsServerStreaming() {
+		return nil
+	}
+	return stream.SendMsg(dynamic.NewMessage(g.method.GetOutputType()))
+}
+
+// deliver sends a message through the pipeline and blocks until it has been
+// acknowledged.
+func (g *grpcServerInput) deliver(ctx context.Context, msg *service.Message) error {
+	resChan := make(chan error, 1)
+	select {
+	case g.messages <- grpcServerMessage{msg: msg, resChan: resChan}:
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-g.shutSig.CloseAtLeisureChan():
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+
+	select {
+	case err := <-resChan:
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to deliver message: %v", err)
+		}
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-g.shutSig.CloseNowChan():
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+	return nil
+}
+
+func (g *grpcServerInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	select {
+	case m := <-g.messages:
+		return m.msg, func(ctx context.Context, err error) error {
+			m.resChan <- err
+			return nil
+		}, nil
+	case <-g.shutSig.CloseAtLeisureChan():
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (g *grpcServerInput) Close(ctx context.Context) error {
+	g.shutSig.CloseAtLeisure()
+
+	g.serverMut.Lock()
+	server := g.server
+	g.server = nil
+	g.serverMut.Unlock()
+	if server == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		g.shutSig.CloseNow()
+		server.Stop()
+		return ctx.Err()
+	}
+	return nil
+}
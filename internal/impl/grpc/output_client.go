@@ -0,0 +1,309 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	// nolint:staticcheck // Ignore SA1019 deprecation warning until we can switch to "google.golang.org/protobuf/types/dynamicpb"
+	"github.com/golang/protobuf/jsonpb"
+	// nolint:staticcheck // Ignore SA1019 deprecation warning until we can switch to "google.golang.org/protobuf/types/dynamicpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func grpcClientOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.2.0").
+		Summary("Sends messages as requests to a gRPC method, converting each message from a JSON document into the request type of the method.").
+		Description(output.Description(true, true, `
+Messages are converted into requests following the [JSON mapping of protobuf messages](https://developers.google.com/protocol-buffers/docs/proto3#json), and the responses of the method are discarded. When `+"`import_paths`"+` is empty the definition of the method is obtained from the server with [server reflection](https://github.com/grpc/grpc/blob/master/doc/server-reflection.md), which the server must support.
+
+For unary methods a request is made for each message of a batch. For client streaming and bidirectional streaming methods a stream is opened for each batch and each message of the batch is sent over it, where the batch is acknowledged once the server closes the stream successfully. Server streaming methods are not supported.`)).
+		Field(service.NewStringField("address").
+			Description("The address of the server to connect to.").
+			Example("localhost:50051")).
+		Field(service.NewStringField("method").
+			Description("The fully qualified name of the method to call.").
+			Example("acme.ingest.v1.Ingest/Send")).
+		Field(service.NewStringListField("import_paths").
+			Description(importPathsDescription+" When empty the definition of the method is obtained from the server with server reflection.").
+			Example([]string{"./protos"}).
+			Default([]string{})).
+		Field(service.NewTLSToggledField("tls")).
+		Field(service.NewIntField("max_in_flight").
+			Description("The maximum number of message batches to have in flight at a given time. Increase this to improve throughput.").
+			Default(64)).
+		Field(service.NewBatchPolicyField("batching")).
+		Example(
+			"Streaming Events",
+			"In this example events are streamed to a service that implements the method `acme.ingest.v1.Ingest/Send`, where the definition of the method is obtained with server reflection and batches of up to a hundred events are sent over each stream.",
+			`
+output:
+  grpc_client:
+    address: ingest.acme.svc.cluster.local:50051
+    method: acme.ingest.v1.Ingest/Send
+    batching:
+      count: 100
+      period: 1s
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"grpc_client", grpcClientOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPol service.BatchPolicy, mif int, err error) {
+			if mif, err = conf.FieldInt("max_in_flight"); err != nil {
+				return
+			}
+			if batchPol, err = conf.FieldBatchPolicy("batching"); err != nil {
+				return
+			}
+			out, err = newGRPCClientOutputFromParsed(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type grpcClientOutput struct {
+	address    string
+	methodName string
+	fds        []*desc.FileDescriptor
+	creds      credentials.TransportCredentials
+
+	connMut     sync.RWMutex
+	conn        *grpc.ClientConn
+	stub        grpcdynamic.Stub
+	method      *desc.MethodDescriptor
+	unmarshaler *jsonpb.Unmarshaler
+
+	log *service.Logger
+}
+
+func newGRPCClientOutputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*grpcClientOutput, error) {
+	g := &grpcClientOutput{
+		creds: insecure.NewCredentials(),
+		log:   mgr.Logger(),
+	}
+
+	var err error
+	if g.address, err = conf.FieldString("address"); err != nil {
+		return nil, err
+	}
+	if g.methodName, err = conf.FieldString("method"); err != nil {
+		return nil, err
+	}
+	if _, _, err = splitMethodName(g.methodName); err != nil {
+		return nil, err
+	}
+
+	importPaths, err := conf.FieldStringList("import_paths")
+	if err != nil {
+		return nil, err
+	}
+	if len(importPaths) > 0 {
+		if g.fds, err = parseDescriptors(importPaths); err != nil {
+			return nil, err
+		}
+		// Fail early rather than on each connection attempt.
+		if _, err = findMethod(g.methodName, g.fds); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled("tls")
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		g.creds = credentials.NewTLS(tlsConf)
+	}
+	return g, nil
+}
+
+// resolveMethod obtains the descriptor of the method from the server with
+// server reflection.
+func (g *grpcClientOutput) resolveMethod(ctx context.Context, conn *grpc.ClientConn) (*desc.MethodDescriptor, error) {
+	svcName, methodName, err := splitMethodName(g.methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := grpcreflect.NewClient(ctx, rpb.NewServerReflectionClient(conn))
+	defer rc.Reset()
+
+	svc, err := rc.ResolveService(svcName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service '%v' with server reflection: %w", svcName, err)
+	}
+	return methodFromService(svc, methodName)
+}
+
+func (g *grpcClientOutput) Connect(ctx context.Context) error {
+	g.connMut.Lock()
+	defer g.connMut.Unlock()
+	if g.conn != nil {
+		return nil
+	}
+
+	conn, err := grpc.DialContext(ctx, g.address, grpc.WithTransportCredentials(g.creds))
+	if err != nil {
+		return err
+	}
+
+	var method *desc.MethodDescriptor
+	fds := g.fds
+	if len(fds) > 0 {
+		method, err = findMethod(g.methodName, fds)
+	} else {
+		if method, err = g.resolveMethod(ctx, conn); err == nil {
+			fds = []*desc.FileDescriptor{method.GetFile()}
+		}
+	}
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	g.conn = conn
+	g.stub = grpcdynamic.NewStub(conn)
+	g.method = method
+	g.unmarshaler = &jsonpb.Unmarshaler{
+		AnyResolver: dynamic.AnyResolver(dynamic.NewMessageFactoryWithDefaults(), fds...),
+	}
+	g.log.Infof("Sending messages to gRPC method /%v at: %v\n", g.methodName, g.address)
+	return nil
+}
+
+func (g *grpcClientOutput) newRequest(msg *service.Message) (proto.Message, error) {
+	data, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	req := dynamic.NewMessage(g.method.GetInputType())
+	if err := req.UnmarshalJSONPB(g.unmarshaler, data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON message: %w", err)
+	}
+	return req, nil
+}
+
+func (g *grpcClientOutput) writeUnary(ctx context.Context, batch service.MessageBatch) error {
+	for _, msg := range batch {
+		req, err := g.newRequest(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := g.stub.InvokeRpc(ctx, g.method, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *grpcClientOutput) writeClientStream(ctx context.Context, batch service.MessageBatch) error {
+	stream, err := g.stub.InvokeRpcClientStream(ctx, g.method)
+	if err != nil {
+		return err
+	}
+	for _, msg := range batch {
+		req, err := g.newRequest(msg)
+		if err != nil {
+			return err
+		}
+		if err := stream.SendMsg(req); err != nil {
+			if errors.Is(err, io.EOF) {
+				// The stream was terminated by the server, and the cause is
+				// obtained by closing it.
+				break
+			}
+			return err
+		}
+	}
+	_, err = stream.CloseAndReceive()
+	return err
+}
+
+func (g *grpcClientOutput) writeBidiStream(ctx context.Context, batch service.MessageBatch) error {
+	stream, err := g.stub.InvokeRpcBidiStream(ctx, g.method)
+	if err != nil {
+		return err
+	}
+	for _, msg := range batch {
+		req, err := g.newRequest(msg)
+		if err != nil {
+			return err
+		}
+		if err := stream.SendMsg(req); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	// The stream is only closed successfully once the server has consumed all
+	// requests, and therefore responses are drained until the end.
+	for {
+		if _, err := stream.RecvMsg(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (g *grpcClientOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	g.connMut.RLock()
+	conn := g.conn
+	g.connMut.RUnlock()
+	if conn == nil {
+		return service.ErrNotConnected
+	}
+
+	// Cancelling the context of a stream releases its resources when the batch
+	// fails part way through.
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	switch {
+	case g.method.IsClientStreaming() && g.method.IsServerStreaming():
+		return g.writeBidiStream(ctx, batch)
+	case g.method.IsClientStreaming():
+		return g.writeClientStream(ctx, batch)
+	}
+	return g.writeUnary(ctx, batch)
+}
+
+func (g *grpcClientOutput) Close(ctx context.Context) error {
+	g.connMut.Lock()
+	defer g.connMut.Unlock()
+	if g.conn == nil {
+		return nil
+	}
+	err := g.conn.Close()
+	g.conn = nil
+	return err
+}
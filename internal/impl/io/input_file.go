@@ -3,6 +3,7 @@ package io
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
 	"github.com/benthosdev/benthos/v4/internal/codec"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
@@ -22,7 +24,7 @@ import (
 
 func init() {
 	err := bundle.AllInputs.Add(processors.WrapConstructor(func(conf input.Config, nm bundle.NewManagement) (input.Streamed, error) {
-		rdr, err := newFileConsumer(conf.File, nm.Logger())
+		rdr, err := newFileConsumer(conf.File, nm, nm.Logger())
 		if err != nil {
 			return nil, err
 		}
@@ -36,6 +38,7 @@ Consumes data from files on disk, emitting messages according to a chosen codec.
 			codec.ReaderDocs,
 			docs.FieldInt("max_buffer", "The largest token size expected when consuming delimited files.").Advanced(),
 			docs.FieldBool("delete_on_finish", "Whether to delete consumed files from the disk once they are fully consumed.").Advanced(),
+			docs.FieldString("checkpoint_cache", "An optional [cache resource](/docs/components/caches/about) in which to store the progress of each file, allowing consumption to resume from where it left off after a restart.").Advanced().AtVersion("4.2.0"),
 		).ChildDefaultAndTypesFromStruct(input.NewFileConfig()),
 		Description: `
 ### Metadata
@@ -49,7 +52,23 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### Checkpoints
+
+When a ` + "`checkpoint_cache`" + ` is set the number of messages consumed from
+each file is stored within the cache once they, and all messages before them
+from the same file, have been successfully delivered. When a file is consumed
+again, for example after a restart, messages up to the stored checkpoint are
+skipped. Checkpoints are stored under keys of the form
+` + "`<label>:<path>`" + `, where the label is that of the input or ` + "`file`" + ` when a
+label is not set.
+
+The checkpoints can be viewed and rewound via the HTTP endpoint
+` + "`/checkpoints/<label>`" + `. A GET request returns the checkpoints of files
+consumed by this input, and a POST request with a JSON body such as
+` + "`{\"partition\":\"./foo.csv\",\"offset\":10}`" + ` rewinds the checkpoint of a file, which
+causes the file to be replayed from that point the next time it is consumed.`,
 		Categories: []string{
 			"Local",
 		},
@@ -77,6 +96,17 @@ type scannerInfo struct {
 	scanner     codec.Reader
 	currentPath string
 	modTime     time.Time
+	checkpoint  *fileCheckpoint
+}
+
+// fileCheckpoint tracks the number of messages read from a file, and the
+// number that have been delivered.
+type fileCheckpoint struct {
+	skip int64
+	read int64
+
+	mut     sync.Mutex
+	tracker *checkpoint.Type
 }
 
 type fileConsumer struct {
@@ -89,9 +119,13 @@ type fileConsumer struct {
 	scannerInfo *scannerInfo
 
 	delete bool
+
+	checkpoints *checkpoint.CacheStore
+	rewoundMut  sync.Mutex
+	rewound     map[string]struct{}
 }
 
-func newFileConsumer(conf input.FileConfig, log log.Modular) (*fileConsumer, error) {
+func newFileConsumer(conf input.FileConfig, mgr bundle.NewManagement, log log.Modular) (*fileConsumer, error) {
 	expandedPaths, err := filepath.Globs(conf.Paths)
 	if err != nil {
 		return nil, err
@@ -104,12 +138,31 @@ func newFileConsumer(conf input.FileConfig, log log.Modular) (*fileConsumer, err
 		return nil, err
 	}
 
-	return &fileConsumer{
+	f := &fileConsumer{
 		log:         log,
 		scannerCtor: ctor,
 		paths:       expandedPaths,
 		delete:      conf.DeleteOnFinish,
-	}, nil
+		rewound:     map[string]struct{}{},
+	}
+	if conf.CheckpointCache != "" {
+		if !mgr.ProbeCache(conf.CheckpointCache) {
+			return nil, fmt.Errorf("checkpoint cache resource '%v' was not found", conf.CheckpointCache)
+		}
+		id := mgr.Label()
+		if id == "" {
+			id = "file"
+		}
+		f.checkpoints = checkpoint.NewCacheStore(mgr, conf.CheckpointCache, id)
+		f.checkpoints.OnRewind(func(path string, offset int64) {
+			// Progress of a file that has already been opened would otherwise
+			// overwrite the rewound checkpoint.
+			f.rewoundMut.Lock()
+			f.rewound[path] = struct{}{}
+			f.rewoundMut.Unlock()
+		})
+	}
+	return f, nil
 }
 
 func (f *fileConsumer) ConnectWithContext(ctx context.Context) error {
@@ -130,6 +183,22 @@ func (f *fileConsumer) getReader(ctx context.Context) (scannerInfo, error) {
 
 	nextPath := f.paths[0]
 
+	var ckpt *fileCheckpoint
+	if f.checkpoints != nil {
+		f.rewoundMut.Lock()
+		delete(f.rewound, nextPath)
+		f.rewoundMut.Unlock()
+
+		skip, _, err := f.checkpoints.Get(ctx, nextPath)
+		if err != nil {
+			return scannerInfo{}, fmt.Errorf("failed to read checkpoint of file '%v': %w", nextPath, err)
+		}
+		ckpt = &fileCheckpoint{
+			skip:    skip,
+			tracker: checkpoint.New(),
+		}
+	}
+
 	file, err := os.Open(nextPath)
 	if err != nil {
 		return scannerInfo{}, err
@@ -157,11 +226,16 @@ func (f *fileConsumer) getReader(ctx context.Context) (scannerInfo, error) {
 		scanner:     scanner,
 		currentPath: nextPath,
 		modTime:     modTime,
+		checkpoint:  ckpt,
 	}
 
 	f.paths = f.paths[1:]
 
-	f.log.Infof("Consuming from file '%v'\n", nextPath)
+	if ckpt != nil && ckpt.skip > 0 {
+		f.log.Infof("Consuming from file '%v', skipping %v messages up to its checkpoint\n", nextPath, ckpt.skip)
+	} else {
+		f.log.Infof("Consuming from file '%v'\n", nextPath)
+	}
 	return *f.scannerInfo, nil
 }
 
@@ -188,6 +262,18 @@ func (f *fileConsumer) ReadWithContext(ctx context.Context) (*message.Batch, inp
 			return nil, nil, err
 		}
 
+		var resolveFn func() interface{}
+		if ckpt := scannerInfo.checkpoint; ckpt != nil {
+			ckpt.read++
+			if ckpt.read <= ckpt.skip {
+				_ = codecAckFn(ctx, nil)
+				continue
+			}
+			ckpt.mut.Lock()
+			resolveFn = ckpt.tracker.Track(ckpt.read, 1)
+			ckpt.mut.Unlock()
+		}
+
 		modTimeUnix, modTime := f.getModTime(scannerInfo.modTime)
 
 		msg := message.QuickBatch(nil)
@@ -208,11 +294,36 @@ func (f *fileConsumer) ReadWithContext(ctx context.Context) (*message.Batch, inp
 		}
 
 		return msg, func(rctx context.Context, res error) error {
+			if res == nil && resolveFn != nil {
+				f.storeCheckpoint(rctx, scannerInfo.currentPath, scannerInfo.checkpoint, resolveFn)
+			}
 			return codecAckFn(rctx, res)
 		}, nil
 	}
 }
 
+func (f *fileConsumer) storeCheckpoint(ctx context.Context, path string, ckpt *fileCheckpoint, resolveFn func() interface{}) {
+	// The lock is held while storing so that checkpoints are stored in order.
+	ckpt.mut.Lock()
+	defer ckpt.mut.Unlock()
+
+	highest, _ := resolveFn().(int64)
+	if highest == 0 {
+		return
+	}
+
+	f.rewoundMut.Lock()
+	_, rewound := f.rewound[path]
+	f.rewoundMut.Unlock()
+	if rewound {
+		return
+	}
+
+	if err := f.checkpoints.Set(ctx, path, highest); err != nil {
+		f.log.Errorf("Failed to store checkpoint of file '%v': %v\n", path, err)
+	}
+}
+
 func (f *fileConsumer) CloseAsync() {
 	go func() {
 		f.scannerMut.Lock()
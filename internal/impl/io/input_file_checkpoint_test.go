@@ -0,0 +1,92 @@
+package io
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestFileCheckpoints(t *testing.T) {
+	ctx := context.Background()
+
+	tmpFile := filepath.Join(t.TempDir(), "foo.txt")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("a\nb\nc\nd\n"), 0o644))
+
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	var endpoint http.HandlerFunc
+	mgr.OnRegisterEndpoint = func(path string, h http.HandlerFunc) {
+		if path == "/checkpoints/file" {
+			endpoint = h
+		}
+	}
+
+	conf := input.NewFileConfig()
+	conf.Paths = []string{tmpFile}
+	conf.CheckpointCache = "foocache"
+
+	readNext := func(f *fileConsumer) (string, input.AsyncAckFn) {
+		t.Helper()
+		msg, ackFn, err := f.ReadWithContext(ctx)
+		require.NoError(t, err)
+		return string(msg.Get(0).Get()), ackFn
+	}
+
+	f, err := newFileConsumer(conf, mgr, log.Noop())
+	require.NoError(t, err)
+
+	first, firstAck := readNext(f)
+	second, secondAck := readNext(f)
+	assert.Equal(t, "a", first)
+	assert.Equal(t, "b", second)
+
+	// The checkpoint only moves once all prior messages are delivered.
+	require.NoError(t, secondAck(ctx, nil))
+	assert.NotContains(t, mgr.Caches["foocache"], "file:"+tmpFile)
+
+	require.NoError(t, firstAck(ctx, nil))
+	assert.Equal(t, "2", mgr.Caches["foocache"]["file:"+tmpFile].Value)
+
+	f.CloseAsync()
+
+	f, err = newFileConsumer(conf, mgr, log.Noop())
+	require.NoError(t, err)
+
+	third, thirdAck := readNext(f)
+	assert.Equal(t, "c", third)
+	require.NoError(t, thirdAck(ctx, nil))
+	assert.Equal(t, "3", mgr.Caches["foocache"]["file:"+tmpFile].Value)
+
+	// Rewinding prevents further progress of the file from being stored.
+	require.NotNil(t, endpoint)
+	res := httptest.NewRecorder()
+	endpoint(res, httptest.NewRequest("POST", "/checkpoints/file", strings.NewReader(`{"partition":"`+tmpFile+`","offset":1}`)))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	fourth, fourthAck := readNext(f)
+	assert.Equal(t, "d", fourth)
+	require.NoError(t, fourthAck(ctx, nil))
+	assert.Equal(t, "1", mgr.Caches["foocache"]["file:"+tmpFile].Value)
+
+	f.CloseAsync()
+
+	f, err = newFileConsumer(conf, mgr, log.Noop())
+	require.NoError(t, err)
+
+	replayed, _ := readNext(f)
+	assert.Equal(t, "b", replayed)
+
+	f.CloseAsync()
+}
@@ -11,6 +11,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -55,6 +56,8 @@ When the rate limit is breached HTTP requests will have a 429 response returned
 
 It's possible to return a response for each message received using [synchronous responses](/docs/guides/sync_responses). When doing so you can customise headers with the ` + "`sync_response` field `headers`" + `, which can also use [function interpolation](/docs/configuration/interpolation#bloblang-queries) in the value based on the response message contents.
 
+By default the response is returned once the request has been fully processed. Alternatively, the ` + "`sync_response` field `streaming`" + ` can be used in order to stream each response batch back to the client as soon as it is produced, either as a chunked response body or as [Server-Sent Events](https://html.spec.whatwg.org/multipage/server-sent-events.html). This allows Benthos to front long running pipelines that produce many response batches from a single request, for example when a request is split into multiple batches or results are queried incrementally. When streaming, the status code and headers are resolved from the first response batch, and the ` + "`timeout`" + ` applies to the period between response batches rather than the request as a whole.
+
 ### Endpoints
 
 The following fields specify endpoints that are registered for sending messages, and support path parameters of the form ` + "`/{foo}`" + `, which are added to ingested messages as metadata:
@@ -63,7 +66,9 @@ The following fields specify endpoints that are registered for sending messages,
 
 This endpoint expects POST requests where the entire request body is consumed as a single message.
 
-If the request contains a multipart ` + "`content-type`" + ` header as per [rfc1341](https://www.w3.org/Protocols/rfc1341/7_2_Multipart.html) then the multiple parts are consumed as a batch of messages, where each body part is a message of the batch.
+If the request contains a multipart ` + "`content-type`" + ` header as per [rfc1341](https://www.w3.org/Protocols/rfc1341/7_2_Multipart.html) then the multiple parts are consumed as a batch of messages, where each body part is a message of the batch. This includes ` + "`multipart/form-data`" + ` requests such as HTML form file uploads, where each form field and file is a message of the batch. Each message of a multipart request is given the metadata fields ` + "`http_server_part_name`, `http_server_part_filename` and `http_server_part_content_type`" + ` when the respective part defines them.
+
+Parts are held in memory until the request has been fully received, and once the parts of a request exceed ` + "`multipart_max_memory`" + ` bytes the remaining parts are buffered within temporary files on disk instead, which are removed once the request has been consumed.
 
 #### ` + "`ws_path` (defaults to `/post/ws`)" + `
 
@@ -87,7 +92,13 @@ This input adds the following metadata fields to each message:
 - All cookies
 ` + "```" + `
 
-You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).`,
+You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).
+
+### Journal
+
+By default a request to the ` + "`path`" + ` endpoint is only responded to once its messages have been delivered, and therefore producers that wait for a response are given at-least-once delivery guarantees. However, messages that are in flight are lost if Benthos crashes. When ` + "[`journal.enabled`](#journalenabled)" + ` is ` + "`true`" + ` the messages of each request are instead persisted to a file within the directory ` + "[`journal.path`](#journalpath)" + `, which is synced to disk before a 200 response is returned, and the file is removed once the messages have been acknowledged. Messages that are rejected by the pipeline are retried until they are delivered, and files remaining within the journal when Benthos restarts are replayed from the same directory.
+
+Once the journal holds ` + "[`journal.max_entries`](#journalmax_entries)" + ` requests new requests are rejected with a 503 response until space becomes available. Since requests are responded to before they are processed [synchronous responses](/docs/guides/sync_responses) are not supported with a journal, and the journal does not apply to the ` + "`ws_path`" + ` endpoint.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("address", "An alternative address to host from. If left empty the service wide address is used."),
 			docs.FieldString("path", "The endpoint path to listen for POST requests."),
@@ -99,6 +110,7 @@ You can access these metadata fields using [function interpolation](/docs/config
 			docs.FieldString("rate_limit", "An optional [rate limit](/docs/components/rate_limits/about) to throttle requests by."),
 			docs.FieldString("cert_file", "Enable TLS by specifying a certificate and key file. Only valid with a custom `address`.").Advanced(),
 			docs.FieldString("key_file", "Enable TLS by specifying a certificate and key file. Only valid with a custom `address`.").Advanced(),
+			docs.FieldInt("multipart_max_memory", "The maximum number of bytes of the parts of a multipart request to hold in memory whilst the request is received, parts beyond this are buffered on disk.").AtVersion("4.2.0").Advanced(),
 			corsSpec,
 			docs.FieldObject("sync_response", "Customise messages returned via [synchronous responses](/docs/guides/sync_responses).").WithChildren(
 				docs.FieldString(
@@ -112,7 +124,19 @@ You can access these metadata fields using [function interpolation](/docs/config
 						"Content-Type": "application/octet-stream",
 					}),
 				docs.FieldObject("metadata_headers", "Specify criteria for which metadata values are added to the response as headers.").WithChildren(imetadata.IncludeFilterDocs()...),
+				docs.FieldString("streaming", "Whether response batches should be streamed back to the client as they are produced rather than once the request has been fully processed.").HasAnnotatedOptions(
+					"none", "Return all response messages once the request has been processed, multiple messages are returned as a multipart body.",
+					"chunked", "Write the raw payload of each response message to a chunked response body as soon as it is produced.",
+					"sse", "Write each response message as a Server-Sent Event as soon as it is produced, where each line of the payload is written as a `data` field.",
+				).AtVersion("4.2.0"),
 			).Advanced(),
+			docs.FieldObject(
+				"journal", "Persist the messages of each request to a write-ahead journal on disk before responding, where they are retained until acknowledged and replayed after a restart. For more information read the [journal section](#journal).",
+			).WithChildren(
+				docs.FieldBool("enabled", "Whether to persist the messages of each request to a journal."),
+				docs.FieldString("path", "The directory to store journal entries within, which is created if it does not exist. Each input must use its own directory.", "./journal/webhooks"),
+				docs.FieldInt("max_entries", "The maximum number of requests to hold within the journal."),
+			).Advanced().AtVersion("4.2.0"),
 		).ChildDefaultAndTypesFromStruct(input.NewHTTPServerConfig()),
 		Categories: []string{
 			"Network",
@@ -137,10 +161,20 @@ type httpServerInput struct {
 	responseStatus  *field.Expression
 	responseHeaders map[string]*field.Expression
 	metaFilter      *imetadata.IncludeFilter
+	streaming       string
 
 	handlerWG    sync.WaitGroup
 	transactions chan message.Transaction
 
+	journal *inputJournal
+
+	// Dispatches of journalled messages are only added to journalWG whilst
+	// journalClosed is false, which is set under journalMut before waiting on
+	// journalWG during shutdown.
+	journalMut    sync.Mutex
+	journalClosed bool
+	journalWG     sync.WaitGroup
+
 	shutSig *shutdown.Signaller
 
 	allowedVerbs map[string]struct{}
@@ -210,6 +244,23 @@ func newHTTPServerInput(conf input.Config, mgr bundle.NewManagement) (input.Stre
 		return nil, fmt.Errorf("failed to construct metadata filter: %w", err)
 	}
 
+	switch h.streaming = h.conf.Response.Streaming; h.streaming {
+	case "":
+		h.streaming = "none"
+	case "none", "chunked", "sse":
+	default:
+		return nil, fmt.Errorf("sync_response streaming mode not recognised: %v", h.streaming)
+	}
+
+	if h.conf.Journal.Enabled {
+		if h.streaming != "none" {
+			return nil, errors.New("sync_response streaming cannot be used with a journal")
+		}
+		if h.journal, err = newInputJournal(h.conf.Journal.Path, h.conf.Journal.MaxEntries); err != nil {
+			return nil, err
+		}
+	}
+
 	postHdlr := gzipHandler(h.postHandler)
 	wsHdlr := gzipHandler(h.wsHandler)
 	if mux != nil {
@@ -244,6 +295,90 @@ func newHTTPServerInput(conf input.Config, mgr bundle.NewManagement) (input.Stre
 
 //------------------------------------------------------------------------------
 
+// readMultipartParts consumes each part of a multipart body as a message.
+// Once maxMemory bytes of parts have been read the remainder of the parts are
+// buffered within temporary files until the body has been fully received.
+func readMultipartParts(body io.Reader, boundary string, maxMemory int64) ([]*message.Part, error) {
+	type bufferedPart struct {
+		meta map[string]string
+		data []byte
+		file *os.File
+	}
+
+	var buffered []*bufferedPart
+	defer func() {
+		for _, bp := range buffered {
+			if bp.file != nil {
+				_ = bp.file.Close()
+				_ = os.Remove(bp.file.Name())
+			}
+		}
+	}()
+
+	remaining := maxMemory
+	mr := multipart.NewReader(body, boundary)
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		bp := &bufferedPart{meta: map[string]string{}}
+		buffered = append(buffered, bp)
+		if name := p.FormName(); name != "" {
+			bp.meta["http_server_part_name"] = name
+		}
+		if filename := p.FileName(); filename != "" {
+			bp.meta["http_server_part_filename"] = filename
+		}
+		if contentType := p.Header.Get("Content-Type"); contentType != "" {
+			bp.meta["http_server_part_content_type"] = contentType
+		}
+
+		var buf bytes.Buffer
+		n, err := io.CopyN(&buf, p, remaining+1)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if n <= remaining {
+			remaining -= n
+			bp.data = buf.Bytes()
+			continue
+		}
+
+		remaining = 0
+		if bp.file, err = os.CreateTemp("", "benthos-multipart-"); err != nil {
+			return nil, err
+		}
+		if _, err = buf.WriteTo(bp.file); err != nil {
+			return nil, err
+		}
+		if _, err = io.Copy(bp.file, p); err != nil {
+			return nil, err
+		}
+	}
+
+	parts := make([]*message.Part, 0, len(buffered))
+	for _, bp := range buffered {
+		data := bp.data
+		if bp.file != nil {
+			var err error
+			if data, err = os.ReadFile(bp.file.Name()); err != nil {
+				return nil, err
+			}
+		}
+		part := message.NewPart(data)
+		for k, v := range bp.meta {
+			part.MetaSet(k, v)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
 func (h *httpServerInput) extractMessageFromRequest(r *http.Request) (*message.Batch, error) {
 	msg := message.QuickBatch(nil)
 
@@ -258,21 +393,11 @@ func (h *httpServerInput) extractMessageFromRequest(r *http.Request) (*message.B
 	}
 
 	if strings.HasPrefix(mediaType, "multipart/") {
-		mr := multipart.NewReader(r.Body, params["boundary"])
-		for {
-			var p *multipart.Part
-			if p, err = mr.NextPart(); err != nil {
-				if err == io.EOF {
-					break
-				}
-				return nil, err
-			}
-			var msgBytes []byte
-			if msgBytes, err = io.ReadAll(p); err != nil {
-				return nil, err
-			}
-			msg.Append(message.NewPart(msgBytes))
+		var parts []*message.Part
+		if parts, err = readMultipartParts(r.Body, params["boundary"], h.conf.MultipartMaxMemory); err != nil {
+			return nil, err
 		}
+		msg.Append(parts...)
 	} else {
 		var msgBytes []byte
 		if msgBytes, err = io.ReadAll(r.Body); err != nil {
@@ -351,11 +476,23 @@ func (h *httpServerInput) postHandler(w http.ResponseWriter, r *http.Request) {
 		h.log.Warnf("Request read failed: %v\n", err)
 		return
 	}
+
+	if h.journal != nil {
+		h.journalRequest(w, msg)
+		return
+	}
 	defer tracing.FinishSpans(msg)
 
 	startedAt := time.Now()
 
-	store := transaction.NewResultStore()
+	var store transaction.ResultStore
+	var streamStore *streamingResultStore
+	if h.streaming != "none" {
+		streamStore = newStreamingResultStore()
+		store = streamStore
+	} else {
+		store = transaction.NewResultStore()
+	}
 	transaction.AddResultStore(msg, store)
 
 	h.mPostRcvd.Incr(int64(msg.Len()))
@@ -375,6 +512,11 @@ func (h *httpServerInput) postHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if streamStore != nil {
+		h.streamResponse(w, r, streamStore, resChan, startedAt)
+		return
+	}
+
 	select {
 	case res, open := <-resChan:
 		if !open {
@@ -478,6 +620,285 @@ func (h *httpServerInput) postHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// streamingResultStore is a result store that signals each time a response
+// batch is added, allowing responses to be written to the client as they are
+// produced.
+type streamingResultStore struct {
+	transaction.ResultStore
+	notify chan struct{}
+}
+
+func newStreamingResultStore() *streamingResultStore {
+	return &streamingResultStore{
+		ResultStore: transaction.NewResultStore(),
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+func (s *streamingResultStore) Add(msg *message.Batch) {
+	s.ResultStore.Add(msg)
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// streamResponse writes response batches to the client as they are added to
+// the store, until the request transaction is resolved. The status code and
+// headers are resolved from the first response batch, and therefore once a
+// batch has been written errors can only be logged.
+func (h *httpServerInput) streamResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	store *streamingResultStore,
+	resChan <-chan error,
+	startedAt time.Time,
+) {
+	flusher, _ := w.(http.Flusher)
+
+	written := 0
+	headersSent := false
+
+	writeHeaders := func(resMsg *message.Batch) error {
+		for k, v := range h.responseHeaders {
+			w.Header().Set(k, v.String(0, resMsg))
+		}
+		_ = resMsg.Get(0).MetaIter(func(k, v string) error {
+			if h.metaFilter.Match(k) {
+				w.Header().Set(k, v)
+			}
+			return nil
+		})
+		if h.streaming == "sse" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+
+		statusCode := 200
+		if statusCodeStr := h.responseStatus.String(0, resMsg); statusCodeStr != "200" {
+			var err error
+			if statusCode, err = strconv.Atoi(statusCodeStr); err != nil {
+				return fmt.Errorf("failed to parse sync response status code expression: %w", err)
+			}
+		}
+		w.WriteHeader(statusCode)
+		headersSent = true
+		return nil
+	}
+
+	writeBatches := func() error {
+		batches := store.Get()
+		for ; written < len(batches); written++ {
+			resMsg := batches[written]
+			if resMsg.Len() == 0 {
+				continue
+			}
+			if !headersSent {
+				if err := writeHeaders(resMsg); err != nil {
+					return err
+				}
+			}
+			if err := resMsg.Iter(func(i int, part *message.Part) error {
+				if h.streaming == "sse" {
+					return writeServerSentEvent(w, part.Get())
+				}
+				_, err := w.Write(part.Get())
+				return err
+			}); err != nil {
+				return err
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	idleTimer := time.NewTimer(h.timeout)
+	defer idleTimer.Stop()
+
+	for {
+		select {
+		case <-store.notify:
+			if err := writeBatches(); err != nil {
+				h.log.Errorf("Failed to stream sync response: %v\n", err)
+				if !headersSent {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+				return
+			}
+			if !idleTimer.Stop() {
+				<-idleTimer.C
+			}
+			idleTimer.Reset(h.timeout)
+		case res, open := <-resChan:
+			if !open {
+				if !headersSent {
+					http.Error(w, "Server closing", http.StatusServiceUnavailable)
+				}
+				return
+			}
+			if res != nil {
+				if !headersSent {
+					http.Error(w, res.Error(), http.StatusBadGateway)
+				} else {
+					h.log.Errorf("Failed to complete streamed sync response: %v\n", res)
+				}
+				return
+			}
+			if err := writeBatches(); err != nil {
+				h.log.Errorf("Failed to stream sync response: %v\n", err)
+				if !headersSent {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+				return
+			}
+			h.mLatency.Timing(time.Since(startedAt).Nanoseconds())
+			return
+		case <-idleTimer.C:
+			if !headersSent {
+				http.Error(w, "Request timed out", http.StatusRequestTimeout)
+			}
+			return
+		case <-r.Context().Done():
+			return
+		case <-h.shutSig.CloseNowChan():
+			if !headersSent {
+				http.Error(w, "Server closing", http.StatusServiceUnavailable)
+			}
+			return
+		}
+	}
+}
+
+// writeServerSentEvent writes a payload as a single event, where each line of
+// the payload is written as a data field.
+func writeServerSentEvent(w io.Writer, payload []byte) error {
+	var buf bytes.Buffer
+	payload = bytes.TrimSuffix(payload, []byte("\n"))
+	for _, line := range bytes.Split(payload, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(bytes.TrimSuffix(line, []byte("\r")))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// journalRequest persists the messages of a request to the journal and
+// dispatches them in the background, responding to the request once the
+// messages are on disk rather than once they are delivered.
+func (h *httpServerInput) journalRequest(w http.ResponseWriter, msg *message.Batch) {
+	if !h.addJournalDispatch() {
+		tracing.FinishSpans(msg)
+		http.Error(w, "Server closing", http.StatusServiceUnavailable)
+		return
+	}
+
+	seq, err := h.journal.Append(spooledPartsFromBatch(msg))
+	if err != nil {
+		h.journalWG.Done()
+		tracing.FinishSpans(msg)
+		if errors.Is(err, errInputJournalFull) {
+			http.Error(w, "Journal is full", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Server error", http.StatusInternalServerError)
+		h.log.Errorf("Failed to journal request: %v\n", err)
+		return
+	}
+
+	h.mPostRcvd.Incr(int64(msg.Len()))
+	h.log.Tracef("Journalled %v messages from POST to '%v'.\n", msg.Len(), h.conf.Path)
+
+	go h.dispatchJournalled(seq, msg)
+}
+
+// addJournalDispatch registers a dispatch of journalled messages with
+// journalWG, which must be marked as done once the dispatch ends. Returns false
+// without registering the dispatch if the input is shutting down.
+func (h *httpServerInput) addJournalDispatch() bool {
+	h.journalMut.Lock()
+	defer h.journalMut.Unlock()
+	if h.journalClosed {
+		return false
+	}
+	h.journalWG.Add(1)
+	return true
+}
+
+// dispatchJournalled delivers the messages of a journal entry, retrying them
+// until they are acknowledged, at which point the entry is removed. Entries
+// that are not delivered by shutdown remain within the journal.
+func (h *httpServerInput) dispatchJournalled(seq uint64, msg *message.Batch) {
+	defer h.journalWG.Done()
+	defer tracing.FinishSpans(msg)
+
+	startedAt := time.Now()
+	resChan := make(chan error, 1)
+	throt := throttle.New(throttle.OptCloseChan(h.shutSig.CloseAtLeisureChan()))
+	for {
+		select {
+		case h.transactions <- message.NewTransaction(msg.Copy(), resChan):
+		case <-h.shutSig.CloseAtLeisureChan():
+			return
+		}
+
+		select {
+		case res, open := <-resChan:
+			if !open {
+				return
+			}
+			if res == nil {
+				h.mLatency.Timing(time.Since(startedAt).Nanoseconds())
+				if err := h.journal.Remove(seq); err != nil {
+					h.log.Errorf("%v\n", err)
+				}
+				return
+			}
+			h.log.Debugf("Retrying journalled messages after rejection: %v\n", res)
+			if !throt.Retry() {
+				return
+			}
+		case <-h.shutSig.CloseNowChan():
+			return
+		}
+	}
+}
+
+// replayJournal dispatches the entries left within the journal by a previous
+// run.
+func (h *httpServerInput) replayJournal() {
+	defer h.journalWG.Done()
+
+	recovered := h.journal.Recovered()
+	if len(recovered) == 0 {
+		return
+	}
+	h.log.Infof("Replaying %v requests from journal: %v\n", len(recovered), h.conf.Journal.Path)
+
+	for _, seq := range recovered {
+		parts, err := h.journal.Read(seq)
+		if err != nil {
+			h.log.Errorf("Dropping unreadable journal entry %v: %v\n", seq, err)
+			if err := h.journal.Remove(seq); err != nil {
+				h.log.Errorf("%v\n", err)
+			}
+			continue
+		}
+
+		msg := batchFromSpooledParts(parts)
+		tracing.InitSpans("input_http_server_journal", msg)
+
+		if !h.addJournalDispatch() {
+			tracing.FinishSpans(msg)
+			return
+		}
+		go h.dispatchJournalled(seq, msg)
+	}
+}
+
 func (h *httpServerInput) wsHandler(w http.ResponseWriter, r *http.Request) {
 	h.handlerWG.Add(1)
 	defer h.handlerWG.Done()
@@ -616,10 +1037,19 @@ func (h *httpServerInput) loop() {
 
 		h.handlerWG.Wait()
 
+		h.journalMut.Lock()
+		h.journalClosed = true
+		h.journalMut.Unlock()
+		h.journalWG.Wait()
+
 		close(h.transactions)
 		h.shutSig.ShutdownComplete()
 	}()
 
+	if h.journal != nil && h.addJournalDispatch() {
+		go h.replayJournal()
+	}
+
 	if h.server != nil {
 		go func() {
 			if len(h.conf.KeyFile) > 0 || len(h.conf.CertFile) > 0 {
@@ -693,6 +1123,15 @@ func (w gzipResponseWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
 
+func (w gzipResponseWriter) Flush() {
+	if f, ok := w.Writer.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func gzipHandler(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
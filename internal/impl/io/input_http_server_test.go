@@ -13,6 +13,7 @@ import (
 	"net/http/httptest"
 	"net/textproto"
 	"net/url"
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -289,6 +290,89 @@ func TestHTTPServerLifecycle(t *testing.T) {
 	assert.NoError(t, serverTwo.WaitForClose(time.Second))
 }
 
+func TestHTTPServerJournal(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Minute)
+	defer done()
+
+	journalDir := t.TempDir()
+	journalEntries := func() int {
+		entries, err := os.ReadDir(journalDir)
+		require.NoError(t, err)
+		return len(entries)
+	}
+
+	reg := apiRegGorillaMutWrapper{mut: mux.NewRouter()}
+	mgr, err := manager.New(manager.NewResourceConfig(), reg, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := input.NewConfig()
+	conf.Type = "http_server"
+	conf.HTTPServer.Path = "/testpost"
+	conf.HTTPServer.Journal.Enabled = true
+	conf.HTTPServer.Journal.Path = journalDir
+
+	h, err := mgr.NewInput(conf)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(reg.mut)
+	defer server.Close()
+
+	readNextMsg := func(in input.Streamed) message.Transaction {
+		t.Helper()
+		select {
+		case tran := <-in.TransactionChan():
+			return tran
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out waiting for message")
+		}
+		return message.Transaction{}
+	}
+
+	// The request is responded to once journalled, before it is consumed.
+	res, err := http.Post(server.URL+"/testpost", "text/plain", bytes.NewReader([]byte("foo")))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, 1, journalEntries())
+
+	tran := readNextMsg(h)
+	assert.Equal(t, "foo", string(tran.Payload.Get(0).Get()))
+	require.NoError(t, tran.Ack(tCtx, errors.New("nope")))
+
+	tran = readNextMsg(h)
+	assert.Equal(t, "foo", string(tran.Payload.Get(0).Get()))
+	require.NoError(t, tran.Ack(tCtx, nil))
+	assert.Eventually(t, func() bool {
+		return journalEntries() == 0
+	}, time.Second, time.Millisecond*10)
+
+	// An unacknowledged request remains within the journal after shutdown.
+	res, err = http.Post(server.URL+"/testpost", "text/plain", bytes.NewReader([]byte("bar")))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+
+	tran = readNextMsg(h)
+	assert.Equal(t, "bar", string(tran.Payload.Get(0).Get()))
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+	assert.Equal(t, 1, journalEntries())
+
+	h, err = mgr.NewInput(conf)
+	require.NoError(t, err)
+
+	tran = readNextMsg(h)
+	assert.Equal(t, "bar", string(tran.Payload.Get(0).Get()))
+	require.NoError(t, tran.Ack(tCtx, nil))
+	assert.Eventually(t, func() bool {
+		return journalEntries() == 0
+	}, time.Second, time.Millisecond*10)
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+}
+
 func TestHTTPServerMetadata(t *testing.T) {
 	tCtx, done := context.WithTimeout(context.Background(), time.Minute)
 	defer done()
@@ -352,6 +436,77 @@ func TestHTTPServerMetadata(t *testing.T) {
 	assert.Contains(t, "bar", part.MetaGet("foo"))
 }
 
+func TestHTTPServerMultipartFormData(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("name", "foo"))
+
+	hdr := textproto.MIMEHeader{}
+	hdr.Set("Content-Disposition", `form-data; name="upload"; filename="data.txt"`)
+	hdr.Set("Content-Type", "text/plain")
+	fw, err := writer.CreatePart(hdr)
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("hello world, this exceeds the memory limit"))
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteField("after", "bar"))
+	require.NoError(t, writer.Close())
+
+	tCtx, done := context.WithTimeout(context.Background(), time.Minute)
+	defer done()
+
+	reg := apiRegGorillaMutWrapper{mut: mux.NewRouter()}
+	mgr, err := manager.New(manager.NewResourceConfig(), reg, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	// The second part exceeds the memory limit and therefore it and all
+	// subsequent parts are buffered on disk.
+	conf := input.NewConfig()
+	conf.Type = "http_server"
+	conf.HTTPServer.Path = "/upload"
+	conf.HTTPServer.MultipartMaxMemory = 8
+
+	server, err := mgr.NewInput(conf)
+	require.NoError(t, err)
+
+	defer func() {
+		server.CloseAsync()
+		assert.NoError(t, server.WaitForClose(time.Second))
+	}()
+
+	testServer := httptest.NewServer(reg.mut)
+	defer testServer.Close()
+
+	go func() {
+		resp, cerr := http.Post(testServer.URL+"/upload", writer.FormDataContentType(), body)
+		require.NoError(t, cerr)
+		defer resp.Body.Close()
+	}()
+
+	var tran message.Transaction
+	select {
+	case tran = <-server.TransactionChan():
+		require.NoError(t, tran.Ack(tCtx, nil))
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+
+	require.Equal(t, 3, tran.Payload.Len())
+	parts := []*message.Part{tran.Payload.Get(0), tran.Payload.Get(1), tran.Payload.Get(2)}
+
+	assert.Equal(t, "foo", string(parts[0].Get()))
+	assert.Equal(t, "name", parts[0].MetaGet("http_server_part_name"))
+	assert.Equal(t, "", parts[0].MetaGet("http_server_part_filename"))
+
+	assert.Equal(t, "hello world, this exceeds the memory limit", string(parts[1].Get()))
+	assert.Equal(t, "upload", parts[1].MetaGet("http_server_part_name"))
+	assert.Equal(t, "data.txt", parts[1].MetaGet("http_server_part_filename"))
+	assert.Equal(t, "text/plain", parts[1].MetaGet("http_server_part_content_type"))
+
+	assert.Equal(t, "bar", string(parts[2].Get()))
+	assert.Equal(t, "after", parts[2].MetaGet("http_server_part_name"))
+}
+
 func TestHTTPtServerPathParameters(t *testing.T) {
 	tCtx, done := context.WithTimeout(context.Background(), time.Minute)
 	defer done()
@@ -1072,3 +1227,117 @@ func TestHTTPSyncResponseHeadersStatus(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestHTTPSyncResponseStreaming(t *testing.T) {
+	tests := []struct {
+		mode        string
+		contentType string
+		first       string
+		second      string
+	}{
+		{
+			mode:        "chunked",
+			contentType: "application/octet-stream",
+			first:       "foo\n",
+			second:      "bar\nbaz\n",
+		},
+		{
+			mode:        "sse",
+			contentType: "text/event-stream",
+			first:       "data: foo\n\n",
+			second:      "data: bar\n\ndata: baz\n\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.mode, func(t *testing.T) {
+			tCtx, done := context.WithTimeout(context.Background(), time.Minute)
+			defer done()
+
+			reg := apiRegGorillaMutWrapper{mut: mux.NewRouter()}
+			mgr, err := manager.New(manager.NewResourceConfig(), reg, log.Noop(), metrics.Noop())
+			require.NoError(t, err)
+
+			conf := input.NewConfig()
+			conf.Type = "http_server"
+			conf.HTTPServer.Path = "/testpost"
+			conf.HTTPServer.Response.Streaming = test.mode
+
+			h, err := mgr.NewInput(conf)
+			require.NoError(t, err)
+
+			server := httptest.NewServer(reg.mut)
+			defer server.Close()
+
+			firstRead := make(chan string, 1)
+			remaining := make(chan string, 1)
+			go func() {
+				res, err := http.Post(server.URL+"/testpost", "text/plain", bytes.NewBufferString("hello world"))
+				if err != nil {
+					t.Error(err)
+					close(firstRead)
+					return
+				}
+				defer res.Body.Close()
+
+				if exp, act := test.contentType, res.Header.Get("Content-Type"); exp != act {
+					t.Errorf("Wrong content type: %v != %v", act, exp)
+				}
+
+				firstBytes := make([]byte, len(test.first))
+				if _, err := io.ReadFull(res.Body, firstBytes); err != nil {
+					t.Error(err)
+				}
+				firstRead <- string(firstBytes)
+
+				remainingBytes, err := io.ReadAll(res.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				remaining <- string(remainingBytes)
+			}()
+
+			var ts message.Transaction
+			select {
+			case ts = <-h.TransactionChan():
+			case <-tCtx.Done():
+				t.Fatal("Timed out waiting for message")
+			}
+
+			respond := func(payloads ...string) {
+				t.Helper()
+				ctx := message.GetContext(ts.Payload.Get(0))
+				resMsg := message.QuickBatch(nil)
+				for _, p := range payloads {
+					resMsg.Append(message.WithContext(ctx, message.NewPart([]byte(p))))
+				}
+				require.NoError(t, transaction.SetAsResponse(resMsg))
+			}
+
+			// The first response batch must reach the client before the
+			// transaction is resolved.
+			respond("foo\n")
+
+			select {
+			case first := <-firstRead:
+				assert.Equal(t, test.first, first)
+			case <-tCtx.Done():
+				t.Fatal("Timed out waiting for first response")
+			}
+
+			respond("bar\n", "baz\n")
+			require.NoError(t, ts.Ack(tCtx, nil))
+
+			select {
+			case rest := <-remaining:
+				assert.Equal(t, test.second, rest)
+			case <-tCtx.Done():
+				t.Fatal("Timed out waiting for remaining response")
+			}
+
+			h.CloseAsync()
+			require.NoError(t, h.WaitForClose(time.Second*5))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package io
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const inputJournalExt = ".entry"
+
+var errInputJournalFull = errors.New("journal is full")
+
+// inputJournal is a bounded write-ahead journal of consumed messages persisted
+// within a directory, where each entry is a file named after its sequence
+// number. Entries are written and synced to disk before a message is
+// confirmed to its producer, and are removed once the message has been
+// acknowledged by the pipeline. Entries left within the directory by a
+// previous run are recovered in order to be replayed.
+type inputJournal struct {
+	dir        string
+	maxEntries int
+
+	mut       sync.Mutex
+	pending   map[uint64]struct{}
+	recovered []uint64
+	nextSeq   uint64
+}
+
+func newInputJournal(dir string, maxEntries int) (*inputJournal, error) {
+	if dir == "" {
+		return nil, errors.New("a journal path must be specified")
+	}
+	if maxEntries <= 0 {
+		return nil, fmt.Errorf("journal max_entries must be greater than zero, got %v", maxEntries)
+	}
+
+	j := &inputJournal{
+		dir:        dir,
+		maxEntries: maxEntries,
+		pending:    map[uint64]struct{}{},
+	}
+	if err := os.MkdirAll(j.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, inputJournalExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, inputJournalExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		j.pending[seq] = struct{}{}
+		j.recovered = append(j.recovered, seq)
+	}
+	sort.Slice(j.recovered, func(i, k int) bool {
+		return j.recovered[i] < j.recovered[k]
+	})
+	if l := len(j.recovered); l > 0 {
+		j.nextSeq = j.recovered[l-1] + 1
+	}
+	return j, nil
+}
+
+func (j *inputJournal) path(seq uint64) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%020d%v", seq, inputJournalExt))
+}
+
+// Recovered returns the sequence numbers of the entries left within the
+// journal by a previous run, in the order that they were written.
+func (j *inputJournal) Recovered() []uint64 {
+	return j.recovered
+}
+
+// Len returns the number of entries within the journal.
+func (j *inputJournal) Len() int {
+	j.mut.Lock()
+	defer j.mut.Unlock()
+	return len(j.pending)
+}
+
+// Append persists an entry to the journal and returns its sequence number, or
+// returns errInputJournalFull if the journal is full. The entry is synced to
+// disk before this call returns.
+func (j *inputJournal) Append(parts []spooledPart) (uint64, error) {
+	data, err := json.Marshal(parts)
+	if err != nil {
+		return 0, err
+	}
+
+	j.mut.Lock()
+	if len(j.pending) >= j.maxEntries {
+		j.mut.Unlock()
+		return 0, errInputJournalFull
+	}
+	seq := j.nextSeq
+	j.nextSeq++
+	j.pending[seq] = struct{}{}
+	j.mut.Unlock()
+
+	if err := j.write(seq, data); err != nil {
+		j.mut.Lock()
+		delete(j.pending, seq)
+		j.mut.Unlock()
+		return 0, fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return seq, nil
+}
+
+// write persists an entry to a temporary file and then renames it so that a
+// partially written entry is never recovered. The directory is synced after the
+// rename so that the entry survives a crash once it has been acknowledged.
+func (j *inputJournal) write(seq uint64, data []byte) error {
+	tmpPath := j.path(seq) + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, j.path(seq))
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return syncDir(j.dir)
+}
+
+// syncDir flushes the entries of a directory to disk, which is required for a
+// rename within it to be durable. Directories cannot be synced on Windows, where
+// this is a no-op.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if cerr := d.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read returns the parts of an entry within the journal.
+func (j *inputJournal) Read(seq uint64) ([]spooledPart, error) {
+	data, err := os.ReadFile(j.path(seq))
+	if err != nil {
+		return nil, err
+	}
+	var parts []spooledPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// Remove deletes an entry from the journal once it has been acknowledged.
+func (j *inputJournal) Remove(seq uint64) error {
+	j.mut.Lock()
+	delete(j.pending, seq)
+	j.mut.Unlock()
+
+	if err := os.Remove(j.path(seq)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove journal entry: %w", err)
+	}
+	return nil
+}
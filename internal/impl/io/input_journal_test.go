@@ -0,0 +1,58 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputJournal(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := newInputJournal(dir, 2)
+	require.NoError(t, err)
+	assert.Empty(t, j.Recovered())
+
+	seqA, err := j.Append([]spooledPart{{Content: []byte("a"), Metadata: map[string]string{"foo": "bar"}}})
+	require.NoError(t, err)
+
+	seqB, err := j.Append([]spooledPart{{Content: []byte("b")}})
+	require.NoError(t, err)
+
+	_, err = j.Append([]spooledPart{{Content: []byte("c")}})
+	assert.Equal(t, errInputJournalFull, err)
+	assert.Equal(t, 2, j.Len())
+
+	parts, err := j.Read(seqA)
+	require.NoError(t, err)
+	assert.Equal(t, []spooledPart{{Content: []byte("a"), Metadata: map[string]string{"foo": "bar"}}}, parts)
+
+	require.NoError(t, j.Remove(seqA))
+	assert.Equal(t, 1, j.Len())
+
+	// Temporary files of interrupted writes are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000009.entry.tmp"), []byte("nope"), 0o644))
+
+	j, err = newInputJournal(dir, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{seqB}, j.Recovered())
+
+	parts, err = j.Read(seqB)
+	require.NoError(t, err)
+	assert.Equal(t, []spooledPart{{Content: []byte("b")}}, parts)
+
+	seqC, err := j.Append([]spooledPart{{Content: []byte("c")}})
+	require.NoError(t, err)
+	assert.Greater(t, seqC, seqB)
+}
+
+func TestInputJournalBadConfig(t *testing.T) {
+	_, err := newInputJournal("", 10)
+	require.Error(t, err)
+
+	_, err = newInputJournal(t.TempDir(), 0)
+	require.Error(t, err)
+}
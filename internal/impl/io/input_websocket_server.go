@@ -0,0 +1,423 @@
+package io
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func websocketServerInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.2.0").
+		Summary("Accepts inbound websocket connections and consumes the messages sent over them, applying backpressure to each connection when the pipeline is saturated.").
+		Description(`
+By default the websocket endpoint is registered with the service-wide HTTP server, and the path is therefore prefixed with the stream identifier when running in streams mode. When an `+"`address`"+` is specified a separate server is started that listens on that address instead.
+
+Each connection may have up to `+"`max_in_flight`"+` messages that are waiting to be acknowledged, and once that limit is reached the input stops reading from the connection until one of them is acknowledged. This pushes back on clients through the flow control of the underlying TCP connection rather than buffering their messages in memory, and a slow or saturated pipeline therefore slows down clients instead of dropping their messages.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- websocket_server_connection_id
+- websocket_server_remote_addr
+- All headers of the upgrade request listed in header_metadata
+`+"```"+`
+
+The connection id is a unique identifier generated for each connection, which allows messages sent over the same connection to be correlated. Header metadata keys are the canonical form of the header name, e.g. `+"`X-Request-Id`"+`.
+
+You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).
+
+### Journal
+
+Messages that have been read from a connection but are not yet acknowledged are lost if Benthos crashes. When `+"[`journal.enabled`](#journalenabled)"+` is `+"`true`"+` each message is persisted to a file within the directory `+"[`journal.path`](#journalpath)"+`, which is synced to disk before the next message is read from the connection, and the file is removed once the message has been acknowledged. Files remaining within the journal when Benthos restarts are replayed from the same directory.
+
+Once the journal holds `+"[`journal.max_entries`](#journalmax_entries)"+` messages reads from all connections are paused until space becomes available.`).
+		Field(service.NewStringField("address").
+			Description("An optional address to listen from. When empty the endpoint is registered with the service-wide HTTP server.").
+			Example("0.0.0.0:4196").
+			Default("")).
+		Field(service.NewStringField("path").
+			Description("The path at which websocket connections are accepted.").
+			Default("/ws")).
+		Field(service.NewStringListField("header_metadata").
+			Description("A list of headers of the upgrade request to add to each message of a connection as metadata.").
+			Example([]string{"Authorization", "X-Request-Id"}).
+			Default([]string{})).
+		Field(service.NewIntField("max_in_flight").
+			Description("The maximum number of messages of each connection that can be waiting to be acknowledged, after which reads from the connection are paused.").
+			Default(64).
+			Advanced()).
+		Field(service.NewObjectField("journal",
+			service.NewBoolField("enabled").
+				Description("Whether to persist each message to a journal.").
+				Default(false),
+			service.NewStringField("path").
+				Description("The directory to store journal entries within, which is created if it does not exist. Each input must use its own directory.").
+				Example("./journal/readings").
+				Default(""),
+			service.NewIntField("max_entries").
+				Description("The maximum number of messages to hold within the journal.").
+				Default(10000),
+		).Description("Persist each message to a write-ahead journal on disk once it is read, where it is retained until acknowledged and replayed after a restart. For more information read the [journal section](#journal).").
+			Advanced()).
+		Example(
+			"Per-Device Streams",
+			"In this example devices stream readings over long lived connections, and the id of each device is taken from a header of the upgrade request so that readings can be partitioned by device.",
+			`
+input:
+  websocket_server:
+    address: 0.0.0.0:4196
+    path: /readings
+    header_metadata: [ X-Device-Id ]
+
+output:
+  kafka:
+    addresses: [ localhost:9092 ]
+    topic: readings
+    key: ${! meta("X-Device-Id") }
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterInput(
+		"websocket_server", websocketServerInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			w, err := newWebsocketServerInputFromParsed(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacks(w), nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type websocketServerMessage struct {
+	msg     *service.Message
+	release func()
+}
+
+type websocketServerInput struct {
+	address     string
+	path        string
+	headerMeta  []string
+	maxInFlight int
+
+	upgrader websocket.Upgrader
+	server   *http.Server
+
+	serveMut sync.Mutex
+	serving  bool
+
+	messages chan websocketServerMessage
+	connWG   sync.WaitGroup
+	journal  *inputJournal
+
+	connections  int64
+	mConnections *service.MetricGauge
+
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+}
+
+func newWebsocketServerInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*websocketServerInput, error) {
+	w := &websocketServerInput{
+		messages:     make(chan websocketServerMessage),
+		mConnections: mgr.Metrics().NewGauge("input_websocket_server_connections"),
+		log:          mgr.Logger(),
+		shutSig:      shutdown.NewSignaller(),
+	}
+
+	var err error
+	if w.address, err = conf.FieldString("address"); err != nil {
+		return nil, err
+	}
+	if w.path, err = conf.FieldString("path"); err != nil {
+		return nil, err
+	}
+	if w.path == "" {
+		return nil, errors.New("a path must be specified")
+	}
+	if w.headerMeta, err = conf.FieldStringList("header_metadata"); err != nil {
+		return nil, err
+	}
+	for i, h := range w.headerMeta {
+		w.headerMeta[i] = http.CanonicalHeaderKey(h)
+	}
+	if w.maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
+		return nil, err
+	}
+	if w.maxInFlight < 1 {
+		return nil, fmt.Errorf("max_in_flight must be greater than zero, got %v", w.maxInFlight)
+	}
+
+	jConf := conf.Namespace("journal")
+	if enabled, err := jConf.FieldBool("enabled"); err != nil {
+		return nil, err
+	} else if enabled {
+		path, err := jConf.FieldString("path")
+		if err != nil {
+			return nil, err
+		}
+		maxEntries, err := jConf.FieldInt("max_entries")
+		if err != nil {
+			return nil, err
+		}
+		if w.journal, err = newInputJournal(path, maxEntries); err != nil {
+			return nil, err
+		}
+		w.connWG.Add(1)
+		go w.replayJournal()
+	}
+
+	if w.address != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc(w.path, w.handleConn)
+		w.server = &http.Server{Addr: w.address, Handler: mux}
+	} else {
+		mgr.RegisterEndpoint(w.path, "Send messages via websocket into Benthos.", w.handleConn)
+	}
+	return w, nil
+}
+
+func (w *websocketServerInput) Connect(ctx context.Context) error {
+	if w.server == nil {
+		return nil
+	}
+
+	w.serveMut.Lock()
+	defer w.serveMut.Unlock()
+	if w.serving {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", w.address)
+	if err != nil {
+		return err
+	}
+	w.serving = true
+
+	w.log.Infof("Receiving websocket messages at: %v%v\n", ln.Addr(), w.path)
+	go func() {
+		if err := w.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			w.log.Errorf("Server error: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+func (w *websocketServerInput) handleConn(rw http.ResponseWriter, r *http.Request) {
+	if w.shutSig.ShouldCloseAtLeisure() {
+		http.Error(rw, "Server closing", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.connWG.Add(1)
+	defer w.connWG.Done()
+
+	// Upgrade responds to the client when it fails.
+	ws, err := w.upgrader.Upgrade(rw, r, nil)
+	if err != nil {
+		w.log.Warnf("Websocket upgrade failed: %v\n", err)
+		return
+	}
+	defer ws.Close()
+
+	connID, err := uuid.NewV4()
+	if err != nil {
+		w.log.Errorf("Failed to generate connection id: %v\n", err)
+		return
+	}
+
+	w.mConnections.Set(atomic.AddInt64(&w.connections, 1))
+	defer func() {
+		w.mConnections.Set(atomic.AddInt64(&w.connections, -1))
+	}()
+
+	meta := map[string]string{
+		"websocket_server_connection_id": connID.String(),
+		"websocket_server_remote_addr":   r.RemoteAddr,
+	}
+	for _, h := range w.headerMeta {
+		if v := r.Header.Get(h); v != "" {
+			meta[h] = v
+		}
+	}
+
+	// Closing the connection on shutdown unblocks any pending read.
+	connDone := make(chan struct{})
+	defer close(connDone)
+	go func() {
+		select {
+		case <-w.shutSig.CloseAtLeisureChan():
+			ws.Close()
+		case <-connDone:
+		}
+	}()
+
+	slots := make(chan struct{}, w.maxInFlight)
+	for {
+		// Reads are paused whilst the connection has max_in_flight messages
+		// waiting to be acknowledged.
+		select {
+		case slots <- struct{}{}:
+		case <-w.shutSig.CloseAtLeisureChan():
+			return
+		}
+
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			if !w.shutSig.ShouldCloseAtLeisure() &&
+				!websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				w.log.Debugf("Connection %v from %v dropped due to: %v\n", meta["websocket_server_connection_id"], r.RemoteAddr, err)
+			}
+			return
+		}
+
+		msg := service.NewMessage(data)
+		for k, v := range meta {
+			msg.MetaSet(k, v)
+		}
+
+		release := func() { <-slots }
+		if w.journal != nil {
+			seq, ok := w.journalMessage(data, meta)
+			if !ok {
+				return
+			}
+			release = func() {
+				<-slots
+				w.removeJournalled(seq)
+			}
+		}
+
+		select {
+		case w.messages <- websocketServerMessage{
+			msg:     msg,
+			release: release,
+		}:
+		case <-w.shutSig.CloseAtLeisureChan():
+			return
+		}
+	}
+}
+
+// journalMessage persists a message to the journal, waiting for space when
+// the journal is full. Returns false if the connection should be closed.
+func (w *websocketServerInput) journalMessage(data []byte, meta map[string]string) (uint64, bool) {
+	parts := []spooledPart{{Content: data, Metadata: meta}}
+	for {
+		seq, err := w.journal.Append(parts)
+		if err == nil {
+			return seq, true
+		}
+		if !errors.Is(err, errInputJournalFull) {
+			w.log.Errorf("Failed to journal message: %v\n", err)
+			return 0, false
+		}
+		select {
+		case <-time.After(time.Millisecond * 100):
+		case <-w.shutSig.CloseAtLeisureChan():
+			return 0, false
+		}
+	}
+}
+
+func (w *websocketServerInput) removeJournalled(seq uint64) {
+	if err := w.journal.Remove(seq); err != nil {
+		w.log.Errorf("%v\n", err)
+	}
+}
+
+// replayJournal feeds the messages left within the journal by a previous run
+// into the input ahead of newly received messages.
+func (w *websocketServerInput) replayJournal() {
+	defer w.connWG.Done()
+
+	recovered := w.journal.Recovered()
+	if len(recovered) > 0 {
+		w.log.Infof("Replaying %v messages from journal\n", len(recovered))
+	}
+
+	for _, seq := range recovered {
+		parts, err := w.journal.Read(seq)
+		if err != nil {
+			w.log.Errorf("Dropping unreadable journal entry %v: %v\n", seq, err)
+			w.removeJournalled(seq)
+			continue
+		}
+		for _, p := range parts {
+			msg := service.NewMessage(p.Content)
+			for k, v := range p.Metadata {
+				msg.MetaSet(k, v)
+			}
+
+			seq := seq
+			select {
+			case w.messages <- websocketServerMessage{
+				msg:     msg,
+				release: func() { w.removeJournalled(seq) },
+			}:
+			case <-w.shutSig.CloseAtLeisureChan():
+				return
+			}
+		}
+	}
+}
+
+func (w *websocketServerInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	select {
+	case m := <-w.messages:
+		return m.msg, func(ctx context.Context, err error) error {
+			m.release()
+			return nil
+		}, nil
+	case <-w.shutSig.CloseAtLeisureChan():
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (w *websocketServerInput) Close(ctx context.Context) error {
+	w.shutSig.CloseAtLeisure()
+	if w.server != nil {
+		if err := w.server.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Hijacked connections, and the replay of the journal, are not tracked by
+	// the server and are therefore waited on separately.
+	connsDone := make(chan struct{})
+	go func() {
+		w.connWG.Wait()
+		close(connsDone)
+	}()
+	select {
+	case <-connsDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	w.shutSig.ShutdownComplete()
+	return nil
+}
@@ -0,0 +1,89 @@
+package io
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestWebsocketServerInputMetadataAndBackpressure(t *testing.T) {
+	conf, err := websocketServerInputConfig().ParseYAML(`
+path: /ws
+header_metadata: [ x-device-id ]
+max_in_flight: 1
+`, nil)
+	require.NoError(t, err)
+
+	w, err := newWebsocketServerInputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+	require.NoError(t, w.Connect(ctx))
+
+	server := httptest.NewServer(http.HandlerFunc(w.handleConn))
+	defer server.Close()
+
+	header := http.Header{}
+	header.Set("X-Device-Id", "foo")
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, []byte("hello world 1")))
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, []byte("hello world 2")))
+
+	msg, ackFn, err := w.Read(ctx)
+	require.NoError(t, err)
+
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world 1", string(b))
+
+	v, _ := msg.MetaGet("X-Device-Id")
+	assert.Equal(t, "foo", v)
+	connID, _ := msg.MetaGet("websocket_server_connection_id")
+	assert.NotEmpty(t, connID)
+	v, _ = msg.MetaGet("websocket_server_remote_addr")
+	assert.NotEmpty(t, v)
+
+	// The second message is not read until the first is acknowledged.
+	readCtx, readDone := context.WithTimeout(ctx, time.Millisecond*50)
+	_, _, err = w.Read(readCtx)
+	readDone()
+	require.Error(t, err)
+
+	require.NoError(t, ackFn(ctx, nil))
+
+	msg, ackFn, err = w.Read(ctx)
+	require.NoError(t, err)
+
+	b, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world 2", string(b))
+
+	v, _ = msg.MetaGet("websocket_server_connection_id")
+	assert.Equal(t, connID, v)
+	require.NoError(t, ackFn(ctx, nil))
+
+	require.NoError(t, w.Close(ctx))
+}
+
+func TestWebsocketServerInputBadConfig(t *testing.T) {
+	conf, err := websocketServerInputConfig().ParseYAML(`
+max_in_flight: 0
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newWebsocketServerInputFromParsed(conf, service.MockResources())
+	require.Error(t, err)
+}
@@ -34,7 +34,13 @@ The body of the HTTP request is the raw contents of the message payload. If the
 
 ### Propagating Responses
 
-It's possible to propagate the response from each HTTP request back to the input source by setting `+"`propagate_response` to `true`"+`. Only inputs that support [synchronous responses](/docs/guides/sync_responses) are able to make use of these propagated responses.`),
+It's possible to propagate the response from each HTTP request back to the input source by setting `+"`propagate_response` to `true`"+`. Only inputs that support [synchronous responses](/docs/guides/sync_responses) are able to make use of these propagated responses.
+
+### Spooling
+
+When `+"[`spool.enabled`](#spoolenabled)"+` is `+"`true`"+` message batches that fail to be sent, once the retries of the request have been exhausted, are persisted to files within the directory `+"[`spool.path`](#spoolpath)"+` and acknowledged, rather than being rejected. Spooled batches are retried in the order that they were spooled with an exponential backoff until they are sent successfully, and whilst the spool holds batches new messages are added to the spool directly in order to preserve ordering. Batches remaining within the spool when Benthos shuts down are resumed from the same directory once it restarts.
+
+Once the spool holds `+"[`spool.max_batches`](#spoolmax_batches)"+` batches failed messages are rejected as usual, which applies back pressure. Responses of spooled batches are not propagated.`),
 		Config: ihttpdocs.ClientFieldSpec(true,
 			docs.FieldBool("batch_as_multipart", "Send message batches as a single request using [RFC1341](https://www.w3.org/Protocols/rfc1341/7_2_Multipart.html). If disabled messages in batches will be sent as individual requests.").Advanced(),
 			docs.FieldBool("propagate_response", "Whether responses from the server should be [propagated back](/docs/guides/sync_responses) to the input.").Advanced(),
@@ -47,6 +53,18 @@ It's possible to propagate the response from each HTTP request back to the input
 				docs.FieldInterpolatedString("content_disposition", "The content disposition of the individual message part.", `form-data; name="bin"; filename='${! meta("AttachmentName") }`).HasDefault(""),
 				docs.FieldInterpolatedString("body", "The body of the individual message part.", `${! json("data.part1") }`).HasDefault(""),
 			).AtVersion("3.63.0"),
+			docs.FieldObject(
+				"spool", "Persist message batches that fail to be sent to a bounded queue on disk, where they are retried in the background until successful. For more information read the [spooling section](#spooling).",
+			).WithChildren(
+				docs.FieldBool("enabled", "Whether to spool message batches that fail to be sent."),
+				docs.FieldString("path", "The directory to store spooled message batches within, which is created if it does not exist. Each output must use its own directory.", "./spool/webhooks"),
+				docs.FieldInt("max_batches", "The maximum number of message batches to hold within the spool."),
+				docs.FieldObject("backoff", "Control time intervals between attempts to send the oldest spooled batch.").WithChildren(
+					docs.FieldString("initial_interval", "The initial period to wait between attempts."),
+					docs.FieldString("max_interval", "The maximum period to wait between attempts."),
+					docs.FieldString("max_elapsed_time", "The maximum period to retry a spooled batch before it is dropped. If zero then no limit is used."),
+				),
+			).Advanced().AtVersion("4.2.0"),
 		).ChildDefaultAndTypesFromStruct(output.NewHTTPClientConfig()),
 		Categories: []string{
 			"Network",
@@ -77,6 +95,8 @@ type httpClientWriter struct {
 
 	log log.Modular
 
+	spool *httpClientSpool
+
 	conf      output.HTTPClientConfig
 	closeChan chan struct{}
 }
@@ -117,6 +137,15 @@ func newHTTPClientWriter(conf output.HTTPClientConfig, mgr bundle.NewManagement,
 	if h.client, err = http.NewClient(conf.Config, opts...); err != nil {
 		return nil, err
 	}
+
+	if conf.Spool.Enabled {
+		if h.spool, err = newHTTPClientSpool(conf.Spool, func(ctx context.Context, msg *message.Batch) error {
+			_, err := h.client.Send(ctx, msg, msg)
+			return err
+		}, log); err != nil {
+			return nil, err
+		}
+	}
 	return &h, nil
 }
 
@@ -126,7 +155,19 @@ func (h *httpClientWriter) ConnectWithContext(ctx context.Context) error {
 }
 
 func (h *httpClientWriter) WriteWithContext(ctx context.Context, msg *message.Batch) error {
+	if h.spool != nil && h.spool.Len() > 0 {
+		return h.spool.Push(msg)
+	}
+
 	resultMsg, err := h.client.Send(ctx, msg, msg)
+	if err != nil && h.spool != nil {
+		if serr := h.spool.Push(msg); serr != nil {
+			h.log.Errorf("Failed to spool message batch: %v\n", serr)
+			return err
+		}
+		h.log.Warnf("Spooled message batch after failed request: %v\n", err)
+		return nil
+	}
 	if err == nil && h.conf.PropagateResponse {
 		msgCopy := msg.Copy()
 		parts := make([]*message.Part, resultMsg.Len())
@@ -155,9 +196,15 @@ func (h *httpClientWriter) WriteWithContext(ctx context.Context, msg *message.Ba
 
 func (h *httpClientWriter) CloseAsync() {
 	close(h.closeChan)
+	if h.spool != nil {
+		h.spool.CloseAsync()
+	}
 	go h.client.Close(context.Background())
 }
 
 func (h *httpClientWriter) WaitForClose(timeout time.Duration) error {
+	if h.spool != nil {
+		return h.spool.WaitForClose(timeout)
+	}
 	return nil
 }
@@ -0,0 +1,285 @@
+package io
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/old/util/retries"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+const httpClientSpoolExt = ".batch"
+
+var errHTTPClientSpoolFull = errors.New("spool is full")
+
+// spooledPart is the serialised form of a message part within a spool file.
+type spooledPart struct {
+	Content  []byte            `json:"content"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func spooledPartsFromBatch(msg *message.Batch) []spooledPart {
+	parts := make([]spooledPart, msg.Len())
+	_ = msg.Iter(func(i int, p *message.Part) error {
+		parts[i].Content = p.Get()
+		_ = p.MetaIter(func(k, v string) error {
+			if parts[i].Metadata == nil {
+				parts[i].Metadata = map[string]string{}
+			}
+			parts[i].Metadata[k] = v
+			return nil
+		})
+		return nil
+	})
+	return parts
+}
+
+func batchFromSpooledParts(parts []spooledPart) *message.Batch {
+	msg := message.QuickBatch(nil)
+	for _, p := range parts {
+		part := message.NewPart(p.Content)
+		for k, v := range p.Metadata {
+			part.MetaSet(k, v)
+		}
+		msg.Append(part)
+	}
+	return msg
+}
+
+// httpClientSpool is a bounded queue of message batches persisted within a
+// directory, where each batch is a file named after its sequence number. The
+// oldest batch is continuously retried in the background with an exponential
+// backoff until it is sent successfully, at which point it is removed.
+type httpClientSpool struct {
+	dir        string
+	maxBatches int
+	boffCtor   func() backoff.BackOff
+	send       func(ctx context.Context, msg *message.Batch) error
+
+	mut     sync.Mutex
+	pending []uint64
+	nextSeq uint64
+	notify  chan struct{}
+
+	log     log.Modular
+	shutSig *shutdown.Signaller
+}
+
+func newHTTPClientSpool(conf output.HTTPClientSpoolConfig, send func(ctx context.Context, msg *message.Batch) error, log log.Modular) (*httpClientSpool, error) {
+	if conf.Path == "" {
+		return nil, errors.New("a spool path must be specified")
+	}
+	if conf.MaxBatches <= 0 {
+		return nil, fmt.Errorf("spool max_batches must be greater than zero, got %v", conf.MaxBatches)
+	}
+
+	rConf := retries.Config{Backoff: conf.Backoff}
+	boffCtor, err := rConf.GetCtor()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &httpClientSpool{
+		dir:        conf.Path,
+		maxBatches: conf.MaxBatches,
+		boffCtor:   boffCtor,
+		send:       send,
+		notify:     make(chan struct{}, 1),
+		log:        log,
+		shutSig:    shutdown.NewSignaller(),
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+	if len(s.pending) > 0 {
+		s.log.Infof("Resuming %v batches from spool: %v\n", len(s.pending), s.dir)
+	}
+
+	go s.loop()
+	return s, nil
+}
+
+// recover reads the sequence numbers of batches left within the spool
+// directory by a previous run.
+func (s *httpClientSpool) recover() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, httpClientSpoolExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, httpClientSpoolExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.pending = append(s.pending, seq)
+	}
+	sort.Slice(s.pending, func(i, j int) bool {
+		return s.pending[i] < s.pending[j]
+	})
+	if l := len(s.pending); l > 0 {
+		s.nextSeq = s.pending[l-1] + 1
+	}
+	return nil
+}
+
+func (s *httpClientSpool) path(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%v", seq, httpClientSpoolExt))
+}
+
+// Len returns the number of batches within the spool.
+func (s *httpClientSpool) Len() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return len(s.pending)
+}
+
+// Push persists a batch to the spool, returning an error if the spool is full.
+func (s *httpClientSpool) Push(msg *message.Batch) error {
+	data, err := json.Marshal(spooledPartsFromBatch(msg))
+	if err != nil {
+		return err
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if len(s.pending) >= s.maxBatches {
+		return errHTTPClientSpoolFull
+	}
+
+	// Batches are written to a temporary file and then renamed so that a
+	// partially written batch is never recovered.
+	seq := s.nextSeq
+	tmpPath := s.path(seq) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write spool file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path(seq)); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write spool file: %w", err)
+	}
+	s.nextSeq++
+	s.pending = append(s.pending, seq)
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *httpClientSpool) front() (uint64, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if len(s.pending) == 0 {
+		return 0, false
+	}
+	return s.pending[0], true
+}
+
+func (s *httpClientSpool) pop(seq uint64) {
+	if err := os.Remove(s.path(seq)); err != nil && !os.IsNotExist(err) {
+		s.log.Errorf("Failed to remove spool file: %v\n", err)
+	}
+	s.mut.Lock()
+	s.pending = s.pending[1:]
+	s.mut.Unlock()
+}
+
+func (s *httpClientSpool) read(seq uint64) (*message.Batch, error) {
+	data, err := os.ReadFile(s.path(seq))
+	if err != nil {
+		return nil, err
+	}
+	var parts []spooledPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return nil, err
+	}
+	return batchFromSpooledParts(parts), nil
+}
+
+func (s *httpClientSpool) loop() {
+	defer s.shutSig.ShutdownComplete()
+
+	boff := s.boffCtor()
+	for {
+		seq, ok := s.front()
+		if !ok {
+			select {
+			case <-s.notify:
+				continue
+			case <-s.shutSig.CloseAtLeisureChan():
+				return
+			}
+		}
+
+		msg, err := s.read(seq)
+		if err != nil {
+			s.log.Errorf("Dropping unreadable spool file %v: %v\n", s.path(seq), err)
+			s.pop(seq)
+			continue
+		}
+
+		ctx, done := s.shutSig.CloseAtLeisureCtx(context.Background())
+		err = s.send(ctx, msg)
+		done()
+		if err == nil {
+			boff.Reset()
+			s.pop(seq)
+			continue
+		}
+
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			s.log.Errorf("Dropping spooled batch after exhausting retries: %v\n", err)
+			boff.Reset()
+			s.pop(seq)
+			continue
+		}
+		s.log.Warnf("Failed to send spooled batch, retrying in %v: %v\n", wait, err)
+
+		select {
+		case <-time.After(wait):
+		case <-s.shutSig.CloseAtLeisureChan():
+			return
+		}
+	}
+}
+
+// CloseAsync stops the background retries, batches remaining within the spool
+// are resumed by the next run.
+func (s *httpClientSpool) CloseAsync() {
+	s.shutSig.CloseAtLeisure()
+}
+
+// WaitForClose blocks until the background retries have stopped.
+func (s *httpClientSpool) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-s.shutSig.HasClosedChan():
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -594,3 +595,76 @@ func TestHTTPOutputClientMultipartHeaders(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestHTTPClientSpool(t *testing.T) {
+	resultChan := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server is unavailable to the first run by path rather than by a
+		// flag, as requests that were in flight when the first run closed may
+		// be handled after the second run has started.
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		resultChan <- string(b) + ":" + r.Header.Get("foo")
+	}))
+	defer ts.Close()
+
+	spoolDir := t.TempDir()
+
+	conf := output.NewHTTPClientConfig()
+	conf.URL = ts.URL + "/down"
+	conf.Headers["foo"] = `${! meta("foo") }`
+	conf.NumRetries = 0
+	conf.Spool.Enabled = true
+	conf.Spool.Path = spoolDir
+	conf.Spool.MaxBatches = 2
+	conf.Spool.Backoff.InitialInterval = "10ms"
+	conf.Spool.Backoff.MaxInterval = "10ms"
+
+	h, err := newHTTPClientWriter(conf, mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for _, content := range []string{"first", "second"} {
+		msg := message.QuickBatch([][]byte{[]byte(content)})
+		msg.Get(0).MetaSet("foo", content+"-meta")
+		require.NoError(t, h.WriteWithContext(context.Background(), msg))
+	}
+
+	// The spool is full and therefore further messages are rejected.
+	require.Error(t, h.WriteWithContext(context.Background(), message.QuickBatch([][]byte{[]byte("third")})))
+
+	// Spooled batches survive a restart.
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+
+	entries, err := os.ReadDir(spoolDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	conf.URL = ts.URL + "/testpost"
+	h, err = newHTTPClientWriter(conf, mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for _, exp := range []string{"first:first-meta", "second:second-meta"} {
+		select {
+		case res := <-resultChan:
+			assert.Equal(t, exp, res)
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		entries, err := os.ReadDir(spoolDir)
+		return err == nil && len(entries) == 0
+	}, time.Second*5, time.Millisecond*10)
+
+	require.NoError(t, h.WriteWithContext(context.Background(), message.QuickBatch([][]byte{[]byte("third")})))
+	assert.Equal(t, "third:null", <-resultChan)
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+}
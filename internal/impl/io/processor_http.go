@@ -130,7 +130,7 @@ func (h *httpProc) ProcessBatch(ctx context.Context, spans []*tracing.Span, msg
 
 	if h.asMultipart || msg.Len() == 1 {
 		// Easy, just do a single request.
-		resultMsg, err := h.client.Send(context.Background(), msg, msg)
+		resultMsg, err := h.client.Send(ctx, msg, msg)
 		if err != nil {
 			var codeStr string
 			var hErr component.ErrUnexpectedHTTPRes
@@ -170,7 +170,7 @@ func (h *httpProc) ProcessBatch(ctx context.Context, spans []*tracing.Span, msg
 		_ = msg.Iter(func(i int, p *message.Part) error {
 			tmpMsg := message.QuickBatch(nil)
 			tmpMsg.Append(p)
-			result, err := h.client.Send(context.Background(), tmpMsg, tmpMsg)
+			result, err := h.client.Send(ctx, tmpMsg, tmpMsg)
 			if err != nil {
 				h.log.Errorf("HTTP request to '%v' failed: %v", h.rawURL, err)
 
@@ -210,7 +210,7 @@ func (h *httpProc) ProcessBatch(ctx context.Context, spans []*tracing.Span, msg
 				for index := range reqChan {
 					tmpMsg := message.QuickBatch(nil)
 					tmpMsg.Append(msg.Get(index))
-					result, err := h.client.Send(context.Background(), tmpMsg, tmpMsg)
+					result, err := h.client.Send(ctx, tmpMsg, tmpMsg)
 					if err == nil && result.Len() != 1 {
 						err = fmt.Errorf("unexpected response size: %v", result.Len())
 					}
@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"github.com/benthosdev/benthos/v4/internal/compression"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func payloadCompressionFieldSpec() docs.FieldSpec {
+	spec := compression.FieldSpec("`content-encoding` header", "`kafka` and `kafka_franz` inputs")
+	spec.Description += " Unlike the field `compression` this reduces the size of large messages as they are stored and consumed. The `kafka` output requires a `target_version` of at least `0.11.0.0` in order to compress payloads."
+	return spec
+}
+
+func payloadCompressorFromParsed(conf *service.ParsedConfig) (*compression.Compressor, error) {
+	algorithm, err := conf.FieldString("algorithm")
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := conf.FieldInt("threshold")
+	if err != nil {
+		return nil, err
+	}
+	return compression.NewCompressor(algorithm, threshold)
+}
+
+// decompressMessage decompresses the contents of a message consumed from a
+// record that was compressed by a producer, removing its content encoding
+// header from the metadata of the message.
+func decompressMessage(msg *service.Message) error {
+	encoding, _ := msg.MetaGet(compression.KafkaHeader)
+	b, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	decoded, ok, err := compression.Decompress(encoding, b)
+	if err != nil {
+		return err
+	}
+	if ok {
+		msg.SetBytes(decoded)
+		msg.MetaDelete(compression.KafkaHeader)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	"github.com/twmb/franz-go/pkg/sasl"
 
 	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
@@ -44,8 +46,15 @@ This input adds the following metadata fields to each message:
 - kafka_partition
 - kafka_offset
 - kafka_timestamp_unix
+- kafka_timestamp_ms
 - All record headers
 ` + "```" + `
+
+### Rebalancing
+
+By default partitions are assigned with the cooperative sticky strategy, where a rebalance only revokes the partitions that move to another consumer, and consumers continue to process their remaining partitions throughout. All consumers of a group must support the cooperative protocol before it is used, and so when migrating a group from an eager strategy each consumer should first be restarted with both strategies listed.
+
+Setting an ` + "`instance_id`" + ` enables static membership, where a consumer that leaves the group and rejoins with the same instance ID within the session timeout of the group is assigned its previous partitions without triggering a rebalance. This prevents rolling restarts from causing rebalances across the whole group, and the duplicates that result from them. Each consumer of a group must have a unique instance ID, which is usually derived from the host, for example ` + "`${HOSTNAME}`" + `.
 `).
 		Field(service.NewStringListField("seed_brokers").
 			Description("A list of broker addresses to connect to in order to establish connections. If an item of the list contains commas it will be expanded into multiple addresses.").
@@ -59,10 +68,23 @@ This input adds the following metadata fields to each message:
 			Default(false)).
 		Field(service.NewStringField("consumer_group").
 			Description("A consumer group to consume as. Partitions are automatically distributed across consumers sharing a consumer group, and partition offsets are automatically commited and resumed under this name.")).
+		Field(service.NewStringField("instance_id").
+			Description("An optional identifier of this consumer that is unique within the consumer group, which enables static group membership. Consumers that restart with the same instance ID keep their partition assignments without triggering a rebalance.").
+			Example("${HOSTNAME}").
+			Optional().
+			Advanced().
+			Version("4.2.0")).
+		Field(service.NewStringListField("rebalance_strategies").
+			Description("A list of partition assignment strategies to support in order of preference, the first strategy supported by all consumers of the group is used. Options are `cooperative_sticky`, `sticky`, `range` and `round_robin`.").
+			Example([]string{"cooperative_sticky", "range"}).
+			Default([]string{"cooperative_sticky"}).
+			Advanced().
+			Version("4.2.0")).
 		Field(service.NewIntField("checkpoint_limit").
 			Description("Determines how many messages of the same partition can be processed in parallel before applying back pressure. When a message of a given offset is delivered to the output the offset is only allowed to be committed when all messages of prior offsets have also been delivered, this ensures at-least-once delivery guarantees. However, this mechanism also increases the likelihood of duplicates in the event of crashes or server faults, reducing the checkpoint limit will mitigate this.").
 			Default(1024).
 			Advanced()).
+		Field(service.NewInternalField(compression.AutoDecompressFieldSpec("`content-encoding` header", "`kafka_franz` output"))).
 		Field(service.NewTLSToggledField("tls")).
 		Field(saslField)
 }
@@ -97,6 +119,9 @@ type franzKafkaReader struct {
 	saslConfs       []sasl.Mechanism
 	checkpointLimit int
 	regexPattern    bool
+	instanceID      string
+	balancers       []kgo.GroupBalancer
+	autoDecompress  bool
 
 	msgChan atomic.Value
 	log     *service.Logger
@@ -142,10 +167,35 @@ func newFranzKafkaReaderFromConfig(conf *service.ParsedConfig, log *service.Logg
 		return nil, err
 	}
 
+	if conf.Contains("instance_id") {
+		if f.instanceID, err = conf.FieldString("instance_id"); err != nil {
+			return nil, err
+		}
+	}
+
+	strategies, err := conf.FieldStringList("rebalance_strategies")
+	if err != nil {
+		return nil, err
+	}
+	if len(strategies) == 0 {
+		return nil, errors.New("at least one rebalance strategy must be specified")
+	}
+	for _, s := range strategies {
+		balancer, err := groupBalancerFromStr(s)
+		if err != nil {
+			return nil, err
+		}
+		f.balancers = append(f.balancers, balancer)
+	}
+
 	if f.checkpointLimit, err = conf.FieldInt("checkpoint_limit"); err != nil {
 		return nil, err
 	}
 
+	if f.autoDecompress, err = conf.FieldBool("auto_decompress"); err != nil {
+		return nil, err
+	}
+
 	tlsConf, tlsEnabled, err := conf.FieldTLSToggled("tls")
 	if err != nil {
 		return nil, err
@@ -160,6 +210,20 @@ func newFranzKafkaReaderFromConfig(conf *service.ParsedConfig, log *service.Logg
 	return &f, nil
 }
 
+func groupBalancerFromStr(s string) (kgo.GroupBalancer, error) {
+	switch s {
+	case "cooperative_sticky":
+		return kgo.CooperativeStickyBalancer(), nil
+	case "sticky":
+		return kgo.StickyBalancer(), nil
+	case "range":
+		return kgo.RangeBalancer(), nil
+	case "round_robin":
+		return kgo.RoundRobinBalancer(), nil
+	}
+	return nil, fmt.Errorf("unrecognised rebalance strategy: %v", s)
+}
+
 //------------------------------------------------------------------------------
 
 type checkpointTracker struct {
@@ -267,6 +331,7 @@ func (f *franzKafkaReader) Connect(ctx context.Context) error {
 		kgo.SeedBrokers(f.seedBrokers...),
 		kgo.ConsumerGroup(f.consumerGroup),
 		kgo.ConsumeTopics(f.topics...),
+		kgo.Balancers(f.balancers...),
 		kgo.SASL(f.saslConfs...),
 		kgo.OnPartitionsRevoked(func(rctx context.Context, c *kgo.Client, m map[string][]int32) {
 			// Note: this is a best attempt, there's a chance of duplicates if
@@ -302,6 +367,9 @@ func (f *franzKafkaReader) Connect(ctx context.Context) error {
 		kgo.WithLogger(&kgoLogger{f.log}),
 	}
 
+	if f.instanceID != "" {
+		clientOpts = append(clientOpts, kgo.InstanceID(f.instanceID))
+	}
 	if f.tlsConf != nil {
 		clientOpts = append(clientOpts, kgo.DialTLSConfig(f.tlsConf))
 	}
@@ -362,6 +430,11 @@ func (f *franzKafkaReader) Connect(ctx context.Context) error {
 			for !iter.Done() {
 				record := iter.Next()
 				msg := recordToMessage(record)
+				if f.autoDecompress {
+					if err := decompressMessage(msg); err != nil {
+						f.log.Warnf("Failed to decompress record: %v", err)
+					}
+				}
 
 				// The record lives on for checkpointing, but we don't need the
 				// contents going forward so discard these. This looked fine to
@@ -427,6 +500,7 @@ func recordToMessage(record *kgo.Record) *service.Message {
 	msg.MetaSet("kafka_partition", strconv.Itoa(int(record.Partition)))
 	msg.MetaSet("kafka_offset", strconv.Itoa(int(record.Offset)))
 	msg.MetaSet("kafka_timestamp_unix", strconv.FormatInt(record.Timestamp.Unix(), 10))
+	msg.MetaSet("kafka_timestamp_ms", strconv.FormatInt(record.Timestamp.UnixNano()/int64(time.Millisecond), 10))
 	for _, hdr := range record.Headers {
 		msg.MetaSet(hdr.Key, string(hdr.Value))
 	}
@@ -3,8 +3,10 @@ package kafka
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +15,7 @@ import (
 	"github.com/Shopify/sarama"
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
+	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/checkpoint"
 	"github.com/benthosdev/benthos/v4/internal/component"
@@ -20,6 +23,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
 	"github.com/benthosdev/benthos/v4/internal/component/input/span"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/impl/kafka/sasl"
 	"github.com/benthosdev/benthos/v4/internal/log"
@@ -51,6 +55,7 @@ This input adds the following metadata fields to each message:
 - kafka_offset
 - kafka_lag
 - kafka_timestamp_unix
+- kafka_timestamp_ms
 - All existing message headers (version 0.11+)
 ` + "```" + `
 
@@ -58,10 +63,20 @@ The field ` + "`kafka_lag`" + ` is the calculated difference between the high wa
 
 You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).
 
+### Lag Metrics
+
+When the field ` + "`lag_metrics_period`" + ` is set this input periodically fetches the end offset of each partition that it consumes, and exports the difference between the end offset and the latest offset marked for commit as the gauge ` + "`input_kafka_lag`" + ` with the labels ` + "`topic`" + ` and ` + "`partition`" + `. This allows you to autoscale consumers based on their lag without deploying a separate lag exporter.
+
+Lag is only reported for a partition once an offset has been committed for it by the consumer group, or marked by this input.
+
 ### Ordering
 
 By default messages of a topic partition can be processed in parallel, up to a limit determined by the field ` + "`checkpoint_limit`" + `. However, if strict ordered processing is required then this value must be set to 1 in order to process shard messages in lock-step. When doing so it is recommended that you perform batching at this component for performance as it will not be possible to batch lock-stepped messages at the output level.
 
+### Rebalancing
+
+This input rebalances consumer groups with the eager protocol, where all partitions of the group are revoked during a rebalance, and does not support static group membership. For cooperative rebalancing and static membership, which prevent rolling restarts from rebalancing the whole group, use the ` + "[`kafka_franz` input](/docs/components/inputs/kafka_franz#rebalancing)" + ` instead.
+
 ### Troubleshooting
 
 If you're seeing issues writing to or reading from Kafka with this component then it's worth trying out the newer ` + "[`kafka_franz` input](/docs/components/inputs/kafka_franz)" + `.
@@ -90,6 +105,7 @@ Unfortunately this error message will appear for a wide range of connection prob
 			docs.FieldString("client_id", "An identifier for the client connection.").Advanced(),
 			docs.FieldString("rack_id", "A rack identifier for this client.").Advanced(),
 			docs.FieldBool("start_from_oldest", "If an offset is not found for a topic partition, determines whether to consume from the oldest available offset, otherwise messages are consumed from the latest offset.").Advanced(),
+			compression.AutoDecompressFieldSpec("`content-encoding` header", "`kafka` output"),
 			docs.FieldInt(
 				"checkpoint_limit", "The maximum number of messages of the same topic and partition that can be processed at a given time. Increasing this limit enables parallel processing and batching at the output level to work on individual partitions. Any given offset will not be committed unless all messages under that offset are delivered in order to preserve at least once delivery guarantees.",
 			).AtVersion("3.33.0"),
@@ -102,6 +118,8 @@ Unfortunately this error message will appear for a wide range of connection prob
 				docs.FieldString("rebalance_timeout", "A period after which rebalancing is abandoned if unresolved.").Advanced(),
 			).Advanced(),
 			docs.FieldInt("fetch_buffer_cap", "The maximum number of unprocessed messages to fetch at a given time.").Advanced(),
+			docs.FieldBool("shared_client", "Whether to share the underlying client with other `kafka` inputs of the service that also enable this field and have identical connection and consumer settings, which reduces the number of connections opened to the brokers. Only topics with explicit partitions can be consumed with a shared client, as consumer groups are unable to share a client.").Advanced().AtVersion("4.2.0"),
+			docs.FieldString("lag_metrics_period", "An optional period at which the end offsets of the consumed partitions are fetched in order to export the lag of each partition as a metric. When empty lag metrics are disabled. Check out the [lag metrics section](#lag-metrics) for more information.", "30s").Advanced().AtVersion("4.2.0"),
 			func() docs.FieldSpec {
 				b := policy.FieldSpec()
 				b.IsAdvanced = true
@@ -155,6 +173,9 @@ type kafkaReader struct {
 	heartbeatInterval time.Duration
 	rebalanceTimeout  time.Duration
 	maxProcPeriod     time.Duration
+	lagMetricsPeriod  time.Duration
+
+	lag *lagTracker
 
 	// Connection resources
 	cMut            sync.Mutex
@@ -287,9 +308,21 @@ func newKafkaReader(conf input.KafkaConfig, mgr bundle.NewManagement, log log.Mo
 			return nil, fmt.Errorf("failed to parse max processing period string: %v", err)
 		}
 	}
+	if tout := conf.LagMetricsPeriod; len(tout) > 0 {
+		var err error
+		if k.lagMetricsPeriod, err = time.ParseDuration(tout); err != nil {
+			return nil, fmt.Errorf("failed to parse lag metrics period string: %v", err)
+		}
+		if k.lagMetricsPeriod > 0 {
+			k.lag = newLagTracker(mgr.Metrics(), log)
+		}
+	}
 	if conf.ConsumerGroup == "" && len(k.balancedTopics) > 0 {
 		return nil, errors.New("a consumer group must be specified when consuming balanced topics")
 	}
+	if conf.SharedClient && len(k.balancedTopics) > 0 {
+		return nil, errors.New("a shared client cannot be used when consuming balanced topics as consumer groups are unable to share a client")
+	}
 
 	var err error
 	if k.version, err = sarama.ParseKafkaVersion(conf.TargetVersion); err != nil {
@@ -325,6 +358,9 @@ func (k *kafkaReader) asyncCheckpointer(topic string, partition int32) func(cont
 				if k.session != nil {
 					k.log.Debugf("Marking offset for topic '%v' partition '%v'.\n", topic, partition)
 					k.session.MarkOffset(topic, partition, maxOffset.(int64), "")
+					if k.lag != nil {
+						k.lag.mark(topic, partition, maxOffset.(int64))
+					}
 				} else {
 					k.log.Debugf("Unable to mark offset for topic '%v' partition '%v'.\n", topic, partition)
 				}
@@ -355,6 +391,9 @@ func (k *kafkaReader) syncCheckpointer(topic string, partition int32) func(conte
 					if k.session != nil {
 						k.log.Debugf("Marking offset for topic '%v' partition '%v'.\n", topic, partition)
 						k.session.MarkOffset(topic, partition, offset, "")
+						if k.lag != nil {
+							k.lag.mark(topic, partition, offset)
+						}
 					} else {
 						k.log.Debugf("Unable to mark offset for topic '%v' partition '%v'.\n", topic, partition)
 					}
@@ -383,6 +422,17 @@ func (k *kafkaReader) syncCheckpointer(topic string, partition int32) func(conte
 	}
 }
 
+// decompressPart decompresses the contents of a part when enabled and the
+// record was compressed by a producer.
+func (k *kafkaReader) decompressPart(part *message.Part) {
+	if !k.conf.AutoDecompress {
+		return
+	}
+	if err := compression.DecompressPart(part, compression.KafkaHeader); err != nil {
+		k.log.Warnf("%v\n", err)
+	}
+}
+
 func dataToPart(highestOffset int64, data *sarama.ConsumerMessage) *message.Part {
 	part := message.NewPart(data.Value)
 
@@ -401,6 +451,7 @@ func dataToPart(highestOffset int64, data *sarama.ConsumerMessage) *message.Part
 	part.MetaSet("kafka_offset", strconv.Itoa(int(data.Offset)))
 	part.MetaSet("kafka_lag", strconv.FormatInt(lag, 10))
 	part.MetaSet("kafka_timestamp_unix", strconv.FormatInt(data.Timestamp.Unix(), 10))
+	part.MetaSet("kafka_timestamp_ms", strconv.FormatInt(data.Timestamp.UnixNano()/int64(time.Millisecond), 10))
 
 	return part
 }
@@ -468,10 +519,65 @@ func (k *kafkaReader) ConnectWithContext(ctx context.Context) error {
 		return err
 	}
 
+	client, release, err := k.newClient(config)
+	if err != nil {
+		return err
+	}
+
 	if len(k.topicPartitions) > 0 {
-		return k.connectExplicitTopics(ctx, config)
+		return k.connectExplicitTopics(ctx, client, release)
+	}
+	return k.connectBalancedTopics(ctx, client, release)
+}
+
+// newClient returns a client along with a func to call instead of closing it,
+// where the client is shared with other inputs when the field shared_client is
+// set.
+func (k *kafkaReader) newClient(config *sarama.Config) (sarama.Client, func() error, error) {
+	if !k.conf.SharedClient {
+		client, err := sarama.NewClient(k.addresses, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, client.Close, nil
+	}
+
+	key, err := sharedClientKey(k.conf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, release, err := k.mgr.SharedClients().Acquire(key, func() (io.Closer, error) {
+		return sarama.NewClient(k.addresses, config)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	client, ok := c.(sarama.Client)
+	if !ok {
+		_ = release()
+		return nil, nil, fmt.Errorf("shared client has unexpected type: %T", c)
+	}
+	return client, release, nil
+}
+
+// sharedClientKey returns the key under which the client of a config is
+// shared, which excludes the fields that only affect the input itself.
+func sharedClientKey(conf input.KafkaConfig) (string, error) {
+	conf.Topics = nil
+	conf.ConsumerGroup = ""
+	conf.CheckpointLimit = 0
+	conf.ExtractTracingMap = ""
+	conf.LagMetricsPeriod = ""
+	conf.AutoDecompress = false
+	conf.Batching = batchconfig.Config{}
+	conf.SharedClient = false
+
+	keyBytes, err := json.Marshal(conf)
+	if err != nil {
+		return "", err
 	}
-	return k.connectBalancedTopics(ctx, config)
+	return "kafka_client:" + string(keyBytes), nil
 }
 
 // ReadWithContext attempts to read a message from a kafkaReader topic.
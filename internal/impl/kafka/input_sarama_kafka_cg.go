@@ -37,6 +37,11 @@ func (k *kafkaReader) ConsumeClaim(sess sarama.ConsumerGroupSession, claim saram
 	defer k.log.Debugf("Stopped consuming messages from topic '%v' partition '%v'\n", topic, partition)
 
 	latestOffset := claim.InitialOffset()
+	if k.lag != nil {
+		k.lag.track(topic, partition, latestOffset)
+		defer k.lag.untrack(topic, partition)
+	}
+
 	batchPolicy, err := policy.New(k.conf.Batching, k.mgr.IntoPath("kafka", "batching"))
 	if err != nil {
 		k.log.Errorf("Failed to initialise batch policy: %v.\n", err)
@@ -74,6 +79,7 @@ func (k *kafkaReader) ConsumeClaim(sess sarama.ConsumerGroupSession, claim saram
 
 			latestOffset = data.Offset
 			part := dataToPart(claim.HighWaterMarkOffset(), data)
+			k.decompressPart(part)
 
 			if batchPolicy.Add(part) {
 				nextTimedBatchChan = nil
@@ -89,13 +95,19 @@ func (k *kafkaReader) ConsumeClaim(sess sarama.ConsumerGroupSession, claim saram
 
 //------------------------------------------------------------------------------
 
-func (k *kafkaReader) connectBalancedTopics(ctx context.Context, config *sarama.Config) error {
+func (k *kafkaReader) connectBalancedTopics(ctx context.Context, client sarama.Client, releaseClient func() error) error {
 	// Start a new consumer group
-	group, err := sarama.NewConsumerGroup(k.addresses, k.conf.ConsumerGroup, config)
+	group, err := sarama.NewConsumerGroupFromClient(k.conf.ConsumerGroup, client)
 	if err != nil {
+		_ = releaseClient()
 		return err
 	}
 
+	lagCtx, lagDone := context.WithCancel(context.Background())
+	if k.lag != nil {
+		go k.lag.loop(lagCtx, client, k.lagMetricsPeriod)
+	}
+
 	// Handle errors
 	go func() {
 		for {
@@ -144,6 +156,8 @@ func (k *kafkaReader) connectBalancedTopics(ctx context.Context, config *sarama.
 		k.log.Debugln("Closing consumer group")
 
 		group.Close()
+		lagDone()
+		_ = releaseClient()
 
 		k.cMut.Lock()
 		if k.msgChan != nil {
@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// lagTracker tracks the latest marked offset of each consumed topic partition,
+// and periodically fetches the end offsets of those partitions in order to
+// export the lag of the consumer as metrics.
+type lagTracker struct {
+	mut       sync.Mutex
+	positions map[topicPartition]int64
+
+	gauge metrics.StatGaugeVec
+	log   log.Modular
+}
+
+func newLagTracker(stats metrics.Type, log log.Modular) *lagTracker {
+	return &lagTracker{
+		positions: map[topicPartition]int64{},
+		gauge:     stats.GetGaugeVec("input_kafka_lag", "topic", "partition"),
+		log:       log,
+	}
+}
+
+// track begins tracking a topic partition from an initial offset, which may be
+// one of the sarama.OffsetNewest or sarama.OffsetOldest sentinels if the
+// consumer has no committed offset, in which case the lag is not reported
+// until an offset is marked.
+func (l *lagTracker) track(topic string, partition int32, offset int64) {
+	l.mut.Lock()
+	l.positions[topicPartition{topic, partition}] = offset
+	l.mut.Unlock()
+}
+
+// untrack stops tracking a topic partition, which happens when the partition
+// is no longer consumed by this input.
+func (l *lagTracker) untrack(topic string, partition int32) {
+	l.mut.Lock()
+	delete(l.positions, topicPartition{topic, partition})
+	l.mut.Unlock()
+}
+
+// mark updates the position of a topic partition to the next offset to be
+// consumed following a commit.
+func (l *lagTracker) mark(topic string, partition int32, offset int64) {
+	l.mut.Lock()
+	if _, exists := l.positions[topicPartition{topic, partition}]; exists {
+		l.positions[topicPartition{topic, partition}] = offset
+	}
+	l.mut.Unlock()
+}
+
+func (l *lagTracker) snapshot() map[topicPartition]int64 {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	positions := make(map[topicPartition]int64, len(l.positions))
+	for tp, offset := range l.positions {
+		positions[tp] = offset
+	}
+	return positions
+}
+
+// update fetches the end offset of each tracked partition and sets the lag
+// metric of the partition accordingly.
+func (l *lagTracker) update(client sarama.Client) {
+	for tp, offset := range l.snapshot() {
+		if offset < 0 {
+			continue
+		}
+		endOffset, err := client.GetOffset(tp.topic, tp.partition, sarama.OffsetNewest)
+		if err != nil {
+			l.log.Debugf("Failed to fetch end offset for topic '%v' partition '%v': %v\n", tp.topic, tp.partition, err)
+			continue
+		}
+		lag := endOffset - offset
+		if lag < 0 {
+			lag = 0
+		}
+		l.gauge.With(tp.topic, strconv.Itoa(int(tp.partition))).Set(lag)
+	}
+}
+
+// loop updates the lag metrics every period until the context is cancelled.
+func (l *lagTracker) loop(ctx context.Context, client sarama.Client, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.update(client)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+type mockOffsetClient struct {
+	sarama.Client
+	endOffsets map[topicPartition]int64
+}
+
+func (m *mockOffsetClient) GetOffset(topic string, partition int32, t int64) (int64, error) {
+	if t != sarama.OffsetNewest {
+		return 0, errors.New("unexpected offset time")
+	}
+	offset, exists := m.endOffsets[topicPartition{topic, partition}]
+	if !exists {
+		return 0, errors.New("unknown partition")
+	}
+	return offset, nil
+}
+
+func TestLagTracker(t *testing.T) {
+	stats := metrics.NewLocal()
+	lag := newLagTracker(stats, log.Noop())
+
+	client := &mockOffsetClient{
+		endOffsets: map[topicPartition]int64{
+			{"foo", 0}: 100,
+			{"foo", 1}: 50,
+			{"bar", 0}: 10,
+		},
+	}
+
+	lag.track("foo", 0, 20)
+	lag.track("foo", 1, sarama.OffsetNewest)
+	lag.track("bar", 0, 5)
+	lag.track("baz", 0, 5)
+
+	lag.update(client)
+	assert.Equal(t, map[string]int64{
+		`input_kafka_lag{partition="0",topic="foo"}`: 80,
+		`input_kafka_lag{partition="0",topic="bar"}`: 5,
+	}, stats.GetCounters())
+
+	lag.mark("foo", 0, 90)
+	lag.mark("foo", 1, 45)
+	lag.mark("bar", 0, 12)
+	lag.untrack("baz", 0)
+
+	// Marking an untracked partition does not begin tracking it.
+	lag.mark("baz", 1, 0)
+
+	lag.update(client)
+	assert.Equal(t, map[string]int64{
+		`input_kafka_lag{partition="0",topic="foo"}`: 10,
+		`input_kafka_lag{partition="1",topic="foo"}`: 5,
+		`input_kafka_lag{partition="0",topic="bar"}`: 0,
+	}, stats.GetCounters())
+}
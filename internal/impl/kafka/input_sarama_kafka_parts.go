@@ -77,6 +77,7 @@ partMsgLoop:
 
 			latestOffset = data.Offset
 			part := dataToPart(consumer.HighWaterMarkOffset(), data)
+			k.decompressPart(part)
 
 			if batchPolicy.Add(part) {
 				nextTimedBatchChan = nil
@@ -125,29 +126,29 @@ func (k *kafkaReader) offsetPartitionPutRequest(consumerGroup string) *sarama.Of
 	return req
 }
 
-func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.Config) error {
+func (k *kafkaReader) connectExplicitTopics(ctx context.Context, client sarama.Client, releaseClient func() error) error {
 	var coordinator *sarama.Broker
 	var consumer sarama.Consumer
-	var client sarama.Client
 	var err error
 
+	// The coordinator belongs to the client, and therefore must not be closed
+	// when the client is shared with other inputs.
+	closeCoordinator := func() {
+		if coordinator != nil && !k.conf.SharedClient {
+			coordinator.Close()
+		}
+	}
+
 	defer func() {
 		if err != nil {
 			if consumer != nil {
 				consumer.Close()
 			}
-			if coordinator != nil {
-				coordinator.Close()
-			}
-			if client != nil {
-				client.Close()
-			}
+			closeCoordinator()
+			_ = releaseClient()
 		}
 	}()
 
-	if client, err = sarama.NewClient(k.addresses, config); err != nil {
-		return err
-	}
 	if len(k.conf.ConsumerGroup) > 0 {
 		if coordinator, err = client.Coordinator(k.conf.ConsumerGroup); err != nil {
 			return err
@@ -232,6 +233,10 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 				}
 			}
 
+			if k.lag != nil {
+				k.lag.track(topic, partition, offset)
+			}
+
 			consumerWG.Add(1)
 			partConsumers = append(partConsumers, partConsumer)
 			go k.runPartitionConsumer(ctx, &consumerWG, topic, partition, partConsumer)
@@ -240,6 +245,11 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 		k.log.Infof("Consuming kafka topic %v, partitions %v from brokers %s as group '%v'\n", topic, partitions, k.addresses, k.conf.ConsumerGroup)
 	}
 
+	lagCtx, lagDone := context.WithCancel(context.Background())
+	if k.lag != nil {
+		go k.lag.loop(lagCtx, client, k.lagMetricsPeriod)
+	}
+
 	doneCtx, doneFn := context.WithCancel(context.Background())
 	go func() {
 		defer doneFn()
@@ -272,10 +282,9 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 		}
 		k.cMut.Unlock()
 
-		if coordinator != nil {
-			coordinator.Close()
-		}
-		client.Close()
+		lagDone()
+		closeCoordinator()
+		_ = releaseClient()
 	}()
 
 	k.consumerCloseFn = doneFn
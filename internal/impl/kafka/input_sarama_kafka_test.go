@@ -1,8 +1,12 @@
 package kafka_test
 
 import (
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -14,6 +18,7 @@ func TestKafkaBadParams(t *testing.T) {
 	testCases := []struct {
 		name   string
 		topics []string
+		shared bool
 		errStr string
 	}{
 		{
@@ -31,6 +36,12 @@ func TestKafkaBadParams(t *testing.T) {
 			topics: []string{"foo:1-2-3"},
 			errStr: "partition '1-2-3' is invalid, only one range can be specified",
 		},
+		{
+			name:   "shared client with balanced topics",
+			topics: []string{"foo"},
+			shared: true,
+			errStr: "a shared client cannot be used when consuming balanced topics",
+		},
 	}
 
 	for _, test := range testCases {
@@ -40,6 +51,8 @@ func TestKafkaBadParams(t *testing.T) {
 			conf.Type = "kafka"
 			conf.Kafka.Addresses = []string{"example.com:1234"}
 			conf.Kafka.Topics = test.topics
+			conf.Kafka.ConsumerGroup = "bar"
+			conf.Kafka.SharedClient = test.shared
 
 			_, err := mock.NewManager().NewInput(conf)
 			require.Error(t, err)
@@ -47,3 +60,81 @@ func TestKafkaBadParams(t *testing.T) {
 		})
 	}
 }
+
+type countingListener struct {
+	net.Listener
+	accepted int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		atomic.AddInt32(&l.accepted, 1)
+	}
+	return conn, err
+}
+
+func TestKafkaSharedClient(t *testing.T) {
+	rawListener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	listener := &countingListener{Listener: rawListener}
+	broker := sarama.NewMockBrokerListener(t, 1, listener)
+	defer broker.Close()
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("foo", 0, broker.BrokerID()).
+			SetLeader("foo", 1, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).SetVersion(1).
+			SetOffset("foo", 0, sarama.OffsetOldest, 0).
+			SetOffset("foo", 0, sarama.OffsetNewest, 0).
+			SetOffset("foo", 1, sarama.OffsetOldest, 0).
+			SetOffset("foo", 1, sarama.OffsetNewest, 0),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(7),
+	})
+
+	offsetRequests := func() (n int) {
+		for _, r := range broker.History() {
+			if _, ok := r.Request.(*sarama.OffsetRequest); ok {
+				n++
+			}
+		}
+		return
+	}
+
+	mgr := mock.NewManager()
+	newInput := func(topic string) input.Streamed {
+		t.Helper()
+
+		conf := input.NewConfig()
+		conf.Type = "kafka"
+		conf.Kafka.Addresses = []string{broker.Addr()}
+		conf.Kafka.Topics = []string{topic}
+		conf.Kafka.SharedClient = true
+
+		in, err := mgr.NewInput(conf)
+		require.NoError(t, err)
+		return in
+	}
+
+	// An input has started consuming once it has fetched the oldest and newest
+	// offsets of its partition.
+	inA := newInput("foo:0")
+	require.Eventually(t, func() bool {
+		return offsetRequests() >= 2
+	}, time.Second*5, time.Millisecond*10)
+	conns := atomic.LoadInt32(&listener.accepted)
+
+	inB := newInput("foo:1")
+	require.Eventually(t, func() bool {
+		return offsetRequests() >= 4
+	}, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, conns, atomic.LoadInt32(&listener.accepted))
+
+	for _, in := range []input.Streamed{inA, inB} {
+		in.CloseAsync()
+		require.NoError(t, in.WaitForClose(time.Second*5))
+	}
+}
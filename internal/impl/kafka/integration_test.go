@@ -129,6 +129,43 @@ input:
 		}),
 		integration.StreamTestOptPort(kafkaPortStr),
 	)
+
+	t.Run("transactional", func(t *testing.T) {
+		template := `
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:$PORT ]
+    topic: topic-$ID
+    max_in_flight: $MAX_IN_FLIGHT
+    transactional_id: tx-$ID
+    batching:
+      count: $OUTPUT_BATCH_COUNT
+
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:$PORT ]
+    topics: [ topic-$ID$VAR1 ]
+    consumer_group: "$VAR4"
+    checkpoint_limit: 100
+`
+
+		suite := integration.StreamTests(
+			integration.StreamTestOpenClose(),
+			integration.StreamTestSendBatch(10),
+			integration.StreamTestStreamSequential(1000),
+			integration.StreamTestStreamParallel(1000),
+			integration.StreamTestSendBatchCount(10),
+		)
+
+		suite.Run(
+			t, template,
+			integration.StreamTestOptPreTest(func(t testing.TB, ctx context.Context, testID string, vars *integration.StreamTestConfigVars) {
+				vars.Var4 = "group" + testID
+				require.NoError(t, createKafkaTopic("localhost:"+kafkaPortStr, testID, 4))
+			}),
+			integration.StreamTestOptPort(kafkaPortStr),
+		)
+	})
 }
 
 func createKafkaTopicSasl(address, id string, partitions int32) error {
@@ -3,14 +3,19 @@ package kafka
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/sasl"
 
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
@@ -29,6 +34,20 @@ This output is new and experimental, and the existing ` + "`kafka`" + ` input is
 - You like shiny new stuff
 - You are experiencing issues with the existing ` + "`kafka`" + ` output
 - Someone told you to
+
+### Transactions
+
+When the field ` + "`transactional_id`" + ` is set each batch is written within a Kafka transaction, which is committed once all records of the batch have been acknowledged and aborted otherwise. Consumers reading with an isolation level of ` + "`read_committed`" + ` will therefore only ever see batches in their entirety, and the records of batches that failed and were retried are never exposed to them.
+
+Transactions are written one at a time, and therefore the field ` + "`max_in_flight`" + ` has no effect on throughput in this mode. The transactional ID must be unique to each instance of this output, as brokers fence off older producers that share an ID.
+
+The offsets of the input that the messages originated from are committed by that input once the transaction has been committed, and are not part of the transaction itself. This means a batch written from a Kafka input can be consumed and written a second time when the pipeline is interrupted between the two commits, in which case duplicates will be seen by consumers.
+
+### Mirroring
+
+When ` + "`mirror.enabled`" + ` is set to ` + "`true`" + ` records consumed from another cluster with a ` + "`kafka_franz`" + ` or ` + "`kafka`" + ` input are written to the same partition they were consumed from, with their original key and timestamp, and with the metadata of each message (excluding fields prefixed with ` + "`kafka_`" + `) written as headers. The target topic must therefore have at least as many partitions as the source topic, and topics can be named after their source with ` + "`topic: ${! meta(\"kafka_topic\") }`" + `.
+
+When ` + "`mirror.offset_cache`" + ` is set the offset of each mirrored record within the target topic is stored within the cache under the key ` + "`<prefix><source topic>/<partition>/<source offset>`" + `, and the most recently mirrored pair of offsets of each partition is stored as a JSON object ` + "`{\"source_offset\":10,\"target_offset\":8}`" + ` under the key ` + "`<prefix><source topic>/<partition>`" + `. Consumer groups can be migrated to the target cluster by translating their committed offsets with these keys: a committed source offset that has been mirrored maps to the offset stored under its key, and a committed offset beyond the most recently mirrored record maps to one after its target offset.
 `).
 		Field(service.NewStringListField("seed_brokers").
 			Description("A list of broker addresses to connect to in order to establish connections. If an item of the list contains commas it will be expanded into multiple addresses.").
@@ -62,6 +81,44 @@ This output is new and experimental, and the existing ` + "`kafka`" + ` input is
 			Description("Optionally set an explicit compression type. The default preference is to use snappy when the broker supports it, and fall back to none if not.").
 			Optional().
 			Advanced()).
+		Field(service.NewIntField("compression_level").
+			Description("An optional level of compression to use with the `gzip`, `lz4` and `zstd` compression types, where higher levels trade throughput for smaller payloads. Requires the field `compression` to be set.").
+			Optional().
+			Advanced().
+			Version("4.2.0")).
+		Field(service.NewInternalField(payloadCompressionFieldSpec())).
+		Field(service.NewDurationField("linger").
+			Description("An optional period of time to accumulate records destined for the same partition before sending them, which allows records from multiple batches in flight to be combined into larger produce requests. Records are still sent early once they reach `max_message_bytes`.").
+			Example("5ms").
+			Example("100ms").
+			Optional().
+			Advanced().
+			Version("4.2.0")).
+		Field(service.NewStringField("transactional_id").
+			Description("An optional transactional ID to produce records with, which causes each batch to be written within a transaction. Read more about transactions [in this section](#transactions).").
+			Optional().
+			Advanced().
+			Version("4.2.0")).
+		Field(service.NewObjectField("mirror",
+			service.NewBoolField("enabled").
+				Description("Whether to mirror records consumed from another Kafka cluster, preserving their partitions, keys, timestamps and headers.").
+				Default(false),
+			service.NewStringField("offset_cache").
+				Description("An optional [cache resource](/docs/components/caches/about) to store the translation of source offsets to target offsets within, allowing consumer groups to be migrated to the target cluster.").
+				Default(""),
+			service.NewStringField("offset_key_prefix").
+				Description("A prefix to add to the keys of offset translations stored within the cache.").
+				Default("").
+				Advanced(),
+			service.NewDurationField("offset_ttl").
+				Description("An optional TTL to set on offset translations stored within the cache, for caches that support it.").
+				Example("168h").
+				Optional().
+				Advanced(),
+		).
+			Description("Write records in a [mirroring](#mirroring) mode that preserves the partitions, keys, timestamps and headers of records consumed from another cluster.").
+			Advanced().
+			Version("4.2.0")).
 		Field(service.NewTLSToggledField("tls")).
 		Field(saslField)
 }
@@ -80,7 +137,7 @@ func init() {
 			if batchPolicy, err = conf.FieldBatchPolicy("batching"); err != nil {
 				return
 			}
-			output, err = newFranzKafkaWriterFromConfig(conf, mgr.Logger())
+			output, err = newFranzKafkaWriterFromConfig(conf, mgr)
 			return
 		})
 
@@ -92,26 +149,37 @@ func init() {
 //------------------------------------------------------------------------------
 
 type franzKafkaWriter struct {
-	seedBrokers      []string
-	topicStr         string
-	topic            *service.InterpolatedString
-	key              *service.InterpolatedString
-	tlsConf          *tls.Config
-	saslConfs        []sasl.Mechanism
-	metaFilter       *service.MetadataFilter
-	partitioner      kgo.Partitioner
-	produceMaxBytes  int32
-	compressionPrefs []kgo.CompressionCodec
+	seedBrokers       []string
+	topicStr          string
+	topic             *service.InterpolatedString
+	key               *service.InterpolatedString
+	tlsConf           *tls.Config
+	saslConfs         []sasl.Mechanism
+	metaFilter        *service.MetadataFilter
+	partitioner       kgo.Partitioner
+	produceMaxBytes   int32
+	compressionPrefs  []kgo.CompressionCodec
+	payloadCompressor *compression.Compressor
+	linger            time.Duration
+	transactionalID   string
+
+	mirror          bool
+	offsetCache     string
+	offsetKeyPrefix string
+	offsetTTL       *time.Duration
 
 	client *kgo.Client
+	txMut  sync.Mutex
 
+	mgr     *service.Resources
 	log     *service.Logger
 	shutSig *shutdown.Signaller
 }
 
-func newFranzKafkaWriterFromConfig(conf *service.ParsedConfig, log *service.Logger) (*franzKafkaWriter, error) {
+func newFranzKafkaWriterFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*franzKafkaWriter, error) {
 	f := franzKafkaWriter{
-		log:     log,
+		mgr:     mgr,
+		log:     mgr.Logger(),
 		shutSig: shutdown.NewSignaller(),
 	}
 
@@ -168,7 +236,28 @@ func newFranzKafkaWriterFromConfig(conf *service.ParsedConfig, log *service.Logg
 		default:
 			return nil, fmt.Errorf("compression codec %v not recognised", cStr)
 		}
+		if conf.Contains("compression_level") {
+			level, err := conf.FieldInt("compression_level")
+			if err != nil {
+				return nil, err
+			}
+			c = c.WithLevel(level)
+		}
 		f.compressionPrefs = append(f.compressionPrefs, c)
+	} else if conf.Contains("compression_level") {
+		return nil, errors.New("field compression_level requires the field compression to be set")
+	}
+
+	if conf.Contains("linger") {
+		if f.linger, err = conf.FieldDuration("linger"); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("transactional_id") {
+		if f.transactionalID, err = conf.FieldString("transactional_id"); err != nil {
+			return nil, err
+		}
 	}
 
 	f.partitioner = kgo.StickyKeyPartitioner(nil)
@@ -193,6 +282,14 @@ func newFranzKafkaWriterFromConfig(conf *service.ParsedConfig, log *service.Logg
 		}
 	}
 
+	if err := f.mirrorFromParsed(conf.Namespace("mirror"), conf.Contains("partitioner")); err != nil {
+		return nil, err
+	}
+
+	if f.payloadCompressor, err = payloadCompressorFromParsed(conf.Namespace("payload_compression")); err != nil {
+		return nil, err
+	}
+
 	tlsConf, tlsEnabled, err := conf.FieldTLSToggled("tls")
 	if err != nil {
 		return nil, err
@@ -207,6 +304,34 @@ func newFranzKafkaWriterFromConfig(conf *service.ParsedConfig, log *service.Logg
 	return &f, nil
 }
 
+func (f *franzKafkaWriter) mirrorFromParsed(conf *service.ParsedConfig, hasPartitioner bool) (err error) {
+	if f.mirror, err = conf.FieldBool("enabled"); err != nil || !f.mirror {
+		return
+	}
+	if hasPartitioner {
+		return errors.New("a partitioner cannot be set when mirroring, as records are written to the partition they were consumed from")
+	}
+	f.partitioner = kgo.ManualPartitioner()
+
+	if f.offsetCache, err = conf.FieldString("offset_cache"); err != nil {
+		return
+	}
+	if f.offsetCache != "" && !f.mgr.HasCache(f.offsetCache) {
+		return fmt.Errorf("cache resource '%v' was not found", f.offsetCache)
+	}
+	if f.offsetKeyPrefix, err = conf.FieldString("offset_key_prefix"); err != nil {
+		return
+	}
+	if conf.Contains("offset_ttl") {
+		var ttl time.Duration
+		if ttl, err = conf.FieldDuration("offset_ttl"); err != nil {
+			return
+		}
+		f.offsetTTL = &ttl
+	}
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 func (f *franzKafkaWriter) Connect(ctx context.Context) error {
@@ -230,6 +355,12 @@ func (f *franzKafkaWriter) Connect(ctx context.Context) error {
 	if len(f.compressionPrefs) > 0 {
 		clientOpts = append(clientOpts, kgo.ProducerBatchCompression(f.compressionPrefs...))
 	}
+	if f.linger > 0 {
+		clientOpts = append(clientOpts, kgo.ProducerLinger(f.linger))
+	}
+	if f.transactionalID != "" {
+		clientOpts = append(clientOpts, kgo.TransactionalID(f.transactionalID))
+	}
 
 	cl, err := kgo.NewClient(clientOpts...)
 	if err != nil {
@@ -247,6 +378,7 @@ func (f *franzKafkaWriter) WriteBatch(ctx context.Context, b service.MessageBatc
 	}
 
 	records := make([]*kgo.Record, 0, len(b))
+	var sources []mirrorSource
 	for i, msg := range b {
 		record := &kgo.Record{Topic: b.InterpolatedString(i, f.topic)}
 		if record.Value, err = msg.AsBytes(); err != nil {
@@ -255,6 +387,13 @@ func (f *franzKafkaWriter) WriteBatch(ctx context.Context, b service.MessageBatc
 		if f.key != nil {
 			record.Key = b.InterpolatedBytes(i, f.key)
 		}
+		if f.mirror {
+			var src mirrorSource
+			if src, err = mirrorRecord(msg, record, f.key == nil, f.metaFilter == nil); err != nil {
+				return
+			}
+			sources = append(sources, src)
+		}
 		_ = f.metaFilter.Walk(msg, func(key, value string) error {
 			record.Headers = append(record.Headers, kgo.RecordHeader{
 				Key:   key,
@@ -262,15 +401,161 @@ func (f *franzKafkaWriter) WriteBatch(ctx context.Context, b service.MessageBatc
 			})
 			return nil
 		})
+		var encoding string
+		if record.Value, encoding, err = f.payloadCompressor.Compress(record.Value); err != nil {
+			return
+		}
+		if encoding != "" {
+			record.Headers = append(record.Headers, kgo.RecordHeader{
+				Key:   compression.KafkaHeader,
+				Value: []byte(encoding),
+			})
+		}
 		records = append(records, record)
 	}
 
-	// TODO: This is very cool and allows us to easily return granular errors,
-	// so we should honor travis by doing it.
-	err = f.client.ProduceSync(ctx, records...).FirstErr()
+	if f.transactionalID != "" {
+		err = f.produceTransaction(ctx, records)
+	} else {
+		// TODO: This is very cool and allows us to easily return granular
+		// errors, so we should honor travis by doing it.
+		err = f.client.ProduceSync(ctx, records...).FirstErr()
+	}
+	if err != nil || f.offsetCache == "" {
+		return
+	}
+	// The records have already been written, and so failing to store their
+	// offsets must not result in them being written again.
+	if serr := f.storeOffsets(ctx, sources, records); serr != nil {
+		f.log.Errorf("Failed to store offset translations of mirrored records: %v", serr)
+	}
 	return
 }
 
+// mirrorSource is the location of a mirrored record within the source cluster.
+type mirrorSource struct {
+	topic     string
+	partition int32
+	offset    int64
+}
+
+// mirrorRecord populates a record from the metadata added by the kafka inputs
+// so that it matches the record it was consumed from.
+func mirrorRecord(msg *service.Message, record *kgo.Record, withKey, withHeaders bool) (src mirrorSource, err error) {
+	partStr, _ := msg.MetaGet("kafka_partition")
+	partition, err := strconv.ParseInt(partStr, 10, 32)
+	if err != nil {
+		return src, fmt.Errorf("failed to parse kafka_partition metadata for mirroring: %w", err)
+	}
+	record.Partition = int32(partition)
+
+	src.topic, _ = msg.MetaGet("kafka_topic")
+	src.partition = record.Partition
+	offsetStr, _ := msg.MetaGet("kafka_offset")
+	if src.offset, err = strconv.ParseInt(offsetStr, 10, 64); err != nil {
+		return src, fmt.Errorf("failed to parse kafka_offset metadata for mirroring: %w", err)
+	}
+
+	if withKey {
+		if key, _ := msg.MetaGet("kafka_key"); key != "" {
+			record.Key = []byte(key)
+		}
+	}
+
+	if tsStr, exists := msg.MetaGet("kafka_timestamp_ms"); exists {
+		if ms, err := strconv.ParseInt(tsStr, 10, 64); err == nil {
+			record.Timestamp = time.Unix(0, ms*int64(time.Millisecond))
+		}
+	} else if tsStr, exists := msg.MetaGet("kafka_timestamp_unix"); exists {
+		if secs, err := strconv.ParseInt(tsStr, 10, 64); err == nil {
+			record.Timestamp = time.Unix(secs, 0)
+		}
+	}
+
+	if withHeaders {
+		_ = msg.MetaWalk(func(key, value string) error {
+			if !strings.HasPrefix(key, "kafka_") {
+				record.Headers = append(record.Headers, kgo.RecordHeader{
+					Key:   key,
+					Value: []byte(value),
+				})
+			}
+			return nil
+		})
+	}
+	return src, nil
+}
+
+type batchedCache interface {
+	SetMulti(ctx context.Context, keyValues ...service.CacheItem) error
+}
+
+// storeOffsets writes the target offsets of mirrored records to the offset
+// cache.
+func (f *franzKafkaWriter) storeOffsets(ctx context.Context, sources []mirrorSource, records []*kgo.Record) error {
+	items := make([]service.CacheItem, 0, len(records))
+	latest := map[string]*kgo.Record{}
+	latestSources := map[string]mirrorSource{}
+	for i, record := range records {
+		src := sources[i]
+		partKey := fmt.Sprintf("%v%v/%v", f.offsetKeyPrefix, src.topic, src.partition)
+		items = append(items, service.CacheItem{
+			Key:   fmt.Sprintf("%v/%v", partKey, src.offset),
+			Value: []byte(strconv.FormatInt(record.Offset, 10)),
+			TTL:   f.offsetTTL,
+		})
+		if prev, exists := latestSources[partKey]; !exists || src.offset > prev.offset {
+			latest[partKey] = record
+			latestSources[partKey] = src
+		}
+	}
+	for partKey, record := range latest {
+		items = append(items, service.CacheItem{
+			Key:   partKey,
+			Value: []byte(fmt.Sprintf(`{"source_offset":%v,"target_offset":%v}`, latestSources[partKey].offset, record.Offset)),
+			TTL:   f.offsetTTL,
+		})
+	}
+
+	var setErr error
+	if err := f.mgr.AccessCache(ctx, f.offsetCache, func(c service.Cache) {
+		if bc, ok := c.(batchedCache); ok {
+			setErr = bc.SetMulti(ctx, items...)
+			return
+		}
+		for _, item := range items {
+			if setErr = c.Set(ctx, item.Key, item.Value, item.TTL); setErr != nil {
+				return
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	return setErr
+}
+
+func (f *franzKafkaWriter) produceTransaction(ctx context.Context, records []*kgo.Record) error {
+	// A producer can only have one transaction open at a time.
+	f.txMut.Lock()
+	defer f.txMut.Unlock()
+
+	if err := f.client.BeginTransaction(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := f.client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		if abortErr := f.client.EndTransaction(ctx, kgo.TryAbort); abortErr != nil {
+			f.log.Errorf("Failed to abort transaction: %v", abortErr)
+		}
+		return err
+	}
+
+	if err := f.client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (f *franzKafkaWriter) disconnect() {
 	if f.client == nil {
 		return
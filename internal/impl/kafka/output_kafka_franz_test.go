@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestFranzKafkaOutputMirrorConfig(t *testing.T) {
+	spec := franzKafkaOutputConfig()
+	env := service.NewEnvironment()
+
+	conf, err := spec.ParseYAML(`
+seed_brokers: [ localhost:9092 ]
+topic: ${! meta("kafka_topic") }
+partitioner: round_robin
+mirror:
+  enabled: true
+`, env)
+	require.NoError(t, err)
+
+	_, err = newFranzKafkaWriterFromConfig(conf, service.MockResources())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "partitioner cannot be set when mirroring")
+
+	conf, err = spec.ParseYAML(`
+seed_brokers: [ localhost:9092 ]
+topic: ${! meta("kafka_topic") }
+mirror:
+  enabled: true
+  offset_cache: nope
+`, env)
+	require.NoError(t, err)
+
+	_, err = newFranzKafkaWriterFromConfig(conf, service.MockResources())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cache resource 'nope' was not found")
+
+	conf, err = spec.ParseYAML(`
+seed_brokers: [ localhost:9092 ]
+topic: ${! meta("kafka_topic") }
+mirror:
+  enabled: true
+`, env)
+	require.NoError(t, err)
+
+	w, err := newFranzKafkaWriterFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	assert.True(t, w.mirror)
+	assert.Equal(t, "", w.offsetCache)
+}
+
+func TestFranzKafkaMirrorRecord(t *testing.T) {
+	msg := service.NewMessage([]byte("hello world"))
+	msg.MetaSet("kafka_key", "foo")
+	msg.MetaSet("kafka_topic", "source")
+	msg.MetaSet("kafka_partition", "3")
+	msg.MetaSet("kafka_offset", "42")
+	msg.MetaSet("kafka_timestamp_unix", "1651234567")
+	msg.MetaSet("kafka_timestamp_ms", "1651234567890")
+	msg.MetaSet("trace_id", "abc")
+
+	record := &kgo.Record{Topic: "source"}
+	src, err := mirrorRecord(msg, record, true, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, mirrorSource{topic: "source", partition: 3, offset: 42}, src)
+	assert.Equal(t, int32(3), record.Partition)
+	assert.Equal(t, []byte("foo"), record.Key)
+	assert.Equal(t, time.Unix(0, 1651234567890*int64(time.Millisecond)), record.Timestamp)
+	assert.Equal(t, []kgo.RecordHeader{{Key: "trace_id", Value: []byte("abc")}}, record.Headers)
+
+	record = &kgo.Record{Topic: "source", Key: []byte("bar")}
+	_, err = mirrorRecord(msg, record, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar"), record.Key)
+	assert.Empty(t, record.Headers)
+
+	msg.MetaDelete("kafka_partition")
+	_, err = mirrorRecord(msg, &kgo.Record{}, true, true)
+	require.Error(t, err)
+}
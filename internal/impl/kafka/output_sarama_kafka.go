@@ -3,8 +3,10 @@ package kafka
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"hash"
+	"io"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +17,7 @@ import (
 
 	batchInternal "github.com/benthosdev/benthos/v4/internal/batch"
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
+	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
@@ -22,6 +25,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/component/output/batcher"
 	"github.com/benthosdev/benthos/v4/internal/component/output/processors"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/impl/kafka/sasl"
 	"github.com/benthosdev/benthos/v4/internal/log"
@@ -52,6 +56,12 @@ You must also ensure that failed batches are never rerouted back to the same out
 
 However, this also means that manual intervention will eventually be required in cases where the batch cannot be sent due to configuration problems such as an incorrect `+"`max_msg_bytes`"+` estimate. A less strict but automated alternative would be to route failed batches to a dead letter queue using a `+"[`fallback` broker](/docs/components/outputs/fallback)"+`, but this would allow subsequent batches to be delivered in the meantime whilst those failed batches are dealt with.
 
+### Idempotent Writes
+
+When the field `+"`idempotent_write`"+` is enabled the producer is assigned an ID by the brokers and sequence numbers are attached to records, allowing the brokers to discard duplicates of records that are resent after intermittent errors. This requires a `+"`target_version`"+` of at least `+"`0.11.0.0`"+`, limits the producer to a single request in flight per broker, and acknowledgement from all replicas is always awaited regardless of the field `+"`ack_replicas`"+`.
+
+Writing batches within Kafka transactions is not supported by this output, for that use the `+"[`kafka_franz` output](/docs/components/outputs/kafka_franz#transactions)"+`.
+
 ### Troubleshooting
 
 If you're seeing issues writing to or reading from Kafka with this component then it's worth trying out the newer `+"[`kafka_franz` output](/docs/components/outputs/kafka_franz)"+`.
@@ -71,13 +81,19 @@ Unfortunately this error message will appear for a wide range of connection prob
 			docs.FieldString("partitioner", "The partitioning algorithm to use.").HasOptions("fnv1a_hash", "murmur2_hash", "random", "round_robin", "manual"),
 			docs.FieldString("partition", "The manually-specified partition to publish messages to, relevant only when the field `partitioner` is set to `manual`. Must be able to parse as a 32-bit integer.").IsInterpolated().Advanced(),
 			docs.FieldString("compression", "The compression algorithm to use.").HasOptions("none", "snappy", "lz4", "gzip", "zstd"),
+			docs.FieldInt("compression_level", "The level of compression to use with the `gzip`, `lz4` and `zstd` algorithms, where higher levels trade throughput for smaller payloads. A value of `-1` uses the default level of the algorithm.").Advanced().AtVersion("4.2.0"),
 			docs.FieldString("static_headers", "An optional map of static headers that should be added to messages in addition to metadata.", map[string]string{"first-static-header": "value-1", "second-static-header": "value-2"}).Map(),
 			docs.FieldObject("metadata", "Specify criteria for which metadata values are sent with messages as headers.").WithChildren(metadata.ExcludeFilterFields()...),
+			payloadCompressionFieldSpec(),
 			output.InjectTracingSpanMappingDocs,
 			docs.FieldInt("max_in_flight", "The maximum number of parallel message batches to have in flight at any given time."),
 			docs.FieldBool("ack_replicas", "Ensure that messages have been copied across all replicas before acknowledging receipt.").Advanced(),
+			docs.FieldBool("idempotent_write", "Enable the idempotent producer, which prevents duplicate records from being written when sends are retried after intermittent errors. Requires a `target_version` of at least `0.11.0.0`.").Advanced().AtVersion("4.2.0"),
 			docs.FieldInt("max_msg_bytes", "The maximum size in bytes of messages sent to the target topic.").Advanced(),
+			docs.FieldString("linger", "An optional period of time to accumulate records destined for the same partition before sending them, which allows records from multiple batches in flight to be combined into larger produce requests. When empty records are sent as soon as possible.", "5ms", "100ms").Advanced().AtVersion("4.2.0"),
+			docs.FieldInt("linger_bytes", "The size in bytes of accumulated records for a partition that triggers a send before the `linger` period has elapsed. A value of `0` means records are only sent once the `linger` period has elapsed.").Advanced().AtVersion("4.2.0"),
 			docs.FieldString("timeout", "The maximum period of time to wait for message sends before abandoning the request and retrying.").Advanced(),
+			docs.FieldBool("shared_client", "Whether to share the underlying producer with other `kafka` outputs of the service that also enable this field and have identical connection and producer settings, which reduces the number of connections opened to the brokers. Fields that are evaluated per message or only affect the output itself, such as `topic`, `key`, `static_headers`, `max_in_flight` and `batching`, can differ between outputs that share a producer.").Advanced().AtVersion("4.2.0"),
 			docs.FieldBool("retry_as_batch", "When enabled forces an entire batch of messages to be retried if any individual message fails on a send, otherwise only the individual messages that failed are retried. Disabling this helps to reduce message duplicates during intermittent errors, but also makes it impossible to guarantee strict ordering of messages.").Advanced(),
 			policy.FieldSpec(),
 		).WithChildren(retries.FieldSpecs()...).ChildDefaultAndTypesFromStruct(output.NewKafkaConfig()),
@@ -124,6 +140,7 @@ type kafkaWriter struct {
 
 	tlsConf *tls.Config
 	timeout time.Duration
+	linger  time.Duration
 
 	addresses []string
 	version   sarama.KafkaVersion
@@ -134,18 +151,21 @@ type kafkaWriter struct {
 	partition *field.Expression
 
 	producer    sarama.SyncProducer
+	release     func() error
 	compression sarama.CompressionCodec
 	partitioner sarama.PartitionerConstructor
 
 	staticHeaders map[string]string
 	metaFilter    *metadata.ExcludeFilter
 
+	payloadCompressor *compression.Compressor
+
 	connMut sync.RWMutex
 }
 
 // NewKafkaWriter returns a kafka writer.
 func NewKafkaWriter(conf output.KafkaConfig, mgr bundle.NewManagement, log log.Modular) (output.AsyncSink, error) {
-	compression, err := strToCompressionCodec(conf.Compression)
+	codec, err := strToCompressionCodec(conf.Compression)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +186,7 @@ func NewKafkaWriter(conf output.KafkaConfig, mgr bundle.NewManagement, log log.M
 		mgr: mgr,
 
 		conf:          conf,
-		compression:   compression,
+		compression:   codec,
 		partitioner:   partitioner,
 		staticHeaders: conf.StaticHeaders,
 	}
@@ -195,6 +215,13 @@ func NewKafkaWriter(conf output.KafkaConfig, mgr bundle.NewManagement, log log.M
 		}
 	}
 
+	if linger := conf.Linger; len(linger) > 0 {
+		var err error
+		if k.linger, err = time.ParseDuration(linger); err != nil {
+			return nil, fmt.Errorf("failed to parse linger string: %v", err)
+		}
+	}
+
 	if conf.TLS.Enabled {
 		var err error
 		if k.tlsConf, err = conf.TLS.Get(); err != nil {
@@ -205,6 +232,15 @@ func NewKafkaWriter(conf output.KafkaConfig, mgr bundle.NewManagement, log log.M
 	if k.version, err = sarama.ParseKafkaVersion(conf.TargetVersion); err != nil {
 		return nil, err
 	}
+	if conf.IdempotentWrite && !k.version.IsAtLeast(sarama.V0_11_0_0) {
+		return nil, fmt.Errorf("idempotent_write requires a target_version of at least 0.11.0.0, got %v", conf.TargetVersion)
+	}
+	if k.payloadCompressor, err = conf.PayloadCompression.Compressor(); err != nil {
+		return nil, err
+	}
+	if k.payloadCompressor != nil && !k.version.IsAtLeast(sarama.V0_11_0_0) {
+		return nil, fmt.Errorf("payload_compression requires a target_version of at least 0.11.0.0, got %v", conf.TargetVersion)
+	}
 
 	for _, addr := range conf.Addresses {
 		for _, splitAddr := range strings.Split(addr, ",") {
@@ -314,6 +350,15 @@ func (k *kafkaWriter) ConnectWithContext(ctx context.Context) error {
 	config.Version = k.version
 
 	config.Producer.Compression = k.compression
+	if k.conf.CompressionLevel != -1 {
+		config.Producer.CompressionLevel = k.conf.CompressionLevel
+	}
+	if k.linger > 0 {
+		// Records are accumulated per partition by the producer until either
+		// threshold is reached.
+		config.Producer.Flush.Frequency = k.linger
+		config.Producer.Flush.Bytes = k.conf.LingerBytes
+	}
 	config.Producer.Partitioner = k.partitioner
 	config.Producer.MaxMessageBytes = k.conf.MaxMsgBytes
 	config.Producer.Timeout = k.timeout
@@ -327,14 +372,20 @@ func (k *kafkaWriter) ConnectWithContext(ctx context.Context) error {
 		return err
 	}
 
-	if k.conf.AckReplicas {
+	if k.conf.AckReplicas || k.conf.IdempotentWrite {
 		config.Producer.RequiredAcks = sarama.WaitForAll
 	} else {
 		config.Producer.RequiredAcks = sarama.WaitForLocal
 	}
+	if k.conf.IdempotentWrite {
+		// Sequence numbers can only be guaranteed in order with a single
+		// request in flight.
+		config.Producer.Idempotent = true
+		config.Net.MaxOpenRequests = 1
+	}
 
 	var err error
-	k.producer, err = sarama.NewSyncProducer(k.addresses, config)
+	k.producer, k.release, err = k.newProducer(config)
 
 	if err == nil {
 		k.log.Infof("Sending Kafka messages to addresses: %s\n", k.addresses)
@@ -342,6 +393,59 @@ func (k *kafkaWriter) ConnectWithContext(ctx context.Context) error {
 	return err
 }
 
+// newProducer returns a producer along with a func to call instead of closing
+// it, where the producer is shared with other outputs when the field
+// shared_client is set.
+func (k *kafkaWriter) newProducer(config *sarama.Config) (sarama.SyncProducer, func() error, error) {
+	if !k.conf.SharedClient {
+		producer, err := sarama.NewSyncProducer(k.addresses, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return producer, producer.Close, nil
+	}
+
+	key, err := sharedProducerKey(k.conf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, release, err := k.mgr.SharedClients().Acquire(key, func() (io.Closer, error) {
+		return sarama.NewSyncProducer(k.addresses, config)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	producer, ok := c.(sarama.SyncProducer)
+	if !ok {
+		_ = release()
+		return nil, nil, fmt.Errorf("shared client has unexpected type: %T", c)
+	}
+	return producer, release, nil
+}
+
+// sharedProducerKey returns the key under which the producer of a config is
+// shared, which excludes the fields that are evaluated per message or only
+// affect the output itself.
+func sharedProducerKey(conf output.KafkaConfig) (string, error) {
+	conf.Key, conf.Partition, conf.Topic = "", "", ""
+	conf.StaticHeaders = nil
+	conf.PayloadCompression = compression.Config{}
+	conf.Metadata = metadata.ExcludeFilterConfig{}
+	conf.InjectTracingMap = ""
+	conf.MaxInFlight = 0
+	conf.Config = retries.Config{}
+	conf.RetryAsBatch = false
+	conf.Batching = batchconfig.Config{}
+	conf.SharedClient = false
+
+	keyBytes, err := json.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+	return "kafka_producer:" + string(keyBytes), nil
+}
+
 // WriteWithContext will attempt to write a message to Kafka, wait for
 // acknowledgement, and returns an error if applicable.
 func (k *kafkaWriter) WriteWithContext(ctx context.Context, msg *message.Batch) error {
@@ -360,12 +464,22 @@ func (k *kafkaWriter) WriteWithContext(ctx context.Context, msg *message.Batch)
 
 	err := msg.Iter(func(i int, p *message.Part) error {
 		key := k.key.Bytes(i, msg)
+		value, encoding, err := k.payloadCompressor.Compress(p.Get())
+		if err != nil {
+			return fmt.Errorf("failed to compress message: %w", err)
+		}
 		nextMsg := &sarama.ProducerMessage{
 			Topic:    k.topic.String(i, msg),
-			Value:    sarama.ByteEncoder(p.Get()),
+			Value:    sarama.ByteEncoder(value),
 			Headers:  append(k.buildSystemHeaders(p), userDefinedHeaders...),
 			Metadata: i, // Store the original index for later reference.
 		}
+		if encoding != "" {
+			nextMsg.Headers = append(nextMsg.Headers, sarama.RecordHeader{
+				Key:   []byte(compression.KafkaHeader),
+				Value: []byte(encoding),
+			})
+		}
 		if len(key) > 0 {
 			nextMsg.Key = sarama.ByteEncoder(key)
 		}
@@ -454,7 +568,7 @@ func (k *kafkaWriter) CloseAsync() {
 	go func() {
 		k.connMut.Lock()
 		if k.producer != nil {
-			k.producer.Close()
+			_ = k.release()
 			k.producer = nil
 		}
 		k.connMut.Unlock()
@@ -0,0 +1,113 @@
+package kafka_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/impl/kafka"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestKafkaWriterLinger(t *testing.T) {
+	conf := output.NewKafkaConfig()
+	conf.Addresses = []string{"example.com:1234"}
+	conf.Topic = "foo"
+	conf.Linger = "10ms"
+	conf.LingerBytes = 1024
+	conf.Compression = "zstd"
+	conf.CompressionLevel = 5
+
+	mgr := mock.NewManager()
+	_, err := kafka.NewKafkaWriter(conf, mgr, mgr.Logger())
+	require.NoError(t, err)
+
+	conf.Linger = "not a duration"
+	_, err = kafka.NewKafkaWriter(conf, mgr, mgr.Logger())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse linger string")
+}
+
+func TestKafkaWriterIdempotent(t *testing.T) {
+	conf := output.NewKafkaConfig()
+	conf.Addresses = []string{"example.com:1234"}
+	conf.Topic = "foo"
+	conf.IdempotentWrite = true
+
+	mgr := mock.NewManager()
+	_, err := kafka.NewKafkaWriter(conf, mgr, mgr.Logger())
+	require.NoError(t, err)
+
+	conf.TargetVersion = "0.10.2.0"
+	_, err = kafka.NewKafkaWriter(conf, mgr, mgr.Logger())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "idempotent_write requires a target_version")
+}
+
+func TestKafkaWriterSharedClient(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("foo", 0, broker.BrokerID()).
+			SetLeader("bar", 0, broker.BrokerID()),
+	})
+
+	metadataRequests := func() (n int) {
+		for _, r := range broker.History() {
+			if _, ok := r.Request.(*sarama.MetadataRequest); ok {
+				n++
+			}
+		}
+		return
+	}
+
+	mgr := mock.NewManager()
+	connectWriter := func(fn func(conf *output.KafkaConfig)) output.AsyncSink {
+		t.Helper()
+
+		conf := output.NewKafkaConfig()
+		conf.Addresses = []string{broker.Addr()}
+		conf.Topic = "foo"
+		conf.SharedClient = true
+		fn(&conf)
+
+		w, err := kafka.NewKafkaWriter(conf, mgr, mgr.Logger())
+		require.NoError(t, err)
+		require.NoError(t, w.ConnectWithContext(context.Background()))
+		return w
+	}
+
+	wFoo := connectWriter(func(conf *output.KafkaConfig) {})
+	assert.Equal(t, 1, metadataRequests())
+
+	// A shared producer is reused by outputs that only differ in fields that
+	// are evaluated per message.
+	wBar := connectWriter(func(conf *output.KafkaConfig) {
+		conf.Topic = "bar"
+		conf.StaticHeaders = map[string]string{"baz": "buz"}
+	})
+	assert.Equal(t, 1, metadataRequests())
+
+	wTimeout := connectWriter(func(conf *output.KafkaConfig) {
+		conf.Timeout = "10s"
+	})
+	assert.Equal(t, 2, metadataRequests())
+
+	wUnshared := connectWriter(func(conf *output.KafkaConfig) {
+		conf.SharedClient = false
+	})
+	assert.Equal(t, 3, metadataRequests())
+
+	for _, w := range []output.AsyncSink{wFoo, wBar, wTimeout, wUnshared} {
+		w.CloseAsync()
+		require.NoError(t, w.WaitForClose(time.Second))
+	}
+}
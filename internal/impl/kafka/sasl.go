@@ -10,9 +10,11 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
 	ksasl "github.com/benthosdev/benthos/v4/internal/impl/kafka/sasl"
+	"github.com/benthosdev/benthos/v4/internal/kerberos"
 	"github.com/benthosdev/benthos/v4/public/service"
 
 	"github.com/twmb/franz-go/pkg/sasl"
+	kerbsasl "github.com/twmb/franz-go/pkg/sasl/kerberos"
 	"github.com/twmb/franz-go/pkg/sasl/oauth"
 	"github.com/twmb/franz-go/pkg/sasl/plain"
 	"github.com/twmb/franz-go/pkg/sasl/scram"
@@ -24,6 +26,7 @@ var saslField = service.NewObjectListField("sasl",
 		"OAUTHBEARER":   "OAuth Bearer based authentication.",
 		"SCRAM-SHA-256": "SCRAM based authentication as specified in RFC5802.",
 		"SCRAM-SHA-512": "SCRAM based authentication as specified in RFC5802.",
+		"GSSAPI":        "Kerberos based authentication, configured with the `kerberos` field.",
 	}).
 		Description("The SASL mechanism to use."),
 	service.NewStringField("username").
@@ -38,6 +41,11 @@ var saslField = service.NewObjectListField("sasl",
 	service.NewStringMapField("extensions").
 		Description("Key/value pairs to add to OAUTHBEARER authentication requests.").
 		Optional(),
+	service.NewInternalField(ksasl.TokenSourceFieldSpec()).
+		Version("4.2.0").
+		Optional(),
+	service.NewInternalField(ksasl.KerberosFieldSpec()).
+		Optional(),
 ).
 	Description("Specify one or more methods of SASL authentication. SASL is tried in order; if the broker supports the first mechanism, all connections will use that mechanism. If the first mechanism fails, the client will pick the first supported mechanism. If the broker does not support any client mechanisms, connections will fail.").
 	Advanced().Optional().
@@ -74,6 +82,8 @@ func saslMechanismsFromConfig(c *service.ParsedConfig) ([]sasl.Mechanism, error)
 				mechanisms[i], err = scram256SaslFromConfig(mConf)
 			case "SCRAM-SHA-512":
 				mechanisms[i], err = scram512SaslFromConfig(mConf)
+			case "GSSAPI":
+				mechanisms[i], err = gssapiSaslFromConfig(mConf)
 			default:
 				err = fmt.Errorf("unknown mechanism: %v", mechStr)
 			}
@@ -117,9 +127,28 @@ func oauthSaslFromConfig(c *service.ParsedConfig) (sasl.Mechanism, error) {
 			return nil, err
 		}
 	}
+
+	tsConf, err := tokenSourceConfigFromParsed(c)
+	if err != nil {
+		return nil, err
+	}
+	src, err := newOAuthBearerTokenSource(tsConf)
+	if err != nil {
+		return nil, err
+	}
+	if src == nil {
+		src = func(context.Context) (oauthBearerToken, error) {
+			return oauthBearerToken{token: token}, nil
+		}
+	}
+
 	return oauth.Oauth(func(c context.Context) (oauth.Auth, error) {
+		tok, err := src(c)
+		if err != nil {
+			return oauth.Auth{}, err
+		}
 		return oauth.Auth{
-			Token:      token,
+			Token:      tok.token,
 			Extensions: extensions,
 		}, nil
 	}), nil
@@ -159,6 +188,49 @@ func scram512SaslFromConfig(c *service.ParsedConfig) (sasl.Mechanism, error) {
 	}), nil
 }
 
+func gssapiSaslFromConfig(c *service.ParsedConfig) (sasl.Mechanism, error) {
+	if !c.Contains("kerberos") {
+		return nil, errors.New("a kerberos config must be specified for GSSAPI authentication")
+	}
+	c = c.Namespace("kerberos")
+
+	serviceName, err := c.FieldString("service_name")
+	if err != nil {
+		return nil, err
+	}
+
+	kConf := kerberos.NewConfig()
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{"realm", &kConf.Realm},
+		{"username", &kConf.Username},
+		{"password", &kConf.Password},
+		{"keytab_file", &kConf.KeytabFile},
+		{"ccache_file", &kConf.CCacheFile},
+		{"config_file", &kConf.ConfigFile},
+	} {
+		if *f.dst, err = c.FieldString(f.name); err != nil {
+			return nil, err
+		}
+	}
+	if kConf.DisablePAFXFAST, err = c.FieldBool("disable_pafxfast"); err != nil {
+		return nil, err
+	}
+
+	cl, err := kConf.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return kerbsasl.Kerberos(func(c context.Context) (kerbsasl.Auth, error) {
+		return kerbsasl.Auth{
+			Client:  cl,
+			Service: serviceName,
+		}, nil
+	}), nil
+}
+
 //------------------------------------------------------------------------------
 
 // SASL specific error types.
@@ -173,7 +245,14 @@ func ApplySASLConfig(s ksasl.Config, mgr bundle.NewManagement, conf *sarama.Conf
 		var tp sarama.AccessTokenProvider
 		var err error
 
-		if s.TokenCache != "" {
+		src, err := newOAuthBearerTokenSource(s.TokenSource)
+		if err != nil {
+			return err
+		}
+
+		if src != nil {
+			tp = &tokenSourceAccessTokenProvider{src: src}
+		} else if s.TokenCache != "" {
 			tp, err = newCacheAccessTokenProvider(mgr, s.TokenCache, s.TokenKey)
 			if err != nil {
 				return err
@@ -200,6 +279,25 @@ func ApplySASLConfig(s ksasl.Config, mgr bundle.NewManagement, conf *sarama.Conf
 	case sarama.SASLTypePlaintext:
 		conf.Net.SASL.User = s.User
 		conf.Net.SASL.Password = s.Password
+	case sarama.SASLTypeGSSAPI:
+		k := s.Kerberos
+		if k.CCacheFile != "" {
+			return errors.New("kerberos ccache_file is not supported by this component, use a keytab_file or password instead")
+		}
+		authType := sarama.KRB5_USER_AUTH
+		if k.KeytabFile != "" {
+			authType = sarama.KRB5_KEYTAB_AUTH
+		}
+		conf.Net.SASL.GSSAPI = sarama.GSSAPIConfig{
+			AuthType:           authType,
+			KeyTabPath:         k.KeytabFile,
+			KerberosConfigPath: k.ConfigFile,
+			ServiceName:        k.ServiceName,
+			Username:           k.Username,
+			Password:           k.Password,
+			Realm:              k.Realm,
+			DisablePAFXFAST:    k.DisablePAFXFAST,
+		}
 	case "", "none":
 		return nil
 	default:
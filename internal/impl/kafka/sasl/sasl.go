@@ -2,22 +2,85 @@ package sasl
 
 import (
 	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/impl/aws/session"
+	"github.com/benthosdev/benthos/v4/internal/kerberos"
 )
 
 // Config contains configuration for SASL based authentication.
 type Config struct {
-	Mechanism   string `json:"mechanism" yaml:"mechanism"`
-	User        string `json:"user" yaml:"user"`
-	Password    string `json:"password" yaml:"password"`
-	AccessToken string `json:"access_token" yaml:"access_token"`
-	TokenCache  string `json:"token_cache" yaml:"token_cache"`
-	TokenKey    string `json:"token_key" yaml:"token_key"`
+	Mechanism   string            `json:"mechanism" yaml:"mechanism"`
+	User        string            `json:"user" yaml:"user"`
+	Password    string            `json:"password" yaml:"password"`
+	AccessToken string            `json:"access_token" yaml:"access_token"`
+	TokenCache  string            `json:"token_cache" yaml:"token_cache"`
+	TokenKey    string            `json:"token_key" yaml:"token_key"`
+	TokenSource TokenSourceConfig `json:"token_source" yaml:"token_source"`
+	Kerberos    KerberosConfig    `json:"kerberos" yaml:"kerberos"`
+}
+
+// TokenSourceConfig contains configuration for obtaining OAUTHBEARER access
+// tokens from a source that issues them.
+type TokenSourceConfig struct {
+	Type              string               `json:"type" yaml:"type"`
+	RenewBeforeExpiry string               `json:"renew_before_expiry" yaml:"renew_before_expiry"`
+	File              FileTokenConfig      `json:"file" yaml:"file"`
+	OIDC              OIDCTokenConfig      `json:"oidc" yaml:"oidc"`
+	AWSMSKIAM         AWSMSKIAMTokenConfig `json:"aws_msk_iam" yaml:"aws_msk_iam"`
+}
+
+// FileTokenConfig contains configuration for reading OAUTHBEARER access tokens
+// from a file.
+type FileTokenConfig struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+// OIDCTokenConfig contains configuration for obtaining OAUTHBEARER access
+// tokens with the OAuth2 client credentials flow.
+type OIDCTokenConfig struct {
+	TokenURL       string            `json:"token_url" yaml:"token_url"`
+	ClientID       string            `json:"client_id" yaml:"client_id"`
+	ClientSecret   string            `json:"client_secret" yaml:"client_secret"`
+	Scopes         []string          `json:"scopes" yaml:"scopes"`
+	EndpointParams map[string]string `json:"endpoint_params" yaml:"endpoint_params"`
+}
+
+// AWSMSKIAMTokenConfig contains configuration for generating OAUTHBEARER
+// access tokens for Amazon MSK IAM access control.
+type AWSMSKIAMTokenConfig struct {
+	session.Config `json:",inline" yaml:",inline"`
+}
+
+// NewTokenSourceConfig returns a new TokenSourceConfig with default values.
+func NewTokenSourceConfig() TokenSourceConfig {
+	return TokenSourceConfig{
+		Type:              "static",
+		RenewBeforeExpiry: "1m",
+		OIDC: OIDCTokenConfig{
+			Scopes:         []string{},
+			EndpointParams: map[string]string{},
+		},
+		AWSMSKIAM: AWSMSKIAMTokenConfig{
+			Config: session.NewConfig(),
+		},
+	}
+}
+
+// KerberosConfig contains configuration for GSSAPI (Kerberos) based
+// authentication.
+type KerberosConfig struct {
+	ServiceName     string `json:"service_name" yaml:"service_name"`
+	kerberos.Config `json:",inline" yaml:",inline"`
 }
 
 // NewConfig returns a new SASL config for Kafka with default values.
 func NewConfig() Config {
 	return Config{
-		Mechanism: "none",
+		Mechanism:   "none",
+		TokenSource: NewTokenSourceConfig(),
+		Kerberos: KerberosConfig{
+			ServiceName: "kafka",
+			Config:      kerberos.NewConfig(),
+		},
 	}
 }
 
@@ -30,11 +93,51 @@ func FieldSpec() docs.FieldSpec {
 			"OAUTHBEARER", "OAuth Bearer based authentication.",
 			"SCRAM-SHA-256", "Authentication using the SCRAM-SHA-256 mechanism.",
 			"SCRAM-SHA-512", "Authentication using the SCRAM-SHA-512 mechanism.",
+			"GSSAPI", "Kerberos based authentication, configured with the [`kerberos`](#saslkerberos) field.",
 		),
 		docs.FieldString("user", "A PLAIN username. It is recommended that you use environment variables to populate this field.", "${USER}"),
 		docs.FieldString("password", "A PLAIN password. It is recommended that you use environment variables to populate this field.", "${PASSWORD}"),
 		docs.FieldString("access_token", "A static OAUTHBEARER access token"),
 		docs.FieldString("token_cache", "Instead of using a static `access_token` allows you to query a [`cache`](/docs/components/caches/about) resource to fetch OAUTHBEARER tokens from"),
 		docs.FieldString("token_key", "Required when using a `token_cache`, the key to query the cache with for tokens."),
+		TokenSourceFieldSpec().AtVersion("4.2.0"),
+		kerberosFieldSpec(),
 	).Advanced()
 }
+
+func kerberosFieldSpec() docs.FieldSpec {
+	spec := KerberosFieldSpec()
+	spec.Description += " Authenticating with a `ccache_file` is not supported by this component."
+	return spec
+}
+
+// KerberosFieldSpec returns specs for GSSAPI (Kerberos) fields.
+func KerberosFieldSpec() docs.FieldSpec {
+	return docs.FieldObject("kerberos", "Configuration for GSSAPI (Kerberos) authentication.").WithChildren(append(docs.FieldSpecs{
+		docs.FieldString("service_name", "The Kerberos service name of the brokers.").HasDefault("kafka"),
+	}, kerberos.FieldSpecs()...)...).AtVersion("4.2.0")
+}
+
+// TokenSourceFieldSpec returns specs for OAUTHBEARER token source fields.
+func TokenSourceFieldSpec() docs.FieldSpec {
+	return docs.FieldObject("token_source", "Configures where OAUTHBEARER access tokens are obtained from. Tokens obtained with the `oidc` and `aws_msk_iam` sources are cached and renewed shortly before they expire.").WithChildren(
+		docs.FieldString("type", "The source of OAUTHBEARER access tokens.").HasAnnotatedOptions(
+			"static", "Use the statically configured access token.",
+			"file", "Read the access token from a file each time a token is required, which allows the token to be rotated by an external process.",
+			"oidc", "Obtain access tokens from an OpenID Connect or OAuth2 provider with the client credentials flow.",
+			"aws_msk_iam", "Generate access tokens for [Amazon MSK IAM access control](https://docs.aws.amazon.com/msk/latest/developerguide/iam-access-control.html) with the configured AWS credentials.",
+		).HasDefault("static"),
+		docs.FieldString("renew_before_expiry", "A period of time before an access token expires at which it is renewed.").HasDefault("1m"),
+		docs.FieldObject("file", "Configuration for the `file` token source.").WithChildren(
+			docs.FieldString("path", "The path of a file containing an access token.", "/var/run/secrets/kafka/token").HasDefault(""),
+		),
+		docs.FieldObject("oidc", "Configuration for the `oidc` token source.").WithChildren(
+			docs.FieldString("token_url", "The URL of the token endpoint of the provider.", "https://auth.example.com/oauth2/token").HasDefault(""),
+			docs.FieldString("client_id", "The ID of the client.").HasDefault(""),
+			docs.FieldString("client_secret", "The secret of the client.").HasDefault(""),
+			docs.FieldString("scopes", "A list of scopes to request.").Array().HasDefault([]string{}),
+			docs.FieldString("endpoint_params", "A map of additional parameters to send to the token endpoint.", map[string]string{"audience": "kafka"}).Map().HasDefault(map[string]string{}),
+		),
+		docs.FieldObject("aws_msk_iam", "Configuration for the `aws_msk_iam` token source.").WithChildren(session.FieldSpecs()...),
+	)
+}
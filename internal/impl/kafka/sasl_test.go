@@ -1,9 +1,19 @@
 package kafka_test
 
 import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
@@ -131,6 +141,134 @@ cache_resources:
 	}
 }
 
+func TestApplyOAuthBearerFileTokenSource(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("foo\n"), 0o600))
+
+	conf := &sarama.Config{}
+
+	saslConf := sasl.NewConfig()
+	saslConf.Mechanism = string(sarama.SASLTypeOAuth)
+	saslConf.TokenSource.Type = "file"
+	saslConf.TokenSource.File.Path = tokenPath
+
+	require.NoError(t, kafka.ApplySASLConfig(saslConf, mock.NewManager(), conf))
+
+	token, err := conf.Net.SASL.TokenProvider.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "foo", token.Token)
+
+	// Tokens rotated externally are picked up.
+	require.NoError(t, os.WriteFile(tokenPath, []byte("bar"), 0o600))
+
+	token, err = conf.Net.SASL.TokenProvider.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "bar", token.Token)
+}
+
+func TestApplyOAuthBearerOIDCTokenSource(t *testing.T) {
+	var tokenReqs uint32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "kafka", r.PostForm.Get("audience"))
+
+		n := atomic.AddUint32(&tokenReqs, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token%v","token_type":"bearer","expires_in":120}`, n)
+	}))
+	defer ts.Close()
+
+	saslConf := sasl.NewConfig()
+	saslConf.Mechanism = string(sarama.SASLTypeOAuth)
+	saslConf.TokenSource.Type = "oidc"
+	saslConf.TokenSource.OIDC.TokenURL = ts.URL
+	saslConf.TokenSource.OIDC.ClientID = "foo"
+	saslConf.TokenSource.OIDC.ClientSecret = "bar"
+	saslConf.TokenSource.OIDC.EndpointParams = map[string]string{"audience": "kafka"}
+
+	// Tokens are cached while they remain valid for longer than the renewal
+	// period.
+	conf := &sarama.Config{}
+	require.NoError(t, kafka.ApplySASLConfig(saslConf, mock.NewManager(), conf))
+	for i := 0; i < 2; i++ {
+		token, err := conf.Net.SASL.TokenProvider.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "token1", token.Token)
+	}
+
+	// Tokens are renewed when they expire within the renewal period.
+	saslConf.TokenSource.RenewBeforeExpiry = "5m"
+
+	conf = &sarama.Config{}
+	require.NoError(t, kafka.ApplySASLConfig(saslConf, mock.NewManager(), conf))
+	for i := 0; i < 2; i++ {
+		token, err := conf.Net.SASL.TokenProvider.Token()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("token%v", i+2), token.Token)
+	}
+}
+
+func TestApplyOAuthBearerMSKIAMTokenSource(t *testing.T) {
+	conf := &sarama.Config{}
+
+	saslConf := sasl.NewConfig()
+	saslConf.Mechanism = string(sarama.SASLTypeOAuth)
+	saslConf.TokenSource.Type = "aws_msk_iam"
+	saslConf.TokenSource.AWSMSKIAM.Region = "eu-west-1"
+	saslConf.TokenSource.AWSMSKIAM.Credentials.ID = "foo"
+	saslConf.TokenSource.AWSMSKIAM.Credentials.Secret = "bar"
+
+	require.NoError(t, kafka.ApplySASLConfig(saslConf, mock.NewManager(), conf))
+
+	token, err := conf.Net.SASL.TokenProvider.Token()
+	require.NoError(t, err)
+
+	urlBytes, err := base64.RawURLEncoding.DecodeString(token.Token)
+	require.NoError(t, err)
+
+	u, err := url.Parse(string(urlBytes))
+	require.NoError(t, err)
+	assert.Equal(t, "kafka.eu-west-1.amazonaws.com", u.Host)
+
+	query := u.Query()
+	assert.Equal(t, "kafka-cluster:Connect", query.Get("Action"))
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.True(t, strings.HasPrefix(query.Get("X-Amz-Credential"), "foo/"))
+	assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+
+	saslConf.TokenSource.AWSMSKIAM.Region = ""
+	require.Error(t, kafka.ApplySASLConfig(saslConf, mock.NewManager(), &sarama.Config{}))
+}
+
+func TestApplyGSSAPI(t *testing.T) {
+	conf := &sarama.Config{}
+
+	saslConf := sasl.NewConfig()
+	saslConf.Mechanism = string(sarama.SASLTypeGSSAPI)
+	saslConf.Kerberos.Username = "foo"
+	saslConf.Kerberos.Realm = "EXAMPLE.COM"
+	saslConf.Kerberos.KeytabFile = "/foo.keytab"
+
+	err := kafka.ApplySASLConfig(saslConf, mock.NewManager(), conf)
+	require.NoError(t, err)
+
+	require.True(t, conf.Net.SASL.Enable)
+	require.Equal(t, sarama.SASLMechanism(sarama.SASLTypeGSSAPI), conf.Net.SASL.Mechanism)
+	require.Equal(t, sarama.GSSAPIConfig{
+		AuthType:           sarama.KRB5_KEYTAB_AUTH,
+		KeyTabPath:         "/foo.keytab",
+		KerberosConfigPath: "/etc/krb5.conf",
+		ServiceName:        "kafka",
+		Username:           "foo",
+		Realm:              "EXAMPLE.COM",
+	}, conf.Net.SASL.GSSAPI)
+
+	saslConf.Kerberos.CCacheFile = "/tmp/krb5cc"
+	require.Error(t, kafka.ApplySASLConfig(saslConf, mock.NewManager(), &sarama.Config{}))
+}
+
 func TestApplyUnknownMechanism(t *testing.T) {
 	conf := &sarama.Config{}
 
@@ -0,0 +1,232 @@
+package kafka
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"golang.org/x/oauth2/clientcredentials"
+
+	baws "github.com/benthosdev/benthos/v4/internal/impl/aws"
+	ksasl "github.com/benthosdev/benthos/v4/internal/impl/kafka/sasl"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// mskIAMTokenLifetime is the period for which a presigned Amazon MSK IAM
+// token remains valid.
+const mskIAMTokenLifetime = 15 * time.Minute
+
+// oauthBearerToken is an OAUTHBEARER access token along with the time at which
+// it expires, a zero expiry indicates a token that does not expire.
+type oauthBearerToken struct {
+	token  string
+	expiry time.Time
+}
+
+// oauthBearerTokenSource obtains OAUTHBEARER access tokens.
+type oauthBearerTokenSource func(ctx context.Context) (oauthBearerToken, error)
+
+// newOAuthBearerTokenSource returns a source of access tokens from a token
+// source config, or nil when tokens are static.
+func newOAuthBearerTokenSource(conf ksasl.TokenSourceConfig) (oauthBearerTokenSource, error) {
+	var renewBefore time.Duration
+	if conf.RenewBeforeExpiry != "" {
+		var err error
+		if renewBefore, err = time.ParseDuration(conf.RenewBeforeExpiry); err != nil {
+			return nil, fmt.Errorf("failed to parse token_source renew_before_expiry: %w", err)
+		}
+	}
+
+	switch conf.Type {
+	case "", "static":
+		return nil, nil
+	case "file":
+		return fileTokenSource(conf.File)
+	case "oidc":
+		src, err := oidcTokenSource(conf.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		return renewingTokenSource(src, renewBefore), nil
+	case "aws_msk_iam":
+		src, err := mskIAMTokenSource(conf.AWSMSKIAM)
+		if err != nil {
+			return nil, err
+		}
+		return renewingTokenSource(src, renewBefore), nil
+	}
+	return nil, fmt.Errorf("token source type not recognised: %v", conf.Type)
+}
+
+func fileTokenSource(conf ksasl.FileTokenConfig) (oauthBearerTokenSource, error) {
+	if conf.Path == "" {
+		return nil, errors.New("a path must be specified for the file token source")
+	}
+	return func(ctx context.Context) (oauthBearerToken, error) {
+		b, err := os.ReadFile(conf.Path)
+		if err != nil {
+			return oauthBearerToken{}, fmt.Errorf("failed to read token file: %w", err)
+		}
+		return oauthBearerToken{token: strings.TrimSpace(string(b))}, nil
+	}, nil
+}
+
+func oidcTokenSource(conf ksasl.OIDCTokenConfig) (oauthBearerTokenSource, error) {
+	if conf.TokenURL == "" {
+		return nil, errors.New("a token_url must be specified for the oidc token source")
+	}
+	ccConf := &clientcredentials.Config{
+		ClientID:     conf.ClientID,
+		ClientSecret: conf.ClientSecret,
+		TokenURL:     conf.TokenURL,
+		Scopes:       conf.Scopes,
+	}
+	if len(conf.EndpointParams) > 0 {
+		ccConf.EndpointParams = url.Values{}
+		for k, v := range conf.EndpointParams {
+			ccConf.EndpointParams.Set(k, v)
+		}
+	}
+	return func(ctx context.Context) (oauthBearerToken, error) {
+		tok, err := ccConf.Token(ctx)
+		if err != nil {
+			return oauthBearerToken{}, err
+		}
+		return oauthBearerToken{token: tok.AccessToken, expiry: tok.Expiry}, nil
+	}, nil
+}
+
+// mskIAMTokenSource generates tokens for Amazon MSK IAM access control, which
+// are base64 encoded URLs of a request to the kafka-cluster:Connect action
+// presigned with AWS Signature Version 4.
+func mskIAMTokenSource(conf ksasl.AWSMSKIAMTokenConfig) (oauthBearerTokenSource, error) {
+	sess, err := baws.GetSessionFromConf(conf.Config)
+	if err != nil {
+		return nil, err
+	}
+	region := ""
+	if sess.Config.Region != nil {
+		region = *sess.Config.Region
+	}
+	if region == "" {
+		return nil, errors.New("a region must be specified for the aws_msk_iam token source")
+	}
+
+	signer := v4.NewSigner(sess.Config.Credentials)
+	endpoint := fmt.Sprintf("https://kafka.%v.amazonaws.com/?Action=%v", region, url.QueryEscape("kafka-cluster:Connect"))
+
+	return func(ctx context.Context) (oauthBearerToken, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return oauthBearerToken{}, err
+		}
+
+		signTime := time.Now()
+		if _, err := signer.Presign(req, nil, "kafka-cluster", region, mskIAMTokenLifetime, signTime); err != nil {
+			return oauthBearerToken{}, fmt.Errorf("failed to presign token: %w", err)
+		}
+
+		// The user agent is added after signing as it is not part of the
+		// signature.
+		query := req.URL.Query()
+		query.Set("User-Agent", "benthos")
+		req.URL.RawQuery = query.Encode()
+
+		return oauthBearerToken{
+			token:  base64.RawURLEncoding.EncodeToString([]byte(req.URL.String())),
+			expiry: signTime.Add(mskIAMTokenLifetime),
+		}, nil
+	}, nil
+}
+
+// renewingTokenSource caches tokens and obtains a new token from the
+// underlying source once the cached token is within a period of expiring.
+func renewingTokenSource(src oauthBearerTokenSource, renewBefore time.Duration) oauthBearerTokenSource {
+	var mut sync.Mutex
+	var cached oauthBearerToken
+	return func(ctx context.Context) (oauthBearerToken, error) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		if cached.token != "" && (cached.expiry.IsZero() || time.Until(cached.expiry) > renewBefore) {
+			return cached, nil
+		}
+
+		tok, err := src(ctx)
+		if err != nil {
+			return oauthBearerToken{}, err
+		}
+		cached = tok
+		return tok, nil
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// tokenSourceAccessTokenProvider provides SASL OAUTHBEARER access tokens to
+// sarama from a token source.
+type tokenSourceAccessTokenProvider struct {
+	src oauthBearerTokenSource
+}
+
+func (t *tokenSourceAccessTokenProvider) Token() (*sarama.AccessToken, error) {
+	tok, err := t.src(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &sarama.AccessToken{Token: tok.token}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// tokenSourceConfigFromParsed reads a token source config from the
+// `token_source` field of a parsed SASL config.
+func tokenSourceConfigFromParsed(c *service.ParsedConfig) (conf ksasl.TokenSourceConfig, err error) {
+	conf = ksasl.NewTokenSourceConfig()
+	if !c.Contains("token_source") {
+		return
+	}
+	c = c.Namespace("token_source")
+
+	for _, f := range []struct {
+		path []string
+		dst  *string
+	}{
+		{[]string{"type"}, &conf.Type},
+		{[]string{"renew_before_expiry"}, &conf.RenewBeforeExpiry},
+		{[]string{"file", "path"}, &conf.File.Path},
+		{[]string{"oidc", "token_url"}, &conf.OIDC.TokenURL},
+		{[]string{"oidc", "client_id"}, &conf.OIDC.ClientID},
+		{[]string{"oidc", "client_secret"}, &conf.OIDC.ClientSecret},
+		{[]string{"aws_msk_iam", "region"}, &conf.AWSMSKIAM.Region},
+		{[]string{"aws_msk_iam", "endpoint"}, &conf.AWSMSKIAM.Endpoint},
+		{[]string{"aws_msk_iam", "credentials", "profile"}, &conf.AWSMSKIAM.Credentials.Profile},
+		{[]string{"aws_msk_iam", "credentials", "id"}, &conf.AWSMSKIAM.Credentials.ID},
+		{[]string{"aws_msk_iam", "credentials", "secret"}, &conf.AWSMSKIAM.Credentials.Secret},
+		{[]string{"aws_msk_iam", "credentials", "token"}, &conf.AWSMSKIAM.Credentials.Token},
+		{[]string{"aws_msk_iam", "credentials", "role"}, &conf.AWSMSKIAM.Credentials.Role},
+		{[]string{"aws_msk_iam", "credentials", "role_external_id"}, &conf.AWSMSKIAM.Credentials.ExternalID},
+		{[]string{"aws_msk_iam", "credentials", "web_identity_token_file"}, &conf.AWSMSKIAM.Credentials.WebIdentityTokenFile},
+	} {
+		if *f.dst, err = c.FieldString(f.path...); err != nil {
+			return
+		}
+	}
+	if conf.AWSMSKIAM.Credentials.UseEC2Creds, err = c.FieldBool("aws_msk_iam", "credentials", "from_ec2_role"); err != nil {
+		return
+	}
+	if conf.OIDC.Scopes, err = c.FieldStringList("oidc", "scopes"); err != nil {
+		return
+	}
+	conf.OIDC.EndpointParams, err = c.FieldStringMap("oidc", "endpoint_params")
+	return
+}
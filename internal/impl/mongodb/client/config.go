@@ -26,6 +26,10 @@ const (
 	OperationUpdateOne Operation = "update-one"
 	// OperationFindOne Find one operation
 	OperationFindOne Operation = "find-one"
+	// OperationFindMany Find many operation
+	OperationFindMany Operation = "find-many"
+	// OperationAggregate Aggregate operation
+	OperationAggregate Operation = "aggregate"
 	// OperationInvalid Invalid operation
 	OperationInvalid Operation = "invalid"
 )
@@ -45,6 +49,10 @@ func NewOperation(op string) Operation {
 		return OperationUpdateOne
 	case "find-one":
 		return OperationFindOne
+	case "find-many":
+		return OperationFindMany
+	case "aggregate":
+		return OperationAggregate
 	default:
 		return OperationInvalid
 	}
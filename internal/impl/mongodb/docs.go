@@ -8,7 +8,11 @@ import (
 
 func processorOperationDocs(defaultOperation client.Operation) docs.FieldSpec {
 	fs := outputOperationDocs(defaultOperation)
-	return fs.HasOptions(append(fs.Options, string(client.OperationFindOne))...)
+	return fs.HasOptions(append(fs.Options,
+		string(client.OperationFindOne),
+		string(client.OperationFindMany),
+		string(client.OperationAggregate),
+	)...)
 }
 
 func outputOperationDocs(defaultOperation client.Operation) docs.FieldSpec {
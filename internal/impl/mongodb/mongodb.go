@@ -13,7 +13,8 @@ func isDocumentAllowed(op client.Operation) bool {
 
 func isFilterAllowed(op client.Operation) bool {
 	switch op {
-	case client.OperationDeleteOne, client.OperationDeleteMany, client.OperationReplaceOne, client.OperationUpdateOne, client.OperationFindOne:
+	case client.OperationDeleteOne, client.OperationDeleteMany, client.OperationReplaceOne, client.OperationUpdateOne,
+		client.OperationFindOne, client.OperationFindMany, client.OperationAggregate:
 		return true
 	default:
 		return false
@@ -22,7 +23,8 @@ func isFilterAllowed(op client.Operation) bool {
 
 func isHintAllowed(op client.Operation) bool {
 	switch op {
-	case client.OperationDeleteOne, client.OperationDeleteMany, client.OperationReplaceOne, client.OperationUpdateOne, client.OperationFindOne:
+	case client.OperationDeleteOne, client.OperationDeleteMany, client.OperationReplaceOne, client.OperationUpdateOne,
+		client.OperationFindOne, client.OperationFindMany, client.OperationAggregate:
 		return true
 	default:
 		return false
@@ -110,7 +110,8 @@ func NewWriter(
 ) (*Writer, error) {
 	// TODO: Remove this after V4 lands and #972 is fixed
 	operation := client.NewOperation(conf.Operation)
-	if operation == client.OperationInvalid {
+	switch operation {
+	case client.OperationInvalid, client.OperationFindOne, client.OperationFindMany, client.OperationAggregate:
 		return nil, fmt.Errorf("mongodb operation '%s' unknown: must be insert-one, delete-one, delete-many, replace-one or update-one", conf.Operation)
 	}
 
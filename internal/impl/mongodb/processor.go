@@ -1,6 +1,7 @@
 package mongodb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -42,6 +43,54 @@ func init() {
 		Version:    "3.43.0",
 		Categories: []string{"Integration"},
 		Summary:    `Performs operations against MongoDB for each message, allowing you to store or retrieve data within message payloads.`,
+		Description: `
+### Read Operations
+
+The operations ` + "`find-one`, `find-many` and `aggregate`" + ` replace the contents of each message with the results of a query, which makes it possible to enrich messages with data stored in MongoDB by placing this processor within a ` + "[`branch` processor](/docs/components/processors/branch)" + `:
+
+- ` + "`find-one`" + ` results in the first document that matches the ` + "`filter_map`" + `, and the message is flagged as failed when no document matches.
+- ` + "`find-many`" + ` results in an array of all documents that match the ` + "`filter_map`" + `, which is empty when no document matches.
+- ` + "`aggregate`" + ` results in an array of the documents returned by an [aggregation pipeline](https://www.mongodb.com/docs/manual/core/aggregation-pipeline/), where the ` + "`filter_map`" + ` must result in an array of pipeline stages.`,
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Enrichment",
+				Summary: "Here we add the orders of a customer to each message by finding them within the collection `orders`, where the customer ID is taken from the message:",
+				Config: `
+pipeline:
+  processors:
+    - branch:
+        processors:
+          - mongodb:
+              url: mongodb://localhost:27017
+              database: shop
+              collection: orders
+              operation: find-many
+              filter_map: root.customer_id = this.customer.id
+        result_map: root.customer.orders = this
+`,
+			},
+			{
+				Title:   "Aggregation",
+				Summary: "Aggregation pipelines can be used in order to summarise the documents related to a message, here we add the total spend of a customer:",
+				Config: `
+pipeline:
+  processors:
+    - branch:
+        processors:
+          - mongodb:
+              url: mongodb://localhost:27017
+              database: shop
+              collection: orders
+              operation: aggregate
+              filter_map: |
+                root = [
+                  { "$match": { "customer_id": this.customer.id } },
+                  { "$group": { "_id": "$customer_id", "total": { "$sum": "$price" } } }
+                ]
+        result_map: root.customer.total_spend = this.index(0).total.or(0)
+`,
+			},
+		},
 		Config: docs.FieldComponent().WithChildren(
 			client.ConfigDocs().Add(
 				processorOperationDocs(client.OperationInsertOne),
@@ -61,7 +110,8 @@ func init() {
 					"filter_map",
 					"A bloblang map representing the filter for the mongo db command. The filter map is required for all operations except "+
 						"insert-one. It is used to find the document(s) for the operation. For example in a delete-one case, the filter map should "+
-						"have the fields required to locate the document to delete.",
+						"have the fields required to locate the document to delete. For the aggregate operation the filter map must result in an "+
+						"array of pipeline stages.",
 					mapExamples()...,
 				),
 				docs.FieldBloblang(
@@ -121,7 +171,7 @@ func NewProcessor(
 	// TODO: V4 Remove this after V4 lands and #972 is fixed
 	operation := client.NewOperation(conf.MongoDB.Operation)
 	if operation == client.OperationInvalid {
-		return nil, fmt.Errorf("mongodb operation '%s' unknown: must be insert-one, delete-one, delete-many, replace-one, update-one or find-one", conf.MongoDB.Operation)
+		return nil, fmt.Errorf("mongodb operation '%s' unknown: must be insert-one, delete-one, delete-many, replace-one, update-one, find-one, find-many or aggregate", conf.MongoDB.Operation)
 	}
 
 	m := &Processor{
@@ -275,7 +325,6 @@ func (m *Processor) ProcessBatch(ctx context.Context, spans []*tracing.Span, bat
 			}
 		}
 
-		findOptions := &options.FindOneOptions{}
 		if m.hintMap != nil {
 			hintVal, err := m.hintMap.MapPart(i, newBatch)
 			if err != nil {
@@ -284,7 +333,6 @@ func (m *Processor) ProcessBatch(ctx context.Context, spans []*tracing.Span, bat
 			if hintJSON, err = hintVal.JSON(); err != nil {
 				return err
 			}
-			findOptions.Hint = hintJSON
 		}
 
 		var writeModel mongo.WriteModel
@@ -320,6 +368,10 @@ func (m *Processor) ProcessBatch(ctx context.Context, spans []*tracing.Span, bat
 				Hint:   hintJSON,
 			}
 		case client.OperationFindOne:
+			findOptions := options.FindOne()
+			if hintJSON != nil {
+				findOptions.SetHint(hintJSON)
+			}
 			var decoded interface{}
 			err := collection.FindOne(context.Background(), filterJSON, findOptions).Decode(&decoded)
 			if err != nil {
@@ -336,6 +388,45 @@ func (m *Processor) ProcessBatch(ctx context.Context, spans []*tracing.Span, bat
 
 			p.Set(data)
 
+			return nil
+		case client.OperationFindMany:
+			findOptions := options.Find()
+			if hintJSON != nil {
+				findOptions.SetHint(hintJSON)
+			}
+			cursor, err := collection.Find(context.Background(), filterJSON, findOptions)
+			if err != nil {
+				m.log.Errorf("Error finding mongo db documents, filter = %v: %s", filterJSON, err)
+				return err
+			}
+			data, err := m.marshalCursor(context.Background(), cursor)
+			if err != nil {
+				return err
+			}
+
+			p.Set(data)
+
+			return nil
+		case client.OperationAggregate:
+			if _, isArray := filterJSON.([]interface{}); !isArray {
+				return fmt.Errorf("filter_map must result in an array of pipeline stages for the aggregate operation, got %T", filterJSON)
+			}
+			aggregateOptions := options.Aggregate()
+			if hintJSON != nil {
+				aggregateOptions.SetHint(hintJSON)
+			}
+			cursor, err := collection.Aggregate(context.Background(), filterJSON, aggregateOptions)
+			if err != nil {
+				m.log.Errorf("Error running mongo db aggregation, pipeline = %v: %s", filterJSON, err)
+				return err
+			}
+			data, err := m.marshalCursor(context.Background(), cursor)
+			if err != nil {
+				return err
+			}
+
+			p.Set(data)
+
 			return nil
 		}
 
@@ -361,6 +452,30 @@ func (m *Processor) ProcessBatch(ctx context.Context, spans []*tracing.Span, bat
 	return []*message.Batch{newBatch}, nil
 }
 
+// marshalCursor consumes all documents of a cursor and marshals them as a JSON
+// array.
+func (m *Processor) marshalCursor(ctx context.Context, cursor *mongo.Cursor) ([]byte, error) {
+	defer cursor.Close(ctx)
+
+	var buf bytes.Buffer
+	_ = buf.WriteByte('[')
+	for i := 0; cursor.Next(ctx); i++ {
+		if i > 0 {
+			_ = buf.WriteByte(',')
+		}
+		data, err := bson.MarshalExtJSON(cursor.Current, m.conf.JSONMarshalMode == client.JSONMarshalModeCanonical, false)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = buf.Write(data)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	_ = buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
 // Close shuts down the processor and stops processing requests.
 func (m *Processor) Close(ctx context.Context) error {
 	return m.client.Disconnect(ctx)
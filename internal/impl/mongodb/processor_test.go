@@ -95,6 +95,9 @@ func TestProcessorIntegration(t *testing.T) {
 	t.Run("find one", func(t *testing.T) {
 		testMongoDBProcessorFindOne(port, t)
 	})
+	t.Run("find many and aggregate", func(t *testing.T) {
+		testMongoDBProcessorFindManyAggregate(port, t)
+	})
 }
 
 func testMongoDBProcessorInsert(port string, t *testing.T) {
@@ -504,3 +507,86 @@ func testMongoDBProcessorFindOne(port string, t *testing.T) {
 		assert.Equalf(t, jsondiff.SupersetMatch.String(), diff.String(), "%s: %s", tt.name, explanation)
 	}
 }
+
+func testMongoDBProcessorFindManyAggregate(port string, t *testing.T) {
+	c := client.Config{
+		URL:        "mongodb://localhost:" + port,
+		Database:   "TestDB",
+		Collection: "TestFindManyCollection",
+		Username:   "mongoadmin",
+		Password:   "secret",
+	}
+
+	mongoClient, err := c.Client()
+	require.NoError(t, err)
+	require.NoError(t, mongoClient.Connect(context.Background()))
+	collection := mongoClient.Database("TestDB").Collection("TestFindManyCollection")
+	for _, doc := range []bson.M{
+		{"customer": "foo", "price": 10},
+		{"customer": "foo", "price": 20},
+		{"customer": "bar", "price": 5},
+	} {
+		_, err = collection.InsertOne(context.Background(), doc)
+		require.NoError(t, err)
+	}
+
+	mgr, err := manager.New(manager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name      string
+		operation string
+		filterMap string
+		message   string
+		expected  string
+	}{
+		{
+			name:      "find many",
+			operation: "find-many",
+			filterMap: "root.customer = this.customer",
+			message:   `{"customer":"foo"}`,
+			expected:  `[{"customer":"foo","price":10},{"customer":"foo","price":20}]`,
+		},
+		{
+			name:      "find many no documents",
+			operation: "find-many",
+			filterMap: "root.customer = this.customer",
+			message:   `{"customer":"baz"}`,
+			expected:  `[]`,
+		},
+		{
+			name:      "aggregate",
+			operation: "aggregate",
+			filterMap: `root = [
+  { "$match": { "customer": this.customer } },
+  { "$group": { "_id": "$customer", "total": { "$sum": "$price" } } }
+]`,
+			message:  `{"customer":"foo"}`,
+			expected: `[{"_id":"foo","total":30}]`,
+		},
+	} {
+		conf := processor.NewConfig()
+		conf.Type = "mongodb"
+		conf.MongoDB = processor.NewMongoDBConfig()
+		conf.MongoDB.MongoDB = c
+		conf.MongoDB.WriteConcern = client.WriteConcern{
+			W:        "1",
+			J:        false,
+			WTimeout: "100s",
+		}
+		conf.MongoDB.Operation = tt.operation
+		conf.MongoDB.FilterMap = tt.filterMap
+		conf.MongoDB.JSONMarshalMode = client.JSONMarshalModeRelaxed
+
+		m, err := mongodb.NewProcessor(conf, mgr, log.Noop(), metrics.Noop())
+		require.NoError(t, err, tt.name)
+		resMsgs, response := m.ProcessBatch(context.Background(), make([]*tracing.Span, 1), message.QuickBatch([][]byte{[]byte(tt.message)}))
+		require.Nil(t, response)
+		require.Len(t, resMsgs, 1)
+		require.NoError(t, resMsgs[0].Get(0).ErrorGet(), tt.name)
+
+		jdopts := jsondiff.DefaultJSONOptions()
+		diff, explanation := jsondiff.Compare(resMsgs[0].Get(0).Get(), []byte(tt.expected), &jdopts)
+		assert.Containsf(t, []string{jsondiff.FullMatch.String(), jsondiff.SupersetMatch.String()}, diff.String(), "%s: %s", tt.name, explanation)
+	}
+}
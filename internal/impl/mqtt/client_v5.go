@@ -0,0 +1,108 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	mqttconf "github.com/benthosdev/benthos/v4/internal/impl/mqtt/shared"
+)
+
+const (
+	protocolVersion311 = "3.1.1"
+	protocolVersion5   = "5"
+)
+
+// checkProtocolVersion returns an error if a protocol version is not one that
+// is supported.
+func checkProtocolVersion(version string) error {
+	switch version {
+	case protocolVersion311, protocolVersion5:
+		return nil
+	}
+	return fmt.Errorf("unsupported protocol_version: %v", version)
+}
+
+// parseExpirySeconds parses an optional duration string into the number of
+// seconds used by the expiry properties of MQTT 5, returning nil when the
+// string is empty.
+func parseExpirySeconds(str string) (*uint32, error) {
+	if str == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		return nil, err
+	}
+	if d < time.Second {
+		return nil, errors.New("must be at least one second")
+	}
+	secs := uint32(d / time.Second)
+	return &secs, nil
+}
+
+// dialV5 opens a network connection to the first broker of a list of URLs that
+// can be reached, as the MQTT 5 client must be given an established connection.
+func dialV5(ctx context.Context, urls []string, tlsConf *tls.Config) (net.Conn, error) {
+	err := errors.New("no urls specified")
+	for _, u := range urls {
+		var conn net.Conn
+		if conn, err = dialV5URL(ctx, u, tlsConf); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+func dialV5URL(ctx context.Context, rawURL string, tlsConf *tls.Config) (net.Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	var dialer net.Dialer
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		return dialer.DialContext(ctx, "tcp", u.Host)
+	case "ssl", "tls", "tcps", "mqtts":
+		if tlsConf == nil {
+			tlsConf = &tls.Config{}
+		}
+		tlsDialer := tls.Dialer{NetDialer: &dialer, Config: tlsConf}
+		return tlsDialer.DialContext(ctx, "tcp", u.Host)
+	}
+	return nil, fmt.Errorf("url scheme %v is not supported with protocol_version 5", u.Scheme)
+}
+
+// connectPacketV5 returns the packet used to establish an MQTT 5 session.
+func connectPacketV5(clientID, user, password string, keepAlive int64, cleanStart bool, sessionExpiry *uint32, will mqttconf.Will) *paho.Connect {
+	cp := &paho.Connect{
+		ClientID:     clientID,
+		KeepAlive:    uint16(keepAlive),
+		CleanStart:   cleanStart,
+		Username:     user,
+		UsernameFlag: user != "",
+		Password:     []byte(password),
+		PasswordFlag: password != "",
+	}
+	if sessionExpiry != nil {
+		cp.Properties = &paho.ConnectProperties{
+			SessionExpiryInterval: sessionExpiry,
+		}
+	}
+	if will.Enabled {
+		cp.WillMessage = &paho.WillMessage{
+			Retain:  will.Retained,
+			QoS:     will.QoS,
+			Topic:   will.Topic,
+			Payload: []byte(will.Payload),
+		}
+	}
+	return cp
+}
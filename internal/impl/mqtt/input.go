@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -24,7 +25,7 @@ import (
 
 func init() {
 	err := bundle.AllInputs.Add(processors.WrapConstructor(func(conf input.Config, nm bundle.NewManagement) (input.Streamed, error) {
-		m, err := newMQTTReader(conf.MQTT, nm.Logger())
+		m, err := newMQTTInput(conf.MQTT, nm.Logger())
 		if err != nil {
 			return nil, err
 		}
@@ -51,8 +52,20 @@ This input adds the following metadata fields to each message:
 - mqtt_message_id
 ` + "```" + `
 
+When connected with ` + "`protocol_version`" + ` 5 the field ` + "`mqtt_duplicate`" + ` is not added, and the user properties of each message are added as metadata fields with the names of the properties.
+
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### MQTT 5
+
+When ` + "`protocol_version`" + ` is set to ` + "`5`" + ` the connection is established with MQTT version 5. Setting ` + "`clean_session`" + ` to ` + "`false`" + ` along with a ` + "`session_expiry_interval`" + ` allows the broker to retain the subscriptions of the session, and the messages published to them, for that long after the connection is lost, which are then delivered once the session is resumed. Only URLs with the schemes ` + "`tcp`" + `, ` + "`mqtt`" + `, ` + "`ssl`" + `, ` + "`tls`" + `, ` + "`tcps`" + ` and ` + "`mqtts`" + ` are supported with this version.
+
+### Shared Subscriptions
+
+Messages of a topic can be distributed across multiple consumers by subscribing to a shared subscription with a topic of the form ` + "`$share/<group>/<topic filter>`" + `, where each message is delivered to only one of the consumers subscribed with the same group. The metadata field ` + "`mqtt_topic`" + ` contains the topic that a message was published to rather than the shared subscription.
+
+Shared subscriptions are part of MQTT version 5, and are also supported for clients of version 3.1.1 by brokers such as Mosquitto 1.6 or newer, EMQX and HiveMQ.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("urls", "A list of URLs to connect to. If an item of the list contains commas it will be expanded into multiple URLs.").Array(),
 			docs.FieldString("topics", "A list of topics to consume from.").Array(),
@@ -67,6 +80,8 @@ You can access these metadata fields using
 			docs.FieldString("user", "A username to assume for the connection.").Advanced(),
 			docs.FieldString("password", "A password to provide for the connection.").Advanced(),
 			docs.FieldInt("keepalive", "Max seconds of inactivity before a keepalive message is sent.").Advanced(),
+			mqttconf.ProtocolVersionFieldSpec(),
+			docs.FieldString("session_expiry_interval", "The period of time for which the broker retains the session after the connection is lost, which is only supported with `protocol_version` 5. When empty the session ends once the connection is closed.", "1h", "24h").Advanced().AtVersion("4.2.0"),
 			tls.FieldSpec().AtVersion("3.45.0"),
 		).ChildDefaultAndTypesFromStruct(input.NewMQTTConfig()),
 		Categories: []string{
@@ -78,6 +93,20 @@ You can access these metadata fields using
 	}
 }
 
+// newMQTTInput returns a reader for the protocol version of a config.
+func newMQTTInput(conf input.MQTTConfig, log log.Modular) (input.Async, error) {
+	if err := checkProtocolVersion(conf.ProtocolVersion); err != nil {
+		return nil, err
+	}
+	if conf.ProtocolVersion == protocolVersion5 {
+		return newMQTTV5Reader(conf, log)
+	}
+	if conf.SessionExpiryInterval != "" {
+		return nil, errors.New("session_expiry_interval requires protocol_version 5")
+	}
+	return newMQTTReader(conf, log)
+}
+
 type mqttReader struct {
 	client  mqtt.Client
 	msgChan chan mqtt.Message
@@ -121,6 +150,12 @@ func newMQTTReader(conf input.MQTTConfig, log log.Modular) (*mqttReader, error)
 		return nil, err
 	}
 
+	for _, topic := range conf.Topics {
+		if err := validateSharedSubscription(topic); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, u := range conf.URLs {
 		for _, splitURL := range strings.Split(u, ",") {
 			if len(splitURL) > 0 {
@@ -132,6 +167,22 @@ func newMQTTReader(conf input.MQTTConfig, log log.Modular) (*mqttReader, error)
 	return m, nil
 }
 
+// validateSharedSubscription checks that a topic of a shared subscription
+// contains both a group and a topic filter.
+func validateSharedSubscription(topic string) error {
+	if !strings.HasPrefix(topic, "$share/") {
+		return nil
+	}
+	parts := strings.SplitN(topic, "/", 3)
+	if len(parts) < 3 || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("shared subscription %v must be of the form $share/<group>/<topic filter>", topic)
+	}
+	if strings.ContainsAny(parts[1], "+#") {
+		return fmt.Errorf("shared subscription %v must not contain wildcards in its group", topic)
+	}
+	return nil
+}
+
 func (m *mqttReader) ConnectWithContext(ctx context.Context) error {
 	m.cMut.Lock()
 	defer m.cMut.Unlock()
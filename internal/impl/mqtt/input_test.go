@@ -0,0 +1,37 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+func TestMQTTSharedSubscriptionValidation(t *testing.T) {
+	for _, test := range []struct {
+		topic string
+		err   string
+	}{
+		{topic: "foo/bar"},
+		{topic: "$SYS/broker/uptime"},
+		{topic: "$share/group/foo/+"},
+		{topic: "$share/group", err: "must be of the form"},
+		{topic: "$share//foo", err: "must be of the form"},
+		{topic: "$share/group/", err: "must be of the form"},
+		{topic: "$share/gr+oup/foo", err: "must not contain wildcards"},
+	} {
+		conf := input.NewMQTTConfig()
+		conf.Topics = []string{test.topic}
+
+		_, err := newMQTTReader(conf, log.Noop())
+		if test.err == "" {
+			require.NoError(t, err, test.topic)
+		} else {
+			require.Error(t, err, test.topic)
+			assert.Contains(t, err.Error(), test.err, test.topic)
+		}
+	}
+}
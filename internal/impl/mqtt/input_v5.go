@@ -0,0 +1,202 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type mqttV5Reader struct {
+	client  *paho.Client
+	msgChan chan *paho.Publish
+	cMut    sync.Mutex
+
+	connectTimeout time.Duration
+	sessionExpiry  *uint32
+	conf           input.MQTTConfig
+
+	interruptChan chan struct{}
+
+	urls []string
+
+	log log.Modular
+}
+
+func newMQTTV5Reader(conf input.MQTTConfig, log log.Modular) (*mqttV5Reader, error) {
+	// Fields that are common to both protocol versions are validated and
+	// normalised in the same way.
+	r, err := newMQTTReader(conf, log)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &mqttV5Reader{
+		connectTimeout: r.connectTimeout,
+		conf:           r.conf,
+		interruptChan:  make(chan struct{}),
+		urls:           r.urls,
+		log:            log,
+	}
+	if m.sessionExpiry, err = parseExpirySeconds(conf.SessionExpiryInterval); err != nil {
+		return nil, fmt.Errorf("failed to parse session_expiry_interval: %w", err)
+	}
+	return m, nil
+}
+
+func (m *mqttV5Reader) ConnectWithContext(ctx context.Context) error {
+	m.cMut.Lock()
+	defer m.cMut.Unlock()
+
+	if m.client != nil {
+		return nil
+	}
+
+	var msgMut sync.Mutex
+	msgChan := make(chan *paho.Publish)
+
+	closeMsgChan := func() bool {
+		msgMut.Lock()
+		chanOpen := msgChan != nil
+		if chanOpen {
+			close(msgChan)
+			msgChan = nil
+		}
+		msgMut.Unlock()
+		return chanOpen
+	}
+
+	connCtx, done := context.WithTimeout(ctx, m.connectTimeout)
+	defer done()
+
+	var tlsConf *tls.Config
+	if m.conf.TLS.Enabled {
+		var err error
+		if tlsConf, err = m.conf.TLS.Get(); err != nil {
+			return err
+		}
+	}
+	conn, err := dialV5(connCtx, m.urls, tlsConf)
+	if err != nil {
+		return err
+	}
+
+	client := paho.NewClient(paho.ClientConfig{
+		ClientID: m.conf.ClientID,
+		Conn:     conn,
+		Router: paho.NewSingleHandlerRouter(func(p *paho.Publish) {
+			msgMut.Lock()
+			if msgChan != nil {
+				select {
+				case msgChan <- p:
+				case <-m.interruptChan:
+				}
+			}
+			msgMut.Unlock()
+		}),
+		OnClientError: func(err error) {
+			if closeMsgChan() {
+				m.log.Errorf("Connection lost due to: %v\n", err)
+			}
+		},
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			if closeMsgChan() {
+				m.log.Errorf("Disconnected by broker with reason code: %v\n", d.ReasonCode)
+			}
+		},
+		// Messages are acknowledged once they have been delivered, and the
+		// client sends acknowledgements in the order they were received.
+		EnableManualAcknowledgment: true,
+	})
+
+	cp := connectPacketV5(m.conf.ClientID, m.conf.User, m.conf.Password, m.conf.KeepAlive, m.conf.CleanSession, m.sessionExpiry, m.conf.Will)
+	if _, err := client.Connect(connCtx, cp); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	subs := make(map[string]paho.SubscribeOptions)
+	for _, topic := range m.conf.Topics {
+		subs[topic] = paho.SubscribeOptions{QoS: m.conf.QoS}
+	}
+	if _, err := client.Subscribe(connCtx, &paho.Subscribe{Subscriptions: subs}); err != nil {
+		_ = client.Disconnect(&paho.Disconnect{})
+		return fmt.Errorf("failed to subscribe to topics '%v': %w", m.conf.Topics, err)
+	}
+
+	m.log.Infof("Receiving MQTT messages from topics: %v\n", m.conf.Topics)
+
+	m.client = client
+	m.msgChan = msgChan
+	return nil
+}
+
+func (m *mqttV5Reader) ReadWithContext(ctx context.Context) (*message.Batch, input.AsyncAckFn, error) {
+	m.cMut.Lock()
+	client, msgChan := m.client, m.msgChan
+	m.cMut.Unlock()
+
+	if msgChan == nil {
+		return nil, nil, component.ErrNotConnected
+	}
+
+	select {
+	case msg, open := <-msgChan:
+		if !open {
+			m.cMut.Lock()
+			m.msgChan = nil
+			m.client = nil
+			m.cMut.Unlock()
+			return nil, nil, component.ErrNotConnected
+		}
+
+		message := message.QuickBatch([][]byte{msg.Payload})
+
+		p := message.Get(0)
+		if msg.Properties != nil {
+			for _, prop := range msg.Properties.User {
+				p.MetaSet(prop.Key, prop.Value)
+			}
+		}
+		p.MetaSet("mqtt_qos", strconv.Itoa(int(msg.QoS)))
+		p.MetaSet("mqtt_retained", strconv.FormatBool(msg.Retain))
+		p.MetaSet("mqtt_topic", msg.Topic)
+		p.MetaSet("mqtt_message_id", strconv.Itoa(int(msg.PacketID)))
+
+		return message, func(ctx context.Context, res error) error {
+			if res == nil {
+				return client.Ack(msg)
+			}
+			return nil
+		}, nil
+	case <-ctx.Done():
+	case <-m.interruptChan:
+		return nil, nil, component.ErrTypeClosed
+	}
+	return nil, nil, component.ErrTimeout
+}
+
+func (m *mqttV5Reader) CloseAsync() {
+	m.cMut.Lock()
+	if m.client != nil {
+		// Interrupt pending messages first, as disconnecting waits for the
+		// client to stop routing them.
+		close(m.interruptChan)
+		_ = m.client.Disconnect(&paho.Disconnect{})
+		m.client = nil
+	}
+	m.cMut.Unlock()
+}
+
+func (m *mqttV5Reader) WaitForClose(timeout time.Duration) error {
+	return nil
+}
@@ -0,0 +1,211 @@
+package mqtt
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+// fakeV5Broker is a minimal MQTT 5 broker that accepts a single connection,
+// grants every subscription and records the packets it receives.
+type fakeV5Broker struct {
+	ln net.Listener
+
+	connects chan *packets.Connect
+	received chan *packets.ControlPacket
+
+	writeMut sync.Mutex
+	conn     net.Conn
+}
+
+func newFakeV5Broker(t *testing.T) *fakeV5Broker {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &fakeV5Broker{
+		ln:       ln,
+		connects: make(chan *packets.Connect, 1),
+		received: make(chan *packets.ControlPacket, 10),
+	}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		b.serve(conn)
+	}()
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+	return b
+}
+
+func (b *fakeV5Broker) URL() string {
+	return "tcp://" + b.ln.Addr().String()
+}
+
+func (b *fakeV5Broker) write(p io.WriterTo) error {
+	b.writeMut.Lock()
+	defer b.writeMut.Unlock()
+	_, err := p.WriteTo(b.conn)
+	return err
+}
+
+func (b *fakeV5Broker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	cp, err := packets.ReadPacket(conn)
+	if err != nil {
+		return
+	}
+	b.writeMut.Lock()
+	b.conn = conn
+	b.writeMut.Unlock()
+
+	b.connects <- cp.Content.(*packets.Connect)
+	if b.write(packets.NewControlPacket(packets.CONNACK)) != nil {
+		return
+	}
+
+	for {
+		if cp, err = packets.ReadPacket(conn); err != nil {
+			return
+		}
+
+		var resp *packets.ControlPacket
+		switch p := cp.Content.(type) {
+		case *packets.Subscribe:
+			resp = packets.NewControlPacket(packets.SUBACK)
+			suback := resp.Content.(*packets.Suback)
+			suback.PacketID = p.PacketID
+			for _, opts := range p.Subscriptions {
+				suback.Reasons = append(suback.Reasons, opts.QoS)
+			}
+		case *packets.Publish:
+			resp = packets.NewControlPacket(packets.PUBACK)
+			resp.Content.(*packets.Puback).PacketID = p.PacketID
+		case *packets.Pingreq:
+			resp = packets.NewControlPacket(packets.PINGRESP)
+		case *packets.Disconnect:
+			return
+		}
+		if resp != nil {
+			if b.write(resp) != nil {
+				return
+			}
+		}
+		b.received <- cp
+	}
+}
+
+func (b *fakeV5Broker) nextConnect(t *testing.T) *packets.Connect {
+	t.Helper()
+	select {
+	case c := <-b.connects:
+		return c
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for connect")
+	}
+	return nil
+}
+
+func (b *fakeV5Broker) next(t *testing.T) *packets.ControlPacket {
+	t.Helper()
+	select {
+	case cp := <-b.received:
+		return cp
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for packet")
+	}
+	return nil
+}
+
+func TestMQTTV5Reader(t *testing.T) {
+	broker := newFakeV5Broker(t)
+
+	conf := input.NewMQTTConfig()
+	conf.URLs = []string{broker.URL()}
+	conf.Topics = []string{"$share/group/foo"}
+	conf.ClientID = "foo"
+	conf.CleanSession = false
+	conf.ProtocolVersion = "5"
+	conf.SessionExpiryInterval = "1h"
+
+	r, err := newMQTTInput(conf, log.Noop())
+	require.NoError(t, err)
+	require.IsType(t, &mqttV5Reader{}, r)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	require.NoError(t, r.ConnectWithContext(ctx))
+	t.Cleanup(r.CloseAsync)
+
+	connect := broker.nextConnect(t)
+	assert.Equal(t, "foo", connect.ClientID)
+	assert.False(t, connect.CleanStart)
+	require.NotNil(t, connect.Properties.SessionExpiryInterval)
+	assert.Equal(t, uint32(3600), *connect.Properties.SessionExpiryInterval)
+
+	sub, ok := broker.next(t).Content.(*packets.Subscribe)
+	require.True(t, ok)
+	assert.Contains(t, sub.Subscriptions, "$share/group/foo")
+
+	require.NoError(t, broker.write(&packets.Publish{
+		Topic:    "foo",
+		QoS:      1,
+		PacketID: 5,
+		Payload:  []byte("hello world"),
+		Properties: &packets.Properties{
+			User: []packets.User{{Key: "foo", Value: "bar"}},
+		},
+	}))
+
+	msg, ackFn, err := r.ReadWithContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, msg.Len())
+
+	p := msg.Get(0)
+	assert.Equal(t, "hello world", string(p.Get()))
+	assert.Equal(t, "bar", p.MetaGet("foo"))
+	assert.Equal(t, "foo", p.MetaGet("mqtt_topic"))
+	assert.Equal(t, "1", p.MetaGet("mqtt_qos"))
+	assert.Equal(t, "5", p.MetaGet("mqtt_message_id"))
+
+	require.NoError(t, ackFn(ctx, nil))
+	puback, ok := broker.next(t).Content.(*packets.Puback)
+	require.True(t, ok)
+	assert.Equal(t, uint16(5), puback.PacketID)
+}
+
+func TestMQTTV5ReaderConfigErrors(t *testing.T) {
+	conf := input.NewMQTTConfig()
+	conf.ProtocolVersion = "4"
+	_, err := newMQTTInput(conf, log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported protocol_version")
+
+	conf = input.NewMQTTConfig()
+	conf.SessionExpiryInterval = "1h"
+	_, err = newMQTTInput(conf, log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires protocol_version 5")
+
+	conf.ProtocolVersion = "5"
+	conf.SessionExpiryInterval = "10ms"
+	_, err = newMQTTInput(conf, log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session_expiry_interval")
+}
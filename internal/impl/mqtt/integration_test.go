@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -94,4 +95,15 @@ input:
 			integration.StreamTestOptVarOne("nanoid"),
 		)
 	})
+	t.Run("with persistent session", func(t *testing.T) {
+		t.Parallel()
+		templatePersistent := strings.ReplaceAll(template, "    qos: 1\n", "    qos: 2\n    clean_session: false\n")
+		suite.Run(
+			t, templatePersistent,
+			integration.StreamTestOptSleepAfterInput(100*time.Millisecond),
+			integration.StreamTestOptSleepAfterOutput(100*time.Millisecond),
+			integration.StreamTestOptPort(resource.GetPort("1883/tcp")),
+			integration.StreamTestOptMaxInFlight(10),
+		)
+	})
 }
@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -25,7 +26,7 @@ import (
 
 func init() {
 	err := bundle.AllOutputs.Add(processors.WrapConstructor(func(conf output.Config, nm bundle.NewManagement) (output.Streamed, error) {
-		w, err := newMQTTWriter(conf.MQTT, nm, nm.Logger())
+		w, err := newMQTTOutput(conf.MQTT, nm, nm.Logger())
 		if err != nil {
 			return nil, err
 		}
@@ -41,7 +42,26 @@ Pushes messages to an MQTT broker.`,
 		Description: output.Description(true, false, `
 The `+"`topic`"+` field can be dynamically set using function interpolations
 described [here](/docs/configuration/interpolation#bloblang-queries). When sending batched
-messages these interpolations are performed per message part.`),
+messages these interpolations are performed per message part.
+
+### Persistent Sessions
+
+By default a clean session is started each time a connection is established,
+and messages that are in flight when a connection is lost are sent again after
+reconnecting, which can result in duplicates even with a `+"`qos`"+` of 2.
+
+When `+"`clean_session`"+` is set to `+"`false`"+` the session is instead
+resumed after a connection is lost, and messages that were in flight are
+redelivered as part of the resumed session rather than sent again, which
+allows the broker to discard duplicates. In this mode the connection is
+reestablished automatically and writes wait for in flight messages to be
+acknowledged through the resumed session, combined with a `+"`qos`"+` of 2 this
+provides exactly once delivery to the broker for as long as Benthos is
+running. A `+"`client_id`"+` must be set in order to resume sessions.
+
+### MQTT 5
+
+When `+"`protocol_version`"+` is set to `+"`5`"+` the connection is established with MQTT version 5, which allows a `+"`message_expiry`"+` to be set on published messages. Persistent sessions are not supported with this version, and so `+"`clean_session`"+` must be `+"`true`"+`. Only URLs with the schemes `+"`tcp`"+`, `+"`mqtt`"+`, `+"`ssl`"+`, `+"`tls`"+`, `+"`tcps`"+` and `+"`mqtts`"+` are supported with this version.`),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("urls", "A list of URLs to connect to. If an item of the list contains commas it will be expanded into multiple URLs.", []string{"tcp://localhost:1883"}).Array(),
 			docs.FieldString("topic", "The topic to publish messages to."),
@@ -50,6 +70,7 @@ messages these interpolations are performed per message part.`),
 				"nanoid", "append a nanoid of length 21 characters",
 			).LinterFunc(nil),
 			docs.FieldInt("qos", "The QoS value to set for each message.").HasOptions("0", "1", "2").LinterFunc(nil),
+			docs.FieldBool("clean_session", "Whether to start a clean session for each connection. When `false` the session is resumed after a connection is lost and in flight messages are redelivered as part of it.").Advanced().AtVersion("4.2.0"),
 			docs.FieldString("connect_timeout", "The maximum amount of time to wait in order to establish a connection before the attempt is abandoned.", "1s", "500ms").HasDefault("30s").AtVersion("3.58.0"),
 			docs.FieldString("write_timeout", "The maximum amount of time to wait to write data before the attempt is abandoned.", "1s", "500ms").HasDefault("3s").AtVersion("3.58.0"),
 			docs.FieldBool("retained", "Set message as retained on the topic."),
@@ -58,6 +79,8 @@ messages these interpolations are performed per message part.`),
 			docs.FieldString("user", "A username to connect with.").Advanced(),
 			docs.FieldString("password", "A password to connect with.").Advanced(),
 			docs.FieldInt("keepalive", "Max seconds of inactivity before a keepalive message is sent.").Advanced(),
+			mqttconf.ProtocolVersionFieldSpec(),
+			docs.FieldString("message_expiry", "The period of time after which the broker discards published messages that have not yet been delivered to subscribers, which is only supported with `protocol_version` 5. When empty messages do not expire.", "60s", "1h").Advanced().AtVersion("4.2.0"),
 			tls.FieldSpec().AtVersion("3.45.0"),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 		).ChildDefaultAndTypesFromStruct(output.NewMQTTConfig()),
@@ -70,6 +93,20 @@ messages these interpolations are performed per message part.`),
 	}
 }
 
+// newMQTTOutput returns a writer for the protocol version of a config.
+func newMQTTOutput(conf output.MQTTConfig, mgr bundle.NewManagement, log log.Modular) (output.AsyncSink, error) {
+	if err := checkProtocolVersion(conf.ProtocolVersion); err != nil {
+		return nil, err
+	}
+	if conf.ProtocolVersion == protocolVersion5 {
+		return newMQTTV5Writer(conf, mgr, log)
+	}
+	if conf.MessageExpiry != "" {
+		return nil, errors.New("message_expiry requires protocol_version 5")
+	}
+	return newMQTTWriter(conf, mgr, log)
+}
+
 type mqttWriter struct {
 	log log.Modular
 
@@ -82,6 +119,7 @@ type mqttWriter struct {
 	retained *field.Expression
 
 	client  mqtt.Client
+	store   mqtt.Store
 	connMut sync.RWMutex
 }
 
@@ -129,6 +167,15 @@ func newMQTTWriter(
 		return nil, err
 	}
 
+	if !m.conf.CleanSession {
+		if m.conf.ClientID == "" {
+			return nil, errors.New("a client_id must be set when clean_session is false")
+		}
+		// The store outlives individual clients so that in flight messages
+		// are retained across connections.
+		m.store = mqtt.NewMemoryStore()
+	}
+
 	for _, u := range conf.URLs {
 		for _, splitURL := range strings.Split(u, ",") {
 			if len(splitURL) > 0 {
@@ -149,16 +196,28 @@ func (m *mqttWriter) ConnectWithContext(ctx context.Context) error {
 	}
 
 	conf := mqtt.NewClientOptions().
-		SetAutoReconnect(false).
-		SetConnectionLostHandler(func(client mqtt.Client, reason error) {
-			client.Disconnect(0)
-			m.log.Errorf("Connection lost due to: %v\n", reason)
-		}).
 		SetConnectTimeout(m.connectTimeout).
 		SetWriteTimeout(m.writeTimeout).
 		SetKeepAlive(time.Duration(m.conf.KeepAlive) * time.Second).
 		SetClientID(m.conf.ClientID)
 
+	if m.conf.CleanSession {
+		conf = conf.SetAutoReconnect(false).
+			SetConnectionLostHandler(func(client mqtt.Client, reason error) {
+				client.Disconnect(0)
+				m.log.Errorf("Connection lost due to: %v\n", reason)
+			})
+	} else {
+		// Reconnecting with the same client resumes the session, and pending
+		// writes are completed once their messages are acknowledged within it.
+		conf = conf.SetCleanSession(false).
+			SetStore(m.store).
+			SetAutoReconnect(true).
+			SetConnectionLostHandler(func(client mqtt.Client, reason error) {
+				m.log.Errorf("Connection lost due to: %v, attempting to resume session\n", reason)
+			})
+	}
+
 	for _, u := range m.urls {
 		conf = conf.AddBroker(u)
 	}
@@ -205,15 +264,7 @@ func (m *mqttWriter) WriteWithContext(ctx context.Context, msg *message.Batch) e
 	}
 
 	return output.IterateBatchedSend(msg, func(i int, p *message.Part) error {
-		retained := m.conf.Retained
-		if m.retained != nil {
-			var parseErr error
-			retained, parseErr = strconv.ParseBool(m.retained.String(i, msg))
-			if parseErr != nil {
-				m.log.Errorf("Error parsing boolean value from retained flag: %v \n", parseErr)
-			}
-		}
-		mtok := client.Publish(m.topic.String(i, msg), m.conf.QoS, retained, p.Get())
+		mtok := client.Publish(m.topic.String(i, msg), m.conf.QoS, isRetained(i, msg, m.conf.Retained, m.retained, m.log), p.Get())
 		mtok.Wait()
 		sendErr := mtok.Error()
 		if sendErr == mqtt.ErrNotConnected {
@@ -226,6 +277,19 @@ func (m *mqttWriter) WriteWithContext(ctx context.Context, msg *message.Batch) e
 	})
 }
 
+// isRetained returns whether a message of a batch should be published as
+// retained, which is the value of retainedInterpolated when set.
+func isRetained(i int, msg *message.Batch, retained bool, retainedInterpolated *field.Expression, log log.Modular) bool {
+	if retainedInterpolated != nil {
+		var parseErr error
+		retained, parseErr = strconv.ParseBool(retainedInterpolated.String(i, msg))
+		if parseErr != nil {
+			log.Errorf("Error parsing boolean value from retained flag: %v \n", parseErr)
+		}
+	}
+	return retained
+}
+
 func (m *mqttWriter) CloseAsync() {
 	go func() {
 		m.connMut.Lock()
@@ -0,0 +1,164 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type mqttV5Writer struct {
+	log log.Modular
+
+	connectTimeout time.Duration
+	writeTimeout   time.Duration
+	messageExpiry  *uint32
+
+	urls     []string
+	conf     output.MQTTConfig
+	topic    *field.Expression
+	retained *field.Expression
+
+	client  *paho.Client
+	connMut sync.RWMutex
+}
+
+func newMQTTV5Writer(conf output.MQTTConfig, mgr bundle.NewManagement, log log.Modular) (*mqttV5Writer, error) {
+	if !conf.CleanSession {
+		return nil, errors.New("clean_session must be true with protocol_version 5")
+	}
+
+	// Fields that are common to both protocol versions are validated and
+	// normalised in the same way.
+	w, err := newMQTTWriter(conf, mgr, log)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &mqttV5Writer{
+		log:            log,
+		connectTimeout: w.connectTimeout,
+		writeTimeout:   w.writeTimeout,
+		urls:           w.urls,
+		conf:           w.conf,
+		topic:          w.topic,
+		retained:       w.retained,
+	}
+	if m.messageExpiry, err = parseExpirySeconds(conf.MessageExpiry); err != nil {
+		return nil, fmt.Errorf("failed to parse message_expiry: %w", err)
+	}
+	return m, nil
+}
+
+func (m *mqttV5Writer) ConnectWithContext(ctx context.Context) error {
+	m.connMut.Lock()
+	defer m.connMut.Unlock()
+
+	if m.client != nil {
+		return nil
+	}
+
+	connCtx, done := context.WithTimeout(ctx, m.connectTimeout)
+	defer done()
+
+	var tlsConf *tls.Config
+	if m.conf.TLS.Enabled {
+		var err error
+		if tlsConf, err = m.conf.TLS.Get(); err != nil {
+			return err
+		}
+	}
+	conn, err := dialV5(connCtx, m.urls, tlsConf)
+	if err != nil {
+		return err
+	}
+
+	var client *paho.Client
+	client = paho.NewClient(paho.ClientConfig{
+		ClientID:      m.conf.ClientID,
+		Conn:          conn,
+		PacketTimeout: m.writeTimeout,
+		OnClientError: func(err error) {
+			m.dropClient(client)
+			m.log.Errorf("Connection lost due to: %v\n", err)
+		},
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			m.dropClient(client)
+			m.log.Errorf("Disconnected by broker with reason code: %v\n", d.ReasonCode)
+		},
+	})
+
+	cp := connectPacketV5(m.conf.ClientID, m.conf.User, m.conf.Password, m.conf.KeepAlive, true, nil, m.conf.Will)
+	if _, err := client.Connect(connCtx, cp); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	m.client = client
+	return nil
+}
+
+// dropClient removes a client that has lost its connection so that the next
+// write reconnects, unless it has already been replaced.
+func (m *mqttV5Writer) dropClient(client *paho.Client) {
+	m.connMut.Lock()
+	if m.client == client {
+		m.client = nil
+	}
+	m.connMut.Unlock()
+}
+
+func (m *mqttV5Writer) WriteWithContext(ctx context.Context, msg *message.Batch) error {
+	m.connMut.RLock()
+	client := m.client
+	m.connMut.RUnlock()
+
+	if client == nil {
+		return component.ErrNotConnected
+	}
+
+	return output.IterateBatchedSend(msg, func(i int, p *message.Part) error {
+		pub := &paho.Publish{
+			QoS:     m.conf.QoS,
+			Retain:  isRetained(i, msg, m.conf.Retained, m.retained, m.log),
+			Topic:   m.topic.String(i, msg),
+			Payload: p.Get(),
+		}
+		if m.messageExpiry != nil {
+			pub.Properties = &paho.PublishProperties{
+				MessageExpiry: m.messageExpiry,
+			}
+		}
+		res, err := client.Publish(ctx, pub)
+		if err == nil && res != nil && res.ReasonCode >= 0x80 {
+			err = fmt.Errorf("publish rejected by broker with reason code: %v", res.ReasonCode)
+		}
+		return err
+	})
+}
+
+func (m *mqttV5Writer) CloseAsync() {
+	go func() {
+		m.connMut.Lock()
+		if m.client != nil {
+			_ = m.client.Disconnect(&paho.Disconnect{})
+			m.client = nil
+		}
+		m.connMut.Unlock()
+	}()
+}
+
+func (m *mqttV5Writer) WaitForClose(timeout time.Duration) error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestMQTTV5Writer(t *testing.T) {
+	broker := newFakeV5Broker(t)
+
+	conf := output.NewMQTTConfig()
+	conf.URLs = []string{broker.URL()}
+	conf.Topic = `${! meta("topic") }`
+	conf.ClientID = "foo"
+	conf.ProtocolVersion = "5"
+	conf.MessageExpiry = "1m"
+
+	w, err := newMQTTOutput(conf, mock.NewManager(), log.Noop())
+	require.NoError(t, err)
+	require.IsType(t, &mqttV5Writer{}, w)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	require.NoError(t, w.ConnectWithContext(ctx))
+	t.Cleanup(w.CloseAsync)
+
+	connect := broker.nextConnect(t)
+	assert.Equal(t, "foo", connect.ClientID)
+	assert.True(t, connect.CleanStart)
+
+	msg := message.QuickBatch([][]byte{[]byte("hello world")})
+	msg.Get(0).MetaSet("topic", "bar")
+	require.NoError(t, w.WriteWithContext(ctx, msg))
+
+	pub, ok := broker.next(t).Content.(*packets.Publish)
+	require.True(t, ok)
+	assert.Equal(t, "bar", pub.Topic)
+	assert.Equal(t, "hello world", string(pub.Payload))
+	assert.Equal(t, byte(1), pub.QoS)
+	require.NotNil(t, pub.Properties.MessageExpiry)
+	assert.Equal(t, uint32(60), *pub.Properties.MessageExpiry)
+}
+
+func TestMQTTV5WriterConfigErrors(t *testing.T) {
+	conf := output.NewMQTTConfig()
+	conf.MessageExpiry = "1m"
+	_, err := newMQTTOutput(conf, mock.NewManager(), log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires protocol_version 5")
+
+	conf.ProtocolVersion = "5"
+	conf.ClientID = "foo"
+	conf.CleanSession = false
+	_, err = newMQTTOutput(conf, mock.NewManager(), log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "clean_session must be true")
+}
@@ -47,3 +47,14 @@ func WillFieldSpec() docs.FieldSpec {
 		docs.FieldString("payload", "Set payload for last will message."),
 	).Advanced()
 }
+
+// ProtocolVersionFieldSpec defines the version of the MQTT protocol that a
+// component connects with.
+func ProtocolVersionFieldSpec() docs.FieldSpec {
+	return docs.FieldString(
+		"protocol_version", "The version of the MQTT protocol to connect with. Fields that are only supported by a specific version say so within their description.",
+	).HasAnnotatedOptions(
+		"3.1.1", "MQTT version 3.1.1.",
+		"5", "MQTT version 5.",
+	).Advanced().AtVersion("4.2.0")
+}
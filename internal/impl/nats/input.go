@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -15,11 +16,13 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/impl/nats/auth"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	btls "github.com/benthosdev/benthos/v4/internal/tls"
+	"github.com/benthosdev/benthos/v4/internal/transaction"
 )
 
 func init() {
@@ -40,6 +43,14 @@ This input adds the following metadata fields to each message:
 
 You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).
 
+### JetStream
+
+By default this input creates a core NATS subscription, where messages are delivered at most once and rejected messages are not redelivered. When ` + "[`jetstream.enabled`](#jetstreamenabled)" + ` is set to ` + "`true`" + ` the input instead consumes from a durable JetStream pull consumer, fetching batches of messages on demand. Messages are only acknowledged once they have been successfully delivered to outputs, and messages that are rejected (or not acknowledged within the ` + "`ack_wait`" + ` period) are redelivered by the server, giving at-least-once delivery guarantees.
+
+### Request Reply
+
+Core NATS messages that are sent as requests, and therefore carry a reply subject, can be replied to with the result of the pipeline by using a ` + "[`sync_response` output](/docs/components/outputs/sync_response)" + `, where each response message is published to the reply subject with its metadata added as headers (when supported by the connection). Responses are only sent once messages have been successfully delivered to all outputs. For more information read [Synchronous Responses](/docs/guides/sync_responses).
+
 ` + auth.Description(),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString(
@@ -51,6 +62,17 @@ You can access these metadata fields using [function interpolation](/docs/config
 			docs.FieldString("queue", "The queue to consume from."),
 			docs.FieldString("subject", "A subject to consume from."),
 			docs.FieldInt("prefetch_count", "The maximum number of messages to pull at a time.").Advanced(),
+			compression.AutoDecompressFieldSpec("`Content-Encoding` header", "`nats` output"),
+			docs.FieldObject("jetstream", "Configure the input to consume from a JetStream pull consumer rather than a core NATS subscription.").WithChildren(
+				docs.FieldBool("enabled", "Whether to consume from a JetStream pull consumer."),
+				docs.FieldString("stream", "An optional stream to bind the consumer to. By default the stream is found by the `subject`."),
+				docs.FieldString("durable", "The durable name of the consumer, which is required when JetStream is enabled. Instances of this input that share a durable name consume messages from the same consumer, and the delivery state of the consumer is preserved across restarts."),
+				docs.FieldString("ack_wait", "The maximum period of time that the server waits for a message to be acknowledged before redelivering it.", "30s", "5m"),
+				docs.FieldInt("max_ack_pending", "The maximum number of messages that can be delivered and awaiting acknowledgement before the server stops delivering messages to the consumer."),
+				docs.FieldInt("fetch_size", "The maximum number of messages to fetch from the consumer in a single request."),
+			).AtVersion("4.2.0").Advanced(),
+			input.NackPolicyFieldSpec(),
+			input.AckTimeoutFieldSpec(),
 			btls.FieldSpec(),
 			auth.FieldSpec(),
 		).ChildDefaultAndTypesFromStruct(input.NewNATSConfig()),
@@ -68,7 +90,11 @@ func newNATSInput(conf input.Config, mgr bundle.NewManagement, log log.Modular,
 	if err != nil {
 		return nil, err
 	}
-	return input.NewAsyncReader("nats", true, input.NewAsyncPreserver(n), log, stats)
+	ackTimeout, err := input.AckTimeoutOpt(conf.NATS.AckTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return input.NewAsyncReader("nats", true, input.NewAsyncPreserver(n), log, stats, ackTimeout)
 }
 
 type natsReader struct {
@@ -81,8 +107,11 @@ type natsReader struct {
 	natsConn      *nats.Conn
 	natsSub       *nats.Subscription
 	natsChan      chan *nats.Msg
+	fetched       []*nats.Msg
 	interruptChan chan struct{}
 	tlsConf       *tls.Config
+	ackWait       time.Duration
+	nackPolicy    input.NackPolicy
 }
 
 func newNATSReader(conf input.NATSConfig, log log.Modular) (*natsReader, error) {
@@ -101,6 +130,25 @@ func newNATSReader(conf input.NATSConfig, log log.Modular) (*natsReader, error)
 			return nil, err
 		}
 	}
+	if n.nackPolicy, err = conf.NackPolicy.Policy(); err != nil {
+		return nil, err
+	}
+	if conf.JetStream.Enabled {
+		if conf.JetStream.Durable == "" {
+			return nil, errors.New("a durable name must be specified when jetstream is enabled")
+		}
+		if conf.QueueID != "" {
+			return nil, errors.New("a queue cannot be specified when jetstream is enabled, instances that share a durable name consume from the same consumer instead")
+		}
+		if conf.JetStream.FetchSize < 1 {
+			return nil, errors.New("jetstream fetch size must be greater than zero")
+		}
+		if conf.JetStream.AckWait != "" {
+			if n.ackWait, err = time.ParseDuration(conf.JetStream.AckWait); err != nil {
+				return nil, fmt.Errorf("failed to parse jetstream ack wait duration: %w", err)
+			}
+		}
+	}
 
 	return &n, nil
 }
@@ -127,15 +175,21 @@ func (n *natsReader) ConnectWithContext(ctx context.Context) error {
 	if natsConn, err = nats.Connect(n.urls, opts...); err != nil {
 		return err
 	}
-	natsChan := make(chan *nats.Msg, n.conf.PrefetchCount)
 
-	if len(n.conf.QueueID) > 0 {
-		natsSub, err = natsConn.ChanQueueSubscribe(n.conf.Subject, n.conf.QueueID, natsChan)
+	var natsChan chan *nats.Msg
+	if n.conf.JetStream.Enabled {
+		natsSub, err = n.pullSubscribe(natsConn)
 	} else {
-		natsSub, err = natsConn.ChanSubscribe(n.conf.Subject, natsChan)
+		natsChan = make(chan *nats.Msg, n.conf.PrefetchCount)
+		if len(n.conf.QueueID) > 0 {
+			natsSub, err = natsConn.ChanQueueSubscribe(n.conf.Subject, n.conf.QueueID, natsChan)
+		} else {
+			natsSub, err = natsConn.ChanSubscribe(n.conf.Subject, natsChan)
+		}
 	}
 
 	if err != nil {
+		natsConn.Close()
 		return err
 	}
 
@@ -147,6 +201,27 @@ func (n *natsReader) ConnectWithContext(ctx context.Context) error {
 	return nil
 }
 
+func (n *natsReader) pullSubscribe(natsConn *nats.Conn) (*nats.Subscription, error) {
+	jCtx, err := natsConn.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []nats.SubOpt{
+		nats.ManualAck(),
+	}
+	if n.ackWait > 0 {
+		options = append(options, nats.AckWait(n.ackWait))
+	}
+	if n.conf.JetStream.MaxAckPending != 0 {
+		options = append(options, nats.MaxAckPending(n.conf.JetStream.MaxAckPending))
+	}
+	if n.conf.JetStream.Stream != "" {
+		options = append(options, nats.BindStream(n.conf.JetStream.Stream))
+	}
+	return jCtx.PullSubscribe(n.conf.Subject, n.conf.JetStream.Durable, options...)
+}
+
 func (n *natsReader) disconnect() {
 	n.cMut.Lock()
 	defer n.cMut.Unlock()
@@ -160,6 +235,58 @@ func (n *natsReader) disconnect() {
 		n.natsConn = nil
 	}
 	n.natsChan = nil
+	n.fetched = nil
+}
+
+// jetStreamFetchTimeout is the maximum period of time that a single fetch from
+// a pull consumer waits for messages, which bounds how long a read takes to
+// notice that the input is closing.
+var jetStreamFetchTimeout = time.Second
+
+func (n *natsReader) nextPulledMsg(ctx context.Context) (*nats.Msg, error) {
+	for {
+		n.cMut.Lock()
+		natsSub := n.natsSub
+		if len(n.fetched) > 0 {
+			msg := n.fetched[0]
+			n.fetched = n.fetched[1:]
+			n.cMut.Unlock()
+			return msg, nil
+		}
+		n.cMut.Unlock()
+
+		if natsSub == nil {
+			return nil, component.ErrNotConnected
+		}
+
+		select {
+		case <-n.interruptChan:
+			n.disconnect()
+			return nil, component.ErrNotConnected
+		default:
+		}
+
+		fetchCtx, done := context.WithTimeout(ctx, jetStreamFetchTimeout)
+		msgs, err := natsSub.Fetch(n.conf.JetStream.FetchSize, nats.Context(fetchCtx))
+		done()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, component.ErrTimeout
+			}
+			if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+				continue
+			}
+			n.log.Errorf("Failed to fetch NATS JetStream messages: %v\n", err)
+			n.disconnect()
+			return nil, component.ErrNotConnected
+		}
+
+		n.cMut.Lock()
+		if n.natsSub == natsSub {
+			n.fetched = append(n.fetched, msgs...)
+		}
+		n.cMut.Unlock()
+	}
 }
 
 func (n *natsReader) ReadWithContext(ctx context.Context) (*message.Batch, input.AsyncAckFn, error) {
@@ -169,16 +296,23 @@ func (n *natsReader) ReadWithContext(ctx context.Context) (*message.Batch, input
 	n.cMut.Unlock()
 
 	var msg *nats.Msg
-	var open bool
-	select {
-	case msg, open = <-natsChan:
-	case <-ctx.Done():
-		return nil, nil, component.ErrTimeout
-	case _, open = <-n.interruptChan:
-	}
-	if !open {
-		n.disconnect()
-		return nil, nil, component.ErrNotConnected
+	if n.conf.JetStream.Enabled {
+		var err error
+		if msg, err = n.nextPulledMsg(ctx); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var open bool
+		select {
+		case msg, open = <-natsChan:
+		case <-ctx.Done():
+			return nil, nil, component.ErrTimeout
+		case _, open = <-n.interruptChan:
+		}
+		if !open {
+			n.disconnect()
+			return nil, nil, component.ErrNotConnected
+		}
 	}
 
 	bmsg := message.QuickBatch([][]byte{msg.Data})
@@ -190,12 +324,36 @@ func (n *natsReader) ReadWithContext(ctx context.Context) (*message.Batch, input
 			value := msg.Header.Get(key)
 			part.MetaSet(key, value)
 		}
+		if n.conf.AutoDecompress {
+			if err := compression.DecompressPart(part, compression.NATSHeader); err != nil {
+				n.log.Warnf("%v\n", err)
+			}
+		}
+	}
+
+	if !n.conf.JetStream.Enabled {
+		if msg.Reply == "" {
+			return bmsg, func(ctx context.Context, res error) error {
+				return nil
+			}, nil
+		}
+		// Core NATS messages are not acknowledged, but requests are replied to
+		// with any synchronous responses.
+		return bmsg, transaction.WithReplies(bmsg, func(ctx context.Context, res *message.Batch) error {
+			return n.reply(natsConn, msg.Reply, res)
+		}, func(ctx context.Context, err error) error {
+			return nil
+		}), nil
 	}
 
 	return bmsg, func(ctx context.Context, res error) error {
 		var ackErr error
 		if res != nil {
-			ackErr = msg.Nak()
+			ackErr = n.nackPolicy.Nack(ctx, natsDeliveryAttempts(msg), func() error {
+				return msg.Nak()
+			}, func() error {
+				return msg.Term()
+			})
 		} else {
 			ackErr = msg.Ack()
 		}
@@ -206,6 +364,20 @@ func (n *natsReader) ReadWithContext(ctx context.Context) (*message.Batch, input
 	}, nil
 }
 
+func (n *natsReader) reply(natsConn *nats.Conn, subject string, res *message.Batch) error {
+	return res.Iter(func(i int, p *message.Part) error {
+		replyMsg := nats.NewMsg(subject)
+		replyMsg.Data = p.Get()
+		if natsConn.HeadersSupported() {
+			_ = p.MetaIter(func(k, v string) error {
+				replyMsg.Header.Add(k, v)
+				return nil
+			})
+		}
+		return natsConn.PublishMsg(replyMsg)
+	})
+}
+
 func (n *natsReader) CloseAsync() {
 	close(n.interruptChan)
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
 
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/impl/nats/auth"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -28,6 +31,10 @@ This input adds the following metadata fields to each message:
 
 ` + "```text" + `
 - nats_subject
+- nats_stream
+- nats_consumer
+- nats_sequence_stream
+- nats_sequence_consumer
 ` + "```" + `
 
 You can access these metadata fields using
@@ -70,6 +77,8 @@ You can access these metadata fields using
 			Description("The maximum number of outstanding acks to be allowed before consuming is halted.").
 			Advanced().
 			Default(1024)).
+		Field(service.NewInternalField(compression.AutoDecompressFieldSpec("`Content-Encoding` header", "`nats_jetstream` output"))).
+		Field(service.NewInternalField(input.NackPolicyFieldSpec())).
 		Field(service.NewTLSToggledField("tls")).
 		Field(service.NewInternalField(auth.FieldSpec()))
 }
@@ -99,8 +108,10 @@ type jetStreamReader struct {
 	durable       string
 	ackWait       time.Duration
 	maxAckPending int
+	decompress    bool
 	authConf      auth.Config
 	tlsConf       *tls.Config
+	nackPolicy    input.NackPolicy
 
 	log *service.Logger
 
@@ -187,6 +198,14 @@ func newJetStreamReaderFromConfig(conf *service.ParsedConfig, log *service.Logge
 		return nil, err
 	}
 
+	if j.decompress, err = conf.FieldBool("auto_decompress"); err != nil {
+		return nil, err
+	}
+
+	if j.nackPolicy, err = nackPolicyFromParsedConfig(conf.Namespace("nack_policy")); err != nil {
+		return nil, err
+	}
+
 	tlsConf, tlsEnabled, err := conf.FieldTLSToggled("tls")
 	if err != nil {
 		return nil, err
@@ -331,7 +350,7 @@ func (j *jetStreamReader) Read(ctx context.Context) (*service.Message, service.A
 			// TODO: Any errors need capturing here to signal a lost connection?
 			return nil, nil, err
 		}
-		return convertMessage(nmsg)
+		return j.convertMessage(nmsg)
 	}
 
 	for {
@@ -352,7 +371,7 @@ func (j *jetStreamReader) Read(ctx context.Context) (*service.Message, service.A
 		if len(msgs) == 0 {
 			continue
 		}
-		return convertMessage(msgs[0])
+		return j.convertMessage(msgs[0])
 	}
 }
 
@@ -369,20 +388,57 @@ func (j *jetStreamReader) Close(ctx context.Context) error {
 	return nil
 }
 
-func convertMessage(m *nats.Msg) (*service.Message, service.AckFunc, error) {
+func (j *jetStreamReader) convertMessage(m *nats.Msg) (*service.Message, service.AckFunc, error) {
 	msg := service.NewMessage(m.Data)
 	msg.MetaSet("nats_subject", m.Subject)
+	if meta, err := m.Metadata(); err == nil {
+		msg.MetaSet("nats_stream", meta.Stream)
+		msg.MetaSet("nats_consumer", meta.Consumer)
+		msg.MetaSet("nats_sequence_stream", strconv.FormatUint(meta.Sequence.Stream, 10))
+		msg.MetaSet("nats_sequence_consumer", strconv.FormatUint(meta.Sequence.Consumer, 10))
+	}
 	for k := range m.Header {
 		v := m.Header.Get(k)
 		if v != "" {
 			msg.MetaSet(k, v)
 		}
 	}
+	if j.decompress {
+		if err := decompressMessage(msg); err != nil {
+			j.log.Warnf("Failed to decompress message: %v\n", err)
+		}
+	}
 
 	return msg, func(ctx context.Context, res error) error {
 		if res == nil {
 			return m.Ack()
 		}
-		return m.Nak()
+		return j.nackPolicy.Nack(ctx, natsDeliveryAttempts(m), func() error {
+			return m.Nak()
+		}, func() error {
+			return m.Term()
+		})
 	}, nil
 }
+
+// natsDeliveryAttempts returns the number of times a JetStream message has
+// been delivered, or zero if unknown.
+func natsDeliveryAttempts(m *nats.Msg) int {
+	meta, err := m.Metadata()
+	if err != nil {
+		return 0
+	}
+	return int(meta.NumDelivered)
+}
+
+func nackPolicyFromParsedConfig(p *service.ParsedConfig) (input.NackPolicy, error) {
+	c := input.NewNackPolicyConfig()
+	var err error
+	if c.RequeueDelay, err = p.FieldString("requeue_delay"); err != nil {
+		return input.NackPolicy{}, err
+	}
+	if c.MaxAttempts, err = p.FieldInt("max_attempts"); err != nil {
+		return input.NackPolicy{}, err
+	}
+	return c.Policy()
+}
@@ -1,6 +1,7 @@
 package nats
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -10,7 +11,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/integration"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/transaction"
 )
 
 func TestIntegrationNats(t *testing.T) {
@@ -75,3 +82,209 @@ input:
 		)
 	})
 }
+
+func TestIntegrationNatsJetStreamPull(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "nats",
+		Tag:        "latest",
+		Cmd:        []string{"--js"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	var natsConn *nats.Conn
+	_ = resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		natsConn, err = nats.Connect(fmt.Sprintf("tcp://localhost:%v", resource.GetPort("4222/tcp")))
+		return err
+	}))
+	t.Cleanup(func() {
+		natsConn.Close()
+	})
+
+	template := `
+output:
+  nats_jetstream:
+    urls: [ nats://localhost:$PORT ]
+    subject: subject-$ID
+
+input:
+  nats:
+    urls: [ nats://localhost:$PORT ]
+    subject: subject-$ID
+    jetstream:
+      enabled: true
+      durable: durable-$ID
+      ack_wait: 1s
+      fetch_size: 10
+`
+	suite := integration.StreamTests(
+		integration.StreamTestOpenClose(),
+		integration.StreamTestSendBatch(10),
+		integration.StreamTestAtLeastOnceDelivery(),
+		integration.StreamTestStreamParallel(500),
+		integration.StreamTestStreamSequential(500),
+		integration.StreamTestStreamParallelLossyThroughReconnect(500),
+	)
+	suite.Run(
+		t, template,
+		integration.StreamTestOptPreTest(func(t testing.TB, ctx context.Context, testID string, vars *integration.StreamTestConfigVars) {
+			js, err := natsConn.JetStream()
+			require.NoError(t, err)
+
+			_, err = js.AddStream(&nats.StreamConfig{
+				Name:     "stream-" + testID,
+				Subjects: []string{"subject-" + testID},
+			})
+			require.NoError(t, err)
+		}),
+		integration.StreamTestOptSleepAfterInput(100*time.Millisecond),
+		integration.StreamTestOptSleepAfterOutput(100*time.Millisecond),
+		integration.StreamTestOptPort(resource.GetPort("4222/tcp")),
+	)
+}
+
+func TestIntegrationNatsRequestReply(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+	resource, err := pool.Run("nats", "latest", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	natsURL := fmt.Sprintf("tcp://localhost:%v", resource.GetPort("4222/tcp"))
+
+	var natsConn *nats.Conn
+	_ = resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		natsConn, err = nats.Connect(natsURL)
+		return err
+	}))
+	t.Cleanup(func() {
+		natsConn.Close()
+	})
+
+	_, err = natsConn.Subscribe("rpc", func(m *nats.Msg) {
+		reply := nats.NewMsg(m.Reply)
+		reply.Data = append([]byte("echo: "), m.Data...)
+		reply.Header.Set("fooheader", "foovalue")
+		_ = m.RespondMsg(reply)
+	})
+	require.NoError(t, err)
+	require.NoError(t, natsConn.Flush())
+
+	conf := output.NewNATSConfig()
+	conf.URLs = []string{natsURL}
+	conf.Subject = "rpc"
+	conf.PropagateResponse = true
+
+	w, err := newNATSWriter(conf, mock.NewManager(), log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, w.ConnectWithContext(context.Background()))
+	t.Cleanup(w.CloseAsync)
+
+	for i := 0; i < 10; i++ {
+		testStr := fmt.Sprintf("test%v", i)
+
+		resultStore := transaction.NewResultStore()
+		testMsg := message.QuickBatch([][]byte{[]byte(testStr)})
+		transaction.AddResultStore(testMsg, resultStore)
+
+		require.NoError(t, w.WriteWithContext(context.Background(), testMsg))
+		resMsgs := resultStore.Get()
+		require.Len(t, resMsgs, 1)
+
+		resMsg := resMsgs[0]
+		require.Equal(t, 1, resMsg.Len())
+		assert.Equal(t, "echo: "+testStr, string(resMsg.Get(0).Get()))
+		assert.Equal(t, "foovalue", resMsg.Get(0).MetaGet("Fooheader"))
+	}
+
+	conf.Subject = "nobody_home"
+	w, err = newNATSWriter(conf, mock.NewManager(), log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, w.ConnectWithContext(context.Background()))
+	t.Cleanup(w.CloseAsync)
+
+	require.Error(t, w.WriteWithContext(context.Background(), message.QuickBatch([][]byte{[]byte("hello")})))
+}
+
+func TestIntegrationNatsInputReply(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+	resource, err := pool.Run("nats", "latest", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	natsURL := fmt.Sprintf("tcp://localhost:%v", resource.GetPort("4222/tcp"))
+
+	var natsConn *nats.Conn
+	_ = resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		natsConn, err = nats.Connect(natsURL)
+		return err
+	}))
+	t.Cleanup(func() {
+		natsConn.Close()
+	})
+
+	conf := input.NewNATSConfig()
+	conf.URLs = []string{natsURL}
+	conf.Subject = "rpc"
+
+	r, err := newNATSReader(conf, log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, r.ConnectWithContext(context.Background()))
+	t.Cleanup(r.CloseAsync)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	replyChan := make(chan *nats.Msg, 1)
+	go func() {
+		reply, err := natsConn.RequestWithContext(ctx, "rpc", []byte("hello"))
+		assert.NoError(t, err)
+		replyChan <- reply
+	}()
+
+	msg, ackFn, err := r.ReadWithContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(msg.Get(0).Get()))
+
+	resMsg := msg.Copy()
+	resMsg.Get(0).Set([]byte("hello world"))
+	resMsg.Get(0).MetaSet("fooheader", "foovalue")
+	require.NoError(t, transaction.SetAsResponse(resMsg))
+	require.NoError(t, ackFn(ctx, nil))
+
+	select {
+	case reply := <-replyChan:
+		require.NotNil(t, reply)
+		assert.Equal(t, "hello world", string(reply.Data))
+		assert.Equal(t, "foovalue", reply.Header.Get("fooheader"))
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for reply")
+	}
+}
@@ -3,6 +3,7 @@ package nats
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -16,11 +17,13 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/component/output/processors"
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/impl/nats/auth"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	btls "github.com/benthosdev/benthos/v4/internal/tls"
+	"github.com/benthosdev/benthos/v4/internal/transaction"
 )
 
 func init() {
@@ -32,6 +35,10 @@ func init() {
 		Description: output.Description(true, false, `
 This output will interpolate functions within the subject field, you can find a list of functions [here](/docs/configuration/interpolation#bloblang-queries).
 
+### Request Reply
+
+It's possible to send each message as a request and wait for a reply by setting `+"`propagate_response` to `true`"+`. The reply of each request is [propagated back](/docs/guides/sync_responses) to the input source, with any reply headers added as metadata, which allows RPC style integrations over NATS. Only inputs that support synchronous responses are able to make use of these propagated responses. A message is considered failed, and is therefore retried, when no reply is received within the `+"`request_timeout`"+`, or when the subject has no responders.
+
 `+auth.Description()),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString(
@@ -47,6 +54,9 @@ This output will interpolate functions within the subject field, you can find a
 					"Timestamp":    `${!meta("Timestamp")}`,
 				},
 			).IsInterpolated().Map(),
+			payloadCompressionFieldSpec(),
+			docs.FieldBool("propagate_response", "Whether to send each message as a request and [propagate the reply](/docs/guides/sync_responses) back to the input.").Advanced().AtVersion("4.2.0"),
+			docs.FieldString("request_timeout", "The maximum period of time to wait for the reply of a request when `propagate_response` is `true`.").Advanced().AtVersion("4.2.0"),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 			btls.FieldSpec(),
 			auth.FieldSpec(),
@@ -74,11 +84,13 @@ type natsWriter struct {
 	natsConn *nats.Conn
 	connMut  sync.RWMutex
 
-	urls       string
-	conf       output.NATSConfig
-	headers    map[string]*field.Expression
-	subjectStr *field.Expression
-	tlsConf    *tls.Config
+	urls           string
+	conf           output.NATSConfig
+	headers        map[string]*field.Expression
+	subjectStr     *field.Expression
+	requestTimeout time.Duration
+	compressor     *compression.Compressor
+	tlsConf        *tls.Config
 }
 
 func newNATSWriter(conf output.NATSConfig, mgr bundle.NewManagement, log log.Modular) (*natsWriter, error) {
@@ -98,6 +110,16 @@ func newNATSWriter(conf output.NATSConfig, mgr bundle.NewManagement, log log.Mod
 	}
 	n.urls = strings.Join(conf.URLs, ",")
 
+	if n.compressor, err = conf.PayloadCompression.Compressor(); err != nil {
+		return nil, err
+	}
+
+	if conf.PropagateResponse {
+		if n.requestTimeout, err = time.ParseDuration(conf.RequestTimeout); err != nil {
+			return nil, fmt.Errorf("failed to parse request timeout: %v", err)
+		}
+	}
+
 	if conf.TLS.Enabled {
 		if n.tlsConf, err = conf.TLS.Get(); err != nil {
 			return nil, err
@@ -136,11 +158,6 @@ func (n *natsWriter) ConnectWithContext(ctx context.Context) error {
 
 // WriteWithContext attempts to write a message.
 func (n *natsWriter) WriteWithContext(ctx context.Context, msg *message.Batch) error {
-	return n.Write(msg)
-}
-
-// Write attempts to write a message.
-func (n *natsWriter) Write(msg *message.Batch) error {
 	n.connMut.RLock()
 	conn := n.natsConn
 	n.connMut.RUnlock()
@@ -149,19 +166,40 @@ func (n *natsWriter) Write(msg *message.Batch) error {
 		return component.ErrNotConnected
 	}
 
-	return output.IterateBatchedSend(msg, func(i int, p *message.Part) error {
+	var replies []*nats.Msg
+	if n.conf.PropagateResponse {
+		replies = make([]*nats.Msg, msg.Len())
+	}
+
+	err := output.IterateBatchedSend(msg, func(i int, p *message.Part) error {
 		subject := n.subjectStr.String(i, msg)
 		n.log.Debugf("Writing NATS message to topic %s", subject)
 		// fill message data
 		nMsg := nats.NewMsg(subject)
-		nMsg.Data = p.Get()
+		data, encoding, err := n.compressor.Compress(p.Get())
+		if err != nil {
+			return fmt.Errorf("failed to compress message: %w", err)
+		}
+		nMsg.Data = data
 		if conn.HeadersSupported() {
 			// fill bloblang headers
 			for k, v := range n.headers {
 				nMsg.Header.Add(k, v.String(i, msg))
 			}
+			if encoding != "" {
+				nMsg.Header.Set(compression.NATSHeader, encoding)
+			}
+		} else if encoding != "" {
+			return errors.New("unable to label compressed message as the server does not support headers")
+		}
+
+		if n.conf.PropagateResponse {
+			reqCtx, done := context.WithTimeout(ctx, n.requestTimeout)
+			replies[i], err = conn.RequestMsgWithContext(reqCtx, nMsg)
+			done()
+		} else {
+			err = conn.PublishMsg(nMsg)
 		}
-		err := conn.PublishMsg(nMsg)
 		if err == nats.ErrConnectionClosed {
 			conn.Close()
 			n.connMut.Lock()
@@ -171,6 +209,25 @@ func (n *natsWriter) Write(msg *message.Batch) error {
 		}
 		return err
 	})
+	if err == nil && n.conf.PropagateResponse {
+		n.propagateReplies(msg, replies)
+	}
+	return err
+}
+
+func (n *natsWriter) propagateReplies(msg *message.Batch, replies []*nats.Msg) {
+	msgCopy := msg.Copy()
+	_ = msgCopy.Iter(func(i int, p *message.Part) error {
+		reply := replies[i]
+		p.Set(reply.Data)
+		for k := range reply.Header {
+			p.MetaSet(k, reply.Header.Get(k))
+		}
+		return nil
+	})
+	if err := transaction.SetAsResponse(msgCopy); err != nil {
+		n.log.Warnf("Unable to propagate response to input: %v", err)
+	}
 }
 
 func (n *natsWriter) CloseAsync() {
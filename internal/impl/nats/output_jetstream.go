@@ -3,11 +3,13 @@ package nats
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"strings"
 	"sync"
 
 	"github.com/nats-io/nats.go"
 
+	"github.com/benthosdev/benthos/v4/internal/compression"
 	"github.com/benthosdev/benthos/v4/internal/impl/nats/auth"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -36,6 +38,7 @@ func natsJetStreamOutputConfig() *service.ConfigSpec {
 				"Content-Type": "application/json",
 				"Timestamp":    `${!meta("Timestamp")}`,
 			}).Version("4.1.0")).
+		Field(service.NewInternalField(payloadCompressionFieldSpec())).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of messages to have in flight at a given time. Increase this to improve throughput.").
 			Default(1024)).
@@ -67,6 +70,7 @@ type jetStreamOutput struct {
 	subjectStrRaw string
 	subjectStr    *service.InterpolatedString
 	headers       map[string]*service.InterpolatedString
+	compressor    *compression.Compressor
 	authConf      auth.Config
 	tlsConf       *tls.Config
 
@@ -103,6 +107,10 @@ func newJetStreamWriterFromConfig(conf *service.ParsedConfig, log *service.Logge
 		return nil, err
 	}
 
+	if j.compressor, err = payloadCompressorFromParsed(conf.Namespace("payload_compression")); err != nil {
+		return nil, err
+	}
+
 	tlsConf, tlsEnabled, err := conf.FieldTLSToggled("tls")
 	if err != nil {
 		return nil, err
@@ -183,10 +191,17 @@ func (j *jetStreamOutput) Write(ctx context.Context, msg *service.Message) error
 	if err != nil {
 		return err
 	}
-	jsmsg.Data = msgBytes
+	data, encoding, err := j.compressor.Compress(msgBytes)
+	if err != nil {
+		return fmt.Errorf("failed to compress message: %w", err)
+	}
+	jsmsg.Data = data
 	for k, v := range j.headers {
 		jsmsg.Header.Add(k, v.String(msg))
 	}
+	if encoding != "" {
+		jsmsg.Header.Set(compression.NATSHeader, encoding)
+	}
 
 	_, err = jCtx.PublishMsg(jsmsg)
 	return err
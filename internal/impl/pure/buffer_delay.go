@@ -0,0 +1,336 @@
+package pure
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func delayBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.2.0").
+		Categories("Utility").
+		Summary("Holds each message until a scheduled time, calculated per message from either an interpolated duration or an interpolated timestamp, before releasing it to the pipeline.").
+		Description(`
+This buffer allows messages to be delivered at a later time without blocking the pipeline, which makes it possible to implement patterns such as retry-after and scheduled delivery natively. Unlike the `+"[`sleep` processor](/docs/components/processors/sleep)"+`, which blocks the processing of all messages until it completes, this buffer holds each message independently and releases messages in the order of their scheduled time, regardless of the order in which they were consumed.
+
+The scheduled time of a message is determined by exactly one of the fields `+"[`duration`](#duration)"+`, which is a period of time relative to when the message was consumed, or `+"[`until`](#until)"+`, which is an absolute timestamp. If the scheduled time of a message cannot be determined, for example if the interpolation resolves to an empty or malformed value, then an error is logged and the message is released immediately. Messages with a scheduled time in the past are also released immediately.
+
+## Delivery Guarantees
+
+This buffer honours the transaction model within Benthos, messages are held in memory but are not acknowledged at the input level until they have been released and successfully delivered to outputs. Therefore, provided the input supports redelivery, messages held by this buffer are not lost when the service is terminated, and are instead consumed again the next time the service starts.
+
+Since messages are not acknowledged until they are delivered, inputs that limit the number of unacknowledged messages in flight (such as the `+"`checkpoint_limit`"+` of the `+"`kafka`"+` input) will apply back pressure once that limit is reached, and this should be taken into account when holding messages for long periods.
+
+If a released message is rejected downstream it is held again and released immediately.
+
+During graceful termination the buffer waits for all held messages to be released and delivered. When the service is forcefully terminated any remaining held messages are rejected so that they are consumed again the next time the service starts.`).
+		Field(service.NewInterpolatedStringField("duration").
+			Description("A duration string describing how long to hold each message for, relative to the time at which it was consumed.").
+			Example("10s").
+			Example(`${! meta("retry_after") }`).
+			Optional()).
+		Field(service.NewInterpolatedStringField("until").
+			Description("A timestamp in RFC 3339 format describing when each message should be released.").
+			Example(`${! json("deliver_at") }`).
+			Optional()).
+		Field(service.NewIntField("limit").
+			Description("The maximum number of messages to hold at a given time (including messages that have been released but not yet acknowledged) before applying back pressure upstream.").
+			Default(10000).
+			Advanced()).
+		Example("Retry After", `
+Here we consume failed requests that carry a `+"`retry_after`"+` metadata field describing how long we should wait before attempting them again. Messages are held without blocking those that are ready to be attempted:`,
+			`
+buffer:
+  delay:
+    duration: ${! meta("retry_after").or("0s") }
+`,
+		).
+		Example("Scheduled Delivery", `
+Here we consume notifications that should be sent at a time specified within the message:`,
+			`
+buffer:
+  delay:
+    until: ${! json("send_at") }
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchBuffer(
+		"delay", delayBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newDelayBufferFromConfig(conf, mgr)
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newDelayBufferFromConfig(conf *service.ParsedConfig, res *service.Resources) (*delayBuffer, error) {
+	var duration, until *service.InterpolatedString
+	var err error
+	if conf.Contains("duration") {
+		if duration, err = conf.FieldInterpolatedString("duration"); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains("until") {
+		if until, err = conf.FieldInterpolatedString("until"); err != nil {
+			return nil, err
+		}
+	}
+	if (duration == nil) == (until == nil) {
+		return nil, errors.New("exactly one of the fields duration or until must be specified")
+	}
+
+	limit, err := conf.FieldInt("limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		return nil, fmt.Errorf("limit must be greater than zero, got %v", limit)
+	}
+
+	return newDelayBuffer(duration, until, limit, time.Now, res.Logger()), nil
+}
+
+//------------------------------------------------------------------------------
+
+type delayedMessage struct {
+	at    time.Time
+	m     *service.Message
+	ackFn service.AckFunc
+}
+
+// delayQueue is a min-heap of messages ordered by their scheduled time.
+type delayQueue []*delayedMessage
+
+func (q delayQueue) Len() int            { return len(q) }
+func (q delayQueue) Less(i, j int) bool  { return q[i].at.Before(q[j].at) }
+func (q delayQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *delayQueue) Push(x interface{}) { *q = append(*q, x.(*delayedMessage)) }
+
+func (q *delayQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	d := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return d
+}
+
+var errDelayClosed = errors.New("message rejected as delay buffer was closed")
+
+type delayBuffer struct {
+	duration *service.InterpolatedString
+	until    *service.InterpolatedString
+	limit    int
+	clock    func() time.Time
+	logger   *service.Logger
+
+	cond       *sync.Cond
+	pending    delayQueue
+	inFlight   int
+	endOfInput bool
+	closed     bool
+}
+
+func newDelayBuffer(
+	duration, until *service.InterpolatedString,
+	limit int,
+	clock func() time.Time,
+	logger *service.Logger,
+) *delayBuffer {
+	return &delayBuffer{
+		duration: duration,
+		until:    until,
+		limit:    limit,
+		clock:    clock,
+		logger:   logger,
+		cond:     sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// scheduledAt returns the time at which a message should be released, falling
+// back to now when it cannot be determined.
+func (d *delayBuffer) scheduledAt(now time.Time, msg *service.Message) time.Time {
+	if d.duration != nil {
+		durStr := d.duration.String(msg)
+		period, err := time.ParseDuration(durStr)
+		if err != nil {
+			d.logger.Errorf("Failed to parse delay duration '%v', releasing message immediately: %v", durStr, err)
+			return now
+		}
+		return now.Add(period)
+	}
+
+	untilStr := d.until.String(msg)
+	ts, err := time.Parse(time.RFC3339Nano, untilStr)
+	if err != nil {
+		d.logger.Errorf("Failed to parse delay timestamp '%v', releasing message immediately: %v", untilStr, err)
+		return now
+	}
+	return ts
+}
+
+func (d *delayBuffer) WriteBatch(ctx context.Context, msgBatch service.MessageBatch, aFn service.AckFunc) error {
+	if len(msgBatch) == 0 {
+		return aFn(ctx, nil)
+	}
+
+	now := d.clock()
+	aggregatedAck := batch.NewCombinedAcker(batch.AckFunc(aFn))
+
+	delayed := make([]*delayedMessage, 0, len(msgBatch))
+	for _, msg := range msgBatch {
+		delayed = append(delayed, &delayedMessage{
+			at:    d.scheduledAt(now, msg),
+			m:     msg,
+			ackFn: service.AckFunc(aggregatedAck.Derive()),
+		})
+	}
+
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		d.cond.L.Lock()
+		d.cond.Broadcast()
+		d.cond.L.Unlock()
+	}()
+
+	d.cond.L.Lock()
+	defer d.cond.L.Unlock()
+
+	// A batch larger than the limit is allowed when nothing else is held,
+	// otherwise it would never be accepted.
+	for held := len(d.pending) + d.inFlight; held > 0 && held+len(delayed) > d.limit; held = len(d.pending) + d.inFlight {
+		if d.closed {
+			return service.ErrEndOfBuffer
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		d.cond.Wait()
+	}
+	if d.closed {
+		return service.ErrEndOfBuffer
+	}
+
+	for _, dm := range delayed {
+		heap.Push(&d.pending, dm)
+	}
+	d.cond.Broadcast()
+	return nil
+}
+
+func (d *delayBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		d.cond.L.Lock()
+		d.cond.Broadcast()
+		d.cond.L.Unlock()
+	}()
+
+	d.cond.L.Lock()
+	defer d.cond.L.Unlock()
+
+	var released []*delayedMessage
+	for {
+		if d.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		var timer *time.Timer
+		if len(d.pending) > 0 {
+			now := d.clock()
+			for len(d.pending) > 0 && !d.pending[0].at.After(now) {
+				released = append(released, heap.Pop(&d.pending).(*delayedMessage))
+			}
+			if len(released) > 0 {
+				break
+			}
+			timer = time.AfterFunc(d.pending[0].at.Sub(now), func() {
+				d.cond.L.Lock()
+				d.cond.Broadcast()
+				d.cond.L.Unlock()
+			})
+		} else if d.endOfInput && d.inFlight == 0 {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+
+		d.cond.Wait()
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	d.inFlight += len(released)
+	d.cond.Broadcast()
+
+	outBatch := make(service.MessageBatch, 0, len(released))
+	for _, dm := range released {
+		outBatch = append(outBatch, dm.m)
+	}
+	return outBatch, func(ctx context.Context, err error) error {
+		d.cond.L.Lock()
+		d.inFlight -= len(released)
+		if err != nil && !d.closed {
+			// Rejected messages are held again and released immediately.
+			now := d.clock()
+			for _, dm := range released {
+				dm.at = now
+				heap.Push(&d.pending, dm)
+			}
+			d.cond.Broadcast()
+			d.cond.L.Unlock()
+			return nil
+		}
+		d.cond.Broadcast()
+		d.cond.L.Unlock()
+
+		for _, dm := range released {
+			_ = dm.ackFn(ctx, err)
+		}
+		return nil
+	}, nil
+}
+
+func (d *delayBuffer) EndOfInput() {
+	d.cond.L.Lock()
+	d.endOfInput = true
+	d.cond.Broadcast()
+	d.cond.L.Unlock()
+}
+
+func (d *delayBuffer) Close(ctx context.Context) error {
+	d.cond.L.Lock()
+	d.closed = true
+	pending := d.pending
+	d.pending = nil
+	d.cond.Broadcast()
+	d.cond.L.Unlock()
+
+	// Reject anything still held so that it is consumed again the next time
+	// the service starts.
+	for _, dm := range pending {
+		_ = dm.ackFn(ctx, errDelayClosed)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func delayBufFromConf(t *testing.T, conf string) *delayBuffer {
+	t.Helper()
+
+	parsedConf, err := delayBufferConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	buf, err := newDelayBufferFromConfig(parsedConf, service.MockResources())
+	require.NoError(t, err)
+
+	return buf
+}
+
+func TestDelayBufferConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`{}`,
+		`
+duration: 1s
+until: 2022-01-01T00:00:00Z
+`,
+		`
+duration: 1s
+limit: 0
+`,
+	} {
+		parsedConf, err := delayBufferConfig().ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = newDelayBufferFromConfig(parsedConf, service.MockResources())
+		assert.Error(t, err, conf)
+	}
+}
+
+func TestDelayBufferDuration(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	buf := delayBufFromConf(t, `
+duration: ${! meta("delay") }
+`)
+
+	var ackMut sync.Mutex
+	var acked []error
+
+	newMsg := func(content, delay string) *service.Message {
+		msg := service.NewMessage([]byte(content))
+		msg.MetaSet("delay", delay)
+		return msg
+	}
+
+	start := time.Now()
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{
+		newMsg("third", "300ms"),
+		newMsg("first", "0s"),
+		newMsg("second", "100ms"),
+		newMsg("malformed", "nope"),
+	}, func(ctx context.Context, err error) error {
+		ackMut.Lock()
+		acked = append(acked, err)
+		ackMut.Unlock()
+		return nil
+	}))
+
+	var contents []string
+	var ackFns []service.AckFunc
+	for len(contents) < 4 {
+		b, aFn, err := buf.ReadBatch(ctx)
+		require.NoError(t, err)
+		for _, m := range b {
+			mBytes, err := m.AsBytes()
+			require.NoError(t, err)
+			contents = append(contents, string(mBytes))
+		}
+		ackFns = append(ackFns, aFn)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*300)
+
+	require.Len(t, contents, 4)
+	assert.ElementsMatch(t, []string{"first", "malformed"}, contents[:2])
+	assert.Equal(t, []string{"second", "third"}, contents[2:])
+
+	// The upstream ack must only be called once every message is delivered.
+	for _, aFn := range ackFns {
+		ackMut.Lock()
+		assert.Empty(t, acked)
+		ackMut.Unlock()
+		require.NoError(t, aFn(ctx, nil))
+	}
+
+	ackMut.Lock()
+	assert.Equal(t, []error{nil}, acked)
+	ackMut.Unlock()
+
+	buf.EndOfInput()
+	_, _, err := buf.ReadBatch(ctx)
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+}
+
+func TestDelayBufferUntil(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	buf := delayBufFromConf(t, `
+until: ${! content() }
+`)
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339Nano)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte(future)),
+		service.NewMessage([]byte(past)),
+	}, func(ctx context.Context, err error) error { return nil }))
+
+	b, aFn, err := buf.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, b, 1)
+	msgEqual(t, past, b[0])
+	require.NoError(t, aFn(ctx, nil))
+
+	readCtx, readDone := context.WithTimeout(ctx, time.Millisecond*50)
+	_, _, err = buf.ReadBatch(readCtx)
+	readDone()
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	require.NoError(t, buf.Close(ctx))
+}
+
+func TestDelayBufferNackRedelivers(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	buf := delayBufFromConf(t, `
+duration: 0s
+`)
+
+	var ackErr error
+	acked := make(chan struct{})
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte("hello world")),
+	}, func(ctx context.Context, err error) error {
+		ackErr = err
+		close(acked)
+		return nil
+	}))
+
+	b, aFn, err := buf.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, b, 1)
+	require.NoError(t, aFn(ctx, errors.New("nope")))
+
+	b, aFn, err = buf.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, b, 1)
+	msgEqual(t, "hello world", b[0])
+	require.NoError(t, aFn(ctx, nil))
+
+	select {
+	case <-acked:
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	}
+	assert.NoError(t, ackErr)
+}
+
+func TestDelayBufferCloseRejects(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	buf := delayBufFromConf(t, `
+duration: 1h
+`)
+
+	var ackErr error
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte("hello world")),
+	}, func(ctx context.Context, err error) error {
+		ackErr = err
+		return nil
+	}))
+
+	require.NoError(t, buf.Close(ctx))
+	assert.Equal(t, errDelayClosed, ackErr)
+
+	_, _, err := buf.ReadBatch(ctx)
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+}
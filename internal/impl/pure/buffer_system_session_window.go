@@ -0,0 +1,301 @@
+package pure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func sessionWindowBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		// Stable(). TODO
+		Version("4.2.0").
+		Categories("Windowing").
+		Summary("Groups messages sharing a key into session windows, where a window is closed once no message of the key has been seen for a gap of inactivity, following the system clock.").
+		Description(`
+A session window is a grouping of messages that share a key, obtained by resolving the `+"[`key` field](#key)"+` for each message, and that are separated by no more than the `+"[`gap` duration](#gap)"+` from one another. Unlike a `+"[`system_window` buffer](/docs/components/buffers/system_window)"+` the size of a session window is not fixed, a window is extended each time a message of the same key arrives within the gap and therefore a window could remain open indefinitely for a key that is consistently active.
+
+Messages are allocated to a window either by the processing time (the time at which they're ingested) or by the event time, and this is controlled via the `+"[`timestamp_mapping` field](#timestamp_mapping)"+`. A window is flushed only once the system clock surpasses the timestamp of the latest message of the window plus the gap. If a message arrives for a key after its window has ended and before it was flushed then the prior window is closed and a new window is started.
+
+When a window is flushed its messages are emitted as a single batch, and each message has the metadata fields `+"`window_start_timestamp`"+` and `+"`window_end_timestamp`"+` added to it containing the timestamps of the earliest and latest messages of the window as RFC3339 strings, and `+"`window_key`"+` containing the key of the window.
+
+## Delivery Guarantees
+
+This buffer honours the transaction model within Benthos in order to ensure that messages are not acknowledged until they are successfully delivered to outputs.
+
+During graceful termination any windows that have not yet been flushed will have their messages nacked such that they are re-consumed the next time the service starts.
+`).
+		Field(service.NewBloblangField("timestamp_mapping").
+			Description(`
+A [Bloblang mapping](/docs/guides/bloblang/about) applied to each message during ingestion that provides the timestamp to use for allocating it a window. By default the function `+"`now()`"+` is used in order to generate a fresh timestamp at the time of ingestion (the processing time), whereas this mapping can instead extract a timestamp from the message itself (the event time).
+
+The timestamp value assigned to `+"`root`"+` must either be a numerical unix time in seconds (with up to nanosecond precision via decimals), or a string in ISO 8601 format. If the mapping fails or provides an invalid result the message will be dropped (with logging to describe the problem).
+`).
+			Default("root = now()").
+			Example("root = this.created_at").Example(`root = meta("kafka_timestamp_unix").number()`)).
+		Field(service.NewInterpolatedStringField("key").
+			Description("An interpolated string that resolves to the key of each message, messages are only grouped into a window with other messages that share the same key.").
+			Example(`${! json("user.id") }`).Example(`${! meta("kafka_key") }`)).
+		Field(service.NewStringField("gap").
+			Description("A duration string describing the period of inactivity after which the window of a key is closed.").
+			Example("30s").Example("10m")).
+		Example("Grouping Clicks into User Sessions", `Given a stream of click events of the form:
+
+`+"```json"+`
+{
+  "user_id": "f55fa7e2",
+  "created_at": "2021-08-07T09:49:35Z",
+  "page": "/checkout"
+}
+`+"```"+`
+
+We can use a session window buffer in order to create a message summarising each browsing session of a user, where a session ends once the user has been inactive for ten minutes:`,
+			`
+buffer:
+  system_session_window:
+    timestamp_mapping: root = this.created_at
+    key: '${! json("user_id") }'
+    gap: 10m
+
+pipeline:
+  processors:
+    # Reduce each batch to a single message by deleting indexes > 0, and
+    # aggregate the pages visited.
+    - bloblang: |
+        root = if batch_index() == 0 {
+          {
+            "user_id": this.user_id,
+            "started_at": meta("window_start_timestamp"),
+            "ended_at": meta("window_end_timestamp"),
+            "pages": json("page").from_all(),
+          }
+        } else { deleted() }
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchBuffer(
+		"system_session_window", sessionWindowBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			gap, err := getDuration(conf, true, "gap")
+			if err != nil {
+				return nil, err
+			}
+			if gap <= 0 {
+				return nil, fmt.Errorf("invalid gap '%v' must be greater than zero", gap)
+			}
+			key, err := conf.FieldInterpolatedString("key")
+			if err != nil {
+				return nil, err
+			}
+			tsMapping, err := conf.FieldBloblang("timestamp_mapping")
+			if err != nil {
+				return nil, err
+			}
+			return newSessionWindowBuffer(tsMapping, key, func() time.Time {
+				return time.Now().UTC()
+			}, gap, mgr.Logger())
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type sessionWindow struct {
+	key        string
+	start, end time.Time
+	pending    []*tsMessage
+}
+
+type sessionWindowBuffer struct {
+	logger *service.Logger
+
+	tsMapping *bloblang.Executor
+	key       *service.InterpolatedString
+	clock     utcNowProvider
+	gap       time.Duration
+
+	// Windows that have been closed by a later message of the same key but
+	// not yet flushed.
+	closed     []*sessionWindow
+	open       map[string]*sessionWindow
+	pendingMut sync.Mutex
+
+	// Signalled when a write may have changed the next window to be flushed.
+	writtenChan chan struct{}
+
+	endOfInputChan      chan struct{}
+	closeEndOfInputOnce sync.Once
+}
+
+func newSessionWindowBuffer(
+	tsMapping *bloblang.Executor,
+	key *service.InterpolatedString,
+	clock utcNowProvider,
+	gap time.Duration,
+	logger *service.Logger,
+) (*sessionWindowBuffer, error) {
+	return &sessionWindowBuffer{
+		logger:         logger,
+		tsMapping:      tsMapping,
+		key:            key,
+		clock:          clock,
+		gap:            gap,
+		open:           map[string]*sessionWindow{},
+		writtenChan:    make(chan struct{}, 1),
+		endOfInputChan: make(chan struct{}),
+	}, nil
+}
+
+func (w *sessionWindowBuffer) WriteBatch(ctx context.Context, msgBatch service.MessageBatch, aFn service.AckFunc) error {
+	w.pendingMut.Lock()
+	defer w.pendingMut.Unlock()
+
+	aggregatedAck := batch.NewCombinedAcker(batch.AckFunc(aFn))
+
+	for i, msg := range msgBatch {
+		ts, err := getWindowTimestamp(w.tsMapping, w.logger, i, msgBatch)
+		if err != nil {
+			return err
+		}
+		key := msgBatch.InterpolatedString(i, w.key)
+
+		window, exists := w.open[key]
+		if exists && ts.After(window.end.Add(w.gap)) {
+			// The gap has passed since the last message of this window, so we
+			// close it and begin a new one.
+			w.closed = append(w.closed, window)
+			exists = false
+		}
+		if !exists {
+			window = &sessionWindow{key: key, start: ts, end: ts}
+			w.open[key] = window
+		}
+		if ts.Before(window.start) {
+			window.start = ts
+		}
+		if ts.After(window.end) {
+			window.end = ts
+		}
+		window.pending = append(window.pending, &tsMessage{
+			ts: ts, m: msg, ackFn: service.AckFunc(aggregatedAck.Derive()),
+		})
+	}
+
+	select {
+	case w.writtenChan <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// nextWindow removes and returns the next window that is ready to be flushed,
+// or if none are ready returns the time at which the next open window will be
+// ready, which is zero if there are no open windows.
+func (w *sessionWindowBuffer) nextWindow() (*sessionWindow, time.Time) {
+	w.pendingMut.Lock()
+	defer w.pendingMut.Unlock()
+
+	if len(w.closed) > 0 {
+		window := w.closed[0]
+		w.closed = w.closed[1:]
+		return window, time.Time{}
+	}
+
+	var next *sessionWindow
+	for _, window := range w.open {
+		if next == nil || window.end.Before(next.end) {
+			next = window
+		}
+	}
+	if next == nil {
+		return nil, time.Time{}
+	}
+
+	closesAt := next.end.Add(w.gap)
+	if w.clock().Before(closesAt) {
+		return nil, closesAt
+	}
+	delete(w.open, next.key)
+	return next, time.Time{}
+}
+
+func (w *sessionWindowBuffer) flushWindow(window *sessionWindow) (service.MessageBatch, service.AckFunc) {
+	flushBatch := make(service.MessageBatch, 0, len(window.pending))
+	flushAcks := make([]service.AckFunc, 0, len(window.pending))
+
+	for _, pending := range window.pending {
+		tmpMsg := pending.m.Copy()
+		tmpMsg.MetaSet("window_start_timestamp", window.start.UTC().Format(time.RFC3339Nano))
+		tmpMsg.MetaSet("window_end_timestamp", window.end.UTC().Format(time.RFC3339Nano))
+		tmpMsg.MetaSet("window_key", window.key)
+		flushBatch = append(flushBatch, tmpMsg)
+		flushAcks = append(flushAcks, pending.ackFn)
+	}
+
+	return flushBatch, func(ctx context.Context, err error) error {
+		for _, aFn := range flushAcks {
+			_ = aFn(ctx, err)
+		}
+		return nil
+	}
+}
+
+func (w *sessionWindowBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		window, closesAt := w.nextWindow()
+		if window != nil {
+			msgBatch, aFn := w.flushWindow(window)
+			return msgBatch, aFn, nil
+		}
+
+		var closesAtChan <-chan time.Time
+		if !closesAt.IsZero() {
+			closesAtChan = time.After(closesAt.Sub(w.clock()))
+		}
+
+		select {
+		case <-closesAtChan:
+		case <-w.writtenChan:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-w.endOfInputChan:
+			// Nack all pending messages so that we re-consume them on the next
+			// start up.
+			w.pendingMut.Lock()
+			for _, window := range w.closed {
+				for _, pending := range window.pending {
+					_ = pending.ackFn(ctx, errWindowClosed)
+				}
+			}
+			for _, window := range w.open {
+				for _, pending := range window.pending {
+					_ = pending.ackFn(ctx, errWindowClosed)
+				}
+			}
+			w.closed = nil
+			w.open = map[string]*sessionWindow{}
+			w.pendingMut.Unlock()
+			return nil, nil, service.ErrEndOfBuffer
+		}
+	}
+}
+
+func (w *sessionWindowBuffer) EndOfInput() {
+	w.closeEndOfInputOnce.Do(func() {
+		close(w.endOfInputChan)
+	})
+}
+
+func (w *sessionWindowBuffer) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,191 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func newTestSessionWindowBuffer(t *testing.T, clock utcNowProvider, gap time.Duration) *sessionWindowBuffer {
+	t.Helper()
+
+	mapping, err := bloblang.Parse(`root = this.ts`)
+	require.NoError(t, err)
+
+	key, err := service.NewInterpolatedString(`${! json("key") }`)
+	require.NoError(t, err)
+
+	w, err := newSessionWindowBuffer(mapping, key, clock, gap, nil)
+	require.NoError(t, err)
+	return w
+}
+
+func assertBatchIDs(t *testing.T, expected []string, msgBatch service.MessageBatch) {
+	t.Helper()
+
+	var actual []string
+	for _, msg := range msgBatch {
+		v, err := msg.AsStructured()
+		require.NoError(t, err)
+		actual = append(actual, v.(map[string]interface{})["id"].(string))
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestSessionWindowCreation(t *testing.T) {
+	currentTS := time.Unix(10, 0).UTC()
+	w := newTestSessionWindowBuffer(t, func() time.Time {
+		return currentTS
+	}, time.Second)
+
+	err := w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"1","key":"a","ts":8}`)),
+		service.NewMessage([]byte(`{"id":"2","key":"b","ts":8.7}`)),
+		service.NewMessage([]byte(`{"id":"3","key":"a","ts":8.9}`)),
+		service.NewMessage([]byte(`{"id":"4","key":"b","ts":9.6}`)),
+		service.NewMessage([]byte(`{"id":"5","key":"a","ts":9.5}`)),
+	}, noopAck)
+	require.NoError(t, err)
+
+	// Window b ends last and therefore remains open.
+	currentTS = time.Unix(10, 550000000).UTC()
+
+	resBatch, _, err := w.ReadBatch(context.Background())
+	require.NoError(t, err)
+	assertBatchIDs(t, []string{"1", "3", "5"}, resBatch)
+
+	for _, msg := range resBatch {
+		v, exists := msg.MetaGet("window_key")
+		assert.True(t, exists)
+		assert.Equal(t, "a", v)
+
+		v, _ = msg.MetaGet("window_start_timestamp")
+		assert.Equal(t, "1970-01-01T00:00:08Z", v)
+
+		v, _ = msg.MetaGet("window_end_timestamp")
+		assert.Equal(t, "1970-01-01T00:00:09.5Z", v)
+	}
+
+	smallWaitCtx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	_, _, err = w.ReadBatch(smallWaitCtx)
+	done()
+	require.Error(t, err)
+
+	currentTS = time.Unix(10, 600000000).UTC()
+
+	resBatch, _, err = w.ReadBatch(context.Background())
+	require.NoError(t, err)
+	assertBatchIDs(t, []string{"2", "4"}, resBatch)
+
+	assert.Len(t, w.open, 0)
+}
+
+func TestSessionWindowGapExceeded(t *testing.T) {
+	currentTS := time.Unix(10, 0).UTC()
+	w := newTestSessionWindowBuffer(t, func() time.Time {
+		return currentTS
+	}, time.Second)
+
+	err := w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"1","key":"a","ts":5}`)),
+		service.NewMessage([]byte(`{"id":"2","key":"a","ts":5.5}`)),
+		service.NewMessage([]byte(`{"id":"3","key":"a","ts":9.5}`)),
+	}, noopAck)
+	require.NoError(t, err)
+
+	// The first window was closed by message 3 before being flushed.
+	resBatch, _, err := w.ReadBatch(context.Background())
+	require.NoError(t, err)
+	assertBatchIDs(t, []string{"1", "2"}, resBatch)
+
+	err = w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"4","key":"a","ts":10}`)),
+	}, noopAck)
+	require.NoError(t, err)
+
+	currentTS = time.Unix(11, 0).UTC()
+
+	resBatch, _, err = w.ReadBatch(context.Background())
+	require.NoError(t, err)
+	assertBatchIDs(t, []string{"3", "4"}, resBatch)
+}
+
+func TestSessionWindowWriteWakesReader(t *testing.T) {
+	w := newTestSessionWindowBuffer(t, func() time.Time {
+		return time.Unix(100, 0).UTC()
+	}, time.Second)
+
+	resChan := make(chan service.MessageBatch)
+	go func() {
+		resBatch, _, err := w.ReadBatch(context.Background())
+		assert.NoError(t, err)
+		resChan <- resBatch
+	}()
+
+	<-time.After(time.Millisecond * 50)
+	err := w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"1","key":"a","ts":10}`)),
+	}, noopAck)
+	require.NoError(t, err)
+
+	select {
+	case resBatch := <-resChan:
+		assertBatchIDs(t, []string{"1"}, resBatch)
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+func TestSessionWindowAcks(t *testing.T) {
+	currentTS := time.Unix(10, 0).UTC()
+	w := newTestSessionWindowBuffer(t, func() time.Time {
+		return currentTS
+	}, time.Second)
+
+	var firstErr, secondErr error
+	firstAcked, secondAcked := false, false
+
+	err := w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"1","key":"a","ts":5}`)),
+		service.NewMessage([]byte(`{"id":"2","key":"b","ts":5}`)),
+	}, func(ctx context.Context, err error) error {
+		firstAcked, firstErr = true, err
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"3","key":"c","ts":20}`)),
+	}, func(ctx context.Context, err error) error {
+		secondAcked, secondErr = true, err
+		return nil
+	})
+	require.NoError(t, err)
+
+	resBatch, aFn, err := w.ReadBatch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resBatch, 1)
+	require.NoError(t, aFn(context.Background(), nil))
+	assert.False(t, firstAcked)
+
+	resBatch, aFn, err = w.ReadBatch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resBatch, 1)
+	require.NoError(t, aFn(context.Background(), nil))
+	assert.True(t, firstAcked)
+	assert.NoError(t, firstErr)
+
+	w.EndOfInput()
+	_, _, err = w.ReadBatch(context.Background())
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+
+	assert.True(t, secondAcked)
+	assert.True(t, errors.Is(secondErr, errWindowClosed))
+}
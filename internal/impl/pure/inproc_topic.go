@@ -0,0 +1,200 @@
+package pure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// inprocTopicRedeliveryPeriod is the period to wait before redelivering a batch
+// that was rejected by a subscriber.
+var inprocTopicRedeliveryPeriod = time.Millisecond * 100
+
+// inprocTopicKey returns the key under which the router of an inproc topic is
+// registered as a shared client, such that all inputs and outputs of the
+// service that reference the same topic share a router.
+func inprocTopicKey(name string) string {
+	return "inproc_topic:" + name
+}
+
+// inprocTopicDelivery is a batch delivered to a single subscriber of a topic,
+// along with a func that must be called once the subscriber has either
+// processed or rejected the batch.
+type inprocTopicDelivery struct {
+	batch service.MessageBatch
+	ackFn service.AckFunc
+}
+
+// inprocSubscription is a single subscriber of a topic, each subscriber
+// receives its own copy of every batch published to the topic.
+type inprocSubscription struct {
+	deliveries chan *inprocTopicDelivery
+	buffered   bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (s *inprocSubscription) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+// send attempts to push a delivery onto the subscription, and returns false if
+// the subscription was closed or the context was cancelled before the
+// delivery could be made.
+func (s *inprocSubscription) send(ctx context.Context, d *inprocTopicDelivery) bool {
+	select {
+	case s.deliveries <- d:
+		return true
+	case <-s.closed:
+	case <-ctx.Done():
+	}
+	return false
+}
+
+// inprocTopic routes batches published to a named topic onto all current
+// subscribers of the topic. Acknowledgements are tracked independently for
+// each subscriber, where a subscriber that rejects a batch has the batch
+// redelivered without affecting the other subscribers.
+type inprocTopic struct {
+	mut  sync.RWMutex
+	subs map[*inprocSubscription]struct{}
+}
+
+func newInprocTopic() *inprocTopic {
+	return &inprocTopic{
+		subs: map[*inprocSubscription]struct{}{},
+	}
+}
+
+// subscribe adds a new subscriber to the topic. When bufferSize is greater
+// than zero the subscriber buffers up to that many batches, and batches are
+// acknowledged to the publisher as soon as they are buffered rather than once
+// they have been processed by the subscriber.
+func (t *inprocTopic) subscribe(bufferSize int) *inprocSubscription {
+	s := &inprocSubscription{
+		deliveries: make(chan *inprocTopicDelivery, bufferSize),
+		buffered:   bufferSize > 0,
+		closed:     make(chan struct{}),
+	}
+	t.mut.Lock()
+	t.subs[s] = struct{}{}
+	t.mut.Unlock()
+	return s
+}
+
+func (t *inprocTopic) unsubscribe(s *inprocSubscription) {
+	t.mut.Lock()
+	delete(t.subs, s)
+	t.mut.Unlock()
+	s.close()
+}
+
+func (t *inprocTopic) subscribers() []*inprocSubscription {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+
+	subs := make([]*inprocSubscription, 0, len(t.subs))
+	for s := range t.subs {
+		subs = append(subs, s)
+	}
+	return subs
+}
+
+// publish delivers a batch to all current subscribers of the topic and blocks
+// until each subscriber has acknowledged it, or has unsubscribed. Returns
+// service.ErrNotConnected if the topic has no subscribers.
+func (t *inprocTopic) publish(ctx context.Context, batch service.MessageBatch) error {
+	subs := t.subscribers()
+	if len(subs) == 0 {
+		return service.ErrNotConnected
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(subs))
+	for i, s := range subs {
+		wg.Add(1)
+		go func(i int, s *inprocSubscription) {
+			defer wg.Done()
+			errs[i] = t.deliver(ctx, s, batch.Copy())
+		}(i, s)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends a batch to a single subscriber until it is acknowledged.
+func (t *inprocTopic) deliver(ctx context.Context, s *inprocSubscription, batch service.MessageBatch) error {
+	if s.buffered {
+		var d *inprocTopicDelivery
+		d = &inprocTopicDelivery{
+			batch: batch,
+			ackFn: func(ctx context.Context, err error) error {
+				if err != nil {
+					// The publisher has already been acknowledged, so we
+					// redeliver the batch to the subscriber directly.
+					go s.send(context.Background(), d)
+				}
+				return nil
+			},
+		}
+		if !s.send(ctx, d) {
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	for {
+		resChan := make(chan error, 1)
+		d := &inprocTopicDelivery{
+			batch: batch,
+			ackFn: func(ctx context.Context, err error) error {
+				select {
+				case resChan <- err:
+				default:
+				}
+				return nil
+			},
+		}
+		if !s.send(ctx, d) {
+			return ctx.Err()
+		}
+
+		select {
+		case err := <-resChan:
+			if err == nil {
+				return nil
+			}
+		case <-s.closed:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// The subscriber rejected the batch, so we deliver it again after a
+		// short pause.
+		select {
+		case <-time.After(inprocTopicRedeliveryPeriod):
+		case <-s.closed:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close is a no-op as the topic holds no resources, and is implemented so that
+// topics can be registered as shared clients.
+func (t *inprocTopic) Close() error {
+	return nil
+}
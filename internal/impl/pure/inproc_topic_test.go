@@ -0,0 +1,154 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func newInprocTopicTestComponents(t *testing.T, res *service.Resources, bufferSizes ...int) (*inprocTopicOutput, []*inprocTopicInput) {
+	t.Helper()
+
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	var inputs []*inprocTopicInput
+	for _, size := range bufferSizes {
+		in := &inprocTopicInput{topicName: "foo", bufferSize: size, mgr: res}
+		require.NoError(t, in.Connect(tCtx))
+		inputs = append(inputs, in)
+	}
+
+	out := &inprocTopicOutput{topicName: "foo", mgr: res}
+	require.NoError(t, out.Connect(tCtx))
+	return out, inputs
+}
+
+func TestInprocTopicNoSubscribers(t *testing.T) {
+	out := &inprocTopicOutput{topicName: "foo", mgr: service.MockResources()}
+	require.Error(t, out.Connect(context.Background()))
+	assert.Equal(t, service.ErrNotConnected, out.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte("hello world")),
+	}))
+	require.NoError(t, out.Close(context.Background()))
+}
+
+func TestInprocTopicIndependentAcks(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	out, inputs := newInprocTopicTestComponents(t, service.MockResources(), 0, 0)
+
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- out.WriteBatch(tCtx, service.MessageBatch{
+			service.NewMessage([]byte("hello world")),
+		})
+	}()
+
+	batchA, ackA, err := inputs[0].ReadBatch(tCtx)
+	require.NoError(t, err)
+	require.Len(t, batchA, 1)
+
+	batchB, ackB, err := inputs[1].ReadBatch(tCtx)
+	require.NoError(t, err)
+	require.Len(t, batchB, 1)
+
+	// Each subscriber receives its own copy.
+	batchA[0].SetBytes([]byte("changed"))
+	bBytes, err := batchB[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(bBytes))
+
+	require.NoError(t, ackA(tCtx, nil))
+	require.NoError(t, ackB(tCtx, errors.New("nope")))
+
+	// Only the subscriber that rejected the batch receives it again.
+	batchB, ackB, err = inputs[1].ReadBatch(tCtx)
+	require.NoError(t, err)
+	require.Len(t, batchB, 1)
+
+	select {
+	case err := <-writeErr:
+		t.Fatalf("write completed before all subscribers acknowledged: %v", err)
+	default:
+	}
+
+	shortCtx, shortDone := context.WithTimeout(tCtx, time.Millisecond*50)
+	_, _, err = inputs[0].ReadBatch(shortCtx)
+	shortDone()
+	require.Error(t, err)
+
+	require.NoError(t, ackB(tCtx, nil))
+	require.NoError(t, <-writeErr)
+
+	for _, in := range inputs {
+		require.NoError(t, in.Close(tCtx))
+	}
+	require.NoError(t, out.Close(tCtx))
+}
+
+func TestInprocTopicBuffered(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	out, inputs := newInprocTopicTestComponents(t, service.MockResources(), 2)
+
+	// Writes complete without the subscriber reading until its buffer is full.
+	for _, content := range []string{"first", "second"} {
+		require.NoError(t, out.WriteBatch(tCtx, service.MessageBatch{
+			service.NewMessage([]byte(content)),
+		}))
+	}
+
+	shortCtx, shortDone := context.WithTimeout(tCtx, time.Millisecond*50)
+	require.Error(t, out.WriteBatch(shortCtx, service.MessageBatch{
+		service.NewMessage([]byte("third")),
+	}))
+	shortDone()
+
+	for _, exp := range []string{"first", "second"} {
+		batch, ackFn, err := inputs[0].ReadBatch(tCtx)
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		mBytes, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(mBytes))
+		require.NoError(t, ackFn(tCtx, nil))
+	}
+
+	require.NoError(t, inputs[0].Close(tCtx))
+	require.NoError(t, out.Close(tCtx))
+}
+
+func TestInprocTopicUnsubscribe(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	out, inputs := newInprocTopicTestComponents(t, service.MockResources(), 0, 0)
+
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- out.WriteBatch(tCtx, service.MessageBatch{
+			service.NewMessage([]byte("hello world")),
+		})
+	}()
+
+	_, ackA, err := inputs[0].ReadBatch(tCtx)
+	require.NoError(t, err)
+	require.NoError(t, ackA(tCtx, nil))
+
+	// A subscriber leaving the topic no longer blocks the publisher.
+	require.NoError(t, inputs[1].Close(tCtx))
+	require.NoError(t, <-writeErr)
+
+	require.NoError(t, inputs[0].Close(tCtx))
+	require.NoError(t, out.Close(tCtx))
+}
@@ -0,0 +1,62 @@
+package pure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestBrokerInputLabelMetadata(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	newGenConf := func(label, content string) input.Config {
+		conf := input.NewConfig()
+		conf.Type = "generate"
+		conf.Label = label
+		conf.Generate.Mapping = `root = "` + content + `"`
+		conf.Generate.Interval = ""
+		conf.Generate.Count = 1
+		return conf
+	}
+
+	conf := input.NewConfig()
+	conf.Type = "broker"
+	conf.Broker.LabelMetadata = true
+	conf.Broker.Inputs = append(conf.Broker.Inputs,
+		newGenConf("foo", "from foo"),
+		newGenConf("bar", "from bar"),
+		newGenConf("", "from unlabelled"),
+	)
+
+	b, err := newBrokerInput(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	labels := map[string]string{}
+	for len(labels) < 3 {
+		select {
+		case tran, open := <-b.TransactionChan():
+			require.True(t, open)
+			part := tran.Payload.Get(0)
+			labels[string(part.Get())] = part.MetaGet("input_label")
+			require.NoError(t, tran.Ack(ctx, nil))
+		case <-ctx.Done():
+			t.Fatal("timed out")
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"from foo":        "foo",
+		"from bar":        "bar",
+		"from unlabelled": "",
+	}, labels)
+
+	b.CloseAsync()
+	require.NoError(t, b.WaitForClose(time.Second*5))
+}
@@ -0,0 +1,130 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func inprocTopicInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		// Stable(). TODO
+		Version("4.2.0").
+		Categories("Utility").
+		Summary("Subscribes to a named topic that other streams within the same Benthos process publish to with an [`inproc_topic` output](/docs/components/outputs/inproc_topic).").
+		Description(`
+This allows you to compose isolated streams whilst running Benthos in [streams mode](/docs/guides/streams_mode/about) without a real message broker. Unlike the ` + "[`inproc` input](/docs/components/inputs/inproc)" + `, where messages are dispatched to a single connected input, each input subscribed to a topic receives its own copy of every message published to the topic.
+
+Messages are acknowledged independently by each subscriber, where a message that a subscriber fails to process is redelivered to that subscriber only. By default a message published to a topic is not acknowledged until all subscribers have processed it, and therefore a slow subscriber applies back pressure to the publisher.
+
+### Buffering
+
+When a ` + "`buffer_size`" + ` greater than zero is set this input buffers up to that number of message batches, and messages are acknowledged to the publisher as soon as they are buffered. This allows a slow subscriber to lag behind the publisher without blocking it, at the cost of losing any buffered messages that have not yet been processed when the service is shut down.`).
+		Field(service.NewStringField("topic").
+			Description("The name of the topic to subscribe to.")).
+		Field(service.NewIntField("buffer_size").
+			Description("The maximum number of message batches to buffer for this subscriber, when zero messages are not acknowledged to the publisher until they have been processed.").
+			Default(0).
+			Advanced())
+}
+
+func init() {
+	err := service.RegisterBatchInput(
+		"inproc_topic", inprocTopicInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return newInprocTopicInputFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type inprocTopicInput struct {
+	topicName  string
+	bufferSize int
+	mgr        *service.Resources
+
+	mut     sync.Mutex
+	topic   *inprocTopic
+	sub     *inprocSubscription
+	release func() error
+}
+
+func newInprocTopicInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*inprocTopicInput, error) {
+	topicName, err := conf.FieldString("topic")
+	if err != nil {
+		return nil, err
+	}
+	if topicName == "" {
+		return nil, errors.New("a topic must be specified")
+	}
+	bufferSize, err := conf.FieldInt("buffer_size")
+	if err != nil {
+		return nil, err
+	}
+	if bufferSize < 0 {
+		return nil, errors.New("buffer_size must not be negative")
+	}
+	return &inprocTopicInput{
+		topicName:  topicName,
+		bufferSize: bufferSize,
+		mgr:        mgr,
+	}, nil
+}
+
+func (i *inprocTopicInput) Connect(ctx context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.sub != nil {
+		return nil
+	}
+
+	client, release, err := i.mgr.AcquireSharedClient(inprocTopicKey(i.topicName), func() (io.Closer, error) {
+		return newInprocTopic(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.topic, i.release = client.(*inprocTopic), release
+	i.sub = i.topic.subscribe(i.bufferSize)
+	i.mgr.Logger().Infof("Subscribed to inproc topic: %v", i.topicName)
+	return nil
+}
+
+func (i *inprocTopicInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	i.mut.Lock()
+	sub := i.sub
+	i.mut.Unlock()
+
+	if sub == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case d := <-sub.deliveries:
+		return d.batch, d.ackFn, nil
+	case <-sub.closed:
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (i *inprocTopicInput) Close(ctx context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.sub == nil {
+		return nil
+	}
+	i.topic.unsubscribe(i.sub)
+	i.topic, i.sub = nil, nil
+	return i.release()
+}
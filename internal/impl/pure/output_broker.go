@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
@@ -84,13 +85,64 @@ potentially disproportionate message allocations to those outputs. Each message
 is sent to a single output, which is determined by allowing outputs to claim
 messages as soon as they are able to process them. This results in certain
 faster outputs potentially processing more messages at the cost of slower
-outputs.`,
+outputs.
+
+### ` + "`weighted_round_robin`" + `
+
+Similar to the round robin pattern except each output is assigned messages in
+proportion to its weight, which is set by the field ` + "`weights`" + `. For
+example, with weights of ` + "`[ 3, 1 ]`" + ` the first output is sent three
+messages for every message sent to the second. Messages of each output are
+interleaved rather than being sent in bursts.
+
+### ` + "`least_pending`" + `
+
+Each message is sent to the single output with the fewest messages that have
+been sent to it but not yet acknowledged, which allows outputs with a greater
+capacity to process more messages. When outputs are tied the output that is
+listed first is chosen.
+
+### ` + "`priority`" + `
+
+With the priority pattern each message is sent to a single output, which is the
+first output in the list that is available. When an output fails to send a
+message the message is passed to the next output in the list, in the same way
+as the ` + "[`fallback` output](/docs/components/outputs/fallback)" + `, and the
+failed output becomes unavailable.
+
+The health of each output is also checked every ` + "`priority.check_interval`" + `,
+and outputs that are not connected become unavailable. Once an unavailable
+output has remained healthy for the ` + "`priority.cooldown`" + ` period it
+becomes available again, at which point traffic automatically fails back to it.
+
+If all outputs are unavailable then messages are sent to the first output.
+
+### ` + "`sharded`" + `
+
+With the sharded pattern each message is sent to a single output, which is
+chosen by hashing the key obtained by resolving ` + "`sharded.key`" + ` for the
+message. Messages that share a key are therefore always sent to the same output,
+which preserves their ordering whilst allowing messages with different keys to
+be written in parallel, similar to the partitioning of a Kafka topic.
+
+Messages of a batch that resolve to different outputs are split into smaller
+batches, and the batch is only acknowledged once all outputs have processed
+their portion of it. Changing the number of outputs (or copies) changes the
+output that each key is assigned to.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldInt("copies", "The number of copies of each configured output to spawn.").Advanced().HasDefault(1),
 			docs.FieldString("pattern", "The brokering pattern to use.").HasOptions(
-				"fan_out", "fan_out_sequential", "round_robin", "greedy",
+				"fan_out", "fan_out_sequential", "round_robin", "weighted_round_robin", "least_pending", "greedy", "priority", "sharded",
 			).HasDefault("fan_out"),
 			docs.FieldOutput("outputs", "A list of child outputs to broker.").Array().HasDefault([]interface{}{}),
+			docs.FieldInt("weights", "A list of weights for the outputs of the [`weighted_round_robin`](#weighted_round_robin) pattern, where each weight corresponds to the output at the same index. When empty all outputs have a weight of 1.", []int{3, 1}).Array().HasDefault([]interface{}{}).Advanced().AtVersion("4.2.0"),
+			docs.FieldObject("priority", "Configures the [`priority`](#priority) pattern.").WithChildren(
+				docs.FieldString("check_interval", "The period between each health check of the outputs.").HasDefault("1s"),
+				docs.FieldString("cooldown", "The period for which an output must remain healthy after failing before messages are sent to it again.").HasDefault("30s"),
+			).Advanced().AtVersion("4.2.0"),
+			docs.FieldObject("sharded", "Configures the [`sharded`](#sharded) pattern.").WithChildren(
+				docs.FieldString("key", "An interpolated string that resolves to the key used to choose the output of each message.", `${! meta("kafka_key") }`, `${! json("user.id") }`).IsInterpolated().HasDefault(""),
+			).Advanced().AtVersion("4.2.0"),
 			policy.FieldSpec(),
 		),
 		Categories: []string{
@@ -104,6 +156,51 @@ outputs.`,
 
 //------------------------------------------------------------------------------
 
+// brokerWeights returns the weight of each output of a broker, including
+// copies.
+func brokerWeights(conf output.BrokerConfig) ([]int, error) {
+	if len(conf.Weights) > 0 && len(conf.Weights) != len(conf.Outputs) {
+		return nil, fmt.Errorf("the number of weights (%v) must match the number of outputs (%v)", len(conf.Weights), len(conf.Outputs))
+	}
+	weights := make([]int, 0, len(conf.Outputs)*conf.Copies)
+	for j := 0; j < conf.Copies; j++ {
+		for i := range conf.Outputs {
+			w := 1
+			if len(conf.Weights) > 0 {
+				w = conf.Weights[i]
+			}
+			weights = append(weights, w)
+		}
+	}
+	return weights, nil
+}
+
+func newPriorityOutputBrokerFromConfig(conf output.BrokerPriorityConfig, outputs []output.Streamed, mgr bundle.NewManagement) (*priorityOutputBroker, error) {
+	checkInterval, err := time.ParseDuration(conf.CheckInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse priority check_interval: %w", err)
+	}
+	if checkInterval <= 0 {
+		return nil, fmt.Errorf("priority check_interval must be greater than zero, got %v", conf.CheckInterval)
+	}
+	cooldown, err := time.ParseDuration(conf.Cooldown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse priority cooldown: %w", err)
+	}
+	return newPriorityOutputBroker(outputs, checkInterval, cooldown, mgr.Logger())
+}
+
+func newShardedOutputBrokerFromConfig(conf output.BrokerShardedConfig, outputs []output.Streamed, mgr bundle.NewManagement) (*shardedOutputBroker, error) {
+	if conf.Key == "" {
+		return nil, errors.New("a sharded key must be specified for the sharded pattern")
+	}
+	key, err := mgr.BloblEnvironment().NewField(conf.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sharded key expression: %v", err)
+	}
+	return newShardedOutputBroker(outputs, key)
+}
+
 func newBroker(conf output.Config, mgr bundle.NewManagement, pipelines ...processor.PipelineConstructorFunc) (output.Streamed, error) {
 	pipelines = processors.AppendFromConfig(conf, mgr, pipelines...)
 
@@ -128,8 +225,10 @@ func newBroker(conf output.Config, mgr bundle.NewManagement, pipelines ...proces
 	outputs := make([]output.Streamed, lOutputs)
 
 	_, isThreaded := map[string]struct{}{
-		"round_robin": {},
-		"greedy":      {},
+		"round_robin":          {},
+		"weighted_round_robin": {},
+		"least_pending":        {},
+		"greedy":               {},
 	}[conf.Broker.Pattern]
 
 	_, isRetryWrapped := map[string]struct{}{
@@ -166,8 +265,19 @@ func newBroker(conf output.Config, mgr bundle.NewManagement, pipelines ...proces
 		b, err = newFanOutSequentialOutputBroker(outputs)
 	case "round_robin":
 		b, err = newRoundRobinOutputBroker(outputs)
+	case "weighted_round_robin":
+		var weights []int
+		if weights, err = brokerWeights(conf.Broker); err == nil {
+			b, err = newWeightedRoundRobinOutputBroker(outputs, weights)
+		}
+	case "least_pending":
+		b, err = newLeastPendingOutputBroker(outputs)
 	case "greedy":
 		b, err = newGreedyOutputBroker(outputs)
+	case "priority":
+		b, err = newPriorityOutputBrokerFromConfig(conf.Broker.Priority, outputs, mgr)
+	case "sharded":
+		b, err = newShardedOutputBrokerFromConfig(conf.Broker.Sharded, outputs, mgr)
 	default:
 		return nil, fmt.Errorf("broker pattern was not recognised: %v", conf.Broker.Pattern)
 	}
@@ -0,0 +1,120 @@
+package pure
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+// leastPendingOutputBroker assigns each message to the output with the fewest
+// messages that have been sent to it but not yet acknowledged.
+type leastPendingOutputBroker struct {
+	transactions <-chan message.Transaction
+
+	outputTSChans []chan message.Transaction
+	outputs       []output.Streamed
+	pending       []int64
+
+	shutSig *shutdown.Signaller
+}
+
+func newLeastPendingOutputBroker(outputs []output.Streamed) (*leastPendingOutputBroker, error) {
+	o := &leastPendingOutputBroker{
+		outputs: outputs,
+		pending: make([]int64, len(outputs)),
+		shutSig: shutdown.NewSignaller(),
+	}
+	o.outputTSChans = make([]chan message.Transaction, len(o.outputs))
+	for i := range o.outputTSChans {
+		o.outputTSChans[i] = make(chan message.Transaction)
+		if err := o.outputs[i].Consume(o.outputTSChans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *leastPendingOutputBroker) Consume(ts <-chan message.Transaction) error {
+	if o.transactions != nil {
+		return component.ErrAlreadyStarted
+	}
+	o.transactions = ts
+
+	go o.loop()
+	return nil
+}
+
+func (o *leastPendingOutputBroker) Connected() bool {
+	for _, out := range o.outputs {
+		if !out.Connected() {
+			return false
+		}
+	}
+	return true
+}
+
+// next returns the index of the output with the fewest pending messages,
+// favouring outputs earlier in the list when there is a tie.
+func (o *leastPendingOutputBroker) next() int {
+	selected, selectedPending := 0, atomic.LoadInt64(&o.pending[0])
+	for i := 1; i < len(o.pending); i++ {
+		if p := atomic.LoadInt64(&o.pending[i]); p < selectedPending {
+			selected, selectedPending = i, p
+		}
+	}
+	return selected
+}
+
+func (o *leastPendingOutputBroker) loop() {
+	defer func() {
+		for _, c := range o.outputTSChans {
+			close(c)
+		}
+		closeAllOutputs(o.outputs)
+		o.shutSig.ShutdownComplete()
+	}()
+
+	for {
+		var ts message.Transaction
+		var open bool
+		select {
+		case ts, open = <-o.transactions:
+			if !open {
+				return
+			}
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+
+		i := o.next()
+		atomic.AddInt64(&o.pending[i], 1)
+		tran := message.NewTransactionFunc(ts.Payload, func(ctx context.Context, err error) error {
+			atomic.AddInt64(&o.pending[i], -1)
+			return ts.Ack(ctx, err)
+		})
+
+		select {
+		case o.outputTSChans[i] <- tran:
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+	}
+}
+
+func (o *leastPendingOutputBroker) CloseAsync() {
+	o.shutSig.CloseAtLeisure()
+}
+
+func (o *leastPendingOutputBroker) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-o.shutSig.HasClosedChan():
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package pure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+// priorityOutputBroker sends each message to the highest priority output that
+// is available, where an output becomes unavailable when it fails to send a
+// message or fails a health check, and only becomes available again once it
+// has remained healthy for a cool-down period.
+type priorityOutputBroker struct {
+	transactions <-chan message.Transaction
+
+	outputTSChans []chan message.Transaction
+	outputs       []output.Streamed
+
+	checkInterval time.Duration
+	cooldown      time.Duration
+
+	failedMut   sync.Mutex
+	lastFailure []time.Time
+	current     int
+
+	log     log.Modular
+	shutSig *shutdown.Signaller
+}
+
+func newPriorityOutputBroker(outputs []output.Streamed, checkInterval, cooldown time.Duration, log log.Modular) (*priorityOutputBroker, error) {
+	o := &priorityOutputBroker{
+		outputs:       outputs,
+		checkInterval: checkInterval,
+		cooldown:      cooldown,
+		lastFailure:   make([]time.Time, len(outputs)),
+		log:           log,
+		shutSig:       shutdown.NewSignaller(),
+	}
+	o.outputTSChans = make([]chan message.Transaction, len(o.outputs))
+	for i := range o.outputTSChans {
+		o.outputTSChans[i] = make(chan message.Transaction)
+		if err := o.outputs[i].Consume(o.outputTSChans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *priorityOutputBroker) Consume(ts <-chan message.Transaction) error {
+	if o.transactions != nil {
+		return component.ErrAlreadyStarted
+	}
+	o.transactions = ts
+
+	go o.loop()
+	return nil
+}
+
+// Connected returns true when any of the outputs are connected, as messages
+// are routed away from outputs that are not.
+func (o *priorityOutputBroker) Connected() bool {
+	for _, out := range o.outputs {
+		if out.Connected() {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+func (o *priorityOutputBroker) markFailed(i int) {
+	o.failedMut.Lock()
+	o.lastFailure[i] = time.Now()
+	o.failedMut.Unlock()
+}
+
+// probe checks the health of each output and marks those that are not
+// connected as having failed.
+func (o *priorityOutputBroker) probe() {
+	for i, out := range o.outputs {
+		if !out.Connected() {
+			o.markFailed(i)
+		}
+	}
+}
+
+// selectOutput returns the index of the highest priority output that has not
+// failed within the cool-down period, or the first output when all of them
+// have.
+func (o *priorityOutputBroker) selectOutput() int {
+	o.failedMut.Lock()
+	defer o.failedMut.Unlock()
+
+	selected := 0
+	for i, t := range o.lastFailure {
+		if t.IsZero() || time.Since(t) >= o.cooldown {
+			selected = i
+			break
+		}
+	}
+	if selected != o.current {
+		if selected < o.current {
+			o.log.Infof("Failing back to broker output %v\n", selected)
+		} else {
+			o.log.Warnf("Failing over to broker output %v\n", selected)
+		}
+		o.current = selected
+	}
+	return selected
+}
+
+func (o *priorityOutputBroker) loop() {
+	defer func() {
+		for _, c := range o.outputTSChans {
+			close(c)
+		}
+		closeAllOutputs(o.outputs)
+		o.shutSig.ShutdownComplete()
+	}()
+
+	probeTicker := time.NewTicker(o.checkInterval)
+	defer probeTicker.Stop()
+
+	for {
+		var open bool
+		var tran message.Transaction
+
+		select {
+		case tran, open = <-o.transactions:
+			if !open {
+				return
+			}
+		case <-probeTicker.C:
+			o.probe()
+			continue
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+
+		// Messages that fail are passed to each lower priority output in turn,
+		// in the same way as the fallback output.
+		i := o.selectOutput()
+		var ackFn func(ctx context.Context, err error) error
+		ackFn = func(ctx context.Context, err error) error {
+			if err == nil {
+				return tran.Ack(ctx, nil)
+			}
+			o.markFailed(i)
+			if i++; len(o.outputTSChans) <= i {
+				return tran.Ack(ctx, err)
+			}
+			select {
+			case o.outputTSChans[i] <- message.NewTransactionFunc(tran.Payload, ackFn):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		}
+
+		select {
+		case o.outputTSChans[i] <- message.NewTransactionFunc(tran.Payload, ackFn):
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+	}
+}
+
+func (o *priorityOutputBroker) CloseAsync() {
+	o.shutSig.CloseAtLeisure()
+}
+
+func (o *priorityOutputBroker) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-o.shutSig.HasClosedChan():
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
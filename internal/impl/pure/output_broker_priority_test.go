@@ -0,0 +1,131 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+var _ output.Streamed = &priorityOutputBroker{}
+
+type mockHealthCheckedOutput struct {
+	mock.OutputChanneled
+	disconnected int32
+}
+
+func (m *mockHealthCheckedOutput) Connected() bool {
+	return atomic.LoadInt32(&m.disconnected) == 0
+}
+
+func TestPriorityBrokerFailover(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	mockOutputs := []*mockHealthCheckedOutput{{}, {}}
+	outputs := []output.Streamed{}
+	for _, o := range mockOutputs {
+		outputs = append(outputs, o)
+	}
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error)
+
+	oTM, err := newPriorityOutputBroker(outputs, time.Millisecond*10, time.Millisecond*200, log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, oTM.Consume(readChan))
+
+	send := func(content string) {
+		t.Helper()
+		select {
+		case readChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte(content)}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker send")
+		}
+	}
+
+	receive := func(index int, content string, ackErr error) {
+		t.Helper()
+		var ts message.Transaction
+		select {
+		case ts = <-mockOutputs[index].TChan:
+		case ts = <-mockOutputs[1-index].TChan:
+			t.Fatalf("Received message on output %v instead of %v", 1-index, index)
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker propagate")
+		}
+		assert.Equal(t, content, string(ts.Payload.Get(0).Get()))
+
+		// Failed messages are passed to the next output within the ack, and
+		// therefore the ack must not block the test.
+		go func() {
+			assert.NoError(t, ts.Ack(tCtx, ackErr))
+		}()
+	}
+
+	result := func() error {
+		t.Helper()
+		select {
+		case res := <-resChan:
+			return res
+		case <-time.After(time.Second):
+			t.Fatal("Timed out responding to broker")
+		}
+		return nil
+	}
+
+	// Messages go to the highest priority output.
+	send("first")
+	receive(0, "first", nil)
+	require.NoError(t, result())
+
+	// A failed message is passed to the next output.
+	send("second")
+	receive(0, "second", errors.New("nope"))
+	receive(1, "second", nil)
+	require.NoError(t, result())
+
+	// The failed output is skipped until its cool-down has passed.
+	send("third")
+	receive(1, "third", nil)
+	require.NoError(t, result())
+
+	<-time.After(time.Millisecond * 300)
+
+	send("fourth")
+	receive(0, "fourth", nil)
+	require.NoError(t, result())
+
+	// An output that fails a health check is also skipped.
+	atomic.StoreInt32(&mockOutputs[0].disconnected, 1)
+	<-time.After(time.Millisecond * 50)
+
+	send("fifth")
+	receive(1, "fifth", nil)
+	require.NoError(t, result())
+
+	atomic.StoreInt32(&mockOutputs[0].disconnected, 0)
+	<-time.After(time.Millisecond * 300)
+
+	send("sixth")
+	receive(0, "sixth", nil)
+	require.NoError(t, result())
+
+	// A message that fails on all outputs is rejected.
+	send("seventh")
+	receive(0, "seventh", errors.New("nope"))
+	receive(1, "seventh", errors.New("nope"))
+	require.Error(t, result())
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*5))
+}
@@ -0,0 +1,208 @@
+package pure
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OneOfOne/xxhash"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+// shardedOutputBroker assigns each message to a single output by hashing a key
+// obtained from the message, such that all messages sharing a key are sent to
+// the same output.
+type shardedOutputBroker struct {
+	transactions <-chan message.Transaction
+
+	outputTSChans []chan message.Transaction
+	outputs       []output.Streamed
+
+	key *field.Expression
+
+	shutSig *shutdown.Signaller
+}
+
+func newShardedOutputBroker(outputs []output.Streamed, key *field.Expression) (*shardedOutputBroker, error) {
+	o := &shardedOutputBroker{
+		outputs: outputs,
+		key:     key,
+		shutSig: shutdown.NewSignaller(),
+	}
+	o.outputTSChans = make([]chan message.Transaction, len(o.outputs))
+	for i := range o.outputTSChans {
+		o.outputTSChans[i] = make(chan message.Transaction)
+		if err := o.outputs[i].Consume(o.outputTSChans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *shardedOutputBroker) Consume(ts <-chan message.Transaction) error {
+	if o.transactions != nil {
+		return component.ErrAlreadyStarted
+	}
+	o.transactions = ts
+
+	go o.loop()
+	return nil
+}
+
+func (o *shardedOutputBroker) Connected() bool {
+	for _, out := range o.outputs {
+		if !out.Connected() {
+			return false
+		}
+	}
+	return true
+}
+
+// shard returns the index of the output that a message of a batch is assigned
+// to.
+func (o *shardedOutputBroker) shard(index int, msg *message.Batch) int {
+	return int(xxhash.ChecksumString64(o.key.String(index, msg)) % uint64(len(o.outputs)))
+}
+
+// dispatch sends each group of parts to its respective output, and calls ackFn
+// once all outputs have acknowledged their parts. Returns false if the broker
+// was closed before all parts were dispatched.
+func (o *shardedOutputBroker) dispatch(
+	group *message.SortGroup,
+	sourceMessage *message.Batch,
+	outputTargets [][]*message.Part,
+	ackFn func(context.Context, error) error,
+) bool {
+	var errLock sync.Mutex
+	var generalErr error
+	var batchErr *batch.Error
+	setErrForPart := func(part *message.Part, err error) {
+		errLock.Lock()
+		defer errLock.Unlock()
+
+		index := group.GetIndex(part)
+		if index == -1 {
+			generalErr = err
+			return
+		}
+		if batchErr == nil {
+			batchErr = batch.NewError(sourceMessage, err)
+		}
+		batchErr.Failed(index, err)
+	}
+	getErr := func() error {
+		errLock.Lock()
+		defer errLock.Unlock()
+		if batchErr != nil {
+			return batchErr
+		}
+		return generalErr
+	}
+
+	var pendingResponses int64
+	for _, parts := range outputTargets {
+		if len(parts) > 0 {
+			pendingResponses++
+		}
+	}
+
+	for target, parts := range outputTargets {
+		if len(parts) == 0 {
+			continue
+		}
+
+		msgCopy := message.QuickBatch(nil)
+		msgCopy.SetAll(parts)
+
+		select {
+		case o.outputTSChans[target] <- message.NewTransactionFunc(msgCopy, func(ctx context.Context, err error) error {
+			if err != nil {
+				if bErr, ok := err.(*batch.Error); ok {
+					bErr.WalkParts(func(i int, p *message.Part, e error) bool {
+						if e != nil {
+							setErrForPart(p, e)
+						}
+						return true
+					})
+				} else {
+					_ = msgCopy.Iter(func(i int, p *message.Part) error {
+						setErrForPart(p, err)
+						return nil
+					})
+				}
+			}
+			if atomic.AddInt64(&pendingResponses, -1) <= 0 {
+				return ackFn(ctx, getErr())
+			}
+			return nil
+		}):
+		case <-o.shutSig.CloseAtLeisureChan():
+			return false
+		}
+	}
+	return true
+}
+
+func (o *shardedOutputBroker) loop() {
+	defer func() {
+		for _, c := range o.outputTSChans {
+			close(c)
+		}
+		closeAllOutputs(o.outputs)
+		o.shutSig.ShutdownComplete()
+	}()
+
+	// The slice of targets is reused across transactions, whereas the parts
+	// of each target are retained by the dispatched batch and are therefore
+	// allocated for each transaction.
+	outputTargets := make([][]*message.Part, len(o.outputs))
+
+	for {
+		var ts message.Transaction
+		var open bool
+		select {
+		case ts, open = <-o.transactions:
+			if !open {
+				return
+			}
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+
+		group, trackedMsg := message.NewSortGroup(ts.Payload)
+
+		_ = trackedMsg.Iter(func(i int, p *message.Part) error {
+			target := o.shard(i, trackedMsg)
+			outputTargets[target] = append(outputTargets[target], p)
+			return nil
+		})
+
+		dispatched := o.dispatch(group, trackedMsg, outputTargets, ts.Ack)
+		for i := range outputTargets {
+			outputTargets[i] = nil
+		}
+		if !dispatched {
+			return
+		}
+	}
+}
+
+func (o *shardedOutputBroker) CloseAsync() {
+	o.shutSig.CloseAtLeisure()
+}
+
+func (o *shardedOutputBroker) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-o.shutSig.HasClosedChan():
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+var _ output.Streamed = &shardedOutputBroker{}
+
+func newShardedTestBroker(t *testing.T, key string, mockOutputs []*mock.OutputChanneled) *shardedOutputBroker {
+	t.Helper()
+
+	outputs := []output.Streamed{}
+	for _, o := range mockOutputs {
+		outputs = append(outputs, o)
+	}
+
+	keyExpr, err := bloblang.GlobalEnvironment().NewField(key)
+	require.NoError(t, err)
+
+	oTM, err := newShardedOutputBroker(outputs, keyExpr)
+	require.NoError(t, err)
+	return oTM
+}
+
+func TestShardedBrokerConsistentKeys(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	mockOutputs := []*mock.OutputChanneled{{}, {}, {}}
+	oTM := newShardedTestBroker(t, `${! json("id") }`, mockOutputs)
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error)
+	require.NoError(t, oTM.Consume(readChan))
+
+	receive := func() (int, message.Transaction) {
+		t.Helper()
+		select {
+		case ts := <-mockOutputs[0].TChan:
+			return 0, ts
+		case ts := <-mockOutputs[1].TChan:
+			return 1, ts
+		case ts := <-mockOutputs[2].TChan:
+			return 2, ts
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker propagate")
+		}
+		return -1, message.Transaction{}
+	}
+
+	keyTargets := map[string]int{}
+	for i := 0; i < 30; i++ {
+		key := fmt.Sprintf("key%v", i%5)
+		content := fmt.Sprintf(`{"id":"%v","n":%v}`, key, i)
+
+		select {
+		case readChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte(content)}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker send")
+		}
+
+		index, ts := receive()
+		require.Equal(t, 1, ts.Payload.Len())
+		assert.Equal(t, content, string(ts.Payload.Get(0).Get()))
+
+		if prev, exists := keyTargets[key]; exists {
+			assert.Equal(t, prev, index, key)
+		}
+		keyTargets[key] = index
+
+		go func() {
+			require.NoError(t, ts.Ack(tCtx, nil))
+		}()
+		select {
+		case err := <-resChan:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker response")
+		}
+	}
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*5))
+}
+
+func TestShardedBrokerSplitBatch(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	mockOutputs := []*mock.OutputChanneled{{}, {}}
+	oTM := newShardedTestBroker(t, `${! content() }`, mockOutputs)
+
+	a := oTM.shard(0, message.QuickBatch([][]byte{[]byte("a")}))
+	b := -1
+	var bKey string
+	for i := 0; i < 100; i++ {
+		bKey = fmt.Sprintf("b%v", i)
+		if b = oTM.shard(0, message.QuickBatch([][]byte{[]byte(bKey)})); b != a {
+			break
+		}
+	}
+	require.NotEqual(t, a, b)
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error)
+	require.NoError(t, oTM.Consume(readChan))
+
+	select {
+	case readChan <- message.NewTransaction(message.QuickBatch([][]byte{
+		[]byte("a"), []byte(bKey), []byte("a"),
+	}), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker send")
+	}
+
+	var aTran, bTran message.Transaction
+	for i := 0; i < 2; i++ {
+		select {
+		case aTran = <-mockOutputs[a].TChan:
+		case bTran = <-mockOutputs[b].TChan:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker propagate")
+		}
+	}
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("a")}, message.GetAllBytes(aTran.Payload))
+	assert.Equal(t, [][]byte{[]byte(bKey)}, message.GetAllBytes(bTran.Payload))
+
+	go func() {
+		require.NoError(t, aTran.Ack(tCtx, nil))
+		require.NoError(t, bTran.Ack(tCtx, errors.New("nope")))
+	}()
+
+	select {
+	case err := <-resChan:
+		require.Error(t, err)
+
+		bErr, ok := err.(*batch.Error)
+		require.True(t, ok)
+
+		failed := map[int]struct{}{}
+		bErr.WalkParts(func(i int, p *message.Part, e error) bool {
+			if e != nil {
+				failed[i] = struct{}{}
+			}
+			return true
+		})
+		assert.Equal(t, map[int]struct{}{1: {}}, failed)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker response")
+	}
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*5))
+}
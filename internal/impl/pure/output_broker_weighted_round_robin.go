@@ -0,0 +1,131 @@
+package pure
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+// weightedRoundRobinOutputBroker assigns each message to a single output in
+// proportion to the weights of the outputs, using a smooth weighted round robin
+// so that messages of an output are interleaved with those of the others
+// rather than being sent in bursts.
+type weightedRoundRobinOutputBroker struct {
+	transactions <-chan message.Transaction
+
+	outputTSChans []chan message.Transaction
+	outputs       []output.Streamed
+
+	weights     []int
+	current     []int
+	totalWeight int
+
+	shutSig *shutdown.Signaller
+}
+
+func newWeightedRoundRobinOutputBroker(outputs []output.Streamed, weights []int) (*weightedRoundRobinOutputBroker, error) {
+	if len(weights) != len(outputs) {
+		return nil, fmt.Errorf("expected %v weights, got %v", len(outputs), len(weights))
+	}
+	o := &weightedRoundRobinOutputBroker{
+		outputs: outputs,
+		weights: weights,
+		current: make([]int, len(weights)),
+		shutSig: shutdown.NewSignaller(),
+	}
+	for _, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("weights must not be negative, got %v", w)
+		}
+		o.totalWeight += w
+	}
+	if o.totalWeight == 0 {
+		return nil, errors.New("at least one output must have a weight greater than zero")
+	}
+	o.outputTSChans = make([]chan message.Transaction, len(o.outputs))
+	for i := range o.outputTSChans {
+		o.outputTSChans[i] = make(chan message.Transaction)
+		if err := o.outputs[i].Consume(o.outputTSChans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *weightedRoundRobinOutputBroker) Consume(ts <-chan message.Transaction) error {
+	if o.transactions != nil {
+		return component.ErrAlreadyStarted
+	}
+	o.transactions = ts
+
+	go o.loop()
+	return nil
+}
+
+func (o *weightedRoundRobinOutputBroker) Connected() bool {
+	for _, out := range o.outputs {
+		if !out.Connected() {
+			return false
+		}
+	}
+	return true
+}
+
+// next returns the index of the output to send the next message to.
+func (o *weightedRoundRobinOutputBroker) next() int {
+	selected := 0
+	for i, w := range o.weights {
+		o.current[i] += w
+		if o.current[i] > o.current[selected] {
+			selected = i
+		}
+	}
+	o.current[selected] -= o.totalWeight
+	return selected
+}
+
+func (o *weightedRoundRobinOutputBroker) loop() {
+	defer func() {
+		for _, c := range o.outputTSChans {
+			close(c)
+		}
+		closeAllOutputs(o.outputs)
+		o.shutSig.ShutdownComplete()
+	}()
+
+	for {
+		var ts message.Transaction
+		var open bool
+		select {
+		case ts, open = <-o.transactions:
+			if !open {
+				return
+			}
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+		select {
+		case o.outputTSChans[o.next()] <- ts:
+		case <-o.shutSig.CloseAtLeisureChan():
+			return
+		}
+	}
+}
+
+func (o *weightedRoundRobinOutputBroker) CloseAsync() {
+	o.shutSig.CloseAtLeisure()
+}
+
+func (o *weightedRoundRobinOutputBroker) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-o.shutSig.HasClosedChan():
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
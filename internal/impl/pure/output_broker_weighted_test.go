@@ -0,0 +1,140 @@
+package pure
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+var (
+	_ output.Streamed = &weightedRoundRobinOutputBroker{}
+	_ output.Streamed = &leastPendingOutputBroker{}
+)
+
+// receiveFromAny reads a transaction from any of the mock outputs and returns
+// the index of the output it was read from.
+func receiveFromAny(t *testing.T, mockOutputs []*mock.OutputChanneled) (int, message.Transaction) {
+	t.Helper()
+	select {
+	case ts := <-mockOutputs[0].TChan:
+		return 0, ts
+	case ts := <-mockOutputs[1].TChan:
+		return 1, ts
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker propagate")
+	}
+	return -1, message.Transaction{}
+}
+
+func TestWeightedRoundRobinBroker(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	mockOutputs := []*mock.OutputChanneled{{}, {}}
+	outputs := []output.Streamed{}
+	for _, o := range mockOutputs {
+		outputs = append(outputs, o)
+	}
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error, 8)
+
+	oTM, err := newWeightedRoundRobinOutputBroker(outputs, []int{3, 1})
+	require.NoError(t, err)
+	require.NoError(t, oTM.Consume(readChan))
+
+	var targets []int
+	for i := 0; i < 8; i++ {
+		content := fmt.Sprintf("hello world %v", i)
+		select {
+		case readChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte(content)}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker send")
+		}
+
+		index, ts := receiveFromAny(t, mockOutputs)
+		assert.Equal(t, content, string(ts.Payload.Get(0).Get()))
+		require.NoError(t, ts.Ack(tCtx, nil))
+		require.NoError(t, <-resChan)
+		targets = append(targets, index)
+	}
+	assert.Equal(t, []int{0, 0, 1, 0, 0, 0, 1, 0}, targets)
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*5))
+}
+
+func TestWeightedRoundRobinBrokerBadWeights(t *testing.T) {
+	outputs := []output.Streamed{&mock.OutputChanneled{}, &mock.OutputChanneled{}}
+
+	_, err := newWeightedRoundRobinOutputBroker(outputs, []int{1})
+	require.Error(t, err)
+
+	_, err = newWeightedRoundRobinOutputBroker(outputs, []int{1, -1})
+	require.Error(t, err)
+
+	_, err = newWeightedRoundRobinOutputBroker(outputs, []int{0, 0})
+	require.Error(t, err)
+}
+
+func TestLeastPendingBroker(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	mockOutputs := []*mock.OutputChanneled{{}, {}}
+	outputs := []output.Streamed{}
+	for _, o := range mockOutputs {
+		outputs = append(outputs, o)
+	}
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error, 4)
+
+	oTM, err := newLeastPendingOutputBroker(outputs)
+	require.NoError(t, err)
+	require.NoError(t, oTM.Consume(readChan))
+
+	send := func() (int, message.Transaction) {
+		t.Helper()
+		select {
+		case readChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("hello world")}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for broker send")
+		}
+		return receiveFromAny(t, mockOutputs)
+	}
+
+	// Messages are routed to the output with the fewest pending messages, with
+	// ties going to the first output.
+	index, first := send()
+	assert.Equal(t, 0, index)
+
+	index, second := send()
+	assert.Equal(t, 1, index)
+
+	index, third := send()
+	assert.Equal(t, 0, index)
+
+	require.NoError(t, second.Ack(tCtx, nil))
+
+	index, fourth := send()
+	assert.Equal(t, 1, index)
+
+	for _, ts := range []message.Transaction{first, third, fourth} {
+		require.NoError(t, ts.Ack(tCtx, nil))
+	}
+	for i := 0; i < 4; i++ {
+		require.NoError(t, <-resChan)
+	}
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*5))
+}
@@ -0,0 +1,227 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/component/output/processors"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+func init() {
+	err := bundle.AllOutputs.Add(processors.WrapConstructor(func(c output.Config, nm bundle.NewManagement) (output.Streamed, error) {
+		if c.DedupeCheckpoint.Output == nil {
+			return nil, errors.New("cannot create a dedupe_checkpoint output without a child")
+		}
+		wrapped, err := nm.NewOutput(*c.DedupeCheckpoint.Output)
+		if err != nil {
+			return nil, err
+		}
+		return newDedupeCheckpointWriter(c.DedupeCheckpoint, wrapped, nm)
+	}), docs.ComponentSpec{
+		Name:    "dedupe_checkpoint",
+		Status:  docs.StatusBeta,
+		Version: "4.2.0",
+		Summary: `Writes messages to a child output and records the checkpoints of delivered messages within a [cache](/docs/components/caches/about), for use with the ` + "[`checkpoint` mode of the `dedupe` processor](/docs/components/processors/dedupe#checkpoint-mode)" + `.`,
+		Description: `
+The checkpoint of each message, which is the position that it was consumed from as identified by the metadata added by its input, is recorded within the cache only once the child output has acknowledged it. A ` + "`dedupe`" + ` processor in checkpoint mode targeting the same cache then drops messages that are replayed by the input after a crash if they had already been delivered.
+
+Offsets and sequence numbers are recorded as a watermark for each partition, which is only advanced beyond an offset once every message of the partition at or below it that has been passed on by a ` + "`dedupe`" + ` processor in checkpoint mode has either been delivered or removed by a later processor. Therefore messages that were in flight during a crash are never dropped when replayed.
+
+Messages without a checkpoint are written without any checkpoint being recorded.`,
+		Categories: []string{
+			"Utility",
+		},
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("cache", "A [cache resource](/docs/components/caches/about) to record checkpoints within, which must be the same cache as the `dedupe` processor."),
+			docs.FieldOutput("output", "A child output.").HasDefault(nil),
+		).ChildDefaultAndTypesFromStruct(output.NewDedupeCheckpointConfig()),
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Drop replayed Kafka messages",
+				Summary: "In this example we consume events from Kafka and send them to an HTTP API, recording the offset of each partition that has been delivered within a Redis cache so that events that are consumed again after a crash are not sent twice.",
+				Config: `
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ foo ]
+    consumer_group: benthos_foo
+  processors:
+    - dedupe:
+        cache: offsets
+        mode: checkpoint
+
+output:
+  dedupe_checkpoint:
+    cache: offsets
+    output:
+      http_client:
+        url: http://example.com/events
+        verb: POST
+
+cache_resources:
+  - label: offsets
+    redis:
+      url: tcp://localhost:6379
+`,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dedupeCheckpointWriter struct {
+	log log.Modular
+	mgr bundle.NewManagement
+
+	cacheName      string
+	tracker        *checkpointTracker
+	releaseTracker func() error
+	wrapped        output.Streamed
+
+	transactionsIn  <-chan message.Transaction
+	transactionsOut chan message.Transaction
+
+	ctx        context.Context
+	done       func()
+	closedChan chan struct{}
+}
+
+func newDedupeCheckpointWriter(conf output.DedupeCheckpointConfig, wrapped output.Streamed, mgr bundle.NewManagement) (*dedupeCheckpointWriter, error) {
+	if !mgr.ProbeCache(conf.Cache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", conf.Cache)
+	}
+	tracker, release, err := acquireCheckpointTracker(mgr, conf.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, done := context.WithCancel(context.Background())
+	return &dedupeCheckpointWriter{
+		log:             mgr.Logger(),
+		mgr:             mgr,
+		cacheName:       conf.Cache,
+		tracker:         tracker,
+		releaseTracker:  release,
+		wrapped:         wrapped,
+		transactionsOut: make(chan message.Transaction),
+		ctx:             ctx,
+		done:            done,
+		closedChan:      make(chan struct{}),
+	}, nil
+}
+
+// record writes the checkpoints of a delivered batch to the cache.
+func (d *dedupeCheckpointWriter) record(ctx context.Context, cps []dedupeCheckpoint) error {
+	var ordered []dedupeCheckpoint
+	for _, cp := range cps {
+		if cp.ordered {
+			ordered = append(ordered, cp)
+			continue
+		}
+		var err error
+		if cerr := d.mgr.AccessCache(ctx, d.cacheName, func(c cache.V1) {
+			err = c.Set(ctx, cp.key, []byte{'t'}, nil)
+		}); cerr != nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for key, mark := range d.tracker.deliver(ordered) {
+		if err := d.tracker.advanceWatermark(ctx, d.mgr, d.cacheName, key, mark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dedupeCheckpointWriter) loop() {
+	defer func() {
+		close(d.transactionsOut)
+		d.wrapped.CloseAsync()
+		_ = d.wrapped.WaitForClose(shutdown.MaximumShutdownWait())
+		_ = d.releaseTracker()
+		close(d.closedChan)
+	}()
+
+	for {
+		var ts message.Transaction
+		var open bool
+		select {
+		case ts, open = <-d.transactionsIn:
+			if !open {
+				return
+			}
+		case <-d.ctx.Done():
+			return
+		}
+
+		var cps []dedupeCheckpoint
+		_ = ts.Payload.Iter(func(_ int, p *message.Part) error {
+			if cp, ok := checkpointFromPart(p); ok {
+				cps = append(cps, cp)
+			}
+			return nil
+		})
+
+		select {
+		case d.transactionsOut <- message.NewTransactionFunc(ts.Payload, func(ctx context.Context, res error) error {
+			if res == nil && len(cps) > 0 {
+				if err := d.record(ctx, cps); err != nil {
+					// The messages have already been written, and so the write
+					// is acknowledged regardless, which means they are written
+					// again if they are replayed.
+					d.log.Errorf("Failed to record checkpoints: %v\n", err)
+				}
+			}
+			return ts.Ack(ctx, res)
+		}):
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *dedupeCheckpointWriter) Consume(ts <-chan message.Transaction) error {
+	if d.transactionsIn != nil {
+		return component.ErrAlreadyStarted
+	}
+	if err := d.wrapped.Consume(d.transactionsOut); err != nil {
+		return err
+	}
+	d.transactionsIn = ts
+	go d.loop()
+	return nil
+}
+
+func (d *dedupeCheckpointWriter) Connected() bool {
+	return d.wrapped.Connected()
+}
+
+func (d *dedupeCheckpointWriter) CloseAsync() {
+	d.done()
+}
+
+func (d *dedupeCheckpointWriter) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-d.closedChan:
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
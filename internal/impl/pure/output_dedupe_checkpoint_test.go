@@ -0,0 +1,150 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestDedupeCheckpointOutput(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foo"] = map[string]mock.CacheItem{}
+
+	procConf := processor.NewConfig()
+	procConf.Type = "dedupe"
+	procConf.Dedupe.Cache = "foo"
+	procConf.Dedupe.Mode = "checkpoint"
+
+	proc, err := mgr.NewProcessor(procConf)
+	require.NoError(t, err)
+
+	conf := output.NewDedupeCheckpointConfig()
+	conf.Cache = "foo"
+
+	child := &mock.OutputChanneled{}
+	w, err := newDedupeCheckpointWriter(conf, child, mgr)
+	require.NoError(t, err)
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, w.Consume(tChan))
+	t.Cleanup(func() {
+		w.CloseAsync()
+		assert.NoError(t, w.WaitForClose(time.Second*5))
+	})
+
+	newPart := func(content string, meta ...string) *message.Part {
+		p := message.NewPart([]byte(content))
+		for i := 0; i < len(meta)-1; i += 2 {
+			p.MetaSet(meta[i], meta[i+1])
+		}
+		return p
+	}
+	kafkaPart := func(offset string) *message.Part {
+		return newPart(offset, "kafka_topic", "foo", "kafka_partition", "0", "kafka_offset", offset)
+	}
+
+	process := func(parts ...*message.Part) []*message.Part {
+		t.Helper()
+		batch := message.QuickBatch(nil)
+		batch.Append(parts...)
+		msgs, err := proc.ProcessMessage(batch)
+		require.NoError(t, err)
+		if len(msgs) == 0 {
+			return nil
+		}
+		var res []*message.Part
+		_ = msgs[0].Iter(func(_ int, p *message.Part) error {
+			res = append(res, p)
+			return nil
+		})
+		return res
+	}
+
+	deliver := func(p *message.Part, res error) {
+		t.Helper()
+		resChan := make(chan error, 1)
+		batch := message.QuickBatch(nil)
+		batch.Append(p)
+		select {
+		case tChan <- message.NewTransaction(batch, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		select {
+		case ts := <-child.TChan:
+			require.NoError(t, ts.Ack(context.Background(), res))
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		select {
+		case err := <-resChan:
+			assert.Equal(t, res, err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+	}
+
+	parts := process(kafkaPart("0"), kafkaPart("1"), kafkaPart("2"))
+	require.Len(t, parts, 3)
+
+	// The watermark is not advanced beyond messages still in flight.
+	deliver(parts[2], nil)
+	assert.Empty(t, mgr.Caches["foo"])
+
+	deliver(parts[0], nil)
+	assert.Equal(t, "0", mgr.Caches["foo"]["kafka/foo/0"].Value)
+
+	// Failed writes do not advance the watermark.
+	nope := errors.New("nope")
+	deliver(parts[1], nope)
+	assert.Equal(t, "0", mgr.Caches["foo"]["kafka/foo/0"].Value)
+
+	// Replayed messages that were not delivered are kept.
+	parts = process(kafkaPart("0"), kafkaPart("1"), kafkaPart("2"))
+	require.Len(t, parts, 2)
+	assert.Equal(t, "1", string(parts[0].Get()))
+	assert.Equal(t, "2", string(parts[1].Get()))
+
+	deliver(parts[0], nil)
+	assert.Equal(t, "1", mgr.Caches["foo"]["kafka/foo/0"].Value)
+
+	deliver(parts[1], nil)
+	assert.Equal(t, "2", mgr.Caches["foo"]["kafka/foo/0"].Value)
+
+	assert.Empty(t, process(kafkaPart("1"), kafkaPart("2")))
+
+	// Messages removed after the processor no longer hold back the watermark
+	// once their transaction has been resolved.
+	batch := message.QuickBatch(nil)
+	batch.Append(kafkaPart("3"), kafkaPart("4"))
+	hooks, batch := message.NewResolveHooks(batch)
+	parts = process(batch.Get(0), batch.Get(1))
+	require.Len(t, parts, 2)
+
+	deliver(parts[1], nil)
+	assert.Equal(t, "2", mgr.Caches["foo"]["kafka/foo/0"].Value)
+
+	hooks.Resolve(nil)
+	parts = process(kafkaPart("5"))
+	require.Len(t, parts, 1)
+	deliver(parts[0], nil)
+	assert.Equal(t, "5", mgr.Caches["foo"]["kafka/foo/0"].Value)
+
+	// Unordered checkpoints are recorded as keys.
+	parts = process(newPart("a", "sqs_message_id", "xyz"))
+	require.Len(t, parts, 1)
+	assert.NotContains(t, mgr.Caches["foo"], "sqs/xyz")
+
+	deliver(parts[0], nil)
+	assert.Contains(t, mgr.Caches["foo"], "sqs/xyz")
+	assert.Empty(t, process(newPart("a", "sqs_message_id", "xyz")))
+}
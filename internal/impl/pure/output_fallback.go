@@ -63,7 +63,19 @@ output:
 
 Benthos makes a best attempt at inferring which specific messages of the batch failed, and only propagates those individual messages to the next fallback tier.
 
-However, depending on the output and the error returned it is sometimes not possible to determine the individual messages that failed, in which case the whole batch is passed to the next tier in order to preserve at-least-once delivery guarantees.`,
+However, depending on the output and the error returned it is sometimes not possible to determine the individual messages that failed, in which case the whole batch is passed to the next tier in order to preserve at-least-once delivery guarantees.
+
+### Metadata
+
+When a message is passed to the next tier the following metadata fields are added to it before the processors of that tier are applied, which makes it possible to record why a message ended up in a dead letter queue:
+
+` + "```text" + `
+- fallback_error
+- fallback_attempts
+- fallback_label
+` + "```" + `
+
+The field ` + "`fallback_error`" + ` contains the error returned by the previous tier, ` + "`fallback_attempts`" + ` contains the number of tiers that have failed to send the message so far, and ` + "`fallback_label`" + ` contains the [label](/docs/components/outputs/about#labels) of the output of the previous tier, and is removed when that output has no label.`,
 		Categories: []string{
 			"Utility",
 		},
@@ -94,8 +106,13 @@ func newFallback(conf output.Config, mgr bundle.NewManagement, pipelines ...proc
 		}
 	}
 
+	labels := make([]string, len(outputConfs))
+	for i, oConf := range outputConfs {
+		labels[i] = oConf.Label
+	}
+
 	var t *fallbackBroker
-	if t, err = newFallbackBroker(outputs); err != nil {
+	if t, err = newFallbackBroker(outputs, labels); err != nil {
 		return nil, err
 	}
 	return output.WrapWithPipelines(t, pipelines...)
@@ -106,14 +123,19 @@ type fallbackBroker struct {
 
 	outputTSChans []chan message.Transaction
 	outputs       []output.Streamed
+	labels        []string
 
 	shutSig *shutdown.Signaller
 }
 
-func newFallbackBroker(outputs []output.Streamed) (*fallbackBroker, error) {
+// newFallbackBroker creates a broker that sends messages to each output in
+// turn until one succeeds, the labels of the outputs are optional and are used
+// to annotate messages that are passed to later tiers.
+func newFallbackBroker(outputs []output.Streamed, labels []string) (*fallbackBroker, error) {
 	t := &fallbackBroker{
 		transactions: nil,
 		outputs:      outputs,
+		labels:       labels,
 		shutSig:      shutdown.NewSignaller(),
 	}
 	if len(outputs) == 0 {
@@ -186,7 +208,7 @@ func (t *fallbackBroker) loop() {
 				return tran.Ack(ctx, err)
 			}
 			select {
-			case t.outputTSChans[i] <- message.NewTransactionFunc(tran.Payload, ackFn):
+			case t.outputTSChans[i] <- message.NewTransactionFunc(t.annotateFailed(tran.Payload, i, err), ackFn):
 			case <-ctx.Done():
 				return ctx.Err()
 			}
@@ -201,6 +223,29 @@ func (t *fallbackBroker) loop() {
 	}
 }
 
+// annotateFailed returns a copy of a batch that failed to be sent to the tier
+// prior to the next tier, with metadata describing the failure.
+func (t *fallbackBroker) annotateFailed(msg *message.Batch, nextTier int, err error) *message.Batch {
+	label := ""
+	if prev := nextTier - 1; prev < len(t.labels) {
+		label = t.labels[prev]
+	}
+	attempts := strconv.Itoa(nextTier)
+
+	annotated := msg.Copy()
+	_ = annotated.Iter(func(i int, p *message.Part) error {
+		p.MetaSet("fallback_error", err.Error())
+		p.MetaSet("fallback_attempts", attempts)
+		if label != "" {
+			p.MetaSet("fallback_label", label)
+		} else {
+			p.MetaDelete("fallback_label")
+		}
+		return nil
+	})
+	return annotated
+}
+
 // CloseAsync shuts down the fallbackBroker broker and stops processing requests.
 func (t *fallbackBroker) CloseAsync() {
 	t.shutSig.CloseAtLeisure()
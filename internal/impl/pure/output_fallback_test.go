@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/internal/bundle"
@@ -104,7 +105,7 @@ func TestFallbackOutputBasic(t *testing.T) {
 }
 
 func TestFallbackDoubleClose(t *testing.T) {
-	oTM, err := newFallbackBroker([]output.Streamed{&mock.OutputChanneled{}})
+	oTM, err := newFallbackBroker([]output.Streamed{&mock.OutputChanneled{}}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -134,7 +135,7 @@ func TestFallbackHappyPath(t *testing.T) {
 	readChan := make(chan message.Transaction)
 	resChan := make(chan error)
 
-	oTM, err := newFallbackBroker(outputs)
+	oTM, err := newFallbackBroker(outputs, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -208,7 +209,7 @@ func TestFallbackHappyishPath(t *testing.T) {
 	readChan := make(chan message.Transaction)
 	resChan := make(chan error)
 
-	oTM, err := newFallbackBroker(outputs)
+	oTM, err := newFallbackBroker(outputs, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -301,7 +302,7 @@ func TestFallbackAllFail(t *testing.T) {
 	readChan := make(chan message.Transaction)
 	resChan := make(chan error)
 
-	oTM, err := newFallbackBroker(outputs)
+	oTM, err := newFallbackBroker(outputs, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -375,7 +376,7 @@ func TestFallbackAllFailParallel(t *testing.T) {
 
 	readChan := make(chan message.Transaction)
 
-	oTM, err := newFallbackBroker(outputs)
+	oTM, err := newFallbackBroker(outputs, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -445,3 +446,82 @@ func TestFallbackAllFailParallel(t *testing.T) {
 }
 
 //------------------------------------------------------------------------------
+
+func TestFallbackMetadata(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	mockOutputs := []*mock.OutputChanneled{{}, {}, {}}
+	outputs := []output.Streamed{}
+	for _, o := range mockOutputs {
+		outputs = append(outputs, o)
+	}
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error)
+
+	oTM, err := newFallbackBroker(outputs, []string{"foo", "", "baz"})
+	require.NoError(t, err)
+	require.NoError(t, oTM.Consume(readChan))
+
+	inMsg := message.QuickBatch([][]byte{[]byte("hello world")})
+	select {
+	case readChan <- message.NewTransaction(inMsg, resChan):
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker send")
+	}
+
+	var ts message.Transaction
+	select {
+	case ts = <-mockOutputs[0].TChan:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker propagate")
+	}
+	assert.Equal(t, "", ts.Payload.Get(0).MetaGet("fallback_error"))
+	go func(ts message.Transaction) {
+		assert.NoError(t, ts.Ack(tCtx, errors.New("first failed")))
+	}(ts)
+
+	select {
+	case ts = <-mockOutputs[1].TChan:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker propagate")
+	}
+	part := ts.Payload.Get(0)
+	assert.Equal(t, "hello world", string(part.Get()))
+	assert.Equal(t, "first failed", part.MetaGet("fallback_error"))
+	assert.Equal(t, "1", part.MetaGet("fallback_attempts"))
+	assert.Equal(t, "foo", part.MetaGet("fallback_label"))
+	go func(ts message.Transaction) {
+		assert.NoError(t, ts.Ack(tCtx, errors.New("second failed")))
+	}(ts)
+
+	select {
+	case ts = <-mockOutputs[2].TChan:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broker propagate")
+	}
+	part = ts.Payload.Get(0)
+	assert.Equal(t, "second failed", part.MetaGet("fallback_error"))
+	assert.Equal(t, "2", part.MetaGet("fallback_attempts"))
+	_ = part.MetaIter(func(k, v string) error {
+		assert.NotEqual(t, "fallback_label", k)
+		return nil
+	})
+	go func(ts message.Transaction) {
+		assert.NoError(t, ts.Ack(tCtx, nil))
+	}(ts)
+
+	select {
+	case res := <-resChan:
+		require.NoError(t, res)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out responding to broker")
+	}
+
+	// The metadata of the original message is left untouched.
+	assert.Equal(t, "", inMsg.Get(0).MetaGet("fallback_error"))
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*10))
+}
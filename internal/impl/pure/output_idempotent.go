@@ -0,0 +1,314 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/component/output/processors"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+func init() {
+	err := bundle.AllOutputs.Add(processors.WrapConstructor(func(c output.Config, nm bundle.NewManagement) (output.Streamed, error) {
+		if c.Idempotent.Output == nil {
+			return nil, errors.New("cannot create an idempotent output without a child")
+		}
+		wrapped, err := nm.NewOutput(*c.Idempotent.Output)
+		if err != nil {
+			return nil, err
+		}
+		return newIdempotentWriter(c.Idempotent, wrapped, nm)
+	}), docs.ComponentSpec{
+		Name:    "idempotent",
+		Status:  docs.StatusBeta,
+		Version: "4.2.0",
+		Summary: `Writes messages to a child output only once per idempotency key, recording the keys of delivered messages within a [cache](/docs/components/caches/about) so that redelivered messages are dropped.`,
+		Description: `
+Benthos delivers messages at-least-once, and so when a pipeline is interrupted messages that have already been written by an output can be delivered to it again. For sinks that have no way of deduplicating writes themselves this output can be used in order to achieve effectively exactly-once delivery.
+
+An idempotency key is calculated for each message with the interpolated field ` + "`key`" + `, which should identify the message regardless of how many times it is delivered, such as an ID within the message or the offset it was consumed from. Before a message is written the key is claimed within the cache by adding it with the TTL ` + "`lock_ttl`" + `, and once the write succeeds the key is marked as delivered with the TTL ` + "`ttl`" + `. Messages with a key that has already been delivered are acknowledged without being written, and when a write fails the claims of its keys are removed so that it can be reattempted.
+
+When a key is claimed but not yet delivered, either because the message is still being written or because Benthos was interrupted during the write, messages with the same key are rejected until the claim is released or expires. Therefore ` + "`lock_ttl`" + ` should exceed the longest time a write can take, and the cache must support the atomic ` + "`add`" + ` operation across every instance sharing it in order for claims to be exclusive, which rules out caches local to each instance such as ` + "`memory`" + ` when running multiple instances.
+
+Messages are only deduplicated for as long as their keys remain within the cache, and a write that succeeds but is interrupted before its keys are marked as delivered will be reattempted once its claims expire.`,
+		Categories: []string{
+			"Utility",
+		},
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("cache", "A [cache resource](/docs/components/caches/about) to record idempotency keys within."),
+			docs.FieldString("key", "An idempotency key to calculate for each message, which identifies the message across redeliveries.",
+				`${! json("id") }`,
+				`${! meta("kafka_topic") }-${! meta("kafka_partition") }-${! meta("kafka_offset") }`,
+			).IsInterpolated(),
+			docs.FieldString("ttl", "An optional TTL to set on the keys of delivered messages, after which redeliveries of the message are no longer detected. Not all caches support per-key TTLs, and those that do not will fall back to their generally configured TTL setting.", "24h", "168h"),
+			docs.FieldString("lock_ttl", "The TTL of the claim on a key while its message is being written, after which the claim expires if the write never completed.").Advanced(),
+			docs.FieldOutput("output", "A child output.").HasDefault(nil),
+		).ChildDefaultAndTypesFromStruct(output.NewIdempotentConfig()),
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Exactly-once HTTP deliveries",
+				Summary: "In this example we consume events from Kafka and send them to an HTTP API that has no way of detecting duplicates, using the origin of each event as its idempotency key so that events that are consumed again after a restart are not sent twice.",
+				Config: `
+output:
+  idempotent:
+    cache: delivered
+    key: ${! meta("kafka_topic") }-${! meta("kafka_partition") }-${! meta("kafka_offset") }
+    ttl: 168h
+    output:
+      http_client:
+        url: http://example.com/events
+        verb: POST
+
+cache_resources:
+  - label: delivered
+    redis:
+      url: tcp://localhost:6379
+`,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var (
+	idempotentClaimed   = []byte("claimed")
+	idempotentDelivered = []byte("delivered")
+)
+
+type idempotentWriter struct {
+	log log.Modular
+	mgr bundle.NewManagement
+
+	cacheName string
+	key       *field.Expression
+	ttl       *time.Duration
+	lockTTL   *time.Duration
+	wrapped   output.Streamed
+
+	transactionsIn  <-chan message.Transaction
+	transactionsOut chan message.Transaction
+
+	ctx        context.Context
+	done       func()
+	closedChan chan struct{}
+}
+
+func newIdempotentWriter(conf output.IdempotentConfig, wrapped output.Streamed, mgr bundle.NewManagement) (*idempotentWriter, error) {
+	if !mgr.ProbeCache(conf.Cache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", conf.Cache)
+	}
+	key, err := mgr.BloblEnvironment().NewField(conf.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key expression: %v", err)
+	}
+
+	ctx, done := context.WithCancel(context.Background())
+	i := &idempotentWriter{
+		log:             mgr.Logger(),
+		mgr:             mgr,
+		cacheName:       conf.Cache,
+		key:             key,
+		wrapped:         wrapped,
+		transactionsOut: make(chan message.Transaction),
+		ctx:             ctx,
+		done:            done,
+		closedChan:      make(chan struct{}),
+	}
+	if conf.TTL != "" {
+		ttl, err := time.ParseDuration(conf.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ttl duration: %w", err)
+		}
+		i.ttl = &ttl
+	}
+	if conf.LockTTL != "" {
+		lockTTL, err := time.ParseDuration(conf.LockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse lock_ttl duration: %w", err)
+		}
+		i.lockTTL = &lockTTL
+	}
+	return i, nil
+}
+
+// claim attempts to claim the keys of a batch, returning the keys that were
+// claimed and the indexes of the messages that should be written. Messages
+// whose keys were already delivered, or that share a key with a prior message
+// of the batch, are omitted.
+func (i *idempotentWriter) claim(ctx context.Context, batch *message.Batch) (claimed []string, indexes []int, err error) {
+	seen := map[string]struct{}{}
+	if cerr := i.mgr.AccessCache(ctx, i.cacheName, func(c cache.V1) {
+		for j := 0; j < batch.Len(); j++ {
+			key := i.key.String(j, batch)
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			aerr := c.Add(ctx, key, idempotentClaimed, i.lockTTL)
+			if aerr == nil {
+				claimed = append(claimed, key)
+				indexes = append(indexes, j)
+				continue
+			}
+			if !errors.Is(aerr, component.ErrKeyAlreadyExists) {
+				err = fmt.Errorf("failed to claim key: %w", aerr)
+				return
+			}
+
+			v, gerr := c.Get(ctx, key)
+			if gerr != nil && !errors.Is(gerr, component.ErrKeyNotFound) {
+				err = fmt.Errorf("failed to check key: %w", gerr)
+				return
+			}
+			if string(v) != string(idempotentDelivered) {
+				err = fmt.Errorf("key %v is claimed by a write that has not yet completed", key)
+				return
+			}
+		}
+	}); cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		i.release(ctx, claimed)
+		return nil, nil, err
+	}
+	return claimed, indexes, nil
+}
+
+// release removes the claims of keys so that their messages can be written
+// again.
+func (i *idempotentWriter) release(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	if cerr := i.mgr.AccessCache(ctx, i.cacheName, func(c cache.V1) {
+		for _, key := range keys {
+			if err := c.Delete(ctx, key); err != nil && !errors.Is(err, component.ErrKeyNotFound) {
+				i.log.Errorf("Failed to release claim of key %v: %v\n", key, err)
+			}
+		}
+	}); cerr != nil {
+		i.log.Errorf("Failed to access cache: %v\n", cerr)
+	}
+}
+
+// deliver marks claimed keys as delivered.
+func (i *idempotentWriter) deliver(ctx context.Context, keys []string) error {
+	items := make(map[string]cache.TTLItem, len(keys))
+	for _, key := range keys {
+		items[key] = cache.TTLItem{
+			Value: idempotentDelivered,
+			TTL:   i.ttl,
+		}
+	}
+	var err error
+	if cerr := i.mgr.AccessCache(ctx, i.cacheName, func(c cache.V1) {
+		err = c.SetMulti(ctx, items)
+	}); cerr != nil {
+		err = cerr
+	}
+	return err
+}
+
+func (i *idempotentWriter) loop() {
+	defer func() {
+		close(i.transactionsOut)
+		i.wrapped.CloseAsync()
+		_ = i.wrapped.WaitForClose(shutdown.MaximumShutdownWait())
+		close(i.closedChan)
+	}()
+
+	for {
+		var ts message.Transaction
+		var open bool
+		select {
+		case ts, open = <-i.transactionsIn:
+			if !open {
+				return
+			}
+		case <-i.ctx.Done():
+			return
+		}
+
+		claimed, indexes, err := i.claim(i.ctx, ts.Payload)
+		if err != nil || len(indexes) == 0 {
+			if err != nil {
+				i.log.Errorf("Failed to claim idempotency keys: %v\n", err)
+			} else {
+				i.log.Debugln("Dropping batch of redelivered messages.")
+			}
+			if aerr := ts.Ack(i.ctx, err); aerr != nil && i.ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		payload := ts.Payload
+		if len(indexes) < payload.Len() {
+			payload = message.QuickBatch(nil)
+			for _, j := range indexes {
+				payload.Append(ts.Payload.Get(j))
+			}
+		}
+
+		select {
+		case i.transactionsOut <- message.NewTransactionFunc(payload, func(ctx context.Context, res error) error {
+			if res != nil {
+				i.release(ctx, claimed)
+			} else if err := i.deliver(ctx, claimed); err != nil {
+				// The messages have already been written, and so the write is
+				// acknowledged regardless, which means they are reattempted
+				// once their claims expire if they are redelivered.
+				i.log.Errorf("Failed to mark idempotency keys as delivered: %v\n", err)
+			}
+			return ts.Ack(ctx, res)
+		}):
+		case <-i.ctx.Done():
+			return
+		}
+	}
+}
+
+func (i *idempotentWriter) Consume(ts <-chan message.Transaction) error {
+	if i.transactionsIn != nil {
+		return component.ErrAlreadyStarted
+	}
+	if err := i.wrapped.Consume(i.transactionsOut); err != nil {
+		return err
+	}
+	i.transactionsIn = ts
+	go i.loop()
+	return nil
+}
+
+func (i *idempotentWriter) Connected() bool {
+	return i.wrapped.Connected()
+}
+
+func (i *idempotentWriter) CloseAsync() {
+	i.done()
+}
+
+func (i *idempotentWriter) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-i.closedChan:
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
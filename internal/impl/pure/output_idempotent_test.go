@@ -0,0 +1,106 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestIdempotentOutput(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foo"] = map[string]mock.CacheItem{}
+
+	conf := output.NewIdempotentConfig()
+	conf.Cache = "foo"
+	conf.Key = `${! json("id") }`
+	conf.TTL = "1h"
+
+	child := &mock.OutputChanneled{}
+	w, err := newIdempotentWriter(conf, child, mgr)
+	require.NoError(t, err)
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, w.Consume(tChan))
+	t.Cleanup(func() {
+		w.CloseAsync()
+		assert.NoError(t, w.WaitForClose(time.Second*5))
+	})
+
+	send := func(parts ...string) <-chan error {
+		t.Helper()
+		resChan := make(chan error, 1)
+		batch := message.QuickBatch(nil)
+		for _, p := range parts {
+			batch.Append(message.NewPart([]byte(p)))
+		}
+		select {
+		case tChan <- message.NewTransaction(batch, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return resChan
+	}
+
+	receive := func(res error) []string {
+		t.Helper()
+		var ts message.Transaction
+		select {
+		case ts = <-child.TChan:
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		var parts []string
+		_ = ts.Payload.Iter(func(_ int, p *message.Part) error {
+			parts = append(parts, string(p.Get()))
+			return nil
+		})
+		require.NoError(t, ts.Ack(context.Background(), res))
+		return parts
+	}
+
+	awaitRes := func(resChan <-chan error) error {
+		t.Helper()
+		select {
+		case err := <-resChan:
+			return err
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return nil
+	}
+
+	// A failed write releases its claims.
+	resChan := send(`{"id":"a"}`, `{"id":"b"}`)
+	assert.Equal(t, []string{`{"id":"a"}`, `{"id":"b"}`}, receive(errors.New("nope")))
+	assert.EqualError(t, awaitRes(resChan), "nope")
+	assert.Empty(t, mgr.Caches["foo"])
+
+	resChan = send(`{"id":"a"}`, `{"id":"b"}`, `{"id":"b"}`)
+	assert.Equal(t, []string{`{"id":"a"}`, `{"id":"b"}`}, receive(nil))
+	require.NoError(t, awaitRes(resChan))
+	assert.Equal(t, "delivered", mgr.Caches["foo"]["a"].Value)
+	assert.Equal(t, time.Hour, *mgr.Caches["foo"]["a"].TTL)
+
+	// Redelivered messages are dropped.
+	resChan = send(`{"id":"b"}`, `{"id":"c"}`)
+	assert.Equal(t, []string{`{"id":"c"}`}, receive(nil))
+	require.NoError(t, awaitRes(resChan))
+
+	resChan = send(`{"id":"a"}`, `{"id":"c"}`)
+	require.NoError(t, awaitRes(resChan))
+
+	// Keys claimed by incomplete writes are rejected.
+	mgr.Caches["foo"]["d"] = mock.CacheItem{Value: "claimed"}
+	resChan = send(`{"id":"d"}`, `{"id":"e"}`)
+	require.Error(t, awaitRes(resChan))
+	_, exists := mgr.Caches["foo"]["e"]
+	assert.False(t, exists)
+}
@@ -0,0 +1,110 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func inprocTopicOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		// Stable(). TODO
+		Version("4.2.0").
+		Categories("Utility").
+		Summary("Publishes messages to a named topic that other streams within the same Benthos process subscribe to with an [`inproc_topic` input](/docs/components/inputs/inproc_topic).").
+		Description(`
+This allows you to compose isolated streams whilst running Benthos in [streams mode](/docs/guides/streams_mode/about) without a real message broker. Unlike the ` + "[`inproc` output](/docs/components/outputs/inproc)" + `, any number of outputs can publish to the same topic, and every input subscribed to the topic receives a copy of each message.
+
+A message is only acknowledged once every subscriber of the topic has processed it, or has buffered it when the subscriber is configured with a ` + "`buffer_size`" + `. Whilst a topic has no subscribers this output is considered disconnected, and messages are not published until at least one subscriber is present.`).
+		Field(service.NewStringField("topic").
+			Description("The name of the topic to publish to.")).
+		Field(service.NewIntField("max_in_flight").
+			Description("The maximum number of message batches to have in flight at a given time. Increase this to improve throughput.").
+			Default(64))
+}
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"inproc_topic", inprocTopicOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
+				return
+			}
+			out, err = newInprocTopicOutputFromConfig(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type inprocTopicOutput struct {
+	topicName string
+	mgr       *service.Resources
+
+	mut     sync.Mutex
+	topic   *inprocTopic
+	release func() error
+}
+
+func newInprocTopicOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*inprocTopicOutput, error) {
+	topicName, err := conf.FieldString("topic")
+	if err != nil {
+		return nil, err
+	}
+	if topicName == "" {
+		return nil, errors.New("a topic must be specified")
+	}
+	return &inprocTopicOutput{
+		topicName: topicName,
+		mgr:       mgr,
+	}, nil
+}
+
+func (o *inprocTopicOutput) Connect(ctx context.Context) error {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	if o.topic == nil {
+		client, release, err := o.mgr.AcquireSharedClient(inprocTopicKey(o.topicName), func() (io.Closer, error) {
+			return newInprocTopic(), nil
+		})
+		if err != nil {
+			return err
+		}
+		o.topic, o.release = client.(*inprocTopic), release
+	}
+
+	if len(o.topic.subscribers()) == 0 {
+		return errors.New("inproc topic has no subscribers")
+	}
+	o.mgr.Logger().Infof("Publishing to inproc topic: %v", o.topicName)
+	return nil
+}
+
+func (o *inprocTopicOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	o.mut.Lock()
+	topic := o.topic
+	o.mut.Unlock()
+
+	if topic == nil {
+		return service.ErrNotConnected
+	}
+	return topic.publish(ctx, batch)
+}
+
+func (o *inprocTopicOutput) Close(ctx context.Context) error {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	if o.topic == nil {
+		return nil
+	}
+	o.topic = nil
+	return o.release()
+}
@@ -3,12 +3,14 @@ package pure
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
@@ -39,7 +41,41 @@ we want to avoid reapplying to the same message more than once in the pipeline.
 
 Rather than retrying the same output you may wish to retry the send using a
 different output target (a dead letter queue). In which case you should instead
-use the ` + "[`fallback`](/docs/components/outputs/fallback)" + ` output type.`,
+use the ` + "[`fallback`](/docs/components/outputs/fallback)" + ` output type.
+
+### Ordering
+
+By default messages are written to the child output in parallel and a failed
+message is retried alongside any messages that were sent after it, and
+therefore messages can reach the target out of order. When ` + "`ordered`" + ` is
+set to ` + "`true`" + ` a message is only written once the prior message has
+either been delivered or abandoned, which guarantees in-order delivery even
+across retries at the cost of throughput.
+
+Setting an ` + "`ordering_key`" + ` relaxes this guarantee so that only messages
+that share the same key are held, which allows messages of different keys to
+be written in parallel. This is useful for change data capture (CDC) streams
+where ordering only matters per table or per row. Up to 1024 messages are held
+across all keys, beyond which no further messages are consumed until the held
+messages are resolved.
+
+When the retry limits are reached the failed message is rejected upstream, and
+the messages that follow it are held until the rejection is resolved, which
+makes it possible to route failed messages to a dead letter queue with a
+` + "[`fallback`](/docs/components/outputs/fallback)" + ` output without breaking
+the order of the remaining messages.
+
+### Expiry
+
+When ` + "`expires_at`" + ` is set the expiry of each message is checked before
+its write is reattempted, and once a message has expired it is abandoned and
+rejected upstream instead, which avoids the delivery of stale data that has
+spent too long being retried. Attaching an expiry to messages is explained in
+the ` + "[`expiry`](/docs/components/processors/expiry)" + ` processor docs,
+and rejected messages can be routed to a dead letter queue with a
+` + "[`fallback`](/docs/components/outputs/fallback)" + ` output, where the
+metadata field ` + "`fallback_error`" + ` of an expired message indicates that
+it expired.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldInt("max_retries", "The maximum number of retries before giving up on the request. If set to zero there is no discrete limit.").HasDefault(0).Advanced(),
 			docs.FieldObject("backoff", "Control time intervals between retry attempts.").WithChildren(
@@ -47,6 +83,9 @@ use the ` + "[`fallback`](/docs/components/outputs/fallback)" + ` output type.`,
 				docs.FieldString("max_interval", "The maximum period to wait between retry attempts.").HasDefault("1s"),
 				docs.FieldString("max_elapsed_time", "The maximum period to wait before retry attempts are abandoned. If zero then no limit is used.").HasDefault("0s"),
 			).Advanced(),
+			docs.FieldBool("ordered", "Whether to guarantee that messages are delivered in the order they were consumed, even across retries, by only writing a message once the prior message has been delivered or abandoned.").HasDefault(false).Advanced().AtVersion("4.2.0"),
+			docs.FieldInterpolatedString("ordering_key", "An optional key that, when `ordered` is `true`, limits the ordering guarantee to messages that share the same key, allowing messages of different keys to be written in parallel.", `${! meta("table") }`).HasDefault("").Advanced().AtVersion("4.2.0"),
+			docs.FieldInterpolatedString("expires_at", "An optional expiry of each message, either as an RFC 3339 timestamp or a unix timestamp in seconds, after which a failed message is [abandoned](#expiry) rather than reattempted.", `${! meta("expires_at").or("") }`).HasDefault("").Advanced().AtVersion("4.2.0"),
 			docs.FieldOutput("output", "A child output."),
 		),
 		Categories: []string{
@@ -82,7 +121,22 @@ func retryOutputFromConfig(conf output.RetryConfig, mgr bundle.NewManagement) (o
 		return nil, err
 	}
 
-	return newIndefiniteRetry(mgr, boffCtor, wrapped)
+	r, err := newIndefiniteRetry(mgr, boffCtor, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ordered = conf.Ordered; r.ordered && conf.OrderingKey != "" {
+		if r.orderingKey, err = mgr.BloblEnvironment().NewField(conf.OrderingKey); err != nil {
+			return nil, fmt.Errorf("failed to parse ordering_key expression: %v", err)
+		}
+	}
+	if conf.ExpiresAt != "" {
+		if r.expiresAt, err = mgr.BloblEnvironment().NewField(conf.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to parse expires_at expression: %v", err)
+		}
+	}
+	return r, nil
 }
 
 func newIndefiniteRetry(mgr bundle.NewManagement, backoffCtor func() backoff.BackOff, wrapped output.Streamed) (*indefiniteRetry, error) {
@@ -98,17 +152,35 @@ func newIndefiniteRetry(mgr bundle.NewManagement, backoffCtor func() backoff.Bac
 		log:             mgr.Logger(),
 		wrapped:         wrapped,
 		backoffCtor:     backoffCtor,
+		maxQueued:       retryOrderedMaxQueued,
 		transactionsOut: make(chan message.Transaction),
 		shutSig:         shutdown.NewSignaller(),
 	}, nil
 }
 
+// retryOrderedMaxQueued is the maximum number of messages that are held across
+// all keys when ordering by key.
+const retryOrderedMaxQueued = 1024
+
 // indefiniteRetry is an output type that continuously writes a message to a
 // child output until the send is successful.
 type indefiniteRetry struct {
 	wrapped     output.Streamed
 	backoffCtor func() backoff.BackOff
 
+	// When ordered only one message is written at a time, or one message per
+	// ordering key when a key is set.
+	ordered     bool
+	orderingKey *field.Expression
+
+	// The maximum number of messages that are either queued or being written
+	// across all ordering keys, once reached no further messages are consumed.
+	maxQueued int
+
+	// When set messages are abandoned rather than reattempted once they have
+	// expired.
+	expiresAt *field.Expression
+
 	log log.Modular
 
 	transactionsIn  <-chan message.Transaction
@@ -117,6 +189,20 @@ type indefiniteRetry struct {
 	shutSig *shutdown.Signaller
 }
 
+// hasExpired returns true when a batch has an expiry that has passed, where the
+// expiry of a batch is calculated from its first message.
+func (r *indefiniteRetry) hasExpired(msg *message.Batch) bool {
+	if r.expiresAt == nil {
+		return false
+	}
+	expiresAt, ok, err := parseExpiry(r.expiresAt.String(0, msg))
+	if err != nil {
+		r.log.Warnf("Ignoring expiry of message: %v\n", err)
+		return false
+	}
+	return ok && !time.Now().Before(expiresAt)
+}
+
 func (r *indefiniteRetry) loop() {
 	wg := sync.WaitGroup{}
 
@@ -134,10 +220,127 @@ func (r *indefiniteRetry) loop() {
 	errInterruptChan := make(chan struct{})
 	var errLooped int64
 
+	// send writes a message to the child output, returning false if we are
+	// shutting down.
+	send := func(msg *message.Batch, resChan chan error) bool {
+		select {
+		case r.transactionsOut <- message.NewTransaction(msg, resChan):
+			return true
+		case <-r.shutSig.CloseAtLeisureChan():
+			return false
+		}
+	}
+
+	// resolve reattempts a message that has been written to the child output
+	// until it is either delivered or abandoned, and then acknowledges it
+	// upstream, returning false if we are shutting down.
+	resolve := func(ts message.Transaction, resChan chan error) bool {
+		var backOff backoff.BackOff
+		var resOut error
+		var inErrLoop bool
+
+		defer func() {
+			if inErrLoop {
+				atomic.AddInt64(&errLooped, -1)
+
+				// We're exiting our error loop, so (attempt to) interrupt the
+				// consumer.
+				select {
+				case errInterruptChan <- struct{}{}:
+				default:
+				}
+			}
+		}()
+
+		for !r.shutSig.ShouldCloseAtLeisure() {
+			var res error
+			select {
+			case res = <-resChan:
+			case <-r.shutSig.CloseAtLeisureChan():
+				return false
+			}
+
+			if res != nil {
+				if !inErrLoop {
+					inErrLoop = true
+					atomic.AddInt64(&errLooped, 1)
+				}
+
+				if backOff == nil {
+					backOff = r.backoffCtor()
+				}
+
+				nextBackoff := backOff.NextBackOff()
+				if nextBackoff == backoff.Stop {
+					r.log.Errorf("Failed to send message: %v\n", res)
+					resOut = errors.New("message failed to reach a target destination")
+					break
+				} else {
+					r.log.Warnf("Failed to send message: %v\n", res)
+				}
+				select {
+				case <-time.After(nextBackoff):
+				case <-r.shutSig.CloseAtLeisureChan():
+					return false
+				}
+				if r.hasExpired(ts.Payload) {
+					r.log.Warnf("Abandoning expired message after failing to send it: %v\n", res)
+					resOut = errors.New("message expired before reaching a target destination")
+					break
+				}
+
+				if !send(ts.Payload, resChan) {
+					return false
+				}
+			} else {
+				resOut = nil
+				break
+			}
+		}
+
+		if err := ts.Ack(ctx, resOut); err != nil && ctx.Err() != nil {
+			return false
+		}
+		return true
+	}
+
+	// When ordered by key the messages of each key are queued and written by
+	// a goroutine dedicated to the key, which exits once the queue of the key
+	// is drained. Messages are only written once the prior message of the same
+	// key has been resolved upstream, but a key that is held never holds the
+	// messages of other keys.
+	var queuesMut sync.Mutex
+	queues := map[string][]message.Transaction{}
+
+	// Each message of a key holds a slot of queueSlots until it is resolved.
+	queueSlots := make(chan struct{}, r.maxQueued)
+
+	writeKey := func(key string) {
+		for {
+			queuesMut.Lock()
+			queue := queues[key]
+			if len(queue) == 0 {
+				delete(queues, key)
+				queuesMut.Unlock()
+				return
+			}
+			tran := queue[0]
+			queues[key] = queue[1:]
+			queuesMut.Unlock()
+
+			rChan := make(chan error)
+			if !send(tran.Payload, rChan) || !resolve(tran, rChan) {
+				return
+			}
+			<-queueSlots
+		}
+	}
+
 	for !r.shutSig.ShouldCloseAtLeisure() {
 		// Do not consume another message while pending messages are being
-		// reattempted.
-		for atomic.LoadInt64(&errLooped) > 0 {
+		// reattempted, unless messages are ordered by key in which case only
+		// messages of the same key are held.
+		for r.orderingKey == nil && atomic.LoadInt64(&errLooped) > 0 {
 			select {
 			case <-errInterruptChan:
 			case <-time.After(time.Millisecond * 100):
@@ -147,6 +350,16 @@ func (r *indefiniteRetry) loop() {
 			}
 		}
 
+		// When ordered by key only consume another message once there is room
+		// to queue it.
+		if r.ordered && r.orderingKey != nil {
+			select {
+			case queueSlots <- struct{}{}:
+			case <-r.shutSig.CloseAtLeisureChan():
+				return
+			}
+		}
+
 		var tran message.Transaction
 		var open bool
 		select {
@@ -158,79 +371,42 @@ func (r *indefiniteRetry) loop() {
 			return
 		}
 
-		rChan := make(chan error)
-		select {
-		case r.transactionsOut <- message.NewTransaction(tran.Payload, rChan):
-		case <-r.shutSig.CloseAtLeisureChan():
-			return
-		}
+		if r.ordered && r.orderingKey != nil {
+			key := r.orderingKey.String(0, tran.Payload)
 
-		wg.Add(1)
-		go func(ts message.Transaction, resChan chan error) {
-			var backOff backoff.BackOff
-			var resOut error
-			var inErrLoop bool
-
-			defer func() {
-				wg.Done()
-				if inErrLoop {
-					atomic.AddInt64(&errLooped, -1)
-
-					// We're exiting our error loop, so (attempt to) interrupt the
-					// consumer.
-					select {
-					case errInterruptChan <- struct{}{}:
-					default:
-					}
-				}
-			}()
+			queuesMut.Lock()
+			queue, active := queues[key]
+			queues[key] = append(queue, tran)
+			queuesMut.Unlock()
 
-			for !r.shutSig.ShouldCloseAtLeisure() {
-				var res error
-				select {
-				case res = <-resChan:
-				case <-r.shutSig.CloseAtLeisureChan():
-					return
-				}
-
-				if res != nil {
-					if !inErrLoop {
-						inErrLoop = true
-						atomic.AddInt64(&errLooped, 1)
-					}
-
-					if backOff == nil {
-						backOff = r.backoffCtor()
-					}
-
-					nextBackoff := backOff.NextBackOff()
-					if nextBackoff == backoff.Stop {
-						r.log.Errorf("Failed to send message: %v\n", res)
-						resOut = errors.New("message failed to reach a target destination")
-						break
-					} else {
-						r.log.Warnf("Failed to send message: %v\n", res)
-					}
-					select {
-					case <-time.After(nextBackoff):
-					case <-r.shutSig.CloseAtLeisureChan():
-						return
-					}
-
-					select {
-					case r.transactionsOut <- message.NewTransaction(ts.Payload, resChan):
-					case <-r.shutSig.CloseAtLeisureChan():
-						return
-					}
-				} else {
-					resOut = nil
-					break
-				}
+			if !active {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					writeKey(key)
+				}()
 			}
+			continue
+		}
 
-			if err := ts.Ack(ctx, resOut); err != nil && ctx.Err() != nil {
+		rChan := make(chan error)
+		if !send(tran.Payload, rChan) {
+			return
+		}
+
+		if r.ordered {
+			// Without an ordering key every message is held until the prior
+			// message has been resolved.
+			if !resolve(tran, rChan) {
 				return
 			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(ts message.Transaction, resChan chan error) {
+			defer wg.Done()
+			resolve(ts, resChan)
 		}(tran, rChan)
 	}
 }
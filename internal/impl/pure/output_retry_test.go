@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/internal/bundle"
@@ -250,6 +251,275 @@ func ackForRetry(
 	}
 }
 
+func TestRetryOrderedByKey(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	conf := output.NewConfig()
+	conf.Type = "retry"
+
+	childConf := output.NewConfig()
+	conf.Retry.Output = &childConf
+	conf.Retry.Backoff.InitialInterval = "10us"
+	conf.Retry.Backoff.MaxInterval = "10us"
+	conf.Retry.Ordered = true
+	conf.Retry.OrderingKey = `${! meta("key") }`
+
+	output, err := bundle.AllOutputs.Init(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	ret, ok := output.(*indefiniteRetry)
+	require.True(t, ok)
+
+	mOut := &mock.OutputChanneled{}
+	ret.wrapped = mOut
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, ret.Consume(tChan))
+
+	newMsg := func(content, key string) *message.Batch {
+		msg := message.QuickBatch([][]byte{[]byte(content)})
+		msg.Get(0).MetaSet("key", key)
+		return msg
+	}
+
+	sendMsg := func(msg *message.Batch) chan error {
+		resChan := make(chan error)
+		select {
+		case tChan <- message.NewTransaction(msg, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return resChan
+	}
+
+	readMsg := func() (tran message.Transaction) {
+		select {
+		case tran = <-mOut.TChan:
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return
+	}
+
+	resA := sendMsg(newMsg("a", "foo"))
+	tranA := readMsg()
+	assert.Equal(t, "a", string(tranA.Payload.Get(0).Get()))
+
+	// Messages of a different key are not held.
+	resC := sendMsg(newMsg("c", "bar"))
+	tranC := readMsg()
+	assert.Equal(t, "c", string(tranC.Payload.Get(0).Get()))
+	require.NoError(t, tranC.Ack(ctx, nil))
+	require.NoError(t, <-resC)
+
+	resB := make(chan error)
+	go func() {
+		select {
+		case tChan <- message.NewTransaction(newMsg("b", "foo"), resB):
+		case <-ctx.Done():
+		}
+	}()
+
+	// The failed message must be retried before the next message of the same
+	// key is written.
+	require.NoError(t, tranA.Ack(ctx, component.ErrFailedSend))
+	tranA = readMsg()
+	assert.Equal(t, "a", string(tranA.Payload.Get(0).Get()))
+
+	select {
+	case tran := <-mOut.TChan:
+		t.Fatalf("Unexpected message written out of order: %s", tran.Payload.Get(0).Get())
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	require.NoError(t, tranA.Ack(ctx, nil))
+	require.NoError(t, <-resA)
+
+	tranB := readMsg()
+	assert.Equal(t, "b", string(tranB.Payload.Get(0).Get()))
+	require.NoError(t, tranB.Ack(ctx, nil))
+	require.NoError(t, <-resB)
+
+	output.CloseAsync()
+	require.NoError(t, output.WaitForClose(time.Second*30))
+}
+
+func TestRetryOrderedByKeyStalled(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	conf := output.NewConfig()
+	conf.Type = "retry"
+
+	childConf := output.NewConfig()
+	conf.Retry.Output = &childConf
+	conf.Retry.Backoff.InitialInterval = "10us"
+	conf.Retry.Backoff.MaxInterval = "10us"
+	conf.Retry.Ordered = true
+	conf.Retry.OrderingKey = `${! meta("key") }`
+
+	output, err := bundle.AllOutputs.Init(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	ret, ok := output.(*indefiniteRetry)
+	require.True(t, ok)
+
+	mOut := &mock.OutputChanneled{}
+	ret.wrapped = mOut
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, ret.Consume(tChan))
+
+	sendMsg := func(content, key string) chan error {
+		msg := message.QuickBatch([][]byte{[]byte(content)})
+		msg.Get(0).MetaSet("key", key)
+
+		resChan := make(chan error)
+		select {
+		case tChan <- message.NewTransaction(msg, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return resChan
+	}
+
+	readMsg := func() (tran message.Transaction) {
+		select {
+		case tran = <-mOut.TChan:
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return
+	}
+
+	// The first message of foo is never acknowledged, and so the messages of
+	// foo that follow it are held.
+	resFooA := sendMsg("foo a", "foo")
+	tranFooA := readMsg()
+	assert.Equal(t, "foo a", string(tranFooA.Payload.Get(0).Get()))
+
+	resFooB := sendMsg("foo b", "foo")
+	resFooC := sendMsg("foo c", "foo")
+
+	// Whilst foo is held the messages of bar continue to be written in order.
+	for _, content := range []string{"bar a", "bar b", "bar c"} {
+		resBar := sendMsg(content, "bar")
+		tranBar := readMsg()
+		assert.Equal(t, content, string(tranBar.Payload.Get(0).Get()))
+		require.NoError(t, tranBar.Ack(ctx, nil))
+		require.NoError(t, <-resBar)
+	}
+
+	require.NoError(t, tranFooA.Ack(ctx, nil))
+	require.NoError(t, <-resFooA)
+
+	for i, res := range []chan error{resFooB, resFooC} {
+		tran := readMsg()
+		assert.Equal(t, []string{"foo b", "foo c"}[i], string(tran.Payload.Get(0).Get()))
+		require.NoError(t, tran.Ack(ctx, nil))
+		require.NoError(t, <-res)
+	}
+
+	output.CloseAsync()
+	require.NoError(t, output.WaitForClose(time.Second*30))
+}
+
+func TestRetryOrderedByKeyMaxQueued(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	conf := output.NewConfig()
+	conf.Type = "retry"
+
+	childConf := output.NewConfig()
+	conf.Retry.Output = &childConf
+	conf.Retry.Ordered = true
+	conf.Retry.OrderingKey = `${! meta("key") }`
+
+	output, err := bundle.AllOutputs.Init(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	ret, ok := output.(*indefiniteRetry)
+	require.True(t, ok)
+
+	mOut := &mock.OutputChanneled{}
+	ret.wrapped = mOut
+	ret.maxQueued = 3
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, ret.Consume(tChan))
+
+	newTran := func(content, key string) (message.Transaction, chan error) {
+		msg := message.QuickBatch([][]byte{[]byte(content)})
+		msg.Get(0).MetaSet("key", key)
+		resChan := make(chan error)
+		return message.NewTransaction(msg, resChan), resChan
+	}
+
+	sendMsg := func(content, key string) chan error {
+		tran, resChan := newTran(content, key)
+		select {
+		case tChan <- tran:
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return resChan
+	}
+
+	readMsg := func() (tran message.Transaction) {
+		select {
+		case tran = <-mOut.TChan:
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return
+	}
+
+	// The first message of foo is held, and the messages queued behind it
+	// fill the remaining slots.
+	resFooA := sendMsg("foo a", "foo")
+	tranFooA := readMsg()
+	resFooB := sendMsg("foo b", "foo")
+	resFooC := sendMsg("foo c", "foo")
+
+	// No further messages are consumed, even of other keys, until a held
+	// message is resolved.
+	tranBar, resBar := newTran("bar a", "bar")
+	select {
+	case tChan <- tranBar:
+		t.Fatal("message consumed beyond the queue limit")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	require.NoError(t, tranFooA.Ack(ctx, nil))
+	require.NoError(t, <-resFooA)
+
+	select {
+	case tChan <- tranBar:
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	var seen []string
+	resChans := map[string]chan error{
+		"foo b": resFooB,
+		"foo c": resFooC,
+		"bar a": resBar,
+	}
+	for i := 0; i < 3; i++ {
+		tran := readMsg()
+		content := string(tran.Payload.Get(0).Get())
+		seen = append(seen, content)
+		require.NoError(t, tran.Ack(ctx, nil))
+		require.NoError(t, <-resChans[content])
+	}
+	assert.ElementsMatch(t, []string{"foo b", "foo c", "bar a"}, seen)
+
+	output.CloseAsync()
+	require.NoError(t, output.WaitForClose(time.Second*30))
+}
+
 func TestRetryParallel(t *testing.T) {
 	conf := output.NewConfig()
 	conf.Type = "retry"
@@ -306,3 +576,56 @@ func TestRetryParallel(t *testing.T) {
 	output.CloseAsync()
 	require.NoError(t, output.WaitForClose(time.Second*30))
 }
+
+func TestRetryExpiry(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	conf := output.NewConfig()
+	conf.Type = "retry"
+
+	childConf := output.NewConfig()
+	conf.Retry.Output = &childConf
+	conf.Retry.Backoff.InitialInterval = "10ms"
+	conf.Retry.Backoff.MaxInterval = "10ms"
+	conf.Retry.ExpiresAt = `${! meta("expires_at") }`
+
+	output, err := bundle.AllOutputs.Init(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	ret, ok := output.(*indefiniteRetry)
+	require.True(t, ok)
+
+	mOut := &mock.OutputChanneled{}
+	ret.wrapped = mOut
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, ret.Consume(tChan))
+
+	testMsg := message.QuickBatch([][]byte{[]byte("hello world")})
+	testMsg.Get(0).MetaSet("expires_at", time.Now().Add(time.Millisecond*200).Format(time.RFC3339Nano))
+
+	resChan := make(chan error)
+	select {
+	case tChan <- message.NewTransaction(testMsg, resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	for {
+		select {
+		case tran := <-mOut.TChan:
+			require.NoError(t, tran.Ack(ctx, component.ErrFailedSend))
+			continue
+		case err := <-resChan:
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "expired")
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+		break
+	}
+
+	ret.CloseAsync()
+	require.NoError(t, ret.WaitForClose(time.Second))
+}
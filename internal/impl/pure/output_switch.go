@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/cenkalti/backoff/v4"
 
 	"github.com/benthosdev/benthos/v4/internal/batch"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
@@ -97,6 +98,14 @@ behavior is false, which will drop the message.`,
 					"continue",
 					"Indicates whether, if this case passes for a message, the next case should also be tested.",
 				).HasDefault(false).Advanced(),
+				docs.FieldInt(
+					"max_retries",
+					"The maximum number of times a failed send to the case output is retried before giving up, at which point the message is routed to the `dead_letter` output if one is set, otherwise the error is propagated back to the input level. If `0` then failed sends are retried indefinitely when `retry_until_success` is `true`, and are not retried otherwise.",
+				).HasDefault(0).Advanced().AtVersion("4.2.0"),
+				docs.FieldOutput(
+					"dead_letter",
+					"An optional [output](/docs/components/outputs/about/) to route messages to when they fail to be sent to the case output, after any retries have been exhausted. Messages routed to the dead letter output are given the metadata fields `fallback_error`, `fallback_attempts` and `fallback_label`, as described in the [`fallback` output docs](/docs/components/outputs/fallback#metadata).",
+				).Optional().Advanced().AtVersion("4.2.0"),
 			).HasDefault([]interface{}{}),
 		).LinterFunc(func(ctx docs.LintContext, line, col int, value interface{}) []docs.Lint {
 			if _, ok := value.(map[string]interface{}); !ok {
@@ -108,6 +117,9 @@ behavior is false, which will drop the message.`,
 				return nil
 			}
 			for _, cObj := range gObj.S("cases").Children() {
+				if maxRetries, _ := cObj.S("max_retries").Data().(int); maxRetries > 0 {
+					continue
+				}
 				typeStr, _ := cObj.S("output", "type").Data().(string)
 				isReject := cObj.Exists("output", "reject")
 				if typeStr == "reject" || isReject {
@@ -176,6 +188,33 @@ output:
           gcp_pubsub:
             project: people
             topic: that_i_dont_want_to_hang_with
+`,
+			},
+			{
+				Title: "Per-Case Delivery Guarantees",
+				Summary: `
+Cases can have their own delivery guarantees by setting ` + "`max_retries`" + `, which limits how many times a failed send is retried, and ` + "`dead_letter`" + `, which is an output that messages are routed to once the retries of a case are exhausted.
+
+In the following example messages destined for the ` + "`orders`" + ` topic are retried indefinitely, whereas messages destined for an HTTP endpoint are retried three times before being written to a file instead.`,
+				Config: `
+output:
+  switch:
+    retry_until_success: true
+    cases:
+      - check: this.type == "order"
+        output:
+          kafka:
+            addresses: [ localhost:9092 ]
+            topic: orders
+
+      - output:
+          http_client:
+            url: http://localhost:4195/post
+        max_retries: 3
+        dead_letter:
+          file:
+            path: ./failed_events.jsonl
+            codec: lines
 `,
 			},
 		},
@@ -225,11 +264,32 @@ func newSwitchOutput(conf output.SwitchConfig, mgr bundle.NewManagement) (output
 		if o.outputs[i], err = oMgr.NewOutput(cConf.Output); err != nil {
 			return nil, err
 		}
-		if conf.RetryUntilSuccess {
-			if o.outputs[i], err = RetryOutputIndefinitely(oMgr, o.outputs[i]); err != nil {
+		if cConf.MaxRetries < 0 {
+			return nil, fmt.Errorf("case '%v' max_retries must not be negative, got %v", i, cConf.MaxRetries)
+		}
+		if conf.RetryUntilSuccess || cConf.MaxRetries > 0 {
+			var boffCtor func() backoff.BackOff
+			if cConf.MaxRetries > 0 {
+				retryConf := output.NewRetryConfig()
+				retryConf.MaxRetries = uint64(cConf.MaxRetries)
+				if boffCtor, err = retryConf.GetCtor(); err != nil {
+					return nil, err
+				}
+			}
+			if o.outputs[i], err = newIndefiniteRetry(oMgr, boffCtor, o.outputs[i]); err != nil {
 				return nil, fmt.Errorf("failed to create case '%v' output type '%v': %v", i, cConf.Output.Type, err)
 			}
 		}
+		if cConf.DeadLetter != nil {
+			dlMgr := mgr.IntoPath("switch", strconv.Itoa(i), "dead_letter")
+			deadLetter, err := dlMgr.NewOutput(*cConf.DeadLetter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create case '%v' dead letter output type '%v': %v", i, cConf.DeadLetter.Type, err)
+			}
+			if o.outputs[i], err = newFallbackBroker([]output.Streamed{o.outputs[i], deadLetter}, []string{cConf.Output.Label, cConf.DeadLetter.Label}); err != nil {
+				return nil, err
+			}
+		}
 		if len(cConf.Check) > 0 {
 			if o.checks[i], err = mgr.BloblEnvironment().NewMapping(cConf.Check); err != nil {
 				return nil, fmt.Errorf("failed to parse case '%v' check mapping: %v", i, err)
@@ -396,6 +456,16 @@ func (o *switchOutput) loop() {
 	shutCtx, done := o.shutSig.CloseAtLeisureCtx(context.Background())
 	defer done()
 
+	// The slice of targets is reused across transactions, whereas the parts
+	// of each target are retained by the dispatched batch and are therefore
+	// allocated for each transaction.
+	outputTargets := make([][]*message.Part, len(o.checks))
+	resetTargets := func() {
+		for i := range outputTargets {
+			outputTargets[i] = nil
+		}
+	}
+
 	for !o.shutSig.ShouldCloseAtLeisure() {
 		var ts message.Transaction
 		var open bool
@@ -411,7 +481,6 @@ func (o *switchOutput) loop() {
 
 		group, trackedMsg := message.NewSortGroup(ts.Payload)
 
-		outputTargets := make([][]*message.Part, len(o.checks))
 		if checksErr := trackedMsg.Iter(func(i int, p *message.Part) error {
 			routedAtLeastOnce := false
 			for j, exe := range o.checks {
@@ -436,6 +505,7 @@ func (o *switchOutput) loop() {
 			}
 			return nil
 		}); checksErr != nil {
+			resetTargets()
 			if err := ts.Ack(shutCtx, checksErr); err != nil && shutCtx.Err() != nil {
 				return
 			}
@@ -452,6 +522,7 @@ func (o *switchOutput) loop() {
 			}
 			return ackErr
 		})
+		resetTargets()
 	}
 }
 
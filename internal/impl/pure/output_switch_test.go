@@ -228,6 +228,69 @@ func TestSwitchBatchNoRetries(t *testing.T) {
 	require.NoError(t, s.WaitForClose(time.Second*5))
 }
 
+func TestSwitchCaseRetriesDeadLetter(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		deadLetter bool
+	}{
+		{name: "with dead letter", deadLetter: true},
+		{name: "without dead letter", deadLetter: false},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := output.NewConfig()
+			conf.Switch.RetryUntilSuccess = false
+
+			errOut := output.NewConfig()
+			errOut.Type = "reject"
+			errOut.Reject = "meow"
+			errCase := output.NewSwitchConfigCase()
+			errCase.Output = errOut
+			errCase.MaxRetries = 1
+			if test.deadLetter {
+				dlOut := output.NewConfig()
+				dlOut.Type = "drop"
+				errCase.DeadLetter = &dlOut
+			}
+			conf.Switch.Cases = append(conf.Switch.Cases, errCase)
+
+			okOut := output.NewConfig()
+			okOut.Type = "drop"
+			conf.Switch.Cases = append(conf.Switch.Cases, output.SwitchConfigCase{
+				Check:  `root = false`,
+				Output: okOut,
+			})
+
+			s, err := newSwitchOutput(conf.Switch, mock.NewManager())
+			require.NoError(t, err)
+
+			readChan := make(chan message.Transaction)
+			resChan := make(chan error)
+			require.NoError(t, s.Consume(readChan))
+
+			select {
+			case readChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("hello world")}), resChan):
+			case <-time.After(time.Second):
+				t.Fatal("Timed out waiting for broker send")
+			}
+
+			select {
+			case res := <-resChan:
+				if test.deadLetter {
+					assert.NoError(t, res)
+				} else {
+					assert.Error(t, res)
+				}
+			case <-time.After(time.Second * 5):
+				t.Fatal("Timed out responding to broker")
+			}
+
+			s.CloseAsync()
+			require.NoError(t, s.WaitForClose(time.Second*5))
+		})
+	}
+}
+
 func TestSwitchBatchNoRetriesBatchErr(t *testing.T) {
 	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
 	defer done()
@@ -545,6 +608,86 @@ func TestSwitchBatchSplit(t *testing.T) {
 	assert.NoError(t, s.WaitForClose(time.Second*5))
 }
 
+func TestSwitchBatchesInFlight(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	mockOutputs := []*mock.OutputChanneled{{}, {}}
+
+	conf := output.NewConfig()
+	for i := 0; i < len(mockOutputs); i++ {
+		conf.Switch.Cases = append(conf.Switch.Cases, output.NewSwitchConfigCase())
+	}
+	conf.Switch.Cases[0].Check = `this.foo == "bar"`
+	conf.Switch.Cases[1].Check = `this.foo == "baz"`
+
+	s := newSwitch(t, conf, mockOutputs)
+
+	readChan := make(chan message.Transaction)
+	resChan := make(chan error, 2)
+
+	require.NoError(t, s.Consume(readChan))
+
+	send := func(contents ...string) {
+		t.Helper()
+		var parts [][]byte
+		for _, c := range contents {
+			parts = append(parts, []byte(c))
+		}
+		select {
+		case readChan <- message.NewTransaction(message.QuickBatch(parts), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting to send")
+		}
+	}
+
+	receive := func(i int) message.Transaction {
+		t.Helper()
+		select {
+		case ts := <-mockOutputs[i].TChan:
+			return ts
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for output to propagate")
+		}
+		return message.Transaction{}
+	}
+
+	// The first batch remains in flight whilst the second is routed, and
+	// therefore must not share its parts.
+	send(`{"foo":"bar","n":0}`, `{"foo":"bar","n":1}`)
+	first := receive(0)
+
+	send(`{"foo":"bar","n":2}`, `{"foo":"baz","n":3}`)
+	second := receive(0)
+	third := receive(1)
+
+	assert.Equal(t, [][]byte{
+		[]byte(`{"foo":"bar","n":0}`),
+		[]byte(`{"foo":"bar","n":1}`),
+	}, message.GetAllBytes(first.Payload))
+	assert.Equal(t, [][]byte{
+		[]byte(`{"foo":"bar","n":2}`),
+	}, message.GetAllBytes(second.Payload))
+	assert.Equal(t, [][]byte{
+		[]byte(`{"foo":"baz","n":3}`),
+	}, message.GetAllBytes(third.Payload))
+
+	for _, ts := range []message.Transaction{first, second, third} {
+		require.NoError(t, ts.Ack(ctx, nil))
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case res := <-resChan:
+			require.NoError(t, res)
+		case <-time.After(time.Second):
+			t.Error("Timed out responding to output")
+		}
+	}
+
+	s.CloseAsync()
+	assert.NoError(t, s.WaitForClose(time.Second*5))
+}
+
 func TestSwitchBatchGroup(t *testing.T) {
 	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
 	defer done()
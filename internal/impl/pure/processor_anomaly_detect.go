@@ -0,0 +1,282 @@
+package pure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func anomalyDetectProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.2.0").
+		Summary("Tracks rolling statistics of a numeric value for each key of a stream and flags messages where the value deviates from them beyond a threshold, allowing spikes and sudden changes to be detected inline.").
+		Description(`
+For each key the processor maintains an exponentially weighted moving average (EWMA) of the value along with an exponentially weighted standard deviation, where `+"`alpha`"+` determines how quickly the statistics adapt to new values. The score of a message is the number of standard deviations that its value is away from the average of its key prior to the message being observed. Once a key has observed `+"`warmup`"+` values each message of that key is given the metadata field `+"`anomaly_score`"+`, and messages where the absolute score exceeds `+"`threshold`"+` are also given the metadata field `+"`anomaly`"+` with the value `+"`true`"+` and counted by the metric `+"`anomaly_detected`"+`.
+
+When `+"`rate_of_change`"+` is `+"`true`"+` the statistics are tracked for the difference between each value and the previous value of the same key rather than the value itself, which detects sudden jumps in a value that drifts over time.
+
+Messages where the value cannot be parsed as a number remain unchanged and are flagged as having failed.
+
+### Storing Statistics
+
+By default statistics are held in memory by each processor, and therefore each processing thread of a pipeline maintains its own statistics. When a `+"`cache`"+` is specified the statistics of each key are stored within it instead, which allows them to be shared between threads and instances, and to survive restarts. Updates of the statistics within a cache are not atomic, and therefore concurrent updates of the same key may occasionally be lost.`).
+		Field(service.NewInterpolatedStringField("value").
+			Description("The numeric value to track for each message.").
+			Example(`${! json("temperature") }`).Example(`${! meta("latency_ms") }`)).
+		Field(service.NewInterpolatedStringField("key").
+			Description("A key that determines which statistics each message is scored against, allowing separate statistics to be tracked for each source of data.").
+			Example(`${! json("sensor_id") }`).
+			Default("")).
+		Field(service.NewFloatField("threshold").
+			Description("The number of standard deviations that a value must be away from the average in order to be flagged as an anomaly.").
+			Default(3.0)).
+		Field(service.NewFloatField("alpha").
+			Description("The smoothing factor of the rolling statistics between 0 and 1, where greater values adapt to new values more quickly.").
+			Default(0.1)).
+		Field(service.NewIntField("warmup").
+			Description("The number of values that a key must observe before its messages are scored.").
+			Default(10)).
+		Field(service.NewBoolField("rate_of_change").
+			Description("Whether to track the difference between each value and the previous value of the same key rather than the value itself.").
+			Default(false)).
+		Field(service.NewStringField("cache").
+			Description("An optional [cache resource](/docs/components/caches/about) to store the statistics of each key within.").
+			Optional().
+			Advanced()).
+		Example(
+			"Alerting on Latency Spikes",
+			"In this example the response latency of each service is tracked and messages with an unusually high or low latency are also sent to an alerting endpoint.",
+			`
+pipeline:
+  processors:
+    - anomaly_detect:
+        value: ${! json("latency_ms") }
+        key: ${! json("service") }
+        threshold: 4
+
+output:
+  switch:
+    cases:
+      - check: meta("anomaly") == "true"
+        continue: true
+        output:
+          http_client:
+            url: http://example.com/alerts
+      - output:
+          file:
+            path: ./latencies.jsonl
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"anomaly_detect", anomalyDetectProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newAnomalyDetectFromParsed(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ewmaStats holds exponentially weighted statistics of the values of a key, it
+// is serialised as JSON when stored within a cache.
+type ewmaStats struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Count    int64   `json:"count"`
+	Last     float64 `json:"last"`
+	HasLast  bool    `json:"has_last"`
+}
+
+// score returns the number of standard deviations that v is away from the
+// mean.
+func (s *ewmaStats) score(v float64) float64 {
+	diff := v - s.Mean
+	if diff == 0 {
+		return 0
+	}
+	if s.Variance == 0 {
+		return math.Copysign(math.Inf(1), diff)
+	}
+	return diff / math.Sqrt(s.Variance)
+}
+
+// observe adds v to the statistics.
+func (s *ewmaStats) observe(v, alpha float64) {
+	if s.Count == 0 {
+		s.Mean = v
+		s.Variance = 0
+	} else {
+		diff := v - s.Mean
+		incr := alpha * diff
+		s.Mean += incr
+		s.Variance = (1 - alpha) * (s.Variance + diff*incr)
+	}
+	s.Count++
+}
+
+//------------------------------------------------------------------------------
+
+type anomalyDetectProc struct {
+	value        *service.InterpolatedString
+	key          *service.InterpolatedString
+	threshold    float64
+	alpha        float64
+	warmup       int64
+	rateOfChange bool
+	cache        string
+
+	statsMut sync.Mutex
+	stats    map[string]*ewmaStats
+
+	mgr       *service.Resources
+	mDetected *service.MetricCounter
+}
+
+func newAnomalyDetectFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*anomalyDetectProc, error) {
+	a := &anomalyDetectProc{
+		stats:     map[string]*ewmaStats{},
+		mgr:       mgr,
+		mDetected: mgr.Metrics().NewCounter("anomaly_detected"),
+	}
+
+	var err error
+	if a.value, err = conf.FieldInterpolatedString("value"); err != nil {
+		return nil, err
+	}
+	if a.key, err = conf.FieldInterpolatedString("key"); err != nil {
+		return nil, err
+	}
+	if a.threshold, err = conf.FieldFloat("threshold"); err != nil {
+		return nil, err
+	}
+	if a.threshold <= 0 {
+		return nil, fmt.Errorf("threshold must be greater than zero, got %v", a.threshold)
+	}
+	if a.alpha, err = conf.FieldFloat("alpha"); err != nil {
+		return nil, err
+	}
+	if a.alpha <= 0 || a.alpha > 1 {
+		return nil, fmt.Errorf("alpha must be greater than zero and no greater than one, got %v", a.alpha)
+	}
+	warmup, err := conf.FieldInt("warmup")
+	if err != nil {
+		return nil, err
+	}
+	if warmup < 1 {
+		return nil, fmt.Errorf("warmup must be greater than zero, got %v", warmup)
+	}
+	a.warmup = int64(warmup)
+	if a.rateOfChange, err = conf.FieldBool("rate_of_change"); err != nil {
+		return nil, err
+	}
+	if conf.Contains("cache") {
+		if a.cache, err = conf.FieldString("cache"); err != nil {
+			return nil, err
+		}
+		if !mgr.HasCache(a.cache) {
+			return nil, fmt.Errorf("cache resource '%v' was not found", a.cache)
+		}
+	}
+	return a, nil
+}
+
+// observe adds a value to the statistics of a key and returns the score of the
+// value, or false if the key has not yet finished warming up.
+func (a *anomalyDetectProc) observe(stats *ewmaStats, v float64) (float64, bool) {
+	if a.rateOfChange {
+		prev, hasPrev := stats.Last, stats.HasLast
+		stats.Last, stats.HasLast = v, true
+		if !hasPrev {
+			return 0, false
+		}
+		v -= prev
+	}
+
+	var score float64
+	scored := stats.Count >= a.warmup
+	if scored {
+		score = stats.score(v)
+	}
+	stats.observe(v, a.alpha)
+	return score, scored
+}
+
+func (a *anomalyDetectProc) observeCached(ctx context.Context, key string, v float64) (score float64, scored bool, err error) {
+	var cErr error
+	if err = a.mgr.AccessCache(ctx, a.cache, func(c service.Cache) {
+		var stats ewmaStats
+		var statsBytes []byte
+		if statsBytes, cErr = c.Get(ctx, key); cErr == nil {
+			if cErr = json.Unmarshal(statsBytes, &stats); cErr != nil {
+				cErr = fmt.Errorf("failed to parse statistics of key '%v': %w", key, cErr)
+				return
+			}
+		} else if !errors.Is(cErr, service.ErrKeyNotFound) {
+			return
+		}
+
+		score, scored = a.observe(&stats, v)
+		if statsBytes, cErr = json.Marshal(stats); cErr != nil {
+			return
+		}
+		cErr = c.Set(ctx, key, statsBytes, nil)
+	}); err != nil {
+		return
+	}
+	err = cErr
+	return
+}
+
+func (a *anomalyDetectProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	valueStr := strings.TrimSpace(a.value.String(msg))
+	v, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse value '%v' as a number", valueStr)
+	}
+	key := a.key.String(msg)
+
+	var score float64
+	var scored bool
+	if a.cache != "" {
+		if score, scored, err = a.observeCached(ctx, key, v); err != nil {
+			return nil, err
+		}
+	} else {
+		a.statsMut.Lock()
+		stats, exists := a.stats[key]
+		if !exists {
+			stats = &ewmaStats{}
+			a.stats[key] = stats
+		}
+		score, scored = a.observe(stats, v)
+		a.statsMut.Unlock()
+	}
+	if !scored {
+		return service.MessageBatch{msg}, nil
+	}
+
+	msg.MetaSet("anomaly_score", strconv.FormatFloat(score, 'f', -1, 64))
+	if math.Abs(score) > a.threshold {
+		a.mDetected.Incr(1)
+		msg.MetaSet("anomaly", "true")
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (a *anomalyDetectProc) Close(ctx context.Context) error {
+	return nil
+}
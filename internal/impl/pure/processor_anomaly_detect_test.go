@@ -0,0 +1,108 @@
+package pure
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestAnomalyDetectSpike(t *testing.T) {
+	conf, err := anomalyDetectProcConfig().ParseYAML(`
+value: ${! json("value") }
+key: ${! json("key") }
+alpha: 0.5
+warmup: 3
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newAnomalyDetectFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	type result struct {
+		score   string
+		anomaly string
+	}
+
+	var results []result
+	for _, content := range []string{
+		`{"key":"a","value":10}`,
+		`{"key":"a","value":12}`,
+		`{"key":"b","value":500}`,
+		`{"key":"a","value":10}`,
+		`{"key":"a","value":11}`,
+		`{"key":"a","value":50}`,
+	} {
+		batch, err := proc.Process(context.Background(), service.NewMessage([]byte(content)))
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		var res result
+		res.score, _ = batch[0].MetaGet("anomaly_score")
+		res.anomaly, _ = batch[0].MetaGet("anomaly")
+		results = append(results, res)
+	}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, result{}, results[i], i)
+	}
+
+	score, err := strconv.ParseFloat(results[4].score, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.577, score, 0.001)
+	assert.Equal(t, "", results[4].anomaly)
+
+	score, err = strconv.ParseFloat(results[5].score, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 59.34, score, 0.01)
+	assert.Equal(t, "true", results[5].anomaly)
+
+	_, err = proc.Process(context.Background(), service.NewMessage([]byte(`{"key":"a","value":"nope"}`)))
+	require.Error(t, err)
+}
+
+func TestAnomalyDetectRateOfChange(t *testing.T) {
+	conf, err := anomalyDetectProcConfig().ParseYAML(`
+value: ${! content() }
+rate_of_change: true
+warmup: 3
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newAnomalyDetectFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	var scores, anomalies []string
+	for _, v := range []string{"1", "2", "3", "4", "5", "6", "20"} {
+		batch, err := proc.Process(context.Background(), service.NewMessage([]byte(v)))
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		score, _ := batch[0].MetaGet("anomaly_score")
+		anomaly, _ := batch[0].MetaGet("anomaly")
+		scores = append(scores, score)
+		anomalies = append(anomalies, anomaly)
+	}
+
+	assert.Equal(t, []string{"", "", "", "", "0", "0", "+Inf"}, scores)
+	assert.Equal(t, []string{"", "", "", "", "", "", "true"}, anomalies)
+}
+
+func TestAnomalyDetectBadConfig(t *testing.T) {
+	for _, c := range []string{
+		"value: foo\nalpha: 0",
+		"value: foo\nthreshold: -1",
+		"value: foo\nwarmup: 0",
+		"value: foo\ncache: nope",
+	} {
+		conf, err := anomalyDetectProcConfig().ParseYAML(c, nil)
+		require.NoError(t, err)
+
+		_, err = newAnomalyDetectFromParsed(conf, service.MockResources())
+		require.Error(t, err, c)
+	}
+}
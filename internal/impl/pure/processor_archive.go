@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/bufpool"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
@@ -73,7 +74,8 @@ type archiveFunc func(hFunc headerFunc, msg service.MessageBatch) (*service.Mess
 type headerFunc func(index int, body *service.Message) os.FileInfo
 
 func tarArchive(hFunc headerFunc, msg service.MessageBatch) (*service.Message, error) {
-	buf := &bytes.Buffer{}
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
 	tw := tar.NewWriter(buf)
 
 	for i, part := range msg {
@@ -95,12 +97,13 @@ func tarArchive(hFunc headerFunc, msg service.MessageBatch) (*service.Message, e
 
 	tw.Close()
 	newPart := msg[0].Copy()
-	newPart.SetBytes(buf.Bytes())
+	newPart.SetBytes(bufpool.Bytes(buf))
 	return newPart, nil
 }
 
 func zipArchive(hFunc headerFunc, msg service.MessageBatch) (*service.Message, error) {
-	buf := &bytes.Buffer{}
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
 	zw := zip.NewWriter(buf)
 
 	for i, part := range msg {
@@ -126,7 +129,7 @@ func zipArchive(hFunc headerFunc, msg service.MessageBatch) (*service.Message, e
 	zw.Close()
 
 	newPart := msg[0].Copy()
-	newPart.SetBytes(buf.Bytes())
+	newPart.SetBytes(bufpool.Bytes(buf))
 	return newPart, nil
 }
 
@@ -1,16 +1,18 @@
 package pure
 
 import (
-	"bytes"
 	"compress/flate"
 	"compress/gzip"
 	"compress/zlib"
 	"context"
 	"fmt"
+	"io"
+	"sync"
 
 	"github.com/golang/snappy"
 	"github.com/pierrec/lz4/v4"
 
+	"github.com/benthosdev/benthos/v4/internal/bufpool"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/docs"
@@ -49,52 +51,75 @@ The 'level' field might not apply to all algorithms.`,
 
 type compressFunc func(level int, bytes []byte) ([]byte, error)
 
-func gzipCompress(level int, b []byte) ([]byte, error) {
-	buf := &bytes.Buffer{}
-	w, err := gzip.NewWriterLevel(buf, level)
-	if err != nil {
-		return nil, err
-	}
+// The gzip, zlib and flate compression levels share the same range, writers
+// are pooled per level as they allocate large internal buffers.
+const (
+	minCompressLevel = flate.HuffmanOnly
+	maxCompressLevel = flate.BestCompression
+)
 
-	if _, err = w.Write(b); err != nil {
-		w.Close()
-		return nil, err
-	}
-	// Must flush writer before calling buf.Bytes()
-	w.Close()
-	return buf.Bytes(), nil
+type compressWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
 }
 
-func zlibCompress(level int, b []byte) ([]byte, error) {
-	buf := &bytes.Buffer{}
-	w, err := zlib.NewWriterLevel(buf, level)
-	if err != nil {
-		return nil, err
-	}
+type compressWriterPools [maxCompressLevel - minCompressLevel + 1]sync.Pool
 
-	if _, err = w.Write(b); err != nil {
-		w.Close()
-		return nil, err
+var gzipWriterPools, zlibWriterPools, flateWriterPools compressWriterPools
+
+func pooledCompress(pools *compressWriterPools, newWriter func(w io.Writer, level int) (compressWriter, error), level int, b []byte) ([]byte, error) {
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+
+	var pool *sync.Pool
+	if level >= minCompressLevel && level <= maxCompressLevel {
+		pool = &pools[level-minCompressLevel]
 	}
-	// Must flush writer before calling buf.Bytes()
-	w.Close()
-	return buf.Bytes(), nil
-}
 
-func flateCompress(level int, b []byte) ([]byte, error) {
-	buf := &bytes.Buffer{}
-	w, err := flate.NewWriter(buf, level)
-	if err != nil {
-		return nil, err
+	var w compressWriter
+	if pool != nil {
+		if pw, ok := pool.Get().(compressWriter); ok {
+			pw.Reset(buf)
+			w = pw
+		}
+	}
+	if w == nil {
+		var err error
+		if w, err = newWriter(buf, level); err != nil {
+			return nil, err
+		}
 	}
 
-	if _, err = w.Write(b); err != nil {
+	if _, err := w.Write(b); err != nil {
 		w.Close()
 		return nil, err
 	}
 	// Must flush writer before calling buf.Bytes()
-	w.Close()
-	return buf.Bytes(), nil
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if pool != nil {
+		pool.Put(w)
+	}
+	return bufpool.Bytes(buf), nil
+}
+
+func gzipCompress(level int, b []byte) ([]byte, error) {
+	return pooledCompress(&gzipWriterPools, func(w io.Writer, level int) (compressWriter, error) {
+		return gzip.NewWriterLevel(w, level)
+	}, level, b)
+}
+
+func zlibCompress(level int, b []byte) ([]byte, error) {
+	return pooledCompress(&zlibWriterPools, func(w io.Writer, level int) (compressWriter, error) {
+		return zlib.NewWriterLevel(w, level)
+	}, level, b)
+}
+
+func flateCompress(level int, b []byte) ([]byte, error) {
+	return pooledCompress(&flateWriterPools, func(w io.Writer, level int) (compressWriter, error) {
+		return flate.NewWriter(w, level)
+	}, level, b)
 }
 
 func snappyCompress(level int, b []byte) ([]byte, error) {
@@ -102,7 +127,9 @@ func snappyCompress(level int, b []byte) ([]byte, error) {
 }
 
 func lz4Compress(level int, b []byte) ([]byte, error) {
-	buf := &bytes.Buffer{}
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+
 	w := lz4.NewWriter(buf)
 	if level > 0 {
 		// The default compression level is 0 (lz4.Fast)
@@ -118,7 +145,7 @@ func lz4Compress(level int, b []byte) ([]byte, error) {
 	// Must flush writer before calling buf.Bytes()
 	w.Close()
 
-	return buf.Bytes(), nil
+	return bufpool.Bytes(buf), nil
 }
 
 func strToCompressor(str string) (compressFunc, error) {
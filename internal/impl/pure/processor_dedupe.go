@@ -42,11 +42,38 @@ This processor enacts on individual messages only, in order to perform a dedupli
 
 Performing deduplication on a stream using a distributed cache voids any at-least-once guarantees that it previously had. This is because the cache will preserve message signatures even if the message fails to leave the Benthos pipeline, which would cause message loss in the event of an outage at the output sink followed by a restart of the Benthos instance (or a server crash, etc).
 
-This problem can be mitigated by using an in-memory cache and distributing messages to horizontally scaled Benthos pipelines partitioned by the deduplication key. However, in situations where at-least-once delivery guarantees are important it is worth avoiding deduplication in favour of implement idempotent behaviour at the edge of your stream pipelines.`,
+This problem can be mitigated by using an in-memory cache and distributing messages to horizontally scaled Benthos pipelines partitioned by the deduplication key. However, in situations where at-least-once delivery guarantees are important it is worth avoiding deduplication in favour of implement idempotent behaviour at the edge of your stream pipelines.
+
+## Checkpoint Mode
+
+When the ` + "`mode`" + ` is ` + "`checkpoint`" + ` messages are deduplicated by the position that they were consumed from, as identified by the metadata added by the input, rather than by a key. This allows messages that are replayed by an input after a crash, but had already been delivered before it, to be dropped without the need for a key to be derived from their contents. The following identifiers are supported:
+
+- The ` + "`kafka_topic`, `kafka_partition` and `kafka_offset`" + ` of the ` + "`kafka` and `kafka_franz`" + ` inputs.
+- The ` + "`nats_stream` and `nats_sequence_stream`" + ` of the ` + "`nats_jetstream`" + ` input.
+- The ` + "`nats_stream_subject` and `nats_stream_sequence`" + ` of the ` + "`nats_stream`" + ` input.
+- The ` + "`sqs_message_id`" + ` of the ` + "`aws_sqs`" + ` input.
+
+In this mode the processor only reads checkpoints from the cache, and they are written by a ` + "[`dedupe_checkpoint` output](/docs/components/outputs/dedupe_checkpoint)" + ` targeting the same cache once messages have been delivered, which must therefore wrap the output of the stream. This ensures that messages which were in flight during a crash, and were never delivered, are not dropped when they are replayed.
+
+Offsets and sequence numbers are tracked as a watermark for each partition, which is stored within the cache, and messages at or below the watermark of their partition are dropped. The watermark is advanced to the greatest delivered offset that is below every offset of the partition still in flight, which only requires a single cache key per partition. Messages that are removed after this processor, for example by a filter, no longer hold back the watermark of their partition once their transaction has been acknowledged, and the metadata this processor relies on must be preserved until the output. Message ids, which have no order, are instead stored within the cache as individual keys once their messages are delivered. Messages that have none of these identifiers are deduplicated by the ` + "`key`" + ` when one is specified, and are otherwise passed through unchanged.
+
+## Bloom Filter
+
+For very high throughput streams where a cache round trip for each message is too costly the processor can instead deduplicate messages with an in-memory bloom filter by setting ` + "`bloom_filter.capacity`" + ` to a value greater than zero, in which case the field ` + "`cache`" + ` must be left empty.
+
+The filter remembers a sliding window of the most recently observed keys, at least the last ` + "`capacity`" + ` keys and up to twice that number, with a memory footprint bounded by the capacity and the configured ` + "`false_positive_rate`" + `. A false positive results in a message that has not been seen before being dropped, and therefore the rate should be chosen with the volume of the stream in mind. Since the filter is held in memory it is not shared between processors or instances of Benthos, and it is lost when Benthos restarts.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("cache", "The [`cache` resource](/docs/components/caches/about) to target with this processor."),
 			docs.FieldString("key", "An interpolated string yielding the key to deduplicate by for each message.", `${! meta("kafka_key") }`, `${! content().hash("xxhash64") }`).IsInterpolated(),
+			docs.FieldString("mode", "The mode with which messages are deduplicated.").HasAnnotatedOptions(
+				"key", "Deduplicate messages by the `key` field.",
+				"checkpoint", "Deduplicate messages by the position they were consumed from, as described in the [checkpoint mode section](#checkpoint-mode).",
+			).AtVersion("4.2.0"),
 			docs.FieldBool("drop_on_err", "Whether messages should be dropped when the cache returns a general error such as a network issue."),
+			docs.FieldObject("bloom_filter", "Configures an in-memory [bloom filter](#bloom-filter) to deduplicate messages with instead of a cache.").WithChildren(
+				docs.FieldInt("capacity", "The number of recent keys to remember, when greater than zero the bloom filter is used instead of a cache.", 1000000),
+				docs.FieldFloat("false_positive_rate", "The probability that a key that has not been seen is considered a duplicate.", 0.001),
+			).Advanced().AtVersion("4.2.0"),
 		).ChildDefaultAndTypesFromStruct(processor.NewDedupeConfig()),
 		Examples: []docs.AnnotatedExample{
 			{
@@ -63,6 +90,47 @@ cache_resources:
   - label: keycache
     memory:
       default_ttl: 60s
+`,
+			},
+			{
+				Title:   "Deduplicate with a bloom filter",
+				Summary: "The following configuration demonstrates a pipeline that deduplicates messages based on the hash of their contents amongst the last million messages, using an in-memory bloom filter.",
+				Config: `
+pipeline:
+  processors:
+    - dedupe:
+        key: ${! content().hash("xxhash64") }
+        bloom_filter:
+          capacity: 1000000
+          false_positive_rate: 0.001
+`,
+			},
+			{
+				Title:   "Drop replayed Kafka messages",
+				Summary: "The following configuration demonstrates an input that drops messages that are consumed again after a crash, by tracking the offset of each partition within a Redis cache.",
+				Config: `
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ foo ]
+    consumer_group: benthos_foo
+  processors:
+    - dedupe:
+        cache: offsets
+        mode: checkpoint
+
+output:
+  dedupe_checkpoint:
+    cache: offsets
+    output:
+      http_client:
+        url: http://example.com/events
+        verb: POST
+
+cache_resources:
+  - label: offsets
+    redis:
+      url: tcp://localhost:6379
 `,
 			},
 		},
@@ -75,56 +143,143 @@ cache_resources:
 type dedupeProc struct {
 	log log.Modular
 
-	dropOnErr bool
-	key       *field.Expression
-	mgr       bundle.NewManagement
-	cacheName string
+	dropOnErr  bool
+	key        *field.Expression
+	mgr        bundle.NewManagement
+	cacheName  string
+	filter     *slidingBloomFilter
+	checkpoint bool
+
+	tracker        *checkpointTracker
+	releaseTracker func() error
 }
 
 func newDedupe(conf processor.DedupeConfig, mgr bundle.NewManagement) (*dedupeProc, error) {
-	if conf.Key == "" {
+	d := &dedupeProc{
+		log:       mgr.Logger(),
+		dropOnErr: conf.DropOnCacheErr,
+		mgr:       mgr,
+		cacheName: conf.Cache,
+	}
+
+	switch conf.Mode {
+	case "", "key":
+	case "checkpoint":
+		d.checkpoint = true
+	default:
+		return nil, fmt.Errorf("dedupe mode not recognised: %v", conf.Mode)
+	}
+
+	if conf.Key == "" && !d.checkpoint {
 		return nil, errors.New("dedupe key must not be empty")
 	}
-	key, err := mgr.BloblEnvironment().NewField(conf.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse key expression: %v", err)
+	if conf.Key != "" {
+		var err error
+		if d.key, err = mgr.BloblEnvironment().NewField(conf.Key); err != nil {
+			return nil, fmt.Errorf("failed to parse key expression: %v", err)
+		}
+	}
+
+	if conf.BloomFilter.Capacity > 0 {
+		if d.checkpoint {
+			return nil, errors.New("a bloom filter cannot be used with the checkpoint mode")
+		}
+		if conf.Cache != "" {
+			return nil, errors.New("a cache resource cannot be specified when a bloom filter is used")
+		}
+		var err error
+		if d.filter, err = newSlidingBloomFilter(conf.BloomFilter.Capacity, conf.BloomFilter.FalsePositiveRate); err != nil {
+			return nil, err
+		}
+		return d, nil
 	}
 
 	if !mgr.ProbeCache(conf.Cache) {
 		return nil, fmt.Errorf("cache resource '%v' was not found", conf.Cache)
 	}
-
-	return &dedupeProc{
-		log:       mgr.Logger(),
-		dropOnErr: conf.DropOnCacheErr,
-		key:       key,
-		mgr:       mgr,
-		cacheName: conf.Cache,
-	}, nil
+	if d.checkpoint {
+		var err error
+		if d.tracker, d.releaseTracker, err = acquireCheckpointTracker(mgr, conf.Cache); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
 }
 
 //------------------------------------------------------------------------------
 
+// addKey attempts to add a key to the cache and returns true if the key
+// already exists.
+func (d *dedupeProc) addKey(key string) (bool, error) {
+	var err error
+	if cerr := d.mgr.AccessCache(context.Background(), d.cacheName, func(cache cache.V1) {
+		err = cache.Add(context.Background(), key, []byte{'t'}, nil)
+	}); cerr != nil {
+		err = cerr
+	}
+	if err == component.ErrKeyAlreadyExists {
+		return true, nil
+	}
+	return false, err
+}
+
+// hasKey returns true if a key exists within the cache.
+func (d *dedupeProc) hasKey(key string) (bool, error) {
+	var err error
+	if cerr := d.mgr.AccessCache(context.Background(), d.cacheName, func(cache cache.V1) {
+		_, err = cache.Get(context.Background(), key)
+	}); cerr != nil {
+		err = cerr
+	}
+	if err == component.ErrKeyNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (d *dedupeProc) isDuplicate(i int, batch *message.Batch) (bool, error) {
+	if d.checkpoint {
+		if cp, ok := checkpointFromPart(batch.Get(i)); ok {
+			if !cp.ordered {
+				return d.hasKey(cp.key)
+			}
+			dupe, err := checkpointBelowWatermark(context.Background(), d.mgr, d.cacheName, cp.key, cp.offset)
+			if err == nil && !dupe {
+				d.tracker.track(cp)
+				message.OnResolve(batch.Get(i), func(err error) {
+					// Messages that are rejected are consumed again and
+					// therefore remain in flight.
+					if err == nil {
+						d.tracker.release(cp)
+					}
+				})
+			}
+			return dupe, err
+		}
+		if d.key == nil {
+			return false, nil
+		}
+	}
+
+	key := d.key.String(i, batch)
+	if d.filter != nil {
+		return !d.filter.add(key), nil
+	}
+	return d.addKey(key)
+}
+
 func (d *dedupeProc) ProcessBatch(ctx context.Context, spans []*tracing.Span, batch *message.Batch) ([]*message.Batch, error) {
 	newBatch := message.QuickBatch(nil)
 	_ = batch.Iter(func(i int, p *message.Part) error {
-		key := d.key.String(i, batch)
-
-		var err error
-		if cerr := d.mgr.AccessCache(context.Background(), d.cacheName, func(cache cache.V1) {
-			err = cache.Add(context.Background(), key, []byte{'t'}, nil)
-		}); cerr != nil {
-			err = cerr
+		dupe, err := d.isDuplicate(i, batch)
+		if dupe {
+			spans[i].LogKV(
+				"event", "dropped",
+				"type", "deduplicated",
+			)
+			return nil
 		}
 		if err != nil {
-			if err == component.ErrKeyAlreadyExists {
-				spans[i].LogKV(
-					"event", "dropped",
-					"type", "deduplicated",
-				)
-				return nil
-			}
-
 			d.log.Errorf("Cache error: %v\n", err)
 			if d.dropOnErr {
 				spans[i].LogKV(
@@ -149,5 +304,8 @@ func (d *dedupeProc) ProcessBatch(ctx context.Context, spans []*tracing.Span, ba
 }
 
 func (d *dedupeProc) Close(context.Context) error {
+	if d.releaseTracker != nil {
+		return d.releaseTracker()
+	}
 	return nil
 }
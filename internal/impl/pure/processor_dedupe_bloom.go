@@ -0,0 +1,107 @@
+package pure
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/OneOfOne/xxhash"
+)
+
+// bloomFilter is a fixed size bloom filter that uses double hashing of a single
+// 64-bit xxhash digest in order to derive the bit positions of a key.
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64
+	k      uint64
+	length int
+}
+
+func newBloomFilter(capacity int, fpRate float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(capacity) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	if m == 0 {
+		m = 1
+	}
+	k := uint64(math.Round(float64(m) / float64(capacity) * math.Ln2))
+	if k == 0 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func (b *bloomFilter) positions(key string, fn func(word uint64, mask uint64) bool) bool {
+	h := xxhash.ChecksumString64(key)
+	h1, h2 := h&0xffffffff, h>>32
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		if !fn(pos/64, 1<<(pos%64)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bloomFilter) test(key string) bool {
+	return b.positions(key, func(word, mask uint64) bool {
+		return b.bits[word]&mask != 0
+	})
+}
+
+func (b *bloomFilter) add(key string) {
+	b.positions(key, func(word, mask uint64) bool {
+		b.bits[word] |= mask
+		return true
+	})
+	b.length++
+}
+
+//------------------------------------------------------------------------------
+
+// slidingBloomFilter remembers the most recently added keys by rotating
+// between two bloom filter generations, where a key is considered present
+// when either generation contains it. Once the current generation reaches its
+// capacity the previous generation is discarded, which bounds memory usage
+// whilst guaranteeing that at least the last capacity keys are remembered.
+type slidingBloomFilter struct {
+	capacity int
+	fpRate   float64
+
+	mut      sync.Mutex
+	current  *bloomFilter
+	previous *bloomFilter
+}
+
+func newSlidingBloomFilter(capacity int, fpRate float64) (*slidingBloomFilter, error) {
+	if capacity <= 0 {
+		return nil, errors.New("bloom filter capacity must be greater than zero")
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		return nil, errors.New("bloom filter false positive rate must be between zero and one")
+	}
+	return &slidingBloomFilter{
+		capacity: capacity,
+		fpRate:   fpRate,
+		current:  newBloomFilter(capacity, fpRate),
+	}, nil
+}
+
+// add attempts to add a key to the filter and returns false if the key was
+// (probably) already present.
+func (s *slidingBloomFilter) add(key string) bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.current.test(key) || (s.previous != nil && s.previous.test(key)) {
+		return false
+	}
+	if s.current.length >= s.capacity {
+		s.previous = s.current
+		s.current = newBloomFilter(s.capacity, s.fpRate)
+	}
+	s.current.add(key)
+	return true
+}
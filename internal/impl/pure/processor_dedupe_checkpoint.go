@@ -0,0 +1,213 @@
+package pure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// dedupeCheckpoint identifies the position of a message within its input.
+// Ordered checkpoints are tracked as a watermark of the greatest offset
+// delivered for their key, whereas unordered checkpoints are tracked by key
+// alone.
+type dedupeCheckpoint struct {
+	key     string
+	offset  int64
+	ordered bool
+}
+
+// checkpointFromPart extracts the checkpoint of a message from the metadata
+// added by the input that consumed it, returning false if the metadata of no
+// known input is present.
+func checkpointFromPart(p *message.Part) (dedupeCheckpoint, bool) {
+	if topic, partition, offset := p.MetaGet("kafka_topic"), p.MetaGet("kafka_partition"), p.MetaGet("kafka_offset"); topic != "" && partition != "" && offset != "" {
+		if o, err := strconv.ParseInt(offset, 10, 64); err == nil {
+			return dedupeCheckpoint{
+				key:     "kafka/" + topic + "/" + partition,
+				offset:  o,
+				ordered: true,
+			}, true
+		}
+	}
+	if stream, seq := p.MetaGet("nats_stream"), p.MetaGet("nats_sequence_stream"); stream != "" && seq != "" {
+		if s, err := strconv.ParseUint(seq, 10, 63); err == nil {
+			return dedupeCheckpoint{
+				key:     "nats_jetstream/" + stream,
+				offset:  int64(s),
+				ordered: true,
+			}, true
+		}
+	}
+	if subject, seq := p.MetaGet("nats_stream_subject"), p.MetaGet("nats_stream_sequence"); subject != "" && seq != "" {
+		if s, err := strconv.ParseUint(seq, 10, 63); err == nil {
+			return dedupeCheckpoint{
+				key:     "nats_stream/" + subject,
+				offset:  int64(s),
+				ordered: true,
+			}, true
+		}
+	}
+	if id := p.MetaGet("sqs_message_id"); id != "" {
+		return dedupeCheckpoint{key: "sqs/" + id}, true
+	}
+	return dedupeCheckpoint{}, false
+}
+
+//------------------------------------------------------------------------------
+
+// checkpointTracker tracks the ordered checkpoints of messages that have been
+// passed on by dedupe processors in checkpoint mode but have not yet been
+// delivered by a dedupe_checkpoint output sharing the same cache, so that the
+// watermark of a key is never advanced beyond a message that is still in
+// flight. A tracker is shared between components through the shared clients
+// of the manager.
+type checkpointTracker struct {
+	mut       sync.Mutex
+	pending   map[string]map[int64]struct{}
+	delivered map[string]int64
+
+	// Watermarks are read and written to the cache as separate operations and
+	// are therefore synchronised between the components sharing a tracker.
+	writeMut sync.Mutex
+}
+
+func acquireCheckpointTracker(mgr bundle.NewManagement, cacheName string) (*checkpointTracker, func() error, error) {
+	c, release, err := mgr.SharedClients().Acquire("dedupe_checkpoint:"+cacheName, func() (io.Closer, error) {
+		return &checkpointTracker{
+			pending:   map[string]map[int64]struct{}{},
+			delivered: map[string]int64{},
+		}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	tracker, ok := c.(*checkpointTracker)
+	if !ok {
+		_ = release()
+		return nil, nil, fmt.Errorf("shared checkpoint tracker has unexpected type: %T", c)
+	}
+	return tracker, release, nil
+}
+
+func (c *checkpointTracker) Close() error {
+	return nil
+}
+
+// track registers an ordered checkpoint as in flight.
+func (c *checkpointTracker) track(cp dedupeCheckpoint) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	offsets, exists := c.pending[cp.key]
+	if !exists {
+		offsets = map[int64]struct{}{}
+		c.pending[cp.key] = offsets
+	}
+	offsets[cp.offset] = struct{}{}
+}
+
+// release removes an ordered checkpoint from those in flight without marking it
+// as delivered, which is used once the transaction of a message has resolved
+// without it reaching a dedupe_checkpoint output, for example when it was
+// removed by a later processor. Checkpoints that have since been delivered are
+// unaffected.
+func (c *checkpointTracker) release(cp dedupeCheckpoint) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if offsets, exists := c.pending[cp.key]; exists {
+		delete(offsets, cp.offset)
+		if len(offsets) == 0 {
+			delete(c.pending, cp.key)
+		}
+	}
+}
+
+// deliver marks ordered checkpoints as delivered and returns the watermarks of
+// their keys that can be committed, which are the greatest delivered offsets
+// that are below every offset of their key still in flight.
+func (c *checkpointTracker) deliver(cps []dedupeCheckpoint) map[string]int64 {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	marks := map[string]int64{}
+	for _, cp := range cps {
+		if offsets, exists := c.pending[cp.key]; exists {
+			delete(offsets, cp.offset)
+			if len(offsets) == 0 {
+				delete(c.pending, cp.key)
+			}
+		}
+		if d, exists := c.delivered[cp.key]; !exists || cp.offset > d {
+			c.delivered[cp.key] = cp.offset
+		}
+		marks[cp.key] = 0
+	}
+
+	for key := range marks {
+		mark := c.delivered[key]
+		for offset := range c.pending[key] {
+			if offset <= mark {
+				mark = offset - 1
+			}
+		}
+		if mark < 0 {
+			delete(marks, key)
+			continue
+		}
+		marks[key] = mark
+	}
+	return marks
+}
+
+//------------------------------------------------------------------------------
+
+// checkpointBelowWatermark returns true if an offset is at or below the
+// watermark of a key.
+func checkpointBelowWatermark(ctx context.Context, mgr bundle.NewManagement, cacheName, key string, offset int64) (bool, error) {
+	var below bool
+	var err error
+	if cerr := mgr.AccessCache(ctx, cacheName, func(c cache.V1) {
+		var value []byte
+		if value, err = c.Get(ctx, key); err != nil {
+			if err == component.ErrKeyNotFound {
+				err = nil
+			}
+			return
+		}
+		var mark int64
+		if mark, err = strconv.ParseInt(string(value), 10, 64); err != nil {
+			err = fmt.Errorf("failed to parse watermark: %w", err)
+			return
+		}
+		below = offset <= mark
+	}); cerr != nil {
+		err = cerr
+	}
+	return below, err
+}
+
+// advanceWatermark advances the watermark held by a key to an offset, unless
+// the watermark is already at or beyond it.
+func (c *checkpointTracker) advanceWatermark(ctx context.Context, mgr bundle.NewManagement, cacheName, key string, offset int64) error {
+	c.writeMut.Lock()
+	defer c.writeMut.Unlock()
+
+	below, err := checkpointBelowWatermark(ctx, mgr, cacheName, key, offset)
+	if err != nil || below {
+		return err
+	}
+	if cerr := mgr.AccessCache(ctx, cacheName, func(c cache.V1) {
+		err = c.Set(ctx, key, []byte(strconv.FormatInt(offset, 10)), nil)
+	}); cerr != nil {
+		err = cerr
+	}
+	return err
+}
@@ -94,3 +94,130 @@ func TestDedupeCacheErrors(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, msgs, 1)
 }
+
+func TestDedupeBloomFilter(t *testing.T) {
+	conf := processor.NewConfig()
+	conf.Type = "dedupe"
+	conf.Dedupe.Key = "${! content() }"
+	conf.Dedupe.BloomFilter.Capacity = 2
+	conf.Dedupe.BloomFilter.FalsePositiveRate = 0.0001
+
+	proc, err := mock.NewManager().NewProcessor(conf)
+	require.NoError(t, err)
+
+	var results []string
+	for _, input := range []string{"a", "b", "a", "c", "a", "d", "e", "b", "a"} {
+		msgs, err := proc.ProcessMessage(message.QuickBatch([][]byte{[]byte(input)}))
+		require.NoError(t, err)
+		for _, m := range msgs {
+			results = append(results, string(m.Get(0).Get()))
+		}
+	}
+
+	// The filter remembers at least the last two keys, and therefore "a" and
+	// "b" are forgotten once "e" is added.
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "b", "a"}, results)
+}
+
+func TestDedupeBloomFilterErrors(t *testing.T) {
+	conf := processor.NewConfig()
+	conf.Type = "dedupe"
+	conf.Dedupe.Key = "${! content() }"
+	conf.Dedupe.BloomFilter.Capacity = 10
+	conf.Dedupe.BloomFilter.FalsePositiveRate = 1.5
+
+	_, err := mock.NewManager().NewProcessor(conf)
+	require.Error(t, err)
+
+	conf.Dedupe.BloomFilter.FalsePositiveRate = 0.01
+	conf.Dedupe.Cache = "foocache"
+
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	_, err = mgr.NewProcessor(conf)
+	require.Error(t, err)
+}
+
+func TestDedupeCheckpoint(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{
+		"kafka/foo/0":        {Value: "1"},
+		"nats_jetstream/bar": {Value: "5"},
+		"sqs/xyz":            {Value: "t"},
+	}
+
+	conf := processor.NewConfig()
+	conf.Type = "dedupe"
+	conf.Dedupe.Cache = "foocache"
+	conf.Dedupe.Mode = "checkpoint"
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	newPart := func(content string, meta ...string) *message.Part {
+		p := message.NewPart([]byte(content))
+		for i := 0; i < len(meta)-1; i += 2 {
+			p.MetaSet(meta[i], meta[i+1])
+		}
+		return p
+	}
+	kafkaPart := func(content, partition, offset string) *message.Part {
+		return newPart(content, "kafka_topic", "foo", "kafka_partition", partition, "kafka_offset", offset)
+	}
+
+	batch := message.QuickBatch(nil)
+	batch.Append(kafkaPart("a", "0", "0"))
+	batch.Append(kafkaPart("b", "0", "1"))
+	batch.Append(kafkaPart("c", "0", "2"))
+	batch.Append(kafkaPart("d", "1", "0"))
+	batch.Append(newPart("e", "nats_stream", "bar", "nats_sequence_stream", "5"))
+	batch.Append(newPart("f", "nats_stream", "bar", "nats_sequence_stream", "6"))
+	batch.Append(newPart("g", "nats_stream_subject", "baz", "nats_stream_sequence", "1"))
+	batch.Append(newPart("h", "sqs_message_id", "xyz"))
+	batch.Append(newPart("i", "sqs_message_id", "abc"))
+	batch.Append(newPart("j"))
+	batch.Append(newPart("j"))
+
+	msgs, err := proc.ProcessMessage(batch)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	var results []string
+	_ = msgs[0].Iter(func(i int, p *message.Part) error {
+		assert.NoError(t, p.ErrorGet())
+		results = append(results, string(p.Get()))
+		return nil
+	})
+	assert.Equal(t, []string{"c", "d", "f", "g", "i", "j", "j"}, results)
+
+	// Checkpoints are only written once messages are delivered.
+	assert.Equal(t, map[string]mock.CacheItem{
+		"kafka/foo/0":        {Value: "1"},
+		"nats_jetstream/bar": {Value: "5"},
+		"sqs/xyz":            {Value: "t"},
+	}, mgr.Caches["foocache"])
+}
+
+func TestDedupeCheckpointFallbackKey(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	conf := processor.NewConfig()
+	conf.Type = "dedupe"
+	conf.Dedupe.Cache = "foocache"
+	conf.Dedupe.Mode = "checkpoint"
+	conf.Dedupe.Key = "${! content() }"
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgs, err := proc.ProcessMessage(message.QuickBatch([][]byte{[]byte("foo"), []byte("foo"), []byte("bar")}))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, 2, msgs[0].Len())
+
+	conf.Dedupe.Mode = "nope"
+	_, err = mgr.NewProcessor(conf)
+	require.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package pure
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func expiryProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.2.0").
+		Summary("Drops messages that have passed their expiry, or flags them so that they can be routed to a dead letter queue, which prevents the delivery of stale data.").
+		Description(`
+The expiry of each message is calculated with the interpolated field `+"`expires_at`"+`, which should resolve to either an [RFC 3339](https://datatracker.ietf.org/doc/html/rfc3339) timestamp or a unix timestamp in seconds, and messages where it resolves to an empty string never expire. By default the expiry is read from the metadata field `+"`expires_at`"+`, which can be attached to messages early on within a pipeline with a [`+"`bloblang`"+` processor](/docs/components/processors/bloblang):
+
+`+"```coffee"+`
+meta expires_at = (timestamp_unix() + 30).string()
+`+"```"+`
+
+Since an expiry is stored within the message itself it is retained by messages as they pass through [buffers](/docs/components/buffers/about), and therefore placing this processor within the [output processors](/docs/components/outputs/about) of a pipeline drops messages that expired whilst they were buffered. The `+"[`retry`](/docs/components/outputs/retry)"+` output also supports an `+"`expires_at`"+` field in order to abandon messages that expire whilst their writes are being reattempted.
+
+When `+"`action`"+` is `+"`drop`"+` expired messages are removed from the batch and acknowledged. When `+"`action`"+` is `+"`flag`"+` expired messages are instead given the metadata field `+"`expired`"+` with the value `+"`true`"+` and flagged as having failed, allowing you to [error handle them](/docs/configuration/error_handling), such as by routing them to a dead letter queue with a `+"[`switch`](/docs/components/outputs/switch)"+` output.
+
+Messages with an expiry that cannot be parsed remain unchanged and are flagged as having failed.`).
+		Field(service.NewInterpolatedStringField("expires_at").
+			Description("The expiry of each message, either as an RFC 3339 timestamp or a unix timestamp in seconds. Messages where this resolves to an empty string never expire.").
+			Example(`${! meta("expires_at").or("") }`).Example(`${! json("valid_until") }`).
+			Default(`${! meta("expires_at").or("") }`)).
+		Field(service.NewStringEnumField("action", "drop", "flag").
+			Description("What to do with expired messages, either `drop` in order to remove them, or `flag` in order to mark them as failed with the metadata field `expired`.").
+			Default("drop")).
+		Example(
+			"Routing Expired Messages",
+			"In this example realtime price updates that are older than a minute by the time they are written are sent to a dead letter queue rather than to their target.",
+			`
+pipeline:
+  processors:
+    - bloblang: |
+        root = this
+        meta expires_at = (timestamp_unix() + 60).string()
+
+output:
+  processors:
+    - expiry:
+        action: flag
+  switch:
+    cases:
+      - check: meta("expired") == "true"
+        output:
+          file:
+            path: ./expired.jsonl
+      - output:
+          http_client:
+            url: http://example.com/prices
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"expiry", expiryProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newExpiryFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type expiryProc struct {
+	expiresAt *service.InterpolatedString
+	flag      bool
+	nowFn     func() time.Time
+}
+
+func newExpiryFromParsed(conf *service.ParsedConfig) (*expiryProc, error) {
+	expiresAt, err := conf.FieldInterpolatedString("expires_at")
+	if err != nil {
+		return nil, err
+	}
+	action, err := conf.FieldString("action")
+	if err != nil {
+		return nil, err
+	}
+	return &expiryProc{
+		expiresAt: expiresAt,
+		flag:      action == "flag",
+		nowFn:     time.Now,
+	}, nil
+}
+
+// parseExpiry parses an expiry from either an RFC 3339 timestamp or a unix
+// timestamp in seconds, where an empty string means the message never expires.
+func parseExpiry(s string) (t time.Time, ok bool, err error) {
+	if s == "" {
+		return t, false, nil
+	}
+	if secs, ferr := strconv.ParseFloat(s, 64); ferr == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*1e9)), true, nil
+	}
+	if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+		return t, false, fmt.Errorf("failed to parse expiry '%v': expected an RFC 3339 or unix timestamp", s)
+	}
+	return t, true, nil
+}
+
+func (e *expiryProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	expiresAt, ok, err := parseExpiry(e.expiresAt.String(msg))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || e.nowFn().Before(expiresAt) {
+		return service.MessageBatch{msg}, nil
+	}
+	if !e.flag {
+		return nil, nil
+	}
+	msg.MetaSet("expired", "true")
+	msg.SetError(fmt.Errorf("message expired at %v", expiresAt.UTC().Format(time.RFC3339Nano)))
+	return service.MessageBatch{msg}, nil
+}
+
+func (e *expiryProc) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package pure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestParseExpiry(t *testing.T) {
+	_, ok, err := parseExpiry("")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ts, ok, err := parseExpiry("1651402800")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1651402800, 0), ts)
+
+	ts, ok, err = parseExpiry("1651402800.5")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1651402800, 5e8), ts)
+
+	ts, ok, err = parseExpiry("2022-05-01T11:00:00Z")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, time.Unix(1651402800, 0).Equal(ts))
+
+	_, _, err = parseExpiry("not a timestamp")
+	require.Error(t, err)
+}
+
+func TestExpiryProcessor(t *testing.T) {
+	now := time.Unix(1651402800, 0)
+
+	newMsg := func(content, expiresAt string) *service.Message {
+		msg := service.NewMessage([]byte(content))
+		if expiresAt != "" {
+			msg.MetaSet("expires_at", expiresAt)
+		}
+		return msg
+	}
+
+	tests := []struct {
+		name      string
+		action    string
+		expiresAt string
+		kept      bool
+		expired   bool
+		errored   bool
+	}{
+		{name: "no expiry", action: "drop", kept: true},
+		{name: "not expired", action: "drop", expiresAt: "1651402801", kept: true},
+		{name: "expired drop", action: "drop", expiresAt: "1651402799"},
+		{name: "expired at now", action: "drop", expiresAt: "2022-05-01T11:00:00Z"},
+		{name: "expired flag", action: "flag", expiresAt: "1651402799", kept: true, expired: true, errored: true},
+		{name: "not expired flag", action: "flag", expiresAt: "2022-05-01T12:00:00Z", kept: true},
+		{name: "bad expiry", action: "drop", expiresAt: "nope", kept: true, errored: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := expiryProcConfig().ParseYAML(`action: `+test.action, nil)
+			require.NoError(t, err)
+
+			proc, err := newExpiryFromParsed(conf)
+			require.NoError(t, err)
+			proc.nowFn = func() time.Time { return now }
+
+			batch, err := proc.Process(context.Background(), newMsg("hello world", test.expiresAt))
+			if test.errored && !test.expired {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if !test.kept {
+				assert.Empty(t, batch)
+				return
+			}
+			require.Len(t, batch, 1)
+
+			v, exists := batch[0].MetaGet("expired")
+			assert.Equal(t, test.expired, exists)
+			if test.expired {
+				assert.Equal(t, "true", v)
+			}
+			if test.errored {
+				require.Error(t, batch[0].GetError())
+				assert.Contains(t, batch[0].GetError().Error(), "message expired at 2022-05-01T10:59:59Z")
+			} else {
+				assert.NoError(t, batch[0].GetError())
+			}
+		})
+	}
+}
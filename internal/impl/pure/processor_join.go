@@ -0,0 +1,204 @@
+package pure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func joinProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Integration").
+		Version("4.2.0").
+		Summary("Enriches messages with values obtained by key from a [cache resource](/docs/components/caches/about), which is populated by a second stream, allowing you to join two streams of data.").
+		Description(`
+For each message the key is resolved and its value is read from the cache, the value is then parsed as a JSON document (or kept as a string when it is not valid JSON) and mapped onto the message with the `+"`result_map`"+` mapping, where `+"`this`"+` refers to the joined value and `+"`root`"+` begins as the original message.
+
+The cache is populated by a second stream, or a second input of the same stream, that writes the messages to be joined into the cache with a `+"[`cache` output](/docs/components/outputs/cache)"+` keyed by the same value. Since the two streams are not synchronised a message may arrive before the value it is joined with, in which case the processor waits up to the `+"`timeout`"+` for the key to appear, checking the cache every `+"`check_interval`"+`.
+
+### Join Types
+
+When a key is not found within the `+"`timeout`"+` the behaviour depends on the `+"`type`"+` of the join. An `+"`inner`"+` join drops the message, whereas a `+"`left`"+` join passes the message along unchanged.
+
+Messages where the cache could not be accessed, or where the mapping fails, remain unchanged and are flagged as having failed, allowing you to use [standard processor error handling patterns](/docs/configuration/error_handling).`).
+		Field(service.NewStringField("resource").
+			Description("The [`cache` resource](/docs/components/caches/about) to read joined values from.")).
+		Field(service.NewInterpolatedStringField("key").
+			Description("The key of the value to join with each message.").
+			Example(`${! json("user_id") }`).Example(`${! meta("kafka_key") }`)).
+		Field(service.NewStringAnnotatedEnumField("type", map[string]string{
+			"inner": "Messages that have no joined value are dropped.",
+			"left":  "Messages that have no joined value are passed along unchanged.",
+		}).Description("The type of join to perform.").
+			Default("left")).
+		Field(service.NewBloblangField("result_map").
+			Description("A [Bloblang mapping](/docs/guides/bloblang/about) that maps the joined value onto the message, where `this` refers to the joined value and `root` begins as the original message.").
+			Example(`root.user = this`).Example(`root.user_name = this.name`)).
+		Field(service.NewDurationField("timeout").
+			Description("The maximum period to wait for a key to appear within the cache when it is not found. When zero the cache is checked only once.").
+			Example("500ms").Example("10s").
+			Default("0s")).
+		Field(service.NewDurationField("check_interval").
+			Description("The period between each check of the cache whilst waiting for a key to appear.").
+			Default("100ms").
+			Advanced()).
+		Example(
+			"Enriching Orders with Users",
+			"In this example a stream of orders is enriched with the details of the user that placed each order, which are consumed from a separate topic by a second stream and stored within a Redis cache. The stream that populates the cache looks like this:\n\n```yaml\ninput:\n  kafka:\n    addresses: [ TODO ]\n    topics: [ users ]\n    consumer_group: benthos_users\n\noutput:\n  cache:\n    target: users\n    key: ${! json(\"id\") }\n```\n\nAnd the stream that joins orders with users, where orders are dropped when their user is not known within five seconds, looks like this:",
+			`
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ orders ]
+    consumer_group: benthos_orders
+
+pipeline:
+  processors:
+    - join:
+        resource: users
+        key: ${! json("user_id") }
+        type: inner
+        timeout: 5s
+        result_map: root.user = this
+
+cache_resources:
+  - label: users
+    redis:
+      url: tcp://localhost:6379
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"join", joinProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newJoinFromParsed(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type joinProc struct {
+	resource      string
+	key           *service.InterpolatedString
+	inner         bool
+	resultMap     *bloblang.Executor
+	timeout       time.Duration
+	checkInterval time.Duration
+
+	mgr *service.Resources
+}
+
+func newJoinFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*joinProc, error) {
+	j := &joinProc{mgr: mgr}
+
+	var err error
+	if j.resource, err = conf.FieldString("resource"); err != nil {
+		return nil, err
+	}
+	if !mgr.HasCache(j.resource) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", j.resource)
+	}
+	if j.key, err = conf.FieldInterpolatedString("key"); err != nil {
+		return nil, err
+	}
+	joinType, err := conf.FieldString("type")
+	if err != nil {
+		return nil, err
+	}
+	switch joinType {
+	case "inner":
+		j.inner = true
+	case "left":
+	default:
+		return nil, fmt.Errorf("join type not recognised: %v", joinType)
+	}
+	if j.resultMap, err = conf.FieldBloblang("result_map"); err != nil {
+		return nil, err
+	}
+	if j.timeout, err = conf.FieldDuration("timeout"); err != nil {
+		return nil, err
+	}
+	if j.checkInterval, err = conf.FieldDuration("check_interval"); err != nil {
+		return nil, err
+	}
+	if j.checkInterval <= 0 {
+		return nil, errors.New("check_interval must be greater than zero")
+	}
+	return j, nil
+}
+
+// lookup attempts to obtain the value of a key from the cache, waiting up to
+// the configured timeout for it to appear. Returns service.ErrKeyNotFound if
+// the key did not appear in time.
+func (j *joinProc) lookup(ctx context.Context, key string) ([]byte, error) {
+	var deadline <-chan time.Time
+	if j.timeout > 0 {
+		timer := time.NewTimer(j.timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		var value []byte
+		var getErr error
+		if err := j.mgr.AccessCache(ctx, j.resource, func(c service.Cache) {
+			value, getErr = c.Get(ctx, key)
+		}); err != nil {
+			return nil, err
+		}
+		if getErr == nil || !errors.Is(getErr, service.ErrKeyNotFound) || deadline == nil {
+			return value, getErr
+		}
+
+		select {
+		case <-time.After(j.checkInterval):
+		case <-deadline:
+			return nil, service.ErrKeyNotFound
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (j *joinProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	value, err := j.lookup(ctx, j.key.String(msg))
+	if err != nil {
+		if !errors.Is(err, service.ErrKeyNotFound) {
+			return nil, err
+		}
+		if j.inner {
+			return nil, nil
+		}
+		return service.MessageBatch{msg}, nil
+	}
+
+	var joined interface{}
+	if jErr := json.Unmarshal(value, &joined); jErr != nil {
+		joined = string(value)
+	}
+
+	root, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as structured value: %w", err)
+	}
+	if err := j.resultMap.Overlay(joined, &root); err != nil {
+		return nil, fmt.Errorf("result_map failed: %w", err)
+	}
+	msg.SetStructured(root)
+	return service.MessageBatch{msg}, nil
+}
+
+func (j *joinProc) Close(ctx context.Context) error {
+	return nil
+}
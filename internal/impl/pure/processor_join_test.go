@@ -0,0 +1,133 @@
+package pure_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+
+	_ "github.com/benthosdev/benthos/v4/internal/impl/pure"
+)
+
+func TestJoinProcessor(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		inputs  []string
+		results []string
+	}{
+		{
+			name: "left join",
+			config: `
+join:
+  resource: users
+  key: ${! json("user_id") }
+  result_map: root.user = this
+`,
+			inputs: []string{
+				`{"id":"1","user_id":"a"}`,
+				`{"id":"2","user_id":"c"}`,
+				`{"id":"3","user_id":"b"}`,
+			},
+			results: []string{
+				`{"id":"1","user":{"name":"alice"},"user_id":"a"}`,
+				`{"id":"2","user_id":"c"}`,
+				`{"id":"3","user":"not json","user_id":"b"}`,
+			},
+		},
+		{
+			name: "inner join",
+			config: `
+join:
+  resource: users
+  key: ${! json("user_id") }
+  type: inner
+  timeout: 10ms
+  check_interval: 1ms
+  result_map: root.user_name = this.name
+`,
+			inputs: []string{
+				`{"id":"1","user_id":"a"}`,
+				`{"id":"2","user_id":"c"}`,
+			},
+			results: []string{
+				`{"id":"1","user_id":"a","user_name":"alice"}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mgr := mock.NewManager()
+			mgr.Caches["users"] = map[string]mock.CacheItem{
+				"a": {Value: `{"name":"alice"}`},
+				"b": {Value: `not json`},
+			}
+
+			conf := processor.NewConfig()
+			require.NoError(t, yaml.Unmarshal([]byte(test.config), &conf))
+
+			proc, err := mgr.NewProcessor(conf)
+			require.NoError(t, err)
+
+			var results []string
+			for _, input := range test.inputs {
+				msgs, res := proc.ProcessMessage(message.QuickBatch([][]byte{[]byte(input)}))
+				require.NoError(t, res)
+				for _, m := range msgs {
+					_ = m.Iter(func(i int, p *message.Part) error {
+						assert.NoError(t, p.ErrorGet())
+						results = append(results, string(p.Get()))
+						return nil
+					})
+				}
+			}
+			assert.Equal(t, test.results, results)
+		})
+	}
+}
+
+func TestJoinProcessorTimeout(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["users"] = map[string]mock.CacheItem{}
+
+	conf := processor.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+join:
+  resource: users
+  key: ${! json("user_id") }
+  type: inner
+  timeout: 100ms
+  check_interval: 10ms
+  result_map: root.user = this
+`), &conf))
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	start := time.Now()
+	msgs, res := proc.ProcessMessage(message.QuickBatch([][]byte{[]byte(`{"user_id":"a"}`)}))
+	require.NoError(t, res)
+	assert.Len(t, msgs, 0)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Millisecond*100))
+}
+
+func TestJoinMissingCache(t *testing.T) {
+	conf := processor.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+join:
+  resource: nope
+  key: foo
+  result_map: root.user = this
+`), &conf))
+
+	_, err := mock.NewManager().NewProcessor(conf)
+	require.Error(t, err)
+}
@@ -0,0 +1,317 @@
+package pure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func parseEmailProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Version("4.2.0").
+		Summary("Parses raw [RFC822](https://datatracker.ietf.org/doc/html/rfc822) emails, including [MIME](https://datatracker.ietf.org/doc/html/rfc2045) multipart emails, into a structured document, and optionally extracts their attachments into their own messages.").
+		Description(`
+Each message is parsed into a structured document containing the decoded headers, the sender and recipients, and the plain text and HTML bodies of the email. Header values encoded following [RFC2047](https://datatracker.ietf.org/doc/html/rfc2047) are decoded, and bodies are decoded from their transfer encoding and converted from their character set to UTF-8.
+
+The parsed document has the following structure:
+
+` + "```json" + `
+{
+  "headers": { "Subject": [ "Hello" ] },
+  "subject": "Hello",
+  "from": [ { "name": "Foo", "address": "foo@example.com" } ],
+  "to": [ { "name": "", "address": "bar@example.com" } ],
+  "cc": [],
+  "date": "2022-05-01T10:00:00Z",
+  "message_id": "<1234@example.com>",
+  "text": "Hello world",
+  "html": "<p>Hello world</p>",
+  "attachments": [
+    { "filename": "report.pdf", "content_type": "application/pdf", "content_id": "", "size": 1024 }
+  ]
+}
+` + "```" + `
+
+When ` + "`extract_attachments`" + ` is enabled each attachment is added to the batch after the parsed document as its own message containing the decoded contents of the attachment, with the following metadata fields:
+
+- email_attachment_filename
+- email_attachment_content_type
+- email_attachment_index
+- email_message_id
+
+Messages that fail to parse remain unchanged and are flagged as having failed, allowing you to [error handle them](/docs/configuration/error_handling).`).
+		Field(service.NewBoolField("extract_attachments").
+			Description("Whether to add the contents of each attachment to the batch as its own message, following the parsed document.").
+			Default(true))
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"parse_email", parseEmailProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newParseEmailFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type parseEmailProc struct {
+	extractAttachments bool
+}
+
+func newParseEmailFromParsed(conf *service.ParsedConfig) (*parseEmailProc, error) {
+	extractAttachments, err := conf.FieldBool("extract_attachments")
+	if err != nil {
+		return nil, err
+	}
+	return &parseEmailProc{extractAttachments: extractAttachments}, nil
+}
+
+type emailAttachment struct {
+	filename    string
+	contentType string
+	contentID   string
+	content     []byte
+}
+
+type parsedEmail struct {
+	text        []string
+	html        []string
+	attachments []emailAttachment
+}
+
+var emailWordDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(charset)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	},
+}
+
+func decodeHeaderValue(v string) string {
+	if decoded, err := emailWordDecoder.DecodeHeader(v); err == nil {
+		return decoded
+	}
+	return v
+}
+
+// decodeCharset converts text of a given character set into UTF-8, text of an
+// unrecognised character set is returned unchanged.
+func decodeCharset(charset string, b []byte) []byte {
+	charset = strings.ToLower(charset)
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return b
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return b
+	}
+	decoded, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return b
+	}
+	return decoded
+}
+
+func decodeTransfer(encoding string, r io.Reader) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: r})
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+	return io.ReadAll(r)
+}
+
+// newlineStripper removes line breaks from base64 encoded content, which the
+// standard decoder would otherwise reject.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n *newlineStripper) Read(p []byte) (int, error) {
+	l, err := n.r.Read(p)
+	j := 0
+	for i := 0; i < l; i++ {
+		if p[i] != '\r' && p[i] != '\n' && p[i] != ' ' && p[i] != '\t' {
+			p[j] = p[i]
+			j++
+		}
+	}
+	return j, err
+}
+
+// walkPart adds the contents of a MIME part to a parsed email, descending into
+// multipart parts.
+func (p *parsedEmail) walkPart(header textproto.MIMEHeader, body io.Reader) error {
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.walkPart(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := decodeTransfer(header.Get("Content-Transfer-Encoding"), body)
+	if err != nil {
+		return fmt.Errorf("failed to decode %v part: %w", mediaType, err)
+	}
+
+	var disposition string
+	var filename string
+	if cd := header.Get("Content-Disposition"); cd != "" {
+		var dParams map[string]string
+		if disposition, dParams, err = mime.ParseMediaType(cd); err == nil {
+			filename = dParams["filename"]
+		}
+	}
+	if filename == "" {
+		filename = params["name"]
+	}
+	filename = decodeHeaderValue(filename)
+
+	if disposition != "attachment" && filename == "" {
+		switch mediaType {
+		case "text/plain":
+			p.text = append(p.text, string(decodeCharset(params["charset"], content)))
+			return nil
+		case "text/html":
+			p.html = append(p.html, string(decodeCharset(params["charset"], content)))
+			return nil
+		}
+	}
+
+	p.attachments = append(p.attachments, emailAttachment{
+		filename:    filename,
+		contentType: mediaType,
+		contentID:   strings.Trim(header.Get("Content-Id"), "<>"),
+		content:     content,
+	})
+	return nil
+}
+
+func addressList(header mail.Header, key string) []interface{} {
+	list := []interface{}{}
+	addrs, err := header.AddressList(key)
+	if err != nil {
+		return list
+	}
+	for _, a := range addrs {
+		list = append(list, map[string]interface{}{
+			"name":    a.Name,
+			"address": a.Address,
+		})
+	}
+	return list
+}
+
+func (e *parseEmailProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := mail.ReadMessage(bytes.NewReader(mBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	var parsed parsedEmail
+	if err := parsed.walkPart(textproto.MIMEHeader(m.Header), m.Body); err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]interface{}, len(m.Header))
+	for k, values := range m.Header {
+		decoded := make([]interface{}, len(values))
+		for i, v := range values {
+			decoded[i] = decodeHeaderValue(v)
+		}
+		headers[k] = decoded
+	}
+
+	var date interface{}
+	if t, err := m.Header.Date(); err == nil {
+		date = t.UTC().Format(time.RFC3339)
+	}
+
+	attachments := make([]interface{}, len(parsed.attachments))
+	for i, a := range parsed.attachments {
+		attachments[i] = map[string]interface{}{
+			"filename":     a.filename,
+			"content_type": a.contentType,
+			"content_id":   a.contentID,
+			"size":         int64(len(a.content)),
+		}
+	}
+
+	messageID := m.Header.Get("Message-Id")
+
+	doc := msg.Copy()
+	doc.SetStructured(map[string]interface{}{
+		"headers":     headers,
+		"subject":     decodeHeaderValue(m.Header.Get("Subject")),
+		"from":        addressList(m.Header, "From"),
+		"to":          addressList(m.Header, "To"),
+		"cc":          addressList(m.Header, "Cc"),
+		"date":        date,
+		"message_id":  messageID,
+		"text":        strings.Join(parsed.text, "\n"),
+		"html":        strings.Join(parsed.html, "\n"),
+		"attachments": attachments,
+	})
+
+	batch := service.MessageBatch{doc}
+	if !e.extractAttachments {
+		return batch, nil
+	}
+	for i, a := range parsed.attachments {
+		part := msg.Copy()
+		part.SetBytes(a.content)
+		part.MetaSet("email_attachment_filename", a.filename)
+		part.MetaSet("email_attachment_content_type", a.contentType)
+		part.MetaSet("email_attachment_index", fmt.Sprintf("%v", i))
+		part.MetaSet("email_message_id", messageID)
+		batch = append(batch, part)
+	}
+	return batch, nil
+}
+
+func (e *parseEmailProc) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,132 @@
+package pure
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const testMultipartEmail = `From: Foo Bar <foo@example.com>
+To: bar@example.com, "Baz" <baz@example.com>
+Subject: =?UTF-8?B?SGVsbG8gd29ybGQg8J+Riw==?=
+Date: Sun, 1 May 2022 11:00:00 +0100
+Message-ID: <1234@example.com>
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset="iso-8859-1"
+Content-Transfer-Encoding: quoted-printable
+
+Caf=E9 menu attached.
+--inner
+Content-Type: text/html; charset="utf-8"
+
+<p>Café menu attached.</p>
+--inner--
+
+--outer
+Content-Type: text/csv; name="menu.csv"
+Content-Disposition: attachment; filename="menu.csv"
+Content-Transfer-Encoding: base64
+
+aXRlbSxwcmljZQpjb2ZmZWUs
+Mwo=
+--outer--
+`
+
+func TestParseEmailMultipart(t *testing.T) {
+	conf, err := parseEmailProcConfig().ParseYAML(``, nil)
+	require.NoError(t, err)
+
+	proc, err := newParseEmailFromParsed(conf)
+	require.NoError(t, err)
+
+	msg := service.NewMessage([]byte(strings.ReplaceAll(testMultipartEmail, "\n", "\r\n")))
+	msg.MetaSet("foo", "bar")
+
+	batch, err := proc.Process(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+
+	doc, err := batch[0].AsStructured()
+	require.NoError(t, err)
+
+	docMap, ok := doc.(map[string]interface{})
+	require.True(t, ok)
+
+	assert.Equal(t, "Hello world 👋", docMap["subject"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Foo Bar", "address": "foo@example.com"},
+	}, docMap["from"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "", "address": "bar@example.com"},
+		map[string]interface{}{"name": "Baz", "address": "baz@example.com"},
+	}, docMap["to"])
+	assert.Equal(t, []interface{}{}, docMap["cc"])
+	assert.Equal(t, "2022-05-01T10:00:00Z", docMap["date"])
+	assert.Equal(t, "<1234@example.com>", docMap["message_id"])
+	assert.Equal(t, "Café menu attached.", docMap["text"])
+	assert.Equal(t, "<p>Café menu attached.</p>", docMap["html"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"filename":     "menu.csv",
+			"content_type": "text/csv",
+			"content_id":   "",
+			"size":         int64(20),
+		},
+	}, docMap["attachments"])
+
+	v, _ := batch[0].MetaGet("foo")
+	assert.Equal(t, "bar", v)
+
+	attBytes, err := batch[1].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "item,price\ncoffee,3\n", string(attBytes))
+
+	for k, exp := range map[string]string{
+		"foo":                           "bar",
+		"email_attachment_filename":     "menu.csv",
+		"email_attachment_content_type": "text/csv",
+		"email_attachment_index":        "0",
+		"email_message_id":              "<1234@example.com>",
+	} {
+		v, _ := batch[1].MetaGet(k)
+		assert.Equal(t, exp, v, k)
+	}
+}
+
+func TestParseEmailPlainNoAttachments(t *testing.T) {
+	conf, err := parseEmailProcConfig().ParseYAML(`
+extract_attachments: false
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newParseEmailFromParsed(conf)
+	require.NoError(t, err)
+
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte(strings.ReplaceAll(testMultipartEmail, "\n", "\r\n"))))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	batch, err = proc.Process(context.Background(), service.NewMessage([]byte("Subject: hi\r\n\r\nhello world")))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	doc, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, "hi", doc.(map[string]interface{})["subject"])
+	assert.Equal(t, "hello world", doc.(map[string]interface{})["text"])
+	assert.Nil(t, doc.(map[string]interface{})["date"])
+
+	_, err = proc.Process(context.Background(), service.NewMessage([]byte("not an email")))
+	require.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package pure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/contract"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	contractModeObserve = "observe"
+	contractModeFlag    = "flag"
+	contractModeReject  = "reject"
+)
+
+func schemaContractProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.2.0").
+		Summary("Infers a JSON schema from the structure of messages passing through a named point of a pipeline, and optionally flags or rejects messages that drift from it.").
+		Description(`
+The schema is inferred from the first `+"`sample_count`"+` messages that pass through the processor, and describes the types observed for each field, where fields that are present within every sampled object are required. The inferred schema is served as a JSON schema document by the HTTP server at the endpoint `+"`/contracts/{name}`"+`, along with the number of messages it was inferred from.
+
+When `+"`mode`"+` is `+"`observe`"+` the schema continues to be inferred from every message and messages are never modified, which is useful for discovering the implicit contract of a stream of data. When `+"`mode`"+` is `+"`flag`"+` or `+"`reject`"+` the schema is locked once it has been inferred from `+"`sample_count`"+` messages, and each message after that point is checked against it. A message drifts from the schema when it contains a field that was never observed, is missing a required field, or contains a value of a type that was never observed.
+
+With `+"`flag`"+` drifting messages are given the metadata field `+"`schema_drift`"+`, containing a description of each way in which they drift. With `+"`reject`"+` drifting messages are also flagged as having failed, allowing you to [error handle them](/docs/configuration/error_handling). In either mode the metric `+"`schema_drift`"+` counts the messages that drift from the schema.
+
+Processors that share a `+"`name`"+` share the same inferred schema, and therefore each processing thread of a pipeline contributes samples to a single schema.`).
+		Field(service.NewStringField("name").
+			Description("A name that identifies the point of the pipeline that the schema is inferred at, which determines the endpoint the schema is served at.").
+			Example("orders_in")).
+		Field(service.NewStringAnnotatedEnumField("mode", map[string]string{
+			contractModeObserve: "Continuously infer the schema without checking messages.",
+			contractModeFlag:    "Lock the schema once it is inferred and add the metadata field `schema_drift` to messages that drift from it.",
+			contractModeReject:  "Lock the schema once it is inferred and flag messages that drift from it as having failed.",
+		}).
+			Description("Whether to enforce the inferred schema.").
+			Default(contractModeObserve)).
+		Field(service.NewIntField("sample_count").
+			Description("The number of messages to infer the schema from before it is enforced.").
+			Default(1000)).
+		Example(
+			"Rejecting Drift",
+			"In this example the schema of orders is inferred from the first hundred orders consumed, and orders that drift from it are sent to a dead letter queue.",
+			`
+pipeline:
+  processors:
+    - schema_contract:
+        name: orders
+        mode: reject
+        sample_count: 100
+
+output:
+  switch:
+    cases:
+      - check: errored()
+        output:
+          file:
+            path: ./drifted.jsonl
+          processors:
+            - bloblang: |
+                root = this
+                root.drift = meta("schema_drift")
+      - output:
+          http_client:
+            url: http://example.com/orders
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"schema_contract", schemaContractProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newSchemaContractFromParsed(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+var (
+	sharedContractsMut sync.Mutex
+	sharedContracts    = map[string]*contract.Contract{}
+)
+
+// sharedContract returns the contract of a given name, creating it if it does
+// not yet exist.
+func sharedContract(name string, sampleCount int, keepLearning bool) *contract.Contract {
+	sharedContractsMut.Lock()
+	defer sharedContractsMut.Unlock()
+
+	c, exists := sharedContracts[name]
+	if !exists {
+		c = contract.New(sampleCount, keepLearning)
+		sharedContracts[name] = c
+	}
+	return c
+}
+
+type schemaContractProc struct {
+	name     string
+	mode     string
+	contract *contract.Contract
+	mDrift   *service.MetricCounter
+	log      *service.Logger
+}
+
+func newSchemaContractFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*schemaContractProc, error) {
+	name, err := conf.FieldString("name")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("a name must be specified")
+	}
+	mode, err := conf.FieldString("mode")
+	if err != nil {
+		return nil, err
+	}
+	sampleCount, err := conf.FieldInt("sample_count")
+	if err != nil {
+		return nil, err
+	}
+	if sampleCount < 1 {
+		return nil, fmt.Errorf("sample_count must be greater than zero, got %v", sampleCount)
+	}
+
+	s := &schemaContractProc{
+		name:     name,
+		mode:     mode,
+		contract: sharedContract(name, sampleCount, mode == contractModeObserve),
+		mDrift:   mgr.Metrics().NewCounter("schema_drift"),
+		log:      mgr.Logger(),
+	}
+	mgr.RegisterEndpoint(
+		"/contracts/"+name,
+		fmt.Sprintf("Returns the schema inferred for messages at the point '%v'.", name),
+		s.handleSchema,
+	)
+	return s, nil
+}
+
+func (s *schemaContractProc) handleSchema(w http.ResponseWriter, r *http.Request) {
+	resBytes, err := json.Marshal(map[string]interface{}{
+		"name":     s.name,
+		"mode":     s.mode,
+		"samples":  s.contract.Samples(),
+		"enforced": s.contract.Enforced(),
+		"schema":   s.contract.JSONSchema(),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBytes)
+}
+
+func (s *schemaContractProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	var drift []string
+	if structured, err := msg.AsStructured(); err != nil {
+		if !s.contract.Enforced() {
+			s.log.Debugf("Skipping sample of message that is not structured: %v\n", err)
+			return service.MessageBatch{msg}, nil
+		}
+		drift = []string{fmt.Sprintf("root: failed to parse message as JSON: %v", err)}
+	} else {
+		drift = s.contract.Check(structured)
+	}
+	if len(drift) == 0 {
+		return service.MessageBatch{msg}, nil
+	}
+
+	s.mDrift.Incr(1)
+	msg.MetaSet("schema_drift", strings.Join(drift, "; "))
+	if s.mode == contractModeReject {
+		msg.SetError(fmt.Errorf("message drifts from schema '%v': %v", s.name, strings.Join(drift, "; ")))
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (s *schemaContractProc) Close(ctx context.Context) error {
+	return nil
+}
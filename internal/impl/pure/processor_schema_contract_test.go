@@ -0,0 +1,101 @@
+package pure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestSchemaContractReject(t *testing.T) {
+	conf, err := schemaContractProcConfig().ParseYAML(`
+name: test_reject
+mode: reject
+sample_count: 2
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newSchemaContractFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	for _, content := range []string{
+		`{"id":1,"name":"foo"}`,
+		`{"id":2,"name":"bar"}`,
+		`{"id":3,"name":"baz"}`,
+	} {
+		batch, err := proc.Process(context.Background(), service.NewMessage([]byte(content)))
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		assert.NoError(t, batch[0].GetError())
+		_, exists := batch[0].MetaGet("schema_drift")
+		assert.False(t, exists)
+	}
+
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte(`{"id":"4"}`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	drift, _ := batch[0].MetaGet("schema_drift")
+	assert.Equal(t, "root.id: expected integer, got string; root.name: missing required field", drift)
+	require.Error(t, batch[0].GetError())
+	assert.Contains(t, batch[0].GetError().Error(), "message drifts from schema 'test_reject'")
+
+	batch, err = proc.Process(context.Background(), service.NewMessage([]byte(`not json`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	require.Error(t, batch[0].GetError())
+}
+
+func TestSchemaContractObserve(t *testing.T) {
+	conf, err := schemaContractProcConfig().ParseYAML(`
+name: test_observe
+sample_count: 1
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newSchemaContractFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	for _, content := range []string{
+		`{"id":1}`,
+		`not json`,
+		`{"id":"2","name":"bar"}`,
+	} {
+		batch, err := proc.Process(context.Background(), service.NewMessage([]byte(content)))
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		assert.NoError(t, batch[0].GetError())
+		_, exists := batch[0].MetaGet("schema_drift")
+		assert.False(t, exists)
+	}
+
+	rec := httptest.NewRecorder()
+	proc.handleSchema(rec, httptest.NewRequest("GET", "/contracts/test_observe", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var res struct {
+		Samples  int                    `json:"samples"`
+		Enforced bool                   `json:"enforced"`
+		Schema   map[string]interface{} `json:"schema"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Samples)
+	assert.False(t, res.Enforced)
+	assert.Equal(t, []interface{}{"id"}, res.Schema["required"])
+}
+
+func TestSchemaContractBadConfig(t *testing.T) {
+	conf, err := schemaContractProcConfig().ParseYAML(`
+name: test_bad
+sample_count: 0
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newSchemaContractFromParsed(conf, service.MockResources())
+	require.Error(t, err)
+}
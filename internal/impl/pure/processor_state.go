@@ -0,0 +1,271 @@
+package pure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func stateProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Integration", "Utility").
+		Version("4.2.0").
+		Summary("Performs an operation against the value of a key within a [cache resource](/docs/components/caches/about) for each message, such as incrementing a counter, and adds the resulting value to the message as a metadata field.").
+		Description(`
+This processor allows state such as sequence numbers, counters and high water marks to be maintained for each key of a stream without the need for a custom mapping. For each message the current value of the key is read from the cache, the operation is applied to it along with the `+"`value`"+` of the message, and the result is written back to the cache and added to the message as the metadata field named by `+"`result_meta`"+`. When a key does not yet exist within the cache the operation is applied as if it held an empty value, which for counters is zero.
+
+Operations are atomic between the messages processed by a processor, and therefore by all threads of a pipeline. However, caches do not provide atomic updates, and therefore keys that are updated by multiple processors or instances of Benthos at the same time may occasionally lose updates.
+
+Messages where the operation fails, such as when the value cannot be parsed as a number, remain unchanged and are flagged as having failed, allowing you to use [standard processor error handling patterns](/docs/configuration/error_handling).`).
+		Field(service.NewStringField("resource").
+			Description("The [`cache` resource](/docs/components/caches/about) to store state within.")).
+		Field(service.NewStringAnnotatedEnumField("operator", map[string]string{
+			"incr":           "Adds the `value`, which must be an integer, to the integer held by the key.",
+			"decr":           "Subtracts the `value`, which must be an integer, from the integer held by the key.",
+			"append":         "Appends the `value` as a string to the JSON array held by the key.",
+			"set_if_greater": "Sets the key to the `value` when the value is a number greater than the number currently held by the key, or when the key does not exist. The result is the greatest value that the key has held.",
+		}).Description("The operation to perform against the value of the key.")).
+		Field(service.NewInterpolatedStringField("key").
+			Description("The key to perform the operation against.").
+			Example(`${! json("user_id") }`).Example(`${! meta("kafka_topic") }`)).
+		Field(service.NewInterpolatedStringField("value").
+			Description("The value to apply with the operation.").
+			Example(`${! json("timestamp") }`).
+			Default("1")).
+		Field(service.NewStringField("result_meta").
+			Description("The name of the metadata field to add the result of the operation to.").
+			Default("state")).
+		Field(service.NewInterpolatedStringField("ttl").
+			Description("An optional TTL to set for the key each time it is updated, as a duration string. Not all caches support per-key TTLs, and those that do not will fall back to their generally configured TTL setting.").
+			Example("60s").Example("24h").
+			Optional().
+			Advanced()).
+		Example(
+			"Sequence Numbers",
+			"In this example each message is given a sequence number that increases for each message of the same user, which is added to the document of the message.",
+			`
+pipeline:
+  processors:
+    - state:
+        resource: sequences
+        operator: incr
+        key: ${! json("user_id") }
+        result_meta: sequence
+    - bloblang: |
+        root = this
+        root.sequence = meta("sequence").number()
+
+cache_resources:
+  - label: sequences
+    redis:
+      url: tcp://localhost:6379
+`,
+		).
+		Example(
+			"Dropping Stale Updates",
+			"In this example messages that are older than the latest message of the same document are dropped, by tracking the greatest timestamp observed for each document.",
+			`
+pipeline:
+  processors:
+    - state:
+        resource: watermarks
+        operator: set_if_greater
+        key: ${! json("doc_id") }
+        value: ${! json("updated_at") }
+        result_meta: latest_updated_at
+    - bloblang: |
+        root = if meta("latest_updated_at") != json("updated_at").string() { deleted() }
+
+cache_resources:
+  - label: watermarks
+    memory: {}
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"state", stateProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newStateFromParsed(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// stateOperator applies an operation to the current value of a key, and
+// returns the result along with whether the result should be stored.
+type stateOperator func(current []byte, exists bool, value string) (result []byte, store bool, err error)
+
+func stateIncrOperator(sign int64) stateOperator {
+	return func(current []byte, exists bool, value string) ([]byte, bool, error) {
+		delta, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse value '%v' as an integer", value)
+		}
+		var n int64
+		if exists {
+			if n, err = strconv.ParseInt(strings.TrimSpace(string(current)), 10, 64); err != nil {
+				return nil, false, fmt.Errorf("failed to parse stored value '%s' as an integer", current)
+			}
+		}
+		return []byte(strconv.FormatInt(n+sign*delta, 10)), true, nil
+	}
+}
+
+func stateAppendOperator(current []byte, exists bool, value string) ([]byte, bool, error) {
+	items := []interface{}{}
+	if exists && len(current) > 0 {
+		if err := json.Unmarshal(current, &items); err != nil {
+			return nil, false, fmt.Errorf("failed to parse stored value as a JSON array: %w", err)
+		}
+	}
+	result, err := json.Marshal(append(items, value))
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
+func stateSetIfGreaterOperator(current []byte, exists bool, value string) ([]byte, bool, error) {
+	value = strings.TrimSpace(value)
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse value '%v' as a number", value)
+	}
+	if exists {
+		c, err := strconv.ParseFloat(strings.TrimSpace(string(current)), 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse stored value '%s' as a number", current)
+		}
+		if v <= c {
+			return current, false, nil
+		}
+	}
+	return []byte(value), true, nil
+}
+
+func stateOperatorFromString(op string) (stateOperator, error) {
+	switch op {
+	case "incr":
+		return stateIncrOperator(1), nil
+	case "decr":
+		return stateIncrOperator(-1), nil
+	case "append":
+		return stateAppendOperator, nil
+	case "set_if_greater":
+		return stateSetIfGreaterOperator, nil
+	}
+	return nil, fmt.Errorf("operator not recognised: %v", op)
+}
+
+//------------------------------------------------------------------------------
+
+type stateProc struct {
+	resource   string
+	operator   stateOperator
+	key        *service.InterpolatedString
+	value      *service.InterpolatedString
+	resultMeta string
+	ttl        *service.InterpolatedString
+
+	// Serialises the read, modify and write of keys within the cache.
+	opMut sync.Mutex
+
+	mgr *service.Resources
+}
+
+func newStateFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*stateProc, error) {
+	s := &stateProc{mgr: mgr}
+
+	var err error
+	if s.resource, err = conf.FieldString("resource"); err != nil {
+		return nil, err
+	}
+	if !mgr.HasCache(s.resource) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", s.resource)
+	}
+	opStr, err := conf.FieldString("operator")
+	if err != nil {
+		return nil, err
+	}
+	if s.operator, err = stateOperatorFromString(opStr); err != nil {
+		return nil, err
+	}
+	if s.key, err = conf.FieldInterpolatedString("key"); err != nil {
+		return nil, err
+	}
+	if s.value, err = conf.FieldInterpolatedString("value"); err != nil {
+		return nil, err
+	}
+	if s.resultMeta, err = conf.FieldString("result_meta"); err != nil {
+		return nil, err
+	}
+	if s.resultMeta == "" {
+		return nil, errors.New("result_meta must not be empty")
+	}
+	if conf.Contains("ttl") {
+		if s.ttl, err = conf.FieldInterpolatedString("ttl"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *stateProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	key := s.key.String(msg)
+	value := s.value.String(msg)
+
+	var ttl *time.Duration
+	if s.ttl != nil {
+		ttlStr := s.ttl.String(msg)
+		t, err := time.ParseDuration(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ttl '%v': %w", ttlStr, err)
+		}
+		ttl = &t
+	}
+
+	s.opMut.Lock()
+	defer s.opMut.Unlock()
+
+	var result []byte
+	var opErr error
+	if err := s.mgr.AccessCache(ctx, s.resource, func(c service.Cache) {
+		current, err := c.Get(ctx, key)
+		exists := err == nil
+		if err != nil && !errors.Is(err, service.ErrKeyNotFound) {
+			opErr = err
+			return
+		}
+
+		var store bool
+		if result, store, opErr = s.operator(current, exists, value); opErr != nil || !store {
+			return
+		}
+		opErr = c.Set(ctx, key, result, ttl)
+	}); err != nil {
+		return nil, err
+	}
+	if opErr != nil {
+		return nil, opErr
+	}
+
+	msg.MetaSet(s.resultMeta, string(result))
+	return service.MessageBatch{msg}, nil
+}
+
+func (s *stateProc) Close(ctx context.Context) error {
+	return nil
+}
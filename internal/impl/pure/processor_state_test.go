@@ -0,0 +1,138 @@
+package pure_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+
+	_ "github.com/benthosdev/benthos/v4/internal/impl/pure"
+)
+
+func TestStateOperators(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		resultMeta string
+		inputs     []string
+		results    []string
+		stored     map[string]string
+		errIndex   int
+	}{
+		{
+			name: "incr",
+			config: `
+state:
+  resource: foocache
+  operator: incr
+  key: ${! json("key") }
+  value: ${! json("value") }
+`,
+			inputs:   []string{`{"key":"a","value":1}`, `{"key":"b","value":5}`, `{"key":"a","value":2}`, `{"key":"a","value":"nope"}`},
+			results:  []string{"1", "5", "3", ""},
+			stored:   map[string]string{"a": "3", "b": "5"},
+			errIndex: 3,
+		},
+		{
+			name: "decr",
+			config: `
+state:
+  resource: foocache
+  operator: decr
+  key: ${! json("key") }
+`,
+			inputs:   []string{`{"key":"a"}`, `{"key":"a"}`},
+			results:  []string{"-1", "-2"},
+			stored:   map[string]string{"a": "-2"},
+			errIndex: -1,
+		},
+		{
+			name: "append",
+			config: `
+state:
+  resource: foocache
+  operator: append
+  key: ${! json("key") }
+  value: ${! json("value") }
+  result_meta: list
+`,
+			resultMeta: "list",
+			inputs:     []string{`{"key":"a","value":"foo"}`, `{"key":"a","value":"bar"}`},
+			results:    []string{`["foo"]`, `["foo","bar"]`},
+			stored:     map[string]string{"a": `["foo","bar"]`},
+			errIndex:   -1,
+		},
+		{
+			name: "set if greater",
+			config: `
+state:
+  resource: foocache
+  operator: set_if_greater
+  key: ${! json("key") }
+  value: ${! json("value") }
+`,
+			inputs:   []string{`{"key":"a","value":5}`, `{"key":"a","value":3}`, `{"key":"a","value":7.5}`, `{"key":"a","value":"nope"}`},
+			results:  []string{"5", "5", "7.5", ""},
+			stored:   map[string]string{"a": "7.5"},
+			errIndex: 3,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mgr := mock.NewManager()
+			mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+			conf := processor.NewConfig()
+			require.NoError(t, yaml.Unmarshal([]byte(test.config), &conf))
+
+			proc, err := mgr.NewProcessor(conf)
+			require.NoError(t, err)
+
+			resultMeta := test.resultMeta
+			if resultMeta == "" {
+				resultMeta = "state"
+			}
+
+			for i, input := range test.inputs {
+				msgs, res := proc.ProcessMessage(message.QuickBatch([][]byte{[]byte(input)}))
+				require.NoError(t, res)
+				require.Len(t, msgs, 1)
+
+				part := msgs[0].Get(0)
+				assert.Equal(t, input, string(part.Get()), i)
+				assert.Equal(t, test.results[i], part.MetaGet(resultMeta), i)
+				if i == test.errIndex {
+					assert.Error(t, part.ErrorGet(), i)
+				} else {
+					assert.NoError(t, part.ErrorGet(), i)
+				}
+			}
+
+			stored := map[string]string{}
+			for k, v := range mgr.Caches["foocache"] {
+				stored[k] = v.Value
+			}
+			assert.Equal(t, test.stored, stored)
+		})
+	}
+}
+
+func TestStateMissingCache(t *testing.T) {
+	conf := processor.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+state:
+  resource: nope
+  operator: incr
+  key: foo
+`), &conf))
+
+	_, err := mock.NewManager().NewProcessor(conf)
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package pure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func timeoutProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Composition").
+		Version("4.2.0").
+		Summary("Executes a list of child processors on each message with a time budget, flagging messages that exceed it as having failed, which prevents a single pathological message from stalling a pipeline.").
+		Description(`
+Each message is processed individually by the child processors, and when processing exceeds the `+"`duration`"+` the original message is flagged as having failed and passed on, allowing you to [error handle it](/docs/configuration/error_handling). The metric `+"`processor_timeout`"+` counts the messages that exceeded their budget.
+
+Once the budget is exceeded the context of the child processors is cancelled, which stops processors that support cancellation, such as those that make network requests. Processors that do not support cancellation continue to execute in the background until they finish, at which point their results are discarded.`).
+		Field(service.NewDurationField("duration").
+			Description("The maximum period of time that the child processors are allowed to spend processing each message.").
+			Example("100ms").Example("5s")).
+		Field(service.NewProcessorListField("processors").
+			Description("A list of child processors to execute with a time budget.")).
+		Example(
+			"Bounding Enrichment Latency",
+			"In this example messages are replaced with the response of an HTTP request, and messages where the request takes longer than a second are passed on unchanged with a log entry.",
+			`
+pipeline:
+  processors:
+    - timeout:
+        duration: 1s
+        processors:
+          - http:
+              url: http://example.com/enrich
+              verb: POST
+    - catch:
+        - log:
+            level: WARN
+            message: 'Failed to enrich message: ${! error() }'
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"timeout", timeoutProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newTimeoutFromParsed(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type timeoutProc struct {
+	duration time.Duration
+	children []*service.OwnedProcessor
+	mTimeout *service.MetricCounter
+}
+
+func newTimeoutFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*timeoutProc, error) {
+	duration, err := conf.FieldDuration("duration")
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be greater than zero, got %v", duration)
+	}
+	children, err := conf.FieldProcessorList("processors")
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutProc{
+		duration: duration,
+		children: children,
+		mTimeout: mgr.Metrics().NewCounter("processor_timeout"),
+	}, nil
+}
+
+type timeoutResult struct {
+	batch service.MessageBatch
+	err   error
+}
+
+// processChildren executes each child processor in turn on the batches
+// resulting from the previous child.
+func (t *timeoutProc) processChildren(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	batches := []service.MessageBatch{{msg}}
+	for _, child := range t.children {
+		var nextBatches []service.MessageBatch
+		for _, batch := range batches {
+			res, err := child.ProcessBatch(ctx, batch)
+			if err != nil {
+				return nil, err
+			}
+			nextBatches = append(nextBatches, res...)
+		}
+		if batches = nextBatches; len(batches) == 0 {
+			return nil, nil
+		}
+	}
+
+	var resBatch service.MessageBatch
+	for _, batch := range batches {
+		resBatch = append(resBatch, batch...)
+	}
+	return resBatch, nil
+}
+
+func (t *timeoutProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	procCtx, done := context.WithTimeout(ctx, t.duration)
+	defer done()
+
+	// The children operate on a copy so that the original message can be
+	// passed on unchanged if they exceed the budget.
+	resChan := make(chan timeoutResult, 1)
+	go func(m *service.Message) {
+		batch, err := t.processChildren(procCtx, m)
+		resChan <- timeoutResult{batch: batch, err: err}
+	}(msg.Copy())
+
+	select {
+	case res := <-resChan:
+		return res.batch, res.err
+	case <-procCtx.Done():
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	t.mTimeout.Incr(1)
+	msg.SetError(fmt.Errorf("processing exceeded timeout of %v", t.duration))
+	return service.MessageBatch{msg}, nil
+}
+
+func (t *timeoutProc) Close(ctx context.Context) error {
+	for _, c := range t.children {
+		if err := c.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package pure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestTimeoutProcessorWithinBudget(t *testing.T) {
+	conf, err := timeoutProcConfig().ParseYAML(`
+duration: 1s
+processors:
+  - bloblang: 'root = content().uppercase()'
+  - bloblang: 'root = content() + " WORLD"'
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newTimeoutFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte("hello")))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	assert.NoError(t, batch[0].GetError())
+
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO WORLD", string(b))
+
+	require.NoError(t, proc.Close(context.Background()))
+}
+
+func TestTimeoutProcessorExceeded(t *testing.T) {
+	conf, err := timeoutProcConfig().ParseYAML(`
+duration: 10ms
+processors:
+  - bloblang: 'root = content().uppercase()'
+  - sleep:
+      duration: 10s
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newTimeoutFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	tStarted := time.Now()
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte("hello")))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(tStarted), time.Second)
+
+	require.Len(t, batch, 1)
+	require.Error(t, batch[0].GetError())
+	assert.Equal(t, "processing exceeded timeout of 10ms", batch[0].GetError().Error())
+
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+
+	require.NoError(t, proc.Close(context.Background()))
+}
+
+func TestTimeoutProcessorBadDuration(t *testing.T) {
+	conf, err := timeoutProcConfig().ParseYAML(`
+duration: 0s
+processors: []
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newTimeoutFromParsed(conf, service.MockResources())
+	require.Error(t, err)
+}
@@ -34,6 +34,10 @@ func redisCacheConfig() *service.ConfigSpec {
 			Optional().
 			Advanced()).
 		Field(service.NewBackOffField("retries", false, retriesDefaults).
+			Advanced()).
+		Field(service.NewBoolField("shared_client").
+			Description("Whether to share the underlying client with other components of the service that also enable this field and have identical `url`, `kind`, `master` and `tls` settings, which reduces the number of connections opened to Redis.").
+			Default(false).
 			Advanced())
 
 	return spec
@@ -43,7 +47,7 @@ func init() {
 	err := service.RegisterCache(
 		"redis", redisCacheConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Cache, error) {
-			return newRedisCacheFromConfig(conf)
+			return newRedisCacheFromConfig(conf, mgr)
 		})
 
 	if err != nil {
@@ -51,8 +55,8 @@ func init() {
 	}
 }
 
-func newRedisCacheFromConfig(conf *service.ParsedConfig) (*redisCache, error) {
-	client, err := getClient(conf)
+func newRedisCacheFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*redisCache, error) {
+	client, closeFn, err := getSharedClient(conf, mgr)
 	if err != nil {
 		return nil, err
 	}
@@ -77,13 +81,44 @@ func newRedisCacheFromConfig(conf *service.ParsedConfig) (*redisCache, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newRedisCache(ttl, prefix, client, backOff)
+	r, err := newRedisCache(ttl, prefix, client, backOff)
+	if err != nil {
+		return nil, err
+	}
+	r.closeFn = closeFn
+	return r, nil
+}
+
+// getSharedClient returns a client along with a func to call once it is no
+// longer needed, where the client is shared with other components when the
+// field shared_client is set.
+func getSharedClient(conf *service.ParsedConfig, mgr *service.Resources) (redis.UniversalClient, func() error, error) {
+	shared, err := conf.FieldBool("shared_client")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !shared {
+		client, err := getClient(conf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, client.Close, nil
+	}
+
+	oldConf, err := oldConfigFromParsed(conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return acquireSharedClient(oldConf, mgr.AcquireSharedClient, func() (redis.UniversalClient, error) {
+		return getClient(conf)
+	})
 }
 
 //------------------------------------------------------------------------------
 
 type redisCache struct {
 	client     redis.UniversalClient
+	closeFn    func() error
 	defaultTTL time.Duration
 	prefix     string
 
@@ -238,5 +273,8 @@ func (r *redisCache) Delete(ctx context.Context, key string) error {
 }
 
 func (r *redisCache) Close(ctx context.Context) error {
+	if r.closeFn != nil {
+		return r.closeFn()
+	}
 	return r.client.Close()
 }
@@ -2,13 +2,16 @@ package redis
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"strings"
 
 	"github.com/go-redis/redis/v7"
 
 	"github.com/benthosdev/benthos/v4/internal/impl/redis/old"
+	btls "github.com/benthosdev/benthos/v4/internal/tls"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
 
@@ -174,3 +177,117 @@ func clientFromConfig(r old.Config) (redis.UniversalClient, error) {
 
 	return client, err
 }
+
+// oldConfigFromParsed extracts the fields of a parsed config that were defined
+// with clientFields into an old.Config.
+func oldConfigFromParsed(parsedConf *service.ParsedConfig) (conf old.Config, err error) {
+	conf = old.NewConfig()
+	if conf.URL, err = parsedConf.FieldString("url"); err != nil {
+		return
+	}
+	if conf.Kind, err = parsedConf.FieldString("kind"); err != nil {
+		return
+	}
+	if conf.Master, err = parsedConf.FieldString("master"); err != nil {
+		return
+	}
+
+	tlsConf := parsedConf.Namespace("tls")
+	for k, v := range map[string]*bool{
+		"enabled":              &conf.TLS.Enabled,
+		"skip_cert_verify":     &conf.TLS.InsecureSkipVerify,
+		"enable_renegotiation": &conf.TLS.EnableRenegotiation,
+	} {
+		if *v, err = tlsConf.FieldBool(k); err != nil {
+			return
+		}
+	}
+	for k, v := range map[string]*string{
+		"root_cas":        &conf.TLS.RootCAs,
+		"root_cas_file":   &conf.TLS.RootCAsFile,
+		"reload_interval": &conf.TLS.ReloadInterval,
+		"server_name":     &conf.TLS.ServerName,
+	} {
+		if *v, err = tlsConf.FieldString(k); err != nil {
+			return
+		}
+	}
+	if !tlsConf.Contains("client_certs") {
+		return
+	}
+
+	var certConfs []*service.ParsedConfig
+	if certConfs, err = tlsConf.FieldObjectList("client_certs"); err != nil {
+		return
+	}
+	for _, c := range certConfs {
+		var cert btls.ClientCertConfig
+		for k, v := range map[string]*string{
+			"cert":      &cert.Cert,
+			"key":       &cert.Key,
+			"cert_file": &cert.CertFile,
+			"key_file":  &cert.KeyFile,
+		} {
+			if *v, err = c.FieldString(k); err != nil {
+				return
+			}
+		}
+		conf.TLS.ClientCertificates = append(conf.TLS.ClientCertificates, cert)
+	}
+	return
+}
+
+// sharedClientKey returns the key under which a client created from a config
+// is shared, which includes every field that affects the connection.
+func sharedClientKey(r old.Config) (string, error) {
+	r.SharedClient = false
+	keyBytes, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return "redis:" + string(keyBytes), nil
+}
+
+// acquireSharedFn acquires a client from a registry of shared clients, which
+// is satisfied by both the internal manager and service.Resources.
+type acquireSharedFn func(key string, ctor func() (io.Closer, error)) (io.Closer, func() error, error)
+
+// acquireSharedClient returns the client shared under the key of a config,
+// creating it with the provided constructor when it does not yet exist, along
+// with a func to call instead of closing the client once it is no longer
+// needed.
+func acquireSharedClient(r old.Config, acquire acquireSharedFn, ctor func() (redis.UniversalClient, error)) (redis.UniversalClient, func() error, error) {
+	key, err := sharedClientKey(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, release, err := acquire(key, func() (io.Closer, error) {
+		return ctor()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	client, ok := c.(redis.UniversalClient)
+	if !ok {
+		_ = release()
+		return nil, nil, fmt.Errorf("shared client has unexpected type: %T", c)
+	}
+	return client, release, nil
+}
+
+// sharedClientFromConfig returns a client along with a func to call once it is
+// no longer needed, where the client is shared with other components when the
+// field shared_client is set.
+func sharedClientFromConfig(r old.Config, acquire acquireSharedFn) (redis.UniversalClient, func() error, error) {
+	if !r.SharedClient {
+		client, err := clientFromConfig(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, client.Close, nil
+	}
+	return acquireSharedClient(r, acquire, func() (redis.UniversalClient, error) {
+		return clientFromConfig(r)
+	})
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/redis/old"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	btls "github.com/benthosdev/benthos/v4/internal/tls"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestSharedClientFromConfig(t *testing.T) {
+	mgr := mock.NewManager()
+
+	conf := old.NewConfig()
+	conf.URL = "redis://localhost:6379"
+	conf.SharedClient = true
+
+	clientA, releaseA, err := sharedClientFromConfig(conf, mgr.SharedClients().Acquire)
+	require.NoError(t, err)
+
+	clientB, releaseB, err := sharedClientFromConfig(conf, mgr.SharedClients().Acquire)
+	require.NoError(t, err)
+	assert.Same(t, clientA, clientB)
+
+	tlsConf := conf
+	tlsConf.TLS.Enabled = true
+	tlsConf.TLS.InsecureSkipVerify = true
+
+	clientC, releaseC, err := sharedClientFromConfig(tlsConf, mgr.SharedClients().Acquire)
+	require.NoError(t, err)
+	assert.NotSame(t, clientA, clientC)
+
+	unsharedConf := conf
+	unsharedConf.SharedClient = false
+
+	clientD, releaseD, err := sharedClientFromConfig(unsharedConf, mgr.SharedClients().Acquire)
+	require.NoError(t, err)
+	assert.NotSame(t, clientA, clientD)
+
+	require.NoError(t, releaseA())
+	require.NoError(t, releaseB())
+	require.NoError(t, releaseC())
+	require.NoError(t, releaseD())
+}
+
+func TestSharedClientKeyFromParsed(t *testing.T) {
+	spec := service.NewConfigSpec()
+	for _, f := range clientFields() {
+		spec = spec.Field(f)
+	}
+
+	parsed, err := spec.ParseYAML(`
+url: redis://localhost:6379
+tls:
+  enabled: true
+  root_cas_file: ./foo.pem
+  client_certs:
+    - cert_file: ./bar.pem
+      key_file: ./bar.key
+`, nil)
+	require.NoError(t, err)
+
+	parsedConf, err := oldConfigFromParsed(parsed)
+	require.NoError(t, err)
+
+	conf := old.NewConfig()
+	conf.URL = "redis://localhost:6379"
+	conf.TLS.Enabled = true
+	conf.TLS.RootCAsFile = "./foo.pem"
+	conf.TLS.ClientCertificates = []btls.ClientCertConfig{
+		{CertFile: "./bar.pem", KeyFile: "./bar.key"},
+	}
+	conf.SharedClient = true
+
+	parsedKey, err := sharedClientKey(parsedConf)
+	require.NoError(t, err)
+
+	key, err := sharedClientKey(conf)
+	require.NoError(t, err)
+	assert.Equal(t, key, parsedKey)
+
+	conf.TLS.RootCAsFile = "./baz.pem"
+	key, err = sharedClientKey(conf)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, parsedKey)
+}
@@ -40,7 +40,7 @@ Pops messages from the beginning of a Redis list using the BLPop command.`,
 }
 
 func newRedisListInput(conf input.Config, mgr bundle.NewManagement, log log.Modular, stats metrics.Type) (input.Streamed, error) {
-	r, err := newRedisListReader(conf.RedisList, log)
+	r, err := newRedisListReader(conf.RedisList, mgr, log)
 	if err != nil {
 		return nil, err
 	}
@@ -48,18 +48,21 @@ func newRedisListInput(conf input.Config, mgr bundle.NewManagement, log log.Modu
 }
 
 type redisListReader struct {
-	client redis.UniversalClient
-	cMut   sync.Mutex
+	client  redis.UniversalClient
+	release func() error
+	cMut    sync.Mutex
 
 	conf    input.RedisListConfig
 	timeout time.Duration
 
+	mgr bundle.NewManagement
 	log log.Modular
 }
 
-func newRedisListReader(conf input.RedisListConfig, log log.Modular) (*redisListReader, error) {
+func newRedisListReader(conf input.RedisListConfig, mgr bundle.NewManagement, log log.Modular) (*redisListReader, error) {
 	r := &redisListReader{
 		conf: conf,
+		mgr:  mgr,
 		log:  log,
 	}
 
@@ -84,17 +87,19 @@ func (r *redisListReader) ConnectWithContext(ctx context.Context) error {
 		return nil
 	}
 
-	client, err := clientFromConfig(r.conf.Config)
-	if err == nil {
-		_, err = client.Ping().Result()
-	}
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
+	if _, err = client.Ping().Result(); err != nil {
+		_ = release()
+		return err
+	}
 
 	r.log.Infof("Receiving messages from Redis list: %v\n", r.conf.Key)
 
 	r.client = client
+	r.release = release
 	return nil
 }
 
@@ -132,7 +137,7 @@ func (r *redisListReader) disconnect() error {
 
 	var err error
 	if r.client != nil {
-		err = r.client.Close()
+		err = r.release()
 		r.client = nil
 	}
 	return err
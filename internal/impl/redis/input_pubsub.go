@@ -51,7 +51,7 @@ verbatim.`,
 }
 
 func newRedisPubSubInput(conf input.Config, mgr bundle.NewManagement, log log.Modular, stats metrics.Type) (input.Streamed, error) {
-	r, err := newRedisPubSubReader(conf.RedisPubSub, log)
+	r, err := newRedisPubSubReader(conf.RedisPubSub, mgr, log)
 	if err != nil {
 		return nil, err
 	}
@@ -59,18 +59,21 @@ func newRedisPubSubInput(conf input.Config, mgr bundle.NewManagement, log log.Mo
 }
 
 type redisPubSubReader struct {
-	client redis.UniversalClient
-	pubsub *redis.PubSub
-	cMut   sync.Mutex
+	client  redis.UniversalClient
+	release func() error
+	pubsub  *redis.PubSub
+	cMut    sync.Mutex
 
 	conf input.RedisPubSubConfig
 
+	mgr bundle.NewManagement
 	log log.Modular
 }
 
-func newRedisPubSubReader(conf input.RedisPubSubConfig, log log.Modular) (*redisPubSubReader, error) {
+func newRedisPubSubReader(conf input.RedisPubSubConfig, mgr bundle.NewManagement, log log.Modular) (*redisPubSubReader, error) {
 	r := &redisPubSubReader{
 		conf: conf,
+		mgr:  mgr,
 		log:  log,
 	}
 
@@ -90,17 +93,19 @@ func (r *redisPubSubReader) ConnectWithContext(ctx context.Context) error {
 		return nil
 	}
 
-	client, err := clientFromConfig(r.conf.Config)
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
 	if _, err := client.Ping().Result(); err != nil {
+		_ = release()
 		return err
 	}
 
 	r.log.Infof("Receiving Redis pub/sub messages from channels: %v\n", r.conf.Channels)
 
 	r.client = client
+	r.release = release
 	if r.conf.UsePatterns {
 		r.pubsub = r.client.PSubscribe(r.conf.Channels...)
 	} else {
@@ -145,7 +150,7 @@ func (r *redisPubSubReader) disconnect() error {
 		r.pubsub = nil
 	}
 	if r.client != nil {
-		err = r.client.Close()
+		err = r.release()
 		r.client = nil
 	}
 	return err
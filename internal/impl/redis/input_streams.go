@@ -31,7 +31,25 @@ Pulls messages from Redis (v5.0+) streams with the XREADGROUP command. The
 		Description: `
 Redis stream entries are key/value pairs, as such it is necessary to specify the
 key that contains the body of the message. All other keys/value pairs are saved
-as metadata fields.`,
+as metadata fields.
+
+Entries are only acknowledged with the XACK command once they have been
+successfully delivered by Benthos, which means entries that were consumed but
+not acknowledged remain pending within the consumer group when a consumer
+terminates unexpectedly.
+
+### Claiming Pending Entries
+
+When ` + "`auto_claim.enabled`" + ` is set to ` + "`true`" + ` the pending entries of the consumer
+group are checked periodically, and entries belonging to other consumers that
+have been idle for longer than ` + "`auto_claim.min_idle`" + ` are claimed with the XCLAIM
+command and consumed again, which allows entries abandoned by consumers that
+have died to be recovered by those that remain. This is equivalent to the
+XAUTOCLAIM command but is also supported by Redis versions prior to 6.2.
+
+The value of ` + "`auto_claim.min_idle`" + ` should be larger than the longest time
+taken to process and deliver an entry, otherwise entries that are still being
+processed by a healthy consumer might be claimed and duplicated.`,
 		Config: docs.FieldComponent().WithChildren(old.ConfigDocs()...).WithChildren(
 			docs.FieldString("body_key", "The field key to extract the raw message from. All other keys will be stored in the message as metadata."),
 			docs.FieldString("streams", "A list of streams to consume from.").Array(),
@@ -42,6 +60,11 @@ as metadata fields.`,
 			docs.FieldBool("start_from_oldest", "If an offset is not found for a stream, determines whether to consume from the oldest available offset, otherwise messages are consumed from the latest offset.").Advanced(),
 			docs.FieldString("commit_period", "The period of time between each commit of the current offset. Offsets are always committed during shutdown.").Advanced(),
 			docs.FieldString("timeout", "The length of time to poll for new messages before reattempting.").Advanced(),
+			docs.FieldObject("auto_claim", "Allows pending entries of other consumers in the group to be claimed once they have been idle for a period of time.").WithChildren(
+				docs.FieldBool("enabled", "Whether to claim idle pending entries of other consumers."),
+				docs.FieldString("min_idle", "The minimum period of time that a pending entry must have been idle for before it is claimed."),
+				docs.FieldString("period", "The period of time between each check for idle pending entries."),
+			).Advanced().AtVersion("4.2.0"),
 		).ChildDefaultAndTypesFromStruct(input.NewRedisStreamsConfig()),
 		Categories: []string{
 			"Services",
@@ -55,7 +78,7 @@ as metadata fields.`,
 func newRedisStreamsInput(conf input.Config, mgr bundle.NewManagement, log log.Modular, stats metrics.Type) (input.Streamed, error) {
 	var c input.Async
 	var err error
-	if c, err = newRedisStreamsReader(conf.RedisStreams, log); err != nil {
+	if c, err = newRedisStreamsReader(conf.RedisStreams, mgr, log); err != nil {
 		return nil, err
 	}
 	c = input.NewAsyncPreserver(c)
@@ -70,12 +93,15 @@ type pendingRedisStreamMsg struct {
 
 type redisStreamsReader struct {
 	client         redis.UniversalClient
+	release        func() error
 	cMut           sync.Mutex
 	pendingMsgs    []pendingRedisStreamMsg
 	pendingMsgsMut sync.Mutex
 
 	timeout      time.Duration
 	commitPeriod time.Duration
+	claimMinIdle time.Duration
+	claimPeriod  time.Duration
 
 	conf input.RedisStreamsConfig
 
@@ -84,6 +110,7 @@ type redisStreamsReader struct {
 	aMut    sync.Mutex
 	ackSend map[string][]string // Acks that can be sent
 
+	mgr bundle.NewManagement
 	log log.Modular
 
 	closeChan  chan struct{}
@@ -91,9 +118,10 @@ type redisStreamsReader struct {
 	closeOnce  sync.Once
 }
 
-func newRedisStreamsReader(conf input.RedisStreamsConfig, log log.Modular) (*redisStreamsReader, error) {
+func newRedisStreamsReader(conf input.RedisStreamsConfig, mgr bundle.NewManagement, log log.Modular) (*redisStreamsReader, error) {
 	r := &redisStreamsReader{
 		conf:       conf,
+		mgr:        mgr,
 		log:        log,
 		backlogs:   make(map[string]string, len(conf.Streams)),
 		ackSend:    make(map[string][]string, len(conf.Streams)),
@@ -123,6 +151,19 @@ func newRedisStreamsReader(conf input.RedisStreamsConfig, log log.Modular) (*red
 		}
 	}
 
+	if conf.AutoClaim.Enabled {
+		var err error
+		if r.claimMinIdle, err = time.ParseDuration(conf.AutoClaim.MinIdle); err != nil {
+			return nil, fmt.Errorf("failed to parse auto claim min idle string: %v", err)
+		}
+		if r.claimPeriod, err = time.ParseDuration(conf.AutoClaim.Period); err != nil {
+			return nil, fmt.Errorf("failed to parse auto claim period string: %v", err)
+		}
+		if r.claimPeriod <= 0 {
+			return nil, fmt.Errorf("auto claim period must be greater than zero, got %v", conf.AutoClaim.Period)
+		}
+	}
+
 	go r.loop()
 	return r, nil
 }
@@ -134,19 +175,30 @@ func (r *redisStreamsReader) loop() {
 		var client redis.UniversalClient
 		r.cMut.Lock()
 		client = r.client
+		release := r.release
 		r.client = nil
 		r.cMut.Unlock()
 		if client != nil {
-			client.Close()
+			_ = release()
 		}
 		close(r.closedChan)
 	}()
 	commitTimer := time.NewTicker(r.commitPeriod)
 
+	var claimChan <-chan time.Time
+	if r.conf.AutoClaim.Enabled {
+		claimTimer := time.NewTicker(r.claimPeriod)
+		defer claimTimer.Stop()
+		claimChan = claimTimer.C
+	}
+
 	closed := false
 	for !closed {
 		select {
 		case <-commitTimer.C:
+		case <-claimChan:
+			r.claimPending()
+			continue
 		case <-r.closeChan:
 			closed = true
 		}
@@ -190,6 +242,86 @@ func (r *redisStreamsReader) sendAcks() {
 	}
 }
 
+// claimPending claims the pending entries of other consumers within the group
+// that have been idle for at least the configured period, and adds them to the
+// messages waiting to be read.
+func (r *redisStreamsReader) claimPending() {
+	var client redis.UniversalClient
+	r.cMut.Lock()
+	client = r.client
+	r.cMut.Unlock()
+
+	if client == nil {
+		return
+	}
+
+	for _, str := range r.conf.Streams {
+		var claimIDs []string
+		start := "-"
+		for {
+			pending, err := client.XPendingExt(&redis.XPendingExtArgs{
+				Stream: str,
+				Group:  r.conf.ConsumerGroup,
+				Start:  start,
+				End:    "+",
+				Count:  r.conf.Limit,
+			}).Result()
+			if err != nil {
+				r.log.Errorf("Failed to list pending entries of stream %v: %v\n", str, err)
+				break
+			}
+			for i, p := range pending {
+				// Ranges are inclusive, so the first entry of subsequent pages
+				// has already been seen.
+				if i == 0 && start != "-" {
+					continue
+				}
+				if p.Consumer != r.conf.ClientID && p.Idle >= r.claimMinIdle {
+					claimIDs = append(claimIDs, p.ID)
+				}
+			}
+			if len(pending) == 0 || int64(len(pending)) < r.conf.Limit || pending[len(pending)-1].ID == start {
+				break
+			}
+			start = pending[len(pending)-1].ID
+		}
+		if len(claimIDs) == 0 {
+			continue
+		}
+
+		claimed, err := client.XClaim(&redis.XClaimArgs{
+			Stream:   str,
+			Group:    r.conf.ConsumerGroup,
+			Consumer: r.conf.ClientID,
+			MinIdle:  r.claimMinIdle,
+			Messages: claimIDs,
+		}).Result()
+		if err != nil {
+			r.log.Errorf("Failed to claim pending entries of stream %v: %v\n", str, err)
+			continue
+		}
+		if len(claimed) == 0 {
+			continue
+		}
+		r.log.Debugf("Claimed %v pending entries of stream %v\n", len(claimed), str)
+
+		var claimedMsgs []pendingRedisStreamMsg
+		for _, xmsg := range claimed {
+			msg, ok := r.toPendingMsg(str, xmsg)
+			if !ok {
+				// Entries without a body would otherwise be claimed repeatedly.
+				r.addAsyncAcks(str, xmsg.ID)
+				continue
+			}
+			claimedMsgs = append(claimedMsgs, msg)
+		}
+
+		r.pendingMsgsMut.Lock()
+		r.pendingMsgs = append(r.pendingMsgs, claimedMsgs...)
+		r.pendingMsgsMut.Unlock()
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // ConnectWithContext establishes a connection to a Redis server.
@@ -201,11 +333,12 @@ func (r *redisStreamsReader) ConnectWithContext(ctx context.Context) error {
 		return nil
 	}
 
-	client, err := clientFromConfig(r.conf.Config)
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
 	if _, err := client.Ping().Result(); err != nil {
+		_ = release()
 		return err
 	}
 
@@ -221,6 +354,7 @@ func (r *redisStreamsReader) ConnectWithContext(ctx context.Context) error {
 			err = client.XGroupCreate(s, r.conf.ConsumerGroup, offset).Err()
 		}
 		if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
+			_ = release()
 			return fmt.Errorf("failed to create group %v for stream %v: %v", r.conf.ConsumerGroup, s, err)
 		}
 	}
@@ -228,6 +362,7 @@ func (r *redisStreamsReader) ConnectWithContext(ctx context.Context) error {
 	r.log.Infof("Receiving messages from Redis streams: %v\n", r.conf.Streams)
 
 	r.client = client
+	r.release = release
 	return nil
 }
 
@@ -288,35 +423,10 @@ func (r *redisStreamsReader) read() (pendingRedisStreamMsg, error) {
 			}
 		}
 		for _, xmsg := range strRes.Messages {
-			body, exists := xmsg.Values[r.conf.BodyKey]
-			if !exists {
-				continue
-			}
-			delete(xmsg.Values, r.conf.BodyKey)
-
-			var bodyBytes []byte
-			switch t := body.(type) {
-			case string:
-				bodyBytes = []byte(t)
-			case []byte:
-				bodyBytes = t
-			}
-			if bodyBytes == nil {
+			nextMsg, ok := r.toPendingMsg(strRes.Stream, xmsg)
+			if !ok {
 				continue
 			}
-
-			part := message.NewPart(bodyBytes)
-			part.MetaSet("redis_stream", xmsg.ID)
-			for k, v := range xmsg.Values {
-				part.MetaSet(k, fmt.Sprintf("%v", v))
-			}
-
-			nextMsg := pendingRedisStreamMsg{
-				payload: message.QuickBatch(nil),
-				stream:  strRes.Stream,
-				id:      xmsg.ID,
-			}
-			nextMsg.payload.Append(part)
 			if msg.payload == nil {
 				msg = nextMsg
 			} else {
@@ -332,6 +442,39 @@ func (r *redisStreamsReader) read() (pendingRedisStreamMsg, error) {
 	return msg, nil
 }
 
+func (r *redisStreamsReader) toPendingMsg(stream string, xmsg redis.XMessage) (pendingRedisStreamMsg, bool) {
+	body, exists := xmsg.Values[r.conf.BodyKey]
+	if !exists {
+		return pendingRedisStreamMsg{}, false
+	}
+	delete(xmsg.Values, r.conf.BodyKey)
+
+	var bodyBytes []byte
+	switch t := body.(type) {
+	case string:
+		bodyBytes = []byte(t)
+	case []byte:
+		bodyBytes = t
+	}
+	if bodyBytes == nil {
+		return pendingRedisStreamMsg{}, false
+	}
+
+	part := message.NewPart(bodyBytes)
+	part.MetaSet("redis_stream", xmsg.ID)
+	for k, v := range xmsg.Values {
+		part.MetaSet(k, fmt.Sprintf("%v", v))
+	}
+
+	msg := pendingRedisStreamMsg{
+		payload: message.QuickBatch(nil),
+		stream:  stream,
+		id:      xmsg.ID,
+	}
+	msg.payload.Append(part)
+	return msg, true
+}
+
 func (r *redisStreamsReader) ReadWithContext(ctx context.Context) (*message.Batch, input.AsyncAckFn, error) {
 	msg, err := r.read()
 	if err != nil {
@@ -367,7 +510,7 @@ func (r *redisStreamsReader) disconnect() error {
 
 	var err error
 	if r.client != nil {
-		err = r.client.Close()
+		err = r.release()
 		r.client = nil
 	}
 	return err
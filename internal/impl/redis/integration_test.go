@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,9 +12,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/integration"
 	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
 )
 
 func TestIntegrationRedis(t *testing.T) {
@@ -35,7 +39,7 @@ func TestIntegrationRedis(t *testing.T) {
 		conf := output.NewRedisStreamsConfig()
 		conf.URL = fmt.Sprintf("tcp://localhost:%v", resource.GetPort("6379/tcp"))
 
-		r, cErr := newRedisStreamsWriter(conf, log.Noop())
+		r, cErr := newRedisStreamsWriter(conf, mock.NewManager(), log.Noop())
 		if cErr != nil {
 			return cErr
 		}
@@ -100,6 +104,75 @@ input:
 		})
 	})
 
+	t.Run("streams auto claim", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{
+			Addr:    fmt.Sprintf("localhost:%v", resource.GetPort("6379/tcp")),
+			Network: "tcp",
+		})
+		t.Cleanup(func() {
+			client.Close()
+		})
+
+		require.NoError(t, client.XGroupCreateMkStream("stream-claim", "group-claim", "0").Err())
+		for _, body := range []string{"foo", "bar"} {
+			require.NoError(t, client.XAdd(&redis.XAddArgs{
+				Stream: "stream-claim",
+				Values: map[string]interface{}{"body": body},
+			}).Err())
+		}
+
+		// A consumer that reads entries without acknowledging them.
+		res, err := client.XReadGroup(&redis.XReadGroupArgs{
+			Group:    "group-claim",
+			Consumer: "dead-consumer",
+			Streams:  []string{"stream-claim", ">"},
+			Count:    10,
+		}).Result()
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].Messages, 2)
+
+		conf := input.NewRedisStreamsConfig()
+		conf.URL = fmt.Sprintf("tcp://localhost:%v", resource.GetPort("6379/tcp"))
+		conf.Streams = []string{"stream-claim"}
+		conf.ClientID = "live-consumer"
+		conf.ConsumerGroup = "group-claim"
+		conf.AutoClaim.Enabled = true
+		conf.AutoClaim.MinIdle = "100ms"
+		conf.AutoClaim.Period = "100ms"
+
+		r, err := newRedisStreamsReader(conf, mock.NewManager(), log.Noop())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			r.CloseAsync()
+			assert.NoError(t, r.WaitForClose(time.Second))
+		})
+
+		ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+		defer done()
+		require.NoError(t, r.ConnectWithContext(ctx))
+
+		var bodies []string
+		for len(bodies) < 2 {
+			msg, ackFn, err := r.ReadWithContext(ctx)
+			if err == component.ErrTimeout {
+				require.NoError(t, ctx.Err())
+				continue
+			}
+			require.NoError(t, err)
+			bodies = append(bodies, string(msg.Get(0).Get()))
+			require.NoError(t, ackFn(ctx, nil))
+		}
+		assert.ElementsMatch(t, []string{"foo", "bar"}, bodies)
+
+		assert.Eventually(t, func() bool {
+			pending, err := client.XPending("stream-claim", "group-claim").Result()
+			return err == nil && pending.Count == 0
+		}, time.Second*10, time.Millisecond*100)
+	})
+
 	t.Run("pubsub", func(t *testing.T) {
 		t.Parallel()
 		template := `
@@ -184,6 +257,15 @@ input:
 				integration.StreamTestOptMaxInFlight(10),
 			)
 		})
+		t.Run("transactional", func(t *testing.T) {
+			t.Parallel()
+			suite.Run(
+				t, strings.Replace(template, "    max_in_flight: $MAX_IN_FLIGHT\n", "    max_in_flight: $MAX_IN_FLIGHT\n    transactional: true\n", 1),
+				integration.StreamTestOptSleepAfterInput(100*time.Millisecond),
+				integration.StreamTestOptSleepAfterOutput(100*time.Millisecond),
+				integration.StreamTestOptPort(resource.GetPort("6379/tcp")),
+			)
+		})
 	})
 
 	// HASH
@@ -241,7 +323,7 @@ func BenchmarkIntegrationRedis(b *testing.B) {
 		conf := output.NewRedisStreamsConfig()
 		conf.URL = fmt.Sprintf("tcp://localhost:%v", resource.GetPort("6379/tcp"))
 
-		r, cErr := newRedisStreamsWriter(conf, log.Noop())
+		r, cErr := newRedisStreamsWriter(conf, mock.NewManager(), log.Noop())
 		if cErr != nil {
 			return cErr
 		}
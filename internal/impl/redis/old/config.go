@@ -7,18 +7,20 @@ import (
 
 // Config is a config struct for a redis connection.
 type Config struct {
-	URL    string      `json:"url" yaml:"url"`
-	Kind   string      `json:"kind" yaml:"kind"`
-	Master string      `json:"master" yaml:"master"`
-	TLS    btls.Config `json:"tls" yaml:"tls"`
+	URL          string      `json:"url" yaml:"url"`
+	Kind         string      `json:"kind" yaml:"kind"`
+	Master       string      `json:"master" yaml:"master"`
+	TLS          btls.Config `json:"tls" yaml:"tls"`
+	SharedClient bool        `json:"shared_client" yaml:"shared_client"`
 }
 
 // NewConfig returns a Config with default values.
 func NewConfig() Config {
 	return Config{
-		URL:  "",
-		Kind: "simple",
-		TLS:  btls.NewConfig(),
+		URL:          "",
+		Kind:         "simple",
+		TLS:          btls.NewConfig(),
+		SharedClient: false,
 	}
 }
 
@@ -43,5 +45,6 @@ Some cloud hosted instances of Redis (such as Azure Cache) might need some hand
 		docs.FieldString("kind", "Specifies a simple, cluster-aware, or failover-aware redis client.", "simple", "cluster", "failover").HasDefault("simple").Advanced(),
 		docs.FieldString("master", "Name of the redis master when `kind` is `failover`", "mymaster").HasDefault("").Advanced(),
 		tlsSpec,
+		docs.FieldBool("shared_client", "Whether to share the underlying client with other components of the service that also enable this field and have identical `url`, `kind`, `master` and `tls` settings, which reduces the number of connections opened to Redis.").HasDefault(false).Advanced().AtVersion("4.2.0"),
 	}
 }
@@ -100,13 +100,16 @@ type redisHashWriter struct {
 	keyStr *field.Expression
 	fields map[string]*field.Expression
 
+	mgr     bundle.NewManagement
 	client  redis.UniversalClient
+	release func() error
 	connMut sync.RWMutex
 }
 
 func newRedisHashWriter(conf output.RedisHashConfig, mgr bundle.NewManagement, log log.Modular) (*redisHashWriter, error) {
 	r := &redisHashWriter{
 		log:    log,
+		mgr:    mgr,
 		conf:   conf,
 		fields: map[string]*field.Expression{},
 	}
@@ -137,17 +140,19 @@ func (r *redisHashWriter) ConnectWithContext(ctx context.Context) error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 
-	client, err := clientFromConfig(r.conf.Config)
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
 	if _, err = client.Ping().Result(); err != nil {
+		_ = release()
 		return err
 	}
 
 	r.log.Infoln("Setting messages as hash objects to Redis")
 
 	r.client = client
+	r.release = release
 	return nil
 }
 
@@ -211,7 +216,7 @@ func (r *redisHashWriter) disconnect() error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 	if r.client != nil {
-		err := r.client.Close()
+		err := r.release()
 		r.client = nil
 		return err
 	}
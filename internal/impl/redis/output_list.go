@@ -34,13 +34,35 @@ already exist) using the RPUSH command.`,
 		Description: output.Description(true, true, `
 The field `+"`key`"+` supports
 [interpolation functions](/docs/configuration/interpolation#bloblang-queries), allowing
-you to create a unique key for each message.`),
+you to create a unique key for each message.
+
+### Transactional Writes
+
+By default the messages of a batch are written with a pipeline, where a failure
+part way through the batch leaves the messages before it written. When
+`+"`transactional`"+` is set to `+"`true`"+` each batch is instead written within a
+MULTI/EXEC transaction, which is applied without being interleaved with other
+clients and is discarded entirely when any command of it is rejected or the
+connection fails, in which case the whole batch is retried. Note that Redis
+does not roll back transactions, and so a command that fails during execution,
+for example because the key holds a value that is not a list, does not prevent
+the remaining commands from being applied.
+
+When using a `+"`cluster`"+` client a transaction can only contain keys that
+belong to the same hash slot, and therefore the messages of a batch are grouped
+by the slot of their key and each group is written in its own transaction. In
+order for a batch to be applied atomically across multiple keys use
+[hash tags](https://redis.io/docs/reference/cluster-spec/#hash-tags) so that
+they belong to the same slot.`),
 		Config: docs.FieldComponent().WithChildren(old.ConfigDocs()...).WithChildren(
 			docs.FieldString(
 				"key", "The key for each message, function interpolations can be optionally used to create a unique key per message.",
 				"benthos_list", "${!meta(\"kafka_key\")}", "${!json(\"doc.id\")}", "${!count(\"msgs\")}",
 			).IsInterpolated(),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldInt("max_length", "When greater than zero the list is trimmed after each write with the LTRIM command so that only the latest entries up to this length are kept, allowing a list to be used as a bounded queue.").AtVersion("4.2.0"),
+			docs.FieldString("ttl", "An optional TTL to set on the list after each write with the EXPIRE command, which results in the list being removed once it has not been written to for this period.", "60s", "24h").AtVersion("4.2.0"),
+			docs.FieldBool("transactional", "Whether to write each batch within a MULTI/EXEC transaction, as described in [transactional writes](#transactional-writes).").Advanced().AtVersion("4.2.0"),
 			policy.FieldSpec(),
 		).ChildDefaultAndTypesFromStruct(output.NewRedisListConfig()),
 		Categories: []string{
@@ -70,14 +92,18 @@ type redisListWriter struct {
 	conf output.RedisListConfig
 
 	keyStr *field.Expression
+	ttl    time.Duration
 
+	mgr     bundle.NewManagement
 	client  redis.UniversalClient
+	release func() error
 	connMut sync.RWMutex
 }
 
 func newRedisListWriter(conf output.RedisListConfig, mgr bundle.NewManagement, log log.Modular) (*redisListWriter, error) {
 	r := &redisListWriter{
 		log:  log,
+		mgr:  mgr,
 		conf: conf,
 	}
 
@@ -85,6 +111,14 @@ func newRedisListWriter(conf output.RedisListConfig, mgr bundle.NewManagement, l
 	if r.keyStr, err = mgr.BloblEnvironment().NewField(conf.Key); err != nil {
 		return nil, fmt.Errorf("failed to parse key expression: %v", err)
 	}
+	if conf.MaxLength < 0 {
+		return nil, fmt.Errorf("max length must not be negative, got %v", conf.MaxLength)
+	}
+	if conf.TTL != "" {
+		if r.ttl, err = time.ParseDuration(conf.TTL); err != nil {
+			return nil, fmt.Errorf("failed to parse ttl: %v", err)
+		}
+	}
 	if _, err := clientFromConfig(conf.Config); err != nil {
 		return nil, err
 	}
@@ -96,15 +130,17 @@ func (r *redisListWriter) ConnectWithContext(ctx context.Context) error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 
-	client, err := clientFromConfig(r.conf.Config)
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
 	if _, err = client.Ping().Result(); err != nil {
+		_ = release()
 		return err
 	}
 
 	r.client = client
+	r.release = release
 	return nil
 }
 
@@ -117,7 +153,7 @@ func (r *redisListWriter) WriteWithContext(ctx context.Context, msg *message.Bat
 		return component.ErrNotConnected
 	}
 
-	if msg.Len() == 1 {
+	if msg.Len() == 1 && r.conf.MaxLength == 0 && r.ttl == 0 {
 		key := r.keyStr.String(0, msg)
 		if err := client.RPush(key, msg.Get(0).Get()).Err(); err != nil {
 			_ = r.disconnect()
@@ -127,21 +163,38 @@ func (r *redisListWriter) WriteWithContext(ctx context.Context, msg *message.Bat
 		return nil
 	}
 
-	pipe := client.Pipeline()
+	var pipe redis.Pipeliner
+	if r.conf.Transactional {
+		// Cluster clients group the commands of a transaction by hash slot,
+		// with each group executed as a separate transaction.
+		pipe = client.TxPipeline()
+	} else {
+		pipe = client.Pipeline()
+	}
+	pushCmds := make([]*redis.IntCmd, msg.Len())
+	var keys []string
+	keySet := map[string]struct{}{}
 	_ = msg.Iter(func(i int, p *message.Part) error {
-		key := r.keyStr.String(0, msg)
-		_ = pipe.RPush(key, p.Get())
+		key := r.keyStr.String(i, msg)
+		pushCmds[i] = pipe.RPush(key, p.Get())
+		if _, exists := keySet[key]; !exists {
+			keySet[key] = struct{}{}
+			keys = append(keys, key)
+		}
 		return nil
 	})
-	cmders, err := pipe.Exec()
-	if err != nil {
-		_ = r.disconnect()
-		r.log.Errorf("Error from redis: %v\n", err)
-		return component.ErrNotConnected
+	for _, key := range keys {
+		if r.conf.MaxLength > 0 {
+			_ = pipe.LTrim(key, -r.conf.MaxLength, -1)
+		}
+		if r.ttl > 0 {
+			_ = pipe.Expire(key, r.ttl)
+		}
 	}
+	_, err := pipe.Exec()
 
 	var batchErr *ibatch.Error
-	for i, res := range cmders {
+	for i, res := range pushCmds {
 		if res.Err() != nil {
 			if batchErr == nil {
 				batchErr = ibatch.NewError(msg, res.Err())
@@ -150,8 +203,19 @@ func (r *redisListWriter) WriteWithContext(ctx context.Context, msg *message.Bat
 		}
 	}
 	if batchErr != nil {
+		if !r.conf.Transactional {
+			_ = r.disconnect()
+			r.log.Errorf("Error from redis: %v\n", err)
+			return component.ErrNotConnected
+		}
+		r.log.Errorf("Error from redis, transaction discarded: %v\n", err)
 		return batchErr
 	}
+	if err != nil {
+		// Messages were written successfully, and so failures to trim or
+		// expire the list are not retried.
+		r.log.Errorf("Failed to trim or expire list: %v\n", err)
+	}
 	return nil
 }
 
@@ -159,7 +223,7 @@ func (r *redisListWriter) disconnect() error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 	if r.client != nil {
-		err := r.client.Close()
+		err := r.release()
 		r.client = nil
 		return err
 	}
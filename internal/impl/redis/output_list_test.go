@@ -0,0 +1,251 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// fakeRedis is a minimal Redis server that records the commands it receives
+// and rejects the commands listed in errCmds.
+type fakeRedis struct {
+	ln net.Listener
+
+	mut     sync.Mutex
+	cmds    []string
+	errCmds map[string]bool
+}
+
+func newFakeRedis(t *testing.T, errCmds ...string) *fakeRedis {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	f := &fakeRedis{ln: ln, errCmds: map[string]bool{}}
+	for _, c := range errCmds {
+		f.errCmds[c] = true
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+	return f
+}
+
+func (f *fakeRedis) URL() string {
+	return "redis://" + f.ln.Addr().String()
+}
+
+func (f *fakeRedis) Commands() []string {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return append([]string(nil), f.cmds...)
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(r)
+		if err != nil {
+			return
+		}
+		name := strings.ToUpper(args[0])
+
+		var reply string
+		switch {
+		case name == "PING":
+			reply = "+PONG\r\n"
+		case f.errCmds[name]:
+			reply = fmt.Sprintf("-ERR %v rejected\r\n", strings.ToLower(name))
+		case name == "LTRIM":
+			reply = "+OK\r\n"
+		default:
+			reply = ":1\r\n"
+		}
+		if name != "PING" {
+			f.mut.Lock()
+			f.cmds = append(f.cmds, strings.Join(args, " "))
+			f.mut.Unlock()
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPArray(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected line: %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		l, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, l+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:l])
+	}
+	return args, nil
+}
+
+func TestRedisListWriterTrimAndExpire(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLength int64
+		ttl       string
+		errCmds   []string
+		batch     []string
+		cmds      []string
+		err       error
+	}{
+		{
+			name:      "trim and expire",
+			maxLength: 2,
+			ttl:       "60s",
+			batch:     []string{"foo", "bar", "baz"},
+			cmds: []string{
+				"rpush foo:foo foo",
+				"rpush foo:bar bar",
+				"rpush foo:baz baz",
+				"ltrim foo:foo -2 -1",
+				"expire foo:foo 60",
+				"ltrim foo:bar -2 -1",
+				"expire foo:bar 60",
+				"ltrim foo:baz -2 -1",
+				"expire foo:baz 60",
+			},
+		},
+		{
+			name:      "single message trimmed",
+			maxLength: 10,
+			batch:     []string{"foo"},
+			cmds: []string{
+				"rpush foo:foo foo",
+				"ltrim foo:foo -10 -1",
+			},
+		},
+		{
+			name:  "single message without trim",
+			batch: []string{"foo"},
+			cmds: []string{
+				"rpush foo:foo foo",
+			},
+		},
+		{
+			name:      "trim errors are not retried",
+			maxLength: 2,
+			ttl:       "1m",
+			errCmds:   []string{"LTRIM", "EXPIRE"},
+			batch:     []string{"foo"},
+			cmds: []string{
+				"rpush foo:foo foo",
+				"ltrim foo:foo -2 -1",
+				"expire foo:foo 60",
+			},
+		},
+		{
+			name:      "push errors are retried",
+			maxLength: 2,
+			errCmds:   []string{"RPUSH"},
+			batch:     []string{"foo", "bar"},
+			cmds: []string{
+				"rpush foo:foo foo",
+				"rpush foo:bar bar",
+				"ltrim foo:foo -2 -1",
+				"ltrim foo:bar -2 -1",
+			},
+			err: component.ErrNotConnected,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			srv := newFakeRedis(t, test.errCmds...)
+
+			conf := output.NewRedisListConfig()
+			conf.URL = srv.URL()
+			conf.Key = `foo:${! content() }`
+			conf.MaxLength = test.maxLength
+			conf.TTL = test.ttl
+
+			w, err := newRedisListWriter(conf, mock.NewManager(), log.Noop())
+			require.NoError(t, err)
+			require.NoError(t, w.ConnectWithContext(context.Background()))
+			t.Cleanup(func() {
+				_ = w.disconnect()
+			})
+
+			var parts [][]byte
+			for _, p := range test.batch {
+				parts = append(parts, []byte(p))
+			}
+			err = w.WriteWithContext(context.Background(), message.QuickBatch(parts))
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.cmds, srv.Commands())
+
+			w.connMut.RLock()
+			connected := w.client != nil
+			w.connMut.RUnlock()
+			assert.Equal(t, test.err == nil, connected)
+		})
+	}
+}
+
+func TestRedisListWriterBadConfig(t *testing.T) {
+	conf := output.NewRedisListConfig()
+	conf.URL = "redis://localhost:6379"
+	conf.MaxLength = -1
+
+	_, err := newRedisListWriter(conf, mock.NewManager(), log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max length must not be negative")
+
+	conf.MaxLength = 0
+	conf.TTL = "nope"
+	_, err = newRedisListWriter(conf, mock.NewManager(), log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse ttl")
+}
@@ -66,13 +66,16 @@ type redisPubSubWriter struct {
 	conf       output.RedisPubSubConfig
 	channelStr *field.Expression
 
+	mgr     bundle.NewManagement
 	client  redis.UniversalClient
+	release func() error
 	connMut sync.RWMutex
 }
 
 func newRedisPubSubWriter(conf output.RedisPubSubConfig, mgr bundle.NewManagement, log log.Modular) (*redisPubSubWriter, error) {
 	r := &redisPubSubWriter{
 		log:  log,
+		mgr:  mgr,
 		conf: conf,
 	}
 	var err error
@@ -89,17 +92,19 @@ func (r *redisPubSubWriter) ConnectWithContext(ctx context.Context) error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 
-	client, err := clientFromConfig(r.conf.Config)
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
 	if _, err = client.Ping().Result(); err != nil {
+		_ = release()
 		return err
 	}
 
 	r.log.Infof("Pushing messages to Redis channel: %v\n", r.conf.Channel)
 
 	r.client = client
+	r.release = release
 	return nil
 }
 
@@ -153,7 +158,7 @@ func (r *redisPubSubWriter) disconnect() error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 	if r.client != nil {
-		err := r.client.Close()
+		err := r.release()
 		r.client = nil
 		return err
 	}
@@ -58,7 +58,7 @@ a metadata item and the body then the body takes precedence.`),
 }
 
 func newRedisStreamsOutput(conf output.Config, mgr bundle.NewManagement, log log.Modular, stats metrics.Type) (output.Streamed, error) {
-	w, err := newRedisStreamsWriter(conf.RedisStreams, log)
+	w, err := newRedisStreamsWriter(conf.RedisStreams, mgr, log)
 	if err != nil {
 		return nil, err
 	}
@@ -75,14 +75,17 @@ type redisStreamsWriter struct {
 	conf       output.RedisStreamsConfig
 	metaFilter *metadata.ExcludeFilter
 
+	mgr     bundle.NewManagement
 	client  redis.UniversalClient
+	release func() error
 	connMut sync.RWMutex
 }
 
-func newRedisStreamsWriter(conf output.RedisStreamsConfig, log log.Modular) (*redisStreamsWriter, error) {
+func newRedisStreamsWriter(conf output.RedisStreamsConfig, mgr bundle.NewManagement, log log.Modular) (*redisStreamsWriter, error) {
 
 	r := &redisStreamsWriter{
 		log:  log,
+		mgr:  mgr,
 		conf: conf,
 	}
 
@@ -101,17 +104,19 @@ func (r *redisStreamsWriter) ConnectWithContext(ctx context.Context) error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 
-	client, err := clientFromConfig(r.conf.Config)
+	client, release, err := sharedClientFromConfig(r.conf.Config, r.mgr.SharedClients().Acquire)
 	if err != nil {
 		return err
 	}
 	if _, err = client.Ping().Result(); err != nil {
+		_ = release()
 		return err
 	}
 
 	r.log.Infof("Pushing messages to Redis stream: %v\n", r.conf.Stream)
 
 	r.client = client
+	r.release = release
 	return nil
 }
 
@@ -184,7 +189,7 @@ func (r *redisStreamsWriter) disconnect() error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 	if r.client != nil {
-		err := r.client.Close()
+		err := r.release()
 		r.client = nil
 		return err
 	}
@@ -140,6 +140,7 @@ type redisProc struct {
 
 	operator    redisOperator
 	client      redis.UniversalClient
+	release     func() error
 	retries     int
 	retryPeriod time.Duration
 }
@@ -153,14 +154,14 @@ func newRedisProc(conf processor.RedisConfig, mgr bundle.NewManagement) (*redisP
 		}
 	}
 
-	client, err := clientFromConfig(conf.Config)
+	key, err := mgr.BloblEnvironment().NewField(conf.Key)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse key expression: %v", err)
 	}
 
-	key, err := mgr.BloblEnvironment().NewField(conf.Key)
+	client, release, err := sharedClientFromConfig(conf.Config, mgr.SharedClients().Acquire)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse key expression: %v", err)
+		return nil, err
 	}
 
 	r := &redisProc{
@@ -170,9 +171,11 @@ func newRedisProc(conf processor.RedisConfig, mgr bundle.NewManagement) (*redisP
 		retries:     conf.Retries,
 		retryPeriod: retryPeriod,
 		client:      client,
+		release:     release,
 	}
 
 	if r.operator, err = getRedisOperator(conf.Operator); err != nil {
+		_ = release()
 		return nil, err
 	}
 	return r, nil
@@ -288,5 +291,5 @@ func (r *redisProc) ProcessBatch(ctx context.Context, spans []*tracing.Span, msg
 }
 
 func (r *redisProc) Close(ctx context.Context) error {
-	return r.client.Close()
+	return r.release()
 }
@@ -2,6 +2,8 @@ package sql
 
 import (
 	"database/sql"
+	"fmt"
+	"io"
 	"net/url"
 	"strings"
 	"time"
@@ -49,9 +51,20 @@ func connFields() []*service.ConfigField {
 			Description(`An optional maximum number of open connections to the database. If conn_max_idle is greater than 0 and the new conn_max_open is less than conn_max_idle, then conn_max_idle will be reduced to match the new conn_max_open limit. If value <= 0, then there is no limit on the number of open connections. The default is 0 (unlimited).`).
 			Optional().
 			Advanced(),
+		service.NewBoolField("shared_pool").
+			Description("Whether to share the connection pool with other components of the service that also enable this field and target the same `driver` and `dsn`, which reduces the number of connections opened to the database. Connection pool settings are taken from the first component to open the pool.").
+			Default(false).
+			Advanced(),
 	}
 }
 
+func stmtCacheField() *service.ConfigField {
+	return service.NewIntField("prepared_statement_cache_size").
+		Description("The maximum number of prepared statements to cache, where the least recently used statements are closed when the limit is reached. Caching prepared statements avoids preparing the same query for each execution. If value <= 0 statements are not cached.").
+		Default(0).
+		Advanced()
+}
+
 func rawQueryField() *service.ConfigField {
 	return service.NewStringField("query").
 		Description("The query to execute. The style of placeholder to use depends on the driver, some drivers require question marks (`?`) whereas others expect incrementing dollar signs (`$1`, `$2`, and so on). The style to use is outlined in this table:" + `
@@ -70,6 +83,8 @@ type connSettings struct {
 	connMaxIdleTime time.Duration
 	maxIdleConns    int
 	maxOpenConns    int
+	sharedPool      bool
+	stmtCacheSize   int
 }
 
 func (c connSettings) apply(db *sql.DB) {
@@ -103,9 +118,53 @@ func connSettingsFromParsed(conf *service.ParsedConfig) (c connSettings, err err
 			return
 		}
 	}
+
+	if conf.Contains("shared_pool") {
+		if c.sharedPool, err = conf.FieldBool("shared_pool"); err != nil {
+			return
+		}
+	}
+
+	if conf.Contains("prepared_statement_cache_size") {
+		if c.stmtCacheSize, err = conf.FieldInt("prepared_statement_cache_size"); err != nil {
+			return
+		}
+	}
 	return
 }
 
+// open a connection pool for a driver and DSN, the returned func must be called
+// instead of closing the pool directly as the pool might be shared with other
+// components.
+func (c connSettings) open(mgr *service.Resources, driver, dsn string) (*sql.DB, func() error, error) {
+	if !c.sharedPool {
+		db, err := sqlOpenWithReworks(mgr.Logger(), driver, dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.apply(db)
+		return db, db.Close, nil
+	}
+
+	client, release, err := mgr.AcquireSharedClient(fmt.Sprintf("sql:%q,%q", driver, dsn), func() (io.Closer, error) {
+		db, err := sqlOpenWithReworks(mgr.Logger(), driver, dsn)
+		if err != nil {
+			return nil, err
+		}
+		c.apply(db)
+		return db, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	db, ok := client.(*sql.DB)
+	if !ok {
+		_ = release()
+		return nil, nil, fmt.Errorf("shared client has unexpected type: %T", client)
+	}
+	return db, release, nil
+}
+
 func sqlOpenWithReworks(logger *service.Logger, driver, dsn string) (*sql.DB, error) {
 	if driver == "clickhouse" && strings.HasPrefix(dsn, "tcp") {
 		u, err := url.Parse(dsn)
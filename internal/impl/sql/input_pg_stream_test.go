@@ -0,0 +1,205 @@
+package sql
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+func TestPGStreamLSN(t *testing.T) {
+	for _, s := range []string{"0/0", "16/B374D848", "FFFFFFFF/FFFFFFFF"} {
+		lsn, err := parseLSN(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, formatLSN(lsn))
+	}
+
+	lsn, err := parseLSN("16/B374D848")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0x16B374D848), lsn)
+
+	for _, s := range []string{"", "16", "16/", "G/0", "1/2/3"} {
+		_, err := parseLSN(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestPGStreamTransactionBatch(t *testing.T) {
+	tx := pgStreamTransaction{
+		lsn: 0x16B374D848,
+		changes: []pgStreamChange{
+			{
+				Action:  "I",
+				Schema:  "public",
+				Table:   "users",
+				Columns: []pgStreamColumn{{Name: "id", Value: 1.0}, {Name: "name", Value: "foo"}},
+			},
+			{
+				Action:   "U",
+				Schema:   "public",
+				Table:    "users",
+				Columns:  []pgStreamColumn{{Name: "id", Value: 1.0}, {Name: "name", Value: "bar"}},
+				Identity: []pgStreamColumn{{Name: "id", Value: 1.0}},
+			},
+			{
+				Action:   "D",
+				Schema:   "public",
+				Table:    "users",
+				Identity: []pgStreamColumn{{Name: "id", Value: 1.0}},
+			},
+		},
+	}
+
+	batch := tx.toBatch()
+	require.Len(t, batch, 3)
+
+	var exp []interface{}
+	for _, v := range []struct {
+		operation     string
+		before, after interface{}
+	}{
+		{operation: "insert", after: map[string]interface{}{"id": 1.0, "name": "foo"}},
+		{
+			operation: "update",
+			before:    map[string]interface{}{"id": 1.0},
+			after:     map[string]interface{}{"id": 1.0, "name": "bar"},
+		},
+		{operation: "delete", before: map[string]interface{}{"id": 1.0}},
+	} {
+		exp = append(exp, map[string]interface{}{
+			"operation": v.operation,
+			"schema":    "public",
+			"table":     "users",
+			"before":    v.before,
+			"after":     v.after,
+		})
+	}
+
+	for i, msg := range batch {
+		v, err := msg.AsStructured()
+		require.NoError(t, err)
+		assert.Equal(t, exp[i], v)
+
+		lsn, _ := msg.MetaGet("pg_stream_lsn")
+		assert.Equal(t, "16/B374D848", lsn)
+		table, _ := msg.MetaGet("pg_stream_table")
+		assert.Equal(t, "users", table)
+		op, _ := msg.MetaGet("pg_stream_operation")
+		assert.Equal(t, exp[i].(map[string]interface{})["operation"], op)
+	}
+}
+
+// fakePGStreamSlot emulates a replication slot, where peeks return whole
+// transactions from the start of the slot.
+type fakePGStreamSlot struct {
+	mut      sync.Mutex
+	rows     []pgStreamRow
+	advanced uint64
+}
+
+func (f *fakePGStreamSlot) peek(ctx context.Context, limit int) ([]pgStreamRow, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	var res, current []pgStreamRow
+	for _, row := range f.rows {
+		current = append(current, row)
+		if row.data != `{"action":"C"}` {
+			continue
+		}
+		lsn, err := parseLSN(row.lsn)
+		if err != nil {
+			return nil, err
+		}
+		if lsn > f.advanced {
+			res = append(res, current...)
+		}
+		current = nil
+		if len(res) >= limit {
+			break
+		}
+	}
+	return res, nil
+}
+
+func (f *fakePGStreamSlot) advance(ctx context.Context, lsn uint64) error {
+	f.mut.Lock()
+	f.advanced = lsn
+	f.mut.Unlock()
+	return nil
+}
+
+func (f *fakePGStreamSlot) getAdvanced() uint64 {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.advanced
+}
+
+func TestPGStreamSkipEmptyTransactions(t *testing.T) {
+	emptyTx := func(commit string) []pgStreamRow {
+		return []pgStreamRow{
+			{lsn: commit, data: `{"action":"B"}`},
+			{lsn: commit, data: `{"action":"C"}`},
+		}
+	}
+
+	var rows []pgStreamRow
+	rows = append(rows, pgStreamRow{lsn: "0/1", data: `{"action":"B"}`})
+	rows = append(rows, pgStreamRow{lsn: "0/1", data: `{"action":"I","schema":"public","table":"users","columns":[{"name":"id","value":1}]}`})
+	rows = append(rows, pgStreamRow{lsn: "0/1", data: `{"action":"C"}`})
+	rows = append(rows, emptyTx("0/2")...)
+	rows = append(rows, emptyTx("0/3")...)
+	rows = append(rows, emptyTx("0/4")...)
+	rows = append(rows, pgStreamRow{lsn: "0/5", data: `{"action":"B"}`})
+	rows = append(rows, pgStreamRow{lsn: "0/5", data: `{"action":"I","schema":"public","table":"users","columns":[{"name":"id","value":2}]}`})
+	rows = append(rows, pgStreamRow{lsn: "0/5", data: `{"action":"C"}`})
+
+	slot := &fakePGStreamSlot{rows: rows}
+	p := &pgStreamInput{
+		batchSize:    2,
+		pollInterval: time.Millisecond,
+		slot:         slot,
+		checkpointer: checkpoint.NewCapped(1024),
+		shutSig:      shutdown.NewSignaller(),
+	}
+
+	readBatch := func() (string, func(context.Context, error) error) {
+		t.Helper()
+		ctx, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		batch, ackFn, err := p.ReadBatch(ctx)
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		lsn, _ := batch[0].MetaGet("pg_stream_lsn")
+		return lsn, ackFn
+	}
+
+	lsn, ackFn := readBatch()
+	assert.Equal(t, "0/1", lsn)
+
+	// Empty transactions are not skipped whilst a prior transaction is yet to
+	// be acknowledged.
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	_, _, err := p.ReadBatch(ctx)
+	done()
+	require.Error(t, err)
+	assert.Equal(t, uint64(0), slot.getAdvanced())
+
+	require.NoError(t, ackFn(context.Background(), nil))
+	assert.Equal(t, uint64(1), slot.getAdvanced())
+
+	// The slot is advanced past empty transactions that would otherwise fill
+	// every peek.
+	lsn, ackFn = readBatch()
+	assert.Equal(t, "0/5", lsn)
+	assert.Equal(t, uint64(4), slot.getAdvanced())
+
+	require.NoError(t, ackFn(context.Background(), nil))
+	assert.Equal(t, uint64(5), slot.getAdvanced())
+}
@@ -0,0 +1,86 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestSQLOutboxInputQueries(t *testing.T) {
+	tests := []struct {
+		name         string
+		conf         string
+		selectQuery  string
+		resolveQuery string
+		resolveArgs  []interface{}
+	}{
+		{
+			name: "postgres delete",
+			conf: `
+driver: postgres
+dsn: woof
+table: outbox
+id_column: id
+`,
+			selectQuery:  "SELECT * FROM outbox ORDER BY id LIMIT 100 FOR UPDATE SKIP LOCKED",
+			resolveQuery: "DELETE FROM outbox WHERE id IN ($1,$2)",
+			resolveArgs:  []interface{}{1, 2},
+		},
+		{
+			name: "mysql mark processed",
+			conf: `
+driver: mysql
+dsn: woof
+table: outbox
+id_column: id
+columns: [ id, payload ]
+processed_column: processed_at
+where: topic = 'orders'
+batch_size: 10
+`,
+			selectQuery:  "SELECT id, payload FROM outbox WHERE processed_at IS NULL AND topic = 'orders' ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED",
+			resolveQuery: "UPDATE outbox SET processed_at = CURRENT_TIMESTAMP WHERE id IN (?,?)",
+			resolveArgs:  []interface{}{1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := sqlOutboxInputConfig().ParseYAML(test.conf, service.NewEnvironment())
+			require.NoError(t, err)
+
+			i, err := newSQLOutboxInputFromConfig(parsed, service.MockResources())
+			require.NoError(t, err)
+
+			query, _, err := i.builder.ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, test.selectQuery, query)
+
+			query, args, err := i.resolveQuery([]interface{}{1, 2}).ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, test.resolveQuery, query)
+			assert.Equal(t, test.resolveArgs, args)
+
+			require.NoError(t, i.Close(context.Background()))
+		})
+	}
+}
+
+func TestSQLOutboxInputBadBatchSize(t *testing.T) {
+	parsed, err := sqlOutboxInputConfig().ParseYAML(`
+driver: postgres
+dsn: woof
+table: outbox
+id_column: id
+batch_size: 0
+`, service.NewEnvironment())
+	require.NoError(t, err)
+
+	_, err = newSQLOutboxInputFromConfig(parsed, service.MockResources())
+	require.Error(t, err)
+}
@@ -3,11 +3,14 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/bloblang"
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -18,7 +21,16 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 		// Stable(). TODO
 		Categories("Services").
 		Summary("Executes a select query and creates a message for each row received.").
-		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a [sequence](/docs/components/inputs/sequence) to execute).`).
+		Description(`
+Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a [sequence](/docs/components/inputs/sequence) to execute).
+
+### Polling With a Cursor
+
+When the field ` + "`cursor.column`" + ` is set this input instead polls the table forever, selecting only rows where the cursor column is greater than the highest value seen so far, in ascending order of that column. The cursor column should be one whose values only ever increase for new or updated rows, such as an auto-incrementing id or an ` + "`updated_at`" + ` timestamp. When a poll returns fewer rows than ` + "`cursor.limit`" + ` the input waits for ` + "`cursor.poll_interval`" + ` before polling again.
+
+When ` + "`cursor.cache`" + ` is set the highest cursor value of rows that have been delivered is stored within the cache resource, and consumption resumes from it after a restart. A cursor value is only stored once all rows prior to it have also been delivered, and therefore rows may be delivered more than once after a restart but are never skipped.
+
+Rows that share a cursor value but are committed after a poll has already read past that value will not be selected, and therefore a timestamp cursor should have a resolution high enough to make this unlikely.`).
 		Field(driverField).
 		Field(dsnField).
 		Field(service.NewStringField("table").
@@ -44,7 +56,35 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 		Field(service.NewStringField("suffix").
 			Description("An optional suffix to append to the select query.").
 			Optional().
-			Advanced())
+			Advanced()).
+		Field(service.NewObjectField("cursor",
+			service.NewStringField("column").
+				Description("A column whose values increase for new or updated rows, which must be included within the selected `columns`.").
+				Example("id").
+				Example("updated_at"),
+			service.NewStringField("initial_value").
+				Description("An optional value of the cursor column to begin selecting rows from when no cursor has been stored. By default all rows are selected by the first poll.").
+				Example("0").
+				Example("2022-01-01T00:00:00Z").
+				Optional(),
+			service.NewStringField("cache").
+				Description("An optional [cache resource](/docs/components/caches/about) to store the cursor within, allowing consumption to resume after a restart.").
+				Optional(),
+			service.NewStringField("cache_key").
+				Description("The key to store the cursor under within the cache. By default the name of the table is used.").
+				Default("").
+				Advanced(),
+			service.NewIntField("limit").
+				Description("The maximum number of rows to select with each poll.").
+				Default(1000).
+				Advanced(),
+			service.NewDurationField("poll_interval").
+				Description("The period of time to wait before polling again when the previous poll returned fewer rows than `limit`.").
+				Default("5s"),
+		).
+			Description("Optionally poll the table continuously for new rows, tracking progress with a cursor column. This allows the input to capture rows as they're inserted or updated. A cursor cannot be used with the `mssql` driver.").
+			Version("4.2.0").
+			Optional())
 
 	for _, f := range connFields() {
 		spec = spec.Field(f)
@@ -67,6 +107,27 @@ input:
       root = [
         now().format_timestamp_unix() - 3600
       ]
+`,
+		).
+		Example("Capture Updated Rows (MySQL)",
+			`
+Here we poll a table for rows as they are inserted or updated, using the column "updated_at" as a cursor that is stored within a Redis cache so that consumption resumes where it left off after a restart:`,
+			`
+input:
+  sql_select:
+    driver: mysql
+    dsn: foouser:foopassword@tcp(localhost:3306)/foodb?parseTime=true
+    table: orders
+    columns: [ '*' ]
+    cursor:
+      column: updated_at
+      cache: cursors
+      poll_interval: 10s
+
+cache_resources:
+  - label: cursors
+    redis:
+      url: tcp://localhost:6379
 `,
 		)
 	return spec
@@ -76,7 +137,7 @@ func init() {
 	err := service.RegisterInput(
 		"sql_select", sqlSelectInputConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
-			i, err := newSQLSelectInputFromConfig(conf, mgr.Logger())
+			i, err := newSQLSelectInputFromConfig(conf, mgr)
 			if err != nil {
 				return nil, err
 			}
@@ -97,19 +158,33 @@ type sqlSelectInput struct {
 	rows    *sql.Rows
 	builder squirrel.SelectBuilder
 	dbMut   sync.Mutex
+	closeDB func() error
 
 	where       string
 	argsMapping *bloblang.Executor
 
+	cursorColumn  string
+	cursorCache   string
+	cursorKey     string
+	cursorLimit   int
+	pollInterval  time.Duration
+	cursor        interface{}
+	rowsRead      int
+	checkpointer  *checkpoint.Capped
+	cursorLoaded  bool
+	cursorStoreMu sync.Mutex
+
 	connSettings connSettings
 
+	mgr     *service.Resources
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
 }
 
-func newSQLSelectInputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlSelectInput, error) {
+func newSQLSelectInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlSelectInput, error) {
 	s := &sqlSelectInput{
-		logger:  logger,
+		mgr:     mgr,
+		logger:  mgr.Logger(),
 		shutSig: shutdown.NewSignaller(),
 	}
 
@@ -166,12 +241,68 @@ func newSQLSelectInputFromConfig(conf *service.ParsedConfig, logger *service.Log
 		s.builder = s.builder.Suffix(suffixStr)
 	}
 
+	if conf.Contains("cursor") {
+		if err := s.cursorFromParsed(conf.Namespace("cursor"), tableStr); err != nil {
+			return nil, err
+		}
+	}
+
 	if s.connSettings, err = connSettingsFromParsed(conf); err != nil {
 		return nil, err
 	}
 	return s, nil
 }
 
+func (s *sqlSelectInput) cursorFromParsed(conf *service.ParsedConfig, table string) (err error) {
+	if s.cursorColumn, err = conf.FieldString("column"); err != nil {
+		return
+	}
+	if s.cursorColumn == "" {
+		return errors.New("cursor.column must not be empty")
+	}
+	if s.driver == "mssql" {
+		// Polls are paginated with a LIMIT clause, which isn't supported by
+		// SQL Server.
+		return errors.New("a cursor cannot be used with the mssql driver")
+	}
+
+	if conf.Contains("initial_value") {
+		var initialValue string
+		if initialValue, err = conf.FieldString("initial_value"); err != nil {
+			return
+		}
+		s.cursor = initialValue
+	}
+
+	if conf.Contains("cache") {
+		if s.cursorCache, err = conf.FieldString("cache"); err != nil {
+			return
+		}
+	}
+
+	if s.cursorKey, err = conf.FieldString("cache_key"); err != nil {
+		return
+	}
+	if s.cursorKey == "" {
+		s.cursorKey = table
+	}
+
+	if s.cursorLimit, err = conf.FieldInt("limit"); err != nil {
+		return
+	}
+	if s.cursorLimit < 1 {
+		return fmt.Errorf("cursor.limit must be greater than zero, got %v", s.cursorLimit)
+	}
+
+	if s.pollInterval, err = conf.FieldDuration("poll_interval"); err != nil {
+		return
+	}
+
+	s.checkpointer = checkpoint.NewCapped(int64(s.cursorLimit))
+	s.builder = s.builder.OrderBy(s.cursorColumn).Limit(uint64(s.cursorLimit))
+	return nil
+}
+
 func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 	s.dbMut.Lock()
 	defer s.dbMut.Unlock()
@@ -181,41 +312,27 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 	}
 
 	var db *sql.DB
-	if db, err = sqlOpenWithReworks(s.logger, s.driver, s.dsn); err != nil {
+	var closeDB func() error
+	if db, closeDB, err = s.connSettings.open(s.mgr, s.driver, s.dsn); err != nil {
 		return
 	}
 	defer func() {
 		if err != nil {
-			_ = db.Close()
+			_ = closeDB()
 		}
 	}()
 
-	s.connSettings.apply(db)
-
-	var args []interface{}
-	if s.argsMapping != nil {
-		var iargs interface{}
-		if iargs, err = s.argsMapping.Query(nil); err != nil {
-			return err
-		}
-
-		var ok bool
-		if args, ok = iargs.([]interface{}); !ok {
-			err = fmt.Errorf("mapping returned non-array result: %T", iargs)
+	var rows *sql.Rows
+	if s.cursorColumn != "" {
+		if err = s.loadCursor(ctx); err != nil {
 			return
 		}
-	}
-
-	queryBuilder := s.builder
-	if s.where != "" {
-		queryBuilder = queryBuilder.Where(s.where, args...)
-	}
-	var rows *sql.Rows
-	if rows, err = queryBuilder.RunWith(db).Query(); err != nil {
+	} else if rows, err = s.query(db); err != nil {
 		return
 	}
 
 	s.db = db
+	s.closeDB = closeDB
 	s.rows = rows
 
 	go func() {
@@ -227,7 +344,7 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 			s.rows = nil
 		}
 		if s.db != nil {
-			_ = s.db.Close()
+			_ = s.closeDB()
 		}
 		s.dbMut.Unlock()
 
@@ -236,7 +353,184 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 	return nil
 }
 
+// query runs the select query, including the cursor condition when polling
+// with a cursor.
+func (s *sqlSelectInput) query(db *sql.DB) (*sql.Rows, error) {
+	var args []interface{}
+	if s.argsMapping != nil {
+		iargs, err := s.argsMapping.Query(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if args, ok = iargs.([]interface{}); !ok {
+			return nil, fmt.Errorf("mapping returned non-array result: %T", iargs)
+		}
+	}
+
+	queryBuilder := s.builder
+	if s.where != "" {
+		queryBuilder = queryBuilder.Where(s.where, args...)
+	}
+	if s.cursorColumn != "" && s.cursor != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Gt{s.cursorColumn: s.cursor})
+	}
+	return queryBuilder.RunWith(db).Query()
+}
+
+// loadCursor attempts to obtain a stored cursor from the cache the first time
+// the input connects, subsequent connections continue from the last row read.
+func (s *sqlSelectInput) loadCursor(ctx context.Context) error {
+	if s.cursorLoaded || s.cursorCache == "" {
+		return nil
+	}
+
+	var value []byte
+	var getErr error
+	if err := s.mgr.AccessCache(ctx, s.cursorCache, func(c service.Cache) {
+		value, getErr = c.Get(ctx, s.cursorKey)
+	}); err != nil {
+		return err
+	}
+	if getErr != nil && !errors.Is(getErr, service.ErrKeyNotFound) {
+		return fmt.Errorf("failed to obtain stored cursor: %w", getErr)
+	}
+	if getErr == nil {
+		s.cursor = string(value)
+	}
+	s.cursorLoaded = true
+	return nil
+}
+
+// storeCursor writes the highest cursor value that can be committed to the
+// cache.
+func (s *sqlSelectInput) storeCursor(ctx context.Context) error {
+	if s.cursorCache == "" {
+		return nil
+	}
+
+	s.cursorStoreMu.Lock()
+	defer s.cursorStoreMu.Unlock()
+
+	highest := s.checkpointer.Highest()
+	if highest == nil {
+		return nil
+	}
+
+	var setErr error
+	if err := s.mgr.AccessCache(ctx, s.cursorCache, func(c service.Cache) {
+		setErr = c.Set(ctx, s.cursorKey, []byte(cursorString(highest)), nil)
+	}); err != nil {
+		return err
+	}
+	return setErr
+}
+
+// cursorString formats a cursor value such that it can be stored and later
+// used as a query argument.
+func cursorString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func (s *sqlSelectInput) readCursor(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	for {
+		s.dbMut.Lock()
+		if s.db == nil {
+			s.dbMut.Unlock()
+			return nil, nil, service.ErrNotConnected
+		}
+
+		if s.rows == nil {
+			rows, err := s.query(s.db)
+			if err != nil {
+				s.dbMut.Unlock()
+				return nil, nil, err
+			}
+			s.rows = rows
+			s.rowsRead = 0
+		}
+
+		if s.rows.Next() {
+			msg, ackFn, err := s.readCursorRow(ctx)
+			s.dbMut.Unlock()
+			return msg, ackFn, err
+		}
+
+		err := s.rows.Err()
+		_ = s.rows.Close()
+		s.rows = nil
+		exhausted := s.rowsRead < s.cursorLimit
+		s.dbMut.Unlock()
+
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exhausted {
+			continue
+		}
+
+		select {
+		case <-time.After(s.pollInterval):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-s.shutSig.CloseNowChan():
+			return nil, nil, service.ErrNotConnected
+		}
+	}
+}
+
+// readCursorRow reads the current row and moves the cursor to its value, the
+// dbMut must be held by the caller.
+func (s *sqlSelectInput) readCursorRow(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	obj, err := sqlRowToMap(s.rows)
+	if err != nil {
+		_ = s.rows.Close()
+		s.rows = nil
+		return nil, nil, err
+	}
+
+	value, exists := obj[s.cursorColumn]
+	if !exists || value == nil {
+		_ = s.rows.Close()
+		s.rows = nil
+		return nil, nil, fmt.Errorf("cursor column '%v' was not found in selected row", s.cursorColumn)
+	}
+
+	resolveFn, err := s.checkpointer.Track(ctx, value, 1)
+	if err != nil {
+		// The row is dropped and therefore the next read must query again
+		// from the previous cursor.
+		_ = s.rows.Close()
+		s.rows = nil
+		return nil, nil, err
+	}
+	s.cursor = value
+	s.rowsRead++
+
+	msg := service.NewMessage(nil)
+	msg.SetStructured(obj)
+	return msg, func(ctx context.Context, err error) error {
+		// Nacks are retried by AutoRetryNacks and therefore we only ever
+		// receive successful acks.
+		_ = resolveFn()
+		return s.storeCursor(ctx)
+	}, nil
+}
+
 func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	if s.cursorColumn != "" {
+		return s.readCursor(ctx)
+	}
+
 	s.dbMut.Lock()
 	defer s.dbMut.Unlock()
 
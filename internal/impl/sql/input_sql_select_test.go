@@ -3,7 +3,10 @@ package sql
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -25,7 +28,96 @@ args_mapping: 'root = [ this.id ]'
 	selectConfig, err := spec.ParseYAML(conf, env)
 	require.NoError(t, err)
 
-	selectInput, err := newSQLSelectInputFromConfig(selectConfig, nil)
+	selectInput, err := newSQLSelectInputFromConfig(selectConfig, service.MockResources())
 	require.NoError(t, err)
 	require.NoError(t, selectInput.Close(context.Background()))
 }
+
+func TestSQLSelectInputCursorConfig(t *testing.T) {
+	spec := sqlSelectInputConfig()
+	env := service.NewEnvironment()
+
+	for _, test := range []struct {
+		name   string
+		conf   string
+		errStr string
+	}{
+		{
+			name: "valid cursor",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ "*" ]
+cursor:
+  column: updated_at
+  initial_value: "2022-01-01T00:00:00Z"
+`,
+		},
+		{
+			name: "empty column",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ "*" ]
+cursor:
+  column: ""
+`,
+			errStr: "cursor.column must not be empty",
+		},
+		{
+			name: "bad limit",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ "*" ]
+cursor:
+  column: id
+  limit: 0
+`,
+			errStr: "cursor.limit must be greater than zero, got 0",
+		},
+		{
+			name: "mssql driver",
+			conf: `
+driver: mssql
+dsn: woof
+table: quack
+columns: [ "*" ]
+cursor:
+  column: id
+`,
+			errStr: "a cursor cannot be used with the mssql driver",
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			selectConfig, err := spec.ParseYAML(test.conf, env)
+			require.NoError(t, err)
+
+			selectInput, err := newSQLSelectInputFromConfig(selectConfig, service.MockResources())
+			if test.errStr != "" {
+				require.EqualError(t, err, test.errStr)
+				return
+			}
+			require.NoError(t, err)
+
+			query, args, err := selectInput.builder.
+				Where(squirrel.Gt{selectInput.cursorColumn: selectInput.cursor}).
+				ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT * FROM quack WHERE updated_at > $1 ORDER BY updated_at LIMIT 1000", query)
+			assert.Equal(t, []interface{}{"2022-01-01T00:00:00Z"}, args)
+
+			require.NoError(t, selectInput.Close(context.Background()))
+		})
+	}
+}
+
+func TestSQLSelectCursorString(t *testing.T) {
+	assert.Equal(t, "10", cursorString(int64(10)))
+	assert.Equal(t, "foo", cursorString([]byte("foo")))
+	assert.Equal(t, "2022-01-02T03:04:05.5Z", cursorString(time.Date(2022, 1, 2, 3, 4, 5, 500000000, time.UTC)))
+}
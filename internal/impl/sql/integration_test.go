@@ -53,11 +53,11 @@ args_mapping: 'root = [ this.id ]'
 			selectConfig, err := isql.SelectProcessorConfig().ParseYAML(queryConf, env)
 			require.NoError(t, err)
 
-			insertProc, err := isql.NewSQLInsertProcessorFromConfig(insertConfig, nil)
+			insertProc, err := isql.NewSQLInsertProcessorFromConfig(insertConfig, service.MockResources())
 			require.NoError(t, err)
 			t.Cleanup(func() { insertProc.Close(context.Background()) })
 
-			selectProc, err := isql.NewSQLSelectProcessorFromConfig(selectConfig, nil)
+			selectProc, err := isql.NewSQLSelectProcessorFromConfig(selectConfig, service.MockResources())
 			require.NoError(t, err)
 			t.Cleanup(func() { selectProc.Close(context.Background()) })
 
@@ -100,11 +100,11 @@ args_mapping: 'root = [ this.id ]'
 			selectConfig, err := isql.RawProcessorConfig().ParseYAML(queryConf, env)
 			require.NoError(t, err)
 
-			insertProc, err := isql.NewSQLRawProcessorFromConfig(insertConfig, nil)
+			insertProc, err := isql.NewSQLRawProcessorFromConfig(insertConfig, service.MockResources())
 			require.NoError(t, err)
 			t.Cleanup(func() { insertProc.Close(context.Background()) })
 
-			selectProc, err := isql.NewSQLRawProcessorFromConfig(selectConfig, nil)
+			selectProc, err := isql.NewSQLRawProcessorFromConfig(selectConfig, service.MockResources())
 			require.NoError(t, err)
 			t.Cleanup(func() { selectProc.Close(context.Background()) })
 
@@ -147,11 +147,11 @@ result_codec: json_array
 			selectConfig, err := isql.DeprecatedProcessorConfig().ParseYAML(queryConf, env)
 			require.NoError(t, err)
 
-			insertProc, err := isql.NewSQLDeprecatedProcessorFromConfig(insertConfig, nil)
+			insertProc, err := isql.NewSQLDeprecatedProcessorFromConfig(insertConfig, service.MockResources())
 			require.NoError(t, err)
 			t.Cleanup(func() { insertProc.Close(context.Background()) })
 
-			selectProc, err := isql.NewSQLDeprecatedProcessorFromConfig(selectConfig, nil)
+			selectProc, err := isql.NewSQLDeprecatedProcessorFromConfig(selectConfig, service.MockResources())
 			require.NoError(t, err)
 			t.Cleanup(func() { selectProc.Close(context.Background()) })
 
@@ -512,12 +512,125 @@ processors:
 	})
 }
 
+func testCursorInput(t *testing.T, driver, dsn, table string) {
+	t.Run("cursor_input", func(t *testing.T) {
+		if driver == "mssql" {
+			t.Skip("cursors are not supported by the mssql driver")
+		}
+
+		confReplacer := strings.NewReplacer(
+			"$driver", driver,
+			"$dsn", dsn,
+			"$table", table,
+		)
+
+		outputConf := confReplacer.Replace(`
+sql_insert:
+  driver: $driver
+  dsn: $dsn
+  table: $table
+  columns: [ foo, bar, baz ]
+  args_mapping: 'root = [ this.foo, this.bar.floor(), this.baz ]'
+`)
+
+		inputConf := confReplacer.Replace(`
+sql_select:
+  driver: $driver
+  dsn: $dsn
+  table: $table
+  columns: [ "*" ]
+  cursor:
+    column: bar
+    limit: 3
+    poll_interval: 100ms
+processors:
+  - bloblang: |
+      root = this.foo
+`)
+
+		streamInBuilder := service.NewStreamBuilder()
+		require.NoError(t, streamInBuilder.SetLoggerYAML(`level: OFF`))
+		require.NoError(t, streamInBuilder.AddOutputYAML(outputConf))
+
+		inFn, err := streamInBuilder.AddBatchProducerFunc()
+		require.NoError(t, err)
+
+		streamIn, err := streamInBuilder.Build()
+		require.NoError(t, err)
+
+		go func() {
+			assert.NoError(t, streamIn.Run(context.Background()))
+		}()
+		defer func() {
+			require.NoError(t, streamIn.StopWithin(time.Second))
+		}()
+
+		insertRows := func(from, to int) {
+			var insertBatch service.MessageBatch
+			for i := from; i < to; i++ {
+				insertBatch = append(insertBatch, service.NewMessage([]byte(fmt.Sprintf(`{
+	"foo": "doc-%v",
+	"bar": %v,
+	"baz": "and this"
+}`, i, i))))
+			}
+			require.NoError(t, inFn(context.Background(), insertBatch))
+		}
+
+		streamOutBuilder := service.NewStreamBuilder()
+		require.NoError(t, streamOutBuilder.SetLoggerYAML(`level: OFF`))
+		require.NoError(t, streamOutBuilder.AddInputYAML(inputConf))
+
+		var outMut sync.Mutex
+		var outDocs []string
+		require.NoError(t, streamOutBuilder.AddConsumerFunc(func(c context.Context, m *service.Message) error {
+			msgBytes, err := m.AsBytes()
+			require.NoError(t, err)
+			outMut.Lock()
+			outDocs = append(outDocs, string(msgBytes))
+			outMut.Unlock()
+			return nil
+		}))
+
+		streamOut, err := streamOutBuilder.Build()
+		require.NoError(t, err)
+
+		insertRows(0, 5)
+
+		go func() {
+			assert.NoError(t, streamOut.Run(context.Background()))
+		}()
+		defer func() {
+			require.NoError(t, streamOut.StopWithin(time.Second))
+		}()
+
+		getDocs := func() []string {
+			outMut.Lock()
+			defer outMut.Unlock()
+			return append([]string{}, outDocs...)
+		}
+
+		expected := []string{"doc-0", "doc-1", "doc-2", "doc-3", "doc-4"}
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual(expected, getDocs())
+		}, time.Second*10, time.Millisecond*50)
+
+		insertRows(5, 8)
+
+		expected = append(expected, "doc-5", "doc-6", "doc-7")
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual(expected, getDocs())
+		}, time.Second*10, time.Millisecond*50)
+	})
+}
+
 func testSuite(t *testing.T, driver, dsn string, createTableFn func(string) error) {
 	for _, fn := range []testFn{
 		testBatchProcessorBasic,
 		testBatchProcessorParallel,
 		testBatchInputOutputBatch,
 		testBatchInputOutputRaw,
+		testCursorInput,
 		testRawProcessorsBasic,
 		testDeprecatedProcessorsBasic,
 	} {
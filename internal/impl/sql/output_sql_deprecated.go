@@ -40,7 +40,7 @@ func init() {
 			if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
 				return
 			}
-			out, err = newSQLDeprecatedOutputFromConfig(conf, mgr.Logger())
+			out, err = newSQLDeprecatedOutputFromConfig(conf, mgr)
 			return
 		})
 
@@ -51,7 +51,7 @@ func init() {
 
 //------------------------------------------------------------------------------
 
-func newSQLDeprecatedOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlRawOutput, error) {
+func newSQLDeprecatedOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlRawOutput, error) {
 	driverStr, err := conf.FieldString("driver")
 	if err != nil {
 		return nil, err
@@ -78,5 +78,5 @@ func newSQLDeprecatedOutputFromConfig(conf *service.ParsedConfig, logger *servic
 	if err != nil {
 		return nil, err
 	}
-	return newSQLRawOutput(logger, driverStr, dsnStr, queryStatic, argsMapping, connSettings), nil
+	return newSQLRawOutput(mgr, driverStr, dsnStr, queryStatic, argsMapping, connSettings), nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -24,7 +25,154 @@ args_mapping: 'root = [ this.id ]'
 	insertConfig, err := spec.ParseYAML(conf, env)
 	require.NoError(t, err)
 
-	insertOutput, err := newSQLInsertOutputFromConfig(insertConfig, nil)
+	insertOutput, err := newSQLInsertOutputFromConfig(insertConfig, service.MockResources())
 	require.NoError(t, err)
 	require.NoError(t, insertOutput.Close(context.Background()))
 }
+
+func TestSQLInsertOutputOnConflict(t *testing.T) {
+	tests := []struct {
+		name        string
+		conf        string
+		expected    string
+		errContains string
+	}{
+		{
+			name: "postgres update",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ id, name, email ]
+args_mapping: 'root = [ this.id, this.name, this.email ]'
+on_conflict:
+  columns: [ id ]
+  action: update
+`,
+			expected: "INSERT INTO quack (id,name,email) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email",
+		},
+		{
+			name: "postgres nothing",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ id, name, email ]
+args_mapping: 'root = [ this.id, this.name, this.email ]'
+on_conflict:
+  columns: [ id ]
+  action: nothing
+`,
+			expected: "INSERT INTO quack (id,name,email) VALUES ($1,$2,$3) ON CONFLICT (id) DO NOTHING",
+		},
+		{
+			name: "mysql update columns",
+			conf: `
+driver: mysql
+dsn: woof
+table: quack
+columns: [ id, name, email ]
+args_mapping: 'root = [ this.id, this.name, this.email ]'
+on_conflict:
+  action: update
+  update_columns: [ email ]
+`,
+			expected: "INSERT INTO quack (id,name,email) VALUES (?,?,?) ON DUPLICATE KEY UPDATE email = VALUES(email)",
+		},
+		{
+			name: "mysql nothing",
+			conf: `
+driver: mysql
+dsn: woof
+table: quack
+columns: [ id, name, email ]
+args_mapping: 'root = [ this.id, this.name, this.email ]'
+on_conflict:
+  action: nothing
+`,
+			expected: "INSERT IGNORE INTO quack (id,name,email) VALUES (?,?,?)",
+		},
+		{
+			name: "postgres without columns",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ id, name ]
+args_mapping: 'root = [ this.id, this.name ]'
+on_conflict:
+  action: nothing
+`,
+			errContains: "on_conflict.columns must be set",
+		},
+		{
+			name: "unsupported driver",
+			conf: `
+driver: mssql
+dsn: woof
+table: quack
+columns: [ id, name ]
+args_mapping: 'root = [ this.id, this.name ]'
+on_conflict:
+  columns: [ id ]
+  action: update
+`,
+			errContains: "not supported by the mssql driver",
+		},
+		{
+			name: "with suffix",
+			conf: `
+driver: postgres
+dsn: woof
+table: quack
+columns: [ id, name ]
+args_mapping: 'root = [ this.id, this.name ]'
+suffix: ON CONFLICT (id) DO NOTHING
+on_conflict:
+  columns: [ id ]
+  action: update
+`,
+			errContains: "cannot be set alongside a suffix",
+		},
+	}
+
+	spec := sqlInsertOutputConfig()
+	env := service.NewEnvironment()
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			insertConfig, err := spec.ParseYAML(test.conf, env)
+			require.NoError(t, err)
+
+			insertOutput, err := newSQLInsertOutputFromConfig(insertConfig, service.MockResources())
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+
+			sqlStr, _, err := insertOutput.builder.Values(1, 2, 3).ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sqlStr)
+		})
+	}
+}
+
+func TestSQLInsertOutputOnConflictAction(t *testing.T) {
+	spec := sqlInsertOutputConfig()
+	env := service.NewEnvironment()
+
+	_, err := spec.ParseYAML(`
+driver: postgres
+dsn: woof
+table: quack
+columns: [ id, name ]
+args_mapping: 'root = [ this.id, this.name ]'
+on_conflict:
+  columns: [ id ]
+`, env)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field 'action' is required")
+}
@@ -33,6 +33,7 @@ func sqlRawOutputConfig() *service.ConfigSpec {
 	for _, f := range connFields() {
 		spec = spec.Field(f)
 	}
+	spec = spec.Field(stmtCacheField())
 
 	spec = spec.Field(service.NewBatchPolicyField("batching")).
 		Version("3.65.0").
@@ -66,7 +67,7 @@ func init() {
 			if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
 				return
 			}
-			out, err = newSQLRawOutputFromConfig(conf, mgr.Logger())
+			out, err = newSQLRawOutputFromConfig(conf, mgr)
 			return
 		})
 
@@ -83,17 +84,21 @@ type sqlRawOutput struct {
 	db     *sql.DB
 	dbMut  sync.RWMutex
 
+	runner  *stmtCache
+	closeDB func() error
+
 	queryStatic string
 
 	argsMapping *bloblang.Executor
 
 	connSettings connSettings
 
+	mgr     *service.Resources
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
 }
 
-func newSQLRawOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlRawOutput, error) {
+func newSQLRawOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlRawOutput, error) {
 	driverStr, err := conf.FieldString("driver")
 	if err != nil {
 		return nil, err
@@ -120,18 +125,19 @@ func newSQLRawOutputFromConfig(conf *service.ParsedConfig, logger *service.Logge
 	if err != nil {
 		return nil, err
 	}
-	return newSQLRawOutput(logger, driverStr, dsnStr, queryStatic, argsMapping, connSettings), nil
+	return newSQLRawOutput(mgr, driverStr, dsnStr, queryStatic, argsMapping, connSettings), nil
 }
 
 func newSQLRawOutput(
-	logger *service.Logger,
+	mgr *service.Resources,
 	driverStr, dsnStr string,
 	queryStatic string,
 	argsMapping *bloblang.Executor,
 	connSettings connSettings,
 ) *sqlRawOutput {
 	return &sqlRawOutput{
-		logger:       logger,
+		mgr:          mgr,
+		logger:       mgr.Logger(),
 		shutSig:      shutdown.NewSignaller(),
 		driver:       driverStr,
 		dsn:          dsnStr,
@@ -150,17 +156,17 @@ func (s *sqlRawOutput) Connect(ctx context.Context) error {
 	}
 
 	var err error
-	if s.db, err = sqlOpenWithReworks(s.logger, s.driver, s.dsn); err != nil {
+	if s.db, s.closeDB, err = s.connSettings.open(s.mgr, s.driver, s.dsn); err != nil {
 		return err
 	}
-
-	s.connSettings.apply(s.db)
+	s.runner = newStmtCache(s.db, s.connSettings.stmtCacheSize)
 
 	go func() {
 		<-s.shutSig.CloseNowChan()
 
 		s.dbMut.Lock()
-		_ = s.db.Close()
+		_ = s.runner.Close()
+		_ = s.closeDB()
 		s.dbMut.Unlock()
 
 		s.shutSig.ShutdownComplete()
@@ -189,7 +195,7 @@ func (s *sqlRawOutput) WriteBatch(ctx context.Context, batch service.MessageBatc
 			return fmt.Errorf("mapping returned non-array result: %T", iargs)
 		}
 
-		if _, err = s.db.ExecContext(ctx, s.queryStatic, args...); err != nil {
+		if _, err = s.runner.ExecContext(ctx, s.queryStatic, args...); err != nil {
 			return err
 		}
 	}
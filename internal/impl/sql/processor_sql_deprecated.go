@@ -40,7 +40,7 @@ func init() {
 	err := service.RegisterBatchProcessor(
 		"sql", DeprecatedProcessorConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
-			return NewSQLDeprecatedProcessorFromConfig(conf, mgr.Logger())
+			return NewSQLDeprecatedProcessorFromConfig(conf, mgr)
 		})
 
 	if err != nil {
@@ -50,7 +50,7 @@ func init() {
 
 // NewSQLDeprecatedProcessorFromConfig returns an internal sql processor.
 // nolint:revive // Not bothered as this is internal anyway
-func NewSQLDeprecatedProcessorFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlRawProcessor, error) {
+func NewSQLDeprecatedProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlRawProcessor, error) {
 	driverStr, err := conf.FieldString("driver")
 	if err != nil {
 		return nil, err
@@ -93,5 +93,5 @@ func NewSQLDeprecatedProcessorFromConfig(conf *service.ParsedConfig, logger *ser
 	if err != nil {
 		return nil, err
 	}
-	return newSQLRawProcessor(logger, driverStr, dsnStr, queryStatic, queryDyn, onlyExec, argsMapping, connSettings)
+	return newSQLRawProcessor(mgr, driverStr, dsnStr, queryStatic, queryDyn, onlyExec, argsMapping, connSettings)
 }
@@ -46,6 +46,7 @@ If the insert fails to execute then the message will still remain unchanged and
 	for _, f := range connFields() {
 		spec = spec.Field(f)
 	}
+	spec = spec.Field(stmtCacheField())
 
 	spec = spec.Version("3.59.0").
 		Example("Table Insert (MySQL)",
@@ -74,7 +75,7 @@ func init() {
 	err := service.RegisterBatchProcessor(
 		"sql_insert", InsertProcessorConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
-			return NewSQLInsertProcessorFromConfig(conf, mgr.Logger())
+			return NewSQLInsertProcessorFromConfig(conf, mgr)
 		})
 
 	if err != nil {
@@ -88,6 +89,8 @@ type sqlInsertProcessor struct {
 	db      *sql.DB
 	builder squirrel.InsertBuilder
 	dbMut   sync.RWMutex
+	runner  *stmtCache
+	closeDB func() error
 
 	useTxStmt   bool
 	argsMapping *bloblang.Executor
@@ -98,9 +101,9 @@ type sqlInsertProcessor struct {
 
 // NewSQLInsertProcessorFromConfig returns an internal sql_insert processor.
 // nolint:revive // Not bothered as this is internal anyway
-func NewSQLInsertProcessorFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlInsertProcessor, error) {
+func NewSQLInsertProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlInsertProcessor, error) {
 	s := &sqlInsertProcessor{
-		logger:  logger,
+		logger:  mgr.Logger(),
 		shutSig: shutdown.NewSignaller(),
 	}
 
@@ -161,17 +164,17 @@ func NewSQLInsertProcessorFromConfig(conf *service.ParsedConfig, logger *service
 		return nil, err
 	}
 
-	if s.db, err = sqlOpenWithReworks(logger, driverStr, dsnStr); err != nil {
+	if s.db, s.closeDB, err = connSettings.open(mgr, driverStr, dsnStr); err != nil {
 		return nil, err
 	}
-
-	connSettings.apply(s.db)
+	s.runner = newStmtCache(s.db, connSettings.stmtCacheSize)
 
 	go func() {
 		<-s.shutSig.CloseNowChan()
 
 		s.dbMut.Lock()
-		_ = s.db.Close()
+		_ = s.runner.Close()
+		_ = s.closeDB()
 		s.dbMut.Unlock()
 
 		s.shutSig.ShutdownComplete()
@@ -235,7 +238,7 @@ func (s *sqlInsertProcessor) ProcessBatch(ctx context.Context, batch service.Mes
 
 	var err error
 	if tx == nil {
-		_, err = insertBuilder.RunWith(s.db).ExecContext(ctx)
+		_, err = insertBuilder.RunWith(s.runner).ExecContext(ctx)
 	} else {
 		err = tx.Commit()
 	}
@@ -40,6 +40,7 @@ If the query fails to execute then the message will remain unchanged and the err
 	for _, f := range connFields() {
 		spec = spec.Field(f)
 	}
+	spec = spec.Field(stmtCacheField())
 
 	spec = spec.Version("3.65.0").
 		Example(
@@ -79,7 +80,7 @@ func init() {
 	err := service.RegisterBatchProcessor(
 		"sql_raw", RawProcessorConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
-			return NewSQLRawProcessorFromConfig(conf, mgr.Logger())
+			return NewSQLRawProcessorFromConfig(conf, mgr)
 		})
 
 	if err != nil {
@@ -90,8 +91,10 @@ func init() {
 //------------------------------------------------------------------------------
 
 type sqlRawProcessor struct {
-	db    *sql.DB
-	dbMut sync.RWMutex
+	db      *sql.DB
+	dbMut   sync.RWMutex
+	runner  *stmtCache
+	closeDB func() error
 
 	queryStatic string
 	queryDyn    *service.InterpolatedString
@@ -105,7 +108,7 @@ type sqlRawProcessor struct {
 
 // NewSQLRawProcessorFromConfig returns an internal sql_raw processor.
 // nolint:revive // Not bothered as this is internal anyway
-func NewSQLRawProcessorFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlRawProcessor, error) {
+func NewSQLRawProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlRawProcessor, error) {
 	driverStr, err := conf.FieldString("driver")
 	if err != nil {
 		return nil, err
@@ -146,11 +149,11 @@ func NewSQLRawProcessorFromConfig(conf *service.ParsedConfig, logger *service.Lo
 	if err != nil {
 		return nil, err
 	}
-	return newSQLRawProcessor(logger, driverStr, dsnStr, queryStatic, queryDyn, onlyExec, argsMapping, connSettings)
+	return newSQLRawProcessor(mgr, driverStr, dsnStr, queryStatic, queryDyn, onlyExec, argsMapping, connSettings)
 }
 
 func newSQLRawProcessor(
-	logger *service.Logger,
+	mgr *service.Resources,
 	driverStr, dsnStr string,
 	queryStatic string,
 	queryDyn *service.InterpolatedString,
@@ -159,7 +162,7 @@ func newSQLRawProcessor(
 	connSettings connSettings,
 ) (*sqlRawProcessor, error) {
 	s := &sqlRawProcessor{
-		logger:      logger,
+		logger:      mgr.Logger(),
 		shutSig:     shutdown.NewSignaller(),
 		queryStatic: queryStatic,
 		queryDyn:    queryDyn,
@@ -168,16 +171,17 @@ func newSQLRawProcessor(
 	}
 
 	var err error
-	if s.db, err = sqlOpenWithReworks(logger, driverStr, dsnStr); err != nil {
+	if s.db, s.closeDB, err = connSettings.open(mgr, driverStr, dsnStr); err != nil {
 		return nil, err
 	}
-	connSettings.apply(s.db)
+	s.runner = newStmtCache(s.db, connSettings.stmtCacheSize)
 
 	go func() {
 		<-s.shutSig.CloseNowChan()
 
 		s.dbMut.Lock()
-		_ = s.db.Close()
+		_ = s.runner.Close()
+		_ = s.closeDB()
 		s.dbMut.Unlock()
 
 		s.shutSig.ShutdownComplete()
@@ -221,13 +225,13 @@ func (s *sqlRawProcessor) ProcessBatch(ctx context.Context, batch service.Messag
 		}
 
 		if s.onlyExec {
-			if _, err := s.db.ExecContext(ctx, queryStr, args...); err != nil {
+			if _, err := s.runner.ExecContext(ctx, queryStr, args...); err != nil {
 				s.logger.Debugf("Failed to run query: %v", err)
 				msg.SetError(err)
 				continue
 			}
 		} else {
-			rows, err := s.db.QueryContext(ctx, queryStr, args...)
+			rows, err := s.runner.QueryContext(ctx, queryStr, args...)
 			if err != nil {
 				s.logger.Debugf("Failed to run query: %v", err)
 				msg.SetError(err)
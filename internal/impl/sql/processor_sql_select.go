@@ -52,6 +52,7 @@ If the query fails to execute then the message will remain unchanged and the err
 	for _, f := range connFields() {
 		spec = spec.Field(f)
 	}
+	spec = spec.Field(stmtCacheField())
 
 	spec = spec.Version("3.59.0").
 		Example("Table Query (PostgreSQL)",
@@ -82,7 +83,7 @@ func init() {
 	err := service.RegisterBatchProcessor(
 		"sql_select", SelectProcessorConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
-			return NewSQLSelectProcessorFromConfig(conf, mgr.Logger())
+			return NewSQLSelectProcessorFromConfig(conf, mgr)
 		})
 
 	if err != nil {
@@ -96,6 +97,8 @@ type sqlSelectProcessor struct {
 	db      *sql.DB
 	builder squirrel.SelectBuilder
 	dbMut   sync.RWMutex
+	runner  *stmtCache
+	closeDB func() error
 
 	where       string
 	argsMapping *bloblang.Executor
@@ -106,9 +109,9 @@ type sqlSelectProcessor struct {
 
 // NewSQLSelectProcessorFromConfig returns an internal sql_select processor.
 // nolint:revive // Not bothered as this is internal anyway
-func NewSQLSelectProcessorFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlSelectProcessor, error) {
+func NewSQLSelectProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlSelectProcessor, error) {
 	s := &sqlSelectProcessor{
-		logger:  logger,
+		logger:  mgr.Logger(),
 		shutSig: shutdown.NewSignaller(),
 	}
 
@@ -170,16 +173,17 @@ func NewSQLSelectProcessorFromConfig(conf *service.ParsedConfig, logger *service
 		return nil, err
 	}
 
-	if s.db, err = sqlOpenWithReworks(logger, driverStr, dsnStr); err != nil {
+	if s.db, s.closeDB, err = connSettings.open(mgr, driverStr, dsnStr); err != nil {
 		return nil, err
 	}
-	connSettings.apply(s.db)
+	s.runner = newStmtCache(s.db, connSettings.stmtCacheSize)
 
 	go func() {
 		<-s.shutSig.CloseNowChan()
 
 		s.dbMut.Lock()
-		_ = s.db.Close()
+		_ = s.runner.Close()
+		_ = s.closeDB()
 		s.dbMut.Unlock()
 
 		s.shutSig.ShutdownComplete()
@@ -222,7 +226,7 @@ func (s *sqlSelectProcessor) ProcessBatch(ctx context.Context, batch service.Mes
 			queryBuilder = queryBuilder.Where(s.where, args...)
 		}
 
-		rows, err := queryBuilder.RunWith(s.db).QueryContext(ctx)
+		rows, err := queryBuilder.RunWith(s.runner).QueryContext(ctx)
 		if err != nil {
 			s.logger.Debugf("Failed to run query: %v", err)
 			msg.SetError(err)
@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+type cachedStmt struct {
+	query string
+	stmt  *sql.Stmt
+
+	// The number of executions currently using the statement, and whether it
+	// has been evicted from the cache, in which case it is closed once no
+	// longer in use.
+	refs    int
+	evicted bool
+}
+
+// stmtCache executes queries against a database with prepared statements that
+// are cached up to a maximum size, where the least recently used statements
+// are closed once the limit is reached. When the size is <= 0 queries are
+// executed against the database directly.
+//
+// Implements squirrel.StdSqlCtx so that it can be used as a runner for query
+// builders.
+type stmtCache struct {
+	db   *sql.DB
+	size int
+
+	mut   sync.Mutex
+	stmts map[string]*list.Element
+	lru   *list.List
+}
+
+func newStmtCache(db *sql.DB, size int) *stmtCache {
+	return &stmtCache{
+		db:    db,
+		size:  size,
+		stmts: map[string]*list.Element{},
+		lru:   list.New(),
+	}
+}
+
+// acquire a prepared statement for a query, the returned func must be called
+// once the statement is no longer being used.
+func (c *stmtCache) acquire(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	c.mut.Lock()
+	e, exists := c.stmts[query]
+	c.mut.Unlock()
+
+	if !exists {
+		stmt, err := c.db.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c.mut.Lock()
+		if e, exists = c.stmts[query]; exists {
+			// Prepared concurrently, keep the existing statement.
+			_ = stmt.Close()
+		} else {
+			e = c.lru.PushFront(&cachedStmt{query: query, stmt: stmt})
+			c.stmts[query] = e
+		}
+		c.mut.Unlock()
+	}
+
+	c.mut.Lock()
+	cs := e.Value.(*cachedStmt)
+	if cs.evicted {
+		// Evicted since we looked it up, try again.
+		c.mut.Unlock()
+		return c.acquire(ctx, query)
+	}
+	defer c.mut.Unlock()
+
+	cs.refs++
+	c.lru.MoveToFront(e)
+
+	for c.lru.Len() > c.size {
+		back := c.lru.Back()
+		evicted := c.lru.Remove(back).(*cachedStmt)
+		delete(c.stmts, evicted.query)
+		evicted.evicted = true
+		if evicted.refs == 0 {
+			_ = evicted.stmt.Close()
+		}
+	}
+
+	return cs.stmt, func() {
+		c.mut.Lock()
+		cs.refs--
+		if cs.evicted && cs.refs == 0 {
+			_ = cs.stmt.Close()
+		}
+		c.mut.Unlock()
+	}, nil
+}
+
+func (c *stmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if c.size <= 0 {
+		return c.db.ExecContext(ctx, query, args...)
+	}
+	stmt, release, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (c *stmtCache) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if c.size <= 0 {
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	stmt, release, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (c *stmtCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if c.size <= 0 {
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	stmt, release, err := c.acquire(ctx, query)
+	if err != nil {
+		// A row can't be created with an error, so fall back to the database
+		// which reports the error when the row is scanned.
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	defer release()
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+func (c *stmtCache) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *stmtCache) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *stmtCache) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// Close all cached statements.
+func (c *stmtCache) Close() error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	var err error
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		cs := e.Value.(*cachedStmt)
+		cs.evicted = true
+		if cs.refs > 0 {
+			continue
+		}
+		if cErr := cs.stmt.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	c.stmts = map[string]*list.Element{}
+	c.lru.Init()
+	return err
+}
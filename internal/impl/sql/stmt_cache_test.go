@@ -0,0 +1,145 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingDriver struct {
+	mut      sync.Mutex
+	prepared map[string]int
+	closed   map[string]int
+}
+
+func (d *countingDriver) Open(name string) (driver.Conn, error) {
+	return &countingConn{d: d}, nil
+}
+
+type countingConn struct {
+	d *countingDriver
+}
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mut.Lock()
+	c.d.prepared[query]++
+	c.d.mut.Unlock()
+	return &countingStmt{d: c.d, query: query}, nil
+}
+
+func (c *countingConn) Close() error {
+	return nil
+}
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type countingStmt struct {
+	d     *countingDriver
+	query string
+}
+
+func (s *countingStmt) Close() error {
+	s.d.mut.Lock()
+	s.d.closed[s.query]++
+	s.d.mut.Unlock()
+	return nil
+}
+
+func (s *countingStmt) NumInput() int {
+	return -1
+}
+
+func (s *countingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string {
+	return nil
+}
+
+func (emptyRows) Close() error {
+	return nil
+}
+
+func (emptyRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
+
+var testDriver = &countingDriver{
+	prepared: map[string]int{},
+	closed:   map[string]int{},
+}
+
+func init() {
+	sql.Register("benthos_counting", testDriver)
+}
+
+func TestStmtCacheLRU(t *testing.T) {
+	db, err := sql.Open("benthos_counting", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Restrict the pool to a single connection so that statements are only
+	// prepared once each.
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	c := newStmtCache(db, 2)
+
+	for _, q := range []string{"lru a", "lru b", "lru a", "lru c", "lru a", "lru b"} {
+		_, err := c.ExecContext(ctx, q)
+		require.NoError(t, err)
+	}
+
+	testDriver.mut.Lock()
+	assert.Equal(t, 1, testDriver.prepared["lru a"])
+	assert.Equal(t, 2, testDriver.prepared["lru b"])
+	assert.Equal(t, 1, testDriver.prepared["lru c"])
+	assert.Equal(t, 1, testDriver.closed["lru b"])
+	assert.Equal(t, 1, testDriver.closed["lru c"])
+	assert.Equal(t, 0, testDriver.closed["lru a"])
+	testDriver.mut.Unlock()
+
+	require.NoError(t, c.Close())
+
+	testDriver.mut.Lock()
+	assert.Equal(t, 1, testDriver.closed["lru a"])
+	assert.Equal(t, 2, testDriver.closed["lru b"])
+	testDriver.mut.Unlock()
+}
+
+func TestStmtCacheDisabled(t *testing.T) {
+	db, err := sql.Open("benthos_counting", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	c := newStmtCache(db, 0)
+
+	for i := 0; i < 3; i++ {
+		rows, err := c.QueryContext(ctx, "disabled a")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+	}
+	require.NoError(t, c.Close())
+
+	testDriver.mut.Lock()
+	assert.Equal(t, 3, testDriver.prepared["disabled a"])
+	assert.Equal(t, 3, testDriver.closed["disabled a"])
+	testDriver.mut.Unlock()
+}
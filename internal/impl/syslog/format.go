@@ -0,0 +1,150 @@
+package syslog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var facilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"ntp":      12,
+	"security": 13,
+	"console":  14,
+	"clock":    15,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+var severities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"error":   3,
+	"warning": 4,
+	"warn":    4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+// parseCode returns the numeric code of either a named or numeric facility or
+// severity.
+func parseCode(kind string, names map[string]int, max int, v string) (int, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if code, exists := names[v]; exists {
+		return code, nil
+	}
+	code, err := strconv.Atoi(v)
+	if err != nil || code < 0 || code > max {
+		return 0, fmt.Errorf("%v '%v' not recognised", kind, v)
+	}
+	return code, nil
+}
+
+func parseFacility(v string) (int, error) {
+	return parseCode("facility", facilities, 23, v)
+}
+
+func parseSeverity(v string) (int, error) {
+	return parseCode("severity", severities, 7, v)
+}
+
+// logMessage contains the header fields and content of a syslog message.
+type logMessage struct {
+	facility  int
+	severity  int
+	timestamp time.Time
+	hostname  string
+	appName   string
+	procID    string
+	msgID     string
+	message   string
+}
+
+func (l logMessage) priority() int {
+	return l.facility*8 + l.severity
+}
+
+// headerValue sanitises a header field so that it only contains printable
+// ASCII characters without spaces and does not exceed a maximum length, an
+// empty value is replaced with the nil value.
+func headerValue(v string, maxLen int) string {
+	if v == "" {
+		return "-"
+	}
+	b := make([]byte, 0, len(v))
+	for i := 0; i < len(v) && len(b) < maxLen; i++ {
+		c := v[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// formatRFC5424 formats a message following
+// https://datatracker.ietf.org/doc/html/rfc5424.
+func formatRFC5424(l logMessage) []byte {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "<%d>1 %v %v %v %v %v -",
+		l.priority(),
+		l.timestamp.Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerValue(l.hostname, 255),
+		headerValue(l.appName, 48),
+		headerValue(l.procID, 128),
+		headerValue(l.msgID, 32),
+	)
+	if l.message != "" {
+		_ = b.WriteByte(' ')
+		_, _ = b.WriteString(l.message)
+	}
+	return []byte(b.String())
+}
+
+// formatRFC3164 formats a message following
+// https://datatracker.ietf.org/doc/html/rfc3164.
+func formatRFC3164(l logMessage) []byte {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "<%d>%v %v %v",
+		l.priority(),
+		l.timestamp.Format(time.Stamp),
+		headerValue(l.hostname, 255),
+		headerValue(l.appName, 32),
+	)
+	if l.procID != "" {
+		_, _ = fmt.Fprintf(&b, "[%v]", headerValue(l.procID, 128))
+	}
+	_, _ = b.WriteString(": ")
+	_, _ = b.WriteString(l.message)
+	return []byte(b.String())
+}
+
+// frame prepares a formatted message for writing to a stream following
+// https://datatracker.ietf.org/doc/html/rfc6587.
+func frame(framing string, msg []byte) []byte {
+	if framing == "non_transparent" {
+		return append(msg, '\n')
+	}
+	return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+}
@@ -0,0 +1,83 @@
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFacilityAndSeverity(t *testing.T) {
+	for input, exp := range map[string]int{
+		"kern":    0,
+		"user":    1,
+		"LOCAL7":  23,
+		" local0": 16,
+		"4":       4,
+	} {
+		code, err := parseFacility(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, exp, code, input)
+	}
+
+	for input, exp := range map[string]int{
+		"emerg": 0,
+		"Error": 3,
+		"warn":  4,
+		"debug": 7,
+		"6":     6,
+	} {
+		code, err := parseSeverity(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, exp, code, input)
+	}
+
+	for _, input := range []string{"", "nope", "24", "-1"} {
+		_, err := parseFacility(input)
+		assert.Error(t, err, input)
+	}
+	for _, input := range []string{"", "nope", "8"} {
+		_, err := parseSeverity(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	l := logMessage{
+		facility:  4,
+		severity:  2,
+		timestamp: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+		hostname:  "mymachine.example.com",
+		appName:   "su",
+		msgID:     "ID47",
+		message:   "'su root' failed for lonvick on /dev/pts/8",
+	}
+
+	assert.Equal(t,
+		"<34>1 2003-10-11T22:14:15.003000Z mymachine.example.com su - ID47 - 'su root' failed for lonvick on /dev/pts/8",
+		string(formatRFC5424(l)),
+	)
+	assert.Equal(t,
+		"<34>Oct 11 22:14:15 mymachine.example.com su: 'su root' failed for lonvick on /dev/pts/8",
+		string(formatRFC3164(l)),
+	)
+
+	l.procID = "123"
+	l.appName = "my app"
+	l.hostname = ""
+	l.message = ""
+	assert.Equal(t,
+		"<34>1 2003-10-11T22:14:15.003000Z - my_app 123 ID47 -",
+		string(formatRFC5424(l)),
+	)
+	assert.Equal(t,
+		"<34>Oct 11 22:14:15 - my_app[123]: ",
+		string(formatRFC3164(l)),
+	)
+}
+
+func TestFrame(t *testing.T) {
+	assert.Equal(t, "5 hello", string(frame("octet_counting", []byte("hello"))))
+	assert.Equal(t, "hello\n", string(frame("non_transparent", []byte("hello"))))
+}
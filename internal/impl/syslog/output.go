@@ -0,0 +1,300 @@
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func syslogOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		// Stable(). TODO
+		Categories("Network").
+		Version("4.2.0").
+		Summary("Sends messages to a syslog server formatted as [RFC5424](https://datatracker.ietf.org/doc/html/rfc5424) or [RFC3164](https://datatracker.ietf.org/doc/html/rfc3164) log lines.").
+		Description(`
+The content of each message becomes the message of a log line by default, and the header fields of the log line such as the facility, severity and app name can be set from each message with [interpolation functions](/docs/configuration/interpolation#bloblang-queries).
+
+When sending over UDP each log line is written as a single datagram. When sending over TCP each log line is framed following [RFC6587](https://datatracker.ietf.org/doc/html/rfc6587), either by prefixing it with its length in bytes (octet counting) or by terminating it with a line feed (non-transparent framing), which is only safe when log lines never contain line feeds.
+
+### Facilities and Severities
+
+The fields `+"`facility` and `severity`"+` accept either the numeric code of a facility or severity, or one of their names. The facility names are `+"`kern`, `user`, `mail`, `daemon`, `auth`, `syslog`, `lpr`, `news`, `uucp`, `cron`, `authpriv`, `ftp`, `ntp`, `security`, `console`, `clock` and `local0` to `local7`"+`. The severity names are `+"`emerg`, `alert`, `crit`, `err` (or `error`), `warning` (or `warn`), `notice`, `info` and `debug`"+`.
+
+If either field resolves to a value that isn't recognised then the message is rejected.`).
+		Field(service.NewStringEnumField("network", "udp", "tcp").
+			Description("The network protocol to send log lines over.").
+			Default("udp")).
+		Field(service.NewStringField("address").
+			Description("The address of the syslog server.").
+			Example("localhost:514")).
+		Field(service.NewStringEnumField("format", "rfc5424", "rfc3164").
+			Description("The format of log lines.").
+			Default("rfc5424")).
+		Field(service.NewInterpolatedStringField("facility").
+			Description("The [facility](#facilities-and-severities) of each log line.").
+			Default("user").
+			Example("local0").
+			Example(`${! meta("facility") }`)).
+		Field(service.NewInterpolatedStringField("severity").
+			Description("The [severity](#facilities-and-severities) of each log line.").
+			Default("info").
+			Example(`${! json("level") }`)).
+		Field(service.NewInterpolatedStringField("app_name").
+			Description("The app name (or tag) of each log line.").
+			Default("benthos")).
+		Field(service.NewInterpolatedStringField("hostname").
+			Description("The hostname of each log line. By default the hostname of the machine running Benthos is used.").
+			Default("")).
+		Field(service.NewInterpolatedStringField("proc_id").
+			Description("An optional process id of each log line.").
+			Default("").
+			Advanced()).
+		Field(service.NewInterpolatedStringField("msg_id").
+			Description("An optional message id of each log line, which is only included within the RFC5424 format.").
+			Default("").
+			Advanced()).
+		Field(service.NewInterpolatedStringField("timestamp").
+			Description("An optional RFC3339 timestamp of each log line. By default the time at which a message is written is used.").
+			Default("").
+			Example(`${! json("created_at") }`).
+			Advanced()).
+		Field(service.NewInterpolatedStringField("message").
+			Description("The message of each log line.").
+			Default(`${! content() }`).
+			Example(`${! json("msg") }`)).
+		Field(service.NewStringEnumField("framing", "octet_counting", "non_transparent").
+			Description("The framing of log lines when sent over TCP.").
+			Default("octet_counting").
+			Advanced()).
+		Field(service.NewTLSToggledField("tls")).
+		Field(service.NewIntField("max_in_flight").
+			Description("The maximum number of messages to have in flight at a given time. Increase this to improve throughput.").
+			Default(64)).
+		Example("Forwarding to a SIEM",
+			`
+Here we forward structured logs to a collector over TLS, mapping the level of each log to the severity of its log line:`,
+			`
+output:
+  syslog:
+    network: tcp
+    address: siem.example.com:6514
+    facility: local4
+    severity: ${! json("level") }
+    app_name: ${! json("service") }
+    message: ${! json("msg") }
+    tls:
+      enabled: true
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterOutput(
+		"syslog", syslogOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Output, int, error) {
+			maxInFlight, err := conf.FieldInt("max_in_flight")
+			if err != nil {
+				return nil, 0, err
+			}
+			w, err := newSyslogWriterFromConfig(conf, mgr.Logger())
+			return w, maxInFlight, err
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type syslogWriter struct {
+	network string
+	address string
+	tlsConf *tls.Config
+	framing string
+	format  func(logMessage) []byte
+
+	facility  *service.InterpolatedString
+	severity  *service.InterpolatedString
+	appName   *service.InterpolatedString
+	hostname  *service.InterpolatedString
+	procID    *service.InterpolatedString
+	msgID     *service.InterpolatedString
+	timestamp *service.InterpolatedString
+	message   *service.InterpolatedString
+
+	defaultHostname string
+	now             func() time.Time
+
+	log *service.Logger
+
+	connMut sync.Mutex
+	conn    net.Conn
+}
+
+func newSyslogWriterFromConfig(conf *service.ParsedConfig, log *service.Logger) (*syslogWriter, error) {
+	s := &syslogWriter{
+		now: time.Now,
+		log: log,
+	}
+
+	var err error
+	if s.network, err = conf.FieldString("network"); err != nil {
+		return nil, err
+	}
+	if s.address, err = conf.FieldString("address"); err != nil {
+		return nil, err
+	}
+
+	format, err := conf.FieldString("format")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "rfc5424":
+		s.format = formatRFC5424
+	case "rfc3164":
+		s.format = formatRFC3164
+	default:
+		return nil, fmt.Errorf("format '%v' not recognised", format)
+	}
+
+	for _, f := range []struct {
+		name string
+		ptr  **service.InterpolatedString
+	}{
+		{name: "facility", ptr: &s.facility},
+		{name: "severity", ptr: &s.severity},
+		{name: "app_name", ptr: &s.appName},
+		{name: "hostname", ptr: &s.hostname},
+		{name: "proc_id", ptr: &s.procID},
+		{name: "msg_id", ptr: &s.msgID},
+		{name: "timestamp", ptr: &s.timestamp},
+		{name: "message", ptr: &s.message},
+	} {
+		if *f.ptr, err = conf.FieldInterpolatedString(f.name); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.framing, err = conf.FieldString("framing"); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled("tls")
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		if s.network != "tcp" {
+			return nil, errors.New("tls can only be enabled when the network is tcp")
+		}
+		s.tlsConf = tlsConf
+	}
+
+	if s.defaultHostname, err = os.Hostname(); err != nil {
+		s.log.Warnf("Failed to obtain hostname: %v", err)
+	}
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *syslogWriter) Connect(ctx context.Context) error {
+	s.connMut.Lock()
+	defer s.connMut.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+
+	var conn net.Conn
+	var err error
+	if s.tlsConf != nil {
+		dialer := tls.Dialer{Config: s.tlsConf}
+		conn, err = dialer.DialContext(ctx, s.network, s.address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, s.network, s.address)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.conn = conn
+	s.log.Infof("Sending syslog messages over %v to: %v", s.network, s.address)
+	return nil
+}
+
+func (s *syslogWriter) logMessage(msg *service.Message) (l logMessage, err error) {
+	if l.facility, err = parseFacility(s.facility.String(msg)); err != nil {
+		return
+	}
+	if l.severity, err = parseSeverity(s.severity.String(msg)); err != nil {
+		return
+	}
+
+	l.timestamp = s.now()
+	if tsStr := s.timestamp.String(msg); tsStr != "" {
+		if l.timestamp, err = time.Parse(time.RFC3339Nano, tsStr); err != nil {
+			err = fmt.Errorf("failed to parse timestamp: %w", err)
+			return
+		}
+	}
+
+	if l.hostname = s.hostname.String(msg); l.hostname == "" {
+		l.hostname = s.defaultHostname
+	}
+	l.appName = s.appName.String(msg)
+	l.procID = s.procID.String(msg)
+	l.msgID = s.msgID.String(msg)
+	l.message = s.message.String(msg)
+	return
+}
+
+func (s *syslogWriter) Write(ctx context.Context, msg *service.Message) error {
+	l, err := s.logMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	line := s.format(l)
+	if s.network == "tcp" {
+		line = frame(s.framing, line)
+	}
+
+	s.connMut.Lock()
+	defer s.connMut.Unlock()
+
+	if s.conn == nil {
+		return service.ErrNotConnected
+	}
+
+	deadline, _ := ctx.Deadline()
+	_ = s.conn.SetWriteDeadline(deadline)
+	if _, err = s.conn.Write(line); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *syslogWriter) Close(ctx context.Context) error {
+	s.connMut.Lock()
+	defer s.connMut.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
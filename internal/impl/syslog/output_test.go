@@ -0,0 +1,146 @@
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testWriter(t *testing.T, conf string) *syslogWriter {
+	t.Helper()
+
+	pConf, err := syslogOutputConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	w, err := newSyslogWriterFromConfig(pConf, service.MockResources().Logger())
+	require.NoError(t, err)
+
+	w.now = func() time.Time {
+		return time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	}
+	return w
+}
+
+func TestSyslogWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ln.Close()
+	})
+
+	linesChan := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			lenStr, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(lenStr))
+			if err != nil {
+				return
+			}
+			line := make([]byte, n)
+			if _, err := io.ReadFull(r, line); err != nil {
+				return
+			}
+			linesChan <- string(line)
+		}
+	}()
+
+	w := testWriter(t, `
+network: tcp
+address: `+ln.Addr().String()+`
+hostname: foohost
+severity: ${! json("level") }
+message: ${! json("msg") }
+`)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	require.NoError(t, w.Connect(ctx))
+	require.NoError(t, w.Write(ctx, service.NewMessage([]byte(`{"level":"warn","msg":"hello world"}`))))
+	require.NoError(t, w.Write(ctx, service.NewMessage([]byte(`{"level":"debug","msg":"and this"}`))))
+
+	for _, exp := range []string{
+		"<12>1 2022-05-01T10:00:00.000000Z foohost benthos - - - hello world",
+		"<15>1 2022-05-01T10:00:00.000000Z foohost benthos - - - and this",
+	} {
+		select {
+		case line := <-linesChan:
+			assert.Equal(t, exp, line)
+		case <-ctx.Done():
+			t.Fatal("timed out")
+		}
+	}
+
+	require.EqualError(t, w.Write(ctx, service.NewMessage([]byte(`{"level":"nope"}`))), "severity 'nope' not recognised")
+	require.NoError(t, w.Close(ctx))
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	w := testWriter(t, `
+network: udp
+address: `+conn.LocalAddr().String()+`
+format: rfc3164
+facility: local0
+hostname: foohost
+app_name: ${! meta("app") }
+proc_id: "10"
+timestamp: ${! meta("ts") }
+`)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	msg := service.NewMessage([]byte(`hello world`))
+	msg.MetaSet("app", "fooapp")
+	msg.MetaSet("ts", "2022-01-02T03:04:05Z")
+
+	require.NoError(t, w.Connect(ctx))
+	require.NoError(t, w.Write(ctx, msg))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*10)))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "<134>Jan  2 03:04:05 foohost fooapp[10]: hello world", string(buf[:n]))
+
+	require.NoError(t, w.Close(ctx))
+}
+
+func TestSyslogWriterTLSOverUDP(t *testing.T) {
+	pConf, err := syslogOutputConfig().ParseYAML(`
+network: udp
+address: localhost:514
+tls:
+  enabled: true
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newSyslogWriterFromConfig(pConf, service.MockResources().Logger())
+	require.EqualError(t, err, "tls can only be enabled when the network is tcp")
+}
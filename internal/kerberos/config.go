@@ -0,0 +1,86 @@
+package kerberos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// Config contains configuration params for authenticating with a Kerberos key
+// distribution center.
+type Config struct {
+	Realm           string `json:"realm" yaml:"realm"`
+	Username        string `json:"username" yaml:"username"`
+	Password        string `json:"password" yaml:"password"`
+	KeytabFile      string `json:"keytab_file" yaml:"keytab_file"`
+	CCacheFile      string `json:"ccache_file" yaml:"ccache_file"`
+	ConfigFile      string `json:"config_file" yaml:"config_file"`
+	DisablePAFXFAST bool   `json:"disable_pafxfast" yaml:"disable_pafxfast"`
+}
+
+// NewConfig creates a new Config with default values.
+func NewConfig() Config {
+	return Config{
+		Realm:           "",
+		Username:        "",
+		Password:        "",
+		KeytabFile:      "",
+		CCacheFile:      "",
+		ConfigFile:      "/etc/krb5.conf",
+		DisablePAFXFAST: false,
+	}
+}
+
+// FieldSpecs returns the specs of Kerberos config fields.
+func FieldSpecs() docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldString("realm", "The Kerberos realm to authenticate within, required unless a `ccache_file` is specified.", "EXAMPLE.COM").HasDefault(""),
+		docs.FieldString("username", "The Kerberos principal name to authenticate as, required unless a `ccache_file` is specified.").HasDefault(""),
+		docs.FieldString("password", "A password to authenticate with. It is recommended that you use environment variables to populate this field.", "${KRB5_PASSWORD}").HasDefault(""),
+		docs.FieldString("keytab_file", "The path of a keytab file containing the keys of the principal, which is used instead of a `password`.", "/etc/security/keytabs/benthos.keytab").HasDefault(""),
+		docs.FieldString("ccache_file", "The path of a credentials cache file containing a ticket granting ticket obtained externally (for example with `kinit`), which is used instead of a `username`, `password` or `keytab_file`. Since tickets obtained from a credentials cache cannot be renewed by Benthos the cache must be refreshed externally.", "/tmp/krb5cc_1000").HasDefault(""),
+		docs.FieldString("config_file", "The path of a Kerberos configuration file.").HasDefault("/etc/krb5.conf"),
+		docs.FieldBool("disable_pafxfast", "Whether to disable the PA-FX-FAST pre-authentication type, which is required by some key distribution centers such as Active Directory.").HasDefault(false).Advanced(),
+	}
+}
+
+// NewClient creates a Kerberos client from the config. The client does not
+// authenticate with the key distribution center until a service ticket is
+// first requested.
+func (c Config) NewClient() (*client.Client, error) {
+	krbConf, err := config.Load(c.ConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kerberos config file: %w", err)
+	}
+
+	settings := []func(*client.Settings){
+		client.DisablePAFXFAST(c.DisablePAFXFAST),
+	}
+
+	if c.CCacheFile != "" {
+		ccache, err := credentials.LoadCCache(c.CCacheFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kerberos credentials cache: %w", err)
+		}
+		return client.NewFromCCache(ccache, krbConf, settings...)
+	}
+
+	if c.Username == "" || c.Realm == "" {
+		return nil, errors.New("a kerberos username and realm must be specified unless a ccache_file is used")
+	}
+
+	if c.KeytabFile != "" {
+		kt, err := keytab.Load(c.KeytabFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kerberos keytab file: %w", err)
+		}
+		return client.NewWithKeytab(c.Username, c.Realm, kt, krbConf, settings...), nil
+	}
+	return client.NewWithPassword(c.Username, c.Realm, c.Password, krbConf, settings...), nil
+}
@@ -0,0 +1,3 @@
+// Package kerberos provides Benthos configuration fields for authenticating
+// with a Kerberos key distribution center.
+package kerberos
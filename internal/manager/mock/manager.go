@@ -32,6 +32,13 @@ type Manager struct {
 	// by components.
 	OnRegisterEndpoint func(path string, h http.HandlerFunc)
 
+	// Hooks contains health checks and lifecycle hooks registered by
+	// components.
+	Hooks *component.Lifecycle
+
+	// Clients contains clients shared by components.
+	Clients *component.SharedClients
+
 	M metrics.Type
 	L log.Modular
 }
@@ -45,6 +52,8 @@ func NewManager() *Manager {
 		Outputs:    map[string]OutputWriter{},
 		Processors: map[string]Processor{},
 		Pipes:      map[string]<-chan message.Transaction{},
+		Hooks:      component.NewLifecycle(),
+		Clients:    component.NewSharedClients(),
 		M:          metrics.Noop(),
 		L:          log.Noop(),
 	}
@@ -74,6 +83,15 @@ func (m *Manager) StoreCache(ctx context.Context, name string, conf cache.Config
 	return component.ErrInvalidType("cache", conf.Type)
 }
 
+// RemoveCache removes a cache resource.
+func (m *Manager) RemoveCache(ctx context.Context, name string) error {
+	if _, ok := m.Caches[name]; !ok {
+		return component.ErrCacheNotFound
+	}
+	delete(m.Caches, name)
+	return nil
+}
+
 // NewInput always errors on invalid type.
 func (m *Manager) NewInput(conf input.Config, pipelines ...processor.PipelineConstructorFunc) (input.Streamed, error) {
 	return bundle.AllInputs.Init(conf, m, pipelines...)
@@ -94,6 +112,15 @@ func (m *Manager) StoreProcessor(ctx context.Context, name string, conf processo
 	return component.ErrInvalidType("processor", conf.Type)
 }
 
+// RemoveProcessor removes a processor resource.
+func (m *Manager) RemoveProcessor(ctx context.Context, name string) error {
+	if _, ok := m.Processors[name]; !ok {
+		return component.ErrProcessorNotFound
+	}
+	delete(m.Processors, name)
+	return nil
+}
+
 // NewOutput always errors on invalid type.
 func (m *Manager) NewOutput(conf output.Config, pipelines ...processor.PipelineConstructorFunc) (output.Streamed, error) {
 	return bundle.AllOutputs.Init(conf, m, pipelines...)
@@ -114,6 +141,15 @@ func (m *Manager) StoreRateLimit(ctx context.Context, name string, conf ratelimi
 	return component.ErrInvalidType("rate_limit", conf.Type)
 }
 
+// RemoveRateLimit removes a rate limit resource.
+func (m *Manager) RemoveRateLimit(ctx context.Context, name string) error {
+	if _, ok := m.RateLimits[name]; !ok {
+		return component.ErrRateLimitNotFound
+	}
+	delete(m.RateLimits, name)
+	return nil
+}
+
 // Path always returns empty.
 func (m *Manager) Path() []string { return nil }
 
@@ -133,6 +169,22 @@ func (m *Manager) RegisterEndpoint(path, desc string, h http.HandlerFunc) {
 	}
 }
 
+// Lifecycle returns the registry of health checks and lifecycle hooks.
+func (m *Manager) Lifecycle() *component.Lifecycle {
+	if m.Hooks == nil {
+		m.Hooks = component.NewLifecycle()
+	}
+	return m.Hooks
+}
+
+// SharedClients returns the registry of shared clients.
+func (m *Manager) SharedClients() *component.SharedClients {
+	if m.Clients == nil {
+		m.Clients = component.NewSharedClients()
+	}
+	return m.Clients
+}
+
 // BloblEnvironment always returns the global environment.
 func (m *Manager) BloblEnvironment() *bloblang.Environment {
 	return bloblang.GlobalEnvironment()
@@ -0,0 +1,145 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/api"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// sanitisedResourceConfig returns a JSON representation of a resource config
+// with unused fields removed, which is delivered to clients of the resource
+// endpoints.
+func sanitisedResourceConfig(spec docs.FieldSpec, conf interface{}) []byte {
+	var node yaml.Node
+	if err := node.Encode(conf); err != nil {
+		return nil
+	}
+
+	sanitConf := docs.NewSanitiseConfig()
+	sanitConf.RemoveTypeField = true
+	if err := spec.SanitiseYAML(&node, sanitConf); err != nil {
+		return nil
+	}
+
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return nil
+	}
+	confBytes, _ := json.Marshal(v)
+	return confBytes
+}
+
+// resourceEndpoint describes how a resource type is created and removed via
+// the resource HTTP endpoints.
+type resourceEndpoint struct {
+	docType docs.Type
+	spec    docs.FieldSpec
+	store   func(ctx context.Context, id string, conf []byte) (interface{}, error)
+	remove  func(ctx context.Context, id string) error
+	initial map[string]interface{}
+}
+
+func (t *Type) registerResourceEndpoints(conf ResourceConfig) {
+	initialCaches := map[string]interface{}{}
+	for _, c := range conf.ResourceCaches {
+		initialCaches[c.Label] = c
+	}
+	initialProcessors := map[string]interface{}{}
+	for _, c := range conf.ResourceProcessors {
+		initialProcessors[c.Label] = c
+	}
+	initialRateLimits := map[string]interface{}{}
+	for _, c := range conf.ResourceRateLimits {
+		initialRateLimits[c.Label] = c
+	}
+
+	endpoints := []resourceEndpoint{
+		{
+			docType: docs.TypeCache,
+			spec:    docs.FieldCache("", ""),
+			store: func(ctx context.Context, id string, confBytes []byte) (interface{}, error) {
+				cacheConf := cache.NewConfig()
+				if err := yaml.Unmarshal(confBytes, &cacheConf); err != nil {
+					return nil, err
+				}
+				cacheConf.Label = id
+				return cacheConf, t.StoreCache(ctx, id, cacheConf)
+			},
+			remove:  t.RemoveCache,
+			initial: initialCaches,
+		},
+		{
+			docType: docs.TypeProcessor,
+			spec:    docs.FieldProcessor("", ""),
+			store: func(ctx context.Context, id string, confBytes []byte) (interface{}, error) {
+				procConf := processor.NewConfig()
+				if err := yaml.Unmarshal(confBytes, &procConf); err != nil {
+					return nil, err
+				}
+				procConf.Label = id
+				return procConf, t.StoreProcessor(ctx, id, procConf)
+			},
+			remove:  t.RemoveProcessor,
+			initial: initialProcessors,
+		},
+		{
+			docType: docs.TypeRateLimit,
+			spec:    docs.FieldRateLimit("", ""),
+			store: func(ctx context.Context, id string, confBytes []byte) (interface{}, error) {
+				rlConf := ratelimit.NewConfig()
+				if err := yaml.Unmarshal(confBytes, &rlConf); err != nil {
+					return nil, err
+				}
+				rlConf.Label = id
+				return rlConf, t.StoreRateLimit(ctx, id, rlConf)
+			},
+			remove:  t.RemoveRateLimit,
+			initial: initialRateLimits,
+		},
+	}
+
+	for _, e := range endpoints {
+		e := e
+		dynAPI := api.NewDynamic()
+		for id, c := range e.initial {
+			dynAPI.Started(id, sanitisedResourceConfig(e.spec, c))
+		}
+
+		dynAPI.OnUpdate(func(ctx context.Context, id string, confBytes []byte) error {
+			c, err := e.store(ctx, id, confBytes)
+			if err != nil {
+				t.logger.Errorf("Failed to set %v resource '%v': %v", e.docType, id, err)
+				return err
+			}
+			dynAPI.Started(id, sanitisedResourceConfig(e.spec, c))
+			return nil
+		})
+		dynAPI.OnDelete(func(ctx context.Context, id string) error {
+			if err := e.remove(ctx, id); err != nil {
+				t.logger.Errorf("Failed to remove %v resource '%v': %v", e.docType, id, err)
+				return err
+			}
+			dynAPI.Stopped(id)
+			return nil
+		})
+
+		t.RegisterEndpoint(
+			"/resources/"+string(e.docType)+"/{id}",
+			"Perform CRUD operations on the configuration of "+string(e.docType)+
+				" resources. For more information read the resources documentation.",
+			dynAPI.HandleCRUD,
+		)
+		t.RegisterEndpoint(
+			"/resources/"+string(e.docType),
+			"Get a map of "+string(e.docType)+" resource identifiers with their current uptimes and configs.",
+			dynAPI.HandleList,
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package manager_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+)
+
+type routerAPIReg struct {
+	router *mux.Router
+}
+
+func (r *routerAPIReg) RegisterEndpoint(path, desc string, h http.HandlerFunc) {
+	r.router.HandleFunc(path, h)
+}
+
+func TestManagerResourceEndpoints(t *testing.T) {
+	conf := manager.NewResourceConfig()
+
+	fooConf := cache.NewConfig()
+	fooConf.Label = "foo"
+	fooConf.Type = "memory"
+	conf.ResourceCaches = append(conf.ResourceCaches, fooConf)
+
+	reg := &routerAPIReg{router: mux.NewRouter()}
+	mgr, err := manager.New(conf, reg, log.Noop(), noopStats(), manager.OptSetResourceEndpoints(true))
+	require.NoError(t, err)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		res := httptest.NewRecorder()
+		reg.router.ServeHTTP(res, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return res
+	}
+
+	res := do("GET", "/resources/cache", "")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"foo":{"uptime":`)
+
+	res = do("POST", "/resources/cache/bar", `memory: { default_ttl: 10s }`)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.True(t, mgr.ProbeCache("bar"))
+
+	res = do("GET", "/resources/cache/bar", "")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"default_ttl":"10s"`)
+
+	require.NoError(t, mgr.AccessCache(context.Background(), "bar", func(c cache.V1) {
+		assert.NoError(t, c.Set(context.Background(), "a", []byte("b"), nil))
+	}))
+
+	res = do("DELETE", "/resources/cache/bar", "")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.False(t, mgr.ProbeCache("bar"))
+
+	res = do("GET", "/resources/cache/bar", "")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	res = do("DELETE", "/resources/rate_limit/nope", "")
+	assert.Equal(t, http.StatusBadGateway, res.Code)
+}
@@ -78,6 +78,16 @@ type Type struct {
 
 	pipes    map[string]<-chan message.Transaction
 	pipeLock *sync.RWMutex
+
+	// Health checks and lifecycle hooks registered by components, a new
+	// registry is created for each stream.
+	lifecycle *component.Lifecycle
+
+	// Clients shared by components targeting the same endpoints, this registry
+	// is shared across all streams of the manager.
+	sharedClients *component.SharedClients
+
+	resourceEndpoints bool
 }
 
 // OptFunc is an opt setting for a manager type.
@@ -109,6 +119,14 @@ func OptSetStreamsMode(b bool) OptFunc {
 	}
 }
 
+// OptSetResourceEndpoints enables HTTP endpoints for listing, creating,
+// updating and removing cache, processor and rate limit resources at runtime.
+func OptSetResourceEndpoints(b bool) OptFunc {
+	return func(t *Type) {
+		t.resourceEndpoints = b
+	}
+}
+
 // New returns an instance of manager.Type, which can be shared amongst
 // components and logical threads of a Benthos service.
 func New(conf ResourceConfig, apiReg APIReg, log log.Modular, stats *metrics.Namespaced, opts ...OptFunc) (*Type, error) {
@@ -131,6 +149,9 @@ func New(conf ResourceConfig, apiReg APIReg, log log.Modular, stats *metrics.Nam
 
 		pipes:    map[string]<-chan message.Transaction{},
 		pipeLock: &sync.RWMutex{},
+
+		lifecycle:     component.NewLifecycle(),
+		sharedClients: component.NewSharedClients(),
 	}
 
 	for _, opt := range opts {
@@ -218,6 +239,9 @@ func New(conf ResourceConfig, apiReg APIReg, log log.Modular, stats *metrics.Nam
 		}
 	}
 
+	if t.resourceEndpoints {
+		t.registerResourceEndpoints(conf)
+	}
 	return t, nil
 }
 
@@ -236,6 +260,7 @@ func (t *Type) forStream(id string) *Type {
 		"stream": id,
 	})
 	newT.stats = t.stats.WithLabels("stream", id)
+	newT.lifecycle = component.NewLifecycle()
 	return &newT
 }
 
@@ -289,6 +314,18 @@ func (t *Type) WithAddedMetrics(m metrics.Type) bundle.NewManagement {
 	return &newT
 }
 
+// Lifecycle returns a registry of health checks and lifecycle hooks for the
+// components of the stream that holds the manager.
+func (t *Type) Lifecycle() *component.Lifecycle {
+	return t.lifecycle
+}
+
+// SharedClients returns a registry of clients that can be shared by components
+// targeting the same endpoints.
+func (t *Type) SharedClients() *component.SharedClients {
+	return t.sharedClients
+}
+
 //------------------------------------------------------------------------------
 
 // RegisterEndpoint registers a server wide HTTP endpoint.
@@ -454,6 +491,24 @@ func (t *Type) StoreCache(ctx context.Context, name string, conf cache.Config) e
 	return nil
 }
 
+// RemoveCache attempts to close and remove a cache resource, returns an error
+// if the cache does not exist or could not be closed.
+func (t *Type) RemoveCache(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	c, ok := t.caches[name]
+	if !ok || c == nil {
+		return ErrResourceNotFound(name)
+	}
+	if err := c.Close(ctx); err != nil {
+		return err
+	}
+
+	delete(t.caches, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ProbeInput returns true if an input resource exists under the provided name.
@@ -586,6 +641,24 @@ func (t *Type) StoreProcessor(ctx context.Context, name string, conf processor.C
 	return nil
 }
 
+// RemoveProcessor attempts to close and remove a processor resource, returns an
+// error if the processor does not exist or could not be closed.
+func (t *Type) RemoveProcessor(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	p, ok := t.processors[name]
+	if !ok || p == nil {
+		return ErrResourceNotFound(name)
+	}
+	if err := closeWithContext(ctx, p); err != nil {
+		return err
+	}
+
+	delete(t.processors, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ProbeOutput returns true if an output resource exists under the provided
@@ -714,6 +787,24 @@ func (t *Type) StoreRateLimit(ctx context.Context, name string, conf ratelimit.C
 	return nil
 }
 
+// RemoveRateLimit attempts to close and remove a rate limit resource, returns
+// an error if the rate limit does not exist or could not be closed.
+func (t *Type) RemoveRateLimit(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	r, ok := t.rateLimits[name]
+	if !ok || r == nil {
+		return ErrResourceNotFound(name)
+	}
+	if err := r.Close(ctx); err != nil {
+		return err
+	}
+
+	delete(t.rateLimits, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // CloseAsync triggers the shut down of all resource types that implement the
@@ -0,0 +1,76 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// JSONParseFunc parses a single JSON document, surrounded by optional
+// whitespace, from a byte slice. When useNumber is true numbers must be parsed
+// as json.Number values, otherwise as float64 values. The resulting structure
+// must only consist of the types produced by the encoding/json package when
+// decoding into an interface{}.
+type JSONParseFunc func(b []byte, useNumber bool) (interface{}, error)
+
+var (
+	jsonParsersMut sync.Mutex
+	jsonParsers    = map[string]JSONParseFunc{}
+
+	jsonParserOnce sync.Once
+	jsonParser     JSONParseFunc
+)
+
+// RegisterJSONParser adds an alternative JSON parser backend that can be
+// selected by setting the environment variable BENTHOS_JSON_PARSER to its name.
+// This is intended to be called from init functions, usually within files that
+// are only built with a specific build tag as parsers often depend on
+// platform specific optimisations.
+//
+// When a parser other than the standard library is selected and it fails to
+// parse a document then the document is parsed again with the standard library
+// so that errors are identical regardless of the parser used.
+func RegisterJSONParser(name string, fn JSONParseFunc) error {
+	if name == "" || name == "stdlib" {
+		return fmt.Errorf("json parser name %q is reserved", name)
+	}
+
+	jsonParsersMut.Lock()
+	defer jsonParsersMut.Unlock()
+
+	if _, exists := jsonParsers[name]; exists {
+		return fmt.Errorf("json parser %q is already registered", name)
+	}
+	jsonParsers[name] = fn
+	return nil
+}
+
+// getJSONParser returns the selected alternative JSON parser, or nil if the
+// standard library should be used.
+func getJSONParser() JSONParseFunc {
+	jsonParserOnce.Do(func() {
+		name := os.Getenv("BENTHOS_JSON_PARSER")
+
+		jsonParsersMut.Lock()
+		jsonParser = jsonParsers[name]
+		jsonParsersMut.Unlock()
+	})
+	return jsonParser
+}
+
+// ParseJSON attempts to parse a byte slice as a single JSON document, with
+// numbers parsed as float64 values, using the selected JSON parser backend.
+// The behaviour matches that of json.Unmarshal.
+func ParseJSON(b []byte) (interface{}, error) {
+	if fn := getJSONParser(); fn != nil {
+		if v, err := fn(b, false); err == nil {
+			return v, nil
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
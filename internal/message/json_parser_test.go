@@ -0,0 +1,59 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterJSONParserReserved(t *testing.T) {
+	fn := func(b []byte, useNumber bool) (interface{}, error) {
+		return nil, nil
+	}
+	require.Error(t, RegisterJSONParser("", fn))
+	require.Error(t, RegisterJSONParser("stdlib", fn))
+
+	require.NoError(t, RegisterJSONParser("test_duplicate", fn))
+	require.Error(t, RegisterJSONParser("test_duplicate", fn))
+}
+
+func TestJSONParserFallback(t *testing.T) {
+	// Resolve the selected parser before overriding it.
+	_ = getJSONParser()
+	t.Cleanup(func() { jsonParser = nil })
+
+	var calls int
+	jsonParser = func(b []byte, useNumber bool) (interface{}, error) {
+		calls++
+		if string(b) == `{"custom":true}` {
+			return map[string]interface{}{"custom": "parsed"}, nil
+		}
+		return nil, errors.New("custom parser failed")
+	}
+
+	v, err := ParseJSON([]byte(`{"custom":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"custom": "parsed"}, v)
+
+	v, err = ParseJSON([]byte(`{"foo":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": 1.0}, v)
+
+	_, err = ParseJSON([]byte(`{"foo":`))
+	var stdErr error
+	var dummy interface{}
+	stdErr = json.Unmarshal([]byte(`{"foo":`), &dummy)
+	assert.Equal(t, stdErr, err)
+
+	v, err = NewPart([]byte(`{"custom":true}`)).JSON()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"custom": "parsed"}, v)
+
+	_, err = NewPart([]byte(`{"foo":1}{"bar":2}`)).JSON()
+	require.EqualError(t, err, "message contains multiple valid documents")
+
+	assert.Equal(t, 5, calls)
+}
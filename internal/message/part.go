@@ -7,6 +7,9 @@ import (
 	"errors"
 	"io"
 	"os"
+	"sync/atomic"
+
+	"github.com/benthosdev/benthos/v4/internal/bufpool"
 )
 
 var useNumber = true
@@ -24,6 +27,38 @@ type rwData struct {
 	jsonCache interface{}
 	metadata  map[string]string
 	err       error
+
+	// Set to 1 when the metadata map is shared with copies of the part, in
+	// which case it must be cloned before being modified.
+	sharedMeta int32
+}
+
+// shareMeta marks the metadata map as shared and returns it along with the
+// shared flag to give the copy.
+func (r *rwData) shareMeta() (map[string]string, int32) {
+	if r.metadata == nil {
+		return nil, 0
+	}
+	atomic.StoreInt32(&r.sharedMeta, 1)
+	return r.metadata, 1
+}
+
+// writableMeta returns a metadata map that is safe to modify, cloning it first
+// if it is shared with copies of the part.
+func (r *rwData) writableMeta() map[string]string {
+	if r.metadata == nil {
+		r.metadata = map[string]string{}
+		return r.metadata
+	}
+	if atomic.LoadInt32(&r.sharedMeta) == 1 {
+		clonedMeta := make(map[string]string, len(r.metadata))
+		for k, v := range r.metadata {
+			clonedMeta[k] = v
+		}
+		r.metadata = clonedMeta
+		atomic.StoreInt32(&r.sharedMeta, 0)
+	}
+	return r.metadata
 }
 
 // Part represents a single Benthos message.
@@ -44,35 +79,27 @@ func NewPart(data []byte) *Part {
 
 //------------------------------------------------------------------------------
 
-// Copy creates a shallow copy of the message part.
+// Copy creates a shallow copy of the message part. Metadata is shared between
+// the copies until either of them modifies it, at which point it is cloned.
 func (p *Part) Copy() *Part {
-	var clonedMeta map[string]string
-	if p.data.metadata != nil {
-		clonedMeta = make(map[string]string, len(p.data.metadata))
-		for k, v := range p.data.metadata {
-			clonedMeta[k] = v
-		}
-	}
+	meta, sharedMeta := p.data.shareMeta()
 	return &Part{
 		data: &rwData{
-			rawBytes:  p.data.rawBytes,
-			metadata:  clonedMeta,
-			jsonCache: p.data.jsonCache,
-			err:       p.data.err,
+			rawBytes:   p.data.rawBytes,
+			metadata:   meta,
+			sharedMeta: sharedMeta,
+			jsonCache:  p.data.jsonCache,
+			err:        p.data.err,
 		},
 		ctx: p.ctx,
 	}
 }
 
-// DeepCopy creates a new deep copy of the message part.
+// DeepCopy creates a new deep copy of the message part. Metadata is shared
+// between the copies until either of them modifies it, at which point it is
+// cloned.
 func (p *Part) DeepCopy() *Part {
-	var clonedMeta map[string]string
-	if p.data.metadata != nil {
-		clonedMeta = make(map[string]string, len(p.data.metadata))
-		for k, v := range p.data.metadata {
-			clonedMeta[k] = v
-		}
-	}
+	meta, sharedMeta := p.data.shareMeta()
 	var clonedJSON interface{}
 	if p.data.jsonCache != nil {
 		var err error
@@ -87,10 +114,11 @@ func (p *Part) DeepCopy() *Part {
 	}
 	return &Part{
 		data: &rwData{
-			rawBytes:  np,
-			metadata:  clonedMeta,
-			jsonCache: clonedJSON,
-			err:       p.data.err,
+			rawBytes:   np,
+			metadata:   meta,
+			sharedMeta: sharedMeta,
+			jsonCache:  clonedJSON,
+			err:        p.data.err,
 		},
 		ctx: p.ctx,
 	}
@@ -140,15 +168,19 @@ func (p *Part) ErrorSet(err error) {
 // Get returns the body of the message part.
 func (p *Part) Get() []byte {
 	if len(p.data.rawBytes) == 0 && p.data.jsonCache != nil {
-		var buf bytes.Buffer
-		enc := json.NewEncoder(&buf)
+		buf := bufpool.Get()
+		defer bufpool.Put(buf)
+
+		enc := json.NewEncoder(buf)
 		enc.SetEscapeHTML(false)
 		err := enc.Encode(p.data.jsonCache)
 		if err != nil {
 			return nil
 		}
 		if buf.Len() > 1 {
-			p.data.rawBytes = buf.Bytes()[:buf.Len()-1]
+			// Trim the trailing newline added by the encoder.
+			buf.Truncate(buf.Len() - 1)
+			p.data.rawBytes = bufpool.Bytes(buf)
 		}
 	}
 	return p.data.rawBytes
@@ -164,6 +196,14 @@ func (p *Part) JSON() (interface{}, error) {
 		return nil, ErrMessagePartNotExist
 	}
 
+	if fn := getJSONParser(); fn != nil {
+		// Errors are reported by the standard library below.
+		if v, err := fn(p.data.rawBytes, useNumber); err == nil {
+			p.data.jsonCache = v
+			return v, nil
+		}
+	}
+
 	dec := json.NewDecoder(bytes.NewReader(p.data.rawBytes))
 	if useNumber {
 		dec.UseNumber()
@@ -215,13 +255,7 @@ func (p *Part) MetaGet(key string) string {
 
 // MetaSet sets the value of a metadata key.
 func (p *Part) MetaSet(key, value string) {
-	if p.data.metadata == nil {
-		p.data.metadata = map[string]string{
-			key: value,
-		}
-		return
-	}
-	p.data.metadata[key] = value
+	p.data.writableMeta()[key] = value
 }
 
 // MetaDelete removes the value of a metadata key.
@@ -229,7 +263,7 @@ func (p *Part) MetaDelete(key string) {
 	if p.data.metadata == nil {
 		return
 	}
-	delete(p.data.metadata, key)
+	delete(p.data.writableMeta(), key)
 }
 
 // MetaIter iterates each metadata key/value pair.
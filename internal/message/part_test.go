@@ -83,6 +83,41 @@ func TestPartShallowCopy(t *testing.T) {
 	}
 }
 
+func TestPartCopyOnWriteMetadata(t *testing.T) {
+	p := NewPart([]byte(`hello world`))
+	p.MetaSet("foo", "bar")
+
+	p2 := p.Copy()
+	p3 := p.DeepCopy()
+	if reflect.ValueOf(p.data.metadata).Pointer() != reflect.ValueOf(p2.data.metadata).Pointer() {
+		t.Error("Expected metadata to be shared after copy")
+	}
+
+	p.MetaSet("foo", "baz")
+	p2.MetaDelete("foo")
+	p3.MetaSet("foo2", "bar2")
+
+	if exp, act := "baz", p.MetaGet("foo"); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "", p2.MetaGet("foo"); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "bar", p3.MetaGet("foo"); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "", p.MetaGet("foo2"); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	// Once a part owns its metadata again further writes are not cloned.
+	meta := p.data.metadata
+	p.MetaSet("foo3", "bar3")
+	if reflect.ValueOf(meta).Pointer() != reflect.ValueOf(p.data.metadata).Pointer() {
+		t.Error("Expected metadata to be modified in place")
+	}
+}
+
 func TestPartCopyDirtyJSON(t *testing.T) {
 	p := NewPart(nil)
 	dirtyObj := map[string]int{
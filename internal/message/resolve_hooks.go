@@ -0,0 +1,68 @@
+package message
+
+import (
+	"context"
+	"sync"
+)
+
+// ResolveHooks is a set of functions to be called once the transaction of the
+// parts it is associated with has been resolved, which allows processors to
+// observe the outcome of messages that they have passed on even when those
+// messages are removed by a later processor.
+type ResolveHooks struct {
+	mut sync.Mutex
+	fns []func(err error)
+}
+
+type resolveHooksKeyType int
+
+const resolveHooksKey resolveHooksKeyType = iota
+
+// NewResolveHooks creates a set of resolve hooks associated with the parts of
+// a batch.
+func NewResolveHooks(m *Batch) (*ResolveHooks, *Batch) {
+	h := &ResolveHooks{}
+
+	newParts := make([]*Part, m.Len())
+	_ = m.Iter(func(i int, part *Part) error {
+		ctx := context.WithValue(GetContext(part), resolveHooksKey, h)
+		newParts[i] = WithContext(ctx, part)
+		return nil
+	})
+
+	newMsg := QuickBatch(nil)
+	newMsg.SetAll(newParts)
+	return h, newMsg
+}
+
+// OnResolve registers a function to be called with the result of the
+// transaction that a message part belongs to once it has been resolved. When
+// resolve hooks have been associated with the part more than once the function
+// is registered with the most recent set. Returns false if the part has no
+// resolve hooks associated with it, in which case the function is never
+// called.
+func OnResolve(p *Part, fn func(err error)) bool {
+	h, ok := GetContext(p).Value(resolveHooksKey).(*ResolveHooks)
+	if !ok {
+		return false
+	}
+
+	h.mut.Lock()
+	h.fns = append(h.fns, fn)
+	h.mut.Unlock()
+	return true
+}
+
+// Resolve calls each function registered with the hooks with the result of
+// their transaction. Functions are only called once, and so those registered
+// after a call to Resolve are only called by a subsequent call.
+func (h *ResolveHooks) Resolve(err error) {
+	h.mut.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mut.Unlock()
+
+	for _, fn := range fns {
+		fn(err)
+	}
+}
@@ -0,0 +1,35 @@
+package message
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveHooks(t *testing.T) {
+	outer, batch := NewResolveHooks(QuickBatch([][]byte{[]byte("foo"), []byte("bar")}))
+	inner, batch := NewResolveHooks(batch)
+
+	var innerRes []error
+	assert.True(t, OnResolve(batch.Get(0), func(err error) {
+		innerRes = append(innerRes, err)
+	}))
+	assert.True(t, OnResolve(batch.Get(1), func(err error) {
+		innerRes = append(innerRes, err)
+	}))
+	assert.False(t, OnResolve(NewPart([]byte("baz")), func(err error) {
+		t.Error("should not be called")
+	}))
+
+	outer.Resolve(nil)
+	assert.Empty(t, innerRes)
+
+	nope := errors.New("nope")
+	inner.Resolve(nope)
+	assert.Equal(t, []error{nope, nope}, innerRes)
+
+	// Hooks are only called once.
+	inner.Resolve(nil)
+	assert.Equal(t, []error{nope, nope}, innerRes)
+}
@@ -15,16 +15,22 @@ import (
 // In order to fully utilise each processing thread you must either have a
 // number of parallel inputs that matches or surpasses the number of pipeline
 // threads, or use a memory buffer.
+//
+// When PreserveOrder is set the results of processing are propagated in the
+// same order as the messages were consumed, regardless of which thread
+// processed them.
 type Config struct {
-	Threads    int                `json:"threads" yaml:"threads"`
-	Processors []processor.Config `json:"processors" yaml:"processors"`
+	Threads       int                `json:"threads" yaml:"threads"`
+	PreserveOrder bool               `json:"preserve_order" yaml:"preserve_order"`
+	Processors    []processor.Config `json:"processors" yaml:"processors"`
 }
 
 // NewConfig returns a configuration struct fully populated with default values.
 func NewConfig() Config {
 	return Config{
-		Threads:    -1,
-		Processors: []processor.Config{},
+		Threads:       -1,
+		PreserveOrder: false,
+		Processors:    []processor.Config{},
 	}
 }
 
@@ -44,5 +50,8 @@ func New(conf Config, mgr bundle.NewManagement) (Type, error) {
 	if conf.Threads == 1 {
 		return NewProcessor(processors...), nil
 	}
+	if conf.PreserveOrder {
+		return NewOrderedPool(conf.Threads, mgr.Logger(), processors...)
+	}
 	return NewPool(conf.Threads, mgr.Logger(), processors...)
 }
@@ -0,0 +1,206 @@
+package pipeline
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	iprocessor "github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+)
+
+// OrderedPool is a pool of processing threads that, unlike Pool, propagates the
+// results of transactions in the same order as the transactions were consumed.
+// Transactions are processed in parallel, but the results of a transaction are
+// not sent until the results of all prior transactions have been sent.
+type OrderedPool struct {
+	running uint32
+
+	threads       int
+	msgProcessors []iprocessor.V1
+
+	log log.Modular
+
+	messagesIn  <-chan message.Transaction
+	messagesOut chan message.Transaction
+
+	closeChan chan struct{}
+	closed    chan struct{}
+}
+
+// NewOrderedPool creates a new processing pool that preserves the ordering of
+// transactions.
+func NewOrderedPool(threads int, log log.Modular, msgProcessors ...iprocessor.V1) (*OrderedPool, error) {
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+	return &OrderedPool{
+		running:       1,
+		threads:       threads,
+		msgProcessors: msgProcessors,
+		log:           log,
+		messagesOut:   make(chan message.Transaction),
+		closeChan:     make(chan struct{}),
+		closed:        make(chan struct{}),
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// orderedSlot holds the payload of a transaction consumed by the pool along
+// with the results of processing it, which are available once done is closed.
+type orderedSlot struct {
+	payload    *message.Batch
+	ackFn      func(context.Context, error) error
+	resultMsgs []*message.Batch
+	resultRes  error
+	done       chan struct{}
+}
+
+// loop is the processing loop of this pipeline.
+func (p *OrderedPool) loop() {
+	var workersWG sync.WaitGroup
+	defer func() {
+		atomic.StoreUint32(&p.running, 0)
+
+		// Wait for all workers to finish before closing the processors as
+		// they may still be executing them.
+		workersWG.Wait()
+
+		for _, c := range p.msgProcessors {
+			c.CloseAsync()
+		}
+		for _, c := range p.msgProcessors {
+			_ = c.WaitForClose(shutdown.MaximumShutdownWait())
+		}
+
+		close(p.messagesOut)
+		close(p.closed)
+	}()
+
+	closeCtx, done := context.WithCancel(context.Background())
+	defer done()
+	go func() {
+		select {
+		case <-p.closeChan:
+			done()
+		case <-closeCtx.Done():
+		}
+	}()
+
+	workChan := make(chan *orderedSlot)
+
+	// The capacity of the ordering queue limits the number of transactions
+	// that are either being processed or waiting for prior transactions to
+	// be sent.
+	orderChan := make(chan *orderedSlot, p.threads)
+
+	workersWG.Add(p.threads)
+	for i := 0; i < p.threads; i++ {
+		go func() {
+			defer workersWG.Done()
+			for slot := range workChan {
+				slot.resultMsgs, slot.resultRes = iprocessor.ExecuteAll(p.msgProcessors, slot.payload)
+				close(slot.done)
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(workChan)
+			close(orderChan)
+		}()
+		for {
+			var tran message.Transaction
+			var open bool
+			select {
+			case tran, open = <-p.messagesIn:
+				if !open {
+					return
+				}
+			case <-p.closeChan:
+				return
+			}
+
+			slot := &orderedSlot{done: make(chan struct{})}
+			slot.payload, slot.ackFn = withResolveHooks(tran)
+			select {
+			case orderChan <- slot:
+			case <-p.closeChan:
+				return
+			}
+			select {
+			case workChan <- slot:
+			case <-p.closeChan:
+				return
+			}
+		}
+	}()
+
+	for slot := range orderChan {
+		select {
+		case <-slot.done:
+		case <-p.closeChan:
+			return
+		}
+
+		if len(slot.resultMsgs) == 0 {
+			if err := slot.ackFn(closeCtx, slot.resultRes); err != nil && closeCtx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		if len(slot.resultMsgs) > 1 {
+			dispatchMessages(closeCtx, p.closeChan, p.messagesOut, slot.resultMsgs, slot.ackFn)
+			continue
+		}
+
+		select {
+		case p.messagesOut <- message.NewTransactionFunc(slot.resultMsgs[0], slot.ackFn):
+		case <-p.closeChan:
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Consume assigns a messages channel for the pipeline to read.
+func (p *OrderedPool) Consume(msgs <-chan message.Transaction) error {
+	if p.messagesIn != nil {
+		return component.ErrAlreadyStarted
+	}
+	p.messagesIn = msgs
+	go p.loop()
+	return nil
+}
+
+// TransactionChan returns the channel used for consuming messages from this
+// pipeline.
+func (p *OrderedPool) TransactionChan() <-chan message.Transaction {
+	return p.messagesOut
+}
+
+// CloseAsync shuts down the pipeline and stops processing messages.
+func (p *OrderedPool) CloseAsync() {
+	if atomic.CompareAndSwapUint32(&p.running, 1, 0) {
+		close(p.closeChan)
+	}
+}
+
+// WaitForClose blocks until the pipeline has closed down.
+func (p *OrderedPool) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-p.closed:
+	case <-time.After(timeout):
+		return component.ErrTimeout
+	}
+	return nil
+}
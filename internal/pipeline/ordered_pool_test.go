@@ -0,0 +1,95 @@
+package pipeline_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/pipeline"
+)
+
+type sleepyProcessor struct{}
+
+func (s sleepyProcessor) ProcessMessage(msg *message.Batch) ([]*message.Batch, error) {
+	n, err := strconv.Atoi(string(msg.Get(0).Get()))
+	if err != nil {
+		return nil, err
+	}
+	// Earlier messages take longer to process.
+	<-time.After(time.Millisecond * time.Duration(10-n) * 5)
+	if n%3 == 0 {
+		// Filter some messages.
+		return nil, nil
+	}
+	return []*message.Batch{msg}, nil
+}
+
+func (s sleepyProcessor) CloseAsync() {}
+
+func (s sleepyProcessor) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func TestOrderedPoolPreservesOrder(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	proc, err := pipeline.NewOrderedPool(4, log.Noop(), sleepyProcessor{})
+	require.NoError(t, err)
+
+	tChan := make(chan message.Transaction)
+	require.NoError(t, proc.Consume(tChan))
+	assert.Error(t, proc.Consume(tChan))
+
+	resChans := make([]chan error, 10)
+	for i := range resChans {
+		resChans[i] = make(chan error, 1)
+	}
+	go func() {
+		for i := 0; i < 10; i++ {
+			select {
+			case tChan <- message.NewTransaction(message.QuickBatch([][]byte{
+				[]byte(strconv.Itoa(i)),
+			}), resChans[i]):
+			case <-ctx.Done():
+				return
+			}
+		}
+		close(tChan)
+	}()
+
+	var results []string
+	for {
+		var tran message.Transaction
+		var open bool
+		select {
+		case tran, open = <-proc.TransactionChan():
+		case <-ctx.Done():
+			t.Fatal("timed out")
+		}
+		if !open {
+			break
+		}
+		results = append(results, string(tran.Payload.Get(0).Get()))
+		require.NoError(t, tran.Ack(ctx, nil))
+	}
+	assert.Equal(t, []string{"1", "2", "4", "5", "7", "8"}, results)
+
+	for i, resChan := range resChans {
+		select {
+		case err := <-resChan:
+			assert.NoError(t, err, i)
+		case <-ctx.Done():
+			t.Fatal("timed out")
+		}
+	}
+
+	proc.CloseAsync()
+	require.NoError(t, proc.WaitForClose(time.Second*5))
+}
@@ -67,19 +67,20 @@ func (p *Processor) loop() {
 			return
 		}
 
-		resultMsgs, resultRes := iprocessor.ExecuteAll(p.msgProcessors, tran.Payload)
+		payload, ackFn := withResolveHooks(tran)
+		resultMsgs, resultRes := iprocessor.ExecuteAll(p.msgProcessors, payload)
 		if len(resultMsgs) == 0 {
-			if err := tran.Ack(closeCtx, resultRes); err != nil && closeCtx.Err() != nil {
+			if err := ackFn(closeCtx, resultRes); err != nil && closeCtx.Err() != nil {
 				return
 			}
 			continue
 		}
 
 		if len(resultMsgs) > 1 {
-			p.dispatchMessages(closeCtx, resultMsgs, tran.Ack)
+			p.dispatchMessages(closeCtx, resultMsgs, ackFn)
 		} else {
 			select {
-			case p.messagesOut <- message.NewTransactionFunc(resultMsgs[0], tran.Ack):
+			case p.messagesOut <- message.NewTransactionFunc(resultMsgs[0], ackFn):
 			case <-p.shutSig.CloseAtLeisureChan():
 				return
 			}
@@ -87,10 +88,31 @@ func (p *Processor) loop() {
 	}
 }
 
+// withResolveHooks associates resolve hooks with the payload of a transaction
+// before it is processed, returning the payload along with an acknowledgement
+// function that calls the hooks with the result of the transaction.
+func withResolveHooks(tran message.Transaction) (*message.Batch, func(context.Context, error) error) {
+	hooks, payload := message.NewResolveHooks(tran.Payload)
+	return payload, func(ctx context.Context, err error) error {
+		hooks.Resolve(err)
+		return tran.Ack(ctx, err)
+	}
+}
+
 // dispatchMessages attempts to send a multiple messages results of processors
 // over the shared messages channel. This send is retried until success.
 func (p *Processor) dispatchMessages(ctx context.Context, msgs []*message.Batch, ackFn func(context.Context, error) error) {
-	throt := throttle.New(throttle.OptCloseChan(p.shutSig.CloseAtLeisureChan()))
+	dispatchMessages(ctx, p.shutSig.CloseAtLeisureChan(), p.messagesOut, msgs, ackFn)
+}
+
+func dispatchMessages(
+	ctx context.Context,
+	closeChan <-chan struct{},
+	messagesOut chan<- message.Transaction,
+	msgs []*message.Batch,
+	ackFn func(context.Context, error) error,
+) {
+	throt := throttle.New(throttle.OptCloseChan(closeChan))
 
 	pending := msgs
 	for len(pending) > 0 {
@@ -113,7 +135,7 @@ func (p *Processor) dispatchMessages(ctx context.Context, msgs []*message.Batch,
 			})
 
 			select {
-			case p.messagesOut <- transac:
+			case messagesOut <- transac:
 			case <-ctx.Done():
 				return
 			}
@@ -396,3 +396,55 @@ func TestProcessorMultiMsgsOddSync(t *testing.T) {
 		t.Error("Expected mockproc to have waited for close")
 	}
 }
+
+type mockResolveProcessor struct {
+	resolved chan error
+}
+
+func (m *mockResolveProcessor) ProcessMessage(msg *message.Batch) ([]*message.Batch, error) {
+	_ = msg.Iter(func(i int, p *message.Part) error {
+		message.OnResolve(p, func(err error) {
+			m.resolved <- err
+		})
+		return nil
+	})
+	return nil, nil
+}
+
+func (m *mockResolveProcessor) CloseAsync() {}
+
+func (m *mockResolveProcessor) WaitForClose(timeout time.Duration) error {
+	return nil
+}
+
+func TestProcessorResolveHooks(t *testing.T) {
+	mockProc := &mockResolveProcessor{resolved: make(chan error, 1)}
+
+	proc := pipeline.NewProcessor(mockProc)
+
+	tChan, resChan := make(chan message.Transaction), make(chan error)
+	require.NoError(t, proc.Consume(tChan))
+
+	select {
+	case tChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("foo")}), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("Timed out")
+	}
+
+	select {
+	case err := <-mockProc.resolved:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out")
+	}
+
+	select {
+	case err := <-resChan:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out")
+	}
+
+	proc.CloseAsync()
+	require.NoError(t, proc.WaitForClose(time.Second*5))
+}
@@ -12,22 +12,40 @@ import (
 
 //------------------------------------------------------------------------------
 
+// LimitsConfig describes limits on the messages in flight within a stream.
+type LimitsConfig struct {
+	MaxInFlight      int `json:"max_in_flight" yaml:"max_in_flight"`
+	MaxInFlightBytes int `json:"max_in_flight_bytes" yaml:"max_in_flight_bytes"`
+}
+
+// NewLimitsConfig returns a LimitsConfig with default values.
+func NewLimitsConfig() LimitsConfig {
+	return LimitsConfig{
+		MaxInFlight:      0,
+		MaxInFlightBytes: 0,
+	}
+}
+
 // Config is a configuration struct representing all four layers of a Benthos
 // stream.
 type Config struct {
-	Input    input.Config    `json:"input" yaml:"input"`
-	Buffer   buffer.Config   `json:"buffer" yaml:"buffer"`
-	Pipeline pipeline.Config `json:"pipeline" yaml:"pipeline"`
-	Output   output.Config   `json:"output" yaml:"output"`
+	Input     input.Config    `json:"input" yaml:"input"`
+	Buffer    buffer.Config   `json:"buffer" yaml:"buffer"`
+	Pipeline  pipeline.Config `json:"pipeline" yaml:"pipeline"`
+	Output    output.Config   `json:"output" yaml:"output"`
+	Limits    LimitsConfig    `json:"limits" yaml:"limits"`
+	DependsOn []string        `json:"depends_on" yaml:"depends_on"`
 }
 
 // NewConfig returns a new configuration with default values.
 func NewConfig() Config {
 	return Config{
-		Input:    input.NewConfig(),
-		Buffer:   buffer.NewConfig(),
-		Pipeline: pipeline.NewConfig(),
-		Output:   output.NewConfig(),
+		Input:     input.NewConfig(),
+		Buffer:    buffer.NewConfig(),
+		Pipeline:  pipeline.NewConfig(),
+		Output:    output.NewConfig(),
+		Limits:    NewLimitsConfig(),
+		DependsOn: []string{},
 	}
 }
 
@@ -11,8 +11,14 @@ func Spec() docs.FieldSpecs {
 		docs.FieldBuffer("buffer", "An optional buffer to store messages during transit.").Optional(),
 		docs.FieldObject("pipeline", "Describes optional processing pipelines used for mutating messages.").WithChildren(
 			docs.FieldInt("threads", "The number of threads to execute processing pipelines across.").HasDefault(-1),
+			docs.FieldBool("preserve_order", "Whether the results of processing should be propagated in the same order as messages were consumed, regardless of which thread processed them. This allows processing to be spread across multiple threads without breaking outputs that require ordered delivery, at the cost of messages that finish processing early waiting for prior messages to complete.").HasDefault(false).Advanced(),
 			docs.FieldProcessor("processors", "A list of processors to apply to messages.").Array().HasDefault([]interface{}{}),
 		),
 		docs.FieldOutput("output", "An output to sink messages to.").Optional(),
+		docs.FieldObject("limits", "Limits on the messages in flight within the stream, where a message is in flight from the moment it is read by the input until it is acknowledged by the output. When a limit is reached the input stops consuming until messages are acknowledged.").WithChildren(
+			docs.FieldInt("max_in_flight", "The maximum number of message batches in flight, or 0 for no limit.").HasDefault(0),
+			docs.FieldInt("max_in_flight_bytes", "The maximum total size in bytes of message batches in flight, or 0 for no limit. A single batch that exceeds this limit is still allowed through when no other batches are in flight.").HasDefault(0),
+		).Advanced(),
+		docs.FieldString("depends_on", "In streams mode, a list of IDs of other streams that this stream depends on. Streams are started after the streams they depend on, and are stopped before them. Dependencies between streams connected with `inproc` or `inproc_topic` inputs and outputs are detected automatically, where the stream writing to an `inproc` or `inproc_topic` output depends on the streams reading from it. Entries of the form `<type>:<label>`, where the type is one of `cache`, `input`, `output`, `processor` or `rate_limit`, declare a dependency on a resource instead, which must exist when the stream is created and cannot be removed whilst the stream is running.").Array().HasDefault([]string{}).Advanced(),
 	}
 }
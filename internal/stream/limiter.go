@@ -0,0 +1,112 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// inFlightLimiter limits the number and total size of transactions that have
+// been read from an input but not yet acknowledged.
+type inFlightLimiter struct {
+	maxCount int
+	maxBytes int
+
+	cond   *sync.Cond
+	count  int
+	bytes  int
+	closed bool
+
+	closeChan chan struct{}
+}
+
+func newInFlightLimiter(conf LimitsConfig) *inFlightLimiter {
+	return &inFlightLimiter{
+		maxCount: conf.MaxInFlight,
+		maxBytes: conf.MaxInFlightBytes,
+		cond:     sync.NewCond(&sync.Mutex{}),
+
+		closeChan: make(chan struct{}),
+	}
+}
+
+func batchSize(b *message.Batch) (size int) {
+	_ = b.Iter(func(i int, p *message.Part) error {
+		size += len(p.Get())
+		return nil
+	})
+	return
+}
+
+// acquire blocks until a transaction of the given size is within the limits,
+// returns false if the limiter was closed before then.
+func (l *inFlightLimiter) acquire(size int) bool {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	for !l.closed {
+		countOk := l.maxCount <= 0 || l.count < l.maxCount
+		bytesOk := l.maxBytes <= 0 || l.count == 0 || l.bytes+size <= l.maxBytes
+		if countOk && bytesOk {
+			l.count++
+			l.bytes += size
+			return true
+		}
+		l.cond.Wait()
+	}
+	return false
+}
+
+func (l *inFlightLimiter) release(size int) {
+	l.cond.L.Lock()
+	l.count--
+	l.bytes -= size
+	l.cond.L.Unlock()
+	l.cond.Broadcast()
+}
+
+// close unblocks any pending acquire calls.
+func (l *inFlightLimiter) close() {
+	l.cond.L.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.closeChan)
+	}
+	l.cond.L.Unlock()
+	l.cond.Broadcast()
+}
+
+// consume reads transactions from a channel and forwards them once they are
+// within the limits, the returned channel is closed once the input channel is
+// closed or the limiter is closed.
+func (l *inFlightLimiter) consume(in <-chan message.Transaction) <-chan message.Transaction {
+	out := make(chan message.Transaction)
+	go func() {
+		defer close(out)
+		for tran := range in {
+			size := batchSize(tran.Payload)
+			if !l.acquire(size) {
+				_ = tran.Ack(context.Background(), component.ErrTypeClosed)
+				return
+			}
+
+			tran := tran
+			var releaseOnce sync.Once
+			select {
+			case out <- message.NewTransactionFunc(tran.Payload, func(ctx context.Context, err error) error {
+				releaseOnce.Do(func() {
+					l.release(size)
+				})
+				return tran.Ack(ctx, err)
+			}):
+			case <-l.closeChan:
+				l.release(size)
+				_ = tran.Ack(context.Background(), component.ErrTypeClosed)
+				return
+			}
+		}
+	}()
+	return out
+}
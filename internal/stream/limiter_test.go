@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestInFlightLimiterCount(t *testing.T) {
+	l := newInFlightLimiter(LimitsConfig{MaxInFlight: 2})
+
+	in := make(chan message.Transaction)
+	out := l.consume(in)
+
+	resChan := make(chan error, 3)
+	go func() {
+		for i := 0; i < 3; i++ {
+			in <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("hello")}), resChan)
+		}
+		close(in)
+	}()
+
+	var trans []message.Transaction
+	for i := 0; i < 2; i++ {
+		select {
+		case tran := <-out:
+			trans = append(trans, tran)
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+	}
+
+	select {
+	case <-out:
+		t.Fatal("expected third transaction to be blocked")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	require.NoError(t, trans[0].Ack(context.Background(), nil))
+	assert.NoError(t, <-resChan)
+
+	select {
+	case tran := <-out:
+		trans = append(trans, tran)
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	l.close()
+}
+
+func TestInFlightLimiterBytes(t *testing.T) {
+	l := newInFlightLimiter(LimitsConfig{MaxInFlightBytes: 10})
+
+	in := make(chan message.Transaction)
+	out := l.consume(in)
+
+	resChan := make(chan error, 2)
+	go func() {
+		// The first batch exceeds the limit but is allowed through as nothing
+		// else is in flight.
+		in <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("hello world this is big")}), resChan)
+		in <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("small")}), resChan)
+	}()
+
+	var first message.Transaction
+	select {
+	case first = <-out:
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	select {
+	case <-out:
+		t.Fatal("expected second transaction to be blocked")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	require.NoError(t, first.Ack(context.Background(), nil))
+
+	select {
+	case <-out:
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	l.close()
+	close(in)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -48,17 +49,44 @@ func (m *Type) registerEndpoints(enableCrud bool) {
 		"/streams/{id}",
 		"Perform CRUD operations on streams, supporting POST (Create),"+
 			" GET (Read), PUT (Update), PATCH (Patch update)"+
-			" and DELETE (Delete).",
+			" and DELETE (Delete). Responses include the stream version"+
+			" as an ETag, and modifications can be made conditional with"+
+			" an If-Match header.",
 		m.HandleStreamCRUD,
 	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/revisions",
+		"GET a list of the retained config revisions of a stream.",
+		m.HandleStreamRevisions,
+	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/rollback",
+		"POST: Replace a stream with the config of a past revision specified"+
+			" with the query parameter `version`.",
+		m.HandleStreamRollback,
+	)
 	m.manager.RegisterEndpoint(
 		"/streams/{id}/stats",
 		"GET a structured JSON object containing metrics for the stream.",
 		m.HandleStreamStats,
 	)
+	m.manager.RegisterEndpoint(
+		"/templates",
+		"GET: List all stream templates along with the number of their"+
+			" instances that are running.",
+		m.HandleTemplatesList,
+	)
+	m.manager.RegisterEndpoint(
+		"/templates/{id}",
+		"Perform CRUD operations on stream templates, which are instantiated"+
+			" as a stream for each set of instance variables, supporting"+
+			" POST (Create), GET (Read), PUT (Create or update) and DELETE"+
+			" (Delete).",
+		m.HandleTemplateCRUD,
+	)
 	m.manager.RegisterEndpoint(
 		"/resources/{type}/{id}",
-		"POST: Create or replace a given resource configuration of a specified type. Types supported are `cache`, `input`, `output`, `processor` and `rate_limit`.",
+		"POST: Create or replace a given resource configuration of a specified type. Types supported are `cache`, `input`, `output`, `processor` and `rate_limit`. DELETE: Remove a resource of type `cache`, `processor` or `rate_limit`, which fails if a stream depends on the resource.",
 		m.HandleResourceCRUD,
 	)
 }
@@ -84,6 +112,28 @@ func (c ConfigSet) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// parseIfMatch extracts a stream version from the If-Match header of a
+// request. A version of zero is returned when the header is empty or a
+// wildcard.
+func parseIfMatch(r *http.Request) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+	ifMatch = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.Atoi(ifMatch)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid If-Match header, expected a stream version: %v", ifMatch)
+	}
+	return version, nil
+}
+
+func (m *Type) setETag(w http.ResponseWriter, id string) {
+	if info, err := m.Read(id); err == nil {
+		w.Header().Set("ETag", strconv.Quote(strconv.Itoa(info.Version())))
+	}
+}
+
 func lintStreamConfigNode(node *yaml.Node) (lints []string) {
 	for _, dLint := range stream.Spec().LintYAML(docs.NewLintContext(), node) {
 		lints = append(lints, fmt.Sprintf("line %v: %v", dLint.Line, dLint.What))
@@ -116,6 +166,7 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 		Active    bool    `json:"active"`
 		Uptime    float64 `json:"uptime"`
 		UptimeStr string  `json:"uptime_str"`
+		Version   int     `json:"version"`
 	}
 	infos := map[string]confInfo{}
 
@@ -125,6 +176,7 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 			Active:    strInfo.IsRunning(),
 			Uptime:    strInfo.Uptime().Seconds(),
 			UptimeStr: strInfo.Uptime().String(),
+			Version:   strInfo.Version(),
 		}
 	}
 	m.lock.Unlock()
@@ -185,6 +237,10 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 
 	for id := range infos {
 		if newConf, exists := newSet[id]; !exists {
+			// Instances of templates are managed with the templates API.
+			if m.isTemplateInstance(id) {
+				continue
+			}
 			toDelete = append(toDelete, id)
 		} else {
 			toUpdate[id] = newConf
@@ -196,14 +252,25 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var tiers [][]string
+	if tiers, requestErr = StartupOrder(newSet); requestErr != nil {
+		return
+	}
+	var createIDs []string
+	for _, tier := range tiers {
+		for _, id := range tier {
+			if _, exists := toCreate[id]; exists {
+				createIDs = append(createIDs, id)
+			}
+		}
+	}
+
 	wg := sync.WaitGroup{}
 	wg.Add(len(toDelete))
 	wg.Add(len(toUpdate))
-	wg.Add(len(toCreate))
 
 	errDelete := make([]error, len(toDelete))
 	errUpdate := make([]error, len(toUpdate))
-	errCreate := make([]error, len(toCreate))
 
 	// TODO: Replace with context
 	tmpTimeout := time.Second * 5
@@ -223,18 +290,17 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 		}(id, &newConf, i)
 		i++
 	}
-	i = 0
-	for id, conf := range toCreate {
-		newConf := conf
-		go func(sid string, sconf *stream.Config, j int) {
-			errCreate[j] = m.Create(sid, *sconf)
-			wg.Done()
-		}(id, &newConf, i)
-		i++
-	}
-
 	wg.Wait()
 
+	// New streams are created in the order of their dependencies once the
+	// other changes are complete.
+	errCreate := make([]error, 0, len(createIDs))
+	for _, id := range createIDs {
+		if err := m.Create(id, toCreate[id]); err != nil {
+			errCreate = append(errCreate, err)
+		}
+	}
+
 	errs := []string{}
 	for _, err := range errDelete {
 		if err != nil {
@@ -283,6 +349,11 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var version int
+	if version, requestErr = parseIfMatch(r); requestErr != nil {
+		return
+	}
+
 	readConfig := func() (confOut stream.Config, lints []string, err error) {
 		var confBytes []byte
 		if confBytes, err = io.ReadAll(r.Body); err != nil {
@@ -317,24 +388,30 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 		type aliasedOut output.Config
 
 		aliasedConf := struct {
-			Input    aliasedIn   `json:"input"`
-			Buffer   aliasedBuf  `json:"buffer"`
-			Pipeline aliasedPipe `json:"pipeline"`
-			Output   aliasedOut  `json:"output"`
+			Input     aliasedIn           `json:"input"`
+			Buffer    aliasedBuf          `json:"buffer"`
+			Pipeline  aliasedPipe         `json:"pipeline"`
+			Output    aliasedOut          `json:"output"`
+			Limits    stream.LimitsConfig `json:"limits"`
+			DependsOn []string            `json:"depends_on"`
 		}{
-			Input:    aliasedIn(confIn.Input),
-			Buffer:   aliasedBuf(confIn.Buffer),
-			Pipeline: aliasedPipe(confIn.Pipeline),
-			Output:   aliasedOut(confIn.Output),
+			Input:     aliasedIn(confIn.Input),
+			Buffer:    aliasedBuf(confIn.Buffer),
+			Pipeline:  aliasedPipe(confIn.Pipeline),
+			Output:    aliasedOut(confIn.Output),
+			Limits:    confIn.Limits,
+			DependsOn: confIn.DependsOn,
 		}
 		if err = yaml.Unmarshal(patchBytes, &aliasedConf); err != nil {
 			return
 		}
 		confOut = stream.Config{
-			Input:    input.Config(aliasedConf.Input),
-			Buffer:   buffer.Config(aliasedConf.Buffer),
-			Pipeline: pipeline.Config(aliasedConf.Pipeline),
-			Output:   output.Config(aliasedConf.Output),
+			Input:     input.Config(aliasedConf.Input),
+			Buffer:    buffer.Config(aliasedConf.Buffer),
+			Pipeline:  pipeline.Config(aliasedConf.Pipeline),
+			Output:    output.Config(aliasedConf.Output),
+			Limits:    aliasedConf.Limits,
+			DependsOn: aliasedConf.DependsOn,
 		}
 		return
 	}
@@ -359,7 +436,9 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write(errBytes)
 			return
 		}
-		serverErr = m.Create(id, conf)
+		if serverErr = m.Create(id, conf); serverErr == nil {
+			m.setETag(w, id)
+		}
 	case "GET":
 		var info *StreamStatus
 		if info, serverErr = m.Read(id); serverErr == nil {
@@ -370,17 +449,20 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 				Active    bool        `json:"active"`
 				Uptime    float64     `json:"uptime"`
 				UptimeStr string      `json:"uptime_str"`
+				Version   int         `json:"version"`
 				Config    interface{} `json:"config"`
 			}{
 				Active:    info.IsRunning(),
 				Uptime:    info.Uptime().Seconds(),
 				UptimeStr: info.Uptime().String(),
+				Version:   info.Version(),
 				Config:    sanit,
 			}); serverErr != nil {
 				return
 			}
 
 			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", strconv.Quote(strconv.Itoa(info.Version())))
 			_, _ = w.Write(bodyBytes)
 		}
 	case "PUT":
@@ -397,16 +479,25 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write(errBytes)
 			return
 		}
-		serverErr = m.Update(id, conf, tmpTimeout)
+		if serverErr = m.UpdateAtVersion(id, version, conf, tmpTimeout); serverErr == nil {
+			m.setETag(w, id)
+		}
 	case "DELETE":
-		serverErr = m.Delete(id, tmpTimeout)
+		serverErr = m.DeleteAtVersion(id, version, tmpTimeout)
 	case "PATCH":
 		var info *StreamStatus
 		if info, serverErr = m.Read(id); serverErr == nil {
+			if version == 0 {
+				// Patches are always applied against the version they were
+				// derived from.
+				version = info.Version()
+			}
 			if conf, requestErr = patchConfig(info.Config()); requestErr != nil {
 				return
 			}
-			serverErr = m.Update(id, conf, tmpTimeout)
+			if serverErr = m.UpdateAtVersion(id, version, conf, tmpTimeout); serverErr == nil {
+				m.setETag(w, id)
+			}
 		}
 	default:
 		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
@@ -422,6 +513,308 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Stream already exists", http.StatusBadRequest)
 		return
 	}
+	if serverErr == ErrStreamVersionMismatch {
+		serverErr = nil
+		http.Error(w, "Stream version does not match", http.StatusPreconditionFailed)
+		return
+	}
+}
+
+// HandleStreamRevisions is an http.HandleFunc for listing the retained config
+// revisions of a stream.
+func (m *Type) HandleStreamRevisions(w http.ResponseWriter, r *http.Request) {
+	var serverErr, requestErr error
+	defer func() {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		if serverErr != nil {
+			m.manager.Logger().Errorf("Stream revisions Error: %v\n", serverErr)
+			http.Error(w, fmt.Sprintf("Error: %v", serverErr), http.StatusBadGateway)
+			return
+		}
+		if requestErr != nil {
+			m.manager.Logger().Debugf("Stream request revisions Error: %v\n", requestErr)
+			http.Error(w, fmt.Sprintf("Error: %v", requestErr), http.StatusBadRequest)
+			return
+		}
+	}()
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "Var `id` must be set", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != "GET" {
+		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
+		return
+	}
+
+	type revisionInfo struct {
+		Version   int         `json:"version"`
+		CreatedAt time.Time   `json:"created_at"`
+		Config    interface{} `json:"config"`
+	}
+
+	var revs []Revision
+	if revs, serverErr = m.Revisions(id); serverErr != nil {
+		if serverErr == ErrStreamDoesNotExist {
+			serverErr = nil
+			http.Error(w, "Stream not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	infos := make([]revisionInfo, 0, len(revs))
+	for _, rev := range revs {
+		sanit, _ := rev.Config.Sanitised()
+		infos = append(infos, revisionInfo{
+			Version:   rev.Version,
+			CreatedAt: rev.CreatedAt,
+			Config:    sanit,
+		})
+	}
+
+	var resBytes []byte
+	if resBytes, serverErr = json.Marshal(infos); serverErr == nil {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(resBytes)
+	}
+}
+
+// HandleStreamRollback is an http.HandleFunc for replacing a stream with the
+// config of a past revision.
+func (m *Type) HandleStreamRollback(w http.ResponseWriter, r *http.Request) {
+	var serverErr, requestErr error
+	defer func() {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		if serverErr != nil {
+			m.manager.Logger().Errorf("Stream rollback Error: %v\n", serverErr)
+			http.Error(w, fmt.Sprintf("Error: %v", serverErr), http.StatusBadGateway)
+			return
+		}
+		if requestErr != nil {
+			m.manager.Logger().Debugf("Stream request rollback Error: %v\n", requestErr)
+			http.Error(w, fmt.Sprintf("Error: %v", requestErr), http.StatusBadRequest)
+			return
+		}
+	}()
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "Var `id` must be set", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != "POST" {
+		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
+		return
+	}
+
+	var version, toVersion int
+	if version, requestErr = parseIfMatch(r); requestErr != nil {
+		return
+	}
+	if toVersion, requestErr = strconv.Atoi(r.URL.Query().Get("version")); requestErr != nil {
+		requestErr = fmt.Errorf("failed to parse query parameter `version`: %w", requestErr)
+		return
+	}
+
+	// TODO: Replace with context
+	tmpTimeout := time.Second * 5
+
+	switch serverErr = m.Rollback(id, version, toVersion, tmpTimeout); serverErr {
+	case nil:
+		m.setETag(w, id)
+	case ErrStreamDoesNotExist:
+		serverErr = nil
+		http.Error(w, "Stream not found", http.StatusNotFound)
+	case ErrRevisionDoesNotExist:
+		serverErr = nil
+		http.Error(w, "Revision not found", http.StatusNotFound)
+	case ErrStreamVersionMismatch:
+		serverErr = nil
+		http.Error(w, "Stream version does not match", http.StatusPreconditionFailed)
+	}
+}
+
+// HandleTemplatesList is an http.HandleFunc for listing stream templates along
+// with the number of their instances and how many of them are running.
+func (m *Type) HandleTemplatesList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, fmt.Sprintf("Error: verb not supported: %v", r.Method), http.StatusBadRequest)
+		return
+	}
+
+	type templateInfo struct {
+		TotalInstances  int `json:"total_instances"`
+		ActiveInstances int `json:"active_instances"`
+	}
+
+	infos := map[string]templateInfo{}
+	for _, id := range m.Templates() {
+		_, statuses, err := m.ReadTemplate(id)
+		if err != nil {
+			continue
+		}
+		info := templateInfo{TotalInstances: len(statuses)}
+		for _, s := range statuses {
+			if s != nil && s.IsRunning() {
+				info.ActiveInstances++
+			}
+		}
+		infos[id] = info
+	}
+
+	resBytes, err := json.Marshal(infos)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBytes)
+}
+
+// HandleTemplateCRUD is an http.HandleFunc for performing CRUD operations on
+// stream templates.
+func (m *Type) HandleTemplateCRUD(w http.ResponseWriter, r *http.Request) {
+	var serverErr, requestErr error
+	defer func() {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		if serverErr != nil {
+			m.manager.Logger().Errorf("Templates CRUD Error: %v\n", serverErr)
+			http.Error(w, fmt.Sprintf("Error: %v", serverErr), http.StatusBadGateway)
+			return
+		}
+		if requestErr != nil {
+			m.manager.Logger().Debugf("Templates request CRUD Error: %v\n", requestErr)
+			http.Error(w, fmt.Sprintf("Error: %v", requestErr), http.StatusBadRequest)
+			return
+		}
+	}()
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "Var `id` must be set", http.StatusBadRequest)
+		return
+	}
+
+	readTemplate := func() (tmpl StreamTemplate, lints []string, err error) {
+		var tmplBytes []byte
+		if tmplBytes, err = io.ReadAll(r.Body); err != nil {
+			return
+		}
+		if err = yaml.Unmarshal(tmplBytes, &tmpl); err != nil {
+			return
+		}
+		if r.URL.Query().Get("chilled") == "true" {
+			return
+		}
+		for name := range tmpl.Instances {
+			var node yaml.Node
+			if err = yaml.Unmarshal(tmpl.InstanceConfigBytes(name), &node); err != nil {
+				err = fmt.Errorf("instance '%v': %w", name, err)
+				return
+			}
+			for _, l := range lintStreamConfigNode(&node) {
+				lints = append(lints, fmt.Sprintf("instance '%v': %v", name, l))
+			}
+		}
+		sort.Strings(lints)
+		return
+	}
+
+	// TODO: Replace with context
+	tmpTimeout := time.Second * 5
+
+	switch r.Method {
+	case "POST", "PUT":
+		var tmpl StreamTemplate
+		var lints []string
+		if tmpl, lints, requestErr = readTemplate(); requestErr != nil {
+			return
+		}
+		if len(lints) > 0 {
+			errBytes, _ := json.Marshal(struct {
+				LintErrs []string `json:"lint_errors"`
+			}{
+				LintErrs: lints,
+			})
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write(errBytes)
+			return
+		}
+		if r.Method == "POST" {
+			serverErr = m.CreateTemplate(id, tmpl, tmpTimeout)
+		} else {
+			serverErr = m.SetTemplate(id, tmpl, tmpTimeout)
+		}
+	case "GET":
+		var tmpl StreamTemplate
+		var statuses map[string]*StreamStatus
+		if tmpl, statuses, serverErr = m.ReadTemplate(id); serverErr != nil {
+			break
+		}
+
+		type instanceInfo struct {
+			StreamID  string            `json:"stream_id"`
+			Variables map[string]string `json:"variables"`
+			Active    bool              `json:"active"`
+			Uptime    float64           `json:"uptime"`
+			UptimeStr string            `json:"uptime_str"`
+			Version   int               `json:"version"`
+		}
+		info := struct {
+			Config          string                  `json:"config"`
+			Instances       map[string]instanceInfo `json:"instances"`
+			TotalInstances  int                     `json:"total_instances"`
+			ActiveInstances int                     `json:"active_instances"`
+		}{
+			Config:         tmpl.Config,
+			Instances:      map[string]instanceInfo{},
+			TotalInstances: len(statuses),
+		}
+		for name, status := range statuses {
+			iInfo := instanceInfo{
+				StreamID:  TemplateInstanceID(id, name),
+				Variables: tmpl.Instances[name],
+			}
+			if status != nil {
+				iInfo.Active = status.IsRunning()
+				iInfo.Uptime = status.Uptime().Seconds()
+				iInfo.UptimeStr = status.Uptime().String()
+				iInfo.Version = status.Version()
+			}
+			if iInfo.Active {
+				info.ActiveInstances++
+			}
+			info.Instances[name] = iInfo
+		}
+
+		var resBytes []byte
+		if resBytes, serverErr = json.Marshal(info); serverErr == nil {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(resBytes)
+		}
+	case "DELETE":
+		serverErr = m.DeleteTemplate(id, tmpTimeout)
+	default:
+		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
+	}
+
+	switch serverErr {
+	case ErrTemplateDoesNotExist:
+		serverErr = nil
+		http.Error(w, "Template not found", http.StatusNotFound)
+	case ErrTemplateExists:
+		serverErr = nil
+		http.Error(w, "Template already exists", http.StatusBadRequest)
+	}
 }
 
 // HandleResourceCRUD is an http.HandleFunc for performing CRUD operations on
@@ -444,7 +837,7 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	if r.Method != "POST" {
+	if r.Method != "POST" && r.Method != "DELETE" {
 		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
 		return
 	}
@@ -457,6 +850,37 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
+	if r.Method == "DELETE" {
+		docType := docs.Type(mux.Vars(r)["type"])
+		if dependents := m.resourceDependents(docType, id); len(dependents) > 0 {
+			requestErr = fmt.Errorf("resource cannot be removed as the following streams depend on it: %v", strings.Join(dependents, ", "))
+			return
+		}
+
+		var exists bool
+		switch docType {
+		case docs.TypeCache:
+			if exists = m.manager.ProbeCache(id); exists {
+				serverErr = m.manager.RemoveCache(ctx, id)
+			}
+		case docs.TypeProcessor:
+			if exists = m.manager.ProbeProcessor(id); exists {
+				serverErr = m.manager.RemoveProcessor(ctx, id)
+			}
+		case docs.TypeRateLimit:
+			if exists = m.manager.ProbeRateLimit(id); exists {
+				serverErr = m.manager.RemoveRateLimit(ctx, id)
+			}
+		default:
+			http.Error(w, "Var `type` must be set to one of `cache`, `processor` or `rate_limit`", http.StatusBadRequest)
+			return
+		}
+		if !exists {
+			http.Error(w, "Resource does not exist", http.StatusNotFound)
+		}
+		return
+	}
+
 	var storeFn func(*yaml.Node)
 
 	docType := docs.Type(mux.Vars(r)["type"])
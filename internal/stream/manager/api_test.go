@@ -35,6 +35,10 @@ func router(m *manager.Type) *mux.Router {
 	router.HandleFunc("/streams", m.HandleStreamsCRUD)
 	router.HandleFunc("/streams/{id}", m.HandleStreamCRUD)
 	router.HandleFunc("/streams/{id}/stats", m.HandleStreamStats)
+	router.HandleFunc("/streams/{id}/revisions", m.HandleStreamRevisions)
+	router.HandleFunc("/streams/{id}/rollback", m.HandleStreamRollback)
+	router.HandleFunc("/templates", m.HandleTemplatesList)
+	router.HandleFunc("/templates/{id}", m.HandleTemplateCRUD)
 	router.HandleFunc("/resources/{type}/{id}", m.HandleResourceCRUD)
 	return router
 }
@@ -334,6 +338,84 @@ func TestTypeAPIPatch(t *testing.T) {
 	assert.Equal(t, conf.Input.HTTPServer.Path, gabs.Wrap(info.Config).S("input", "http_server", "path").Data())
 }
 
+func TestTypeAPIVersioning(t *testing.T) {
+	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(res)
+	r := router(mgr)
+
+	conf := harmlessConf()
+	conf.Input.HTTPServer.Path = "/first"
+
+	request := genRequest("POST", "/streams/foo?chilled=true", conf)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, `"1"`, response.Header().Get("ETag"))
+
+	conf.Input.HTTPServer.Path = "/second"
+	request = genRequest("PUT", "/streams/foo?chilled=true", conf)
+	request.Header.Set("If-Match", `"1"`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, `"2"`, response.Header().Get("ETag"))
+
+	// A concurrent writer with a stale version is rejected
+	conf.Input.HTTPServer.Path = "/stale"
+	request = genRequest("PUT", "/streams/foo?chilled=true", conf)
+	request.Header.Set("If-Match", `"1"`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusPreconditionFailed, response.Code, response.Body.String())
+
+	request = genRequest("DELETE", "/streams/foo", nil)
+	request.Header.Set("If-Match", `"1"`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusPreconditionFailed, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/streams/foo", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, `"2"`, response.Header().Get("ETag"))
+	assert.Equal(t, "/second", gabs.Wrap(parseGetBody(t, response.Body).Config).S("input", "http_server", "path").Data())
+
+	request = genRequest("GET", "/streams/foo/revisions", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	revs, err := gabs.ParseJSON(response.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, revs.Children(), 2)
+	assert.Equal(t, float64(1), revs.S("0", "version").Data())
+	assert.Equal(t, "/first", revs.S("0", "config", "input", "http_server", "path").Data())
+	assert.Equal(t, float64(2), revs.S("1", "version").Data())
+
+	request = genRequest("POST", "/streams/foo/rollback?version=5", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusNotFound, response.Code, response.Body.String())
+
+	request = genRequest("POST", "/streams/foo/rollback?version=1", nil)
+	request.Header.Set("If-Match", `"2"`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, `"3"`, response.Header().Get("ETag"))
+
+	request = genRequest("GET", "/streams/foo", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "/first", gabs.Wrap(parseGetBody(t, response.Body).Config).S("input", "http_server", "path").Data())
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
+
 func TestTypeAPIBasicOperationsYAML(t *testing.T) {
 	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
 	require.NoError(t, err)
@@ -518,6 +600,123 @@ func TestTypeAPISetStreams(t *testing.T) {
 	assert.Equal(t, "BAZ_ONE", gabs.Wrap(conf.Config).S("input", "http_server", "path").Data())
 }
 
+func TestTypeAPITemplates(t *testing.T) {
+	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(res)
+
+	r := router(mgr)
+
+	tmplConf := `
+input:
+  http_server:
+    path: /${TENANT}
+output:
+  drop: {}
+`
+
+	request := genYAMLRequest("POST", "/templates/foo", map[string]interface{}{
+		"config": tmplConf,
+		"instances": map[string]interface{}{
+			"a": map[string]string{"TENANT": "tenant_a"},
+			"b": map[string]string{"TENANT": "tenant_b"},
+		},
+	})
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/streams/foo_b", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	conf := parseGetBody(t, response.Body)
+	assert.Equal(t, "/tenant_b", gabs.Wrap(conf.Config).S("input", "http_server", "path").Data())
+
+	// Setting the streams collection leaves template instances untouched.
+	request = genRequest("POST", "/streams", map[string]interface{}{})
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/templates/foo", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	tmplInfo, err := gabs.ParseJSON(response.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), tmplInfo.S("total_instances").Data())
+	assert.Equal(t, float64(2), tmplInfo.S("active_instances").Data())
+	assert.Equal(t, "foo_a", tmplInfo.S("instances", "a", "stream_id").Data())
+	assert.Equal(t, "tenant_a", tmplInfo.S("instances", "a", "variables", "TENANT").Data())
+	assert.Equal(t, true, tmplInfo.S("instances", "a", "active").Data())
+
+	request = genYAMLRequest("POST", "/templates/foo", map[string]interface{}{
+		"config": tmplConf,
+	})
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+
+	request = genYAMLRequest("PUT", "/templates/foo", map[string]interface{}{
+		"config": "input:\n  http_server: {}\nnope: true\noutput:\n  drop: {}\n",
+		"instances": map[string]interface{}{
+			"a": map[string]string{},
+		},
+	})
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), "lint_errors")
+
+	request = genYAMLRequest("PUT", "/templates/foo", map[string]interface{}{
+		"config": tmplConf,
+		"instances": map[string]interface{}{
+			"b": map[string]string{"TENANT": "tenant_b"},
+		},
+	})
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/templates", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	listInfo, err := gabs.ParseJSON(response.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), listInfo.S("foo", "total_instances").Data())
+
+	request = genRequest("GET", "/streams", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	info := parseListBody(response.Body)
+	assert.NotContains(t, info, "foo_a")
+	assert.Contains(t, info, "foo_b")
+
+	request = genRequest("DELETE", "/templates/foo", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/templates/foo", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/streams", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Empty(t, parseListBody(response.Body))
+}
+
 func TestTypeAPIStreamsDefaultConf(t *testing.T) {
 	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
 	require.NoError(t, err)
@@ -884,3 +1083,70 @@ file:
 	require.NoError(t, err)
 	assert.Equal(t, `{"id":"second","content":"hello world 2"}`, string(file2Bytes))
 }
+
+func TestTypeAPIRemoveResources(t *testing.T) {
+	bmgr, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	r := router(manager.New(bmgr))
+
+	request := genYAMLRequest("POST", "/resources/cache/foocache?chilled=true", `
+memory: {}
+`)
+	hResponse := httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusOK, hResponse.Code, hResponse.Body.String())
+	assert.True(t, bmgr.ProbeCache("foocache"))
+
+	request = genRequest("DELETE", "/resources/cache/foocache", nil)
+	hResponse = httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusOK, hResponse.Code, hResponse.Body.String())
+	assert.False(t, bmgr.ProbeCache("foocache"))
+
+	request = genRequest("DELETE", "/resources/cache/foocache", nil)
+	hResponse = httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusNotFound, hResponse.Code)
+
+	request = genRequest("DELETE", "/resources/input/fooinput", nil)
+	hResponse = httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusBadRequest, hResponse.Code)
+}
+
+func TestTypeAPIRemoveResourcesDependedOn(t *testing.T) {
+	bmgr, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(bmgr)
+	r := router(mgr)
+
+	conf := harmlessConf()
+	conf.DependsOn = []string{"cache:foocache"}
+	require.EqualError(t, mgr.Create("foo", conf), "stream depends on cache resource 'foocache', which does not exist")
+
+	request := genYAMLRequest("POST", "/resources/cache/foocache?chilled=true", `
+memory: {}
+`)
+	hResponse := httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusOK, hResponse.Code, hResponse.Body.String())
+
+	require.NoError(t, mgr.Create("foo", conf))
+
+	request = genRequest("DELETE", "/resources/cache/foocache", nil)
+	hResponse = httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusBadRequest, hResponse.Code)
+	assert.Contains(t, hResponse.Body.String(), "the following streams depend on it: foo")
+	assert.True(t, bmgr.ProbeCache("foocache"))
+
+	require.NoError(t, mgr.Delete("foo", time.Second))
+
+	request = genRequest("DELETE", "/resources/cache/foocache", nil)
+	hResponse = httptest.NewRecorder()
+	r.ServeHTTP(hResponse, request)
+	assert.Equal(t, http.StatusOK, hResponse.Code, hResponse.Body.String())
+	assert.False(t, bmgr.ProbeCache("foocache"))
+}
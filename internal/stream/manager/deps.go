@@ -0,0 +1,198 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+// inprocPipes returns the names of inproc pipes and topics found within a
+// config node. Topic names are prefixed in order to distinguish them from pipes
+// of the same name.
+func inprocPipes(node *yaml.Node) (pipes []string) {
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range node.Content {
+			pipes = append(pipes, inprocPipes(c)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if k.Value == "inproc" && v.Kind == yaml.ScalarNode {
+				if v.Value != "" {
+					pipes = append(pipes, v.Value)
+				}
+				continue
+			}
+			if k.Value == "inproc_topic" && v.Kind == yaml.MappingNode {
+				for j := 0; j < len(v.Content)-1; j += 2 {
+					if v.Content[j].Value == "topic" && v.Content[j+1].Value != "" {
+						pipes = append(pipes, "inproc_topic:"+v.Content[j+1].Value)
+					}
+				}
+				continue
+			}
+			pipes = append(pipes, inprocPipes(v)...)
+		}
+	}
+	return
+}
+
+// sanitisedSections returns the sanitised input and output sections of a
+// stream config, which excludes the fields of unused component types.
+func sanitisedSections(conf stream.Config) (input, output *yaml.Node) {
+	var node yaml.Node
+	if err := node.Encode(conf); err != nil {
+		return nil, nil
+	}
+
+	sanitConf := docs.NewSanitiseConfig()
+	sanitConf.RemoveTypeField = true
+	if err := stream.Spec().SanitiseYAML(&node, sanitConf); err != nil {
+		return nil, nil
+	}
+
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		switch node.Content[i].Value {
+		case "input":
+			input = node.Content[i+1]
+		case "output":
+			output = node.Content[i+1]
+		}
+	}
+	return
+}
+
+// ResourceDependency parses an entry of the `depends_on` field of a stream
+// config that refers to a resource rather than a stream, which takes the form
+// `<type>:<label>`, where the type is one of `cache`, `input`, `output`,
+// `processor` or `rate_limit`. Returns false if the entry refers to a stream.
+func ResourceDependency(dep string) (docs.Type, string, bool) {
+	i := strings.Index(dep, ":")
+	if i == -1 {
+		return "", "", false
+	}
+	switch t := docs.Type(dep[:i]); t {
+	case docs.TypeCache, docs.TypeInput, docs.TypeOutput, docs.TypeProcessor, docs.TypeRateLimit:
+		return t, dep[i+1:], true
+	}
+	return "", "", false
+}
+
+// Dependencies returns a map of stream IDs to the IDs of other streams within
+// the set that they depend on. Dependencies are either declared explicitly
+// with the `depends_on` field, or inferred from inproc pipes and topics, where a
+// stream writing to an inproc output depends on the streams reading from it.
+// Dependencies on resources and on streams that are not within the set are
+// ignored.
+func Dependencies(confs map[string]stream.Config) map[string][]string {
+	inputs := make(map[string]*yaml.Node, len(confs))
+	outputs := make(map[string]*yaml.Node, len(confs))
+	readers := map[string][]string{}
+	for id, conf := range confs {
+		inputs[id], outputs[id] = sanitisedSections(conf)
+		for _, pipe := range inprocPipes(inputs[id]) {
+			readers[pipe] = append(readers[pipe], id)
+		}
+	}
+
+	deps := make(map[string][]string, len(confs))
+	for id, conf := range confs {
+		seen := map[string]struct{}{id: {}}
+		var idDeps []string
+		add := func(dep string) {
+			if _, exists := confs[dep]; !exists {
+				return
+			}
+			if _, exists := seen[dep]; exists {
+				return
+			}
+			seen[dep] = struct{}{}
+			idDeps = append(idDeps, dep)
+		}
+		for _, dep := range conf.DependsOn {
+			if _, _, isResource := ResourceDependency(dep); isResource {
+				continue
+			}
+			add(dep)
+		}
+		for _, pipe := range inprocPipes(outputs[id]) {
+			for _, dep := range readers[pipe] {
+				add(dep)
+			}
+		}
+		sort.Strings(idDeps)
+		deps[id] = idDeps
+	}
+	return deps
+}
+
+// StartupOrder returns a set of stream configs grouped into tiers, where each
+// stream only depends on streams within prior tiers. Streams should be started
+// tier by tier, and stopped in the reverse order. An error is returned if a
+// stream explicitly depends on a stream that is not within the set, or if the
+// dependencies contain a cycle.
+func StartupOrder(confs map[string]stream.Config) ([][]string, error) {
+	ids := make([]string, 0, len(confs))
+	for id := range confs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, dep := range confs[id].DependsOn {
+			if _, _, isResource := ResourceDependency(dep); isResource {
+				continue
+			}
+			if _, exists := confs[dep]; !exists {
+				return nil, fmt.Errorf("stream '%v' depends on unknown stream '%v'", id, dep)
+			}
+		}
+	}
+
+	deps := Dependencies(confs)
+
+	var tiers [][]string
+	placed := make(map[string]struct{}, len(confs))
+	for len(placed) < len(confs) {
+		var tier []string
+		for id, idDeps := range deps {
+			if _, done := placed[id]; done {
+				continue
+			}
+			ready := true
+			for _, dep := range idDeps {
+				if _, done := placed[dep]; !done {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				tier = append(tier, id)
+			}
+		}
+		if len(tier) == 0 {
+			var remaining []string
+			for id := range deps {
+				if _, done := placed[id]; !done {
+					remaining = append(remaining, id)
+				}
+			}
+			sort.Strings(remaining)
+			return nil, fmt.Errorf("dependency cycle detected between streams: %v", strings.Join(remaining, ", "))
+		}
+		sort.Strings(tier)
+		for _, id := range tier {
+			placed[id] = struct{}{}
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
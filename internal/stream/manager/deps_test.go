@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+func inprocConf(in, out string) stream.Config {
+	c := harmlessConf()
+	if in != "" {
+		c.Input.Type = "inproc"
+		c.Input.Inproc = input.InprocConfig(in)
+	}
+	if out != "" {
+		c.Output.Type = "inproc"
+		c.Output.Inproc = out
+	}
+	return c
+}
+
+func TestStreamDependencies(t *testing.T) {
+	explicit := harmlessConf()
+	explicit.DependsOn = []string{"producer", "cache:foo"}
+
+	confs := map[string]stream.Config{
+		"producer": inprocConf("", "foo"),
+		"consumer": inprocConf("foo", "bar"),
+		"sink":     inprocConf("bar", ""),
+		"explicit": explicit,
+	}
+
+	assert.Equal(t, map[string][]string{
+		"producer": {"consumer"},
+		"consumer": {"sink"},
+		"sink":     nil,
+		"explicit": {"producer"},
+	}, Dependencies(confs))
+
+	tiers, err := StartupOrder(confs)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"sink"},
+		{"consumer"},
+		{"producer"},
+		{"explicit"},
+	}, tiers)
+}
+
+func TestStreamDependenciesUnknown(t *testing.T) {
+	explicit := harmlessConf()
+	explicit.DependsOn = []string{"producer", "nope"}
+
+	confs := map[string]stream.Config{
+		"producer": inprocConf("", "foo"),
+		"explicit": explicit,
+	}
+
+	assert.Equal(t, map[string][]string{
+		"producer": nil,
+		"explicit": {"producer"},
+	}, Dependencies(confs))
+
+	_, err := StartupOrder(confs)
+	require.EqualError(t, err, "stream 'explicit' depends on unknown stream 'nope'")
+}
+
+func TestResourceDependency(t *testing.T) {
+	for _, test := range []struct {
+		dep    string
+		rType  docs.Type
+		label  string
+		isRsrc bool
+	}{
+		{dep: "cache:foo", rType: docs.TypeCache, label: "foo", isRsrc: true},
+		{dep: "rate_limit:bar:baz", rType: docs.TypeRateLimit, label: "bar:baz", isRsrc: true},
+		{dep: "foo"},
+		{dep: "buffer:foo"},
+	} {
+		rType, label, isRsrc := ResourceDependency(test.dep)
+		assert.Equal(t, test.rType, rType, test.dep)
+		assert.Equal(t, test.label, label, test.dep)
+		assert.Equal(t, test.isRsrc, isRsrc, test.dep)
+	}
+}
+
+func TestStreamDependenciesCycle(t *testing.T) {
+	confs := map[string]stream.Config{
+		"a": inprocConf("foo", "bar"),
+		"b": inprocConf("bar", "foo"),
+		"c": harmlessConf(),
+	}
+
+	_, err := StartupOrder(confs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a, b")
+}
+
+func TestInprocPipesTopics(t *testing.T) {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+broker:
+  inputs:
+    - inproc: foo
+    - inproc_topic:
+        topic: bar
+        buffer_size: 10
+    - inproc_topic:
+        topic: foo
+`), &node))
+
+	assert.Equal(t, []string{"foo", "inproc_topic:bar", "inproc_topic:foo"}, inprocPipes(&node))
+}
@@ -0,0 +1,242 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+// StreamTemplate is a parameterised stream config that is instantiated as a
+// separate stream for each named set of variables within Instances. Variables
+// are referenced within the config with the same `${NAME:default}` pattern as
+// environment variables, and variables that an instance does not set fall back
+// to environment variables.
+type StreamTemplate struct {
+	Config    string                       `json:"config" yaml:"config"`
+	Instances map[string]map[string]string `json:"instances" yaml:"instances"`
+}
+
+// TemplateInstanceID returns the ID of the stream of an instance of a
+// template.
+func TemplateInstanceID(templateID, instance string) string {
+	return templateID + "_" + instance
+}
+
+// InstanceConfigBytes returns the config of an instance of the template with
+// its variables replaced.
+func (t StreamTemplate) InstanceConfigBytes(instance string) []byte {
+	vars := t.Instances[instance]
+	return config.ReplaceVariables([]byte(t.Config), func(name string) string {
+		if v, exists := vars[name]; exists {
+			return v
+		}
+		return os.Getenv(name)
+	})
+}
+
+// InstanceConfigs parses the stream config of each instance of the template.
+func (t StreamTemplate) InstanceConfigs() (map[string]stream.Config, error) {
+	confs := make(map[string]stream.Config, len(t.Instances))
+	for name := range t.Instances {
+		if name == "" {
+			return nil, errors.New("instance names must not be empty")
+		}
+		conf := stream.NewConfig()
+		if err := yaml.Unmarshal(t.InstanceConfigBytes(name), &conf); err != nil {
+			return nil, fmt.Errorf("instance '%v': %w", name, err)
+		}
+		confs[name] = conf
+	}
+	return confs, nil
+}
+
+// templateState tracks the instances of a template that currently have a
+// stream owned by the template.
+type templateState struct {
+	template  StreamTemplate
+	instances map[string]struct{}
+}
+
+// Errors specifically returned by a stream manager for templates.
+var (
+	ErrTemplateExists       = errors.New("stream template already exists")
+	ErrTemplateDoesNotExist = errors.New("stream template does not exist")
+)
+
+//------------------------------------------------------------------------------
+
+// CreateTemplate attempts to create a stream for each instance of a new
+// template. If a template with the same ID already exists an error is
+// returned.
+func (m *Type) CreateTemplate(id string, tmpl StreamTemplate, timeout time.Duration) error {
+	m.templateLock.Lock()
+	defer m.templateLock.Unlock()
+
+	if _, exists := m.templates[id]; exists {
+		return ErrTemplateExists
+	}
+	return m.setTemplate(id, tmpl, timeout)
+}
+
+// SetTemplate creates or replaces a template, where a stream is created for
+// each new instance, the streams of existing instances are updated when their
+// config has changed, and the streams of instances that are no longer present
+// are removed.
+func (m *Type) SetTemplate(id string, tmpl StreamTemplate, timeout time.Duration) error {
+	m.templateLock.Lock()
+	defer m.templateLock.Unlock()
+
+	return m.setTemplate(id, tmpl, timeout)
+}
+
+func (m *Type) setTemplate(id string, tmpl StreamTemplate, timeout time.Duration) error {
+	confs, err := tmpl.InstanceConfigs()
+	if err != nil {
+		return err
+	}
+
+	prev, exists := m.templates[id]
+	if !exists {
+		prev = &templateState{instances: map[string]struct{}{}}
+	}
+
+	// Streams that are not owned by the template are never replaced.
+	for name := range confs {
+		if _, owned := prev.instances[name]; owned {
+			continue
+		}
+		sid := TemplateInstanceID(id, name)
+		if _, err := m.Read(sid); err == nil {
+			return fmt.Errorf("instance '%v': stream '%v': %w", name, sid, ErrStreamExists)
+		}
+	}
+
+	state := &templateState{
+		template:  tmpl,
+		instances: map[string]struct{}{},
+	}
+	m.templates[id] = state
+
+	var errs []string
+	for name := range prev.instances {
+		if _, exists := confs[name]; exists {
+			continue
+		}
+		if err := m.Delete(TemplateInstanceID(id, name), timeout); err != nil && err != ErrStreamDoesNotExist {
+			errs = append(errs, fmt.Sprintf("failed to delete instance '%v': %v", name, err))
+			state.instances[name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(confs))
+	for name := range confs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sid := TemplateInstanceID(id, name)
+		err := m.Update(sid, confs[name], timeout)
+		if err == ErrStreamDoesNotExist {
+			err = m.Create(sid, confs[name])
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to set instance '%v': %v", name, err))
+			continue
+		}
+		state.instances[name] = struct{}{}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// ReadTemplate returns a template along with the status of the stream of each
+// of its instances, where the status is nil for instances that have no
+// running stream.
+func (m *Type) ReadTemplate(id string) (StreamTemplate, map[string]*StreamStatus, error) {
+	m.templateLock.Lock()
+	defer m.templateLock.Unlock()
+
+	state, exists := m.templates[id]
+	if !exists {
+		return StreamTemplate{}, nil, ErrTemplateDoesNotExist
+	}
+
+	statuses := make(map[string]*StreamStatus, len(state.template.Instances))
+	for name := range state.template.Instances {
+		statuses[name] = nil
+		if _, owned := state.instances[name]; !owned {
+			continue
+		}
+		if strm, err := m.Read(TemplateInstanceID(id, name)); err == nil {
+			statuses[name] = strm
+		}
+	}
+	return state.template, statuses, nil
+}
+
+// Templates returns the IDs of all templates.
+func (m *Type) Templates() []string {
+	m.templateLock.Lock()
+	defer m.templateLock.Unlock()
+
+	ids := make([]string, 0, len(m.templates))
+	for id := range m.templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// DeleteTemplate attempts to stop and remove the streams of all instances of
+// a template, followed by the template itself.
+func (m *Type) DeleteTemplate(id string, timeout time.Duration) error {
+	m.templateLock.Lock()
+	defer m.templateLock.Unlock()
+
+	state, exists := m.templates[id]
+	if !exists {
+		return ErrTemplateDoesNotExist
+	}
+
+	var errs []string
+	for name := range state.instances {
+		if err := m.Delete(TemplateInstanceID(id, name), timeout); err != nil && err != ErrStreamDoesNotExist {
+			errs = append(errs, fmt.Sprintf("failed to delete instance '%v': %v", name, err))
+			continue
+		}
+		delete(state.instances, name)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+
+	delete(m.templates, id)
+	return nil
+}
+
+// isTemplateInstance returns true if a stream is owned by a template.
+func (m *Type) isTemplateInstance(streamID string) bool {
+	m.templateLock.Lock()
+	defer m.templateLock.Unlock()
+
+	for id, state := range m.templates {
+		for name := range state.instances {
+			if TemplateInstanceID(id, name) == streamID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	bmanager "github.com/benthosdev/benthos/v4/internal/manager"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+const testTemplateConfig = `
+input:
+  http_server:
+    path: /${TENANT}/${SUFFIX:post}
+output:
+  drop: {}
+`
+
+func TestTemplateInstanceConfigs(t *testing.T) {
+	tmpl := StreamTemplate{
+		Config: testTemplateConfig,
+		Instances: map[string]map[string]string{
+			"a": {"TENANT": "foo"},
+			"b": {"TENANT": "bar", "SUFFIX": "baz"},
+		},
+	}
+
+	confs, err := tmpl.InstanceConfigs()
+	require.NoError(t, err)
+	require.Len(t, confs, 2)
+
+	assert.Equal(t, "/foo/post", confs["a"].Input.HTTPServer.Path)
+	assert.Equal(t, "/bar/baz", confs["b"].Input.HTTPServer.Path)
+	assert.Equal(t, "drop", confs["b"].Output.Type)
+
+	tmpl.Config = "input: [ nope"
+	_, err = tmpl.InstanceConfigs()
+	require.Error(t, err)
+}
+
+func TestTemplateOperations(t *testing.T) {
+	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := New(res)
+	defer func() {
+		assert.NoError(t, mgr.Stop(time.Second*5))
+	}()
+
+	tmpl := StreamTemplate{
+		Config: testTemplateConfig,
+		Instances: map[string]map[string]string{
+			"a": {"TENANT": "a"},
+			"b": {"TENANT": "b"},
+		},
+	}
+	require.NoError(t, mgr.CreateTemplate("foo", tmpl, time.Second))
+	assert.Equal(t, ErrTemplateExists, mgr.CreateTemplate("foo", tmpl, time.Second))
+	assert.Equal(t, []string{"foo"}, mgr.Templates())
+
+	for _, name := range []string{"a", "b"} {
+		strm, err := mgr.Read("foo_" + name)
+		require.NoError(t, err)
+		assert.Equal(t, "/"+name+"/post", strm.Config().Input.HTTPServer.Path)
+		assert.Equal(t, 1, strm.Version())
+	}
+
+	// Instance a is removed, b is left unchanged, and c is added.
+	tmpl = StreamTemplate{
+		Config: testTemplateConfig,
+		Instances: map[string]map[string]string{
+			"b": {"TENANT": "b"},
+			"c": {"TENANT": "c"},
+		},
+	}
+	require.NoError(t, mgr.SetTemplate("foo", tmpl, time.Second))
+
+	_, err = mgr.Read("foo_a")
+	assert.Equal(t, ErrStreamDoesNotExist, err)
+
+	strm, err := mgr.Read("foo_b")
+	require.NoError(t, err)
+	assert.Equal(t, 1, strm.Version())
+
+	strm, err = mgr.Read("foo_c")
+	require.NoError(t, err)
+	assert.Equal(t, "/c/post", strm.Config().Input.HTTPServer.Path)
+
+	// Changing the variables of an instance updates only its stream.
+	tmpl.Instances["b"] = map[string]string{"TENANT": "b", "SUFFIX": "other"}
+	require.NoError(t, mgr.SetTemplate("foo", tmpl, time.Second))
+
+	strm, err = mgr.Read("foo_b")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strm.Version())
+	assert.Equal(t, "/b/other", strm.Config().Input.HTTPServer.Path)
+
+	strm, err = mgr.Read("foo_c")
+	require.NoError(t, err)
+	assert.Equal(t, 1, strm.Version())
+
+	// A deleted instance stream is reported and recreated on the next set.
+	require.NoError(t, mgr.Delete("foo_c", time.Second))
+
+	_, statuses, err := mgr.ReadTemplate("foo")
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.NotNil(t, statuses["b"])
+	assert.Nil(t, statuses["c"])
+
+	require.NoError(t, mgr.SetTemplate("foo", tmpl, time.Second))
+	_, err = mgr.Read("foo_c")
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.DeleteTemplate("foo", time.Second))
+	for _, name := range []string{"b", "c"} {
+		_, err = mgr.Read("foo_" + name)
+		assert.Equal(t, ErrStreamDoesNotExist, err)
+	}
+	assert.Equal(t, ErrTemplateDoesNotExist, mgr.DeleteTemplate("foo", time.Second))
+	assert.Empty(t, mgr.Templates())
+}
+
+func TestTemplateStreamCollision(t *testing.T) {
+	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := New(res)
+	defer func() {
+		assert.NoError(t, mgr.Stop(time.Second*5))
+	}()
+
+	require.NoError(t, mgr.Create("foo_a", harmlessConf()))
+
+	err = mgr.CreateTemplate("foo", StreamTemplate{
+		Config: testTemplateConfig,
+		Instances: map[string]map[string]string{
+			"a": {"TENANT": "a"},
+		},
+	}, time.Second)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrStreamExists))
+
+	_, _, err = mgr.ReadTemplate("foo")
+	assert.Equal(t, ErrTemplateDoesNotExist, err)
+	assert.False(t, mgr.isTemplateInstance("foo_a"))
+}
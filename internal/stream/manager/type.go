@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/stream"
 )
@@ -24,6 +26,7 @@ type StreamStatus struct {
 	logger       log.Modular
 	metrics      *metrics.Local
 	createdAt    time.Time
+	version      int
 }
 
 // NewStreamStatus creates a new StreamStatus.
@@ -67,6 +70,12 @@ func (s *StreamStatus) Config() stream.Config {
 	return s.config
 }
 
+// Version returns the revision number of the stream config, which is
+// incremented each time the stream is updated.
+func (s *StreamStatus) Version() int {
+	return s.version
+}
+
 // Metrics returns a metrics aggregator of the stream.
 func (s *StreamStatus) Metrics() *metrics.Local {
 	return s.metrics
@@ -85,14 +94,37 @@ type StreamProcConstructorFunc func(streamID string) (processor.V1, error)
 
 //------------------------------------------------------------------------------
 
+// Revision is a historical version of a stream config.
+type Revision struct {
+	Version   int
+	Config    stream.Config
+	CreatedAt time.Time
+}
+
+type streamHistory struct {
+	writeLock sync.Mutex
+
+	// The latest version is retained after a stream is deleted so that
+	// version numbers are never reused for the same stream ID.
+	latest    int
+	revisions []Revision
+}
+
+//------------------------------------------------------------------------------
+
 // Type manages a collection of streams, providing APIs for CRUD operations on
 // the streams.
 type Type struct {
 	closed  bool
 	streams map[string]*StreamStatus
+	history map[string]*streamHistory
+
+	templates    map[string]*templateState
+	templateLock sync.Mutex
 
-	manager    bundle.NewManagement
-	apiEnabled bool
+	manager      bundle.NewManagement
+	apiEnabled   bool
+	historyLimit int
 
 	lock sync.Mutex
 }
@@ -100,9 +132,12 @@ type Type struct {
 // New creates a new stream manager.Type.
 func New(mgr bundle.NewManagement, opts ...func(*Type)) *Type {
 	t := &Type{
-		streams:    map[string]*StreamStatus{},
-		apiEnabled: true,
-		manager:    mgr,
+		streams:      map[string]*StreamStatus{},
+		history:      map[string]*streamHistory{},
+		templates:    map[string]*templateState{},
+		apiEnabled:   true,
+		historyLimit: 10,
+		manager:      mgr,
 	}
 	for _, opt := range opts {
 		opt(t)
@@ -121,19 +156,103 @@ func OptAPIEnabled(b bool) func(*Type) {
 	}
 }
 
+// OptRevisionHistoryLimit sets the maximum number of past config revisions
+// retained for each stream, which can be used to roll a stream back. The
+// default is 10.
+func OptRevisionHistoryLimit(n int) func(*Type) {
+	return func(t *Type) {
+		t.historyLimit = n
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Errors specifically returned by a stream manager.
 var (
-	ErrStreamExists       = errors.New("stream already exists")
-	ErrStreamDoesNotExist = errors.New("stream does not exist")
+	ErrStreamExists          = errors.New("stream already exists")
+	ErrStreamDoesNotExist    = errors.New("stream does not exist")
+	ErrStreamVersionMismatch = errors.New("stream version does not match")
+	ErrRevisionDoesNotExist  = errors.New("stream revision does not exist")
 )
 
 //------------------------------------------------------------------------------
 
+// lockHistory obtains the write lock for a stream ID, which serialises
+// modifications to the stream so that version checks are atomic with the
+// change that follows them. The history of a stream ID is only created when
+// create is true, otherwise ErrStreamDoesNotExist is returned for IDs that
+// have never been created.
+func (m *Type) lockHistory(id string, create bool) (*streamHistory, error) {
+	for {
+		m.lock.Lock()
+		if m.closed {
+			m.lock.Unlock()
+			return nil, component.ErrTypeClosed
+		}
+		h, exists := m.history[id]
+		if !exists {
+			if !create {
+				m.lock.Unlock()
+				return nil, ErrStreamDoesNotExist
+			}
+			h = &streamHistory{}
+			m.history[id] = h
+		}
+		m.lock.Unlock()
+
+		h.writeLock.Lock()
+
+		// The history may have been removed by a failed create whilst we were
+		// waiting for the lock, in which case we try again.
+		m.lock.Lock()
+		current := m.history[id] == h
+		m.lock.Unlock()
+		if current {
+			return h, nil
+		}
+		h.writeLock.Unlock()
+	}
+}
+
+func (m *Type) checkVersion(id string, version int) (*StreamStatus, error) {
+	m.lock.Lock()
+	wrapper, exists := m.streams[id]
+	closed := m.closed
+	m.lock.Unlock()
+
+	if closed {
+		return nil, component.ErrTypeClosed
+	}
+	if !exists {
+		return nil, ErrStreamDoesNotExist
+	}
+	if version > 0 && wrapper.version != version {
+		return nil, ErrStreamVersionMismatch
+	}
+	return wrapper, nil
+}
+
+//------------------------------------------------------------------------------
+
 // Create attempts to construct and run a new stream under a unique ID. If the
 // ID already exists an error is returned.
 func (m *Type) Create(id string, conf stream.Config) error {
+	h, err := m.lockHistory(id, true)
+	if err != nil {
+		return err
+	}
+	defer h.writeLock.Unlock()
+
+	if err = m.create(h, id, conf); err != nil && h.latest == 0 {
+		// Do not retain the history of streams that were never created.
+		m.lock.Lock()
+		delete(m.history, id)
+		m.lock.Unlock()
+	}
+	return err
+}
+
+func (m *Type) create(h *streamHistory, id string, conf stream.Config) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -145,6 +264,10 @@ func (m *Type) Create(id string, conf stream.Config) error {
 		return ErrStreamExists
 	}
 
+	if err := m.checkResourceDependencies(conf); err != nil {
+		return err
+	}
+
 	strmFlatMetrics := metrics.NewLocal()
 	sMgr := m.manager.ForStream(id).WithAddedMetrics(strmFlatMetrics)
 
@@ -157,10 +280,73 @@ func (m *Type) Create(id string, conf stream.Config) error {
 	}
 
 	wrapper = NewStreamStatus(conf, strm, sMgr.Logger(), strmFlatMetrics)
+
+	h.latest++
+	wrapper.version = h.latest
+	h.revisions = append(h.revisions, Revision{
+		Version:   wrapper.version,
+		Config:    conf,
+		CreatedAt: wrapper.createdAt,
+	})
+	if m.historyLimit > 0 && len(h.revisions) > m.historyLimit {
+		h.revisions = h.revisions[len(h.revisions)-m.historyLimit:]
+	}
+
 	m.streams[id] = wrapper
 	return nil
 }
 
+// probeResource returns true if a resource of a given type and label exists.
+func (m *Type) probeResource(t docs.Type, label string) bool {
+	switch t {
+	case docs.TypeCache:
+		return m.manager.ProbeCache(label)
+	case docs.TypeInput:
+		return m.manager.ProbeInput(label)
+	case docs.TypeOutput:
+		return m.manager.ProbeOutput(label)
+	case docs.TypeProcessor:
+		return m.manager.ProbeProcessor(label)
+	case docs.TypeRateLimit:
+		return m.manager.ProbeRateLimit(label)
+	}
+	return false
+}
+
+// checkResourceDependencies returns an error if a stream config depends on a
+// resource that does not exist.
+func (m *Type) checkResourceDependencies(conf stream.Config) error {
+	for _, dep := range conf.DependsOn {
+		t, label, isResource := ResourceDependency(dep)
+		if !isResource {
+			continue
+		}
+		if !m.probeResource(t, label) {
+			return fmt.Errorf("stream depends on %v resource '%v', which does not exist", t, label)
+		}
+	}
+	return nil
+}
+
+// resourceDependents returns the IDs of active streams that depend on a
+// resource.
+func (m *Type) resourceDependents(t docs.Type, label string) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var ids []string
+	for id, wrapper := range m.streams {
+		for _, dep := range wrapper.config.DependsOn {
+			if depT, depLabel, isResource := ResourceDependency(dep); isResource && depT == t && depLabel == label {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // Read attempts to obtain the status of a managed stream. Returns an error if
 // the stream does not exist.
 func (m *Type) Read(id string) (*StreamStatus, error) {
@@ -182,32 +368,117 @@ func (m *Type) Read(id string) (*StreamStatus, error) {
 // Update attempts to stop an existing stream and replace it with a new version
 // of the same stream.
 func (m *Type) Update(id string, conf stream.Config, timeout time.Duration) error {
-	m.lock.Lock()
-	wrapper, exists := m.streams[id]
-	closed := m.closed
-	m.lock.Unlock()
+	return m.UpdateAtVersion(id, 0, conf, timeout)
+}
 
-	if closed {
-		return component.ErrTypeClosed
+// UpdateAtVersion attempts to stop an existing stream and replace it with a new
+// version of the same stream, but only if the current version of the stream
+// matches the version provided. If the version is zero the check is skipped.
+func (m *Type) UpdateAtVersion(id string, version int, conf stream.Config, timeout time.Duration) error {
+	h, err := m.lockHistory(id, false)
+	if err != nil {
+		return err
 	}
-	if !exists {
-		return ErrStreamDoesNotExist
+	defer h.writeLock.Unlock()
+
+	wrapper, err := m.checkVersion(id, version)
+	if err != nil {
+		return err
 	}
 
 	if reflect.DeepEqual(wrapper.config, conf) {
 		return nil
 	}
 
-	if err := m.Delete(id, timeout); err != nil {
+	if err := m.delete(id, timeout); err != nil {
+		return err
+	}
+	return m.create(h, id, conf)
+}
+
+// Revisions returns the retained config revisions of a stream, ordered from
+// oldest to newest.
+func (m *Type) Revisions(id string) ([]Revision, error) {
+	h, err := m.lockHistory(id, false)
+	if err != nil {
+		return nil, err
+	}
+	defer h.writeLock.Unlock()
+
+	if _, err := m.checkVersion(id, 0); err != nil {
+		return nil, err
+	}
+
+	revs := make([]Revision, len(h.revisions))
+	copy(revs, h.revisions)
+	return revs, nil
+}
+
+// Rollback attempts to replace an existing stream with the config of a past
+// revision, which results in a new revision of the stream. If the version
+// provided is non-zero the rollback only happens when the current version of
+// the stream matches it.
+func (m *Type) Rollback(id string, version, toVersion int, timeout time.Duration) error {
+	h, err := m.lockHistory(id, false)
+	if err != nil {
+		return err
+	}
+	defer h.writeLock.Unlock()
+
+	wrapper, err := m.checkVersion(id, version)
+	if err != nil {
+		return err
+	}
+
+	var target *Revision
+	for i := range h.revisions {
+		if h.revisions[i].Version == toVersion {
+			target = &h.revisions[i]
+			break
+		}
+	}
+	if target == nil {
+		return ErrRevisionDoesNotExist
+	}
+	if wrapper.version == toVersion {
+		return nil
+	}
+
+	conf := target.Config
+	if err := m.delete(id, timeout); err != nil {
 		return err
 	}
-	return m.Create(id, conf)
+	return m.create(h, id, conf)
 }
 
 // Delete attempts to stop and remove a stream by its ID. Returns an error if
 // the stream was not found, or if clean shutdown fails in the specified period
 // of time.
 func (m *Type) Delete(id string, timeout time.Duration) error {
+	return m.DeleteAtVersion(id, 0, timeout)
+}
+
+// DeleteAtVersion attempts to stop and remove a stream by its ID, but only if
+// the current version of the stream matches the version provided. If the
+// version is zero the check is skipped.
+func (m *Type) DeleteAtVersion(id string, version int, timeout time.Duration) error {
+	h, err := m.lockHistory(id, false)
+	if err != nil {
+		return err
+	}
+	defer h.writeLock.Unlock()
+
+	if _, err := m.checkVersion(id, version); err != nil {
+		return err
+	}
+	if err := m.delete(id, timeout); err != nil {
+		return err
+	}
+	h.revisions = nil
+	return nil
+}
+
+func (m *Type) delete(id string, timeout time.Duration) error {
 	m.lock.Lock()
 	if m.closed {
 		m.lock.Unlock()
@@ -233,38 +504,105 @@ func (m *Type) Delete(id string, timeout time.Duration) error {
 
 //------------------------------------------------------------------------------
 
-// Stop attempts to gracefully shut down all active streams and close the
-// stream manager.
-func (m *Type) Stop(timeout time.Duration) error {
-	m.lock.Lock()
-	defer m.lock.Unlock()
+// stopInOrder stops all active streams tier by tier, where streams are stopped
+// before the streams that they depend on, and returns the IDs of streams that
+// failed to stop. If the timeout elapses before all tiers have been stopped
+// then the remaining tiers are given no time to stop gracefully, and
+// component.ErrTimeout is returned. The lock must be held by the caller.
+func (m *Type) stopInOrder(timeout time.Duration, stopFn func(id string, strm *StreamStatus, timeout time.Duration) error) ([]string, error) {
+	confs := make(map[string]stream.Config, len(m.streams))
+	for k, v := range m.streams {
+		confs[k] = v.config
+	}
 
-	resultChan := make(chan string)
+	tiers, err := StartupOrder(confs)
+	if err != nil {
+		m.manager.Logger().Warnf("Stopping all streams at once: %v\n", err)
+		tiers = [][]string{{}}
+		for k := range confs {
+			tiers[0] = append(tiers[0], k)
+		}
+	}
 
-	for k, v := range m.streams {
-		go func(id string, strm *StreamStatus) {
-			if err := strm.strm.Stop(timeout); err != nil {
-				resultChan <- id
-			} else {
-				resultChan <- ""
+	deadline := time.Now().Add(timeout)
+	failedStreams := []string{}
+	var timedOut bool
+	for i := len(tiers) - 1; i >= 0; i-- {
+		resultChan := make(chan string)
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			remaining = 0
+			timedOut = true
+		}
+		for _, id := range tiers[i] {
+			go func(id string, strm *StreamStatus) {
+				if err := stopFn(id, strm, remaining); err != nil {
+					resultChan <- id
+				} else {
+					resultChan <- ""
+				}
+			}(id, m.streams[id])
+		}
+		for range tiers[i] {
+			if failedStrm := <-resultChan; len(failedStrm) > 0 {
+				failedStreams = append(failedStreams, failedStrm)
 			}
-		}(k, v)
+		}
+	}
+	sort.Strings(failedStreams)
+	if timedOut {
+		return failedStreams, component.ErrTimeout
 	}
+	return failedStreams, nil
+}
 
-	failedStreams := []string{}
-	for i := 0; i < len(m.streams); i++ {
-		if failedStrm := <-resultChan; len(failedStrm) > 0 {
-			failedStreams = append(failedStreams, failedStrm)
+// Drain stops the inputs of all active streams and waits for their buffered
+// and in-flight data to be flushed through to their outputs, then closes the
+// stream manager. Unlike Stop there is no less graceful fallback when the
+// timeout is exceeded. The progress func is called with the ID of a stream and
+// the name of the layer of the stream being drained.
+func (m *Type) Drain(timeout time.Duration, progress func(id, layer string)) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	failedStreams, err := m.stopInOrder(timeout, func(id string, strm *StreamStatus, timeout time.Duration) error {
+		return strm.strm.Drain(timeout, func(layer string) {
+			progress(id, layer)
+		})
+	})
+
+	m.streams = map[string]*StreamStatus{}
+	m.closed = true
+
+	if len(failedStreams) > 0 {
+		if err != nil {
+			return fmt.Errorf("failed to drain the following streams: %v: %w", failedStreams, err)
 		}
+		return fmt.Errorf("failed to drain the following streams: %v", failedStreams)
 	}
+	return err
+}
+
+// Stop attempts to gracefully shut down all active streams and close the
+// stream manager. Streams are stopped before the streams that they depend on.
+func (m *Type) Stop(timeout time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	failedStreams, err := m.stopInOrder(timeout, func(id string, strm *StreamStatus, timeout time.Duration) error {
+		return strm.strm.Stop(timeout)
+	})
 
 	m.streams = map[string]*StreamStatus{}
 	m.closed = true
 
 	if len(failedStreams) > 0 {
+		if err != nil {
+			return fmt.Errorf("failed to gracefully stop the following streams: %v: %w", failedStreams, err)
+		}
 		return fmt.Errorf("failed to gracefully stop the following streams: %v", failedStreams)
 	}
-	return nil
+	return err
 }
 
 //------------------------------------------------------------------------------
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -102,3 +103,63 @@ func TestTypeBasicClose(t *testing.T) {
 		t.Errorf("Unexpected error: %v != %v", act, exp)
 	}
 }
+
+func TestTypeStopInOrderTimeout(t *testing.T) {
+	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := New(res, OptAPIEnabled(false))
+
+	downstream := harmlessConf()
+	upstream := harmlessConf()
+	upstream.DependsOn = []string{"downstream"}
+	mgr.streams["downstream"] = &StreamStatus{config: downstream}
+	mgr.streams["upstream"] = &StreamStatus{config: upstream}
+
+	var mut sync.Mutex
+	timeouts := map[string]time.Duration{}
+	failed, err := mgr.stopInOrder(time.Millisecond*10, func(id string, strm *StreamStatus, timeout time.Duration) error {
+		mut.Lock()
+		timeouts[id] = timeout
+		mut.Unlock()
+		if id == "upstream" {
+			<-time.After(time.Millisecond * 50)
+			return component.ErrTimeout
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, component.ErrTimeout)
+	require.Equal(t, []string{"upstream"}, failed)
+	require.Equal(t, time.Duration(0), timeouts["downstream"])
+	require.Greater(t, int64(timeouts["upstream"]), int64(0))
+}
+
+func TestTypeHistoryUnknownStreams(t *testing.T) {
+	res, err := bmanager.New(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := New(res, OptAPIEnabled(false))
+
+	require.Equal(t, ErrStreamDoesNotExist, mgr.Update("foo", harmlessConf(), time.Second))
+	require.Equal(t, ErrStreamDoesNotExist, mgr.Rollback("foo", 0, 1, time.Second))
+	require.Equal(t, ErrStreamDoesNotExist, mgr.Delete("foo", time.Second))
+	_, err = mgr.Revisions("foo")
+	require.Equal(t, ErrStreamDoesNotExist, err)
+
+	badConf := harmlessConf()
+	badConf.Input.Type = "not_a_real_input"
+	require.Error(t, mgr.Create("bar", badConf))
+
+	require.Empty(t, mgr.history)
+
+	require.NoError(t, mgr.Create("foo", harmlessConf()))
+	require.NoError(t, mgr.Delete("foo", time.Second))
+	require.Equal(t, ErrStreamDoesNotExist, mgr.Update("foo", harmlessConf(), time.Second))
+
+	require.NoError(t, mgr.Create("foo", harmlessConf()))
+	info, err := mgr.Read("foo")
+	require.NoError(t, err)
+	require.Equal(t, 2, info.Version())
+
+	require.NoError(t, mgr.Stop(time.Second))
+}
@@ -2,6 +2,8 @@ package stream
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"net/http"
 	"runtime/pprof"
 	"time"
@@ -25,6 +27,7 @@ type Type struct {
 	bufferLayer   ibuffer.Streamed
 	pipelineLayer pipeline.Type
 	outputLayer   ioutput.Streamed
+	limiter       *inFlightLimiter
 
 	manager bundle.NewManagement
 
@@ -57,15 +60,24 @@ func New(conf Config, mgr bundle.NewManagement, opts ...func(*Type)) (*Type, err
 			w.WriteHeader(http.StatusServiceUnavailable)
 			_, _ = w.Write([]byte("output not connected\n"))
 		}
+		_ = t.manager.Lifecycle().CheckHealth(r.Context(), func(name string, err error) {
+			if connected {
+				connected = false
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			_, _ = fmt.Fprintf(w, "%v unhealthy: %v\n", name, err)
+		})
 		if connected {
 			_, _ = w.Write([]byte("OK"))
 		}
 	}
 	t.manager.RegisterEndpoint(
 		"/ready",
-		"Returns 200 OK if all inputs and outputs are connected, otherwise a 503 is returned.",
+		"Returns 200 OK if all inputs and outputs are connected and all components report as healthy, otherwise a 503 is returned.",
 		healthCheck,
 	)
+
+	t.manager.Lifecycle().PostStart(context.Background())
 	return t, nil
 }
 
@@ -81,9 +93,12 @@ func OptOnClose(onClose func()) func(*Type) {
 //------------------------------------------------------------------------------
 
 // IsReady returns a boolean indicating whether both the input and output layers
-// of the stream are connected.
+// of the stream are connected, and all components report as healthy.
 func (t *Type) IsReady() bool {
-	return t.inputLayer.Connected() && t.outputLayer.Connected()
+	if !t.inputLayer.Connected() || !t.outputLayer.Connected() {
+		return false
+	}
+	return t.manager.Lifecycle().CheckHealth(context.Background(), func(string, error) {})
 }
 
 func (t *Type) start() (err error) {
@@ -113,6 +128,10 @@ func (t *Type) start() (err error) {
 	var nextTranChan <-chan message.Transaction
 
 	nextTranChan = t.inputLayer.TransactionChan()
+	if t.conf.Limits.MaxInFlight > 0 || t.conf.Limits.MaxInFlightBytes > 0 {
+		t.limiter = newInFlightLimiter(t.conf.Limits)
+		nextTranChan = t.limiter.consume(nextTranChan)
+	}
 	if t.bufferLayer != nil {
 		if err = t.bufferLayer.Consume(nextTranChan); err != nil {
 			return
@@ -132,6 +151,7 @@ func (t *Type) start() (err error) {
 	go func(out ioutput.Streamed) {
 		for {
 			if err := out.WaitForClose(time.Second); err == nil {
+				t.manager.Lifecycle().PostClose(context.Background())
 				t.onClose()
 				return
 			}
@@ -146,6 +166,17 @@ func (t *Type) start() (err error) {
 // proxy. This should guarantee that all in-flight and buffered data is resolved
 // before shutting down.
 func (t *Type) StopGracefully(timeout time.Duration) (err error) {
+	return t.Drain(timeout, func(string) {})
+}
+
+// Drain behaves the same as StopGracefully, where the input layer is closed
+// and all buffered and in-flight data is flushed through to the outputs before
+// the remaining layers terminate. The progress func is called with the name of
+// each layer as the drain begins waiting on it.
+func (t *Type) Drain(timeout time.Duration, progress func(layer string)) (err error) {
+	t.manager.Lifecycle().PreDrain(context.Background())
+
+	progress("input")
 	t.inputLayer.CloseAsync()
 	started := time.Now()
 	if err = t.inputLayer.WaitForClose(timeout); err != nil {
@@ -157,6 +188,7 @@ func (t *Type) StopGracefully(timeout time.Duration) (err error) {
 	// If we have a buffer then wait right here. We want to try and allow the
 	// buffer to empty out before prompting the other layers to shut down.
 	if t.bufferLayer != nil {
+		progress("buffer")
 		t.bufferLayer.StopConsuming()
 		remaining = timeout - time.Since(started)
 		if remaining < 0 {
@@ -169,6 +201,7 @@ func (t *Type) StopGracefully(timeout time.Duration) (err error) {
 
 	// After this point we can start closing the remaining components.
 	if t.pipelineLayer != nil {
+		progress("pipeline")
 		t.pipelineLayer.CloseAsync()
 		remaining = timeout - time.Since(started)
 		if remaining < 0 {
@@ -179,6 +212,7 @@ func (t *Type) StopGracefully(timeout time.Duration) (err error) {
 		}
 	}
 
+	progress("output")
 	t.outputLayer.CloseAsync()
 	remaining = timeout - time.Since(started)
 	if remaining < 0 {
@@ -243,6 +277,9 @@ func (t *Type) StopOrdered(timeout time.Duration) (err error) {
 // should only be attempted if both stopGracefully and stopOrdered failed.
 func (t *Type) StopUnordered(timeout time.Duration) (err error) {
 	t.inputLayer.CloseAsync()
+	if t.limiter != nil {
+		t.limiter.close()
+	}
 	if t.bufferLayer != nil {
 		t.bufferLayer.CloseAsync()
 	}
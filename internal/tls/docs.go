@@ -15,6 +15,10 @@ func FieldSpec() docs.FieldSpec {
 			"skip_cert_verify", "Whether to skip server side certificate verification.",
 		).HasDefault(false),
 
+		docs.FieldString(
+			"server_name", "An optional server name to verify the certificates of servers against and to send with SNI, which may also be an IP address. When empty the host of the server that is connected to is used.", "example.com", "10.0.0.1",
+		).HasDefault("").Advanced().AtVersion("4.2.0"),
+
 		docs.FieldBool(
 			"enable_renegotiation", "Whether to allow the remote server to repeatedly request renegotiation. Enable this option if you're seeing the error message `local error: tls: no renegotiation`.",
 		).AtVersion("3.45.0").Advanced().HasDefault(false),
@@ -47,5 +51,9 @@ func FieldSpec() docs.FieldSpec {
 			docs.FieldString("cert_file", "The path to a certificate to use.").HasDefault(""),
 			docs.FieldString("key_file", "The path of a certificate key to use.").HasDefault(""),
 		),
+
+		docs.FieldString(
+			"reload_interval", "An optional interval at which the files of root certificate authorities and client certificates are checked for modifications, where modified files are reloaded without restarting. Files are only checked when a connection is established, and therefore existing connections continue to use the previous certificates. This is useful when certificates are rotated frequently, for example by cert-manager. When root certificate authorities are reloaded the hostname of a server is verified against the `server_name` when set, and otherwise against the server name sent in the handshake, which is never an IP address. Therefore connections to servers addressed by an IP address are rejected unless the address is set as the `server_name`, or `skip_cert_verify` is enabled.", "1m", "10s",
+		).HasDefault("").Advanced().AtVersion("4.2.0"),
 	).Advanced()
 }
@@ -0,0 +1,157 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileReloader holds client certificates and root certificate authorities that
+// are loaded from files, and reloads them when the files are modified. Files
+// are checked at most once per interval, and only during TLS handshakes, which
+// means a reloader does not need to be closed.
+type fileReloader struct {
+	conf     *Config
+	interval time.Duration
+
+	mut       sync.Mutex
+	lastCheck time.Time
+	modTimes  map[string]time.Time
+	certs     []tls.Certificate
+	rootCAs   *x509.CertPool
+}
+
+func newFileReloader(conf *Config, interval time.Duration) (*fileReloader, error) {
+	r := &fileReloader{
+		conf:     conf,
+		interval: interval,
+		modTimes: map[string]time.Time{},
+	}
+	for _, path := range conf.watchedFiles() {
+		r.modTimes[path] = modTime(path)
+	}
+
+	var err error
+	if r.certs, r.rootCAs, err = r.load(); err != nil {
+		return nil, err
+	}
+	r.lastCheck = time.Now()
+	return r, nil
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (r *fileReloader) load() (certs []tls.Certificate, rootCAs *x509.CertPool, err error) {
+	for _, conf := range r.conf.ClientCertificates {
+		cert, err := conf.Load()
+		if err != nil {
+			return nil, nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if r.conf.RootCAsFile != "" {
+		caCert, err := os.ReadFile(r.conf.RootCAsFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		rootCAs = x509.NewCertPool()
+		rootCAs.AppendCertsFromPEM(caCert)
+	}
+	return
+}
+
+// current returns the latest certificates and root certificate authorities,
+// reloading them first if the interval has passed and any of the files have
+// been modified. If a reload fails, for example because a file is only
+// partially written, the previous values are kept and the reload is attempted
+// again after the next interval.
+func (r *fileReloader) current() ([]tls.Certificate, *x509.CertPool) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if time.Since(r.lastCheck) < r.interval {
+		return r.certs, r.rootCAs
+	}
+	r.lastCheck = time.Now()
+
+	newModTimes := make(map[string]time.Time, len(r.modTimes))
+	changed := false
+	for path, prev := range r.modTimes {
+		newModTimes[path] = modTime(path)
+		if !newModTimes[path].Equal(prev) {
+			changed = true
+		}
+	}
+	if !changed {
+		return r.certs, r.rootCAs
+	}
+
+	certs, rootCAs, err := r.load()
+	if err != nil {
+		return r.certs, r.rootCAs
+	}
+	r.certs, r.rootCAs, r.modTimes = certs, rootCAs, newModTimes
+	return r.certs, r.rootCAs
+}
+
+func (r *fileReloader) getClientCertificate(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	certs, _ := r.current()
+	for i := range certs {
+		if err := cri.SupportsCertificate(&certs[i]); err == nil {
+			return &certs[i], nil
+		}
+	}
+	if len(certs) > 0 {
+		return &certs[0], nil
+	}
+	// No certificate is sent.
+	return &tls.Certificate{}, nil
+}
+
+// verifyConnection returns a function that verifies the certificate chain
+// presented by a server against the latest root certificate authorities, which
+// replaces the standard verification as the root certificate authorities of a
+// tls.Config cannot be changed once it is in use.
+//
+// Since the standard verification is disabled the hostname of the certificate
+// is verified here, against the configured server name when one is set and
+// otherwise against the server name sent with SNI. IP addresses are never sent
+// with SNI, and so a server that is dialled by its IP address can only be
+// verified when the address is configured as the server name. Connections
+// without any server name are rejected, as the hostname of the certificate
+// would otherwise not be verified at all.
+func (r *fileReloader) verifyConnection(serverName string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		name := serverName
+		if name == "" {
+			name = cs.ServerName
+		}
+		if name == "" {
+			return errors.New("a server name must be specified in order to verify the certificate hostname, set server_name when connecting to an IP address with reloaded root certificate authorities")
+		}
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no certificates presented by peer")
+		}
+
+		_, rootCAs := r.current()
+		opts := x509.VerifyOptions{
+			DNSName:       name,
+			Roots:         rootCAs,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+}
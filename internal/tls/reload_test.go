@@ -0,0 +1,251 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestCert(t *testing.T, certPath, keyPath, commonName string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certPath, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyPath, modTime, modTime))
+}
+
+func clientCertCommonName(t *testing.T, tlsConf *tls.Config) string {
+	t.Helper()
+
+	cert, err := tlsConf.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	require.NotEmpty(t, cert.Certificate)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestConfigReloadClientCerts(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	startTime := time.Now().Add(-time.Hour)
+	writeTestCert(t, certPath, keyPath, "first", startTime)
+
+	conf := NewConfig()
+	conf.ClientCertificates = []ClientCertConfig{
+		{CertFile: certPath, KeyFile: keyPath},
+	}
+	conf.RootCAsFile = certPath
+	conf.ReloadInterval = "1ms"
+
+	tlsConf, err := conf.Get()
+	require.NoError(t, err)
+
+	assert.Empty(t, tlsConf.Certificates)
+	assert.True(t, tlsConf.InsecureSkipVerify)
+	assert.NotNil(t, tlsConf.VerifyConnection)
+	assert.Equal(t, "first", clientCertCommonName(t, tlsConf))
+
+	writeTestCert(t, certPath, keyPath, "second", startTime.Add(time.Minute))
+	<-time.After(time.Millisecond * 5)
+
+	assert.Equal(t, "second", clientCertCommonName(t, tlsConf))
+
+	// A partially written file is ignored until it is complete.
+	require.NoError(t, os.WriteFile(keyPath, []byte("nope"), 0o600))
+	<-time.After(time.Millisecond * 5)
+
+	assert.Equal(t, "second", clientCertCommonName(t, tlsConf))
+
+	writeTestCert(t, certPath, keyPath, "third", startTime.Add(time.Minute*2))
+	<-time.After(time.Millisecond * 5)
+
+	assert.Equal(t, "third", clientCertCommonName(t, tlsConf))
+}
+
+func TestConfigNoReload(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, certPath, keyPath, "first", time.Now())
+
+	conf := NewConfig()
+	conf.ClientCertificates = []ClientCertConfig{
+		{CertFile: certPath, KeyFile: keyPath},
+	}
+	conf.RootCAsFile = certPath
+
+	tlsConf, err := conf.Get()
+	require.NoError(t, err)
+
+	assert.Len(t, tlsConf.Certificates, 1)
+	assert.Nil(t, tlsConf.GetClientCertificate)
+	assert.False(t, tlsConf.InsecureSkipVerify)
+	assert.NotNil(t, tlsConf.RootCAs)
+
+	conf.ReloadInterval = "nope"
+	_, err = conf.Get()
+	require.Error(t, err)
+}
+
+func writeTestCA(t *testing.T, caPath string, modTime time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600))
+	require.NoError(t, os.Chtimes(caPath, modTime, modTime))
+	return cert, key
+}
+
+func testServerCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	return tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  key,
+	}
+}
+
+func TestConfigReloadRootCAsVerify(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+
+	startTime := time.Now().Add(-time.Hour)
+	ca, caKey := writeTestCA(t, caPath, startTime)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{testServerCert(t, ca, caKey)},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ln.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	conf := NewConfig()
+	conf.RootCAsFile = caPath
+	conf.ReloadInterval = "1ms"
+
+	tlsConf, err := conf.Get()
+	require.NoError(t, err)
+
+	dial := func(serverName string) error {
+		c := tlsConf.Clone()
+		c.ServerName = serverName
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		return tls.Client(conn, c).Handshake()
+	}
+
+	require.NoError(t, dial("localhost"))
+	require.Error(t, dial("example.com"))
+
+	// Without a server name the hostname cannot be verified.
+	require.Error(t, dial(""))
+
+	// IP addresses are not sent with SNI and so the hostname cannot be
+	// verified either, even when the certificate lists the address.
+	require.Error(t, dial("127.0.0.1"))
+	require.Error(t, dial("127.0.0.2"))
+
+	_, err = tls.Dial("tcp", ln.Addr().String(), tlsConf)
+	require.Error(t, err)
+
+	// A configured server name is used to verify the certificate, which can
+	// be an IP address.
+	conf.ServerName = "127.0.0.1"
+	ipConf, err := conf.Get()
+	require.NoError(t, err)
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), ipConf)
+	require.NoError(t, err)
+	conn.Close()
+
+	conf.ServerName = "127.0.0.2"
+	ipConf, err = conf.Get()
+	require.NoError(t, err)
+
+	_, err = tls.Dial("tcp", ln.Addr().String(), ipConf)
+	require.Error(t, err)
+
+	// Replacing the root certificate authority means the server is no longer
+	// trusted.
+	writeTestCA(t, caPath, startTime.Add(time.Minute))
+	<-time.After(time.Millisecond * 5)
+
+	require.Error(t, dial("localhost"))
+}
@@ -4,7 +4,9 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"os"
+	"time"
 )
 
 //------------------------------------------------------------------------------
@@ -44,8 +46,10 @@ type Config struct {
 	RootCAs             string             `json:"root_cas" yaml:"root_cas"`
 	RootCAsFile         string             `json:"root_cas_file" yaml:"root_cas_file"`
 	InsecureSkipVerify  bool               `json:"skip_cert_verify" yaml:"skip_cert_verify"`
+	ServerName          string             `json:"server_name" yaml:"server_name"`
 	ClientCertificates  []ClientCertConfig `json:"client_certs" yaml:"client_certs"`
 	EnableRenegotiation bool               `json:"enable_renegotiation" yaml:"enable_renegotiation"`
+	ReloadInterval      string             `json:"reload_interval" yaml:"reload_interval"`
 }
 
 // NewConfig creates a new Config with default values.
@@ -55,11 +59,30 @@ func NewConfig() Config {
 		RootCAs:             "",
 		RootCAsFile:         "",
 		InsecureSkipVerify:  false,
+		ServerName:          "",
 		ClientCertificates:  []ClientCertConfig{},
 		EnableRenegotiation: false,
+		ReloadInterval:      "",
 	}
 }
 
+// watchedFiles returns the paths of all files that certificates and root
+// certificate authorities are loaded from.
+func (c *Config) watchedFiles() (paths []string) {
+	if c.RootCAsFile != "" {
+		paths = append(paths, c.RootCAsFile)
+	}
+	for _, conf := range c.ClientCertificates {
+		if conf.CertFile != "" {
+			paths = append(paths, conf.CertFile)
+		}
+		if conf.KeyFile != "" {
+			paths = append(paths, conf.KeyFile)
+		}
+	}
+	return
+}
+
 //------------------------------------------------------------------------------
 
 // Get returns a valid *tls.Config based on the configuration values of Config.
@@ -79,14 +102,39 @@ func (c *Config) Get() (*tls.Config, error) {
 		return nil, errors.New("only one field between root_cas and root_cas_file can be specified")
 	}
 
-	if len(c.RootCAsFile) > 0 {
-		caCert, err := os.ReadFile(c.RootCAsFile)
-		if err != nil {
+	var reloadInterval time.Duration
+	if c.ReloadInterval != "" {
+		var err error
+		if reloadInterval, err = time.ParseDuration(c.ReloadInterval); err != nil {
+			return nil, fmt.Errorf("failed to parse reload_interval: %w", err)
+		}
+	}
+
+	var reloader *fileReloader
+	if reloadInterval > 0 && len(c.watchedFiles()) > 0 {
+		var err error
+		if reloader, err = newFileReloader(c, reloadInterval); err != nil {
 			return nil, err
 		}
+	}
+
+	if len(c.RootCAsFile) > 0 {
 		initConf()
-		tlsConf.RootCAs = x509.NewCertPool()
-		tlsConf.RootCAs.AppendCertsFromPEM(caCert)
+		if reloader != nil {
+			if !c.InsecureSkipVerify {
+				// Standard verification is replaced as it would otherwise
+				// only use the root certificate authorities loaded initially.
+				tlsConf.InsecureSkipVerify = true
+				tlsConf.VerifyConnection = reloader.verifyConnection(c.ServerName)
+			}
+		} else {
+			caCert, err := os.ReadFile(c.RootCAsFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConf.RootCAs = x509.NewCertPool()
+			tlsConf.RootCAs.AppendCertsFromPEM(caCert)
+		}
 	}
 
 	if len(c.RootCAs) > 0 {
@@ -95,13 +143,23 @@ func (c *Config) Get() (*tls.Config, error) {
 		tlsConf.RootCAs.AppendCertsFromPEM([]byte(c.RootCAs))
 	}
 
-	for _, conf := range c.ClientCertificates {
-		cert, err := conf.Load()
-		if err != nil {
-			return nil, err
+	if reloader != nil && len(c.ClientCertificates) > 0 {
+		initConf()
+		tlsConf.GetClientCertificate = reloader.getClientCertificate
+	} else {
+		for _, conf := range c.ClientCertificates {
+			cert, err := conf.Load()
+			if err != nil {
+				return nil, err
+			}
+			initConf()
+			tlsConf.Certificates = append(tlsConf.Certificates, cert)
 		}
+	}
+
+	if c.ServerName != "" {
 		initConf()
-		tlsConf.Certificates = append(tlsConf.Certificates, cert)
+		tlsConf.ServerName = c.ServerName
 	}
 
 	if c.EnableRenegotiation {
@@ -0,0 +1,37 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// ReplyFunc sends a batch of response messages back to the origin of a
+// request, such as a reply subject, topic or queue.
+type ReplyFunc func(ctx context.Context, responses *message.Batch) error
+
+// WithReplies adds a ResultStore to the context of a message batch and returns
+// a wrapped version of an acknowledgement function. When the batch is
+// successfully delivered any responses that were stored, usually by a
+// sync_response output, are sent with the reply function before the
+// acknowledgement function is called.
+//
+// A failure to send a response is passed to the acknowledgement function as a
+// delivery error, which allows inputs to redeliver requests that were not
+// replied to. This allows inputs that receive requests carrying a reply
+// address and correlation ID to respond to them with the result of a pipeline,
+// similar to the http_server input.
+func WithReplies(msg *message.Batch, replyFn ReplyFunc, ackFn func(context.Context, error) error) func(context.Context, error) error {
+	store := NewResultStore()
+	AddResultStore(msg, store)
+	return func(ctx context.Context, err error) error {
+		if err == nil {
+			for _, res := range store.Get() {
+				if err = replyFn(ctx, res); err != nil {
+					break
+				}
+			}
+		}
+		return ackFn(ctx, err)
+	}
+}
@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestWithReplies(t *testing.T) {
+	ctx := context.Background()
+
+	var replies []string
+	var replyErr error
+	replyFn := func(ctx context.Context, res *message.Batch) error {
+		if replyErr != nil {
+			return replyErr
+		}
+		replies = append(replies, string(res.Get(0).Get()))
+		return nil
+	}
+
+	var ackErrs []error
+	ackFn := func(ctx context.Context, err error) error {
+		ackErrs = append(ackErrs, err)
+		return nil
+	}
+
+	msg := message.QuickBatch([][]byte{[]byte("request")})
+	wrappedAck := WithReplies(msg, replyFn, ackFn)
+
+	resMsg := msg.Copy()
+	resMsg.Get(0).Set([]byte("response"))
+	require.NoError(t, SetAsResponse(resMsg))
+
+	require.NoError(t, wrappedAck(ctx, nil))
+	assert.Equal(t, []string{"response"}, replies)
+	assert.Equal(t, []error{nil}, ackErrs)
+
+	// Responses are not sent for failed deliveries.
+	replies, ackErrs = nil, nil
+	require.NoError(t, wrappedAck(ctx, errors.New("nope")))
+	assert.Empty(t, replies)
+	assert.Equal(t, []error{errors.New("nope")}, ackErrs)
+
+	// Failed replies are passed to the acknowledgement.
+	replyErr = errors.New("reply failed")
+	ackErrs = nil
+	require.NoError(t, wrappedAck(ctx, nil))
+	assert.Equal(t, []error{replyErr}, ackErrs)
+
+	// Nothing is sent when there are no responses.
+	replyErr, replies, ackErrs = nil, nil, nil
+	require.NoError(t, WithReplies(message.QuickBatch([][]byte{[]byte("foo")}), replyFn, ackFn)(ctx, nil))
+	assert.Empty(t, replies)
+	assert.Equal(t, []error{nil}, ackErrs)
+}
@@ -14,8 +14,10 @@ import (
 	_ "github.com/benthosdev/benthos/v4/internal/impl/cassandra"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/confluent"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/dgraph"
+	_ "github.com/benthosdev/benthos/v4/internal/impl/dns"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/elasticsearch"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/gcp"
+	_ "github.com/benthosdev/benthos/v4/internal/impl/grpc"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/hdfs"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/influxdb"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/io"
@@ -38,6 +40,7 @@ import (
 	_ "github.com/benthosdev/benthos/v4/internal/impl/snowflake"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/sql"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/statsd"
+	_ "github.com/benthosdev/benthos/v4/internal/impl/syslog"
 	_ "github.com/benthosdev/benthos/v4/internal/impl/xml"
 	"github.com/benthosdev/benthos/v4/internal/template"
 
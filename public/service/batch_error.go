@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// BatchError is an error type that can be returned by batched outputs in order
+// to describe which messages of a batch failed, allowing Benthos to retry or
+// reroute only the messages that failed rather than the entire batch.
+type BatchError struct {
+	err        error
+	batch      MessageBatch
+	partErrors map[int]error
+}
+
+// NewBatchError creates a new batch-wide error, where it's possible to add
+// granular errors for individual messages of the batch with Failed. The batch
+// provided should be the batch that was passed to the output.
+func NewBatchError(b MessageBatch, headline error) *BatchError {
+	if bErr, ok := headline.(*BatchError); ok {
+		headline = bErr.err
+	}
+	return &BatchError{
+		err:   headline,
+		batch: b,
+	}
+}
+
+// Failed stores an error state for a particular message of the batch, where i
+// is the index of the message within the batch. Returns a pointer to the
+// underlying error, allowing this method to be chained.
+//
+// If Failed is not called then all messages are assumed to have failed. If it
+// is called at least once then all message indexes that aren't explicitly
+// failed are assumed to have been processed successfully.
+func (err *BatchError) Failed(i int, merr error) *BatchError {
+	if err.partErrors == nil {
+		err.partErrors = make(map[int]error)
+	}
+	err.partErrors[i] = merr
+	return err
+}
+
+// IndexedErrors returns the number of indexed errors that have been registered
+// for the batch.
+func (err *BatchError) IndexedErrors() int {
+	return len(err.partErrors)
+}
+
+// WalkMessages applies a closure to each message of the batch, where the
+// closure is provided the message index, the message, and its individual
+// error, which is nil if the message was processed successfully. The closure
+// returns a bool which indicates whether the iteration should be continued.
+func (err *BatchError) WalkMessages(fn func(int, *Message, error) bool) {
+	for i, m := range err.batch {
+		merr := err.err
+		if err.partErrors != nil {
+			merr = err.partErrors[i]
+		}
+		if !fn(i, m, merr) {
+			return
+		}
+	}
+}
+
+// Error implements the common error interface.
+func (err *BatchError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the underlying headline error.
+func (err *BatchError) Unwrap() error {
+	return err.err
+}
+
+// toInternalBatchError converts a batch error returned by a plugin into the
+// internal representation, where the indexes of the plugin batch match those
+// of the internal batch it was derived from.
+func toInternalBatchError(err error, msg *message.Batch) error {
+	var bErr *BatchError
+	if err == nil || !errors.As(err, &bErr) {
+		return err
+	}
+	iErr := batch.NewError(msg, bErr.err)
+	for i, merr := range bErr.partErrors {
+		iErr.Failed(i, merr)
+	}
+	return iErr
+}
+
+// fromInternalBatchError converts an internal batch error into a BatchError
+// where the indexes of the internal batch match those of the provided batch.
+func fromInternalBatchError(err error, b MessageBatch) error {
+	var iErr *batch.Error
+	if err == nil || !errors.As(err, &iErr) {
+		return err
+	}
+	bErr := NewBatchError(b, iErr.Unwrap())
+	if iErr.IndexedErrors() > 0 {
+		iErr.WalkParts(func(i int, _ *message.Part, merr error) bool {
+			bErr.Failed(i, merr)
+			return true
+		})
+	}
+	return bErr
+}
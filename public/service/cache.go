@@ -31,9 +31,9 @@ type Cache interface {
 	Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error
 
 	// Delete attempts to remove a key. If the key does not exist then it is
-	// considered correct to return an error, however, for cache implementations
-	// where it is difficult to determine this then it is acceptable to return
-	// nil.
+	// considered correct to return ErrKeyNotFound, however, for cache
+	// implementations where it is difficult to determine this then it is
+	// acceptable to return nil.
 	Delete(ctx context.Context, key string) error
 
 	Closer
@@ -110,7 +110,11 @@ func (a *airGapCache) Add(ctx context.Context, key string, value []byte, ttl *ti
 }
 
 func (a *airGapCache) Delete(ctx context.Context, key string) error {
-	return a.c.Delete(ctx, key)
+	err := a.c.Delete(ctx, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		err = component.ErrKeyNotFound
+	}
+	return err
 }
 
 func (a *airGapCache) Close(ctx context.Context) error {
@@ -147,8 +151,22 @@ func (r *reverseAirGapCache) Add(ctx context.Context, key string, value []byte,
 	return
 }
 
-func (r *reverseAirGapCache) Delete(ctx context.Context, key string) error {
-	return r.c.Delete(ctx, key)
+func (r *reverseAirGapCache) SetMulti(ctx context.Context, keyValues ...CacheItem) error {
+	items := make(map[string]cache.TTLItem, len(keyValues))
+	for _, kv := range keyValues {
+		items[kv.Key] = cache.TTLItem{
+			Value: kv.Value,
+			TTL:   kv.TTL,
+		}
+	}
+	return r.c.SetMulti(ctx, items)
+}
+
+func (r *reverseAirGapCache) Delete(ctx context.Context, key string) (err error) {
+	if err = r.c.Delete(ctx, key); errors.Is(err, component.ErrKeyNotFound) {
+		err = ErrKeyNotFound
+	}
+	return
 }
 
 func (r *reverseAirGapCache) Close(ctx context.Context) error {
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"errors"
 	"testing"
 	"time"
 
@@ -298,6 +297,10 @@ func TestCacheAirGapDelete(t *testing.T) {
 	err := agrl.Delete(ctx, "foo")
 	assert.NoError(t, err)
 	assert.Equal(t, map[string]testCacheItem{}, rl.m)
+
+	rl.err = ErrKeyNotFound
+	err = agrl.Delete(ctx, "foo")
+	assert.Equal(t, component.ErrKeyNotFound, err)
 }
 
 type closableCacheType struct {
@@ -328,7 +331,15 @@ func (c *closableCacheType) Set(ctx context.Context, key string, value []byte, t
 }
 
 func (c *closableCacheType) SetMulti(ctx context.Context, items map[string]cache.TTLItem) error {
-	return errors.New("not implemented")
+	if c.err != nil {
+		return c.err
+	}
+	for k, v := range items {
+		c.m[k] = testCacheItem{
+			b: v.Value, ttl: v.TTL,
+		}
+	}
+	return nil
 }
 
 func (c *closableCacheType) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
@@ -491,4 +502,25 @@ func TestCacheReverseAirGapDelete(t *testing.T) {
 	err := agrl.Delete(context.Background(), "foo")
 	assert.NoError(t, err)
 	assert.Equal(t, map[string]testCacheItem{}, rl.m)
+
+	rl.err = component.ErrKeyNotFound
+	err = agrl.Delete(context.Background(), "foo")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestCacheReverseAirGapSetMulti(t *testing.T) {
+	rl := &closableCacheType{m: map[string]testCacheItem{}}
+	agrl := newReverseAirGapCache(rl)
+
+	ttl := time.Second
+	err := agrl.SetMulti(context.Background(), CacheItem{
+		Key: "foo", Value: []byte("bar"), TTL: &ttl,
+	}, CacheItem{
+		Key: "baz", Value: []byte("buz"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]testCacheItem{
+		"foo": {b: []byte("bar"), ttl: &ttl},
+		"baz": {b: []byte("buz")},
+	}, rl.m)
 }
@@ -142,6 +142,16 @@ func NewObjectListField(name string, fields ...*ConfigField) *ConfigField {
 	}
 }
 
+// NewObjectMapField describes a new config field consisting of an object of
+// arbitrary keys with object values, where each object consists of one or more
+// child fields.
+func NewObjectMapField(name string, fields ...*ConfigField) *ConfigField {
+	objField := NewObjectField(name, fields...)
+	return &ConfigField{
+		field: objField.field.Map(),
+	}
+}
+
 // NewInternalField returns a ConfigField derived from an internal package field
 // spec. This function is for internal use only.
 func NewInternalField(ifield docs.FieldSpec) *ConfigField {
@@ -682,3 +692,30 @@ func (p *ParsedConfig) FieldObjectList(path ...string) ([]*ParsedConfig, error)
 	}
 	return sList, nil
 }
+
+// FieldObjectMap accesses a field that is an object of arbitrary keys and
+// object values from the parsed config by its name and returns the value as a
+// map of *ParsedConfig types, where each one represents an object value in the
+// map. Returns an error if the field is not found, or is not an object of
+// objects.
+func (p *ParsedConfig) FieldObjectMap(path ...string) (map[string]*ParsedConfig, error) {
+	v, exists := p.field(path...)
+	if !exists {
+		return nil, fmt.Errorf("field '%v' was not found in the config", p.fullDotPath(path...))
+	}
+	iMap, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected field '%v' to be an object map, got %T", p.fullDotPath(path...), v)
+	}
+	sMap := make(map[string]*ParsedConfig, len(iMap))
+	for k, ev := range iMap {
+		if _, ok := ev.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("expected field '%v' to be an object map, found an element of type %T", p.fullDotPath(path...), ev)
+		}
+		sMap[k] = &ParsedConfig{
+			mgr:     p.mgr,
+			generic: ev,
+		}
+	}
+	return sMap, nil
+}
@@ -24,7 +24,10 @@ type BatchPolicy struct {
 	Period   string
 
 	// Only available when using NewBatchPolicyField.
-	procs []processor.Config
+	procs            []processor.Config
+	jitter           float64
+	maxInFlightBytes int
+	targetLatency    string
 }
 
 func (b BatchPolicy) toInternal() batchconfig.Config {
@@ -33,6 +36,9 @@ func (b BatchPolicy) toInternal() batchconfig.Config {
 	batchConf.Count = b.Count
 	batchConf.Check = b.Check
 	batchConf.Period = b.Period
+	batchConf.Jitter = b.jitter
+	batchConf.MaxInFlightBytes = b.maxInFlightBytes
+	batchConf.TargetLatency = b.targetLatency
 	batchConf.Processors = b.procs
 	return batchConf
 }
@@ -149,6 +155,22 @@ func (p *ParsedConfig) FieldBatchPolicy(path ...string) (conf BatchPolicy, err e
 		return conf, err
 	}
 
+	if p.Contains(append(path, "jitter")...) {
+		if conf.jitter, err = p.FieldFloat(append(path, "jitter")...); err != nil {
+			return conf, err
+		}
+	}
+	if p.Contains(append(path, "max_in_flight_bytes")...) {
+		if conf.maxInFlightBytes, err = p.FieldInt(append(path, "max_in_flight_bytes")...); err != nil {
+			return conf, err
+		}
+	}
+	if p.Contains(append(path, "target_latency")...) {
+		if conf.targetLatency, err = p.FieldString(append(path, "target_latency")...); err != nil {
+			return conf, err
+		}
+	}
+
 	procsNode, exists := p.field(append(path, "processors")...)
 	if !exists {
 		return
@@ -16,6 +16,15 @@ func NewInterpolatedStringField(name string) *ConfigField {
 	return &ConfigField{field: tf}
 }
 
+// NewInterpolatedStringListField describes a new config field consisting of a
+// list of interpolated string values. It is then possible to extract a slice of
+// *InterpolatedString from the resulting parsed config with the method
+// FieldInterpolatedStringList.
+func NewInterpolatedStringListField(name string) *ConfigField {
+	tf := docs.FieldString(name, "").IsInterpolated().Array()
+	return &ConfigField{field: tf}
+}
+
 // NewInterpolatedStringMapField describes a new config field consisting of an
 // object of arbitrary keys with interpolated string values. It is then
 // possible to extract an *InterpolatedString from the resulting parsed config
@@ -47,6 +56,27 @@ func (p *ParsedConfig) FieldInterpolatedString(path ...string) (*InterpolatedStr
 	return &InterpolatedString{expr: e}, nil
 }
 
+// FieldInterpolatedStringList accesses a field that is a list of interpolated
+// string values from the parsed config by its name and returns the value.
+//
+// Returns an error if the field is not found, or is not a list of interpolated
+// strings.
+func (p *ParsedConfig) FieldInterpolatedStringList(path ...string) ([]*InterpolatedString, error) {
+	strs, err := p.FieldStringList(path...)
+	if err != nil {
+		return nil, err
+	}
+	iList := make([]*InterpolatedString, len(strs))
+	for i, str := range strs {
+		e, err := p.mgr.BloblEnvironment().NewField(str)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse interpolated field '%v' index %v: %v", p.fullDotPath(path...), i, err)
+		}
+		iList[i] = &InterpolatedString{expr: e}
+	}
+	return iList, nil
+}
+
 // FieldInterpolatedStringMap accesses a field that is an object of arbitrary
 // keys and interpolated string values from the parsed config by its name and
 // returns the value.
@@ -309,6 +309,52 @@ func TestConfigListOfObjects(t *testing.T) {
 	assert.Equal(t, 13, intValue)
 }
 
+func TestConfigMapOfObjects(t *testing.T) {
+	spec := NewConfigSpec().
+		Field(NewObjectMapField("objects",
+			NewStringField("foo"),
+			NewIntField("bar").Default(10),
+		))
+
+	_, err := spec.ParseYAML(`objects:
+  a:
+    bar: 11
+`, nil)
+	require.Error(t, err)
+
+	parsedConfig, err := spec.ParseYAML(`objects:
+  a:
+    foo: "foo value 1"
+    bar: 11
+  b:
+    foo: "foo value 2"
+`, nil)
+	require.NoError(t, err)
+
+	_, err = parsedConfig.FieldObjectMap("nope")
+	require.Error(t, err)
+
+	objs, err := parsedConfig.FieldObjectMap("objects")
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	strValue, err := objs["a"].FieldString("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo value 1", strValue)
+
+	intValue, err := objs["a"].FieldInt("bar")
+	require.NoError(t, err)
+	assert.Equal(t, 11, intValue)
+
+	strValue, err = objs["b"].FieldString("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo value 2", strValue)
+
+	intValue, err = objs["b"].FieldInt("bar")
+	require.NoError(t, err)
+	assert.Equal(t, 10, intValue)
+}
+
 func TestConfigTLS(t *testing.T) {
 	spec := NewConfigSpec().
 		Field(NewTLSField("a")).
@@ -387,3 +433,31 @@ b:
 	res = iConf["d"].String(NewMessage([]byte("hello world")))
 	assert.Equal(t, "xyzzy hello world baz", res)
 }
+
+func TestConfigInterpolatedStringList(t *testing.T) {
+	spec := NewConfigSpec().
+		Field(NewInterpolatedStringListField("a")).
+		Field(NewStringListField("b"))
+
+	parsedConfig, err := spec.ParseYAML(`
+a:
+  - foo ${! content() } bar
+  - xyzzy ${! content() } baz
+b:
+  - this is ${! json( } an invalid interp string
+`, nil)
+	require.NoError(t, err)
+
+	_, err = parsedConfig.FieldInterpolatedStringList("b")
+	require.Error(t, err)
+
+	_, err = parsedConfig.FieldInterpolatedStringList("c")
+	require.Error(t, err)
+
+	iList, err := parsedConfig.FieldInterpolatedStringList("a")
+	require.NoError(t, err)
+	require.Len(t, iList, 2)
+
+	assert.Equal(t, "foo hello world bar", iList[0].String(NewMessage([]byte("hello world"))))
+	assert.Equal(t, "xyzzy hello world baz", iList[1].String(NewMessage([]byte("hello world"))))
+}
@@ -10,13 +10,17 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	iprocessors "github.com/benthosdev/benthos/v4/internal/component/input/processors"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/component/output/batcher"
 	oprocessors "github.com/benthosdev/benthos/v4/internal/component/output/processors"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
+	"github.com/benthosdev/benthos/v4/internal/component/tracer"
 	"github.com/benthosdev/benthos/v4/internal/config"
 	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager"
 	"github.com/benthosdev/benthos/v4/public/bloblang"
 )
 
@@ -106,6 +110,7 @@ func (e *Environment) RegisterBatchBuffer(name string, spec *ConfigSpec, ctor Ba
 		if err != nil {
 			return nil, err
 		}
+		b = withBatchBufferLifecycle(nm, b)
 		return buffer.NewStream(conf.Type, newAirGapBatchBuffer(b), nm.Logger(), nm.Metrics()), nil
 	}, componentSpec)
 }
@@ -137,6 +142,7 @@ func (e *Environment) RegisterCache(name string, spec *ConfigSpec, ctor CacheCon
 		if err != nil {
 			return nil, err
 		}
+		c = withCacheLifecycle(nm, c)
 		return newAirGapCache(c, nm.Metrics()), nil
 	}, componentSpec)
 }
@@ -172,6 +178,7 @@ func (e *Environment) RegisterInput(name string, spec *ConfigSpec, ctor InputCon
 		if err != nil {
 			return nil, err
 		}
+		i = withInputLifecycle(nm, i)
 		rdr := newAirGapReader(i)
 		return input.NewAsyncReader(conf.Type, false, rdr, nm.Logger(), nm.Metrics())
 	}), componentSpec)
@@ -199,6 +206,7 @@ func (e *Environment) RegisterBatchInput(name string, spec *ConfigSpec, ctor Bat
 		if err != nil {
 			return nil, err
 		}
+		i = withBatchInputLifecycle(nm, i)
 		rdr := newAirGapBatchReader(i)
 		return input.NewAsyncReader(conf.Type, false, rdr, nm.Logger(), nm.Metrics())
 	}), componentSpec)
@@ -232,6 +240,7 @@ func (e *Environment) RegisterOutput(name string, spec *ConfigSpec, ctor OutputC
 			if err != nil {
 				return nil, err
 			}
+			op = withOutputLifecycle(nm, op)
 			if maxInFlight < 1 {
 				return nil, fmt.Errorf("invalid maxInFlight parameter: %v", maxInFlight)
 			}
@@ -271,6 +280,7 @@ func (e *Environment) RegisterBatchOutput(name string, spec *ConfigSpec, ctor Ba
 			if err != nil {
 				return nil, err
 			}
+			op = withBatchOutputLifecycle(nm, op)
 
 			if maxInFlight < 1 {
 				return nil, fmt.Errorf("invalid maxInFlight parameter: %v", maxInFlight)
@@ -316,6 +326,7 @@ func (e *Environment) RegisterProcessor(name string, spec *ConfigSpec, ctor Proc
 		if err != nil {
 			return nil, err
 		}
+		r = withProcessorLifecycle(nm, r)
 		return newAirGapProcessor(conf.Type, r, nm.Metrics()), nil
 	}, componentSpec)
 }
@@ -341,6 +352,7 @@ func (e *Environment) RegisterBatchProcessor(name string, spec *ConfigSpec, ctor
 		if err != nil {
 			return nil, err
 		}
+		r = withBatchProcessorLifecycle(nm, r)
 		return newAirGapBatchProcessor(conf.Type, r, nm.Metrics()), nil
 	}, componentSpec)
 }
@@ -372,6 +384,7 @@ func (e *Environment) RegisterRateLimit(name string, spec *ConfigSpec, ctor Rate
 		if err != nil {
 			return nil, err
 		}
+		r = withRateLimitLifecycle(nm, r)
 		return newAirGapRateLimit(r, nm.Metrics()), nil
 	}, componentSpec)
 }
@@ -386,22 +399,86 @@ func (e *Environment) WalkRateLimits(fn func(name string, config *ConfigView)) {
 	}
 }
 
+// RegisterMetricsExporter attempts to register a new metrics exporter plugin by
+// providing a description of the configuration for the plugin as well as a
+// constructor for the metrics exporter itself. The constructor will be called
+// for each instantiation of the component within a config.
+//
+// Experimental: This method may change outside of major version releases.
+func (e *Environment) RegisterMetricsExporter(name string, spec *ConfigSpec, ctor MetricsExporterConstructor) error {
+	componentSpec := spec.component
+	componentSpec.Name = name
+	componentSpec.Type = docs.TypeMetrics
+	return e.internal.MetricsAdd(func(conf metrics.Config, l log.Modular) (metrics.Type, error) {
+		nm, err := e.newDetachedManager(l)
+		if err != nil {
+			return nil, err
+		}
+		pluginConf, err := extractConfig(nm, spec, name, conf.Plugin, conf)
+		if err != nil {
+			return nil, err
+		}
+		m, err := ctor(pluginConf, newReverseAirGapLogger(l))
+		if err != nil {
+			return nil, err
+		}
+		return newAirGapMetrics(m), nil
+	}, componentSpec)
+}
+
 // WalkMetrics executes a provided function argument for every metrics component
-// that has been registered to the environment. Note that metrics components
-// available to an environment cannot be modified
+// that has been registered to the environment.
 func (e *Environment) WalkMetrics(fn func(name string, config *ConfigView)) {
-	for _, v := range bundle.AllMetrics.Docs() {
+	for _, v := range e.internal.MetricsDocs() {
 		fn(v.Name, &ConfigView{
 			component: v,
 		})
 	}
 }
 
+// RegisterOtelTracerProvider attempts to register a new open telemetry tracer
+// provider plugin by providing a description of the configuration for the
+// plugin as well as a constructor for the provider itself. The constructor will
+// be called for each instantiation of the component within a config, and the
+// resulting provider is set as the global open telemetry tracer provider.
+//
+// Experimental: This method may change outside of major version releases.
+func (e *Environment) RegisterOtelTracerProvider(name string, spec *ConfigSpec, ctor OtelTracerProviderConstructor) error {
+	componentSpec := spec.component
+	componentSpec.Name = name
+	componentSpec.Type = docs.TypeTracer
+	return e.internal.TracerAdd(func(conf tracer.Config) (tracer.Type, error) {
+		nm, err := e.newDetachedManager(log.Noop())
+		if err != nil {
+			return nil, err
+		}
+		pluginConf, err := extractConfig(nm, spec, name, conf.Plugin, conf)
+		if err != nil {
+			return nil, err
+		}
+		prov, err := ctor(pluginConf)
+		if err != nil {
+			return nil, err
+		}
+		return newAirGapTracer(prov), nil
+	}, componentSpec)
+}
+
+// Metrics exporters and tracers are created before the resources of a stream,
+// and therefore their configs are parsed with an isolated manager that only
+// provides access to the environment.
+func (e *Environment) newDetachedManager(l log.Modular) (*manager.Type, error) {
+	return manager.New(
+		manager.NewResourceConfig(), nil, l, metrics.Noop(),
+		manager.OptSetEnvironment(e.internal),
+		manager.OptSetBloblangEnvironment(e.getBloblangParserEnv()),
+	)
+}
+
 // WalkTracers executes a provided function argument for every tracer component
-// that has been registered to the environment. Note that tracer components
-// available to an environment cannot be modified
+// that has been registered to the environment.
 func (e *Environment) WalkTracers(fn func(name string, config *ConfigView)) {
-	for _, v := range bundle.AllTracers.Docs() {
+	for _, v := range e.internal.TracerDocs() {
 		fn(v.Name, &ConfigView{
 			component: v,
 		})
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/benthosdev/benthos/v4/internal/codec"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// InputCodec is an interface implemented by custom codecs, which consume a
+// stream of bytes and produce discrete messages from it. Codecs are used by
+// inputs that support the `codec` field, such as `file`, `socket`, `sftp` and
+// `aws_s3`.
+//
+// Experimental: This type may change outside of major version releases.
+type InputCodec interface {
+	// Next attempts to read the next message batch from the underlying byte
+	// stream. The returned AckFunc will be called once the batch has been
+	// processed downstream.
+	//
+	// Once the byte stream is fully consumed ErrEndOfInput should be returned.
+	Next(context.Context) (MessageBatch, AckFunc, error)
+
+	// Close the codec along with the underlying byte stream.
+	Close(context.Context) error
+}
+
+// InputCodecReaderConstructor creates an InputCodec that consumes from a byte
+// stream. The path is the name of the source (a file path or object key), it
+// can be empty and is usually ignored, but might be useful for some codecs.
+// The provided AckFunc must be called once the byte stream has been fully
+// consumed and all messages read from it have been acknowledged, or with an
+// error if the stream could not be consumed.
+//
+// Experimental: This type may change outside of major version releases.
+type InputCodecReaderConstructor func(path string, r io.ReadCloser, ackFn AckFunc) (InputCodec, error)
+
+// InputCodecConstructor is called once for each input config that references
+// the codec, and is provided the argument that follows the codec name. For
+// example, a codec registered with the name `foo` configured as `foo:bar`
+// receives the argument `bar`. The returned InputCodecReaderConstructor is then
+// called for each byte stream consumed by the input.
+//
+// Experimental: This type may change outside of major version releases.
+type InputCodecConstructor func(arg string) (InputCodecReaderConstructor, error)
+
+// RegisterInputCodec attempts to register a custom codec that can be used by
+// any input that supports the `codec` field. The name must not contain the
+// characters `:` or `/`, and must not clash with a built in codec. Custom codecs
+// can be chained with built in codecs in the same way as regular codecs, e.g.
+// `gzip/foo`.
+//
+// Codecs are registered globally and are therefore available to all
+// environments.
+//
+// Experimental: This function may change outside of major version releases.
+func RegisterInputCodec(name string, ctor InputCodecConstructor) error {
+	return codec.RegisterPluginReader(name, func(arg string, _ codec.ReaderConfig) (codec.ReaderConstructor, error) {
+		rCtor, err := ctor(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(path string, r io.ReadCloser, fn codec.ReaderAckFn) (codec.Reader, error) {
+			c, err := rCtor(path, r, AckFunc(fn))
+			if err != nil {
+				return nil, err
+			}
+			return &airGapCodecReader{c: c}, nil
+		}, nil
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// Implements codec.Reader
+type airGapCodecReader struct {
+	c InputCodec
+}
+
+func (a *airGapCodecReader) Next(ctx context.Context) ([]*message.Part, codec.ReaderAckFn, error) {
+	batch, ackFn, err := a.c.Next(ctx)
+	if err != nil {
+		if errors.Is(err, ErrEndOfInput) {
+			err = io.EOF
+		}
+		return nil, nil, err
+	}
+	parts := make([]*message.Part, len(batch))
+	for i, m := range batch {
+		parts[i] = m.part
+	}
+	if ackFn == nil {
+		ackFn = func(context.Context, error) error {
+			return nil
+		}
+	}
+	return parts, codec.ReaderAckFn(ackFn), nil
+}
+
+func (a *airGapCodecReader) Close(ctx context.Context) error {
+	return a.c.Close(ctx)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/codec"
+)
+
+type splitInputCodec struct {
+	parts []string
+	r     io.ReadCloser
+	ackFn AckFunc
+}
+
+func (s *splitInputCodec) Next(ctx context.Context) (MessageBatch, AckFunc, error) {
+	if len(s.parts) == 0 {
+		return nil, nil, ErrEndOfInput
+	}
+	m := NewMessage([]byte(s.parts[0]))
+	s.parts = s.parts[1:]
+	if len(s.parts) == 0 {
+		return MessageBatch{m}, s.ackFn, nil
+	}
+	return MessageBatch{m}, nil, nil
+}
+
+func (s *splitInputCodec) Close(ctx context.Context) error {
+	return s.r.Close()
+}
+
+func TestInputCodecPlugin(t *testing.T) {
+	require.NoError(t, RegisterInputCodec("testsplit", func(arg string) (InputCodecReaderConstructor, error) {
+		if arg == "" {
+			return nil, errors.New("a delimiter is required")
+		}
+		return func(path string, r io.ReadCloser, ackFn AckFunc) (InputCodec, error) {
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			return &splitInputCodec{
+				parts: strings.Split(string(b), arg),
+				r:     r,
+				ackFn: ackFn,
+			}, nil
+		}, nil
+	}))
+
+	require.Error(t, RegisterInputCodec("lines", nil))
+	require.Error(t, RegisterInputCodec("foo:bar", nil))
+
+	_, err := codec.GetReader("testsplit", codec.NewReaderConfig())
+	require.Error(t, err)
+
+	ctor, err := codec.GetReader("testsplit:,", codec.NewReaderConfig())
+	require.NoError(t, err)
+
+	var acked bool
+	r, err := ctor("", io.NopCloser(bytes.NewReader([]byte("foo,bar,baz"))), func(ctx context.Context, err error) error {
+		require.NoError(t, err)
+		acked = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	var results []string
+	for {
+		parts, ackFn, err := r.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		for _, p := range parts {
+			results = append(results, string(p.Get()))
+		}
+		require.NoError(t, ackFn(context.Background(), nil))
+	}
+	assert.Equal(t, []string{"foo", "bar", "baz"}, results)
+	assert.True(t, acked)
+
+	require.NoError(t, r.Close(context.Background()))
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component"
+)
+
+// HealthChecker is an optional interface that can be implemented by plugin
+// components (inputs, outputs, processors, buffers, caches and rate limits) in
+// order to report their health. When a component returns a non-nil error the
+// /ready endpoint of the stream that owns it returns a 503 status along with
+// the error.
+//
+// Experimental: This type may change outside of major version releases.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// PostStartHook is an optional interface that can be implemented by plugin
+// components in order to be notified once the stream that owns them has
+// started.
+//
+// Experimental: This type may change outside of major version releases.
+type PostStartHook interface {
+	PostStart(ctx context.Context)
+}
+
+// PreDrainHook is an optional interface that can be implemented by plugin
+// components in order to be notified when the stream that owns them begins a
+// graceful drain, before its inputs are closed.
+//
+// Experimental: This type may change outside of major version releases.
+type PreDrainHook interface {
+	PreDrain(ctx context.Context)
+}
+
+// PostCloseHook is an optional interface that can be implemented by plugin
+// components in order to be notified once the stream that owns them has fully
+// closed.
+//
+// Experimental: This type may change outside of major version releases.
+type PostCloseHook interface {
+	PostClose(ctx context.Context)
+}
+
+// registerLifecycle checks whether a plugin implements any of the optional
+// health and lifecycle interfaces and, if so, registers them with the manager.
+// Returns a func that removes the registrations, which must be called once the
+// plugin is closed, or nil if the plugin implements none of the interfaces.
+func registerLifecycle(mgr bundle.NewManagement, plugin interface{}) func() {
+	var removeFns []func()
+
+	var hooks component.LifecycleHooks
+	var hasHooks bool
+	if h, ok := plugin.(PostStartHook); ok {
+		hooks.PostStart = h.PostStart
+		hasHooks = true
+	}
+	if h, ok := plugin.(PreDrainHook); ok {
+		hooks.PreDrain = h.PreDrain
+		hasHooks = true
+	}
+	if h, ok := plugin.(PostCloseHook); ok {
+		hooks.PostClose = h.PostClose
+		hasHooks = true
+	}
+	if hasHooks {
+		removeFns = append(removeFns, mgr.Lifecycle().AddHooks(hooks))
+	}
+
+	if h, ok := plugin.(HealthChecker); ok {
+		name := mgr.Label()
+		if name == "" {
+			name = "root"
+			if p := mgr.Path(); len(p) > 0 {
+				name += "." + query.SliceToDotPath(p...)
+			}
+		}
+		removeFns = append(removeFns, mgr.Lifecycle().AddHealthCheck(name, h.CheckHealth))
+	}
+
+	if len(removeFns) == 0 {
+		return nil
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for _, fn := range removeFns {
+				fn()
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// The following types wrap plugins that registered health checks or lifecycle
+// hooks in order to remove the registrations once the plugin is closed.
+
+type lifecycleBatchBuffer struct {
+	BatchBuffer
+	unregister func()
+}
+
+func (l *lifecycleBatchBuffer) Close(ctx context.Context) error {
+	l.unregister()
+	return l.BatchBuffer.Close(ctx)
+}
+
+func withBatchBufferLifecycle(mgr bundle.NewManagement, b BatchBuffer) BatchBuffer {
+	if unregister := registerLifecycle(mgr, b); unregister != nil {
+		return &lifecycleBatchBuffer{BatchBuffer: b, unregister: unregister}
+	}
+	return b
+}
+
+type lifecycleCache struct {
+	Cache
+	unregister func()
+}
+
+func (l *lifecycleCache) Close(ctx context.Context) error {
+	l.unregister()
+	return l.Cache.Close(ctx)
+}
+
+// Preserves the optional batchedCache interface of the wrapped cache.
+type lifecycleBatchedCache struct {
+	*lifecycleCache
+	batchedCache
+}
+
+func withCacheLifecycle(mgr bundle.NewManagement, c Cache) Cache {
+	unregister := registerLifecycle(mgr, c)
+	if unregister == nil {
+		return c
+	}
+	lc := &lifecycleCache{Cache: c, unregister: unregister}
+	if bc, ok := c.(batchedCache); ok {
+		return &lifecycleBatchedCache{lifecycleCache: lc, batchedCache: bc}
+	}
+	return lc
+}
+
+type lifecycleInput struct {
+	Input
+	unregister func()
+}
+
+func (l *lifecycleInput) Close(ctx context.Context) error {
+	l.unregister()
+	return l.Input.Close(ctx)
+}
+
+func withInputLifecycle(mgr bundle.NewManagement, i Input) Input {
+	if unregister := registerLifecycle(mgr, i); unregister != nil {
+		return &lifecycleInput{Input: i, unregister: unregister}
+	}
+	return i
+}
+
+type lifecycleBatchInput struct {
+	BatchInput
+	unregister func()
+}
+
+func (l *lifecycleBatchInput) Close(ctx context.Context) error {
+	l.unregister()
+	return l.BatchInput.Close(ctx)
+}
+
+func withBatchInputLifecycle(mgr bundle.NewManagement, i BatchInput) BatchInput {
+	if unregister := registerLifecycle(mgr, i); unregister != nil {
+		return &lifecycleBatchInput{BatchInput: i, unregister: unregister}
+	}
+	return i
+}
+
+type lifecycleOutput struct {
+	Output
+	unregister func()
+}
+
+func (l *lifecycleOutput) Close(ctx context.Context) error {
+	l.unregister()
+	return l.Output.Close(ctx)
+}
+
+func withOutputLifecycle(mgr bundle.NewManagement, o Output) Output {
+	if unregister := registerLifecycle(mgr, o); unregister != nil {
+		return &lifecycleOutput{Output: o, unregister: unregister}
+	}
+	return o
+}
+
+type lifecycleBatchOutput struct {
+	BatchOutput
+	unregister func()
+}
+
+func (l *lifecycleBatchOutput) Close(ctx context.Context) error {
+	l.unregister()
+	return l.BatchOutput.Close(ctx)
+}
+
+func withBatchOutputLifecycle(mgr bundle.NewManagement, o BatchOutput) BatchOutput {
+	if unregister := registerLifecycle(mgr, o); unregister != nil {
+		return &lifecycleBatchOutput{BatchOutput: o, unregister: unregister}
+	}
+	return o
+}
+
+type lifecycleProcessor struct {
+	Processor
+	unregister func()
+}
+
+func (l *lifecycleProcessor) Close(ctx context.Context) error {
+	l.unregister()
+	return l.Processor.Close(ctx)
+}
+
+func withProcessorLifecycle(mgr bundle.NewManagement, p Processor) Processor {
+	if unregister := registerLifecycle(mgr, p); unregister != nil {
+		return &lifecycleProcessor{Processor: p, unregister: unregister}
+	}
+	return p
+}
+
+type lifecycleBatchProcessor struct {
+	BatchProcessor
+	unregister func()
+}
+
+func (l *lifecycleBatchProcessor) Close(ctx context.Context) error {
+	l.unregister()
+	return l.BatchProcessor.Close(ctx)
+}
+
+func withBatchProcessorLifecycle(mgr bundle.NewManagement, p BatchProcessor) BatchProcessor {
+	if unregister := registerLifecycle(mgr, p); unregister != nil {
+		return &lifecycleBatchProcessor{BatchProcessor: p, unregister: unregister}
+	}
+	return p
+}
+
+type lifecycleRateLimit struct {
+	RateLimit
+	unregister func()
+}
+
+func (l *lifecycleRateLimit) Close(ctx context.Context) error {
+	l.unregister()
+	return l.RateLimit.Close(ctx)
+}
+
+func withRateLimitLifecycle(mgr bundle.NewManagement, r RateLimit) RateLimit {
+	if unregister := registerLifecycle(mgr, r); unregister != nil {
+		return &lifecycleRateLimit{RateLimit: r, unregister: unregister}
+	}
+	return r
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+type lifecyclePlugin struct {
+	healthErr error
+	events    []string
+}
+
+func (l *lifecyclePlugin) CheckHealth(ctx context.Context) error {
+	return l.healthErr
+}
+
+func (l *lifecyclePlugin) PostStart(ctx context.Context) {
+	l.events = append(l.events, "post_start")
+}
+
+func (l *lifecyclePlugin) PreDrain(ctx context.Context) {
+	l.events = append(l.events, "pre_drain")
+}
+
+func (l *lifecyclePlugin) PostClose(ctx context.Context) {
+	l.events = append(l.events, "post_close")
+}
+
+func TestRegisterLifecycle(t *testing.T) {
+	mgr := mock.NewManager()
+
+	p := &lifecyclePlugin{}
+	assert.NotNil(t, registerLifecycle(mgr, p))
+	assert.Nil(t, registerLifecycle(mgr, struct{}{}))
+
+	lc := mgr.Lifecycle()
+	assert.True(t, lc.CheckHealth(context.Background(), func(name string, err error) {
+		t.Errorf("unexpected unhealthy component %v: %v", name, err)
+	}))
+
+	p.healthErr = errors.New("nope")
+
+	var unhealthy []string
+	assert.False(t, lc.CheckHealth(context.Background(), func(name string, err error) {
+		unhealthy = append(unhealthy, name+": "+err.Error())
+	}))
+	assert.Equal(t, []string{"root: nope"}, unhealthy)
+
+	lc.PostStart(context.Background())
+	lc.PreDrain(context.Background())
+	lc.PostClose(context.Background())
+	assert.Equal(t, []string{"post_start", "pre_drain", "post_close"}, p.events)
+}
+
+type lifecycleProc struct {
+	lifecyclePlugin
+}
+
+func (l *lifecycleProc) Process(ctx context.Context, msg *Message) (MessageBatch, error) {
+	return MessageBatch{msg}, nil
+}
+
+func (l *lifecycleProc) Close(ctx context.Context) error {
+	l.events = append(l.events, "close")
+	return nil
+}
+
+func TestLifecycleRemovedOnClose(t *testing.T) {
+	mgr := mock.NewManager()
+	lc := mgr.Lifecycle()
+
+	first := &lifecycleProc{lifecyclePlugin{healthErr: errors.New("first")}}
+	second := &lifecycleProc{lifecyclePlugin{healthErr: errors.New("second")}}
+
+	firstProc := withProcessorLifecycle(mgr, first)
+	_ = withProcessorLifecycle(mgr, second)
+
+	var unhealthy []string
+	lc.CheckHealth(context.Background(), func(name string, err error) {
+		unhealthy = append(unhealthy, err.Error())
+	})
+	assert.Equal(t, []string{"first", "second"}, unhealthy)
+
+	require.NoError(t, firstProc.Close(context.Background()))
+
+	unhealthy = nil
+	lc.CheckHealth(context.Background(), func(name string, err error) {
+		unhealthy = append(unhealthy, err.Error())
+	})
+	assert.Equal(t, []string{"second"}, unhealthy)
+
+	// Closed components are still notified once the stream closes, but are not
+	// notified of any further events.
+	lc.PostStart(context.Background())
+	lc.PostClose(context.Background())
+	lc.PostClose(context.Background())
+	assert.Equal(t, []string{"close", "post_close"}, first.events)
+	assert.Equal(t, []string{"post_start", "post_close", "post_close"}, second.events)
+}
+
+type batchedLifecyclePlugin struct {
+	lifecyclePlugin
+	Cache
+}
+
+func (l *batchedLifecyclePlugin) SetMulti(ctx context.Context, keyValues ...CacheItem) error {
+	return nil
+}
+
+func TestLifecycleCachePreservesBatching(t *testing.T) {
+	mgr := mock.NewManager()
+
+	c := withCacheLifecycle(mgr, &batchedLifecyclePlugin{})
+	_, isBatched := c.(batchedCache)
+	assert.True(t, isBatched)
+}
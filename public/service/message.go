@@ -24,6 +24,10 @@ type MessageBatchHandlerFunc func(context.Context, MessageBatch) error
 type Message struct {
 	part       *message.Part
 	partCopied bool
+
+	// Indicates that the structured contents of the part are exclusively owned
+	// by this message and can therefore be mutated in place without cloning.
+	structuredOwned bool
 }
 
 // MessageBatch describes a collection of one or more messages.
@@ -50,7 +54,7 @@ func NewMessage(content []byte) *Message {
 }
 
 func newMessageFromPart(part *message.Part) *Message {
-	return &Message{part, false}
+	return &Message{part: part}
 }
 
 // Copy creates a shallow copy of a message that is safe to mutate with Set
@@ -61,10 +65,21 @@ func newMessageFromPart(part *message.Part) *Message {
 // contents of the message, and therefore it is not safe to perform inline
 // mutations on those values without copying them.
 func (m *Message) Copy() *Message {
-	return &Message{
+	c := &Message{
 		part:       m.part.Copy(),
 		partCopied: true,
 	}
+	if m.structuredOwned {
+		// The original message may continue to mutate its structured contents
+		// in place, and so the copy is given its own.
+		if v, err := m.part.JSON(); err == nil {
+			if v, err = message.CopyJSON(v); err == nil {
+				c.part.SetJSON(v)
+				c.structuredOwned = true
+			}
+		}
+	}
+	return c
 }
 
 func (m *Message) ensureCopied() {
@@ -125,10 +140,42 @@ func (m *Message) AsStructuredMut() (interface{}, error) {
 	return message.CopyJSON(v)
 }
 
+// AsStructuredMutInPlace returns the structured contents of a message, parsing
+// the bytes contents as a JSON document if necessary, as a value that is
+// exclusively owned by the message and can therefore be mutated in place.
+//
+// Unlike AsStructuredMut the returned value is not a detached copy, and
+// mutations to it are reflected in the message without calling SetStructured.
+// The structured contents are deep cloned the first time this method is called
+// on a message that doesn't already own them, after which subsequent calls
+// return the same value without cloning it again. The contents of a message
+// set with SetStructured are already owned and are never cloned.
+//
+// Since the message is set to the returned value any raw bytes contents are
+// discarded, and therefore the formatting of the message when it is later
+// serialised may differ from that of its original bytes.
+func (m *Message) AsStructuredMutInPlace() (interface{}, error) {
+	v, err := m.part.JSON()
+	if err != nil {
+		return nil, err
+	}
+	if m.structuredOwned {
+		return v, nil
+	}
+	if v, err = message.CopyJSON(v); err != nil {
+		return nil, err
+	}
+	m.ensureCopied()
+	m.part.SetJSON(v)
+	m.structuredOwned = true
+	return v, nil
+}
+
 // SetBytes sets the underlying contents of the message as a byte slice.
 func (m *Message) SetBytes(b []byte) {
 	m.ensureCopied()
 	m.part.Set(b)
+	m.structuredOwned = false
 }
 
 // SetStructured sets the underlying contents of the message as a structured
@@ -137,9 +184,14 @@ func (m *Message) SetBytes(b []byte) {
 // through the hierarchy, this ensures that other processors are able to work
 // with the contents and that they can be JSON marshalled when coerced into a
 // byte array.
+//
+// The message takes ownership of the provided value, and therefore it should
+// not be mutated after this call other than through the result of
+// AsStructuredMutInPlace.
 func (m *Message) SetStructured(i interface{}) {
 	m.ensureCopied()
 	m.part.SetJSON(i)
+	m.structuredOwned = true
 }
 
 // SetError marks the message as having failed a processing step and adds the
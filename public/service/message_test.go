@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -221,6 +222,88 @@ func TestNewMessageMutate(t *testing.T) {
 	assert.Equal(t, map[string]string{"foo": "new bar", "bar": "baz"}, seen)
 }
 
+func TestMessageStructuredMutDetached(t *testing.T) {
+	g0 := NewMessage([]byte(`{"foo":  "bar"}`))
+
+	s0, err := g0.AsStructuredMut()
+	require.NoError(t, err)
+	s0.(map[string]interface{})["foo"] = "baz"
+
+	// Mutations of the detached copy are not reflected in the message.
+	s1, err := g0.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, s1)
+
+	b, err := g0.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":  "bar"}`, string(b))
+}
+
+func TestMessageStructuredMutInPlace(t *testing.T) {
+	g0 := NewMessage([]byte(`{"foo":"bar"}`))
+
+	s0, err := g0.AsStructuredMutInPlace()
+	require.NoError(t, err)
+	s0.(map[string]interface{})["foo"] = "baz"
+
+	// Mutations of owned contents are reflected without setting them again.
+	s1, err := g0.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "baz"}, s1)
+
+	g1 := g0.Copy()
+
+	s2, err := g1.AsStructuredMutInPlace()
+	require.NoError(t, err)
+	s2.(map[string]interface{})["foo"] = "qux"
+
+	s3, err := g0.AsStructuredMutInPlace()
+	require.NoError(t, err)
+	s3.(map[string]interface{})["bar"] = "quz"
+
+	s0, err = g0.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "baz", "bar": "quz"}, s0)
+
+	s1, err = g1.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "qux"}, s1)
+
+	g0.SetBytes([]byte(`{"foo":"new"}`))
+	s0, err = g0.AsStructuredMutInPlace()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "new"}, s0)
+}
+
+func TestMessageStructuredMutInPlaceConcurrentCopies(t *testing.T) {
+	g0 := NewMessage(nil)
+	g0.SetStructured(map[string]interface{}{"foo": "bar"})
+
+	var wg sync.WaitGroup
+	copies := make([]*Message, 10)
+	for i := range copies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			copies[i] = g0.Copy()
+			s, err := copies[i].AsStructuredMutInPlace()
+			require.NoError(t, err)
+			s.(map[string]interface{})["foo"] = i
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := g0.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, s)
+
+	for i, c := range copies {
+		s, err := c.AsStructured()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"foo": i}, s)
+	}
+}
+
 func TestMessageMapping(t *testing.T) {
 	part := NewMessage(nil)
 	part.SetStructured(map[string]interface{}{
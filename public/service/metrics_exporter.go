@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+)
+
+// MetricsExporterCounter represents a counter metric of a given name and
+// labels.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterCounter interface {
+	// Incr increments a counter metric by an integer amount.
+	Incr(count int64)
+}
+
+// MetricsExporterTimer represents a timing metric of a given name and labels.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterTimer interface {
+	// Timing sets a timing metric, the value is measured in nanoseconds.
+	Timing(delta int64)
+}
+
+// MetricsExporterGauge represents a gauge metric of a given name and labels.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterGauge interface {
+	// Set sets a gauge metric to an integer value.
+	Set(value int64)
+}
+
+// MetricsExporterCounterCtor is a constructor for a MetricsExporterCounter
+// that must be called with a variadic list of label values exactly matching
+// the length and order of the label keys provided.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterCounterCtor func(labelValues ...string) MetricsExporterCounter
+
+// MetricsExporterTimerCtor is a constructor for a MetricsExporterTimer that
+// must be called with a variadic list of label values exactly matching the
+// length and order of the label keys provided.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterTimerCtor func(labelValues ...string) MetricsExporterTimer
+
+// MetricsExporterGaugeCtor is a constructor for a MetricsExporterGauge that
+// must be called with a variadic list of label values exactly matching the
+// length and order of the label keys provided.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterGaugeCtor func(labelValues ...string) MetricsExporterGauge
+
+// MetricsExporter is an interface implemented by Benthos metrics exporters.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporter interface {
+	NewCounterCtor(name string, labelKeys ...string) MetricsExporterCounterCtor
+	NewTimerCtor(name string, labelKeys ...string) MetricsExporterTimerCtor
+	NewGaugeCtor(name string, labelKeys ...string) MetricsExporterGaugeCtor
+	Close(ctx context.Context) error
+}
+
+// MetricsExporterConstructor is a func that's provided a configuration type
+// and access to a logger, and must return an instantiation of a metrics
+// exporter based on the config, or an error.
+//
+// Experimental: This type may change outside of major version releases.
+type MetricsExporterConstructor func(conf *ParsedConfig, log *Logger) (MetricsExporter, error)
+
+//------------------------------------------------------------------------------
+
+// Implements internal metrics.Type around a public MetricsExporter.
+type airGapMetrics struct {
+	airGapped MetricsExporter
+
+	gaugesMut sync.Mutex
+	gauges    map[string]*airGapGauge
+}
+
+func newAirGapMetrics(m MetricsExporter) metrics.Type {
+	return &airGapMetrics{
+		airGapped: m,
+		gauges:    map[string]*airGapGauge{},
+	}
+}
+
+func (a *airGapMetrics) GetCounter(path string) metrics.StatCounter {
+	return a.airGapped.NewCounterCtor(path)()
+}
+
+func (a *airGapMetrics) GetCounterVec(path string, labelNames ...string) metrics.StatCounterVec {
+	ctor := a.airGapped.NewCounterCtor(path, labelNames...)
+	return metrics.FakeCounterVec(func(labelValues ...string) metrics.StatCounter {
+		return ctor(labelValues...)
+	})
+}
+
+func (a *airGapMetrics) GetTimer(path string) metrics.StatTimer {
+	return a.airGapped.NewTimerCtor(path)()
+}
+
+func (a *airGapMetrics) GetTimerVec(path string, labelNames ...string) metrics.StatTimerVec {
+	ctor := a.airGapped.NewTimerCtor(path, labelNames...)
+	return metrics.FakeTimerVec(func(labelValues ...string) metrics.StatTimer {
+		return ctor(labelValues...)
+	})
+}
+
+// getGauge returns the gauge of a path and set of labels, creating it if it
+// does not already exist. Gauges are reused as their values are tracked
+// locally, and so a fresh gauge would lose any prior increments.
+func (a *airGapMetrics) getGauge(path string, labelNames, labelValues []string, ctor MetricsExporterGaugeCtor) *airGapGauge {
+	key := path + "\x00" + strings.Join(labelNames, "\x00") + "\x00\x00" + strings.Join(labelValues, "\x00")
+
+	a.gaugesMut.Lock()
+	defer a.gaugesMut.Unlock()
+
+	g, exists := a.gauges[key]
+	if !exists {
+		g = &airGapGauge{g: ctor(labelValues...)}
+		a.gauges[key] = g
+	}
+	return g
+}
+
+func (a *airGapMetrics) GetGauge(path string) metrics.StatGauge {
+	return a.getGauge(path, nil, nil, a.airGapped.NewGaugeCtor(path))
+}
+
+func (a *airGapMetrics) GetGaugeVec(path string, labelNames ...string) metrics.StatGaugeVec {
+	ctor := a.airGapped.NewGaugeCtor(path, labelNames...)
+	return metrics.FakeGaugeVec(func(labelValues ...string) metrics.StatGauge {
+		return a.getGauge(path, labelNames, labelValues, ctor)
+	})
+}
+
+func (a *airGapMetrics) HandlerFunc() http.HandlerFunc {
+	return nil
+}
+
+func (a *airGapMetrics) Close() error {
+	return a.airGapped.Close(context.Background())
+}
+
+// Public gauges only support Set, and therefore increments and decrements are
+// tracked locally and converted into absolute values.
+type airGapGauge struct {
+	value int64
+	g     MetricsExporterGauge
+}
+
+func (a *airGapGauge) Set(value int64) {
+	atomic.StoreInt64(&a.value, value)
+	a.g.Set(value)
+}
+
+func (a *airGapGauge) Incr(count int64) {
+	a.g.Set(atomic.AddInt64(&a.value, count))
+}
+
+func (a *airGapGauge) Decr(count int64) {
+	a.g.Set(atomic.AddInt64(&a.value, -count))
+}
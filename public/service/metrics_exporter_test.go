@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+type mockMetricsExporter struct {
+	prefix string
+	closed bool
+
+	mut    sync.Mutex
+	values map[string]int64
+}
+
+type mockMetricsStat struct {
+	key string
+	e   *mockMetricsExporter
+}
+
+func (m *mockMetricsStat) Incr(count int64) {
+	m.e.mut.Lock()
+	m.e.values[m.key] += count
+	m.e.mut.Unlock()
+}
+
+func (m *mockMetricsStat) Timing(delta int64) {
+	m.Set(delta)
+}
+
+func (m *mockMetricsStat) Set(value int64) {
+	m.e.mut.Lock()
+	m.e.values[m.key] = value
+	m.e.mut.Unlock()
+}
+
+func (m *mockMetricsExporter) key(name string, labelKeys, labelValues []string) string {
+	var labels []string
+	for i, k := range labelKeys {
+		labels = append(labels, k+"="+labelValues[i])
+	}
+	return m.prefix + name + "{" + strings.Join(labels, ",") + "}"
+}
+
+func (m *mockMetricsExporter) NewCounterCtor(name string, labelKeys ...string) MetricsExporterCounterCtor {
+	return func(labelValues ...string) MetricsExporterCounter {
+		return &mockMetricsStat{key: m.key(name, labelKeys, labelValues), e: m}
+	}
+}
+
+func (m *mockMetricsExporter) NewTimerCtor(name string, labelKeys ...string) MetricsExporterTimerCtor {
+	return func(labelValues ...string) MetricsExporterTimer {
+		return &mockMetricsStat{key: m.key(name, labelKeys, labelValues), e: m}
+	}
+}
+
+func (m *mockMetricsExporter) NewGaugeCtor(name string, labelKeys ...string) MetricsExporterGaugeCtor {
+	return func(labelValues ...string) MetricsExporterGauge {
+		return &mockMetricsStat{key: m.key(name, labelKeys, labelValues), e: m}
+	}
+}
+
+func (m *mockMetricsExporter) Close(ctx context.Context) error {
+	m.closed = true
+	return nil
+}
+
+func TestMetricsExporterPlugin(t *testing.T) {
+	env := NewEnvironment()
+
+	var exporter *mockMetricsExporter
+	require.NoError(t, env.RegisterMetricsExporter(
+		"mock_exporter", NewConfigSpec().Field(NewStringField("prefix")),
+		func(conf *ParsedConfig, log *Logger) (MetricsExporter, error) {
+			prefix, err := conf.FieldString("prefix")
+			if err != nil {
+				return nil, err
+			}
+			exporter = &mockMetricsExporter{prefix: prefix, values: map[string]int64{}}
+			return exporter, nil
+		}))
+
+	var seen bool
+	env.WalkMetrics(func(name string, config *ConfigView) {
+		if name == "mock_exporter" {
+			seen = true
+		}
+	})
+	assert.True(t, seen)
+
+	var conf metrics.Config
+	require.NoError(t, yaml.Unmarshal([]byte(`
+mock_exporter:
+  prefix: foo_
+`), &conf))
+
+	stats, err := env.internal.MetricsInit(conf, log.Noop())
+	require.NoError(t, err)
+
+	stats.GetCounter("counter").Incr(2)
+	stats.GetCounterVec("counter_vec", "a").With("b").Incr(3)
+	stats.GetTimerVec("timer_vec", "a").With("c").Timing(10)
+
+	gge := stats.GetGauge("gauge")
+	gge.Incr(5)
+	gge.Decr(2)
+
+	ggeVec := stats.GetGaugeVec("gauge_vec", "a")
+	ggeVec.With("d").Incr(4)
+	ggeVec.With("d").Decr(1)
+	ggeVec.With("e").Incr(2)
+	stats.GetGaugeVec("gauge_vec", "a").With("e").Decr(1)
+
+	require.NoError(t, stats.Close())
+
+	assert.True(t, exporter.closed)
+	assert.Equal(t, map[string]int64{
+		"foo_counter{}":        2,
+		"foo_counter_vec{a=b}": 3,
+		"foo_timer_vec{a=c}":   10,
+		"foo_gauge{}":          3,
+		"foo_gauge_vec{a=d}":   3,
+		"foo_gauge_vec{a=e}":   1,
+	}, exporter.values)
+}
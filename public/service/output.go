@@ -66,6 +66,9 @@ type BatchOutput interface {
 	//
 	// If this method returns ErrNotConnected then write will not be called
 	// again until Connect has returned a nil error.
+	//
+	// If only some messages of the batch could not be delivered then a
+	// *BatchError can be returned in order to indicate which messages failed.
 	WriteBatch(context.Context, MessageBatch) error
 
 	Closer
@@ -140,7 +143,7 @@ func (a *airGapBatchWriter) WriteWithContext(ctx context.Context, msg *message.B
 	if err != nil && errors.Is(err, ErrNotConnected) {
 		err = component.ErrNotConnected
 	}
-	return err
+	return toInternalBatchError(err, msg)
 }
 
 func (a *airGapBatchWriter) CloseAsync() {
@@ -205,7 +208,8 @@ func (o *OwnedOutput) Write(ctx context.Context, m *Message) error {
 }
 
 // WriteBatch attempts to write a message batch to the output, and returns an
-// error either if delivery is not possible or the context is cancelled.
+// error either if delivery is not possible or the context is cancelled. If only
+// some messages of the batch failed the error returned may be a *BatchError.
 func (o *OwnedOutput) WriteBatch(ctx context.Context, b MessageBatch) error {
 	payload := message.QuickBatch(nil)
 	for _, m := range b {
@@ -221,7 +225,7 @@ func (o *OwnedOutput) WriteBatch(ctx context.Context, b MessageBatch) error {
 
 	select {
 	case res := <-resChan:
-		return res
+		return fromInternalBatchError(res, b)
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/batch"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/message"
 )
@@ -177,3 +179,63 @@ func TestBatchOutputAirGapHappy(t *testing.T) {
 
 	assert.Equal(t, "hello world", wroteMsg)
 }
+
+func TestBatchOutputAirGapBatchError(t *testing.T) {
+	o := &fnBatchOutput{
+		connect: func() error {
+			return nil
+		},
+		writeBatch: func(m MessageBatch) error {
+			return NewBatchError(m, errors.New("headline")).
+				Failed(1, errors.New("bad message 1"))
+		},
+	}
+	agi := newAirGapBatchWriter(o)
+
+	inMsg := message.QuickBatch([][]byte{
+		[]byte("foo"),
+		[]byte("bar"),
+		[]byte("baz"),
+	})
+
+	err := agi.WriteWithContext(context.Background(), inMsg)
+	require.Error(t, err)
+	assert.EqualError(t, err, "headline")
+
+	var iErr *batch.Error
+	require.True(t, errors.As(err, &iErr))
+	assert.Equal(t, 1, iErr.IndexedErrors())
+
+	failed := map[string]string{}
+	iErr.WalkParts(func(i int, p *message.Part, err error) bool {
+		if err != nil {
+			failed[string(p.Get())] = err.Error()
+		}
+		return true
+	})
+	assert.Equal(t, map[string]string{"bar": "bad message 1"}, failed)
+}
+
+func TestBatchErrorFromInternal(t *testing.T) {
+	inMsg := message.QuickBatch([][]byte{
+		[]byte("foo"),
+		[]byte("bar"),
+	})
+	iErr := batch.NewError(inMsg, errors.New("headline")).Failed(0, errors.New("bad message 0"))
+
+	b := MessageBatch{NewMessage([]byte("foo")), NewMessage([]byte("bar"))}
+
+	var bErr *BatchError
+	require.True(t, errors.As(fromInternalBatchError(iErr, b), &bErr))
+	assert.EqualError(t, bErr, "headline")
+
+	failed := map[string]string{}
+	bErr.WalkMessages(func(i int, m *Message, err error) bool {
+		if err != nil {
+			mBytes, _ := m.AsBytes()
+			failed[string(mBytes)] = err.Error()
+		}
+		return true
+	})
+	assert.Equal(t, map[string]string{"foo": "bad message 0"}, failed)
+}
@@ -148,3 +148,23 @@ type RateLimitConstructor func(conf *ParsedConfig, mgr *Resources) (RateLimit, e
 func RegisterRateLimit(name string, spec *ConfigSpec, ctor RateLimitConstructor) error {
 	return globalEnvironment.RegisterRateLimit(name, spec, ctor)
 }
+
+// RegisterMetricsExporter attempts to register a new metrics exporter plugin by
+// providing a description of the configuration for the plugin as well as a
+// constructor for the metrics exporter itself. The constructor will be called
+// for each instantiation of the component within a config.
+//
+// Experimental: This function may change outside of major version releases.
+func RegisterMetricsExporter(name string, spec *ConfigSpec, ctor MetricsExporterConstructor) error {
+	return globalEnvironment.RegisterMetricsExporter(name, spec, ctor)
+}
+
+// RegisterOtelTracerProvider attempts to register a new open telemetry tracer
+// provider plugin by providing a description of the configuration for the
+// plugin as well as a constructor for the provider itself. The constructor will
+// be called for each instantiation of the component within a config.
+//
+// Experimental: This function may change outside of major version releases.
+func RegisterOtelTracerProvider(name string, spec *ConfigSpec, ctor OtelTracerProviderConstructor) error {
+	return globalEnvironment.RegisterOtelTracerProvider(name, spec, ctor)
+}
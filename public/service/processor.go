@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
@@ -57,11 +59,57 @@ type BatchProcessor interface {
 	// The Message types returned MUST be derived from the provided messages,
 	// and CANNOT be custom implementations of Message. In order to copy the
 	// provided messages use the Copy method.
+	//
+	// In order to signal back pressure upstream a *RetryAfterError can be
+	// returned, in which case the input messages are not marked with the
+	// error, and instead the same batch is provided to ProcessBatch again once
+	// the specified duration has passed, without any changes that were made to
+	// its messages during the failed attempt. The pipeline does not progress
+	// past the batch during this time.
 	ProcessBatch(context.Context, MessageBatch) ([]MessageBatch, error)
 
 	Closer
 }
 
+// RetryAfterError is an error type that can be returned by a BatchProcessor in
+// order to signal that it is temporarily unable to process a batch, and that
+// the batch should be retried after a period of time. This allows processors
+// that depend on downstream capacity, or that aggregate messages into windows,
+// to apply back pressure to the pipeline rather than failing messages.
+type RetryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+// NewRetryAfterError creates a new error that signals the batch should be
+// provided to the processor again once the duration has passed. If the
+// pipeline is shut down before the duration passes then the messages of the
+// batch are marked with the underlying error.
+func NewRetryAfterError(err error, after time.Duration) *RetryAfterError {
+	return &RetryAfterError{
+		err:   err,
+		after: after,
+	}
+}
+
+// RetryAfter returns the duration to wait before the batch is retried.
+func (err *RetryAfterError) RetryAfter() time.Duration {
+	return err.after
+}
+
+// Error implements the common error interface.
+func (err *RetryAfterError) Error() string {
+	if err.err == nil {
+		return fmt.Sprintf("batch should be retried after %v", err.after)
+	}
+	return err.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *RetryAfterError) Unwrap() error {
+	return err.err
+}
+
 //------------------------------------------------------------------------------
 
 // Implements types.Processor for a Processor.
@@ -94,20 +142,53 @@ func (a *airGapProcessor) Close(ctx context.Context) error {
 // Implements types.Processor for a BatchProcessor.
 type airGapBatchProcessor struct {
 	p BatchProcessor
+
+	closeCtx  context.Context
+	closeDone func()
 }
 
 func newAirGapBatchProcessor(typeStr string, p BatchProcessor, stats metrics.Type) processor.V1 {
-	return processor.NewV2BatchedToV1Processor(typeStr, &airGapBatchProcessor{p}, stats)
+	closeCtx, closeDone := context.WithCancel(context.Background())
+	return processor.NewV2BatchedToV1Processor(typeStr, &airGapBatchProcessor{
+		p:         p,
+		closeCtx:  closeCtx,
+		closeDone: closeDone,
+	}, stats)
+}
+
+// waitRetryAfter blocks for the duration of a RetryAfterError and returns true
+// if the batch should be retried.
+func (a *airGapBatchProcessor) waitRetryAfter(ctx context.Context, err error) bool {
+	var rErr *RetryAfterError
+	if !errors.As(err, &rErr) {
+		return false
+	}
+	select {
+	case <-time.After(rErr.after):
+	case <-ctx.Done():
+		return false
+	case <-a.closeCtx.Done():
+		return false
+	}
+	return true
 }
 
 func (a *airGapBatchProcessor) ProcessBatch(ctx context.Context, spans []*tracing.Span, batch *message.Batch) ([]*message.Batch, error) {
-	inputBatch := make([]*Message, batch.Len())
-	_ = batch.Iter(func(i int, p *message.Part) error {
-		inputBatch[i] = newMessageFromPart(p)
-		return nil
-	})
+	// The input batch is rebuilt from the original parts for each attempt so
+	// that changes made to the messages during a failed attempt are discarded.
+	process := func() ([]MessageBatch, error) {
+		inputBatch := make([]*Message, batch.Len())
+		_ = batch.Iter(func(i int, p *message.Part) error {
+			inputBatch[i] = newMessageFromPart(p)
+			return nil
+		})
+		return a.p.ProcessBatch(ctx, inputBatch)
+	}
 
-	outputBatches, err := a.p.ProcessBatch(ctx, inputBatch)
+	outputBatches, err := process()
+	for err != nil && a.waitRetryAfter(ctx, err) {
+		outputBatches, err = process()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +206,7 @@ func (a *airGapBatchProcessor) ProcessBatch(ctx context.Context, spans []*tracin
 }
 
 func (a *airGapBatchProcessor) Close(ctx context.Context) error {
+	a.closeDone()
 	return a.p.Close(context.Background())
 }
 
@@ -146,7 +228,7 @@ func (o *OwnedProcessor) Process(ctx context.Context, msg *Message) (MessageBatc
 	msg.ensureCopied()
 	outMsg.Append(msg.part)
 
-	iMsgs, res := o.p.ProcessMessage(outMsg)
+	iMsgs, res := processor.ProcessWithContext(ctx, o.p, outMsg)
 	if res != nil {
 		return nil, res
 	}
@@ -174,7 +256,7 @@ func (o *OwnedProcessor) ProcessBatch(ctx context.Context, batch MessageBatch) (
 		outMsg.Append(msg.part)
 	}
 
-	iMsgs, res := o.p.ProcessMessage(outMsg)
+	iMsgs, res := processor.ProcessWithContext(ctx, o.p, outMsg)
 	if res != nil {
 		return nil, res
 	}
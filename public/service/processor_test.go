@@ -201,3 +201,97 @@ func TestBatchProcessorAirGapOneToMany(t *testing.T) {
 	assert.Equal(t, 1, msgs[1].Len())
 	assert.Equal(t, "changed 3", string(msgs[1].Get(0).Get()))
 }
+
+func TestBatchProcessorAirGapRetryAfter(t *testing.T) {
+	var calls int
+	agrp := newAirGapBatchProcessor("foo", &fnBatchProcessor{
+		fn: func(c context.Context, msgs MessageBatch) ([]MessageBatch, error) {
+			if calls++; calls < 3 {
+				return nil, NewRetryAfterError(errors.New("not yet"), time.Millisecond)
+			}
+			msgs[0].SetBytes([]byte("changed"))
+			return []MessageBatch{{msgs[0]}}, nil
+		},
+	}, metrics.Noop())
+
+	msg := message.QuickBatch([][]byte{[]byte("unchanged")})
+	msgs, res := agrp.ProcessMessage(msg)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "changed", string(msgs[0].Get(0).Get()))
+	assert.NoError(t, msgs[0].Get(0).ErrorGet())
+}
+
+func TestBatchProcessorAirGapRetryAfterDiscardsChanges(t *testing.T) {
+	var calls int
+	agrp := newAirGapBatchProcessor("foo", &fnBatchProcessor{
+		fn: func(c context.Context, msgs MessageBatch) ([]MessageBatch, error) {
+			require.Len(t, msgs, 2)
+			b, err := msgs[0].AsBytes()
+			require.NoError(t, err)
+			assert.Equal(t, "first", string(b))
+			_, exists := msgs[0].MetaGet("foo")
+			assert.False(t, exists)
+
+			if calls++; calls < 2 {
+				msgs[0].SetBytes([]byte("changed"))
+				msgs[0].MetaSet("foo", "bar")
+				msgs[0], msgs[1] = msgs[1], msgs[0]
+				return nil, NewRetryAfterError(errors.New("not yet"), time.Millisecond)
+			}
+			return []MessageBatch{msgs}, nil
+		},
+	}, metrics.Noop())
+
+	msg := message.QuickBatch([][]byte{[]byte("first"), []byte("second")})
+	msgs, res := agrp.ProcessMessage(msg)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "first", string(msgs[0].Get(0).Get()))
+	assert.Equal(t, "second", string(msgs[0].Get(1).Get()))
+}
+
+func TestBatchProcessorAirGapRetryAfterShutdown(t *testing.T) {
+	agrp := newAirGapBatchProcessor("foo", &fnBatchProcessor{
+		fn: func(c context.Context, msgs MessageBatch) ([]MessageBatch, error) {
+			return nil, NewRetryAfterError(errors.New("not yet"), time.Hour)
+		},
+	}, metrics.Noop())
+
+	go func() {
+		<-time.After(time.Millisecond * 10)
+		agrp.CloseAsync()
+	}()
+
+	msg := message.QuickBatch([][]byte{[]byte("unchanged")})
+	msgs, res := agrp.ProcessMessage(msg)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "unchanged", string(msgs[0].Get(0).Get()))
+	assert.EqualError(t, msgs[0].Get(0).ErrorGet(), "not yet")
+}
+
+func TestRetryAfterErrorNilCause(t *testing.T) {
+	err := NewRetryAfterError(nil, time.Second)
+	assert.EqualError(t, err, "batch should be retried after 1s")
+	assert.Nil(t, err.Unwrap())
+
+	agrp := newAirGapBatchProcessor("foo", &fnBatchProcessor{
+		fn: func(c context.Context, msgs MessageBatch) ([]MessageBatch, error) {
+			return nil, NewRetryAfterError(nil, time.Hour)
+		},
+	}, metrics.Noop())
+
+	go func() {
+		<-time.After(time.Millisecond * 10)
+		agrp.CloseAsync()
+	}()
+
+	msg := message.QuickBatch([][]byte{[]byte("unchanged")})
+	msgs, res := agrp.ProcessMessage(msg)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.EqualError(t, msgs[0].Get(0).ErrorGet(), "batch should be retried after 1h0m0s")
+}
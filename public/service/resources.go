@@ -2,11 +2,16 @@ package service
 
 import (
 	"context"
+	"io"
+	"net/http"
 
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
 	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
 )
 
 // Resources provides access to service-wide resources.
@@ -47,6 +52,29 @@ func (r *Resources) Metrics() *Metrics {
 	return newReverseAirGapMetrics(r.mgr.Metrics())
 }
 
+// RegisterEndpoint registers a server wide HTTP endpoint, which is prefixed
+// with the stream identifier when running in streams mode.
+//
+// Experimental: This method may change outside of major version releases.
+func (r *Resources) RegisterEndpoint(path, desc string, fn http.HandlerFunc) {
+	r.mgr.RegisterEndpoint(path, desc, fn)
+}
+
+// AcquireSharedClient returns a client that is shared by all components of the
+// service that acquire it under the same key, creating it with the provided
+// constructor if it does not yet exist. The key should uniquely identify the
+// target endpoint along with any options that change the behaviour of the
+// client, such as the URL, credentials and TLS settings.
+//
+// The returned release func must be called when the component is finished with
+// the client instead of closing the client directly, and the client is closed
+// once all components that acquired it have released it.
+//
+// Experimental: This method may change outside of major version releases.
+func (r *Resources) AcquireSharedClient(key string, ctor func() (io.Closer, error)) (io.Closer, func() error, error) {
+	return r.mgr.SharedClients().Acquire(key, ctor)
+}
+
 // AccessCache attempts to access a cache resource by name. This action can
 // block if CRUD operations are being actively performed on the resource.
 func (r *Resources) AccessCache(ctx context.Context, name string, fn func(c Cache)) error {
@@ -76,3 +104,95 @@ func (r *Resources) AccessRateLimit(ctx context.Context, name string, fn func(r
 func (r *Resources) HasRateLimit(name string) bool {
 	return r.mgr.ProbeRateLimit(name)
 }
+
+// AccessProcessor attempts to access a processor resource by name. This action
+// can block if CRUD operations are being actively performed on the resource.
+//
+// The processor is owned by the resources of the service and therefore it is
+// only valid for the lifetime of the provided closure.
+func (r *Resources) AccessProcessor(ctx context.Context, name string, fn func(p *ResourceProcessor)) error {
+	return r.mgr.AccessProcessor(ctx, name, func(p processor.V1) {
+		fn(&ResourceProcessor{p: p})
+	})
+}
+
+// HasProcessor confirms whether a processor with a given name has been
+// registered as a resource. This method is useful during component
+// initialisation as it is defensive against ordering.
+func (r *Resources) HasProcessor(name string) bool {
+	return r.mgr.ProbeProcessor(name)
+}
+
+// AccessOutput attempts to access an output resource by name. This action can
+// block if CRUD operations are being actively performed on the resource.
+//
+// The output is owned by the resources of the service and therefore it is only
+// valid for the lifetime of the provided closure.
+func (r *Resources) AccessOutput(ctx context.Context, name string, fn func(o *ResourceOutput)) error {
+	return r.mgr.AccessOutput(ctx, name, func(o output.Sync) {
+		fn(&ResourceOutput{o: o})
+	})
+}
+
+// HasOutput confirms whether an output with a given name has been registered as
+// a resource. This method is useful during component initialisation as it is
+// defensive against ordering.
+func (r *Resources) HasOutput(name string) bool {
+	return r.mgr.ProbeOutput(name)
+}
+
+//------------------------------------------------------------------------------
+
+// ResourceProcessor provides access to a processor resource. Unlike an
+// OwnedProcessor the lifecycle of a resource is managed by the service and
+// therefore it cannot be closed.
+type ResourceProcessor struct {
+	p processor.V1
+}
+
+// Process a single message, returns either a batch of zero or more resulting
+// messages or an error if the message could not be processed.
+func (r *ResourceProcessor) Process(ctx context.Context, msg *Message) (MessageBatch, error) {
+	return (&OwnedProcessor{p: r.p}).Process(ctx, msg)
+}
+
+// ProcessBatch attempts to process a batch of messages, returns zero or more
+// batches of resulting messages or an error if the messages could not be
+// processed.
+func (r *ResourceProcessor) ProcessBatch(ctx context.Context, batch MessageBatch) ([]MessageBatch, error) {
+	return (&OwnedProcessor{p: r.p}).ProcessBatch(ctx, batch)
+}
+
+// ResourceOutput provides access to an output resource. Unlike an OwnedOutput
+// the lifecycle of a resource is managed by the service and therefore it
+// cannot be closed.
+type ResourceOutput struct {
+	o output.Sync
+}
+
+// Write a message to the output, or return an error either if delivery is not
+// possible or the context is cancelled.
+func (r *ResourceOutput) Write(ctx context.Context, m *Message) error {
+	return r.WriteBatch(ctx, MessageBatch{m})
+}
+
+// WriteBatch attempts to write a message batch to the output, and returns an
+// error either if delivery is not possible or the context is cancelled.
+func (r *ResourceOutput) WriteBatch(ctx context.Context, b MessageBatch) error {
+	payload := message.QuickBatch(nil)
+	for _, m := range b {
+		payload.Append(m.part)
+	}
+
+	resChan := make(chan error, 1)
+	if err := r.o.WriteTransaction(ctx, message.NewTransaction(payload, resChan)); err != nil {
+		return err
+	}
+
+	select {
+	case res := <-resChan:
+		return res
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestResourcesAccessProcessor(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Processors["foo"] = func(b *message.Batch) ([]*message.Batch, error) {
+		_ = b.Iter(func(i int, p *message.Part) error {
+			p.Set(append([]byte("foo: "), p.Get()...))
+			return nil
+		})
+		return []*message.Batch{b}, nil
+	}
+
+	res := newResourcesFromManager(mgr)
+	assert.True(t, res.HasProcessor("foo"))
+	assert.False(t, res.HasProcessor("bar"))
+
+	var batches []MessageBatch
+	var perr error
+	require.NoError(t, res.AccessProcessor(context.Background(), "foo", func(p *ResourceProcessor) {
+		batches, perr = p.ProcessBatch(context.Background(), MessageBatch{
+			NewMessage([]byte("hello")),
+			NewMessage([]byte("world")),
+		})
+	}))
+	require.NoError(t, perr)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 2)
+
+	b, err := batches[0][0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "foo: hello", string(b))
+
+	b, err = batches[0][1].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "foo: world", string(b))
+
+	require.Error(t, res.AccessProcessor(context.Background(), "bar", func(p *ResourceProcessor) {
+		t.Error("should not be called")
+	}))
+}
+
+func TestResourcesAccessOutput(t *testing.T) {
+	var written []string
+	mgr := mock.NewManager()
+	mgr.Outputs["foo"] = func(ctx context.Context, tran message.Transaction) error {
+		_ = tran.Payload.Iter(func(i int, p *message.Part) error {
+			written = append(written, string(p.Get()))
+			return nil
+		})
+		return tran.Ack(ctx, nil)
+	}
+	mgr.Outputs["bar"] = func(ctx context.Context, tran message.Transaction) error {
+		return tran.Ack(ctx, errors.New("nope"))
+	}
+
+	res := newResourcesFromManager(mgr)
+	assert.True(t, res.HasOutput("foo"))
+	assert.False(t, res.HasOutput("baz"))
+
+	var werr error
+	require.NoError(t, res.AccessOutput(context.Background(), "foo", func(o *ResourceOutput) {
+		werr = o.WriteBatch(context.Background(), MessageBatch{
+			NewMessage([]byte("hello")),
+			NewMessage([]byte("world")),
+		})
+	}))
+	require.NoError(t, werr)
+	assert.Equal(t, []string{"hello", "world"}, written)
+
+	require.NoError(t, res.AccessOutput(context.Background(), "bar", func(o *ResourceOutput) {
+		werr = o.Write(context.Background(), NewMessage([]byte("hello")))
+	}))
+	require.EqualError(t, werr, "nope")
+}
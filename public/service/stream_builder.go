@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/Jeffail/gabs/v2"
 	"github.com/gofrs/uuid"
@@ -43,6 +44,7 @@ import (
 type StreamBuilder struct {
 	http       api.Config
 	threads    int
+	ordered    bool
 	inputs     []input.Config
 	buffer     buffer.Config
 	processors []processor.Config
@@ -51,10 +53,11 @@ type StreamBuilder struct {
 	metrics    metrics.Config
 	logger     log.Config
 
-	producerChan chan message.Transaction
-	producerID   string
-	consumerFunc MessageBatchHandlerFunc
-	consumerID   string
+	producerChan   chan message.Transaction
+	producerID     string
+	consumerFunc   MessageBatchHandlerFunc
+	consumerClosed func()
+	consumerID     string
 
 	apiMut       manager.APIReg
 	customLogger log.Modular
@@ -352,6 +355,92 @@ func (s *StreamBuilder) AddBatchConsumerFunc(fn MessageBatchHandlerFunc) error {
 	return nil
 }
 
+// MessageBatchReadFunc is a function signature for pulling message batches out
+// of a stream. Each batch returned must be acknowledged by calling the
+// accompanying AckFunc, where a nil error indicates that the batch was
+// successfully consumed and a non-nil error results in the batch being nacked.
+// Once the stream has finished and all batches have been read ErrEndOfInput is
+// returned.
+type MessageBatchReadFunc func(context.Context) (MessageBatch, AckFunc, error)
+
+type pendingConsumerBatch struct {
+	batch   MessageBatch
+	resChan chan error
+}
+
+// AddBatchConsumerReadFunc adds an output to the builder and returns a
+// MessageBatchReadFunc that allows you to pull message batches out of the
+// stream, which is an alternative to AddBatchConsumerFunc for applications that
+// prefer to consume results on their own terms. If more than one output
+// configuration is added they will automatically be composed within a fan out
+// broker when the pipeline is built.
+//
+// The returned MessageBatchReadFunc can be called concurrently from any number
+// of goroutines, and each call will block until a batch is available, the
+// stream has ended, or the context is cancelled. The stream will not progress
+// past a batch until it has been acknowledged, and therefore it is important to
+// call the AckFunc of each batch read.
+//
+// Only one consumer can be added to a stream builder, and subsequent calls will
+// return an error.
+func (s *StreamBuilder) AddBatchConsumerReadFunc() (MessageBatchReadFunc, error) {
+	if s.consumerFunc != nil {
+		return nil, errors.New("unable to add multiple consumer funcs to a stream builder")
+	}
+
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate a consumer uuid: %w", err)
+	}
+
+	batchChan := make(chan pendingConsumerBatch)
+	closedChan := make(chan struct{})
+	var closeOnce sync.Once
+
+	s.consumerFunc = func(ctx context.Context, mb MessageBatch) error {
+		resChan := make(chan error, 1)
+		select {
+		case batchChan <- pendingConsumerBatch{batch: mb, resChan: resChan}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case res := <-resChan:
+			return res
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	s.consumerClosed = func() {
+		closeOnce.Do(func() {
+			close(closedChan)
+		})
+	}
+	s.consumerID = uuid.String()
+
+	conf := output.NewConfig()
+	conf.Type = "inproc"
+	conf.Inproc = s.consumerID
+	s.outputs = append(s.outputs, conf)
+
+	return func(ctx context.Context) (MessageBatch, AckFunc, error) {
+		select {
+		case p := <-batchChan:
+			var ackOnce sync.Once
+			return p.batch, func(ctx context.Context, err error) error {
+				ackOnce.Do(func() {
+					p.resChan <- err
+				})
+				return nil
+			}, nil
+		case <-closedChan:
+			return nil, nil, ErrEndOfInput
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}, nil
+}
+
 // AddOutputYAML parses an output YAML configuration and adds it to the builder.
 // If more than one output configuration is added they will automatically be
 // composed within a fan out broker when the pipeline is built.
@@ -544,6 +633,7 @@ func (s *StreamBuilder) setFromConfig(sconf config.Type) {
 	s.buffer = sconf.Buffer
 	s.processors = sconf.Pipeline.Processors
 	s.threads = sconf.Pipeline.Threads
+	s.ordered = sconf.Pipeline.PreserveOrder
 	s.outputs = []output.Config{sconf.Output}
 	s.resources = sconf.ResourceConfig
 	s.logger = sconf.Logger
@@ -656,6 +746,9 @@ func (s *StreamBuilder) runConsumerFunc(mgr *manager.Type) error {
 		for {
 			tran, open := <-tChan
 			if !open {
+				if s.consumerClosed != nil {
+					s.consumerClosed()
+				}
 				return
 			}
 			batch := make(MessageBatch, tran.Payload.Len())
@@ -702,7 +795,7 @@ func (s *StreamBuilder) buildWithEnv(env *bundle.Environment) (*Stream, error) {
 		}
 	}
 
-	stats, err := bundle.AllMetrics.Init(s.metrics, logger)
+	stats, err := env.MetricsInit(s.metrics, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -772,6 +865,7 @@ func (s *StreamBuilder) buildConfig() builderConfig {
 	conf.Buffer = s.buffer
 
 	conf.Pipeline.Threads = s.threads
+	conf.Pipeline.PreserveOrder = s.ordered
 	conf.Pipeline.Processors = s.processors
 
 	if len(s.outputs) == 1 {
@@ -34,6 +34,7 @@ func TestStreamBuilderDefault(t *testing.T) {
     none: {}`,
 		`pipeline:
     threads: 0
+    preserve_order: false
     processors: []`,
 		`output:
     label: ""
@@ -367,6 +368,63 @@ file:
 	outMut.Unlock()
 }
 
+func TestStreamBuilderBatchConsumerReadFunc(t *testing.T) {
+	b := service.NewStreamBuilder()
+	require.NoError(t, b.SetLoggerYAML("level: NONE"))
+	require.NoError(t, b.AddProcessorYAML(`bloblang: 'root = content().uppercase()'`))
+
+	sendFn, err := b.AddProducerFunc()
+	require.NoError(t, err)
+
+	readFn, err := b.AddBatchConsumerReadFunc()
+	require.NoError(t, err)
+
+	// Fails on second call.
+	_, err = b.AddBatchConsumerReadFunc()
+	require.Error(t, err)
+	require.Error(t, b.AddConsumerFunc(func(context.Context, *service.Message) error {
+		return nil
+	}))
+
+	strm, err := b.Build()
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+		defer done()
+
+		for i := 0; i < 3; i++ {
+			sendErr := make(chan error, 1)
+			go func(i int) {
+				sendErr <- sendFn(ctx, service.NewMessage([]byte(fmt.Sprintf("hello world %v", i))))
+			}(i)
+
+			batch, ackFn, err := readFn(ctx)
+			require.NoError(t, err)
+			require.Len(t, batch, 1)
+
+			mBytes, err := batch[0].AsBytes()
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("HELLO WORLD %v", i), string(mBytes))
+
+			require.NoError(t, ackFn(ctx, nil))
+			require.NoError(t, <-sendErr)
+		}
+
+		require.NoError(t, strm.StopWithin(time.Second*5))
+
+		_, _, err := readFn(ctx)
+		assert.Equal(t, service.ErrEndOfInput, err)
+	}()
+
+	require.NoError(t, strm.Run(context.Background()))
+	wg.Wait()
+}
+
 func TestStreamBuilderCustomLogger(t *testing.T) {
 	b := service.NewStreamBuilder()
 	b.SetPrintLogger(nil)
@@ -406,6 +464,7 @@ type: local`))
     memory: {}`,
 		`pipeline:
     threads: 10
+    preserve_order: false
     processors:`,
 		`
         - label: ""
@@ -723,6 +782,7 @@ output:
     none: {}`,
 		`pipeline:
     threads: 5
+    preserve_order: false
     processors:`,
 		`
         - label: ""
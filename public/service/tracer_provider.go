@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OtelTracerProviderConstructor is a func that's provided a configuration type
+// and must return an instantiation of an open telemetry tracer provider based
+// on the config, or an error.
+//
+// Experimental: This type may change outside of major version releases.
+type OtelTracerProviderConstructor func(conf *ParsedConfig) (trace.TracerProvider, error)
+
+//------------------------------------------------------------------------------
+
+// Implements internal tracer.Type around an open telemetry tracer provider.
+type airGapTracer struct {
+	prov trace.TracerProvider
+}
+
+func newAirGapTracer(prov trace.TracerProvider) *airGapTracer {
+	otel.SetTracerProvider(prov)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return &airGapTracer{prov: prov}
+}
+
+func (a *airGapTracer) Close() error {
+	// Tracer providers are not required to implement Shutdown, but the SDK
+	// implementation does and it's needed in order to flush pending spans.
+	if s, ok := a.prov.(interface {
+		Shutdown(ctx context.Context) error
+	}); ok {
+		return s.Shutdown(context.Background())
+	}
+	return nil
+}